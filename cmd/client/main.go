@@ -1,7 +1,6 @@
 package main
 
 import (
-	"fmt"
 	"github.com/SmirnovND/gophkeeper/internal/command"
 	"github.com/SmirnovND/gophkeeper/internal/container/client"
 	"github.com/SmirnovND/gophkeeper/internal/interfaces"
@@ -34,6 +33,9 @@ func main() {
 	rootCmd.AddCommand(Command.RegisterCmd())
 	rootCmd.AddCommand(Command.UploadCmd())
 	rootCmd.AddCommand(Command.DownloadCmd())
+	rootCmd.AddCommand(Command.FileCmd())
+	rootCmd.AddCommand(Command.UsageCmd())
+	rootCmd.AddCommand(Command.SettingsCmd())
 	
 	// Добавляем команды для работы с текстовыми данными
 	rootCmd.AddCommand(Command.SaveTextCmd())
@@ -49,12 +51,68 @@ func main() {
 	rootCmd.AddCommand(Command.SaveCredentialCmd())
 	rootCmd.AddCommand(Command.GetCredentialCmd())
 	rootCmd.AddCommand(Command.DeleteCredentialCmd())
+	rootCmd.AddCommand(Command.FindCmd())
 	
+	// Добавляем команду вывода кода TOTP
+	rootCmd.AddCommand(Command.OTPCmd())
+
+	// Добавляем команды для работы с ключами SSH и агент SSH
+	rootCmd.AddCommand(Command.SaveSSHKeyCmd())
+	rootCmd.AddCommand(Command.GetSSHKeyCmd())
+	rootCmd.AddCommand(Command.DeleteSSHKeyCmd())
+	rootCmd.AddCommand(Command.SSHAgentCmd())
+
+	// Добавляем команды для работы с записями структурированных типов
+	rootCmd.AddCommand(Command.ItemCmd())
+
 	// Добавляем команду для получения информации о версии
 	rootCmd.AddCommand(Command.VersionCmd())
+
+	// Добавляем полноэкранный интерфейс
+	rootCmd.AddCommand(Command.TUICmd())
+
+	// Добавляем команду копирования секрета в буфер обмена
+	rootCmd.AddCommand(Command.CopyCmd())
+
+	// Добавляем команду генерации паролей
+	rootCmd.AddCommand(Command.GenerateCmd())
+
+	// Добавляем команду импорта из других менеджеров паролей
+	rootCmd.AddCommand(Command.ImportCmd())
+
+	// Добавляем команды резервного копирования хранилища
+	rootCmd.AddCommand(Command.ExportCmd())
+	rootCmd.AddCommand(Command.RestoreBackupCmd())
+
+	// Добавляем команду запуска процесса с секретами в окружении
+	rootCmd.AddCommand(Command.RunCmd())
+
+	// Добавляем команду подстановки секретов в шаблоны
+	rootCmd.AddCommand(Command.InjectCmd())
+
+	// Добавляем помощник учетных данных git
+	rootCmd.AddCommand(Command.GitCredentialCmd())
+
+	// Добавляем помощник учетных данных docker
+	rootCmd.AddCommand(Command.DockerCredentialCmd())
+
+	// Добавляем команду управления профилями; профиль выбирается после разбора флагов
+	rootCmd.AddCommand(Command.ProfileCmd())
+	rootCmd.PersistentPreRunE = Command.SelectProfile
+	command.AddProfileFlags(rootCmd)
 	
+	// Глобальные флаги формата вывода; у download и inject флаг --output задает путь результата
+	command.AddOutputFlags(rootCmd)
+
+	// Команды сами сообщают об ошибках, а код завершения зависит от вида ошибки
+	rootCmd.SilenceErrors = true
+	rootCmd.SilenceUsage = true
+	// Под именем помощника, например git-credential-passcli, выполняется соответствующая команда
+	if args := command.HelperArgs(os.Args); args != nil {
+		rootCmd.SetArgs(args)
+	}
 	if err := rootCmd.Execute(); err != nil {
-		fmt.Println(err)
-		os.Exit(1)
+		command.PrintError(err)
+		os.Exit(command.ExitCode(err))
 	}
 }
@@ -9,6 +9,7 @@ import (
 	"github.com/SmirnovND/toolbox/pkg/middleware"
 	"github.com/SmirnovND/toolbox/pkg/migrations"
 	"github.com/jmoiron/sqlx"
+	"log"
 	"net/http"
 )
 
@@ -34,6 +35,21 @@ func Run() error {
 	dbBase := dbx.DB
 	migrations.StartMigrations(dbBase)
 
+	// После смены мастер-ключа переупаковываем им ключи пользователей, прежние ключи можно убрать из конфигурации
+	var keyService interfaces.KeyService
+	if err := diContainer.Invoke(func(k interfaces.KeyService) {
+		keyService = k
+	}); err != nil {
+		return err
+	}
+	rotated, err := keyService.RotateMasterKey()
+	if err != nil {
+		return err
+	}
+	if rotated > 0 {
+		log.Printf("Ключи пользователей переупакованы текущим мастер-ключом: %d", rotated)
+	}
+
 	return http.ListenAndServe(cf.GetRunAddr(), middleware.ChainMiddleware(
 		router.Handler(diContainer),
 		logger.WithLogging,
@@ -15,19 +15,16 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/api/data/card/{label}": {
+        "/api/data": {
             "get": {
-                "description": "Получает данные кредитной карты пользователя по метке",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Возвращает тип, метку, метаданные и даты изменения всех записей пользователя без их содержимого",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "data"
+                    "items"
                 ],
-                "summary": "Получить данные кредитной карты",
+                "summary": "Получить список записей",
                 "parameters": [
                     {
                         "type": "string",
@@ -35,28 +32,15 @@ const docTemplate = `{
                         "name": "Authorization",
                         "in": "header",
                         "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Метка для идентификации данных",
-                        "name": "label",
-                        "in": "path",
-                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/domain.CardData"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/domain.ItemInfo"
                             }
                         }
                     },
@@ -69,15 +53,6 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
-                        }
-                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -88,9 +63,11 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "post": {
-                "description": "Сохраняет данные кредитной карты пользователя",
+            }
+        },
+        "/api/data/{type}/{label}": {
+            "get": {
+                "description": "Устаревший маршрут, используйте /api/v2/items/{type}/{label}\nПоля-списки, например uris и fields учетных данных, передаются списками JSON",
                 "consumes": [
                     "application/json"
                 ],
@@ -100,7 +77,7 @@ const docTemplate = `{
                 "tags": [
                     "data"
                 ],
-                "summary": "Сохранить данные кредитной карты",
+                "summary": "Получить запись (API v1)",
                 "parameters": [
                     {
                         "type": "string",
@@ -111,29 +88,24 @@ const docTemplate = `{
                     },
                     {
                         "type": "string",
-                        "description": "Метка для идентификации данных",
-                        "name": "label",
+                        "description": "Тип записи",
+                        "name": "type",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Данные кредитной карты",
-                        "name": "card",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/domain.CardData"
-                        }
+                        "type": "string",
+                        "description": "Метка для идентификации данных",
+                        "name": "label",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "Поля записи под ключом типа с метаинформацией",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "type": "object"
                         }
                     },
                     "400": {
@@ -154,6 +126,15 @@ const docTemplate = `{
                             }
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -165,8 +146,8 @@ const docTemplate = `{
                     }
                 }
             },
-            "delete": {
-                "description": "Удаляет данные кредитной карты пользователя по метке",
+            "post": {
+                "description": "Устаревший маршрут, используйте /api/v2/items/{type}/{label}\nПоля-списки, например uris и fields учетных данных, передаются списками JSON",
                 "consumes": [
                     "application/json"
                 ],
@@ -176,7 +157,7 @@ const docTemplate = `{
                 "tags": [
                     "data"
                 ],
-                "summary": "Удалить данные кредитной карты",
+                "summary": "Сохранить запись (API v1)",
                 "parameters": [
                     {
                         "type": "string",
@@ -185,12 +166,28 @@ const docTemplate = `{
                         "in": "header",
                         "required": true
                     },
+                    {
+                        "type": "string",
+                        "description": "Тип записи",
+                        "name": "type",
+                        "in": "path",
+                        "required": true
+                    },
                     {
                         "type": "string",
                         "description": "Метка для идентификации данных",
                         "name": "label",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Поля записи под ключом типа с метаинформацией",
+                        "name": "item",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object"
+                        }
                     }
                 ],
                 "responses": {
@@ -230,6 +227,15 @@ const docTemplate = `{
                             }
                         }
                     },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -240,11 +246,9 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/api/data/credential/{label}": {
-            "get": {
-                "description": "Получает пару логин/пароль пользователя по метке",
+            },
+            "delete": {
+                "description": "Удаляет запись по типу и метке",
                 "consumes": [
                     "application/json"
                 ],
@@ -252,9 +256,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "data"
+                    "items"
                 ],
-                "summary": "Получить учетные данные",
+                "summary": "Удалить запись",
                 "parameters": [
                     {
                         "type": "string",
@@ -263,6 +267,13 @@ const docTemplate = `{
                         "in": "header",
                         "required": true
                     },
+                    {
+                        "type": "string",
+                        "description": "Тип записи",
+                        "name": "type",
+                        "in": "path",
+                        "required": true
+                    },
                     {
                         "type": "string",
                         "description": "Метка для идентификации данных",
@@ -275,7 +286,10 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/domain.CredentialData"
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     },
                     "400": {
@@ -315,47 +329,42 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "post": {
-                "description": "Сохраняет пару логин/пароль пользователя",
-                "consumes": [
-                    "application/json"
+            }
+        },
+        "/api/file": {
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
                 ],
+                "description": "Удаляет файл из хранилища вместе с его метаданными",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "data"
+                    "files"
                 ],
-                "summary": "Сохранить учетные данные",
+                "summary": "Удаление файла",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Bearer токен",
+                        "description": "Bearer токен авторизации",
                         "name": "Authorization",
                         "in": "header",
                         "required": true
                     },
                     {
                         "type": "string",
-                        "description": "Метка для идентификации данных",
+                        "description": "Метка файла",
                         "name": "label",
-                        "in": "path",
+                        "in": "query",
                         "required": true
-                    },
-                    {
-                        "description": "Учетные данные",
-                        "name": "credential",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/domain.CredentialData"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "Файл удален",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -364,7 +373,7 @@ const docTemplate = `{
                         }
                     },
                     "400": {
-                        "description": "Bad Request",
+                        "description": "Ошибка в формате запроса",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -373,7 +382,16 @@ const docTemplate = `{
                         }
                     },
                     "401": {
-                        "description": "Unauthorized",
+                        "description": "Пользователь не авторизован",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Файл не найден",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -382,7 +400,7 @@ const docTemplate = `{
                         }
                     },
                     "500": {
-                        "description": "Internal Server Error",
+                        "description": "Внутренняя ошибка сервера",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -391,47 +409,48 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
-                "description": "Удаляет пару логин/пароль пользователя по метке",
-                "consumes": [
-                    "application/json"
+            }
+        },
+        "/api/file/info": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
                 ],
+                "description": "Возвращает расширение, размер, метаинформацию и даты изменения файла",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "data"
+                    "files"
                 ],
-                "summary": "Удалить учетные данные",
+                "summary": "Сведения о файле",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Bearer токен",
+                        "description": "Bearer токен авторизации",
                         "name": "Authorization",
                         "in": "header",
                         "required": true
                     },
                     {
                         "type": "string",
-                        "description": "Метка для идентификации данных",
+                        "description": "Метка файла",
                         "name": "label",
-                        "in": "path",
+                        "in": "query",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "Сведения о файле",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/domain.FileInfo"
                         }
                     },
                     "400": {
-                        "description": "Bad Request",
+                        "description": "Ошибка в формате запроса",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -440,7 +459,7 @@ const docTemplate = `{
                         }
                     },
                     "401": {
-                        "description": "Unauthorized",
+                        "description": "Пользователь не авторизован",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -449,7 +468,7 @@ const docTemplate = `{
                         }
                     },
                     "404": {
-                        "description": "Not Found",
+                        "description": "Файл не найден",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -458,7 +477,7 @@ const docTemplate = `{
                         }
                     },
                     "500": {
-                        "description": "Internal Server Error",
+                        "description": "Внутренняя ошибка сервера",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -469,62 +488,42 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/data/text/{label}": {
+        "/api/file/list": {
             "get": {
-                "description": "Получает произвольный текст пользователя по метке",
-                "consumes": [
-                    "application/json"
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
                 ],
+                "description": "Возвращает сведения обо всех файлах пользователя",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "data"
+                    "files"
                 ],
-                "summary": "Получить текстовые данные",
+                "summary": "Список файлов",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Bearer токен",
+                        "description": "Bearer токен авторизации",
                         "name": "Authorization",
                         "in": "header",
                         "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Метка для идентификации данных",
-                        "name": "label",
-                        "in": "path",
-                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/domain.TextData"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
+                        "description": "Список файлов",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/domain.FileInfo"
                             }
                         }
                     },
                     "401": {
-                        "description": "Unauthorized",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
-                        }
-                    },
-                    "404": {
-                        "description": "Not Found",
+                        "description": "Пользователь не авторизован",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -533,7 +532,7 @@ const docTemplate = `{
                         }
                     },
                     "500": {
-                        "description": "Internal Server Error",
+                        "description": "Внутренняя ошибка сервера",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -542,9 +541,16 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
+            }
+        },
+        "/api/file/rename": {
             "post": {
-                "description": "Сохраняет произвольный текст пользователя",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Меняет метку файла и переносит объект в хранилище под новое имя",
                 "consumes": [
                     "application/json"
                 ],
@@ -552,37 +558,30 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "data"
+                    "files"
                 ],
-                "summary": "Сохранить текстовые данные",
+                "summary": "Переименование файла",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Bearer токен",
+                        "description": "Bearer токен авторизации",
                         "name": "Authorization",
                         "in": "header",
                         "required": true
                     },
                     {
-                        "type": "string",
-                        "description": "Метка для идентификации данных",
-                        "name": "label",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Текстовые данные",
-                        "name": "text",
+                        "description": "Текущая и новая метки файла",
+                        "name": "rename",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/domain.TextData"
+                            "$ref": "#/definitions/domain.FileRename"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "Файл переименован",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -591,7 +590,7 @@ const docTemplate = `{
                         }
                     },
                     "400": {
-                        "description": "Bad Request",
+                        "description": "Ошибка в формате запроса",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -600,7 +599,25 @@ const docTemplate = `{
                         }
                     },
                     "401": {
-                        "description": "Unauthorized",
+                        "description": "Пользователь не авторизован",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Файл не найден",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Новая метка уже занята",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -609,7 +626,7 @@ const docTemplate = `{
                         }
                     },
                     "500": {
-                        "description": "Internal Server Error",
+                        "description": "Внутренняя ошибка сервера",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -618,38 +635,42 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
-                "description": "Удаляет произвольный текст пользователя по метке",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
+            }
+        },
+        "/api/file/verify": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Определяет тип загруженного файла по содержимому и проверяет его политикой типов файлов. Файл запрещенного типа удаляется вместе с метаданными",
+                "produces": [
+                    "application/json"
                 ],
                 "tags": [
-                    "data"
+                    "files"
                 ],
-                "summary": "Удалить текстовые данные",
+                "summary": "Проверка типа загруженного файла",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Bearer токен",
+                        "description": "Bearer токен авторизации",
                         "name": "Authorization",
                         "in": "header",
                         "required": true
                     },
                     {
                         "type": "string",
-                        "description": "Метка для идентификации данных",
+                        "description": "Метка файла",
                         "name": "label",
-                        "in": "path",
+                        "in": "query",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "Тип файла разрешен",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -658,7 +679,7 @@ const docTemplate = `{
                         }
                     },
                     "400": {
-                        "description": "Bad Request",
+                        "description": "Ошибка в формате запроса",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -667,7 +688,7 @@ const docTemplate = `{
                         }
                     },
                     "401": {
-                        "description": "Unauthorized",
+                        "description": "Пользователь не авторизован",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -676,7 +697,16 @@ const docTemplate = `{
                         }
                     },
                     "404": {
-                        "description": "Not Found",
+                        "description": "Файл не найден",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "415": {
+                        "description": "Тип файла запрещен, файл удален",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -685,7 +715,7 @@ const docTemplate = `{
                         }
                     },
                     "500": {
-                        "description": "Internal Server Error",
+                        "description": "Внутренняя ошибка сервера",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -991,70 +1021,942 @@ const docTemplate = `{
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "domain.CardData": {
-            "type": "object",
-            "properties": {
-                "cvv": {
-                    "type": "string"
-                },
-                "expiry_date": {
-                    "type": "string"
-                },
-                "holder": {
-                    "type": "string"
-                },
-                "number": {
-                    "type": "string"
+        },
+        "/api/user/settings": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Возвращает действующие настройки аккаунта: заданные пользователем или значения сервера по умолчанию",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Настройки аккаунта",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer токен авторизации",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Настройки аккаунта",
+                        "schema": {
+                            "$ref": "#/definitions/domain.UserSettings"
+                        }
+                    },
+                    "401": {
+                        "description": "Пользователь не авторизован",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Сохраняет настройки аккаунта. password_history - сколько прежних паролей хранится у учетных данных, от 0 до 100.\nУменьшение длины истории применяется к записи при ее следующем сохранении",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Изменить настройки аккаунта",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer токен авторизации",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Настройки аккаунта",
+                        "name": "settings",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/domain.UserSettings"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Сохраненные настройки",
+                        "schema": {
+                            "$ref": "#/definitions/domain.UserSettings"
+                        }
+                    },
+                    "400": {
+                        "description": "Недопустимые настройки",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Пользователь не авторизован",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
                 }
             }
         },
-        "domain.CredentialData": {
-            "type": "object",
-            "properties": {
-                "login": {
-                    "type": "string"
-                },
-                "password": {
-                    "type": "string"
+        "/api/user/usage": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Возвращает количество записей, файлов, суммарный размер файлов и действующую квоту пользователя",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Потребление хранилища",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer токен авторизации",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Текущее потребление и квота",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Usage"
+                        }
+                    },
+                    "401": {
+                        "description": "Пользователь не авторизован",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
                 }
             }
         },
-        "domain.Credentials": {
-            "type": "object",
-            "properties": {
-                "login": {
-                    "type": "string"
-                },
-                "password": {
-                    "type": "string"
+        "/api/v2/items": {
+            "get": {
+                "description": "Возвращает тип, метку, метаданные и даты изменения всех записей пользователя без их содержимого",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "items"
+                ],
+                "summary": "Получить список записей",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer токен",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/domain.ItemInfo"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
                 }
             }
         },
-        "domain.FileData": {
-            "type": "object",
-            "required": [
-                "extension",
-                "name"
-            ],
-            "properties": {
-                "extension": {
+        "/api/v2/items/{type}/{label}": {
+            "get": {
+                "description": "Получает поля записи в data по типу и метке. С redact=true значения секретных полей заменяются маской,\nу номера карты остаются видны последние четыре цифры",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "items"
+                ],
+                "summary": "Получить запись",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer токен",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Тип записи",
+                        "name": "type",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Метка для идентификации данных",
+                        "name": "label",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Скрыть значения секретных полей",
+                        "name": "redact",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Поля записи в data с метаинформацией",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Сохраняет запись типа из реестра: credential, card, text, ssh_key, identity, bank_account, api_token, wifi или license,\nили пользовательского типа из шаблона. Поля записи передаются в data и проверяются по описанию типа",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "items"
+                ],
+                "summary": "Сохранить запись",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer токен",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Тип записи",
+                        "name": "type",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Метка для идентификации данных",
+                        "name": "label",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Поля записи в data с метаинформацией",
+                        "name": "item",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Удаляет запись по типу и метке",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "items"
+                ],
+                "summary": "Удалить запись",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer токен",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Тип записи",
+                        "name": "type",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Метка для идентификации данных",
+                        "name": "label",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v2/templates": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "templates"
+                ],
+                "summary": "Получить список шаблонов типов записей",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer токен",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/domain.ItemTemplate"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v2/templates/{name}": {
+            "get": {
+                "description": "Возвращает имя, схему и даты изменения шаблона",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "templates"
+                ],
+                "summary": "Получить шаблон типа записей",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer токен",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Имя типа",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ItemTemplate"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Сохраняет пользовательский тип, описанный подмножеством JSON Schema: объект со строковыми полями в properties.\nВид поля задают enum, writeOnly (секрет), format uri или date, остальные поля - текст. Шаблон с тем же именем заменяется",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "templates"
+                ],
+                "summary": "Сохранить шаблон типа записей",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer токен",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Имя типа",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Схема шаблона",
+                        "name": "schema",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ItemTemplate"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Шаблон, по которому сохранены записи, не удаляется",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "templates"
+                ],
+                "summary": "Удалить шаблон типа записей",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer токен",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Имя типа",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "domain.CredentialURI": {
+            "type": "object",
+            "properties": {
+                "match": {
+                    "type": "string"
+                },
+                "uri": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.Credentials": {
+            "type": "object",
+            "properties": {
+                "login": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.FileData": {
+            "type": "object",
+            "required": [
+                "extension",
+                "name",
+                "size"
+            ],
+            "properties": {
+                "extension": {
+                    "type": "string"
+                },
+                "metadata": {
+                    "type": "string"
+                },
+                "mime_type": {
                     "type": "string"
                 },
                 "name": {
                     "type": "string"
+                },
+                "original_name": {
+                    "type": "string"
+                },
+                "size": {
+                    "type": "integer"
+                }
+            }
+        },
+        "domain.FileInfo": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "encrypted": {
+                    "type": "boolean"
+                },
+                "extension": {
+                    "type": "string"
+                },
+                "label": {
+                    "type": "string"
+                },
+                "metadata": {
+                    "type": "string"
+                },
+                "mime_type": {
+                    "type": "string"
+                },
+                "original_name": {
+                    "type": "string"
+                },
+                "size": {
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.FileRename": {
+            "type": "object",
+            "required": [
+                "label",
+                "new_label"
+            ],
+            "properties": {
+                "label": {
+                    "type": "string"
+                },
+                "new_label": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.ItemInfo": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "label": {
+                    "type": "string"
+                },
+                "metadata": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "uris": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.CredentialURI"
+                    }
                 }
             }
         },
-        "domain.TextData": {
+        "domain.ItemTemplate": {
             "type": "object",
             "properties": {
-                "content": {
+                "created_at": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "schema": {
+                    "type": "object"
+                },
+                "updated_at": {
                     "type": "string"
                 }
             }
+        },
+        "domain.Quota": {
+            "type": "object",
+            "properties": {
+                "max_file_size": {
+                    "type": "integer"
+                },
+                "max_items": {
+                    "type": "integer"
+                },
+                "max_total_bytes": {
+                    "type": "integer"
+                }
+            }
+        },
+        "domain.Usage": {
+            "type": "object",
+            "properties": {
+                "files": {
+                    "type": "integer"
+                },
+                "items": {
+                    "type": "integer"
+                },
+                "quota": {
+                    "$ref": "#/definitions/domain.Quota"
+                },
+                "total_bytes": {
+                    "type": "integer"
+                }
+            }
+        },
+        "domain.UserSettings": {
+            "type": "object",
+            "properties": {
+                "password_history": {
+                    "type": "integer"
+                }
+            }
         }
     }
 }`
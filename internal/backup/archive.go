@@ -0,0 +1,236 @@
+package backup
+
+import (
+	"archive/tar"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"github.com/SmirnovND/gophkeeper/internal/domain"
+	"github.com/SmirnovND/gophkeeper/internal/itemtype"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Имена внутри архива резервной копии
+const (
+	manifestName = "manifest.json"
+	filesDir     = "files/"
+	// FormatName - значение поля format манифеста
+	FormatName = "gophkeeper-backup"
+	// maxManifestSize ограничивает манифест при чтении
+	maxManifestSize = 256 << 20
+)
+
+// Manifest - содержимое резервной копии, кроме самих файлов. Templates - шаблоны пользовательских типов,
+// они восстанавливаются раньше записей
+type Manifest struct {
+	Format    string                `json:"format"`
+	Version   int                   `json:"version"`
+	CreatedAt time.Time             `json:"created_at"`
+	Templates []domain.ItemTemplate `json:"templates,omitempty"`
+	Items     []Item                `json:"items"`
+}
+
+// Item - запись хранилища. Заполнено только поле содержимого ее типа, у структурированных типов - поле Data
+type Item struct {
+	Type       string                 `json:"type"`
+	Label      string                 `json:"label"`
+	Metadata   string                 `json:"metadata"`
+	CreatedAt  time.Time              `json:"created_at"`
+	UpdatedAt  time.Time              `json:"updated_at"`
+	Credential *domain.CredentialData `json:"credential,omitempty"`
+	Card       *domain.CardData       `json:"card,omitempty"`
+	Text       *domain.TextData       `json:"text,omitempty"`
+	File       *File                  `json:"file,omitempty"`
+	SSHKey     *domain.SSHKeyData     `json:"ssh_key,omitempty"`
+	Data       domain.ItemData        `json:"data,omitempty"`
+}
+
+// File - сведения о файле и путь к его содержимому в архиве
+type File struct {
+	Path         string `json:"path"`
+	Extension    string `json:"extension"`
+	Size         int64  `json:"size"`
+	MimeType     string `json:"mime_type"`
+	OriginalName string `json:"original_name"`
+	SHA256       string `json:"sha256"`
+}
+
+// check проверяет, что у записи заполнено содержимое ее типа. custom - имена пользовательских типов из шаблонов копии
+func (item *Item) check(custom map[string]bool) error {
+	var ok bool
+	switch item.Type {
+	case domain.UserDataTypeCredential:
+		ok = item.Credential != nil
+	case domain.UserDataTypeCard:
+		ok = item.Card != nil
+	case domain.UserDataTypeText:
+		ok = item.Text != nil
+	case domain.UserDataTypeFile:
+		ok = item.File != nil && item.File.Path != ""
+	case domain.UserDataTypeSSHKey:
+		ok = item.SSHKey != nil
+	default:
+		if _, err := itemtype.Lookup(item.Type); err == nil || custom[item.Type] {
+			ok = item.Data != nil
+			break
+		}
+		return fmt.Errorf("%w: неизвестный тип '%s' записи '%s'", ErrFormat, item.Type, item.Label)
+	}
+	if !ok {
+		return fmt.Errorf("%w: нет содержимого записи '%s'", ErrFormat, item.Label)
+	}
+	return nil
+}
+
+// FilePath возвращает путь к содержимому index-го файла в архиве
+func FilePath(index int) string {
+	return fmt.Sprintf("%s%06d", filesDir, index)
+}
+
+// WriteArchive записывает в w tar-архив: сначала манифест, затем содержимое файлов, открываемое через open
+func WriteArchive(w io.Writer, manifest *Manifest, open func(file *File) (io.ReadCloser, error)) error {
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	archive := tar.NewWriter(w)
+	if err := archive.WriteHeader(&tar.Header{Name: manifestName, Mode: 0o600, Size: int64(len(data))}); err != nil {
+		return err
+	}
+	if _, err := archive.Write(data); err != nil {
+		return err
+	}
+
+	for _, item := range manifest.Items {
+		if item.File == nil {
+			continue
+		}
+		if err := writeFile(archive, item.File, open); err != nil {
+			return fmt.Errorf("файл '%s': %w", item.Label, err)
+		}
+	}
+	return archive.Close()
+}
+
+func writeFile(archive *tar.Writer, file *File, open func(file *File) (io.ReadCloser, error)) error {
+	content, err := open(file)
+	if err != nil {
+		return err
+	}
+	defer content.Close()
+
+	if err := archive.WriteHeader(&tar.Header{Name: file.Path, Mode: 0o600, Size: file.Size}); err != nil {
+		return err
+	}
+	_, err = io.Copy(archive, content)
+	return err
+}
+
+// ReadArchive читает архив, сохраняет содержимое файлов в dir и проверяет целостность: манифест идет первым,
+// у каждого файла манифеста есть содержимое с указанными размером и SHA-256, лишних данных нет.
+// Возвращает манифест и пути к сохраненным файлам по путям в архиве
+func ReadArchive(r io.Reader, dir string) (*Manifest, map[string]string, error) {
+	archive := tar.NewReader(r)
+
+	header, err := archive.Next()
+	if err != nil {
+		return nil, nil, err
+	}
+	if header.Name != manifestName || header.Size > maxManifestSize {
+		return nil, nil, fmt.Errorf("%w: манифест не найден", ErrFormat)
+	}
+	var manifest Manifest
+	data, err := io.ReadAll(archive)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, nil, fmt.Errorf("%w: неверный манифест: %v", ErrFormat, err)
+	}
+	if manifest.Format != FormatName || manifest.Version != Version {
+		return nil, nil, fmt.Errorf("%w: неподдерживаемый манифест %s версии %d", ErrFormat, manifest.Format, manifest.Version)
+	}
+
+	custom := make(map[string]bool, len(manifest.Templates))
+	for _, template := range manifest.Templates {
+		if custom[template.Name] {
+			return nil, nil, fmt.Errorf("%w: повторяющийся шаблон '%s'", ErrFormat, template.Name)
+		}
+		if _, err := itemtype.ParseSchema(template.Name, template.Schema); err != nil {
+			return nil, nil, fmt.Errorf("%w: шаблон '%s': %v", ErrFormat, template.Name, err)
+		}
+		custom[template.Name] = true
+	}
+
+	expected := make(map[string]*File)
+	labels := make(map[string]bool)
+	for i := range manifest.Items {
+		item := &manifest.Items[i]
+		if item.Label == "" || labels[item.Label] {
+			return nil, nil, fmt.Errorf("%w: пустая или повторяющаяся метка '%s'", ErrFormat, item.Label)
+		}
+		labels[item.Label] = true
+		if err := item.check(custom); err != nil {
+			return nil, nil, err
+		}
+		if item.File != nil {
+			if expected[item.File.Path] != nil {
+				return nil, nil, fmt.Errorf("%w: повторяющийся путь файла '%s'", ErrFormat, item.File.Path)
+			}
+			expected[item.File.Path] = item.File
+		}
+	}
+
+	paths := make(map[string]string, len(expected))
+	for {
+		header, err := archive.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+
+		file, ok := expected[header.Name]
+		if !ok || paths[header.Name] != "" {
+			return nil, nil, fmt.Errorf("%w: неожиданный элемент архива '%s'", ErrFormat, header.Name)
+		}
+		path, err := saveFile(archive, file, dir, len(paths))
+		if err != nil {
+			return nil, nil, err
+		}
+		paths[header.Name] = path
+	}
+
+	for name := range expected {
+		if paths[name] == "" {
+			return nil, nil, fmt.Errorf("%w: нет содержимого файла '%s'", ErrFormat, name)
+		}
+	}
+	return &manifest, paths, nil
+}
+
+// saveFile сохраняет содержимое файла в dir и проверяет его размер и SHA-256
+func saveFile(r io.Reader, file *File, dir string, index int) (string, error) {
+	path := filepath.Join(dir, fmt.Sprintf("%06d", index))
+	out, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_EXCL, 0o600)
+	if err != nil {
+		return "", err
+	}
+	defer out.Close()
+
+	hash := sha256.New()
+	size, err := io.Copy(io.MultiWriter(out, hash), r)
+	if err != nil {
+		return "", err
+	}
+	if size != file.Size || hex.EncodeToString(hash.Sum(nil)) != file.SHA256 {
+		return "", fmt.Errorf("%w: содержимое '%s' не совпадает с контрольной суммой", ErrFormat, file.Path)
+	}
+	return path, out.Close()
+}
@@ -0,0 +1,36 @@
+package backup
+
+import (
+	"io"
+)
+
+// Write записывает в w зашифрованную парольной фразой копию: манифест и содержимое файлов, открываемое через open
+func Write(w io.Writer, passphrase []byte, manifest *Manifest, open func(file *File) (io.ReadCloser, error)) error {
+	encrypted, err := NewWriter(w, passphrase)
+	if err != nil {
+		return err
+	}
+	if err := WriteArchive(encrypted, manifest, open); err != nil {
+		return err
+	}
+	return encrypted.Close()
+}
+
+// Read расшифровывает копию целиком, сохраняет содержимое файлов в dir и проверяет целостность.
+// Копия считается верной, только если прочитан последний фрагмент, поэтому результат можно использовать
+// лишь при отсутствии ошибки
+func Read(r io.Reader, passphrase []byte, dir string) (*Manifest, map[string]string, error) {
+	decrypted, err := NewReader(r, passphrase)
+	if err != nil {
+		return nil, nil, err
+	}
+	manifest, paths, err := ReadArchive(decrypted, dir)
+	if err != nil {
+		return nil, nil, err
+	}
+	// tar не читает данные после конца архива, но последний фрагмент должен быть проверен
+	if _, err := io.Copy(io.Discard, decrypted); err != nil {
+		return nil, nil, err
+	}
+	return manifest, paths, nil
+}
@@ -0,0 +1,181 @@
+package backup
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"github.com/SmirnovND/gophkeeper/internal/domain"
+	"io"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+var passphrase = []byte("correct horse battery staple")
+
+// testManifest возвращает манифест с записями всех типов и содержимое файла размером больше одного фрагмента
+func testManifest() (*Manifest, []byte) {
+	content := bytes.Repeat([]byte("0123456789abcdef"), chunkSize/8+3)
+	sum := sha256.Sum256(content)
+	created := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	return &Manifest{
+		Format:    FormatName,
+		Version:   Version,
+		CreatedAt: created,
+		Templates: []domain.ItemTemplate{
+			{Name: "db_profile", Schema: json.RawMessage(`{"type":"object","properties":{"host":{"type":"string"},"password":{"type":"string","writeOnly":true}}}`)},
+		},
+		Items: []Item{
+			{Type: domain.UserDataTypeCredential, Label: "github", Metadata: "URL: https://github.com", CreatedAt: created, UpdatedAt: created,
+				Credential: &domain.CredentialData{Login: "alice", Password: "s3cret"}},
+			{Type: domain.UserDataTypeCard, Label: "visa", Card: &domain.CardData{Number: "4111111111111111", ExpiryDate: "03/27", CVV: "123", Holder: "ALICE"}},
+			{Type: domain.UserDataTypeText, Label: "note", Text: &domain.TextData{Content: "секрет"}},
+			{Type: domain.UserDataTypeFile, Label: "key", File: &File{
+				Path: FilePath(0), Extension: "bin", Size: int64(len(content)), MimeType: "application/octet-stream",
+				OriginalName: "key.bin", SHA256: hex.EncodeToString(sum[:]),
+			}},
+			{Type: domain.UserDataTypeAPIToken, Label: "token", Data: domain.ItemData{"service": "GitHub", "token": "ghp_x"}},
+			{Type: "db_profile", Label: "prod", Data: domain.ItemData{"host": "db.local", "password": "s3cret"}},
+		},
+	}, content
+}
+
+// writeBackup возвращает зашифрованную копию манифеста с содержимым файла content
+func writeBackup(t *testing.T, manifest *Manifest, content []byte) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	err := Write(&buf, passphrase, manifest, func(file *File) (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(content)), nil
+	})
+	if err != nil {
+		t.Fatalf("Неожиданная ошибка записи: %v", err)
+	}
+	return buf.Bytes()
+}
+
+// TestRoundTrip тестирует восстановление записей и файлов из созданной копии
+func TestRoundTrip(t *testing.T) {
+	manifest, content := testManifest()
+	data := writeBackup(t, manifest, content)
+	if !bytes.HasPrefix(data, []byte(Magic)) {
+		t.Fatal("Копия должна начинаться с сигнатуры")
+	}
+	if bytes.Contains(data, []byte("s3cret")) || bytes.Contains(data, content[:64]) {
+		t.Fatal("Копия содержит открытые данные")
+	}
+
+	dir := t.TempDir()
+	got, paths, err := Read(bytes.NewReader(data), passphrase, dir)
+	if err != nil {
+		t.Fatalf("Неожиданная ошибка чтения: %v", err)
+	}
+	if len(got.Items) != 6 || len(got.Templates) != 1 || got.Items[5].Data["host"] != "db.local" || got.Items[0].Credential.Password != "s3cret" || got.Items[1].Card.CVV != "123" ||
+		got.Items[2].Text.Content != "секрет" || got.Items[4].Data["token"] != "ghp_x" || !got.Items[0].CreatedAt.Equal(manifest.Items[0].CreatedAt) {
+		t.Errorf("Записи восстановлены неверно: %+v", got.Items)
+	}
+
+	restored, err := os.ReadFile(paths[FilePath(0)])
+	if err != nil {
+		t.Fatalf("Файл не сохранен: %v", err)
+	}
+	if !bytes.Equal(restored, content) {
+		t.Error("Содержимое файла восстановлено неверно")
+	}
+}
+
+// TestRead_Errors тестирует отказ от неверной парольной фразы, поврежденных и обрезанных копий
+func TestRead_Errors(t *testing.T) {
+	manifest, content := testManifest()
+	data := writeBackup(t, manifest, content)
+	headerEnd := len(Magic) + 4 + int(binary.BigEndian.Uint32(data[len(Magic):]))
+
+	flip := func(offset int) []byte {
+		damaged := bytes.Clone(data)
+		damaged[offset] ^= 1
+		return damaged
+	}
+
+	tests := []struct {
+		name       string
+		data       []byte
+		passphrase []byte
+		want       error
+	}{
+		{"неверная парольная фраза", data, []byte("wrong passphrase"), ErrPassphrase},
+		{"не резервная копия", []byte("hello world, this is not a backup"), passphrase, ErrFormat},
+		{"измененный заголовок", flip(headerEnd - 3), passphrase, nil},
+		{"измененный фрагмент", flip(headerEnd + 100), passphrase, ErrPassphrase},
+		{"обрезан посередине", data[:len(data)/2], passphrase, ErrTruncated},
+		// Без последнего фрагмента копия выглядит завершенной на границе фрагментов
+		{"нет последнего фрагмента", data[:headerEnd+4+chunkSize+16], passphrase, ErrTruncated},
+		{"данные после конца", append(bytes.Clone(data), 0), passphrase, ErrFormat},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := Read(bytes.NewReader(tt.data), tt.passphrase, t.TempDir())
+			if err == nil {
+				t.Fatal("Ожидалась ошибка")
+			}
+			if tt.want != nil && !errors.Is(err, tt.want) {
+				t.Errorf("Ожидалась ошибка %v, получено: %v", tt.want, err)
+			}
+		})
+	}
+}
+
+// TestRead_Integrity тестирует проверку содержимого файлов и манифеста
+func TestRead_Integrity(t *testing.T) {
+	manifest, content := testManifest()
+	manifest.Items[3].File.SHA256 = strings.Repeat("0", 64)
+	if _, _, err := Read(bytes.NewReader(writeBackup(t, manifest, content)), passphrase, t.TempDir()); !errors.Is(err, ErrFormat) {
+		t.Errorf("Ожидалась ошибка контрольной суммы, получено: %v", err)
+	}
+
+	manifest, content = testManifest()
+	manifest.Items[2].Label = "github"
+	if _, _, err := Read(bytes.NewReader(writeBackup(t, manifest, content)), passphrase, t.TempDir()); !errors.Is(err, ErrFormat) {
+		t.Errorf("Ожидалась ошибка повторяющейся метки, получено: %v", err)
+	}
+
+	manifest, content = testManifest()
+	manifest.Items[1].Card = nil
+	if _, _, err := Read(bytes.NewReader(writeBackup(t, manifest, content)), passphrase, t.TempDir()); !errors.Is(err, ErrFormat) {
+		t.Errorf("Ожидалась ошибка записи без содержимого, получено: %v", err)
+	}
+
+	// Запись пользовательского типа требует шаблона в копии
+	manifest, content = testManifest()
+	manifest.Templates = nil
+	if _, _, err := Read(bytes.NewReader(writeBackup(t, manifest, content)), passphrase, t.TempDir()); !errors.Is(err, ErrFormat) {
+		t.Errorf("Ожидалась ошибка записи без шаблона, получено: %v", err)
+	}
+
+	manifest, content = testManifest()
+	manifest.Templates[0].Schema = json.RawMessage(`{"type":"object","properties":{"host":{"type":"integer"}}}`)
+	if _, _, err := Read(bytes.NewReader(writeBackup(t, manifest, content)), passphrase, t.TempDir()); !errors.Is(err, ErrFormat) {
+		t.Errorf("Ожидалась ошибка неверного шаблона, получено: %v", err)
+	}
+}
+
+// TestNewReader_HeaderLimits тестирует отказ от заголовков с недопустимыми параметрами Argon2id
+func TestNewReader_HeaderLimits(t *testing.T) {
+	header := Header{
+		Version:     Version,
+		KDF:         KDF{Name: "argon2id", Time: 1, Memory: 16 * 1024 * 1024, Threads: 1, Salt: make([]byte, argonSaltSize)},
+		Cipher:      "aes-256-gcm",
+		ChunkSize:   chunkSize,
+		NoncePrefix: make([]byte, noncePrefixSize),
+	}
+	data, _ := json.Marshal(header)
+	file := append(binary.BigEndian.AppendUint32([]byte(Magic), uint32(len(data))), data...)
+
+	if _, err := NewReader(bytes.NewReader(file), passphrase); !errors.Is(err, ErrFormat) {
+		t.Errorf("Ожидалась ошибка параметров Argon2id, получено: %v", err)
+	}
+}
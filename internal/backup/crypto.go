@@ -0,0 +1,308 @@
+package backup
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"golang.org/x/crypto/argon2"
+	"io"
+)
+
+// Magic - сигнатура в начале файла резервной копии
+const Magic = "GKBACKUP"
+
+// Параметры формата
+const (
+	// Version - версия формата файла
+	Version = 1
+	// chunkSize - размер открытого текста одного фрагмента
+	chunkSize = 64 * 1024
+	// noncePrefixSize - случайная часть nonce, остальные 5 байт - номер фрагмента и признак последнего фрагмента
+	noncePrefixSize = 7
+	// maxHeaderSize ограничивает заголовок при чтении
+	maxHeaderSize = 4096
+)
+
+// Параметры Argon2id при создании копии (RFC 9106, второй рекомендуемый вариант) и ограничения при чтении,
+// чтобы поддельный заголовок не заставлял выделять неограниченную память
+const (
+	argonTime       = 3
+	argonMemory     = 64 * 1024
+	argonThreads    = 4
+	argonSaltSize   = 16
+	maxArgonTime    = 10
+	maxArgonMemory  = 1024 * 1024
+	maxArgonThreads = 64
+)
+
+// ErrPassphrase возвращается, если фрагмент не расшифровывается: парольная фраза неверна или файл поврежден
+var ErrPassphrase = errors.New("неверная парольная фраза или файл резервной копии поврежден")
+
+// ErrTruncated возвращается, если файл закончился до последнего фрагмента
+var ErrTruncated = errors.New("файл резервной копии обрезан")
+
+// ErrFormat возвращается для файлов, не являющихся резервной копией gophkeeper
+var ErrFormat = errors.New("файл не является резервной копией gophkeeper")
+
+// Header - открытый заголовок файла. Все фрагменты аутентифицируются вместе с ним,
+// поэтому изменить параметры, не повредив копию, нельзя
+type Header struct {
+	Version     int    `json:"version"`
+	KDF         KDF    `json:"kdf"`
+	Cipher      string `json:"cipher"`
+	ChunkSize   int    `json:"chunk_size"`
+	NoncePrefix []byte `json:"nonce_prefix"`
+}
+
+// KDF - параметры получения ключа из парольной фразы
+type KDF struct {
+	Name    string `json:"name"`
+	Time    uint32 `json:"time"`
+	Memory  uint32 `json:"memory"`
+	Threads uint8  `json:"threads"`
+	Salt    []byte `json:"salt"`
+}
+
+// stream шифрует или расшифровывает последовательность фрагментов конструкцией STREAM:
+// nonce = префикс || номер фрагмента || признак последнего фрагмента
+type stream struct {
+	aead    cipher.AEAD
+	prefix  []byte
+	aad     []byte
+	counter uint32
+}
+
+func newStream(header *Header, headerData []byte, passphrase []byte) (*stream, error) {
+	key := argon2.IDKey(passphrase, header.KDF.Salt, header.KDF.Time, header.KDF.Memory, header.KDF.Threads, 32)
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &stream{aead: aead, prefix: header.NoncePrefix, aad: append([]byte(Magic), headerData...)}, nil
+}
+
+func (s *stream) nonce(last bool) ([]byte, error) {
+	if s.counter == ^uint32(0) {
+		return nil, errors.New("превышено количество фрагментов")
+	}
+	nonce := make([]byte, 0, s.aead.NonceSize())
+	nonce = append(nonce, s.prefix...)
+	nonce = binary.BigEndian.AppendUint32(nonce, s.counter)
+	if last {
+		nonce = append(nonce, 1)
+	} else {
+		nonce = append(nonce, 0)
+	}
+	s.counter++
+	return nonce, nil
+}
+
+// writer шифрует данные фрагментами
+type writer struct {
+	w      io.Writer
+	stream *stream
+	buf    []byte
+	closed bool
+}
+
+// NewWriter записывает заголовок и возвращает writer, шифрующий данные ключом из passphrase.
+// Close записывает последний фрагмент, без него копия считается обрезанной
+func NewWriter(w io.Writer, passphrase []byte) (io.WriteCloser, error) {
+	header := &Header{
+		Version: Version,
+		KDF: KDF{
+			Name:    "argon2id",
+			Time:    argonTime,
+			Memory:  argonMemory,
+			Threads: argonThreads,
+			Salt:    make([]byte, argonSaltSize),
+		},
+		Cipher:      "aes-256-gcm",
+		ChunkSize:   chunkSize,
+		NoncePrefix: make([]byte, noncePrefixSize),
+	}
+	if _, err := rand.Read(header.KDF.Salt); err != nil {
+		return nil, err
+	}
+	if _, err := rand.Read(header.NoncePrefix); err != nil {
+		return nil, err
+	}
+
+	headerData, err := json.Marshal(header)
+	if err != nil {
+		return nil, err
+	}
+	prefix := binary.BigEndian.AppendUint32([]byte(Magic), uint32(len(headerData)))
+	if _, err := w.Write(append(prefix, headerData...)); err != nil {
+		return nil, err
+	}
+
+	s, err := newStream(header, headerData, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	return &writer{w: w, stream: s, buf: make([]byte, 0, chunkSize)}, nil
+}
+
+func (w *writer) Write(p []byte) (int, error) {
+	if w.closed {
+		return 0, errors.New("запись в закрытую резервную копию")
+	}
+	written := 0
+	for len(p) > 0 {
+		n := copy(w.buf[len(w.buf):cap(w.buf)], p)
+		w.buf = w.buf[:len(w.buf)+n]
+		p = p[n:]
+		written += n
+		// Полный фрагмент записывается, только когда есть следующие данные: последний фрагмент пишет Close
+		if len(w.buf) == cap(w.buf) && len(p) > 0 {
+			if err := w.flush(false); err != nil {
+				return written, err
+			}
+		}
+	}
+	return written, nil
+}
+
+func (w *writer) flush(last bool) error {
+	nonce, err := w.stream.nonce(last)
+	if err != nil {
+		return err
+	}
+	sealed := w.stream.aead.Seal(nil, nonce, w.buf, w.stream.aad)
+	w.buf = w.buf[:0]
+
+	if _, err := w.w.Write(binary.BigEndian.AppendUint32(nil, uint32(len(sealed)))); err != nil {
+		return err
+	}
+	_, err = w.w.Write(sealed)
+	return err
+}
+
+func (w *writer) Close() error {
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+	return w.flush(true)
+}
+
+// reader расшифровывает фрагменты и проверяет, что последний фрагмент получен
+type reader struct {
+	r      io.Reader
+	stream *stream
+	// maxSealed - наибольший размер зашифрованного фрагмента
+	maxSealed int
+	buf       []byte
+	last      bool
+}
+
+// NewReader читает заголовок и возвращает reader расшифрованных данных. Ошибки ErrPassphrase и ErrTruncated
+// возвращаются при чтении; данные фрагмента выдаются только после его проверки
+func NewReader(r io.Reader, passphrase []byte) (io.Reader, error) {
+	prefix := make([]byte, len(Magic)+4)
+	if _, err := io.ReadFull(r, prefix); err != nil || string(prefix[:len(Magic)]) != Magic {
+		return nil, ErrFormat
+	}
+	size := binary.BigEndian.Uint32(prefix[len(Magic):])
+	if size == 0 || size > maxHeaderSize {
+		return nil, ErrFormat
+	}
+	headerData := make([]byte, size)
+	if _, err := io.ReadFull(r, headerData); err != nil {
+		return nil, ErrTruncated
+	}
+
+	var header Header
+	if err := json.Unmarshal(headerData, &header); err != nil {
+		return nil, fmt.Errorf("%w: неверный заголовок", ErrFormat)
+	}
+	if err := checkHeader(&header); err != nil {
+		return nil, err
+	}
+
+	s, err := newStream(&header, headerData, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	return &reader{r: r, stream: s, maxSealed: header.ChunkSize + s.aead.Overhead()}, nil
+}
+
+// checkHeader проверяет версию формата и ограничения параметров
+func checkHeader(header *Header) error {
+	switch {
+	case header.Version != Version:
+		return fmt.Errorf("%w: неподдерживаемая версия формата %d", ErrFormat, header.Version)
+	case header.KDF.Name != "argon2id" || header.Cipher != "aes-256-gcm":
+		return fmt.Errorf("%w: неподдерживаемые алгоритмы %s и %s", ErrFormat, header.KDF.Name, header.Cipher)
+	case header.ChunkSize <= 0 || header.ChunkSize > 16*chunkSize:
+		return fmt.Errorf("%w: неверный размер фрагмента", ErrFormat)
+	case len(header.NoncePrefix) != noncePrefixSize || len(header.KDF.Salt) < argonSaltSize:
+		return fmt.Errorf("%w: неверные nonce или соль", ErrFormat)
+	case header.KDF.Time == 0 || header.KDF.Time > maxArgonTime ||
+		header.KDF.Memory == 0 || header.KDF.Memory > maxArgonMemory ||
+		header.KDF.Threads == 0 || header.KDF.Threads > maxArgonThreads:
+		return fmt.Errorf("%w: недопустимые параметры Argon2id", ErrFormat)
+	}
+	return nil
+}
+
+func (r *reader) Read(p []byte) (int, error) {
+	for len(r.buf) == 0 {
+		if r.last {
+			return 0, io.EOF
+		}
+		if err := r.next(); err != nil {
+			return 0, err
+		}
+	}
+	n := copy(p, r.buf)
+	r.buf = r.buf[n:]
+	return n, nil
+}
+
+// next читает и расшифровывает следующий фрагмент. Признак последнего фрагмента входит в nonce,
+// поэтому фрагмент не может выдать себя за последний, а отсутствие последнего фрагмента обнаруживается
+func (r *reader) next() error {
+	var size [4]byte
+	if _, err := io.ReadFull(r.r, size[:]); err != nil {
+		return ErrTruncated
+	}
+	length := binary.BigEndian.Uint32(size[:])
+	if length < uint32(r.stream.aead.Overhead()) || length > uint32(r.maxSealed) {
+		return ErrPassphrase
+	}
+	sealed := make([]byte, length)
+	if _, err := io.ReadFull(r.r, sealed); err != nil {
+		return ErrTruncated
+	}
+
+	for _, last := range []bool{false, true} {
+		counter := r.stream.counter
+		nonce, err := r.stream.nonce(last)
+		if err != nil {
+			return err
+		}
+		if plain, err := r.stream.aead.Open(nil, nonce, sealed, r.stream.aad); err == nil {
+			r.buf, r.last = plain, last
+			if last {
+				// После последнего фрагмента данных быть не должно
+				var extra [1]byte
+				if n, _ := r.r.Read(extra[:]); n > 0 {
+					return fmt.Errorf("%w: данные после последнего фрагмента", ErrFormat)
+				}
+			}
+			return nil
+		}
+		r.stream.counter = counter
+	}
+	return ErrPassphrase
+}
@@ -0,0 +1,231 @@
+package clipboard
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"github.com/mattn/go-isatty"
+	"os"
+	"os/exec"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Названия способов записи в буфер обмена, Auto выбирает способ по окружению
+const (
+	Auto    = "auto"
+	OSC52   = "osc52"
+	PBCopy  = "pbcopy"
+	WLCopy  = "wl-copy"
+	XClip   = "xclip"
+	XSel    = "xsel"
+	Windows = "windows"
+)
+
+// ErrUnreadable возвращается способом записи, который не умеет читать буфер обмена
+var ErrUnreadable = errors.New("содержимое буфера обмена нельзя прочитать")
+
+// ErrNoBackend возвращается, если в окружении нет ни одного способа записи в буфер обмена
+var ErrNoBackend = errors.New("не найден способ записи в буфер обмена: установите wl-clipboard, xclip или xsel, " +
+	"либо используйте терминал с поддержкой OSC 52")
+
+// Backend - способ записи в буфер обмена
+type Backend interface {
+	// Name возвращает название способа, по нему способ можно выбрать явно
+	Name() string
+	Write(value []byte) error
+	// Read возвращает содержимое буфера обмена или ErrUnreadable
+	Read() ([]byte, error)
+	Clear() error
+}
+
+// Зависимости от окружения, в тестах заменяются
+var (
+	lookPath = exec.LookPath
+	goos     = runtime.GOOS
+	// terminal возвращает терминал для escape-последовательностей OSC 52
+	terminal = func() (*os.File, bool) {
+		fd := os.Stderr.Fd()
+		return os.Stderr, isatty.IsTerminal(fd) || isatty.IsCygwinTerminal(fd)
+	}
+)
+
+// backends - способы записи, вызывающие программы работы с буфером обмена
+var backends = map[string]*commandBackend{
+	PBCopy: {name: PBCopy, write: []string{"pbcopy"}, read: []string{"pbpaste"}},
+	WLCopy: {name: WLCopy, write: []string{"wl-copy"}, read: []string{"wl-paste", "--no-newline"},
+		clear: []string{"wl-copy", "--clear"}},
+	XClip: {name: XClip, write: []string{"xclip", "-selection", "clipboard", "-in"},
+		read: []string{"xclip", "-selection", "clipboard", "-out"}},
+	XSel: {name: XSel, write: []string{"xsel", "--clipboard", "--input"}, read: []string{"xsel", "--clipboard", "--output"}},
+	// clip.exe искажает символы не из кодовой страницы консоли, поэтому значение записывается через PowerShell в UTF-8
+	Windows: {name: Windows,
+		write: []string{"powershell.exe", "-NoProfile", "-NonInteractive", "-Command",
+			"[Console]::InputEncoding = [Text.Encoding]::UTF8; Set-Clipboard -Value ([Console]::In.ReadToEnd())"},
+		read: []string{"powershell.exe", "-NoProfile", "-NonInteractive", "-Command",
+			"[Console]::OutputEncoding = [Text.Encoding]::UTF8; Get-Clipboard -Raw"},
+		clear:    []string{"clip.exe"},
+		trimRead: true},
+}
+
+// Names возвращает названия всех способов записи
+func Names() []string {
+	names := []string{Auto, OSC52}
+	for name := range backends {
+		names = append(names, name)
+	}
+	sort.Strings(names[2:])
+	return names
+}
+
+// Detect возвращает способ записи с названием name. Для Auto выбирается программа буфера обмена
+// текущего графического окружения, а в SSH-сессии без него и при отсутствии программ - OSC 52
+func Detect(name string) (Backend, error) {
+	switch name {
+	case "", Auto:
+		return detectAuto()
+	case OSC52:
+		out, ok := terminal()
+		if !ok {
+			return nil, errors.New("OSC 52 требует вывода в терминал")
+		}
+		return newOSC52(out), nil
+	}
+
+	backend, ok := backends[name]
+	if !ok {
+		return nil, fmt.Errorf("неизвестный способ записи в буфер обмена '%s', допустимы %s", name, strings.Join(Names(), ", "))
+	}
+	if _, err := lookPath(backend.write[0]); err != nil {
+		return nil, fmt.Errorf("программа %s не найдена", backend.write[0])
+	}
+	return backend, nil
+}
+
+// detectAuto выбирает способ записи по операционной системе и переменным окружения
+func detectAuto() (Backend, error) {
+	wayland, x11 := os.Getenv("WAYLAND_DISPLAY") != "", os.Getenv("DISPLAY") != ""
+	ssh := os.Getenv("SSH_TTY") != "" || os.Getenv("SSH_CONNECTION") != ""
+
+	var candidates []string
+	switch {
+	case ssh && !wayland && !x11:
+		// Программы удаленной машины не имеют доступа к буферу обмена пользователя
+	case goos == "darwin":
+		candidates = []string{PBCopy}
+	case goos == "windows":
+		candidates = []string{Windows}
+	default:
+		if wayland {
+			candidates = append(candidates, WLCopy)
+		}
+		if x11 {
+			candidates = append(candidates, XClip, XSel)
+		}
+		// В WSL буфер обмена Windows доступен через powershell.exe
+		if os.Getenv("WSL_DISTRO_NAME") != "" {
+			candidates = append(candidates, Windows)
+		}
+	}
+
+	for _, name := range candidates {
+		if backend, err := Detect(name); err == nil {
+			return backend, nil
+		}
+	}
+	if out, ok := terminal(); ok {
+		return newOSC52(out), nil
+	}
+	return nil, ErrNoBackend
+}
+
+// ClearIfUnchanged очищает буфер обмена, если в нем все еще находится значение с хешем sum SHA-256.
+// Если буфер нельзя прочитать, он очищается без проверки. Возвращает true, если буфер очищен
+func ClearIfUnchanged(backend Backend, sum [sha256.Size]byte) (bool, error) {
+	current, err := backend.Read()
+	switch {
+	case errors.Is(err, ErrUnreadable):
+	case err != nil:
+		// Буфер пуст или им владеет другая программа, значит наше значение уже заменено
+		return false, nil
+	case sha256.Sum256(current) != sum:
+		return false, nil
+	}
+
+	if err := backend.Clear(); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// readTimeout - время ожидания программы чтения буфера обмена, readWaitDelay - время ожидания закрытия ее вывода
+// после завершения
+var (
+	readTimeout   = 5 * time.Second
+	readWaitDelay = 500 * time.Millisecond
+)
+
+// commandBackend записывает и читает буфер обмена через внешние программы
+type commandBackend struct {
+	name  string
+	write []string
+	read  []string
+	// clear - команда очистки, без нее в буфер записывается пустое значение
+	clear []string
+	// trimRead - программа чтения добавляет перевод строки после содержимого
+	trimRead bool
+}
+
+func (b *commandBackend) Name() string {
+	return b.name
+}
+
+func (b *commandBackend) Write(value []byte) error {
+	return run(b.write, value)
+}
+
+func (b *commandBackend) Read() ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), readTimeout)
+	defer cancel()
+
+	// Содержимое буфера обмена читается только в память. xclip и wl-copy оставляют в фоне процесс, который держит
+	// унаследованный канал вывода открытым, поэтому после завершения программы закрытия канала ждем не дольше WaitDelay
+	var out bytes.Buffer
+	cmd := exec.CommandContext(ctx, b.read[0], b.read[1:]...)
+	cmd.Stdout = &out
+	cmd.WaitDelay = readWaitDelay
+	if err := cmd.Run(); err != nil && !errors.Is(err, exec.ErrWaitDelay) {
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("%s: буфер обмена не прочитан за %s", b.read[0], readTimeout)
+		}
+		return nil, fmt.Errorf("%s: %w", b.read[0], err)
+	}
+
+	value := out.Bytes()
+	if b.trimRead {
+		value = bytes.TrimSuffix(value, []byte("\n"))
+		value = bytes.TrimSuffix(value, []byte("\r"))
+	}
+	return value, nil
+}
+
+func (b *commandBackend) Clear() error {
+	if b.clear != nil {
+		return run(b.clear, nil)
+	}
+	return run(b.write, nil)
+}
+
+// run выполняет программу args и передает ей stdin
+func run(args []string, stdin []byte) error {
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Stdin = bytes.NewReader(stdin)
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("%s: %w", args[0], err)
+	}
+	return nil
+}
@@ -0,0 +1,222 @@
+package clipboard
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+)
+
+// fakeBackend - буфер обмена в памяти
+type fakeBackend struct {
+	value    []byte
+	readErr  error
+	cleared  bool
+	clearErr error
+}
+
+func (f *fakeBackend) Name() string { return "fake" }
+
+func (f *fakeBackend) Write(value []byte) error {
+	f.value = value
+	return nil
+}
+
+func (f *fakeBackend) Read() ([]byte, error) { return f.value, f.readErr }
+
+func (f *fakeBackend) Clear() error {
+	f.cleared = true
+	return f.clearErr
+}
+
+// fakeXClip устанавливает в PATH программу xclip, хранящую буфер обмена в файле, и возвращает путь к нему
+func fakeXClip(t *testing.T) string {
+	if runtime.GOOS == "windows" {
+		t.Skip("Скрипт оболочки не запускается в Windows")
+	}
+
+	dir := t.TempDir()
+	storage := filepath.Join(dir, "clipboard")
+	script := "#!/bin/sh\ncase \"$*\" in\n*-out*) /bin/cat \"" + storage + "\" ;;\n*) /bin/cat > \"" + storage + "\" ;;\nesac\n"
+	if err := os.WriteFile(filepath.Join(dir, "xclip"), []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir)
+	return storage
+}
+
+// withTerminal подменяет терминал для OSC 52
+func withTerminal(t *testing.T, ok bool) {
+	oldTerminal, oldGOOS := terminal, goos
+	t.Cleanup(func() { terminal, goos = oldTerminal, oldGOOS })
+	terminal = func() (*os.File, bool) { return os.Stderr, ok }
+	goos = "linux"
+}
+
+// TestOSC52 тестирует escape-последовательности записи и очистки буфера обмена
+func TestOSC52(t *testing.T) {
+	var out bytes.Buffer
+	backend := &osc52{out: &out}
+
+	if err := backend.Write([]byte("s3cret")); err != nil {
+		t.Fatalf("Неожиданная ошибка: %v", err)
+	}
+	if err := backend.Clear(); err != nil {
+		t.Fatalf("Неожиданная ошибка: %v", err)
+	}
+	if want := "\x1b]52;c;czNjcmV0\x07\x1b]52;c;\x07"; out.String() != want {
+		t.Errorf("Ожидался вывод %q, получен %q", want, out.String())
+	}
+	if _, err := backend.Read(); !errors.Is(err, ErrUnreadable) {
+		t.Errorf("Ожидалась ошибка ErrUnreadable, получена %v", err)
+	}
+
+	out.Reset()
+	backend.tmux = true
+	if err := backend.Write([]byte("s3cret")); err != nil {
+		t.Fatalf("Неожиданная ошибка: %v", err)
+	}
+	if want := "\x1bPtmux;\x1b\x1b]52;c;czNjcmV0\x07\x1b\\"; out.String() != want {
+		t.Errorf("Ожидался вывод %q, получен %q", want, out.String())
+	}
+}
+
+// TestDetect тестирует автоматический выбор способа записи по окружению
+func TestDetect(t *testing.T) {
+	fakeXClip(t)
+	t.Setenv("WAYLAND_DISPLAY", "")
+	t.Setenv("WSL_DISTRO_NAME", "")
+	t.Setenv("SSH_TTY", "")
+
+	tests := []struct {
+		name     string
+		display  string
+		ssh      string
+		terminal bool
+		want     string
+		wantErr  error
+	}{
+		{name: "X11", display: ":0", want: XClip},
+		{name: "X11 over SSH", display: "localhost:10.0", ssh: "10.0.0.1 22 10.0.0.2 22", want: XClip},
+		{name: "SSH without display", ssh: "10.0.0.1 22 10.0.0.2 22", terminal: true, want: OSC52},
+		{name: "No display", terminal: true, want: OSC52},
+		{name: "Nothing", wantErr: ErrNoBackend},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			withTerminal(t, tt.terminal)
+			t.Setenv("DISPLAY", tt.display)
+			t.Setenv("SSH_CONNECTION", tt.ssh)
+
+			backend, err := Detect(Auto)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("Ожидалась ошибка %v, получена %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Неожиданная ошибка: %v", err)
+			}
+			if backend.Name() != tt.want {
+				t.Errorf("Ожидался способ %s, получен %s", tt.want, backend.Name())
+			}
+		})
+	}
+
+	if _, err := Detect(XSel); err == nil {
+		t.Error("Ожидалась ошибка для отсутствующей программы xsel")
+	}
+	if _, err := Detect("clipboard"); err == nil {
+		t.Error("Ожидалась ошибка для неизвестного способа")
+	}
+}
+
+// TestCommandBackend тестирует запись, чтение и очистку буфера обмена через программу xclip
+func TestCommandBackend(t *testing.T) {
+	storage := fakeXClip(t)
+	backend, err := Detect(XClip)
+	if err != nil {
+		t.Fatalf("Неожиданная ошибка: %v", err)
+	}
+
+	if err := backend.Write([]byte("пароль")); err != nil {
+		t.Fatalf("Неожиданная ошибка: %v", err)
+	}
+	value, err := backend.Read()
+	if err != nil || string(value) != "пароль" {
+		t.Fatalf("Ожидалось значение 'пароль', получено '%s' (%v)", value, err)
+	}
+
+	cleared, err := ClearIfUnchanged(backend, sha256.Sum256([]byte("пароль")))
+	if err != nil || !cleared {
+		t.Fatalf("Буфер обмена не очищен: %v", err)
+	}
+	if data, _ := os.ReadFile(storage); len(data) != 0 {
+		t.Errorf("Ожидался пустой буфер обмена, получено '%s'", data)
+	}
+}
+
+// TestCommandBackend_ReadTimeout тестирует чтение буфера обмена программой, оставляющей в фоне процесс
+// с открытым выводом, и программой, которая не завершается
+func TestCommandBackend_ReadTimeout(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Скрипт оболочки не запускается в Windows")
+	}
+
+	oldTimeout, oldWaitDelay := readTimeout, readWaitDelay
+	t.Cleanup(func() { readTimeout, readWaitDelay = oldTimeout, oldWaitDelay })
+	readTimeout, readWaitDelay = 200*time.Millisecond, 50*time.Millisecond
+
+	dir := t.TempDir()
+	background := filepath.Join(dir, "background")
+	script := "#!/bin/sh\nprintf 'пароль'\n/bin/sleep 5 &\n"
+	if err := os.WriteFile(background, []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Now()
+	value, err := (&commandBackend{name: XClip, read: []string{background}}).Read()
+	if err != nil || string(value) != "пароль" {
+		t.Fatalf("Ожидалось значение 'пароль', получено '%s' (%v)", value, err)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("Чтение ждало фоновый процесс %s", elapsed)
+	}
+
+	if _, err := (&commandBackend{name: XClip, read: []string{"/bin/sleep", "5"}}).Read(); err == nil {
+		t.Error("Ожидалась ошибка для программы чтения, превысившей время ожидания")
+	}
+}
+
+// TestClearIfUnchanged тестирует очистку буфера обмена только со скопированным значением
+func TestClearIfUnchanged(t *testing.T) {
+	sum := sha256.Sum256([]byte("s3cret"))
+	tests := []struct {
+		name    string
+		backend *fakeBackend
+		want    bool
+	}{
+		{name: "Unchanged", backend: &fakeBackend{value: []byte("s3cret")}, want: true},
+		{name: "Replaced", backend: &fakeBackend{value: []byte("other")}, want: false},
+		{name: "Empty", backend: &fakeBackend{readErr: errors.New("nothing is copied")}, want: false},
+		{name: "Unreadable", backend: &fakeBackend{readErr: ErrUnreadable}, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cleared, err := ClearIfUnchanged(tt.backend, sum)
+			if err != nil {
+				t.Fatalf("Неожиданная ошибка: %v", err)
+			}
+			if cleared != tt.want || tt.backend.cleared != tt.want {
+				t.Errorf("Ожидалась очистка %v, получено %v", tt.want, cleared)
+			}
+		})
+	}
+}
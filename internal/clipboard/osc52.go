@@ -0,0 +1,48 @@
+package clipboard
+
+import (
+	"encoding/base64"
+	"io"
+	"os"
+	"strings"
+)
+
+// osc52 записывает буфер обмена escape-последовательностью OSC 52, которую выполняет сам терминал.
+// Способ работает и в SSH-сессии, но прочитать буфер обратно нельзя: большинство терминалов запрещают чтение
+type osc52 struct {
+	out io.Writer
+	// tmux - вывод идет через tmux, последовательность передается терминалу в обертке DCS
+	tmux bool
+}
+
+func newOSC52(out io.Writer) *osc52 {
+	return &osc52{out: out, tmux: os.Getenv("TMUX") != ""}
+}
+
+func (b *osc52) Name() string {
+	return OSC52
+}
+
+func (b *osc52) Write(value []byte) error {
+	_, err := io.WriteString(b.out, b.sequence(base64.StdEncoding.EncodeToString(value)))
+	return err
+}
+
+func (b *osc52) Read() ([]byte, error) {
+	return nil, ErrUnreadable
+}
+
+// Clear записывает пустое значение, терминалы очищают буфер обмена
+func (b *osc52) Clear() error {
+	_, err := io.WriteString(b.out, b.sequence(""))
+	return err
+}
+
+// sequence возвращает escape-последовательность записи payload в base64 в буфер обмена
+func (b *osc52) sequence(payload string) string {
+	sequence := "\x1b]52;c;" + payload + "\x07"
+	if b.tmux {
+		return "\x1bPtmux;" + strings.ReplaceAll(sequence, "\x1b", "\x1b\x1b") + "\x1b\\"
+	}
+	return sequence
+}
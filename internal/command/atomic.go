@@ -0,0 +1,29 @@
+package command
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// writeFileAtomic записывает файл path с правами perm через временный файл в той же директории и переименование,
+// поэтому при ошибке существующий файл не повреждается, а читатели не видят частично записанный файл
+func writeFileAtomic(path string, perm os.FileMode, write func(w io.Writer) error) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	if err := tmp.Chmod(perm); err != nil {
+		return err
+	}
+	if err := write(tmp); err != nil {
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
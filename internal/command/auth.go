@@ -3,56 +3,85 @@ package command
 import (
 	"fmt"
 	"github.com/spf13/cobra"
-	"os"
 )
 
 func (c *Command) Login() *cobra.Command {
-	return &cobra.Command{
-		Use:   "login",
+	cmd := &cobra.Command{
+		Use:   "login [username]",
 		Short: "Авторизация в сервисе",
-		Run: func(cmd *cobra.Command, args []string) {
-			var username, password string
-			
-			fmt.Print("Введите логин: ")
-			fmt.Fscanln(os.Stdin, &username)
-			
-			fmt.Print("Введите пароль: ")
-			fmt.Fscanln(os.Stdin, &password)
-			
-			err := c.clientUseCase.Login(username, password)
+		Long: "Авторизация в сервисе. Без терминала логин передается аргументом или флагом --login,\n" +
+			"а пароль - первой строкой stdin с флагом --password-stdin, например: echo \"$PASS\" | passcli login alice --password-stdin",
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			username, _ := cmd.Flags().GetString("login")
+			passwordStdin, _ := cmd.Flags().GetBool("password-stdin")
+			if len(args) > 0 {
+				username = args[0]
+			}
+
+			in := newInput()
+			username, err := in.require(username, "Введите логин:", "логин задается аргументом или флагом --login")
+			if err != nil {
+				return fail("Ошибка авторизации:", err)
+			}
+			password, err := in.secret(passwordStdin, loginPasswordField)
+			if err != nil {
+				return fail("Ошибка авторизации:", err)
+			}
+
+			err = c.clientUseCase.Login(username, password)
 			if err != nil {
-				fmt.Println("Ошибка авторизации:", err)
-				return
+				return fail("Ошибка авторизации:", err)
 			}
-			
+
 			fmt.Println("Успешная авторизация!")
+			return nil
 		},
 	}
+
+	cmd.Flags().StringP("login", "u", "", "Логин пользователя")
+	cmd.Flags().Bool("password-stdin", false, "Прочитать пароль из первой строки stdin")
+
+	return cmd
 }
 
 func (c *Command) RegisterCmd() *cobra.Command {
-	return &cobra.Command{
-		Use:   "register",
+	cmd := &cobra.Command{
+		Use:   "register [username]",
 		Short: "Регистрация в сервисе",
-		Run: func(cmd *cobra.Command, args []string) {
-			var username, password, passwordCheck string
-			
-			fmt.Print("Введите логин: ")
-			fmt.Fscanln(os.Stdin, &username)
-			
-			fmt.Print("Введите пароль: ")
-			fmt.Fscanln(os.Stdin, &password)
-			
-			fmt.Print("Введите пароль еще раз: ")
-			fmt.Fscanln(os.Stdin, &passwordCheck)
-			
-			err := c.clientUseCase.Register(username, password, passwordCheck)
+		Long: "Регистрация в сервисе. Без терминала логин передается аргументом или флагом --login,\n" +
+			"а пароль - первой строкой stdin с флагом --password-stdin, повторный ввод пароля при этом не требуется",
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			username, _ := cmd.Flags().GetString("login")
+			passwordStdin, _ := cmd.Flags().GetBool("password-stdin")
+			if len(args) > 0 {
+				username = args[0]
+			}
+
+			in := newInput()
+			username, err := in.require(username, "Введите логин:", "логин задается аргументом или флагом --login")
+			if err != nil {
+				return fail("Ошибка регистрации:", err)
+			}
+			// Пароль, введенный на терминале, подтверждается повторным вводом, пароль из stdin - нет
+			password, err := in.secret(passwordStdin, registerPasswordField)
+			if err != nil {
+				return fail("Ошибка регистрации:", err)
+			}
+
+			err = c.clientUseCase.Register(username, password, password)
 			if err != nil {
-				fmt.Println("Ошибка регистрации:", err)
-				return
+				return fail("Ошибка регистрации:", err)
 			}
-			
+
 			fmt.Println("Успешная регистрация!")
+			return nil
 		},
 	}
+
+	cmd.Flags().StringP("login", "u", "", "Логин пользователя")
+	cmd.Flags().Bool("password-stdin", false, "Прочитать пароль из первой строки stdin")
+
+	return cmd
 }
@@ -2,9 +2,12 @@ package command
 
 import (
 	"bytes"
+	"encoding/json"
 	"errors"
 	"github.com/SmirnovND/gophkeeper/internal/domain"
+	"github.com/SmirnovND/gophkeeper/internal/itemtype"
 	"io"
+	"net/http"
 	"os"
 	"strings"
 	"testing"
@@ -31,14 +34,56 @@ func (m *MockClientUseCase) Register(username string, password string, passwordC
 }
 
 // Реализация остальных методов интерфейса ClientUseCase
-func (m *MockClientUseCase) Upload(filePath string, label string) (string, error) {
+func (m *MockClientUseCase) Upload(filePath string, label string, metadata string) (string, error) {
 	return "", nil
 }
 
-func (m *MockClientUseCase) Download(label string) error {
+func (m *MockClientUseCase) Download(label string, outputPath string) error {
 	return nil
 }
 
+func (m *MockClientUseCase) UploadDir(dirPath string, label string, metadata string) (string, error) {
+	return "", nil
+}
+
+func (m *MockClientUseCase) Extract(label string, destDir string) error {
+	return nil
+}
+
+func (m *MockClientUseCase) ListFiles() ([]domain.FileInfo, error) {
+	return nil, nil
+}
+
+func (m *MockClientUseCase) FileInfo(label string) (*domain.FileInfo, error) {
+	return nil, nil
+}
+
+func (m *MockClientUseCase) RenameFile(label string, newLabel string) error {
+	return nil
+}
+
+func (m *MockClientUseCase) DeleteFile(label string) error {
+	return nil
+}
+
+func (m *MockClientUseCase) Usage() (*domain.Usage, error) {
+	return nil, nil
+}
+
+func (m *MockClientUseCase) Settings() (*domain.UserSettings, error) {
+	return nil, nil
+}
+
+func (m *MockClientUseCase) SaveSettings(settings *domain.UserSettings) error {
+	return nil
+}
+
+func (m *MockClientUseCase) ListItems() ([]domain.ItemInfo, error) {
+	return nil, nil
+}
+
+func (m *MockClientUseCase) SetProgress(progress domain.ProgressFunc) {}
+
 func (m *MockClientUseCase) SaveText(label string, textData *domain.TextData, metadata string) error {
 	return nil
 }
@@ -51,6 +96,50 @@ func (m *MockClientUseCase) DeleteText(label string) error {
 	return nil
 }
 
+func (m *MockClientUseCase) SaveSSHKey(label string, sshKeyData *domain.SSHKeyData, metadata string) error {
+	return nil
+}
+
+func (m *MockClientUseCase) GetSSHKey(label string) (*domain.SSHKeyData, string, error) {
+	return nil, "", nil
+}
+
+func (m *MockClientUseCase) DeleteSSHKey(label string) error {
+	return nil
+}
+
+func (m *MockClientUseCase) SaveItem(itemType string, label string, data domain.ItemData, metadata string) error {
+	return nil
+}
+
+func (m *MockClientUseCase) GetItem(itemType string, label string) (domain.ItemData, string, error) {
+	return nil, "", nil
+}
+
+func (m *MockClientUseCase) DeleteItem(itemType string, label string) error {
+	return nil
+}
+
+func (m *MockClientUseCase) ItemType(itemType string) (*itemtype.Type, error) {
+	return itemtype.Lookup(itemType)
+}
+
+func (m *MockClientUseCase) SaveItemTemplate(name string, schema json.RawMessage) error {
+	return nil
+}
+
+func (m *MockClientUseCase) GetItemTemplate(name string) (*domain.ItemTemplate, error) {
+	return nil, &domain.Error{Message: "шаблон не найден", CodeValue: http.StatusNotFound}
+}
+
+func (m *MockClientUseCase) ListItemTemplates() ([]domain.ItemTemplate, error) {
+	return []domain.ItemTemplate{}, nil
+}
+
+func (m *MockClientUseCase) DeleteItemTemplate(name string) error {
+	return nil
+}
+
 func (m *MockClientUseCase) SaveCard(label string, cardData *domain.CardData, metadata string) error {
 	return nil
 }
@@ -122,7 +211,7 @@ func TestCommand_Login_Success(t *testing.T) {
 	loginCmd := cmd.Login()
 
 	// Выполняем команду
-	loginCmd.Run(loginCmd, []string{})
+	loginCmd.RunE(loginCmd, []string{})
 
 	// Закрываем pipe для записи и копируем вывод в буфер
 	w2.Close()
@@ -158,6 +247,9 @@ func TestCommand_Login_Error(t *testing.T) {
 	defer func() { os.Stdout = oldStdout }()
 	r2, w2, _ := os.Pipe()
 	os.Stdout = w2
+	oldStderr := os.Stderr
+	defer func() { os.Stderr = oldStderr }()
+	os.Stderr = w2
 
 	// Создаем мок для ClientUseCase
 	mockClientUseCase := &MockClientUseCase{
@@ -175,7 +267,7 @@ func TestCommand_Login_Error(t *testing.T) {
 	loginCmd := cmd.Login()
 
 	// Выполняем команду
-	loginCmd.Run(loginCmd, []string{})
+	loginCmd.RunE(loginCmd, []string{})
 
 	// Закрываем pipe для записи и копируем вывод в буфер
 	w2.Close()
@@ -238,7 +330,7 @@ func TestCommand_RegisterCmd_Success(t *testing.T) {
 	registerCmd := cmd.RegisterCmd()
 
 	// Выполняем команду
-	registerCmd.Run(registerCmd, []string{})
+	registerCmd.RunE(registerCmd, []string{})
 
 	// Закрываем pipe для записи и копируем вывод в буфер
 	w2.Close()
@@ -274,6 +366,9 @@ func TestCommand_RegisterCmd_Error(t *testing.T) {
 	defer func() { os.Stdout = oldStdout }()
 	r2, w2, _ := os.Pipe()
 	os.Stdout = w2
+	oldStderr := os.Stderr
+	defer func() { os.Stderr = oldStderr }()
+	os.Stderr = w2
 
 	// Создаем мок для ClientUseCase
 	mockClientUseCase := &MockClientUseCase{
@@ -291,7 +386,7 @@ func TestCommand_RegisterCmd_Error(t *testing.T) {
 	registerCmd := cmd.RegisterCmd()
 
 	// Выполняем команду
-	registerCmd.Run(registerCmd, []string{})
+	registerCmd.RunE(registerCmd, []string{})
 
 	// Закрываем pipe для записи и копируем вывод в буфер
 	w2.Close()
@@ -0,0 +1,412 @@
+package command
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"github.com/SmirnovND/gophkeeper/internal/backup"
+	"github.com/SmirnovND/gophkeeper/internal/domain"
+	"github.com/SmirnovND/gophkeeper/internal/importer"
+	"github.com/SmirnovND/gophkeeper/pkg"
+	"github.com/spf13/cobra"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Режимы восстановления резервной копии
+const (
+	restoreMerge   = "merge"
+	restoreReplace = "replace"
+)
+
+// minBackupPassphrase - минимальная длина парольной фразы резервной копии
+const minBackupPassphrase = 8
+
+// ExportCmd создает команду экспорта всего хранилища в зашифрованный файл резервной копии
+func (c *Command) ExportCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "export <file>",
+		Short: "Экспорт хранилища в зашифрованную резервную копию",
+		Long: "Сохраняет все записи хранилища с метаинформацией, датами создания и изменения и содержимым файлов\n" +
+			"в один файл, зашифрованный парольной фразой. Файл восстанавливается командой restore-backup\n" +
+			"в любую учетную запись на любом сервере. Формат файла описан в README",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			force, _ := cmd.Flags().GetBool("force")
+			fromStdin, _ := cmd.Flags().GetBool("passphrase-stdin")
+
+			path := args[0]
+			if _, err := os.Stat(path); err == nil && !force {
+				return fail("Ошибка при экспорте:", fmt.Errorf("%w: файл '%s' уже существует, для перезаписи укажите --force", errUsage, path))
+			}
+
+			passphrase, err := newInput().secret(fromStdin, exportPassphraseField)
+			if err != nil {
+				return fail("Ошибка при экспорте:", err)
+			}
+			if len(passphrase) < minBackupPassphrase {
+				return fail("Ошибка при экспорте:", fmt.Errorf("%w: парольная фраза должна быть не короче %d символов", errUsage, minBackupPassphrase))
+			}
+
+			items, err := c.clientUseCase.ListItems()
+			if err != nil {
+				return fail("Ошибка при получении списка записей:", err)
+			}
+
+			dir, err := os.MkdirTemp("", "gophkeeper-export-")
+			if err != nil {
+				return fail("Ошибка при экспорте:", err)
+			}
+			defer os.RemoveAll(dir)
+
+			manifest, blobs, err := c.collectBackup(items, dir)
+			if err != nil {
+				return fail("Ошибка при экспорте:", err)
+			}
+			if err := writeBackupFile(path, []byte(passphrase), manifest, blobs); err != nil {
+				return fail("Ошибка при записи резервной копии:", err)
+			}
+
+			fmt.Printf("Резервная копия сохранена в '%s'. Записей: %d, из них файлов: %d\n", path, len(manifest.Items), len(blobs))
+			return nil
+		},
+	}
+
+	cmd.Flags().Bool("passphrase-stdin", false, "Прочитать парольную фразу из первой строки stdin")
+	cmd.Flags().Bool("force", false, "Перезаписать существующий файл")
+
+	return cmd
+}
+
+// RestoreBackupCmd создает команду восстановления записей из резервной копии
+func (c *Command) RestoreBackupCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "restore-backup <file>",
+		Short: "Восстановление записей из резервной копии",
+		Long: "Восстанавливает записи из файла, созданного командой export. Копия целиком расшифровывается\n" +
+			"и проверяется до изменения хранилища: поврежденная или обрезанная копия ничего не записывает.\n" +
+			"Режим merge добавляет записи к существующим, совпадения меток разрешаются флагом --on-conflict:\n" +
+			"skip - пропустить, rename - сохранить под меткой \"label (2)\", overwrite - перезаписать.\n" +
+			"Режим replace удаляет все записи хранилища и восстанавливает копию, он требует подтверждения",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			mode, _ := cmd.Flags().GetString("mode")
+			conflict, _ := cmd.Flags().GetString("on-conflict")
+			fromStdin, _ := cmd.Flags().GetBool("passphrase-stdin")
+			yes, _ := cmd.Flags().GetBool("yes")
+			if mode != restoreMerge && mode != restoreReplace {
+				return fail("Ошибка при восстановлении:", fmt.Errorf("%w: неизвестный режим '%s', допустимы merge и replace", errUsage, mode))
+			}
+			if mode == restoreReplace && cmd.Flags().Changed("on-conflict") {
+				return fail("Ошибка при восстановлении:", fmt.Errorf("%w: --on-conflict применяется только в режиме merge", errUsage))
+			}
+
+			file, err := os.Open(args[0])
+			if err != nil {
+				return fail("Ошибка при чтении резервной копии:", err)
+			}
+			defer file.Close()
+
+			in := newInput()
+			passphrase, err := in.secret(fromStdin, restorePassphraseField)
+			if err != nil {
+				return fail("Ошибка при восстановлении:", err)
+			}
+
+			dir, err := os.MkdirTemp("", "gophkeeper-restore-")
+			if err != nil {
+				return fail("Ошибка при восстановлении:", err)
+			}
+			defer os.RemoveAll(dir)
+
+			manifest, blobs, err := backup.Read(file, []byte(passphrase), dir)
+			if err != nil {
+				return fail("Ошибка при чтении резервной копии:", err)
+			}
+			fmt.Fprintf(os.Stderr, "Резервная копия от %s проверена, записей: %d\n",
+				manifest.CreatedAt.Local().Format(time.DateTime), len(manifest.Items))
+
+			existing, err := c.clientUseCase.ListItems()
+			if err != nil {
+				return fail("Ошибка при получении списка записей:", err)
+			}
+
+			if mode == restoreReplace {
+				prompt := fmt.Sprintf("Удалить все записи хранилища (%d) и восстановить записи из копии (%d)?", len(existing), len(manifest.Items))
+				ok, err := in.confirm(yes, prompt, "подтвердите замену хранилища флагом --yes")
+				if err != nil {
+					return fail("Ошибка при восстановлении:", err)
+				}
+				if !ok {
+					fmt.Fprintln(os.Stderr, "Восстановление отменено")
+					return nil
+				}
+				for _, item := range existing {
+					if err := c.deleteItem(item.Type, item.Label); err != nil {
+						return fail("Ошибка при удалении записей:", fmt.Errorf("'%s': %w", item.Label, err))
+					}
+				}
+				existing = nil
+			}
+
+			if err := c.restoreTemplates(manifest.Templates, mode == restoreReplace); err != nil {
+				return fail("Ошибка при восстановлении шаблонов:", err)
+			}
+
+			entries := make([]importer.Entry, 0, len(manifest.Items))
+			for _, item := range manifest.Items {
+				entries = append(entries, importer.Entry{Type: item.Type, Label: item.Label})
+			}
+			steps, err := importer.Plan(entries, existing, conflict)
+			if err != nil {
+				return fail("Ошибка при восстановлении:", fmt.Errorf("%w: %v", errUsage, err))
+			}
+
+			stored := make(map[string]string, len(existing))
+			for _, item := range existing {
+				stored[item.Label] = item.Type
+			}
+
+			restored, skipped, failed := 0, 0, 0
+			for i, step := range steps {
+				if step.Action == importer.ActionSkip {
+					skipped++
+					fmt.Fprintf(os.Stderr, "Пропущена запись '%s': %s\n", step.Entry.Label, step.Reason)
+					continue
+				}
+				if err := c.restoreItem(step, stored[step.Label], &manifest.Items[i], blobs, dir); err != nil {
+					failed++
+					fmt.Fprintf(os.Stderr, "Ошибка при восстановлении '%s': %v\n", step.Label, err)
+					continue
+				}
+				restored++
+			}
+
+			fmt.Printf("Восстановлено записей: %d, пропущено: %d, с ошибкой: %d\n", restored, skipped, failed)
+			if failed > 0 {
+				return fail("Ошибка при восстановлении:", fmt.Errorf("не удалось восстановить %d записей", failed))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().String("mode", restoreMerge, "Режим восстановления: merge или replace")
+	cmd.Flags().String("on-conflict", importer.ConflictSkip, "Действие при совпадении метки в режиме merge: skip, rename или overwrite")
+	cmd.Flags().Bool("passphrase-stdin", false, "Прочитать парольную фразу из первой строки stdin")
+	cmd.Flags().Bool("yes", false, "Не запрашивать подтверждение замены хранилища")
+
+	return cmd
+}
+
+// collectBackup получает содержимое записей items и скачивает файлы в dir.
+// Возвращает манифест и пути к скачанным файлам по путям в архиве
+func (c *Command) collectBackup(items []domain.ItemInfo, dir string) (*backup.Manifest, map[string]string, error) {
+	manifest := &backup.Manifest{
+		Format:    backup.FormatName,
+		Version:   backup.Version,
+		CreatedAt: time.Now().UTC(),
+		Items:     make([]backup.Item, 0, len(items)),
+	}
+	blobs := make(map[string]string)
+
+	templates, err := c.clientUseCase.ListItemTemplates()
+	if err != nil {
+		return nil, nil, fmt.Errorf("шаблоны: %w", err)
+	}
+	manifest.Templates = templates
+
+	for _, info := range items {
+		item := backup.Item{
+			Type:      info.Type,
+			Label:     info.Label,
+			Metadata:  info.Metadata,
+			CreatedAt: info.CreatedAt,
+			UpdatedAt: info.UpdatedAt,
+		}
+
+		var err error
+		switch info.Type {
+		case domain.UserDataTypeCredential:
+			item.Credential, _, err = c.clientUseCase.GetCredential(info.Label)
+		case domain.UserDataTypeCard:
+			item.Card, _, err = c.clientUseCase.GetCard(info.Label)
+		case domain.UserDataTypeText:
+			item.Text, _, err = c.clientUseCase.GetText(info.Label)
+		case domain.UserDataTypeSSHKey:
+			item.SSHKey, _, err = c.clientUseCase.GetSSHKey(info.Label)
+		case domain.UserDataTypeFile:
+			path := filepath.Join(dir, fmt.Sprintf("%06d", len(blobs)))
+			item.File, err = c.exportFile(info.Label, path, backup.FilePath(len(blobs)))
+			if err == nil {
+				blobs[item.File.Path] = path
+			}
+		default:
+			if _, err = c.clientUseCase.ItemType(info.Type); err == nil {
+				item.Data, _, err = c.clientUseCase.GetItem(info.Type, info.Label)
+			}
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("'%s': %w", info.Label, err)
+		}
+		fmt.Fprintf(os.Stderr, "Экспортировано %d из %d\n", len(manifest.Items)+1, len(items))
+		manifest.Items = append(manifest.Items, item)
+	}
+	return manifest, blobs, nil
+}
+
+// exportFile скачивает файл с меткой label в path и возвращает его описание для манифеста
+func (c *Command) exportFile(label string, path string, archivePath string) (*backup.File, error) {
+	info, err := c.clientUseCase.FileInfo(label)
+	if err != nil {
+		return nil, err
+	}
+
+	restore := quietStdout()
+	err = c.clientUseCase.Download(label, path)
+	restore()
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer content.Close()
+	hash := sha256.New()
+	size, err := io.Copy(hash, content)
+	if err != nil {
+		return nil, err
+	}
+
+	return &backup.File{
+		Path:         archivePath,
+		Extension:    info.Extension,
+		Size:         size,
+		MimeType:     info.MimeType,
+		OriginalName: info.OriginalName,
+		SHA256:       hex.EncodeToString(hash.Sum(nil)),
+	}, nil
+}
+
+// writeBackupFile записывает копию атомарно, поэтому при ошибке существующий файл не повреждается
+func writeBackupFile(path string, passphrase []byte, manifest *backup.Manifest, blobs map[string]string) error {
+	return writeFileAtomic(path, 0o600, func(w io.Writer) error {
+		return backup.Write(w, passphrase, manifest, func(file *backup.File) (io.ReadCloser, error) {
+			return os.Open(blobs[file.Path])
+		})
+	})
+}
+
+// restoreTemplates сохраняет шаблоны копии до ее записей. Без overwrite шаблон с тем же именем не перезаписывается,
+// чтобы не изменить схему уже сохраненных записей
+func (c *Command) restoreTemplates(templates []domain.ItemTemplate, overwrite bool) error {
+	if len(templates) == 0 {
+		return nil
+	}
+	stored := make(map[string]bool)
+	if !overwrite {
+		existing, err := c.clientUseCase.ListItemTemplates()
+		if err != nil {
+			return err
+		}
+		for _, template := range existing {
+			stored[template.Name] = true
+		}
+	}
+
+	for _, template := range templates {
+		if stored[template.Name] {
+			fmt.Fprintf(os.Stderr, "Пропущен шаблон '%s': шаблон с таким именем уже есть\n", template.Name)
+			continue
+		}
+		if err := c.clientUseCase.SaveItemTemplate(template.Name, template.Schema); err != nil {
+			return fmt.Errorf("'%s': %w", template.Name, err)
+		}
+	}
+	return nil
+}
+
+// restoreItem сохраняет запись копии под меткой step.Label. Запись другого типа с той же меткой
+// storedType удаляется перед перезаписью
+func (c *Command) restoreItem(step importer.Step, storedType string, item *backup.Item, blobs map[string]string, dir string) error {
+	if step.Action == importer.ActionOverwrite && storedType != "" && storedType != item.Type {
+		if err := c.deleteItem(storedType, step.Label); err != nil {
+			return err
+		}
+	}
+
+	switch item.Type {
+	case domain.UserDataTypeCredential:
+		return c.clientUseCase.SaveCredential(step.Label, item.Credential, item.Metadata)
+	case domain.UserDataTypeCard:
+		return c.clientUseCase.SaveCard(step.Label, item.Card, item.Metadata)
+	case domain.UserDataTypeText:
+		return c.clientUseCase.SaveText(step.Label, item.Text, item.Metadata)
+	case domain.UserDataTypeSSHKey:
+		return c.clientUseCase.SaveSSHKey(step.Label, item.SSHKey, item.Metadata)
+	case domain.UserDataTypeFile:
+		return c.restoreFile(step.Label, item, blobs[item.File.Path], dir)
+	default:
+		return c.clientUseCase.SaveItem(item.Type, step.Label, item.Data, item.Metadata)
+	}
+}
+
+// restoreFile загружает файл копии. Файл получает исходное имя, чтобы сервер сохранил имя и расширение
+func (c *Command) restoreFile(label string, item *backup.Item, blob string, dir string) error {
+	name := filepath.Base(item.File.OriginalName)
+	if item.File.OriginalName == "" || pkg.GetExtensionByPath(name) != item.File.Extension {
+		name = "file"
+		if item.File.Extension != "" {
+			name += "." + item.File.Extension
+		}
+	}
+
+	uploadDir, err := os.MkdirTemp(dir, "upload-")
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(uploadDir, name)
+	if err := os.Rename(blob, path); err != nil {
+		return err
+	}
+
+	restore := quietStdout()
+	_, err = c.clientUseCase.Upload(path, label, item.Metadata)
+	restore()
+	return err
+}
+
+// deleteItem удаляет запись типа itemType
+func (c *Command) deleteItem(itemType string, label string) error {
+	switch itemType {
+	case domain.UserDataTypeCredential:
+		return c.clientUseCase.DeleteCredential(label)
+	case domain.UserDataTypeCard:
+		return c.clientUseCase.DeleteCard(label)
+	case domain.UserDataTypeText:
+		return c.clientUseCase.DeleteText(label)
+	case domain.UserDataTypeSSHKey:
+		return c.clientUseCase.DeleteSSHKey(label)
+	case domain.UserDataTypeFile:
+		return c.clientUseCase.DeleteFile(label)
+	default:
+		return c.clientUseCase.DeleteItem(itemType, label)
+	}
+}
+
+// quietStdout подавляет сообщения сценариев ClientUseCase в stdout и возвращает функцию восстановления вывода
+func quietStdout() func() {
+	stdout := os.Stdout
+	devNull, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+	if err != nil {
+		return func() {}
+	}
+	os.Stdout = devNull
+	return func() {
+		os.Stdout = stdout
+		devNull.Close()
+	}
+}
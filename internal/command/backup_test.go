@@ -0,0 +1,317 @@
+package command
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/SmirnovND/gophkeeper/internal/domain"
+	"github.com/SmirnovND/gophkeeper/internal/itemtype"
+	"github.com/SmirnovND/gophkeeper/internal/sshkey"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+)
+
+// fakeVault - хранилище записей для проверки экспорта и восстановления
+type fakeVault struct {
+	items       map[string]domain.ItemInfo
+	credentials map[string]*domain.CredentialData
+	texts       map[string]*domain.TextData
+	sshKeys     map[string]*domain.SSHKeyData
+	records     map[string]domain.ItemData
+	files       map[string][]byte
+	names       map[string]string
+	templates   map[string]json.RawMessage
+}
+
+func newFakeVault() *fakeVault {
+	return &fakeVault{
+		items:       make(map[string]domain.ItemInfo),
+		credentials: make(map[string]*domain.CredentialData),
+		texts:       make(map[string]*domain.TextData),
+		sshKeys:     make(map[string]*domain.SSHKeyData),
+		records:     make(map[string]domain.ItemData),
+		files:       make(map[string][]byte),
+		names:       make(map[string]string),
+		templates:   make(map[string]json.RawMessage),
+	}
+}
+
+func (v *fakeVault) labels() []string {
+	labels := make([]string, 0, len(v.items))
+	for label := range v.items {
+		labels = append(labels, label)
+	}
+	sort.Strings(labels)
+	return labels
+}
+
+// command возвращает команду, работающую с хранилищем
+func (v *fakeVault) command() *Command {
+	remove := func(label string) error {
+		delete(v.items, label)
+		return nil
+	}
+
+	itemTypeOf := func(itemType string) (*itemtype.Type, error) {
+		if t, err := itemtype.Lookup(itemType); err == nil {
+			return t, nil
+		}
+		schema, ok := v.templates[itemType]
+		if !ok {
+			return nil, fmt.Errorf("%w '%s'", itemtype.ErrUnknownType, itemType)
+		}
+		return itemtype.ParseSchema(itemType, schema)
+	}
+
+	return &Command{clientUseCase: &MockDataClientUseCase{
+		ListItemsFunc: func() ([]domain.ItemInfo, error) {
+			var items []domain.ItemInfo
+			for _, label := range v.labels() {
+				items = append(items, v.items[label])
+			}
+			return items, nil
+		},
+		SaveCredentialFunc: func(label string, credentialData *domain.CredentialData, metadata string) error {
+			v.items[label] = domain.ItemInfo{Type: domain.UserDataTypeCredential, Label: label, Metadata: metadata, URIs: credentialData.URIs}
+			v.credentials[label] = credentialData
+			return nil
+		},
+		GetCredentialFunc: func(label string) (*domain.CredentialData, string, error) {
+			return v.credentials[label], v.items[label].Metadata, nil
+		},
+		SaveTextFunc: func(label string, textData *domain.TextData, metadata string) error {
+			v.items[label] = domain.ItemInfo{Type: domain.UserDataTypeText, Label: label, Metadata: metadata}
+			v.texts[label] = textData
+			return nil
+		},
+		GetTextFunc: func(label string) (*domain.TextData, string, error) {
+			return v.texts[label], v.items[label].Metadata, nil
+		},
+		SaveSSHKeyFunc: func(label string, sshKeyData *domain.SSHKeyData, metadata string) error {
+			if err := sshkey.Complete(sshKeyData); err != nil {
+				return err
+			}
+			v.items[label] = domain.ItemInfo{Type: domain.UserDataTypeSSHKey, Label: label, Metadata: metadata}
+			v.sshKeys[label] = sshKeyData
+			return nil
+		},
+		GetSSHKeyFunc: func(label string) (*domain.SSHKeyData, string, error) {
+			return v.sshKeys[label], v.items[label].Metadata, nil
+		},
+		SaveItemFunc: func(itemType string, label string, data domain.ItemData, metadata string) error {
+			t, err := itemTypeOf(itemType)
+			if err != nil {
+				return err
+			}
+			if err := t.Validate(data); err != nil {
+				return err
+			}
+			v.items[label] = domain.ItemInfo{Type: itemType, Label: label, Metadata: metadata}
+			v.records[label] = data
+			return nil
+		},
+		GetItemFunc: func(itemType string, label string) (domain.ItemData, string, error) {
+			if v.items[label].Type != itemType {
+				return nil, "", &domain.Error{Message: "запись не найдена", CodeValue: http.StatusNotFound}
+			}
+			return v.records[label], v.items[label].Metadata, nil
+		},
+		UploadFunc: func(filePath string, label string, metadata string) (string, error) {
+			content, err := os.ReadFile(filePath)
+			if err != nil {
+				return "", err
+			}
+			v.items[label] = domain.ItemInfo{Type: domain.UserDataTypeFile, Label: label, Metadata: metadata}
+			v.files[label], v.names[label] = content, filepath.Base(filePath)
+			return "", nil
+		},
+		DownloadFunc: func(label string, outputPath string) error {
+			return os.WriteFile(outputPath, v.files[label], 0o600)
+		},
+		FileInfoFunc: func(label string) (*domain.FileInfo, error) {
+			return &domain.FileInfo{Label: label, Extension: filepath.Ext(v.names[label])[1:], OriginalName: v.names[label]}, nil
+		},
+		DeleteCredentialFunc: remove,
+		DeleteTextFunc:       remove,
+		DeleteSSHKeyFunc:     remove,
+		DeleteFileFunc:       remove,
+		DeleteItemFunc: func(itemType string, label string) error {
+			return remove(label)
+		},
+		ItemTypeFunc: itemTypeOf,
+		SaveItemTemplateFunc: func(name string, schema json.RawMessage) error {
+			if _, err := itemtype.ParseSchema(name, schema); err != nil {
+				return err
+			}
+			v.templates[name] = schema
+			return nil
+		},
+		GetItemTemplateFunc: func(name string) (*domain.ItemTemplate, error) {
+			schema, ok := v.templates[name]
+			if !ok {
+				return nil, &domain.Error{Message: "шаблон не найден", CodeValue: http.StatusNotFound}
+			}
+			return &domain.ItemTemplate{Name: name, Schema: schema}, nil
+		},
+		ListItemTemplatesFunc: func() ([]domain.ItemTemplate, error) {
+			names := make([]string, 0, len(v.templates))
+			for name := range v.templates {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			templates := make([]domain.ItemTemplate, 0, len(names))
+			for _, name := range names {
+				templates = append(templates, domain.ItemTemplate{Name: name, Schema: v.templates[name]})
+			}
+			return templates, nil
+		},
+		DeleteItemTemplateFunc: func(name string) error {
+			for _, item := range v.items {
+				if item.Type == name {
+					return &domain.Error{Message: "шаблон используется записями", CodeValue: http.StatusConflict}
+				}
+			}
+			delete(v.templates, name)
+			return nil
+		},
+	}}
+}
+
+// exportVault сохраняет резервную копию хранилища с записями github, note, home и key
+func exportVault(t *testing.T) string {
+	t.Helper()
+	vault := newFakeVault()
+	vault.command().clientUseCase.SaveCredential("github", &domain.CredentialData{Login: "alice", Password: "s3cret"}, "URL: https://github.com")
+	vault.command().clientUseCase.SaveText("note", &domain.TextData{Content: "заметка"}, "")
+	vault.command().clientUseCase.SaveItem(domain.UserDataTypeWiFi, "home", domain.ItemData{"ssid": "home", "security": "WPA2", "password": "hunter22"}, "")
+	vault.files["key"], vault.names["key"] = []byte("ssh key"), "id_ed25519.pem"
+	vault.items["key"] = domain.ItemInfo{Type: domain.UserDataTypeFile, Label: "key", Metadata: "сервер"}
+
+	path := filepath.Join(t.TempDir(), "vault.gkb")
+	withStdin(t, "passphrase\n", false)
+	if _, err := runWithOutput(t, vault.command().ExportCmd(), path, "--passphrase-stdin"); err != nil {
+		t.Fatalf("Неожиданная ошибка экспорта: %v", err)
+	}
+	return path
+}
+
+// TestCommand_ExportCmd_RestoreMerge тестирует восстановление копии в другое хранилище с пропуском совпадающих меток
+func TestCommand_ExportCmd_RestoreMerge(t *testing.T) {
+	path := exportVault(t)
+
+	vault := newFakeVault()
+	vault.command().clientUseCase.SaveText("github", &domain.TextData{Content: "другая запись"}, "")
+
+	withStdin(t, "passphrase\n", false)
+	if _, err := runWithOutput(t, vault.command().RestoreBackupCmd(), path, "--passphrase-stdin"); err != nil {
+		t.Fatalf("Неожиданная ошибка восстановления: %v", err)
+	}
+
+	if got := vault.labels(); len(got) != 4 || vault.texts["github"].Content != "другая запись" {
+		t.Errorf("Существующая запись должна быть пропущена, записи: %v", got)
+	}
+	if vault.texts["note"].Content != "заметка" || vault.records["home"]["password"] != "hunter22" || string(vault.files["key"]) != "ssh key" ||
+		vault.names["key"] != "id_ed25519.pem" || vault.items["key"].Metadata != "сервер" {
+		t.Errorf("Записи восстановлены неверно: %v", vault.items)
+	}
+}
+
+// TestCommand_RestoreBackupCmd_Replace тестирует замену хранилища и отказ без подтверждения
+func TestCommand_RestoreBackupCmd_Replace(t *testing.T) {
+	path := exportVault(t)
+
+	vault := newFakeVault()
+	vault.command().clientUseCase.SaveText("old", &domain.TextData{Content: "старая запись"}, "")
+	vault.command().clientUseCase.SaveText("github", &domain.TextData{Content: "другая запись"}, "")
+
+	withStdin(t, "passphrase\n", false)
+	_, err := runWithOutput(t, vault.command().RestoreBackupCmd(), path, "--passphrase-stdin", "--mode", "replace")
+	if !errors.Is(err, errMissingInput) || len(vault.items) != 2 {
+		t.Fatalf("Без --yes замена должна завершаться ошибкой, получено: %v, записи: %v", err, vault.labels())
+	}
+
+	withStdin(t, "passphrase\n", false)
+	if _, err := runWithOutput(t, vault.command().RestoreBackupCmd(), path, "--passphrase-stdin", "--mode", "replace", "--yes"); err != nil {
+		t.Fatalf("Неожиданная ошибка восстановления: %v", err)
+	}
+	if got := vault.labels(); len(got) != 4 || vault.items["github"].Type != domain.UserDataTypeCredential ||
+		vault.credentials["github"].Password != "s3cret" {
+		t.Errorf("Хранилище должно содержать только записи копии, записи: %v", got)
+	}
+}
+
+// TestCommand_RestoreBackupCmd_WrongPassphrase тестирует, что копия с неверной парольной фразой ничего не записывает
+func TestCommand_RestoreBackupCmd_WrongPassphrase(t *testing.T) {
+	path := exportVault(t)
+
+	vault := newFakeVault()
+	withStdin(t, "wrong passphrase\n", false)
+	if _, err := runWithOutput(t, vault.command().RestoreBackupCmd(), path, "--passphrase-stdin"); err == nil {
+		t.Fatal("Ожидалась ошибка неверной парольной фразы")
+	}
+	if len(vault.items) != 0 {
+		t.Errorf("Записи не должны сохраняться, записи: %v", vault.labels())
+	}
+}
+
+// TestCommand_ExportCmd_Validation тестирует отказ от короткой парольной фразы и перезаписи файла без --force
+func TestCommand_ExportCmd_Validation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "vault.gkb")
+
+	withStdin(t, "short\n", false)
+	if _, err := runWithOutput(t, newFakeVault().command().ExportCmd(), path, "--passphrase-stdin"); ExitCode(err) != ExitUsage {
+		t.Errorf("Ожидалась ошибка короткой парольной фразы, получено: %v", err)
+	}
+
+	if err := os.WriteFile(path, []byte("data"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	withStdin(t, "passphrase\n", false)
+	if _, err := runWithOutput(t, newFakeVault().command().ExportCmd(), path, "--passphrase-stdin"); ExitCode(err) != ExitUsage {
+		t.Errorf("Ожидалась ошибка существующего файла, получено: %v", err)
+	}
+}
+
+// TestCommand_ExportCmd_Templates тестирует перенос шаблонов и записей пользовательских типов и сохранение
+// существующего шаблона в режиме merge
+func TestCommand_ExportCmd_Templates(t *testing.T) {
+	schema := json.RawMessage(`{"type":"object","properties":{"host":{"type":"string"},"password":{"type":"string","writeOnly":true}}}`)
+	source := newFakeVault()
+	source.command().clientUseCase.SaveItemTemplate("db_profile", schema)
+	if err := source.command().clientUseCase.SaveItem("db_profile", "prod", domain.ItemData{"host": "db.local", "password": "s3cret"}, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(t.TempDir(), "vault.gkb")
+	withStdin(t, "passphrase\n", false)
+	if _, err := runWithOutput(t, source.command().ExportCmd(), path, "--passphrase-stdin"); err != nil {
+		t.Fatalf("Неожиданная ошибка экспорта: %v", err)
+	}
+
+	vault := newFakeVault()
+	withStdin(t, "passphrase\n", false)
+	if _, err := runWithOutput(t, vault.command().RestoreBackupCmd(), path, "--passphrase-stdin"); err != nil {
+		t.Fatalf("Неожиданная ошибка восстановления: %v", err)
+	}
+	var restored bytes.Buffer
+	if err := json.Compact(&restored, vault.templates["db_profile"]); err != nil || restored.String() != string(schema) ||
+		vault.records["prod"]["password"] != "s3cret" {
+		t.Errorf("Шаблон и запись восстановлены неверно: %v, %v", vault.templates, vault.records)
+	}
+
+	// В режиме merge существующий шаблон не заменяется
+	other := json.RawMessage(`{"type":"object","properties":{"host":{"type":"string"},"password":{"type":"string"}}}`)
+	vault = newFakeVault()
+	vault.templates["db_profile"] = other
+	withStdin(t, "passphrase\n", false)
+	if _, err := runWithOutput(t, vault.command().RestoreBackupCmd(), path, "--passphrase-stdin"); err != nil {
+		t.Fatalf("Неожиданная ошибка восстановления: %v", err)
+	}
+	if string(vault.templates["db_profile"]) != string(other) {
+		t.Errorf("Существующий шаблон не должен заменяться, получено %s", vault.templates["db_profile"])
+	}
+}
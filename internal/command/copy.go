@@ -0,0 +1,144 @@
+package command
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"github.com/SmirnovND/gophkeeper/internal/clipboard"
+	"github.com/spf13/cobra"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// defaultClearAfter - время, через которое буфер обмена очищается по умолчанию
+const defaultClearAfter = 45 * time.Second
+
+// detectClipboard выбирает способ записи в буфер обмена, в тестах заменяется
+var detectClipboard = clipboard.Detect
+
+// startClearer запускает отдельный процесс passcli, который очистит буфер обмена после выхода из команды copy.
+// Хеш значения передается через stdin, чтобы он не был виден в списке процессов
+var startClearer = func(backend clipboard.Backend, sum [sha256.Size]byte, clearAfter time.Duration) error {
+	executable, err := os.Executable()
+	if err != nil {
+		return err
+	}
+
+	process := exec.Command(executable, "copy", "--clear-watch",
+		"--clear-after", clearAfter.String(), "--clipboard", backend.Name())
+	process.SysProcAttr = detachAttr()
+	// OSC 52 выполняет терминал, поэтому процессу очистки нужен вывод в тот же терминал
+	if backend.Name() == clipboard.OSC52 {
+		process.Stderr = os.Stderr
+	}
+	stdin, err := process.StdinPipe()
+	if err != nil {
+		return err
+	}
+	if err := process.Start(); err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(stdin, hex.EncodeToString(sum[:]))
+	stdin.Close()
+	if err != nil {
+		return err
+	}
+	return process.Process.Release()
+}
+
+// CopyCmd создает команду копирования секрета записи в буфер обмена с последующей очисткой
+func (c *Command) CopyCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "copy [label]",
+		Short: "Копирование секрета записи в буфер обмена",
+		Long: "Копирует поле записи в буфер обмена: по умолчанию пароль учетных данных, номер карты, текст или закрытый ключ SSH,\n" +
+			"а для записей passcli item - основной секрет типа, например токен API или номер документа.\n" +
+			"Другое поле выбирается флагом --field, например --field login.\n" +
+			"Через --clear-after буфер очищается, если в нем все еще находится скопированное значение.\n" +
+			"Способ записи выбирается автоматически: wl-copy, xclip или xsel в графическом окружении Linux,\n" +
+			"pbcopy в macOS, PowerShell в Windows и WSL, escape-последовательность OSC 52 в SSH-сессии и\n" +
+			"других терминалах. Буфер, записанный через OSC 52, нельзя прочитать, поэтому он очищается без проверки",
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clearAfter, _ := cmd.Flags().GetDuration("clear-after")
+			if clearAfter < 0 {
+				return fail("Ошибка при копировании:", fmt.Errorf("%w: --clear-after не может быть отрицательным", errUsage))
+			}
+			name, _ := cmd.Flags().GetString("clipboard")
+			backend, err := detectClipboard(name)
+			if err != nil {
+				return fail("Ошибка при копировании:", err)
+			}
+
+			if watch, _ := cmd.Flags().GetBool("clear-watch"); watch {
+				return clearWatch(backend, clearAfter)
+			}
+
+			label, err := promptLabel(cmd, args, newInput(), "Введите уникальное название (label) записи для копирования:")
+			if err != nil {
+				return fail("Ошибка при копировании:", err)
+			}
+			field, _ := cmd.Flags().GetString("field")
+			field, value, err := c.newSecretResolver().resolve(secretRef{Label: label, Field: field})
+			if err != nil {
+				return fail("Ошибка при копировании:", err)
+			}
+
+			if err := backend.Write([]byte(value)); err != nil {
+				return fail("Ошибка при записи в буфер обмена:", err)
+			}
+			if clearAfter == 0 {
+				fmt.Printf("Поле %s записи '%s' скопировано в буфер обмена (%s)\n", field, label, backend.Name())
+				return nil
+			}
+			fmt.Printf("Поле %s записи '%s' скопировано в буфер обмена (%s) и будет очищено через %s\n",
+				field, label, backend.Name(), clearAfter)
+
+			sum := sha256.Sum256([]byte(value))
+			if wait, _ := cmd.Flags().GetBool("wait"); wait {
+				time.Sleep(clearAfter)
+				if _, err := clipboard.ClearIfUnchanged(backend, sum); err != nil {
+					return fail("Ошибка при очистке буфера обмена:", err)
+				}
+				return nil
+			}
+			if err := startClearer(backend, sum, clearAfter); err != nil {
+				return fail("Ошибка при запуске очистки буфера обмена:", err)
+			}
+			return nil
+		},
+	}
+
+	addLabelFlag(cmd)
+	cmd.Flags().Duration("clear-after", defaultClearAfter, "Очистить буфер обмена через указанное время, 0 - не очищать")
+	cmd.Flags().String("clipboard", clipboard.Auto,
+		"Способ записи в буфер обмена: "+strings.Join(clipboard.Names(), ", "))
+	cmd.Flags().Bool("wait", false, "Дождаться очистки буфера обмена, не запуская фоновый процесс")
+	// Режим фонового процесса очистки, запускаемого самой командой
+	cmd.Flags().Bool("clear-watch", false, "")
+	_ = cmd.Flags().MarkHidden("clear-watch")
+
+	return cmd
+}
+
+// clearWatch читает из stdin хеш скопированного значения, ждет clearAfter и очищает буфер обмена,
+// если значение в нем не изменилось
+func clearWatch(backend clipboard.Backend, clearAfter time.Duration) error {
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil {
+		return err
+	}
+	decoded, err := hex.DecodeString(strings.TrimSpace(line))
+	if err != nil || len(decoded) != sha256.Size {
+		return fmt.Errorf("%w: ожидался хеш SHA-256", errUsage)
+	}
+
+	var sum [sha256.Size]byte
+	copy(sum[:], decoded)
+	time.Sleep(clearAfter)
+	_, err = clipboard.ClearIfUnchanged(backend, sum)
+	return err
+}
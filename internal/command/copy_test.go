@@ -0,0 +1,171 @@
+package command
+
+import (
+	"crypto/sha256"
+	"errors"
+	"github.com/SmirnovND/gophkeeper/internal/clipboard"
+	"github.com/SmirnovND/gophkeeper/internal/domain"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeClipboard - буфер обмена в памяти
+type fakeClipboard struct {
+	value   []byte
+	cleared bool
+}
+
+func (f *fakeClipboard) Name() string { return "fake" }
+
+func (f *fakeClipboard) Write(value []byte) error {
+	f.value = append([]byte{}, value...)
+	return nil
+}
+
+func (f *fakeClipboard) Read() ([]byte, error) { return f.value, nil }
+
+func (f *fakeClipboard) Clear() error {
+	f.value, f.cleared = nil, true
+	return nil
+}
+
+// withClipboard подменяет буфер обмена и запуск фонового процесса очистки
+func withClipboard(t *testing.T, board *fakeClipboard) *[sha256.Size]byte {
+	oldDetect, oldStart := detectClipboard, startClearer
+	t.Cleanup(func() { detectClipboard, startClearer = oldDetect, oldStart })
+
+	var started [sha256.Size]byte
+	detectClipboard = func(name string) (clipboard.Backend, error) { return board, nil }
+	startClearer = func(backend clipboard.Backend, sum [sha256.Size]byte, clearAfter time.Duration) error {
+		started = sum
+		return nil
+	}
+	return &started
+}
+
+// newCopyCommand возвращает команду copy с записями разных типов
+func newCopyCommand() *Command {
+	return &Command{clientUseCase: &MockDataClientUseCase{
+		ListItemsFunc: func() ([]domain.ItemInfo, error) {
+			return []domain.ItemInfo{
+				{Type: domain.UserDataTypeCredential, Label: "bank"},
+				{Type: domain.UserDataTypeCard, Label: "visa"},
+				{Type: domain.UserDataTypeFile, Label: "photo"},
+			}, nil
+		},
+		GetCredentialFunc: func(label string) (*domain.CredentialData, string, error) {
+			return &domain.CredentialData{Login: "alice", Password: "s3cret"}, "", nil
+		},
+		GetCardFunc: func(label string) (*domain.CardData, string, error) {
+			return &domain.CardData{Number: "4111 1111 1111 1111", CVV: "123"}, "", nil
+		},
+	}}
+}
+
+// TestCommand_CopyCmd тестирует выбор поля по типу записи и запуск очистки буфера обмена
+func TestCommand_CopyCmd(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{name: "Credential password", args: []string{"bank"}, want: "s3cret"},
+		{name: "Credential login", args: []string{"bank", "--field", "login"}, want: "alice"},
+		{name: "Card number", args: []string{"visa"}, want: "4111 1111 1111 1111"},
+		{name: "Card CVV", args: []string{"visa", "--field", "CVV"}, want: "123"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			board := &fakeClipboard{}
+			started := withClipboard(t, board)
+
+			out, err := runWithOutput(t, newCopyCommand().CopyCmd(), tt.args...)
+			if err != nil {
+				t.Fatalf("Неожиданная ошибка: %v", err)
+			}
+			if string(board.value) != tt.want {
+				t.Errorf("Ожидалось значение '%s' в буфере обмена, получено '%s'", tt.want, board.value)
+			}
+			if *started != sha256.Sum256([]byte(tt.want)) {
+				t.Error("Очистка буфера обмена не запущена для скопированного значения")
+			}
+			if strings.Contains(out, tt.want) {
+				t.Errorf("Значение не должно выводиться в stdout: %s", out)
+			}
+		})
+	}
+}
+
+// TestCommand_CopyCmd_Wait тестирует очистку буфера обмена, только если в нем осталось скопированное значение
+func TestCommand_CopyCmd_Wait(t *testing.T) {
+	board := &fakeClipboard{}
+	withClipboard(t, board)
+
+	if _, err := runWithOutput(t, newCopyCommand().CopyCmd(), "bank", "--wait", "--clear-after", "1ms"); err != nil {
+		t.Fatalf("Неожиданная ошибка: %v", err)
+	}
+	if !board.cleared || board.value != nil {
+		t.Error("Буфер обмена не очищен")
+	}
+
+	// Пользователь скопировал другое значение до истечения времени
+	board = &fakeClipboard{}
+	withClipboard(t, board)
+	detectClipboard = func(name string) (clipboard.Backend, error) {
+		return &replacingClipboard{fakeClipboard: board}, nil
+	}
+	if _, err := runWithOutput(t, newCopyCommand().CopyCmd(), "bank", "--wait", "--clear-after", "1ms"); err != nil {
+		t.Fatalf("Неожиданная ошибка: %v", err)
+	}
+	if board.cleared || string(board.value) != "другое" {
+		t.Errorf("Буфер обмена с чужим значением не должен очищаться, получено '%s'", board.value)
+	}
+}
+
+// replacingClipboard заменяет записанное значение, имитируя копирование пользователем
+type replacingClipboard struct {
+	*fakeClipboard
+}
+
+func (r *replacingClipboard) Write(value []byte) error {
+	r.value = []byte("другое")
+	return nil
+}
+
+// TestCommand_CopyCmd_Errors тестирует коды завершения для отсутствующей записи и неподходящего типа
+func TestCommand_CopyCmd_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		code int
+	}{
+		{name: "Not found", args: []string{"missing"}, code: ExitNotFound},
+		{name: "File", args: []string{"photo"}, code: ExitUsage},
+		{name: "Unknown field", args: []string{"bank", "--field", "cvv"}, code: ExitUsage},
+		{name: "Negative timeout", args: []string{"bank", "--clear-after", "-1s"}, code: ExitUsage},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			board := &fakeClipboard{}
+			withClipboard(t, board)
+
+			_, err := runWithOutput(t, newCopyCommand().CopyCmd(), tt.args...)
+			if ExitCode(err) != tt.code {
+				t.Errorf("Ожидался код завершения %d, получен %d (%v)", tt.code, ExitCode(err), err)
+			}
+			if board.value != nil {
+				t.Errorf("Буфер обмена не должен меняться, получено '%s'", board.value)
+			}
+		})
+	}
+
+	withClipboard(t, &fakeClipboard{})
+	detectClipboard = func(name string) (clipboard.Backend, error) { return nil, clipboard.ErrNoBackend }
+	_, err := runWithOutput(t, newCopyCommand().CopyCmd(), "bank")
+	if !errors.Is(err, clipboard.ErrNoBackend) || ExitCode(err) != ExitError {
+		t.Errorf("Ожидалась ошибка отсутствия буфера обмена, получена %v", err)
+	}
+}
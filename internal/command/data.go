@@ -1,37 +1,98 @@
 package command
 
 import (
-	"bufio"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/SmirnovND/gophkeeper/internal/domain"
+	"github.com/SmirnovND/gophkeeper/internal/generator"
+	"github.com/SmirnovND/gophkeeper/internal/itemtype"
+	"github.com/SmirnovND/gophkeeper/internal/totp"
+	"github.com/SmirnovND/gophkeeper/internal/urimatch"
 	"github.com/spf13/cobra"
-	"os"
+	"regexp"
 	"strings"
 )
 
+// labelHint подсказывает, как передать метку записи без запроса
+const labelHint = "метка задается аргументом или флагом --label"
+
+// addLabelFlag добавляет флаг --label, альтернативный позиционному аргументу
+func addLabelFlag(cmd *cobra.Command) {
+	cmd.Flags().StringP("label", "l", "", "Уникальное название (label) записи")
+}
+
+// addSaveFlags добавляет флаги, общие для команд сохранения записей
+func addSaveFlags(cmd *cobra.Command, fromFileUsage string) {
+	addLabelFlag(cmd)
+	cmd.Flags().StringP("metadata", "m", "", "Метаинформация записи")
+	cmd.Flags().StringP("from-file", "f", "", fromFileUsage)
+}
+
+// promptLabel возвращает метку из аргумента или флага, а если она не задана - запрашивает ее на терминале
+func promptLabel(cmd *cobra.Command, args []string, in *input, prompt string) (string, error) {
+	label, err := labelArg(cmd, args)
+	if err != nil {
+		return "", err
+	}
+	return in.require(label, prompt, labelHint)
+}
+
+// promptMetadata возвращает метаинформацию из флага --metadata, а если флаг не задан - запрашивает ее на терминале
+func promptMetadata(cmd *cobra.Command, in *input) string {
+	metadata, _ := cmd.Flags().GetString("metadata")
+	return in.optional(metadata, cmd.Flags().Changed("metadata"), "Введите метаинформацию (необязательно):")
+}
+
+// readJSONSource заполняет v данными JSON из файла --from-file, если флаг задан
+func readJSONSource(cmd *cobra.Command, in *input, v interface{}) error {
+	fromFile, _ := cmd.Flags().GetString("from-file")
+	if fromFile == "" {
+		return nil
+	}
+
+	data, err := in.source(fromFile)
+	if err != nil {
+		return fmt.Errorf("ошибка при чтении %s: %w", fromFile, err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("ошибка при разборе JSON из %s: %w", fromFile, err)
+	}
+	return nil
+}
+
 // SaveTextCmd создает команду для сохранения текстовых данных
 func (c *Command) SaveTextCmd() *cobra.Command {
-	return &cobra.Command{
-		Use:   "save-text",
+	cmd := &cobra.Command{
+		Use:   "save-text [label]",
 		Short: "Сохранение текстовых данных",
-		Run: func(cmd *cobra.Command, args []string) {
-			var label, content, metadata string
-
-			fmt.Println("Введите уникальное название (label) для текстовых данных:")
-			fmt.Print("> ")
-			fmt.Fscanln(os.Stdin, &label)
+		Long: "Сохранение текстовых данных. Текст читается из файла --from-file ('-' для stdin), а на терминале вводится до Ctrl+D,\n" +
+			"например: passcli save-text notes --from-file notes.txt --metadata \"личное\"",
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			fromFile, _ := cmd.Flags().GetString("from-file")
+
+			in := newInput()
+			label, err := promptLabel(cmd, args, in, "Введите уникальное название (label) для текстовых данных:")
+			if err != nil {
+				return fail("Ошибка при сохранении текста:", err)
+			}
 
-			fmt.Println("Введите текст для сохранения:")
-			fmt.Print("> ")
-			// Используем bufio.Scanner для чтения многострочного текста
-			reader := bufio.NewReader(os.Stdin)
-			content, _ = reader.ReadString('\n')
-			content = strings.TrimSpace(content)
+			var content string
+			if fromFile != "" {
+				data, err := in.source(fromFile)
+				if err != nil {
+					return fail("Ошибка при сохранении текста:", err)
+				}
+				content = string(data)
+			} else {
+				content, err = in.text("Введите текст для сохранения, завершите ввод Ctrl+D:", "текст передается флагом --from-file, '-' для stdin")
+				if err != nil {
+					return fail("Ошибка при сохранении текста:", err)
+				}
+			}
 
-			fmt.Println("Введите метаинформацию (необязательно):")
-			fmt.Print("> ")
-			metadata, _ = reader.ReadString('\n')
-			metadata = strings.TrimSpace(metadata)
+			metadata := promptMetadata(cmd, in)
 
 			// Создаем структуру TextData
 			textData := &domain.TextData{
@@ -39,287 +100,542 @@ func (c *Command) SaveTextCmd() *cobra.Command {
 			}
 
 			// Вызываем метод сохранения текста
-			err := c.clientUseCase.SaveText(label, textData, metadata)
+			err = c.clientUseCase.SaveText(label, textData, metadata)
 			if err != nil {
-				fmt.Println("Ошибка при сохранении текста:", err)
-				return
+				return fail("Ошибка при сохранении текста:", err)
 			}
 
 			fmt.Println("Текст успешно сохранен!")
+			return nil
 		},
 	}
+
+	addSaveFlags(cmd, "Файл с текстом, '-' для чтения из stdin")
+
+	return cmd
 }
 
 // GetTextCmd создает команду для получения текстовых данных
 func (c *Command) GetTextCmd() *cobra.Command {
-	return &cobra.Command{
-		Use:   "get-text",
+	cmd := &cobra.Command{
+		Use:   "get-text [label]",
 		Short: "Получение текстовых данных",
-		Run: func(cmd *cobra.Command, args []string) {
-			var label string
-
-			fmt.Println("Введите уникальное название (label) текстовых данных для получения:")
-			fmt.Print("> ")
-			reader := bufio.NewReader(os.Stdin)
-			label, _ = reader.ReadString('\n')
-			label = strings.TrimSpace(label)
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out, err := newPrinter(cmd)
+			if err != nil {
+				return fail("Ошибка при получении текста:", err)
+			}
+			label, err := promptLabel(cmd, args, newInput(), "Введите уникальное название (label) текстовых данных для получения:")
+			if err != nil {
+				return fail("Ошибка при получении текста:", err)
+			}
 
 			// Вызываем метод получения текста
 			textData, metadata, err := c.clientUseCase.GetText(label)
 			if err != nil {
-				fmt.Println("Ошибка при получении текста:", err)
-				return
+				return fail("Ошибка при получении текста:", err)
 			}
 
-			fmt.Println("\nПолученный текст:")
-			fmt.Println("------------------")
-			fmt.Println(textData.Content)
-			fmt.Println("------------------")
-			
-			if metadata != "" {
-				fmt.Println("\nМетаинформация:")
+			item := &textItem{Type: "text", Label: label, Content: textData.Content, Metadata: metadata}
+			err = out.print(item, func() {
+				fmt.Println("\nПолученный текст:")
 				fmt.Println("------------------")
-				fmt.Println(metadata)
+				fmt.Println(textData.Content)
 				fmt.Println("------------------")
+
+				if metadata != "" {
+					fmt.Println("\nМетаинформация:")
+					fmt.Println("------------------")
+					fmt.Println(metadata)
+					fmt.Println("------------------")
+				}
+			})
+			if err != nil {
+				return fail("Ошибка при получении текста:", err)
 			}
+			return nil
 		},
 	}
+
+	addLabelFlag(cmd)
+
+	return cmd
 }
 
 // DeleteTextCmd создает команду для удаления текстовых данных
 func (c *Command) DeleteTextCmd() *cobra.Command {
-	return &cobra.Command{
-		Use:   "delete-text",
+	cmd := &cobra.Command{
+		Use:   "delete-text [label]",
 		Short: "Удаление текстовых данных",
-		Run: func(cmd *cobra.Command, args []string) {
-			var label string
-
-			fmt.Println("Введите уникальное название (label) текстовых данных для удаления:")
-			fmt.Print("> ")
-			fmt.Fscanln(os.Stdin, &label)
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			label, err := promptLabel(cmd, args, newInput(), "Введите уникальное название (label) текстовых данных для удаления:")
+			if err != nil {
+				return fail("Ошибка при удалении текста:", err)
+			}
 
 			// Вызываем метод удаления текста
-			err := c.clientUseCase.DeleteText(label)
+			err = c.clientUseCase.DeleteText(label)
 			if err != nil {
-				fmt.Println("Ошибка при удалении текста:", err)
-				return
+				return fail("Ошибка при удалении текста:", err)
 			}
 
 			fmt.Println("Текст успешно удален!")
+			return nil
 		},
 	}
+
+	addLabelFlag(cmd)
+
+	return cmd
 }
 
 // SaveCardCmd создает команду для сохранения данных кредитной карты
 func (c *Command) SaveCardCmd() *cobra.Command {
-	return &cobra.Command{
-		Use:   "save-card",
+	cmd := &cobra.Command{
+		Use:   "save-card [label]",
 		Short: "Сохранение данных кредитной карты",
-		Run: func(cmd *cobra.Command, args []string) {
-			var label, number, holder, expiryDate, cvv, metadata string
-
-			fmt.Println("Введите уникальное название (label) для данных карты:")
-			fmt.Print("> ")
-			fmt.Fscanln(os.Stdin, &label)
-
-			fmt.Println("Введите номер карты:")
-			fmt.Print("> ")
-			fmt.Fscanln(os.Stdin, &number)
-
-			fmt.Println("Введите имя держателя карты:")
-			fmt.Print("> ")
-			fmt.Fscanln(os.Stdin, &holder)
-
-			fmt.Println("Введите срок действия карты (MM/YY):")
-			fmt.Print("> ")
-			fmt.Fscanln(os.Stdin, &expiryDate)
-
-			fmt.Println("Введите CVV код:")
-			fmt.Print("> ")
-			fmt.Fscanln(os.Stdin, &cvv)
+		Long: "Сохранение данных кредитной карты. Данные передаются флагами или JSON-файлом --from-file ('-' для stdin)\n" +
+			"с полями number, holder, expiry_date и cvv. CVV не передается флагом, чтобы не попасть в список процессов",
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			in := newInput()
+			label, err := promptLabel(cmd, args, in, "Введите уникальное название (label) для данных карты:")
+			if err != nil {
+				return fail("Ошибка при сохранении данных карты:", err)
+			}
 
-			fmt.Println("Введите метаинформацию (необязательно):")
-			fmt.Print("> ")
-			fmt.Fscanln(os.Stdin, &metadata)
+			// Создаем структуру CardData, флаги дополняют данные из файла
+			cardData := &domain.CardData{}
+			if err := readJSONSource(cmd, in, cardData); err != nil {
+				return fail("Ошибка при сохранении данных карты:", err)
+			}
+			if number, _ := cmd.Flags().GetString("number"); number != "" {
+				cardData.Number = number
+			}
+			if holder, _ := cmd.Flags().GetString("holder"); holder != "" {
+				cardData.Holder = holder
+			}
+			if expiry, _ := cmd.Flags().GetString("expiry"); expiry != "" {
+				cardData.ExpiryDate = expiry
+			}
 
-			// Создаем структуру CardData
-			cardData := &domain.CardData{
-				Number:     number,
-				Holder:     holder,
-				ExpiryDate: expiryDate,
-				CVV:        cvv,
+			cardData.Number, err = in.require(cardData.Number, "Введите номер карты:", "номер карты задается флагом --number или полем number в --from-file")
+			if err != nil {
+				return fail("Ошибка при сохранении данных карты:", err)
+			}
+			cardData.Holder = in.optional(cardData.Holder, false, "Введите имя держателя карты:")
+			cardData.ExpiryDate = in.optional(cardData.ExpiryDate, false, "Введите срок действия карты (MM/YY):")
+			cardData.CVV, err = in.optionalSecret(cardData.CVV, cvvField)
+			if err != nil {
+				return fail("Ошибка при сохранении данных карты:", err)
 			}
+			metadata := promptMetadata(cmd, in)
 
 			// Вызываем метод сохранения данных карты
-			err := c.clientUseCase.SaveCard(label, cardData, metadata)
+			err = c.clientUseCase.SaveCard(label, cardData, metadata)
 			if err != nil {
-				fmt.Println("Ошибка при сохранении данных карты:", err)
-				return
+				return fail("Ошибка при сохранении данных карты:", err)
 			}
 
 			fmt.Println("Данные карты успешно сохранены!")
+			return nil
 		},
 	}
+
+	addSaveFlags(cmd, "JSON-файл с данными карты, '-' для чтения из stdin")
+	cmd.Flags().String("number", "", "Номер карты")
+	cmd.Flags().String("holder", "", "Имя держателя карты")
+	cmd.Flags().String("expiry", "", "Срок действия карты (MM/YY)")
+
+	return cmd
 }
 
 // GetCardCmd создает команду для получения данных кредитной карты
 func (c *Command) GetCardCmd() *cobra.Command {
-	return &cobra.Command{
-		Use:   "get-card",
+	cmd := &cobra.Command{
+		Use:   "get-card [label]",
 		Short: "Получение данных кредитной карты",
-		Run: func(cmd *cobra.Command, args []string) {
-			var label string
-
-			fmt.Println("Введите уникальное название (label) данных карты для получения:")
-			fmt.Print("> ")
-			fmt.Fscanln(os.Stdin, &label)
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out, err := newPrinter(cmd)
+			if err != nil {
+				return fail("Ошибка при получении данных карты:", err)
+			}
+			label, err := promptLabel(cmd, args, newInput(), "Введите уникальное название (label) данных карты для получения:")
+			if err != nil {
+				return fail("Ошибка при получении данных карты:", err)
+			}
 
 			// Вызываем метод получения данных карты
 			cardData, metadata, err := c.clientUseCase.GetCard(label)
 			if err != nil {
-				fmt.Println("Ошибка при получении данных карты:", err)
-				return
-			}
-
-			fmt.Println("\nДанные кредитной карты:")
-			fmt.Println("------------------------")
-			fmt.Println("Номер карты:", cardData.Number)
-			fmt.Println("Держатель карты:", cardData.Holder)
-			fmt.Println("Срок действия:", cardData.ExpiryDate)
-			fmt.Println("CVV код:", cardData.CVV)
-			fmt.Println("------------------------")
-			
-			if metadata != "" {
-				fmt.Println("\nМетаинформация:")
-				fmt.Println("------------------")
-				fmt.Println(metadata)
-				fmt.Println("------------------")
+				return fail("Ошибка при получении данных карты:", err)
 			}
+
+			item := &cardItem{
+				Type:       "card",
+				Label:      label,
+				Number:     cardData.Number,
+				Holder:     cardData.Holder,
+				ExpiryDate: cardData.ExpiryDate,
+				CVV:        cardData.CVV,
+				Metadata:   metadata,
+			}
+			err = out.print(item, func() {
+				fmt.Println("\nДанные кредитной карты:")
+				fmt.Println("------------------------")
+				fmt.Println("Номер карты:", cardData.Number)
+				fmt.Println("Держатель карты:", cardData.Holder)
+				fmt.Println("Срок действия:", cardData.ExpiryDate)
+				fmt.Println("CVV код:", cardData.CVV)
+				fmt.Println("------------------------")
+
+				if metadata != "" {
+					fmt.Println("\nМетаинформация:")
+					fmt.Println("------------------")
+					fmt.Println(metadata)
+					fmt.Println("------------------")
+				}
+			})
+			if err != nil {
+				return fail("Ошибка при получении данных карты:", err)
+			}
+			return nil
 		},
 	}
+
+	addLabelFlag(cmd)
+
+	return cmd
 }
 
 // DeleteCardCmd создает команду для удаления данных кредитной карты
 func (c *Command) DeleteCardCmd() *cobra.Command {
-	return &cobra.Command{
-		Use:   "delete-card",
+	cmd := &cobra.Command{
+		Use:   "delete-card [label]",
 		Short: "Удаление данных кредитной карты",
-		Run: func(cmd *cobra.Command, args []string) {
-			var label string
-
-			fmt.Println("Введите уникальное название (label) данных карты для удаления:")
-			fmt.Print("> ")
-			fmt.Fscanln(os.Stdin, &label)
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			label, err := promptLabel(cmd, args, newInput(), "Введите уникальное название (label) данных карты для удаления:")
+			if err != nil {
+				return fail("Ошибка при удалении данных карты:", err)
+			}
 
 			// Вызываем метод удаления данных карты
-			err := c.clientUseCase.DeleteCard(label)
+			err = c.clientUseCase.DeleteCard(label)
 			if err != nil {
-				fmt.Println("Ошибка при удалении данных карты:", err)
-				return
+				return fail("Ошибка при удалении данных карты:", err)
 			}
 
 			fmt.Println("Данные карты успешно удалены!")
+			return nil
 		},
 	}
+
+	addLabelFlag(cmd)
+
+	return cmd
 }
 
 // SaveCredentialCmd создает команду для сохранения учетных данных
 func (c *Command) SaveCredentialCmd() *cobra.Command {
-	return &cobra.Command{
-		Use:   "save-credential",
+	cmd := &cobra.Command{
+		Use:   "save-credential [label]",
 		Short: "Сохранение учетных данных (логин/пароль)",
-		Run: func(cmd *cobra.Command, args []string) {
-			var label, login, password, metadata string
+		Long: "Сохранение учетных данных. Пароль передается первой строкой stdin с флагом --password-stdin\n" +
+			"или JSON-файлом --from-file с полями login, password и totp, например:\n" +
+			"echo \"$PASS\" | passcli save-credential github --login alice --password-stdin\n" +
+			"С флагом --generate пароль генерируется по тем же флагам политики, что и в passcli generate,\n" +
+			"и выводится только с флагом --show, например:\n" +
+			"passcli save-credential github --login alice --generate --length 32\n" +
+			"Ключ второго фактора задается флагом --totp в формате otpauth://totp/..., текущий код выводит passcli otp.\n" +
+			"Адреса сайтов задаются повторяемым флагом --uri с необязательным правилом сравнения перед '=',\n" +
+			"дополнительные поля - флагами --custom имя=значение и --custom-bool имя=true|false, например:\n" +
+			"passcli save-credential gitlab --login alice --uri gitlab.com --uri host=https://git.corp:8443 --custom workspace=core\n" +
+			"Скрытые дополнительные поля (\"type\": \"hidden\") задаются только в --from-file",
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			login, _ := cmd.Flags().GetString("login")
+			totpURI, _ := cmd.Flags().GetString("totp")
+			passwordStdin, _ := cmd.Flags().GetBool("password-stdin")
+			fromFile, _ := cmd.Flags().GetString("from-file")
+			generate, _ := cmd.Flags().GetBool("generate")
+			uriFlags, _ := cmd.Flags().GetStringArray("uri")
+			customFlags, _ := cmd.Flags().GetStringArray("custom")
+			customBoolFlags, _ := cmd.Flags().GetStringArray("custom-bool")
+
+			if passwordStdin && fromFile == "-" {
+				return fail("Ошибка при сохранении учетных данных:", fmt.Errorf("%w: флаги --password-stdin и --from-file - нельзя использовать вместе", errUsage))
+			}
+			if generate && passwordStdin {
+				return fail("Ошибка при сохранении учетных данных:", fmt.Errorf("%w: флаги --generate и --password-stdin нельзя использовать вместе", errUsage))
+			}
 
-			fmt.Println("Введите уникальное название (label) для учетных данных:")
-			fmt.Print("> ")
-			fmt.Fscanln(os.Stdin, &label)
+			// Адреса и дополнительные поля разбираются до запросов, чтобы опечатка не заставляла вводить данные повторно
+			uris, err := parseURIFlags(uriFlags)
+			if err != nil {
+				return fail("Ошибка при сохранении учетных данных:", err)
+			}
+			customs, err := parseCustomFlags(customFlags, customBoolFlags)
+			if err != nil {
+				return fail("Ошибка при сохранении учетных данных:", err)
+			}
 
-			fmt.Println("Введите логин:")
-			fmt.Print("> ")
-			fmt.Fscanln(os.Stdin, &login)
+			// Пароль генерируется до запросов, чтобы неверная политика не заставляла вводить данные повторно
+			var generated generator.Result
+			if generate {
+				var err error
+				if generated, err = generateFromFlags(cmd); err != nil {
+					return fail("Ошибка при сохранении учетных данных:", err)
+				}
+			}
 
-			fmt.Println("Введите пароль:")
-			fmt.Print("> ")
-			fmt.Fscanln(os.Stdin, &password)
+			in := newInput()
+			label, err := promptLabel(cmd, args, in, "Введите уникальное название (label) для учетных данных:")
+			if err != nil {
+				return fail("Ошибка при сохранении учетных данных:", err)
+			}
 
-			fmt.Println("Введите метаинформацию (необязательно):")
-			fmt.Print("> ")
-			fmt.Fscanln(os.Stdin, &metadata)
+			// Создаем структуру CredentialData, флаги дополняют данные из файла
+			credentialData := &domain.CredentialData{}
+			if err := readJSONSource(cmd, in, credentialData); err != nil {
+				return fail("Ошибка при сохранении учетных данных:", err)
+			}
+			if login != "" {
+				credentialData.Login = login
+			}
+			if totpURI != "" {
+				credentialData.TOTP = totpURI
+			}
+			if len(uris) > 0 {
+				credentialData.URIs = uris
+			}
+			credentialData.Fields = mergeCustomFields(credentialData.Fields, customs)
+			// Ключ TOTP проверяется до запроса пароля, сервер повторяет проверку при сохранении
+			if credentialData.TOTP != "" {
+				if _, err := totp.Parse(credentialData.TOTP); err != nil {
+					return fail("Ошибка при сохранении учетных данных:", fmt.Errorf("%w: %v", errUsage, err))
+				}
+			}
 
-			// Создаем структуру CredentialData
-			credentialData := &domain.CredentialData{
-				Login:    login,
-				Password: password,
+			credentialData.Login = in.optional(credentialData.Login, false, "Введите логин:")
+			if generate {
+				credentialData.Password = generated.Value
+			} else if passwordStdin || credentialData.Password == "" {
+				credentialData.Password, err = in.secret(passwordStdin, credentialPasswordField)
+				if err != nil {
+					return fail("Ошибка при сохранении учетных данных:", err)
+				}
 			}
+			metadata := promptMetadata(cmd, in)
 
-			// Вызываем метод сохранения учетных данных
-			err := c.clientUseCase.SaveCredential(label, credentialData, metadata)
+			// Вызываем метод сохранения учетных данных, адреса и дополнительные поля проверяются до отправки
+			err = c.clientUseCase.SaveCredential(label, credentialData, metadata)
+			if errors.Is(err, itemtype.ErrInvalidItem) {
+				err = fmt.Errorf("%w: %v", errUsage, err)
+			}
 			if err != nil {
-				fmt.Println("Ошибка при сохранении учетных данных:", err)
-				return
+				return fail("Ошибка при сохранении учетных данных:", err)
 			}
 
 			fmt.Println("Учетные данные успешно сохранены!")
+			if generate {
+				fmt.Println("Пароль сгенерирован,", describeEntropy(generated))
+				if show, _ := cmd.Flags().GetBool("show"); show {
+					fmt.Println("Пароль:", generated.Value)
+				}
+			}
+			return nil
 		},
 	}
+
+	addSaveFlags(cmd, "JSON-файл с учетными данными, '-' для чтения из stdin")
+	cmd.Flags().String("login", "", "Логин")
+	cmd.Flags().String("totp", "", "Ключ TOTP в формате URI otpauth://totp/...")
+	cmd.Flags().StringArray("uri", nil, "Адрес сайта, перед адресом можно указать правило сравнения: "+
+		strings.Join(urimatch.Rules, ", ")+", например host=https://git.corp:8443; флаг повторяется")
+	cmd.Flags().StringArray("custom", nil, "Текстовое дополнительное поле в виде имя=значение, флаг повторяется")
+	cmd.Flags().StringArray("custom-bool", nil, "Дополнительный флаг в виде имя=true|false, флаг повторяется")
+	cmd.Flags().Bool("password-stdin", false, "Прочитать пароль из первой строки stdin")
+	cmd.Flags().Bool("generate", false, "Сгенерировать пароль вместо ввода")
+	cmd.Flags().Bool("show", false, "Вывести сгенерированный пароль")
+	addGenerateFlags(cmd)
+
+	return cmd
 }
 
 // GetCredentialCmd создает команду для получения учетных данных
 func (c *Command) GetCredentialCmd() *cobra.Command {
-	return &cobra.Command{
-		Use:   "get-credential",
+	cmd := &cobra.Command{
+		Use:   "get-credential [label]",
 		Short: "Получение учетных данных (логин/пароль)",
-		Run: func(cmd *cobra.Command, args []string) {
-			var label string
-
-			fmt.Println("Введите уникальное название (label) учетных данных для получения:")
-			fmt.Print("> ")
-			fmt.Fscanln(os.Stdin, &label)
+		Long: "Получение учетных данных. Сервер хранит прежние пароли записи, их выводит флаг --history:\n" +
+			"passcli get-credential github --history",
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out, err := newPrinter(cmd)
+			if err != nil {
+				return fail("Ошибка при получении учетных данных:", err)
+			}
+			label, err := promptLabel(cmd, args, newInput(), "Введите уникальное название (label) учетных данных для получения:")
+			if err != nil {
+				return fail("Ошибка при получении учетных данных:", err)
+			}
 
 			// Вызываем метод получения учетных данных
 			credentialData, metadata, err := c.clientUseCase.GetCredential(label)
 			if err != nil {
-				fmt.Println("Ошибка при получении учетных данных:", err)
-				return
-			}
-
-			fmt.Println("\nУчетные данные:")
-			fmt.Println("---------------")
-			fmt.Println("Логин:", credentialData.Login)
-			fmt.Println("Пароль:", credentialData.Password)
-			fmt.Println("---------------")
-			
-			if metadata != "" {
-				fmt.Println("\nМетаинформация:")
-				fmt.Println("------------------")
-				fmt.Println(metadata)
-				fmt.Println("------------------")
+				return fail("Ошибка при получении учетных данных:", err)
+			}
+
+			item := newCredentialItem(label, credentialData, metadata)
+			showHistory, _ := cmd.Flags().GetBool("history")
+			if showHistory {
+				item.History = credentialData.History
+			}
+			err = out.print(item, func() {
+				fmt.Println("\nУчетные данные:")
+				fmt.Println("---------------")
+				fmt.Println("Логин:", credentialData.Login)
+				fmt.Println("Пароль:", credentialData.Password)
+				if key, err := totp.Parse(credentialData.TOTP); err == nil {
+					moment := otpClock()
+					fmt.Printf("Код TOTP: %s (сменится через %d с)\n", key.Code(moment), remainingSeconds(key, moment))
+				}
+				for _, uri := range credentialData.URIs {
+					fmt.Println("Адрес:", describeURI(uri))
+				}
+				for _, field := range credentialData.Fields {
+					fmt.Printf("%s: %s\n", field.Name, field.Value)
+				}
+				if !showHistory && len(credentialData.History) > 0 {
+					fmt.Printf("Прежних паролей: %d (показать: --history)\n", len(credentialData.History))
+				}
+				fmt.Println("---------------")
+
+				if showHistory {
+					fmt.Println("\nИстория паролей:")
+					fmt.Println("------------------")
+					if len(credentialData.History) == 0 {
+						fmt.Println("Пароль не менялся")
+					}
+					for _, change := range credentialData.History {
+						fmt.Printf("%s\t%s\n", change.ChangedAt.Local().Format("2006-01-02 15:04"), change.Password)
+					}
+					fmt.Println("------------------")
+				}
+
+				if metadata != "" {
+					fmt.Println("\nМетаинформация:")
+					fmt.Println("------------------")
+					fmt.Println(metadata)
+					fmt.Println("------------------")
+				}
+			})
+			if err != nil {
+				return fail("Ошибка при получении учетных данных:", err)
 			}
+			return nil
 		},
 	}
+
+	cmd.Flags().Bool("history", false, "Вывести прежние пароли с датами смены")
+	addLabelFlag(cmd)
+
+	return cmd
 }
 
 // DeleteCredentialCmd создает команду для удаления учетных данных
 func (c *Command) DeleteCredentialCmd() *cobra.Command {
-	return &cobra.Command{
-		Use:   "delete-credential",
+	cmd := &cobra.Command{
+		Use:   "delete-credential [label]",
 		Short: "Удаление учетных данных (логин/пароль)",
-		Run: func(cmd *cobra.Command, args []string) {
-			var label string
-
-			fmt.Println("Введите уникальное название (label) учетных данных для удаления:")
-			fmt.Print("> ")
-			fmt.Fscanln(os.Stdin, &label)
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			label, err := promptLabel(cmd, args, newInput(), "Введите уникальное название (label) учетных данных для удаления:")
+			if err != nil {
+				return fail("Ошибка при удалении учетных данных:", err)
+			}
 
 			// Вызываем метод удаления учетных данных
-			err := c.clientUseCase.DeleteCredential(label)
+			err = c.clientUseCase.DeleteCredential(label)
 			if err != nil {
-				fmt.Println("Ошибка при удалении учетных данных:", err)
-				return
+				return fail("Ошибка при удалении учетных данных:", err)
 			}
 
 			fmt.Println("Учетные данные успешно удалены!")
+			return nil
 		},
 	}
+
+	addLabelFlag(cmd)
+
+	return cmd
+}
+
+// uriRulePattern - префикс значения флага --uri, который считается правилом сравнения
+var uriRulePattern = regexp.MustCompile(`^[a-z_]+$`)
+
+// parseURIFlags разбирает значения флагов --uri. Префикс до '=' считается правилом сравнения, только если
+// он состоит из латинских букв и '_', поэтому адреса с '=' в запросе передаются без изменений
+func parseURIFlags(flags []string) ([]domain.CredentialURI, error) {
+	var uris []domain.CredentialURI
+	for _, flag := range flags {
+		uri := domain.CredentialURI{URI: flag}
+		if rule, value, ok := strings.Cut(flag, "="); ok && uriRulePattern.MatchString(rule) {
+			uri = domain.CredentialURI{URI: value, Match: rule}
+		}
+		if err := urimatch.Validate(uri); err != nil {
+			return nil, fmt.Errorf("%w: %v", errUsage, err)
+		}
+		uris = append(uris, uri)
+	}
+	return uris, nil
+}
+
+// parseCustomFlags разбирает значения флагов --custom и --custom-bool в дополнительные поля
+func parseCustomFlags(texts []string, booleans []string) ([]domain.CustomField, error) {
+	var fields []domain.CustomField
+	for _, text := range texts {
+		name, value, ok := strings.Cut(text, "=")
+		if !ok || name == "" {
+			return nil, fmt.Errorf("%w: флаг --custom задается в виде имя=значение, получено '%s'", errUsage, text)
+		}
+		fields = append(fields, domain.CustomField{Name: name, Type: domain.CustomFieldText, Value: value})
+	}
+	for _, boolean := range booleans {
+		name, value, ok := strings.Cut(boolean, "=")
+		if !ok || name == "" || (value != "true" && value != "false") {
+			return nil, fmt.Errorf("%w: флаг --custom-bool задается в виде имя=true или имя=false, получено '%s'", errUsage, boolean)
+		}
+		fields = append(fields, domain.CustomField{Name: name, Type: domain.CustomFieldBoolean, Value: value})
+	}
+	return fields, nil
+}
+
+// mergeCustomFields заменяет одноименные дополнительные поля fields полями из флагов, остальные добавляет в конец
+func mergeCustomFields(fields []domain.CustomField, flags []domain.CustomField) []domain.CustomField {
+	for _, flag := range flags {
+		replaced := false
+		for i := range fields {
+			if fields[i].Name == flag.Name {
+				fields[i], replaced = flag, true
+				break
+			}
+		}
+		if !replaced {
+			fields = append(fields, flag)
+		}
+	}
+	return fields
+}
+
+// describeURI возвращает адрес учетных данных вместе с правилом сравнения, если оно отличается от правила по умолчанию
+func describeURI(uri domain.CredentialURI) string {
+	if uri.Match == "" || uri.Match == urimatch.RuleDomain {
+		return uri.URI
+	}
+	return fmt.Sprintf("%s (%s)", uri.URI, uri.Match)
 }
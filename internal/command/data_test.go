@@ -2,9 +2,12 @@ package command
 
 import (
 	"bytes"
+	"encoding/json"
 	"errors"
 	"github.com/SmirnovND/gophkeeper/internal/domain"
+	"github.com/SmirnovND/gophkeeper/internal/itemtype"
 	"io"
+	"net/http"
 	"os"
 	"strings"
 	"testing"
@@ -12,15 +15,31 @@ import (
 
 // MockDataClientUseCase - расширенный мок для интерфейса ClientUseCase с методами для работы с данными
 type MockDataClientUseCase struct {
-	SaveTextFunc         func(label string, textData *domain.TextData, metadata string) error
-	GetTextFunc          func(label string) (*domain.TextData, string, error)
-	DeleteTextFunc       func(label string) error
-	SaveCardFunc         func(label string, cardData *domain.CardData, metadata string) error
-	GetCardFunc          func(label string) (*domain.CardData, string, error)
-	DeleteCardFunc       func(label string) error
-	SaveCredentialFunc   func(label string, credentialData *domain.CredentialData, metadata string) error
-	GetCredentialFunc    func(label string) (*domain.CredentialData, string, error)
-	DeleteCredentialFunc func(label string) error
+	SaveTextFunc           func(label string, textData *domain.TextData, metadata string) error
+	GetTextFunc            func(label string) (*domain.TextData, string, error)
+	DeleteTextFunc         func(label string) error
+	SaveSSHKeyFunc         func(label string, sshKeyData *domain.SSHKeyData, metadata string) error
+	GetSSHKeyFunc          func(label string) (*domain.SSHKeyData, string, error)
+	DeleteSSHKeyFunc       func(label string) error
+	SaveCardFunc           func(label string, cardData *domain.CardData, metadata string) error
+	GetCardFunc            func(label string) (*domain.CardData, string, error)
+	DeleteCardFunc         func(label string) error
+	SaveCredentialFunc     func(label string, credentialData *domain.CredentialData, metadata string) error
+	GetCredentialFunc      func(label string) (*domain.CredentialData, string, error)
+	DeleteCredentialFunc   func(label string) error
+	SaveItemFunc           func(itemType string, label string, data domain.ItemData, metadata string) error
+	GetItemFunc            func(itemType string, label string) (domain.ItemData, string, error)
+	DeleteItemFunc         func(itemType string, label string) error
+	ItemTypeFunc           func(itemType string) (*itemtype.Type, error)
+	SaveItemTemplateFunc   func(name string, schema json.RawMessage) error
+	GetItemTemplateFunc    func(name string) (*domain.ItemTemplate, error)
+	ListItemTemplatesFunc  func() ([]domain.ItemTemplate, error)
+	DeleteItemTemplateFunc func(name string) error
+	ListItemsFunc          func() ([]domain.ItemInfo, error)
+	UploadFunc             func(filePath string, label string, metadata string) (string, error)
+	DownloadFunc           func(label string, outputPath string) error
+	FileInfoFunc           func(label string) (*domain.FileInfo, error)
+	DeleteFileFunc         func(label string) error
 }
 
 // Реализация методов интерфейса ClientUseCase для работы с текстовыми данными
@@ -45,6 +64,28 @@ func (m *MockDataClientUseCase) DeleteText(label string) error {
 	return nil
 }
 
+// Реализация методов интерфейса ClientUseCase для работы с ключами SSH
+func (m *MockDataClientUseCase) SaveSSHKey(label string, sshKeyData *domain.SSHKeyData, metadata string) error {
+	if m.SaveSSHKeyFunc != nil {
+		return m.SaveSSHKeyFunc(label, sshKeyData, metadata)
+	}
+	return nil
+}
+
+func (m *MockDataClientUseCase) GetSSHKey(label string) (*domain.SSHKeyData, string, error) {
+	if m.GetSSHKeyFunc != nil {
+		return m.GetSSHKeyFunc(label)
+	}
+	return nil, "", nil
+}
+
+func (m *MockDataClientUseCase) DeleteSSHKey(label string) error {
+	if m.DeleteSSHKeyFunc != nil {
+		return m.DeleteSSHKeyFunc(label)
+	}
+	return nil
+}
+
 // Реализация методов интерфейса ClientUseCase для работы с данными карт
 func (m *MockDataClientUseCase) SaveCard(label string, cardData *domain.CardData, metadata string) error {
 	if m.SaveCardFunc != nil {
@@ -89,6 +130,63 @@ func (m *MockDataClientUseCase) DeleteCredential(label string) error {
 	return nil
 }
 
+// Реализация методов интерфейса ClientUseCase для работы с записями структурированных типов
+func (m *MockDataClientUseCase) SaveItem(itemType string, label string, data domain.ItemData, metadata string) error {
+	if m.SaveItemFunc != nil {
+		return m.SaveItemFunc(itemType, label, data, metadata)
+	}
+	return nil
+}
+
+func (m *MockDataClientUseCase) GetItem(itemType string, label string) (domain.ItemData, string, error) {
+	if m.GetItemFunc != nil {
+		return m.GetItemFunc(itemType, label)
+	}
+	return nil, "", nil
+}
+
+func (m *MockDataClientUseCase) DeleteItem(itemType string, label string) error {
+	if m.DeleteItemFunc != nil {
+		return m.DeleteItemFunc(itemType, label)
+	}
+	return nil
+}
+
+func (m *MockDataClientUseCase) ItemType(itemType string) (*itemtype.Type, error) {
+	if m.ItemTypeFunc != nil {
+		return m.ItemTypeFunc(itemType)
+	}
+	return itemtype.Lookup(itemType)
+}
+
+func (m *MockDataClientUseCase) SaveItemTemplate(name string, schema json.RawMessage) error {
+	if m.SaveItemTemplateFunc != nil {
+		return m.SaveItemTemplateFunc(name, schema)
+	}
+	return nil
+}
+
+func (m *MockDataClientUseCase) GetItemTemplate(name string) (*domain.ItemTemplate, error) {
+	if m.GetItemTemplateFunc != nil {
+		return m.GetItemTemplateFunc(name)
+	}
+	return nil, &domain.Error{Message: "шаблон не найден", CodeValue: http.StatusNotFound}
+}
+
+func (m *MockDataClientUseCase) ListItemTemplates() ([]domain.ItemTemplate, error) {
+	if m.ListItemTemplatesFunc != nil {
+		return m.ListItemTemplatesFunc()
+	}
+	return []domain.ItemTemplate{}, nil
+}
+
+func (m *MockDataClientUseCase) DeleteItemTemplate(name string) error {
+	if m.DeleteItemTemplateFunc != nil {
+		return m.DeleteItemTemplateFunc(name)
+	}
+	return nil
+}
+
 // Реализация остальных методов интерфейса ClientUseCase, которые не используются в тестах
 func (m *MockDataClientUseCase) Login(username string, password string) error {
 	return nil
@@ -98,14 +196,71 @@ func (m *MockDataClientUseCase) Register(username string, password string, passw
 	return nil
 }
 
-func (m *MockDataClientUseCase) Upload(filePath string, label string) (string, error) {
+func (m *MockDataClientUseCase) Upload(filePath string, label string, metadata string) (string, error) {
+	if m.UploadFunc != nil {
+		return m.UploadFunc(filePath, label, metadata)
+	}
+	return "", nil
+}
+
+func (m *MockDataClientUseCase) Download(label string, outputPath string) error {
+	if m.DownloadFunc != nil {
+		return m.DownloadFunc(label, outputPath)
+	}
+	return nil
+}
+
+func (m *MockDataClientUseCase) UploadDir(dirPath string, label string, metadata string) (string, error) {
 	return "", nil
 }
 
-func (m *MockDataClientUseCase) Download(label string) error {
+func (m *MockDataClientUseCase) Extract(label string, destDir string) error {
+	return nil
+}
+
+func (m *MockDataClientUseCase) ListFiles() ([]domain.FileInfo, error) {
+	return nil, nil
+}
+
+func (m *MockDataClientUseCase) FileInfo(label string) (*domain.FileInfo, error) {
+	if m.FileInfoFunc != nil {
+		return m.FileInfoFunc(label)
+	}
+	return nil, nil
+}
+
+func (m *MockDataClientUseCase) RenameFile(label string, newLabel string) error {
+	return nil
+}
+
+func (m *MockDataClientUseCase) DeleteFile(label string) error {
+	if m.DeleteFileFunc != nil {
+		return m.DeleteFileFunc(label)
+	}
+	return nil
+}
+
+func (m *MockDataClientUseCase) Usage() (*domain.Usage, error) {
+	return nil, nil
+}
+
+func (m *MockDataClientUseCase) Settings() (*domain.UserSettings, error) {
+	return nil, nil
+}
+
+func (m *MockDataClientUseCase) SaveSettings(settings *domain.UserSettings) error {
 	return nil
 }
 
+func (m *MockDataClientUseCase) ListItems() ([]domain.ItemInfo, error) {
+	if m.ListItemsFunc != nil {
+		return m.ListItemsFunc()
+	}
+	return nil, nil
+}
+
+func (m *MockDataClientUseCase) SetProgress(progress domain.ProgressFunc) {}
+
 // Тесты для команд работы с текстовыми данными
 
 // TestCommand_SaveTextCmd_Success тестирует успешное сохранение текстовых данных
@@ -155,7 +310,7 @@ func TestCommand_SaveTextCmd_Success(t *testing.T) {
 	saveTextCmd := cmd.SaveTextCmd()
 
 	// Выполняем команду
-	saveTextCmd.Run(saveTextCmd, []string{})
+	saveTextCmd.RunE(saveTextCmd, []string{})
 
 	// Закрываем pipe для записи и копируем вывод в буфер
 	w2.Close()
@@ -191,6 +346,9 @@ func TestCommand_SaveTextCmd_Error(t *testing.T) {
 	defer func() { os.Stdout = oldStdout }()
 	r2, w2, _ := os.Pipe()
 	os.Stdout = w2
+	oldStderr := os.Stderr
+	defer func() { os.Stderr = oldStderr }()
+	os.Stderr = w2
 
 	// Создаем мок для ClientUseCase
 	mockClientUseCase := &MockDataClientUseCase{
@@ -208,7 +366,7 @@ func TestCommand_SaveTextCmd_Error(t *testing.T) {
 	saveTextCmd := cmd.SaveTextCmd()
 
 	// Выполняем команду
-	saveTextCmd.Run(saveTextCmd, []string{})
+	saveTextCmd.RunE(saveTextCmd, []string{})
 
 	// Закрываем pipe для записи и копируем вывод в буфер
 	w2.Close()
@@ -267,7 +425,7 @@ func TestCommand_GetTextCmd_Success(t *testing.T) {
 	getTextCmd := cmd.GetTextCmd()
 
 	// Выполняем команду
-	getTextCmd.Run(getTextCmd, []string{})
+	getTextCmd.RunE(getTextCmd, []string{})
 
 	// Закрываем pipe для записи и копируем вывод в буфер
 	w2.Close()
@@ -303,6 +461,9 @@ func TestCommand_GetTextCmd_Error(t *testing.T) {
 	defer func() { os.Stdout = oldStdout }()
 	r2, w2, _ := os.Pipe()
 	os.Stdout = w2
+	oldStderr := os.Stderr
+	defer func() { os.Stderr = oldStderr }()
+	os.Stderr = w2
 
 	// Создаем мок для ClientUseCase
 	mockClientUseCase := &MockDataClientUseCase{
@@ -320,7 +481,7 @@ func TestCommand_GetTextCmd_Error(t *testing.T) {
 	getTextCmd := cmd.GetTextCmd()
 
 	// Выполняем команду
-	getTextCmd.Run(getTextCmd, []string{})
+	getTextCmd.RunE(getTextCmd, []string{})
 
 	// Закрываем pipe для записи и копируем вывод в буфер
 	w2.Close()
@@ -377,7 +538,7 @@ func TestCommand_DeleteTextCmd_Success(t *testing.T) {
 	deleteTextCmd := cmd.DeleteTextCmd()
 
 	// Выполняем команду
-	deleteTextCmd.Run(deleteTextCmd, []string{})
+	deleteTextCmd.RunE(deleteTextCmd, []string{})
 
 	// Закрываем pipe для записи и копируем вывод в буфер
 	w2.Close()
@@ -413,6 +574,9 @@ func TestCommand_DeleteTextCmd_Error(t *testing.T) {
 	defer func() { os.Stdout = oldStdout }()
 	r2, w2, _ := os.Pipe()
 	os.Stdout = w2
+	oldStderr := os.Stderr
+	defer func() { os.Stderr = oldStderr }()
+	os.Stderr = w2
 
 	// Создаем мок для ClientUseCase
 	mockClientUseCase := &MockDataClientUseCase{
@@ -430,7 +594,7 @@ func TestCommand_DeleteTextCmd_Error(t *testing.T) {
 	deleteTextCmd := cmd.DeleteTextCmd()
 
 	// Выполняем команду
-	deleteTextCmd.Run(deleteTextCmd, []string{})
+	deleteTextCmd.RunE(deleteTextCmd, []string{})
 
 	// Закрываем pipe для записи и копируем вывод в буфер
 	w2.Close()
@@ -502,7 +666,7 @@ func TestCommand_SaveCardCmd_Success(t *testing.T) {
 	saveCardCmd := cmd.SaveCardCmd()
 
 	// Выполняем команду
-	saveCardCmd.Run(saveCardCmd, []string{})
+	saveCardCmd.RunE(saveCardCmd, []string{})
 
 	// Закрываем pipe для записи и копируем вывод в буфер
 	w2.Close()
@@ -539,6 +703,9 @@ func TestCommand_SaveCardCmd_Error(t *testing.T) {
 	defer func() { os.Stdout = oldStdout }()
 	r2, w2, _ := os.Pipe()
 	os.Stdout = w2
+	oldStderr := os.Stderr
+	defer func() { os.Stderr = oldStderr }()
+	os.Stderr = w2
 
 	// Создаем мок для ClientUseCase
 	mockClientUseCase := &MockDataClientUseCase{
@@ -556,7 +723,7 @@ func TestCommand_SaveCardCmd_Error(t *testing.T) {
 	saveCardCmd := cmd.SaveCardCmd()
 
 	// Выполняем команду
-	saveCardCmd.Run(saveCardCmd, []string{})
+	saveCardCmd.RunE(saveCardCmd, []string{})
 
 	// Закрываем pipe для записи и копируем вывод в буфер
 	w2.Close()
@@ -618,7 +785,7 @@ func TestCommand_GetCardCmd_Success(t *testing.T) {
 	getCardCmd := cmd.GetCardCmd()
 
 	// Выполняем команду
-	getCardCmd.Run(getCardCmd, []string{})
+	getCardCmd.RunE(getCardCmd, []string{})
 
 	// Закрываем pipe для записи и копируем вывод в буфер
 	w2.Close()
@@ -657,6 +824,9 @@ func TestCommand_GetCardCmd_Error(t *testing.T) {
 	defer func() { os.Stdout = oldStdout }()
 	r2, w2, _ := os.Pipe()
 	os.Stdout = w2
+	oldStderr := os.Stderr
+	defer func() { os.Stderr = oldStderr }()
+	os.Stderr = w2
 
 	// Создаем мок для ClientUseCase
 	mockClientUseCase := &MockDataClientUseCase{
@@ -674,7 +844,7 @@ func TestCommand_GetCardCmd_Error(t *testing.T) {
 	getCardCmd := cmd.GetCardCmd()
 
 	// Выполняем команду
-	getCardCmd.Run(getCardCmd, []string{})
+	getCardCmd.RunE(getCardCmd, []string{})
 
 	// Закрываем pipe для записи и копируем вывод в буфер
 	w2.Close()
@@ -731,7 +901,7 @@ func TestCommand_DeleteCardCmd_Success(t *testing.T) {
 	deleteCardCmd := cmd.DeleteCardCmd()
 
 	// Выполняем команду
-	deleteCardCmd.Run(deleteCardCmd, []string{})
+	deleteCardCmd.RunE(deleteCardCmd, []string{})
 
 	// Закрываем pipe для записи и копируем вывод в буфер
 	w2.Close()
@@ -767,6 +937,9 @@ func TestCommand_DeleteCardCmd_Error(t *testing.T) {
 	defer func() { os.Stdout = oldStdout }()
 	r2, w2, _ := os.Pipe()
 	os.Stdout = w2
+	oldStderr := os.Stderr
+	defer func() { os.Stderr = oldStderr }()
+	os.Stderr = w2
 
 	// Создаем мок для ClientUseCase
 	mockClientUseCase := &MockDataClientUseCase{
@@ -784,7 +957,7 @@ func TestCommand_DeleteCardCmd_Error(t *testing.T) {
 	deleteCardCmd := cmd.DeleteCardCmd()
 
 	// Выполняем команду
-	deleteCardCmd.Run(deleteCardCmd, []string{})
+	deleteCardCmd.RunE(deleteCardCmd, []string{})
 
 	// Закрываем pipe для записи и копируем вывод в буфер
 	w2.Close()
@@ -810,7 +983,7 @@ func TestCommand_SaveCredentialCmd_Success(t *testing.T) {
 	os.Stdin = r
 
 	// Пишем тестовые данные в пайп
-	input := "test_label\ntest_login\ntest_password\n"
+	input := "test_label\ntest_login\ntest_password\ntest_password\n"
 	go func() {
 		w.Write([]byte(input))
 		w.Close()
@@ -849,7 +1022,7 @@ func TestCommand_SaveCredentialCmd_Success(t *testing.T) {
 	saveCredentialCmd := cmd.SaveCredentialCmd()
 
 	// Выполняем команду
-	saveCredentialCmd.Run(saveCredentialCmd, []string{})
+	saveCredentialCmd.RunE(saveCredentialCmd, []string{})
 
 	// Закрываем pipe для записи и копируем вывод в буфер
 	w2.Close()
@@ -873,7 +1046,7 @@ func TestCommand_SaveCredentialCmd_Error(t *testing.T) {
 	os.Stdin = r
 
 	// Пишем тестовые данные в пайп
-	input := "test_label\ntest_login\ntest_password\n"
+	input := "test_label\ntest_login\ntest_password\ntest_password\n"
 	go func() {
 		w.Write([]byte(input))
 		w.Close()
@@ -885,6 +1058,9 @@ func TestCommand_SaveCredentialCmd_Error(t *testing.T) {
 	defer func() { os.Stdout = oldStdout }()
 	r2, w2, _ := os.Pipe()
 	os.Stdout = w2
+	oldStderr := os.Stderr
+	defer func() { os.Stderr = oldStderr }()
+	os.Stderr = w2
 
 	// Создаем мок для ClientUseCase
 	mockClientUseCase := &MockDataClientUseCase{
@@ -902,7 +1078,7 @@ func TestCommand_SaveCredentialCmd_Error(t *testing.T) {
 	saveCredentialCmd := cmd.SaveCredentialCmd()
 
 	// Выполняем команду
-	saveCredentialCmd.Run(saveCredentialCmd, []string{})
+	saveCredentialCmd.RunE(saveCredentialCmd, []string{})
 
 	// Закрываем pipe для записи и копируем вывод в буфер
 	w2.Close()
@@ -962,7 +1138,7 @@ func TestCommand_GetCredentialCmd_Success(t *testing.T) {
 	getCredentialCmd := cmd.GetCredentialCmd()
 
 	// Выполняем команду
-	getCredentialCmd.Run(getCredentialCmd, []string{})
+	getCredentialCmd.RunE(getCredentialCmd, []string{})
 
 	// Закрываем pipe для записи и копируем вывод в буфер
 	w2.Close()
@@ -998,6 +1174,9 @@ func TestCommand_GetCredentialCmd_Error(t *testing.T) {
 	defer func() { os.Stdout = oldStdout }()
 	r2, w2, _ := os.Pipe()
 	os.Stdout = w2
+	oldStderr := os.Stderr
+	defer func() { os.Stderr = oldStderr }()
+	os.Stderr = w2
 
 	// Создаем мок для ClientUseCase
 	mockClientUseCase := &MockDataClientUseCase{
@@ -1015,7 +1194,7 @@ func TestCommand_GetCredentialCmd_Error(t *testing.T) {
 	getCredentialCmd := cmd.GetCredentialCmd()
 
 	// Выполняем команду
-	getCredentialCmd.Run(getCredentialCmd, []string{})
+	getCredentialCmd.RunE(getCredentialCmd, []string{})
 
 	// Закрываем pipe для записи и копируем вывод в буфер
 	w2.Close()
@@ -1072,7 +1251,7 @@ func TestCommand_DeleteCredentialCmd_Success(t *testing.T) {
 	deleteCredentialCmd := cmd.DeleteCredentialCmd()
 
 	// Выполняем команду
-	deleteCredentialCmd.Run(deleteCredentialCmd, []string{})
+	deleteCredentialCmd.RunE(deleteCredentialCmd, []string{})
 
 	// Закрываем pipe для записи и копируем вывод в буфер
 	w2.Close()
@@ -1108,6 +1287,9 @@ func TestCommand_DeleteCredentialCmd_Error(t *testing.T) {
 	defer func() { os.Stdout = oldStdout }()
 	r2, w2, _ := os.Pipe()
 	os.Stdout = w2
+	oldStderr := os.Stderr
+	defer func() { os.Stderr = oldStderr }()
+	os.Stderr = w2
 
 	// Создаем мок для ClientUseCase
 	mockClientUseCase := &MockDataClientUseCase{
@@ -1125,7 +1307,7 @@ func TestCommand_DeleteCredentialCmd_Error(t *testing.T) {
 	deleteCredentialCmd := cmd.DeleteCredentialCmd()
 
 	// Выполняем команду
-	deleteCredentialCmd.Run(deleteCredentialCmd, []string{})
+	deleteCredentialCmd.RunE(deleteCredentialCmd, []string{})
 
 	// Закрываем pipe для записи и копируем вывод в буфер
 	w2.Close()
@@ -0,0 +1,12 @@
+//go:build !aix && !darwin && !dragonfly && !freebsd && !linux && !netbsd && !openbsd && !solaris && !zos && !windows
+
+package command
+
+import (
+	"syscall"
+)
+
+// detachAttr не меняет параметры запуска процесса на платформах без отдельных сессий
+func detachAttr() *syscall.SysProcAttr {
+	return nil
+}
@@ -0,0 +1,12 @@
+//go:build aix || darwin || dragonfly || freebsd || linux || netbsd || openbsd || solaris || zos
+
+package command
+
+import (
+	"syscall"
+)
+
+// detachAttr запускает процесс в отдельной сессии, чтобы он пережил закрытие терминала
+func detachAttr() *syscall.SysProcAttr {
+	return &syscall.SysProcAttr{Setsid: true}
+}
@@ -0,0 +1,13 @@
+//go:build windows
+
+package command
+
+import (
+	"golang.org/x/sys/windows"
+	"syscall"
+)
+
+// detachAttr запускает процесс без консоли, чтобы он пережил закрытие окна терминала
+func detachAttr() *syscall.SysProcAttr {
+	return &syscall.SysProcAttr{CreationFlags: windows.DETACHED_PROCESS | windows.CREATE_NEW_PROCESS_GROUP}
+}
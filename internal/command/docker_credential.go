@@ -0,0 +1,213 @@
+package command
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/SmirnovND/gophkeeper/internal/dockercredential"
+	"github.com/SmirnovND/gophkeeper/internal/domain"
+	"github.com/SmirnovND/gophkeeper/internal/urimatch"
+	"github.com/spf13/cobra"
+	"os"
+)
+
+// DockerCredentialCmd создает команду помощника учетных данных docker
+func (c *Command) DockerCredentialCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "docker-credential <get|store|erase|list|version>",
+		Short: "Помощник учетных данных docker",
+		Long: "Реализует протокол docker-credential-helpers: docker передает запрос в stdin и читает ответ в JSON из stdout.\n" +
+			"Учетные данные реестров хранятся записями credential с меткой docker:<адрес реестра>,\n" +
+			"а не в ~/.docker/config.json. Реестр ищется сначала по адресам учетных данных, затем по метке; store и erase\n" +
+			"меняют только записи помощника. Подключение: \"credsStore\": \"passcli\" в ~/.docker/config.json\n" +
+			"и docker-credential-passcli в PATH, см. README",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var err error
+			switch args[0] {
+			case "get":
+				err = c.dockerCredentialGet()
+			case "store":
+				err = c.dockerCredentialStore()
+			case "erase":
+				err = c.dockerCredentialErase()
+			case "list":
+				err = c.dockerCredentialList()
+			case "version":
+				fmt.Printf("docker-credential-passcli %s\n", version)
+			default:
+				err = fmt.Errorf("%w: неизвестная операция '%s', допустимы get, store, erase, list и version", errUsage, args[0])
+			}
+			if err != nil {
+				// docker читает сообщение об ошибке помощника из stdout
+				fmt.Fprintln(os.Stdout, err)
+				return &reportedError{err: err}
+			}
+			return nil
+		},
+	}
+
+	return cmd
+}
+
+// dockerCredentialGet выводит учетные данные реестра, адрес которого передан в stdin
+func (c *Command) dockerCredentialGet() error {
+	serverURL, err := dockercredential.ReadServerURL(os.Stdin)
+	if err != nil {
+		return err
+	}
+	info, err := c.findDockerCredential(serverURL)
+	if err != nil {
+		return err
+	}
+	data, _, err := c.clientUseCase.GetCredential(info.Label)
+	if err != nil {
+		return err
+	}
+	return json.NewEncoder(os.Stdout).Encode(&dockercredential.Credentials{
+		ServerURL: serverURL,
+		Username:  data.Login,
+		Secret:    data.Password,
+	})
+}
+
+// dockerCredentialStore сохраняет учетные данные реестра. Обновляется только запись, созданная помощником,
+// даже если docker передал адрес в другой форме; у нее меняются только логин и пароль. Записи пользователя,
+// подошедшие к адресу реестра, не меняются, а для реестра создается новая запись
+func (c *Command) dockerCredentialStore() error {
+	credentials, err := dockercredential.ReadCredentials(os.Stdin)
+	if err != nil {
+		return err
+	}
+
+	items, err := c.clientUseCase.ListItems()
+	if err != nil {
+		return err
+	}
+	if info := findOwnedDockerCredential(items, credentials.ServerURL); info != nil {
+		data, _, err := c.clientUseCase.GetCredential(info.Label)
+		if err != nil {
+			return err
+		}
+		updated := *data
+		updated.Login, updated.Password = credentials.Username, credentials.Secret
+		return c.clientUseCase.SaveCredential(info.Label, &updated, info.Metadata)
+	}
+
+	label := dockercredential.Label(credentials.ServerURL)
+	for _, item := range items {
+		if item.Label == label {
+			return fmt.Errorf("метка '%s' занята записью типа %s", label, item.Type)
+		}
+	}
+
+	data := &domain.CredentialData{Login: credentials.Username, Password: credentials.Secret,
+		URIs: []domain.CredentialURI{dockerCredentialURI(credentials.ServerURL)}}
+	return c.clientUseCase.SaveCredential(label, data, "")
+}
+
+// dockerCredentialErase удаляет учетные данные реестра, адрес которого передан в stdin.
+// Удаляются только записи с меткой docker:, записи пользователя остаются
+func (c *Command) dockerCredentialErase() error {
+	serverURL, err := dockercredential.ReadServerURL(os.Stdin)
+	if err != nil {
+		return err
+	}
+	items, err := c.clientUseCase.ListItems()
+	if err != nil {
+		return err
+	}
+	info := findOwnedDockerCredential(items, serverURL)
+	if info == nil {
+		return dockercredential.ErrNotFound
+	}
+	if _, ok := dockercredential.ServerURL(info.Label); !ok {
+		fmt.Fprintf(os.Stderr, "Учетные данные '%s' созданы не помощником docker и не удалены\n", info.Label)
+		return nil
+	}
+	return c.clientUseCase.DeleteCredential(info.Label)
+}
+
+// dockerCredentialList выводит адреса реестров и имена пользователей всех учетных данных docker
+func (c *Command) dockerCredentialList() error {
+	items, err := c.clientUseCase.ListItems()
+	if err != nil {
+		return err
+	}
+
+	resolver := c.newSecretResolver()
+	var refs []secretRef
+	for _, item := range items {
+		if _, ok := dockercredential.ServerURL(item.Label); ok && item.Type == domain.UserDataTypeCredential {
+			refs = append(refs, secretRef{Type: item.Type, Label: item.Label, Field: "login"})
+		}
+	}
+	if err := resolver.prefetch(refs); err != nil {
+		return err
+	}
+
+	registries := make(map[string]string, len(refs))
+	for _, ref := range refs {
+		serverURL, _ := dockercredential.ServerURL(ref.Label)
+		_, login, err := resolver.resolve(ref)
+		if err != nil {
+			return err
+		}
+		registries[serverURL] = login
+	}
+	return json.NewEncoder(os.Stdout).Encode(registries)
+}
+
+// findDockerCredential возвращает учетные данные реестра serverURL: сначала по адресам записей с их правилами
+// сравнения, затем по записям помощника
+func (c *Command) findDockerCredential(serverURL string) (*domain.ItemInfo, error) {
+	items, err := c.clientUseCase.ListItems()
+	if err != nil {
+		return nil, err
+	}
+
+	matches := matchCredentials(items, func(uri domain.CredentialURI) bool {
+		return urimatch.Match(uri, serverURL)
+	})
+	if len(matches) > 0 {
+		return matches[0].info, nil
+	}
+	if info := findOwnedDockerCredential(items, serverURL); info != nil {
+		return info, nil
+	}
+	return nil, dockercredential.ErrNotFound
+}
+
+// findOwnedDockerCredential возвращает запись, созданную помощником для реестра serverURL: по точной метке,
+// по метке с тем же реестром в другой форме адреса, затем по адресу, который записывает store.
+// Записи пользователя, подошедшие к адресу по домену или другому правилу, не возвращаются
+func findOwnedDockerCredential(items []domain.ItemInfo, serverURL string) *domain.ItemInfo {
+	var match *domain.ItemInfo
+	for i := range items {
+		item := &items[i]
+		if item.Type != domain.UserDataTypeCredential {
+			continue
+		}
+		if item.Label == dockercredential.Label(serverURL) {
+			return item
+		}
+		if registry, ok := dockercredential.ServerURL(item.Label); ok && match == nil && dockercredential.SameRegistry(registry, serverURL) {
+			match = item
+		}
+	}
+	if match != nil {
+		return match
+	}
+
+	uri := dockerCredentialURI(serverURL)
+	for i := range items {
+		if items[i].Type == domain.UserDataTypeCredential && hasURI(&items[i], uri) {
+			return &items[i]
+		}
+	}
+	return nil
+}
+
+// dockerCredentialURI возвращает адрес, который store записывает в новые учетные данные реестра
+func dockerCredentialURI(serverURL string) domain.CredentialURI {
+	return domain.CredentialURI{URI: serverURL, Match: urimatch.RuleHost}
+}
@@ -0,0 +1,162 @@
+package command
+
+import (
+	"encoding/json"
+	"github.com/SmirnovND/gophkeeper/internal/domain"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// newDockerCredentialVault возвращает хранилище с учетными данными реестров и посторонней записью
+func newDockerCredentialVault() *fakeVault {
+	vault := newFakeVault()
+	save := vault.command().clientUseCase.SaveCredential
+	save("docker:https://index.docker.io/v1/", &domain.CredentialData{Login: "alice", Password: "hub-token"}, "URL: https://index.docker.io/v1/")
+	save("docker:ghcr.io", &domain.CredentialData{Login: "bob", Password: "ghp_token"}, "URL: ghcr.io")
+	save("GitHub", &domain.CredentialData{Login: "bob", Password: "password"}, "")
+	return vault
+}
+
+// TestCommand_DockerCredentialCmd_Get тестирует поиск учетных данных по точному и эквивалентному адресу реестра
+func TestCommand_DockerCredentialCmd_Get(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"точный адрес", "https://index.docker.io/v1/\n", `{"ServerURL":"https://index.docker.io/v1/","Username":"alice","Secret":"hub-token"}`},
+		{"адрес со схемой", "https://ghcr.io", `{"ServerURL":"https://ghcr.io","Username":"bob","Secret":"ghp_token"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			withStdin(t, tt.input, false)
+			out, err := runWithOutput(t, newDockerCredentialVault().command().DockerCredentialCmd(), "get")
+			if err != nil {
+				t.Fatalf("Неожиданная ошибка: %v", err)
+			}
+			if strings.TrimSpace(out) != tt.want {
+				t.Errorf("Ожидалось %s, получено %s", tt.want, out)
+			}
+		})
+	}
+
+	withStdin(t, "registry.example.com", false)
+	out, err := runWithOutput(t, newDockerCredentialVault().command().DockerCredentialCmd(), "get")
+	if err == nil || strings.TrimSpace(out) != "credentials not found in native keychain" {
+		t.Errorf("Ожидалось сообщение docker об отсутствии учетных данных, получено %q, %v", out, err)
+	}
+}
+
+// TestCommand_DockerCredentialCmd_StoreEraseList тестирует сохранение, обновление, удаление и список учетных данных
+func TestCommand_DockerCredentialCmd_StoreEraseList(t *testing.T) {
+	vault := newDockerCredentialVault()
+	run := func(action string, input string) string {
+		t.Helper()
+		withStdin(t, input, false)
+		out, err := runWithOutput(t, vault.command().DockerCredentialCmd(), action)
+		if err != nil {
+			t.Fatalf("Неожиданная ошибка %s: %v", action, err)
+		}
+		return out
+	}
+
+	run("store", `{"ServerURL":"https://ghcr.io/","Username":"bob","Secret":"new-token"}`)
+	run("store", `{"ServerURL":"registry.example.com","Username":"<token>","Secret":"identity"}`)
+	if vault.credentials["docker:ghcr.io"].Password != "new-token" ||
+		!reflect.DeepEqual(vault.credentials["docker:registry.example.com"].URIs, []domain.CredentialURI{{URI: "registry.example.com", Match: "host"}}) {
+		t.Errorf("Учетные данные сохранены неверно: %+v", vault.items)
+	}
+
+	run("erase", "https://index.docker.io/v1/")
+	want := []string{"GitHub", "docker:ghcr.io", "docker:registry.example.com"}
+	if got := vault.labels(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("Ожидались записи %v, получено %v", want, got)
+	}
+
+	var registries map[string]string
+	if err := json.Unmarshal([]byte(run("list", "")), &registries); err != nil {
+		t.Fatalf("Неверный ответ list: %v", err)
+	}
+	wantRegistries := map[string]string{"ghcr.io": "bob", "registry.example.com": "<token>"}
+	if !reflect.DeepEqual(registries, wantRegistries) {
+		t.Errorf("Ожидалось %v, получено %v", wantRegistries, registries)
+	}
+}
+
+// TestCommand_DockerCredentialCmd_LabelTaken тестирует отказ сохранять учетные данные под меткой записи другого типа
+func TestCommand_DockerCredentialCmd_LabelTaken(t *testing.T) {
+	vault := newFakeVault()
+	vault.command().clientUseCase.SaveText("docker:ghcr.io", &domain.TextData{Content: "заметка"}, "")
+
+	withStdin(t, `{"ServerURL":"ghcr.io","Username":"bob","Secret":"token"}`, false)
+	if _, err := runWithOutput(t, vault.command().DockerCredentialCmd(), "store"); err == nil {
+		t.Error("Ожидалась ошибка для занятой метки")
+	}
+	if vault.texts["docker:ghcr.io"].Content != "заметка" {
+		t.Error("Запись другого типа не должна изменяться")
+	}
+}
+
+// TestCommand_DockerCredentialCmd_StoreKeepsFields тестирует, что обновление токена реестра не стирает
+// код TOTP, адреса и дополнительные поля записи
+func TestCommand_DockerCredentialCmd_StoreKeepsFields(t *testing.T) {
+	vault := newFakeVault()
+	stored := &domain.CredentialData{Login: "bob", Password: "old-token", TOTP: "otpauth://totp/ghcr?secret=JBSWY3DPEHPK3PXP",
+		URIs:   []domain.CredentialURI{{URI: "https://ghcr.io", Match: "host"}},
+		Fields: []domain.CustomField{{Name: "org", Type: domain.CustomFieldText, Value: "acme"}}}
+	vault.command().clientUseCase.SaveCredential("docker:ghcr.io", stored, "URL: ghcr.io")
+
+	withStdin(t, `{"ServerURL":"https://ghcr.io","Username":"bob-ci","Secret":"new-token"}`, false)
+	if _, err := runWithOutput(t, vault.command().DockerCredentialCmd(), "store"); err != nil {
+		t.Fatalf("Неожиданная ошибка: %v", err)
+	}
+
+	want := *stored
+	want.Login, want.Password = "bob-ci", "new-token"
+	if got := vault.credentials["docker:ghcr.io"]; got == nil || !reflect.DeepEqual(*got, want) {
+		t.Errorf("Ожидалось %+v, получено %+v", want, got)
+	}
+	if vault.items["docker:ghcr.io"].Metadata != "URL: ghcr.io" {
+		t.Errorf("Метаинформация не должна изменяться, получено %q", vault.items["docker:ghcr.io"].Metadata)
+	}
+}
+
+// TestCommand_DockerCredentialCmd_URIs тестирует поиск учетных данных по адресам записи и то, что store и erase
+// не меняют найденные так записи, созданные не помощником
+func TestCommand_DockerCredentialCmd_URIs(t *testing.T) {
+	vault := newDockerCredentialVault()
+	vault.command().clientUseCase.SaveCredential("Harbor", &domain.CredentialData{Login: "robot", Password: "harbor-token",
+		URIs: []domain.CredentialURI{{URI: "https://harbor.corp", Match: "host"}}}, "")
+	vault.command().clientUseCase.SaveCredential("GitLab", &domain.CredentialData{Login: "dave@example.com", Password: "web-pass",
+		URIs: []domain.CredentialURI{{URI: "gitlab.com"}}}, "")
+
+	withStdin(t, "harbor.corp", false)
+	out, err := runWithOutput(t, vault.command().DockerCredentialCmd(), "get")
+	want := `{"ServerURL":"harbor.corp","Username":"robot","Secret":"harbor-token"}`
+	if err != nil || strings.TrimSpace(out) != want {
+		t.Errorf("Ожидалось %s, получено %q, %v", want, out, err)
+	}
+
+	withStdin(t, `{"ServerURL":"harbor.corp","Username":"robot","Secret":"rotated"}`, false)
+	if _, err := runWithOutput(t, vault.command().DockerCredentialCmd(), "store"); err != nil {
+		t.Fatalf("Неожиданная ошибка: %v", err)
+	}
+	withStdin(t, `{"ServerURL":"registry.gitlab.com","Username":"dave","Secret":"glpat"}`, false)
+	if _, err := runWithOutput(t, vault.command().DockerCredentialCmd(), "store"); err != nil {
+		t.Fatalf("Неожиданная ошибка: %v", err)
+	}
+	if vault.credentials["Harbor"].Password != "harbor-token" || vault.credentials["GitLab"].Password != "web-pass" ||
+		vault.credentials["docker:harbor.corp"].Password != "rotated" || vault.credentials["docker:registry.gitlab.com"].Password != "glpat" {
+		t.Errorf("Ожидались новые записи помощника без изменения записей пользователя, получено %+v", vault.items)
+	}
+
+	withStdin(t, "harbor.corp", false)
+	if _, err := runWithOutput(t, vault.command().DockerCredentialCmd(), "erase"); err != nil {
+		t.Fatalf("Неожиданная ошибка: %v", err)
+	}
+	if vault.credentials["Harbor"] == nil || vault.items["docker:harbor.corp"].Label != "" {
+		t.Errorf("Ожидалось удаление только записи помощника, получено %+v", vault.items)
+	}
+}
@@ -0,0 +1,96 @@
+package command
+
+import (
+	"errors"
+	"fmt"
+	"github.com/SmirnovND/gophkeeper/internal/domain"
+	"net/http"
+	"net/url"
+	"os"
+)
+
+// Коды завершения passcli
+const (
+	ExitOK       = 0
+	ExitError    = 1
+	ExitUsage    = 2
+	ExitNotFound = 3
+	ExitAuth     = 4
+	ExitNetwork  = 5
+)
+
+// errUsage возвращается при неверных значениях аргументов и флагов
+var errUsage = errors.New("неверные аргументы")
+
+// reportedError - ошибка, о которой команда уже сообщила пользователю
+type reportedError struct {
+	err error
+}
+
+func (e *reportedError) Error() string {
+	return e.err.Error()
+}
+
+func (e *reportedError) Unwrap() error {
+	return e.err
+}
+
+// childExitError передает код завершения процесса, запущенного командой, в код завершения passcli
+type childExitError struct {
+	code int
+}
+
+func (e *childExitError) Error() string {
+	return fmt.Sprintf("процесс завершился с кодом %d", e.code)
+}
+
+// fail выводит сообщение об ошибке в stderr и возвращает ошибку для выбора кода завершения
+func fail(message string, err error) error {
+	fmt.Fprintln(os.Stderr, message, err)
+	return &reportedError{err: err}
+}
+
+// PrintError выводит в stderr ошибку выполнения команды, если команда не сообщила о ней сама,
+// например ошибку разбора аргументов
+func PrintError(err error) {
+	var reported *reportedError
+	if err != nil && !errors.As(err, &reported) {
+		fmt.Fprintln(os.Stderr, "Ошибка:", err)
+	}
+}
+
+// ExitCode возвращает код завершения для ошибки выполнения команды.
+// Ошибки, о которых команда не сообщила сама, возникают при разборе аргументов и флагов
+func ExitCode(err error) int {
+	if err == nil {
+		return ExitOK
+	}
+
+	var statusErr *domain.Error
+	if errors.As(err, &statusErr) {
+		switch statusErr.Code() {
+		case http.StatusNotFound:
+			return ExitNotFound
+		case http.StatusUnauthorized, http.StatusForbidden:
+			return ExitAuth
+		}
+	}
+
+	var childErr *childExitError
+	if errors.As(err, &childErr) {
+		return childErr.code
+	}
+
+	var urlErr *url.Error
+	var reported *reportedError
+	switch {
+	case errors.Is(err, domain.ErrNotLoggedIn):
+		return ExitAuth
+	case errors.As(err, &urlErr):
+		return ExitNetwork
+	case errors.Is(err, errMissingInput), errors.Is(err, errUsage), errors.Is(err, domain.ErrProfileNotFound), !errors.As(err, &reported):
+		return ExitUsage
+	default:
+		return ExitError
+	}
+}
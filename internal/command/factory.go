@@ -4,12 +4,15 @@ import "github.com/SmirnovND/gophkeeper/internal/interfaces"
 
 type Command struct {
 	clientUseCase interfaces.ClientUseCase
+	config        interfaces.ConfigClient
 }
 
 func NewCommand(
 	ClientUseCase interfaces.ClientUseCase,
+	Config interfaces.ConfigClient,
 ) interfaces.Command {
 	return &Command{
 		clientUseCase: ClientUseCase,
+		config:        Config,
 	}
 }
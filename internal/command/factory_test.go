@@ -1,8 +1,10 @@
 package command
 
 import (
+	"encoding/json"
 	"github.com/SmirnovND/gophkeeper/internal/domain"
 	"github.com/SmirnovND/gophkeeper/internal/interfaces"
+	"github.com/SmirnovND/gophkeeper/internal/itemtype"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"testing"
@@ -23,16 +25,85 @@ func (m *MockClientUseCaseForFactory) Register(username string, password string,
 	return args.Error(0)
 }
 
-func (m *MockClientUseCaseForFactory) Upload(filePath string, label string) (string, error) {
-	args := m.Called(filePath, label)
+func (m *MockClientUseCaseForFactory) Upload(filePath string, label string, metadata string) (string, error) {
+	args := m.Called(filePath, label, metadata)
 	return args.String(0), args.Error(1)
 }
 
-func (m *MockClientUseCaseForFactory) Download(label string) error {
+func (m *MockClientUseCaseForFactory) Download(label string, outputPath string) error {
+	args := m.Called(label, outputPath)
+	return args.Error(0)
+}
+
+func (m *MockClientUseCaseForFactory) UploadDir(dirPath string, label string, metadata string) (string, error) {
+	args := m.Called(dirPath, label, metadata)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockClientUseCaseForFactory) Extract(label string, destDir string) error {
+	args := m.Called(label, destDir)
+	return args.Error(0)
+}
+
+func (m *MockClientUseCaseForFactory) ListFiles() ([]domain.FileInfo, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.FileInfo), args.Error(1)
+}
+
+func (m *MockClientUseCaseForFactory) FileInfo(label string) (*domain.FileInfo, error) {
 	args := m.Called(label)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.FileInfo), args.Error(1)
+}
+
+func (m *MockClientUseCaseForFactory) RenameFile(label string, newLabel string) error {
+	args := m.Called(label, newLabel)
 	return args.Error(0)
 }
 
+func (m *MockClientUseCaseForFactory) DeleteFile(label string) error {
+	args := m.Called(label)
+	return args.Error(0)
+}
+
+func (m *MockClientUseCaseForFactory) Usage() (*domain.Usage, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Usage), args.Error(1)
+}
+
+func (m *MockClientUseCaseForFactory) Settings() (*domain.UserSettings, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.UserSettings), args.Error(1)
+}
+
+func (m *MockClientUseCaseForFactory) SaveSettings(settings *domain.UserSettings) error {
+	args := m.Called(settings)
+	return args.Error(0)
+}
+
+func (m *MockClientUseCaseForFactory) ListItems() ([]domain.ItemInfo, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.ItemInfo), args.Error(1)
+}
+
+func (m *MockClientUseCaseForFactory) SetProgress(progress domain.ProgressFunc) {
+	m.Called(progress)
+}
+
 func (m *MockClientUseCaseForFactory) SaveText(label string, textData *domain.TextData, metadata string) error {
 	args := m.Called(label, textData, metadata)
 	return args.Error(0)
@@ -52,6 +123,66 @@ func (m *MockClientUseCaseForFactory) DeleteText(label string) error {
 	return args.Error(0)
 }
 
+func (m *MockClientUseCaseForFactory) SaveSSHKey(label string, sshKeyData *domain.SSHKeyData, metadata string) error {
+	args := m.Called(label, sshKeyData, metadata)
+	return args.Error(0)
+}
+
+func (m *MockClientUseCaseForFactory) GetSSHKey(label string) (*domain.SSHKeyData, string, error) {
+	args := m.Called(label)
+	return args.Get(0).(*domain.SSHKeyData), args.String(1), args.Error(2)
+}
+
+func (m *MockClientUseCaseForFactory) DeleteSSHKey(label string) error {
+	args := m.Called(label)
+	return args.Error(0)
+}
+
+func (m *MockClientUseCaseForFactory) SaveItem(itemType string, label string, data domain.ItemData, metadata string) error {
+	args := m.Called(itemType, label, data, metadata)
+	return args.Error(0)
+}
+
+func (m *MockClientUseCaseForFactory) GetItem(itemType string, label string) (domain.ItemData, string, error) {
+	args := m.Called(itemType, label)
+	return args.Get(0).(domain.ItemData), args.String(1), args.Error(2)
+}
+
+func (m *MockClientUseCaseForFactory) DeleteItem(itemType string, label string) error {
+	args := m.Called(itemType, label)
+	return args.Error(0)
+}
+
+func (m *MockClientUseCaseForFactory) ItemType(itemType string) (*itemtype.Type, error) {
+	return itemtype.Lookup(itemType)
+}
+
+func (m *MockClientUseCaseForFactory) SaveItemTemplate(name string, schema json.RawMessage) error {
+	args := m.Called(name, schema)
+	return args.Error(0)
+}
+
+func (m *MockClientUseCaseForFactory) GetItemTemplate(name string) (*domain.ItemTemplate, error) {
+	args := m.Called(name)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.ItemTemplate), args.Error(1)
+}
+
+func (m *MockClientUseCaseForFactory) ListItemTemplates() ([]domain.ItemTemplate, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.ItemTemplate), args.Error(1)
+}
+
+func (m *MockClientUseCaseForFactory) DeleteItemTemplate(name string) error {
+	args := m.Called(name)
+	return args.Error(0)
+}
+
 func (m *MockClientUseCaseForFactory) SaveCard(label string, cardData *domain.CardData, metadata string) error {
 	args := m.Called(label, cardData, metadata)
 	return args.Error(0)
@@ -96,7 +227,7 @@ func TestNewCommand(t *testing.T) {
 	mockClientUseCase := new(MockClientUseCaseForFactory)
 	
 	// Act
-	cmd := NewCommand(mockClientUseCase)
+	cmd := NewCommand(mockClientUseCase, nil)
 	
 	// Assert
 	assert.NotNil(t, cmd)
@@ -112,7 +243,7 @@ func TestNewCommand(t *testing.T) {
 func TestCommand_ImplementsCommandInterface(t *testing.T) {
 	// Arrange
 	mockClientUseCase := new(MockClientUseCaseForFactory)
-	cmd := NewCommand(mockClientUseCase)
+	cmd := NewCommand(mockClientUseCase, nil)
 	
 	// Act & Assert
 	// Проверяем, что все методы интерфейса Command возвращают не nil
@@ -120,6 +251,7 @@ func TestCommand_ImplementsCommandInterface(t *testing.T) {
 	assert.NotNil(t, cmd.RegisterCmd())
 	assert.NotNil(t, cmd.UploadCmd())
 	assert.NotNil(t, cmd.DownloadCmd())
+	assert.NotNil(t, cmd.SettingsCmd())
 	
 	assert.NotNil(t, cmd.SaveTextCmd())
 	assert.NotNil(t, cmd.GetTextCmd())
@@ -132,4 +264,12 @@ func TestCommand_ImplementsCommandInterface(t *testing.T) {
 	assert.NotNil(t, cmd.SaveCredentialCmd())
 	assert.NotNil(t, cmd.GetCredentialCmd())
 	assert.NotNil(t, cmd.DeleteCredentialCmd())
+	assert.NotNil(t, cmd.FindCmd())
+	assert.NotNil(t, cmd.OTPCmd())
+
+	assert.NotNil(t, cmd.SaveSSHKeyCmd())
+	assert.NotNil(t, cmd.GetSSHKeyCmd())
+	assert.NotNil(t, cmd.DeleteSSHKeyCmd())
+	assert.NotNil(t, cmd.SSHAgentCmd())
+	assert.NotNil(t, cmd.ItemCmd())
 }
\ No newline at end of file
@@ -1,54 +1,240 @@
 package command
 
 import (
+	"errors"
 	"fmt"
+	"github.com/SmirnovND/gophkeeper/internal/domain"
+	"github.com/SmirnovND/gophkeeper/pkg"
 	"github.com/spf13/cobra"
-	"os"
 )
 
 func (c *Command) UploadCmd() *cobra.Command {
-	return &cobra.Command{
-		Use:   "upload",
+	cmd := &cobra.Command{
+		Use:   "upload [file]",
 		Short: "Хранение текстовых/бинарных данных",
-		Run: func(cmd *cobra.Command, args []string) {
-			var filePath, label string
+		Long: "Загрузка файла на сервер. Путь '-' означает чтение содержимого из stdin, например: cat key.bin | passcli upload --file - --label key\n" +
+			"С флагом --dir директория упаковывается в tar-архив и сохраняется как один файл, например: passcli upload --dir ~/.kube --label kube",
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			filePath, _ := cmd.Flags().GetString("file")
+			dirPath, _ := cmd.Flags().GetString("dir")
+			label, _ := cmd.Flags().GetString("label")
+			if len(args) > 0 {
+				if filePath != "" {
+					return fail("Ошибка:", errors.New("путь к файлу задан и аргументом, и флагом --file"))
+				}
+				filePath = args[0]
+			}
+
+			in := newInput()
+
+			if dirPath != "" {
+				if filePath != "" {
+					return fail("Ошибка:", errors.New("флаги --file и --dir нельзя использовать вместе"))
+				}
+
+				label, err := in.require(label, "Введите уникальное название (label) сохраняемого объекта:", "метка задается флагом --label")
+				if err != nil {
+					return fail("Ошибка:", err)
+				}
+
+				resp, err := c.clientUseCase.UploadDir(dirPath, label, promptMetadata(cmd, in))
+				if err != nil {
+					return fail("Ошибка:", err)
+				}
+
+				fmt.Println("Директория успешно загружена:", resp)
+				return nil
+			}
 
-			fmt.Println("Введите путь и имя файла:")
-			fmt.Print("> ")
-			fmt.Fscanln(os.Stdin, &filePath)
+			filePath, err := in.require(filePath, "Введите путь и имя файла:", "путь задается аргументом или флагом --file")
+			if err != nil {
+				return fail("Ошибка:", err)
+			}
 
-			fmt.Println("Введите уникальное название (label) сохраняемого объекта:")
-			fmt.Print("> ")
-			fmt.Fscanln(os.Stdin, &label)
+			// stdin занят содержимым файла, поэтому метка и метаинформация не запрашиваются
+			if filePath == "-" {
+				in.interactive = false
+			}
+			label, err = in.require(label, "Введите уникальное название (label) сохраняемого объекта:", "метка задается флагом --label")
+			if err != nil {
+				return fail("Ошибка:", err)
+			}
 
-			resp, err := c.clientUseCase.Upload(filePath, label)
+			resp, err := c.clientUseCase.Upload(filePath, label, promptMetadata(cmd, in))
 			if err != nil {
-				fmt.Println("Ошибка:", err)
-				return
+				return fail("Ошибка:", err)
 			}
 
 			fmt.Println("Файл успешно загружен:", resp)
+			return nil
 		},
 	}
+
+	cmd.Flags().StringP("file", "f", "", "Путь к файлу, '-' для чтения из stdin")
+	cmd.Flags().StringP("dir", "d", "", "Путь к директории, которая будет загружена одним tar-архивом")
+	cmd.Flags().StringP("label", "l", "", "Уникальное название (label) сохраняемого объекта")
+	cmd.Flags().StringP("metadata", "m", "", "Метаинформация файла")
+
+	return cmd
 }
 
 func (c *Command) DownloadCmd() *cobra.Command {
-	return &cobra.Command{
-		Use:   "download",
+	cmd := &cobra.Command{
+		Use:   "download [label]",
 		Short: "Скачивание файла с сервера",
-		Run: func(cmd *cobra.Command, args []string) {
-			var label string
+		Args:  cobra.MaximumNArgs(1),
+		Long: "Скачивание файла с сервера. С флагом --extract архив директории, загруженный через upload --dir,\n" +
+			"распаковывается в директорию --output (по умолчанию <директория загрузок>/<label>)",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			output, _ := cmd.Flags().GetString("output")
+			extract, _ := cmd.Flags().GetBool("extract")
 
-			fmt.Println("Введите уникальное название (label) файла для скачивания:")
-			fmt.Print("> ")
-			fmt.Fscanln(os.Stdin, &label)
+			label, err := promptLabel(cmd, args, newInput(), "Введите уникальное название (label) файла для скачивания:")
+			if err != nil {
+				return fail("Ошибка при скачивании файла:", err)
+			}
+
+			if extract {
+				err := c.clientUseCase.Extract(label, output)
+				if err != nil {
+					return fail("Ошибка при распаковке архива:", err)
+				}
+				return nil
+			}
 
-			// Передаем пустую строку в качестве пути, чтобы использовать директорию загрузок по умолчанию
-			err := c.clientUseCase.Download(label)
+			// Пустой путь означает директорию загрузок по умолчанию
+			err = c.clientUseCase.Download(label, output)
 			if err != nil {
-				fmt.Println("Ошибка при скачивании файла:", err)
-				return
+				return fail("Ошибка при скачивании файла:", err)
 			}
+			return nil
 		},
 	}
+
+	cmd.Flags().StringP("output", "o", "", "Путь для сохранения файла, '-' для вывода в stdout (по умолчанию директория загрузок)")
+	cmd.Flags().BoolP("extract", "x", false, "Распаковать архив директории в директорию --output")
+	addLabelFlag(cmd)
+
+	return cmd
+}
+
+// FileCmd создает команду управления файлами с подкомандами list, info, rename и delete
+func (c *Command) FileCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "file",
+		Short: "Управление файлами",
+	}
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "list",
+		Short: "Список файлов",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out, err := newPrinter(cmd)
+			if err != nil {
+				return fail("Ошибка при получении списка файлов:", err)
+			}
+			files, err := c.clientUseCase.ListFiles()
+			if err != nil {
+				return fail("Ошибка при получении списка файлов:", err)
+			}
+			if files == nil {
+				files = []domain.FileInfo{}
+			}
+
+			err = out.print(files, func() {
+				if len(files) == 0 {
+					fmt.Println("Файлы не найдены")
+					return
+				}
+
+				fmt.Println("\nФайлы:")
+				fmt.Println("----------------------")
+				for _, file := range files {
+					fmt.Printf("%s\t.%s\t%s\t%s\n", file.Label, file.Extension, pkg.FormatBytes(file.Size), file.UpdatedAt.Format("2006-01-02 15:04"))
+				}
+				fmt.Println("----------------------")
+			})
+			if err != nil {
+				return fail("Ошибка при получении списка файлов:", err)
+			}
+			return nil
+		},
+	})
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "info <label>",
+		Short: "Сведения о файле",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out, err := newPrinter(cmd)
+			if err != nil {
+				return fail("Ошибка при получении сведений о файле:", err)
+			}
+			file, err := c.clientUseCase.FileInfo(args[0])
+			if err != nil {
+				return fail("Ошибка при получении сведений о файле:", err)
+			}
+
+			err = out.print(file, func() {
+				fmt.Println("\nСведения о файле:")
+				fmt.Println("----------------------")
+				fmt.Println("Метка:", file.Label)
+				fmt.Println("Расширение:", file.Extension)
+				fmt.Println("Размер:", pkg.FormatBytes(file.Size))
+				if file.MimeType != "" {
+					fmt.Println("Тип содержимого:", file.MimeType)
+				}
+				if file.OriginalName != "" {
+					fmt.Println("Исходное имя:", file.OriginalName)
+				}
+				if file.Encrypted {
+					fmt.Println("Зашифрован в хранилище: да")
+				}
+				fmt.Println("Создан:", file.CreatedAt.Format("2006-01-02 15:04:05"))
+				fmt.Println("Изменен:", file.UpdatedAt.Format("2006-01-02 15:04:05"))
+				if file.Metadata != "" {
+					fmt.Println("Метаинформация:", file.Metadata)
+				}
+				fmt.Println("----------------------")
+			})
+			if err != nil {
+				return fail("Ошибка при получении сведений о файле:", err)
+			}
+			return nil
+		},
+	})
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "rename <label> <new-label>",
+		Short: "Переименование файла",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			err := c.clientUseCase.RenameFile(args[0], args[1])
+			if err != nil {
+				return fail("Ошибка при переименовании файла:", err)
+			}
+
+			fmt.Printf("Файл '%s' переименован в '%s'\n", args[0], args[1])
+			return nil
+		},
+	})
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "delete <label>",
+		Short: "Удаление файла",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			err := c.clientUseCase.DeleteFile(args[0])
+			if err != nil {
+				return fail("Ошибка при удалении файла:", err)
+			}
+
+			fmt.Printf("Файл '%s' успешно удален\n", args[0])
+			return nil
+		},
+	})
+
+	return cmd
 }
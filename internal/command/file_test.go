@@ -2,9 +2,12 @@ package command
 
 import (
 	"bytes"
+	"encoding/json"
 	"errors"
 	"github.com/SmirnovND/gophkeeper/internal/domain"
+	"github.com/SmirnovND/gophkeeper/internal/itemtype"
 	"io"
+	"net/http"
 	"os"
 	"strings"
 	"testing"
@@ -12,25 +15,104 @@ import (
 
 // MockFileClientUseCase - мок для интерфейса ClientUseCase с методами для работы с файлами
 type MockFileClientUseCase struct {
-	UploadFunc   func(filePath string, label string) (string, error)
-	DownloadFunc func(label string) error
+	UploadFunc     func(filePath string, label string, metadata string) (string, error)
+	DownloadFunc   func(label string, outputPath string) error
+	UploadDirFunc  func(dirPath string, label string, metadata string) (string, error)
+	ExtractFunc    func(label string, destDir string) error
+	UsageFunc      func() (*domain.Usage, error)
+	ListFilesFunc  func() ([]domain.FileInfo, error)
+	FileInfoFunc   func(label string) (*domain.FileInfo, error)
+	RenameFileFunc func(label string, newLabel string) error
+	DeleteFileFunc func(label string) error
+
+	SettingsFunc     func() (*domain.UserSettings, error)
+	SaveSettingsFunc func(settings *domain.UserSettings) error
 }
 
 // Реализация методов интерфейса ClientUseCase для работы с файлами
-func (m *MockFileClientUseCase) Upload(filePath string, label string) (string, error) {
+func (m *MockFileClientUseCase) Upload(filePath string, label string, metadata string) (string, error) {
 	if m.UploadFunc != nil {
-		return m.UploadFunc(filePath, label)
+		return m.UploadFunc(filePath, label, metadata)
 	}
 	return "", nil
 }
 
-func (m *MockFileClientUseCase) Download(label string) error {
+func (m *MockFileClientUseCase) Download(label string, outputPath string) error {
 	if m.DownloadFunc != nil {
-		return m.DownloadFunc(label)
+		return m.DownloadFunc(label, outputPath)
 	}
 	return nil
 }
 
+func (m *MockFileClientUseCase) UploadDir(dirPath string, label string, metadata string) (string, error) {
+	if m.UploadDirFunc != nil {
+		return m.UploadDirFunc(dirPath, label, metadata)
+	}
+	return "", nil
+}
+
+func (m *MockFileClientUseCase) Extract(label string, destDir string) error {
+	if m.ExtractFunc != nil {
+		return m.ExtractFunc(label, destDir)
+	}
+	return nil
+}
+
+func (m *MockFileClientUseCase) ListFiles() ([]domain.FileInfo, error) {
+	if m.ListFilesFunc != nil {
+		return m.ListFilesFunc()
+	}
+	return nil, nil
+}
+
+func (m *MockFileClientUseCase) FileInfo(label string) (*domain.FileInfo, error) {
+	if m.FileInfoFunc != nil {
+		return m.FileInfoFunc(label)
+	}
+	return nil, nil
+}
+
+func (m *MockFileClientUseCase) RenameFile(label string, newLabel string) error {
+	if m.RenameFileFunc != nil {
+		return m.RenameFileFunc(label, newLabel)
+	}
+	return nil
+}
+
+func (m *MockFileClientUseCase) DeleteFile(label string) error {
+	if m.DeleteFileFunc != nil {
+		return m.DeleteFileFunc(label)
+	}
+	return nil
+}
+
+func (m *MockFileClientUseCase) Usage() (*domain.Usage, error) {
+	if m.UsageFunc != nil {
+		return m.UsageFunc()
+	}
+	return nil, nil
+}
+
+func (m *MockFileClientUseCase) Settings() (*domain.UserSettings, error) {
+	if m.SettingsFunc != nil {
+		return m.SettingsFunc()
+	}
+	return nil, nil
+}
+
+func (m *MockFileClientUseCase) SaveSettings(settings *domain.UserSettings) error {
+	if m.SaveSettingsFunc != nil {
+		return m.SaveSettingsFunc(settings)
+	}
+	return nil
+}
+
+func (m *MockFileClientUseCase) ListItems() ([]domain.ItemInfo, error) {
+	return nil, nil
+}
+
+func (m *MockFileClientUseCase) SetProgress(progress domain.ProgressFunc) {}
+
 // Реализация остальных методов интерфейса ClientUseCase, которые не используются в тестах
 func (m *MockFileClientUseCase) Login(username string, password string) error {
 	return nil
@@ -52,6 +134,50 @@ func (m *MockFileClientUseCase) DeleteText(label string) error {
 	return nil
 }
 
+func (m *MockFileClientUseCase) SaveSSHKey(label string, sshKeyData *domain.SSHKeyData, metadata string) error {
+	return nil
+}
+
+func (m *MockFileClientUseCase) GetSSHKey(label string) (*domain.SSHKeyData, string, error) {
+	return nil, "", nil
+}
+
+func (m *MockFileClientUseCase) DeleteSSHKey(label string) error {
+	return nil
+}
+
+func (m *MockFileClientUseCase) SaveItem(itemType string, label string, data domain.ItemData, metadata string) error {
+	return nil
+}
+
+func (m *MockFileClientUseCase) GetItem(itemType string, label string) (domain.ItemData, string, error) {
+	return nil, "", nil
+}
+
+func (m *MockFileClientUseCase) DeleteItem(itemType string, label string) error {
+	return nil
+}
+
+func (m *MockFileClientUseCase) ItemType(itemType string) (*itemtype.Type, error) {
+	return itemtype.Lookup(itemType)
+}
+
+func (m *MockFileClientUseCase) SaveItemTemplate(name string, schema json.RawMessage) error {
+	return nil
+}
+
+func (m *MockFileClientUseCase) GetItemTemplate(name string) (*domain.ItemTemplate, error) {
+	return nil, &domain.Error{Message: "шаблон не найден", CodeValue: http.StatusNotFound}
+}
+
+func (m *MockFileClientUseCase) ListItemTemplates() ([]domain.ItemTemplate, error) {
+	return []domain.ItemTemplate{}, nil
+}
+
+func (m *MockFileClientUseCase) DeleteItemTemplate(name string) error {
+	return nil
+}
+
 func (m *MockFileClientUseCase) SaveCard(label string, cardData *domain.CardData, metadata string) error {
 	return nil
 }
@@ -102,7 +228,7 @@ func TestCommand_UploadCmd_Success(t *testing.T) {
 
 	// Создаем мок для ClientUseCase
 	mockClientUseCase := &MockFileClientUseCase{
-		UploadFunc: func(filePath string, label string) (string, error) {
+		UploadFunc: func(filePath string, label string, metadata string) (string, error) {
 			// Проверяем параметры
 			if filePath != "/path/to/file.txt" {
 				t.Errorf("Ожидался путь к файлу '/path/to/file.txt', получен '%s'", filePath)
@@ -123,7 +249,7 @@ func TestCommand_UploadCmd_Success(t *testing.T) {
 	uploadCmd := cmd.UploadCmd()
 
 	// Выполняем команду
-	uploadCmd.Run(uploadCmd, []string{})
+	uploadCmd.RunE(uploadCmd, []string{})
 
 	// Закрываем pipe для записи и копируем вывод в буфер
 	w2.Close()
@@ -162,10 +288,13 @@ func TestCommand_UploadCmd_Error(t *testing.T) {
 	defer func() { os.Stdout = oldStdout }()
 	r2, w2, _ := os.Pipe()
 	os.Stdout = w2
+	oldStderr := os.Stderr
+	defer func() { os.Stderr = oldStderr }()
+	os.Stderr = w2
 
 	// Создаем мок для ClientUseCase
 	mockClientUseCase := &MockFileClientUseCase{
-		UploadFunc: func(filePath string, label string) (string, error) {
+		UploadFunc: func(filePath string, label string, metadata string) (string, error) {
 			return "", errors.New("ошибка при загрузке файла")
 		},
 	}
@@ -179,7 +308,7 @@ func TestCommand_UploadCmd_Error(t *testing.T) {
 	uploadCmd := cmd.UploadCmd()
 
 	// Выполняем команду
-	uploadCmd.Run(uploadCmd, []string{})
+	uploadCmd.RunE(uploadCmd, []string{})
 
 	// Закрываем pipe для записи и копируем вывод в буфер
 	w2.Close()
@@ -215,10 +344,13 @@ func TestCommand_DownloadCmd_Success(t *testing.T) {
 	defer func() { os.Stdout = oldStdout }()
 	r2, w2, _ := os.Pipe()
 	os.Stdout = w2
+	oldStderr := os.Stderr
+	defer func() { os.Stderr = oldStderr }()
+	os.Stderr = w2
 
 	// Создаем мок для ClientUseCase
 	mockClientUseCase := &MockFileClientUseCase{
-		DownloadFunc: func(label string) error {
+		DownloadFunc: func(label string, outputPath string) error {
 			// Проверяем параметры
 			if label != "test_label" {
 				t.Errorf("Ожидалась метка 'test_label', получена '%s'", label)
@@ -236,7 +368,7 @@ func TestCommand_DownloadCmd_Success(t *testing.T) {
 	downloadCmd := cmd.DownloadCmd()
 
 	// Выполняем команду
-	downloadCmd.Run(downloadCmd, []string{})
+	downloadCmd.RunE(downloadCmd, []string{})
 
 	// Закрываем pipe для записи и копируем вывод в буфер
 	w2.Close()
@@ -273,10 +405,13 @@ func TestCommand_DownloadCmd_Error(t *testing.T) {
 	defer func() { os.Stdout = oldStdout }()
 	r2, w2, _ := os.Pipe()
 	os.Stdout = w2
+	oldStderr := os.Stderr
+	defer func() { os.Stderr = oldStderr }()
+	os.Stderr = w2
 
 	// Создаем мок для ClientUseCase
 	mockClientUseCase := &MockFileClientUseCase{
-		DownloadFunc: func(label string) error {
+		DownloadFunc: func(label string, outputPath string) error {
 			return errors.New("ошибка при скачивании файла")
 		},
 	}
@@ -290,7 +425,7 @@ func TestCommand_DownloadCmd_Error(t *testing.T) {
 	downloadCmd := cmd.DownloadCmd()
 
 	// Выполняем команду
-	downloadCmd.Run(downloadCmd, []string{})
+	downloadCmd.RunE(downloadCmd, []string{})
 
 	// Закрываем pipe для записи и копируем вывод в буфер
 	w2.Close()
@@ -301,4 +436,195 @@ func TestCommand_DownloadCmd_Error(t *testing.T) {
 	if !strings.Contains(output, "Ошибка при скачивании файла:") {
 		t.Errorf("Ожидалось сообщение об ошибке при скачивании файла, получено: %s", output)
 	}
-}
\ No newline at end of file
+}
+// TestCommand_DownloadCmd_OutputFlag тестирует скачивание по метке из аргумента с флагом --output
+func TestCommand_DownloadCmd_OutputFlag(t *testing.T) {
+	mockClientUseCase := &MockFileClientUseCase{
+		DownloadFunc: func(label string, outputPath string) error {
+			if label != "test_label" {
+				t.Errorf("Ожидалась метка 'test_label', получена '%s'", label)
+			}
+			if outputPath != "-" {
+				t.Errorf("Ожидался путь '-', получен '%s'", outputPath)
+			}
+			return nil
+		},
+	}
+
+	cmd := &Command{
+		clientUseCase: mockClientUseCase,
+	}
+
+	downloadCmd := cmd.DownloadCmd()
+	downloadCmd.SetArgs([]string{"test_label", "--output", "-"})
+	if err := downloadCmd.Execute(); err != nil {
+		t.Fatalf("Неожиданная ошибка: %v", err)
+	}
+}
+
+// TestCommand_UploadCmd_Flags тестирует загрузку с путем и меткой из флагов
+func TestCommand_UploadCmd_Flags(t *testing.T) {
+	mockClientUseCase := &MockFileClientUseCase{
+		UploadFunc: func(filePath string, label string, metadata string) (string, error) {
+			if filePath != "-" || label != "piped" {
+				t.Errorf("Ожидались путь '-' и метка 'piped', получены '%s' и '%s'", filePath, label)
+			}
+			return "ok", nil
+		},
+	}
+
+	cmd := &Command{
+		clientUseCase: mockClientUseCase,
+	}
+
+	uploadCmd := cmd.UploadCmd()
+	uploadCmd.SetArgs([]string{"--file", "-", "--label", "piped"})
+	if err := uploadCmd.Execute(); err != nil {
+		t.Fatalf("Неожиданная ошибка: %v", err)
+	}
+}
+
+// TestCommand_UploadCmd_Dir тестирует загрузку директории
+func TestCommand_UploadCmd_Dir(t *testing.T) {
+	mockClientUseCase := &MockFileClientUseCase{
+		UploadFunc: func(filePath string, label string, metadata string) (string, error) {
+			t.Error("Для директории не должен вызываться Upload")
+			return "", nil
+		},
+		UploadDirFunc: func(dirPath string, label string, metadata string) (string, error) {
+			if dirPath != "/tmp/certs" || label != "certs" {
+				t.Errorf("Ожидались путь '/tmp/certs' и метка 'certs', получены '%s' и '%s'", dirPath, label)
+			}
+			return "ok", nil
+		},
+	}
+
+	cmd := &Command{
+		clientUseCase: mockClientUseCase,
+	}
+
+	uploadCmd := cmd.UploadCmd()
+	uploadCmd.SetArgs([]string{"--dir", "/tmp/certs", "--label", "certs"})
+	if err := uploadCmd.Execute(); err != nil {
+		t.Fatalf("Неожиданная ошибка: %v", err)
+	}
+}
+
+// TestCommand_DownloadCmd_Extract тестирует распаковку архива директории
+func TestCommand_DownloadCmd_Extract(t *testing.T) {
+	mockClientUseCase := &MockFileClientUseCase{
+		DownloadFunc: func(label string, outputPath string) error {
+			t.Error("При распаковке не должен вызываться Download")
+			return nil
+		},
+		ExtractFunc: func(label string, destDir string) error {
+			if label != "certs" || destDir != "/tmp/out" {
+				t.Errorf("Ожидались метка 'certs' и путь '/tmp/out', получены '%s' и '%s'", label, destDir)
+			}
+			return nil
+		},
+	}
+
+	cmd := &Command{
+		clientUseCase: mockClientUseCase,
+	}
+
+	downloadCmd := cmd.DownloadCmd()
+	downloadCmd.SetArgs([]string{"certs", "--extract", "--output", "/tmp/out"})
+	if err := downloadCmd.Execute(); err != nil {
+		t.Fatalf("Неожиданная ошибка: %v", err)
+	}
+}
+
+// TestCommand_FileCmd тестирует подкоманды управления файлами
+func TestCommand_FileCmd(t *testing.T) {
+	var renamed, deleted string
+
+	mockClientUseCase := &MockFileClientUseCase{
+		ListFilesFunc: func() ([]domain.FileInfo, error) {
+			return []domain.FileInfo{{Label: "report", Extension: "txt", Size: 2048}}, nil
+		},
+		FileInfoFunc: func(label string) (*domain.FileInfo, error) {
+			return &domain.FileInfo{Label: label, Extension: "txt", Size: 10, Metadata: "заметка"}, nil
+		},
+		RenameFileFunc: func(label string, newLabel string) error {
+			renamed = label + "->" + newLabel
+			return nil
+		},
+		DeleteFileFunc: func(label string) error {
+			deleted = label
+			return nil
+		},
+	}
+
+	cmd := &Command{
+		clientUseCase: mockClientUseCase,
+	}
+
+	run := func(args ...string) string {
+		var buf bytes.Buffer
+		oldStdout := os.Stdout
+		r, w, _ := os.Pipe()
+		os.Stdout = w
+
+		fileCmd := cmd.FileCmd()
+		fileCmd.SetArgs(args)
+		err := fileCmd.Execute()
+
+		w.Close()
+		os.Stdout = oldStdout
+		io.Copy(&buf, r)
+		if err != nil {
+			t.Fatalf("Неожиданная ошибка для %v: %v", args, err)
+		}
+		return buf.String()
+	}
+
+	if output := run("list"); !strings.Contains(output, "report") || !strings.Contains(output, "2.0 КБ") {
+		t.Errorf("Неожиданный вывод list: %s", output)
+	}
+	if output := run("info", "report"); !strings.Contains(output, "Метаинформация: заметка") {
+		t.Errorf("Неожиданный вывод info: %s", output)
+	}
+	run("rename", "old", "new")
+	if renamed != "old->new" {
+		t.Errorf("Ожидалось переименование 'old->new', получено '%s'", renamed)
+	}
+	run("delete", "report")
+	if deleted != "report" {
+		t.Errorf("Ожидалось удаление 'report', получено '%s'", deleted)
+	}
+}
+
+// TestCommand_FileCmd_Error тестирует вывод ошибки подкоманды удаления
+func TestCommand_FileCmd_Error(t *testing.T) {
+	var buf bytes.Buffer
+	oldStdout := os.Stdout
+	defer func() { os.Stdout = oldStdout }()
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+	oldStderr := os.Stderr
+	defer func() { os.Stderr = oldStderr }()
+	os.Stderr = w
+
+	mockClientUseCase := &MockFileClientUseCase{
+		DeleteFileFunc: func(label string) error {
+			return errors.New("файл не найден")
+		},
+	}
+
+	cmd := &Command{
+		clientUseCase: mockClientUseCase,
+	}
+
+	fileCmd := cmd.FileCmd()
+	fileCmd.SetArgs([]string{"delete", "missing"})
+	fileCmd.Execute()
+
+	w.Close()
+	io.Copy(&buf, r)
+
+	if !strings.Contains(buf.String(), "Ошибка при удалении файла: файл не найден") {
+		t.Errorf("Ожидалось сообщение об ошибке, получено: %s", buf.String())
+	}
+}
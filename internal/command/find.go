@@ -0,0 +1,124 @@
+package command
+
+import (
+	"fmt"
+	"github.com/SmirnovND/gophkeeper/internal/domain"
+	"github.com/SmirnovND/gophkeeper/internal/urimatch"
+	"github.com/spf13/cobra"
+	"net/http"
+)
+
+// credentialMatch - учетные данные, адрес которых подошел к адресу страницы
+type credentialMatch struct {
+	Label string `json:"label"`
+	Login string `json:"login"`
+	URI   string `json:"uri"`
+	Match string `json:"match"`
+}
+
+// FindCmd создает команду поиска учетных данных по адресу страницы
+func (c *Command) FindCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "find",
+		Short: "Поиск учетных данных по адресу сайта",
+		Long: "Выводит учетные данные, один из адресов которых подходит к адресу --url по своему правилу сравнения:\n" +
+			"domain (по умолчанию) - тот же домен или поддомен, host - тот же хост и порт, starts_with - адрес\n" +
+			"начинается с адреса записи, exact - полное совпадение, regex - регулярное выражение, never - не сравнивается.\n" +
+			"Если подходящих записей нет, команда завершается с кодом 3, например:\n" +
+			"passcli find --url https://gist.github.com/alice --field label",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out, err := newPrinter(cmd)
+			if err != nil {
+				return fail("Ошибка при поиске учетных данных:", err)
+			}
+			target, _ := cmd.Flags().GetString("url")
+			if target == "" {
+				return fail("Ошибка при поиске учетных данных:", fmt.Errorf("%w: адрес задается флагом --url", errUsage))
+			}
+
+			matches, err := c.findCredentials(target)
+			if err != nil {
+				return fail("Ошибка при поиске учетных данных:", err)
+			}
+			if len(matches) == 0 {
+				return fail("Ошибка при поиске учетных данных:",
+					&domain.Error{Message: fmt.Sprintf("учетные данные для %s не найдены", target), CodeValue: http.StatusNotFound})
+			}
+
+			err = out.print(matches, func() {
+				fmt.Println("\nНайденные учетные данные:")
+				fmt.Println("----------------------")
+				for _, match := range matches {
+					fmt.Printf("%s\t%s\t%s\n", match.Label, match.Login, describeURI(domain.CredentialURI{URI: match.URI, Match: match.Match}))
+				}
+				fmt.Println("----------------------")
+			})
+			if err != nil {
+				return fail("Ошибка при поиске учетных данных:", err)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().String("url", "", "Адрес страницы, например https://github.com/login")
+
+	return cmd
+}
+
+// findCredentials возвращает учетные данные с адресом, подходящим к target, в порядке списка записей.
+// Для каждой записи выводится первый подходящий адрес
+func (c *Command) findCredentials(target string) ([]credentialMatch, error) {
+	items, err := c.clientUseCase.ListItems()
+	if err != nil {
+		return nil, err
+	}
+
+	found := matchCredentials(items, func(uri domain.CredentialURI) bool {
+		return urimatch.Match(uri, target)
+	})
+	matches := make([]credentialMatch, 0, len(found))
+	for _, match := range found {
+		data, _, err := c.clientUseCase.GetCredential(match.info.Label)
+		if err != nil {
+			return nil, err
+		}
+		matches = append(matches, credentialMatch{Label: match.info.Label, Login: data.Login, URI: match.uri.URI, Match: match.uri.Match})
+	}
+	return matches, nil
+}
+
+// uriMatch - запись учетных данных и ее первый адрес, подошедший к адресу страницы
+type uriMatch struct {
+	info *domain.ItemInfo
+	uri  domain.CredentialURI
+}
+
+// matchCredentials возвращает учетные данные из items, один из адресов которых подходит по match,
+// в порядке items. Адреса берутся из списка записей, сами записи не запрашиваются
+func matchCredentials(items []domain.ItemInfo, match func(uri domain.CredentialURI) bool) []uriMatch {
+	var matches []uriMatch
+	for i := range items {
+		if items[i].Type != domain.UserDataTypeCredential {
+			continue
+		}
+		for _, uri := range items[i].URIs {
+			if match(uri) {
+				matches = append(matches, uriMatch{info: &items[i], uri: uri})
+				break
+			}
+		}
+	}
+	return matches
+}
+
+// hasURI сообщает, есть ли у учетных данных info адрес uri с тем же правилом сравнения. Так помощники узнают
+// записи, которым адрес записали сами: совпадение по правилу, например по домену, запись своей не делает
+func hasURI(info *domain.ItemInfo, uri domain.CredentialURI) bool {
+	for _, own := range info.URIs {
+		if own.URI == uri.URI && own.Match == uri.Match {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,85 @@
+package command
+
+import (
+	"encoding/json"
+	"github.com/SmirnovND/gophkeeper/internal/domain"
+	"reflect"
+	"testing"
+)
+
+// TestCommand_SaveCredentialCmd_URIs тестирует сохранение адресов и дополнительных полей из флагов и их вывод
+func TestCommand_SaveCredentialCmd_URIs(t *testing.T) {
+	vault := newFakeVault()
+
+	withStdin(t, "s3cret\n", false)
+	_, err := runWithOutput(t, vault.command().SaveCredentialCmd(), "gitlab", "--login", "alice", "--password-stdin",
+		"--uri", "gitlab.com", "--uri", "host=https://git.corp:8443", "--uri", "https://example.com/?a=b",
+		"--custom", "workspace=core", "--custom-bool", "sso=true")
+	if err != nil {
+		t.Fatalf("Неожиданная ошибка: %v", err)
+	}
+	got := vault.credentials["gitlab"]
+	wantURIs := []domain.CredentialURI{{URI: "gitlab.com"}, {URI: "https://git.corp:8443", Match: "host"}, {URI: "https://example.com/?a=b"}}
+	wantFields := []domain.CustomField{{Name: "workspace", Type: domain.CustomFieldText, Value: "core"}, {Name: "sso", Type: domain.CustomFieldBoolean, Value: "true"}}
+	if got == nil || !reflect.DeepEqual(got.URIs, wantURIs) || !reflect.DeepEqual(got.Fields, wantFields) {
+		t.Fatalf("Учетные данные сохранены неверно: %+v", got)
+	}
+
+	out, err := runWithOutput(t, vault.command().GetCredentialCmd(), "gitlab", "--field", "fields.workspace")
+	if err != nil || out != "core" {
+		t.Errorf("Ожидалось значение дополнительного поля, получено %q, %v", out, err)
+	}
+	out, err = runWithOutput(t, vault.command().GetCredentialCmd(), "gitlab", "--field", "uris.1.match")
+	if err != nil || out != "host" {
+		t.Errorf("Ожидалось правило второго адреса, получено %q, %v", out, err)
+	}
+
+	for _, args := range [][]string{
+		{"--uri", "wildcard=github.com"},
+		{"--uri", "regex=^https://("},
+		{"--custom", "workspace"},
+		{"--custom-bool", "sso=yes"},
+		{"--from-file", "-"},
+	} {
+		withStdin(t, "s3cret\n", false)
+		args = append([]string{"other", "--login", "alice", "--password-stdin"}, args...)
+		if _, err := runWithOutput(t, vault.command().SaveCredentialCmd(), args...); ExitCode(err) != ExitUsage {
+			t.Errorf("Для %v ожидалась ошибка аргументов, получено %v", args, err)
+		}
+	}
+}
+
+// TestCommand_FindCmd тестирует поиск учетных данных по адресу страницы с учетом правил сравнения
+func TestCommand_FindCmd(t *testing.T) {
+	vault := newFakeVault()
+	vault.command().clientUseCase.SaveCredential("github", &domain.CredentialData{Login: "alice", Password: "x",
+		URIs: []domain.CredentialURI{{URI: "https://github.com"}}}, "")
+	vault.command().clientUseCase.SaveCredential("corp-git", &domain.CredentialData{Login: "bob", Password: "x",
+		URIs: []domain.CredentialURI{{URI: "https://docs.corp", Match: "exact"}, {URI: "https://git.corp:8443", Match: "host"}}}, "")
+	vault.command().clientUseCase.SaveCredential("plain", &domain.CredentialData{Login: "carol", Password: "x"}, "")
+
+	out, err := runWithOutput(t, vault.command().FindCmd(), "--url", "https://gist.github.com/alice", "--field", "login")
+	if err != nil || out != "alice\n" {
+		t.Errorf("Ожидался логин alice, получено %q, %v", out, err)
+	}
+
+	out, err = runWithOutput(t, vault.command().FindCmd(), "--url", "https://git.corp:8443/repo", "-o", "json")
+	if err != nil {
+		t.Fatalf("Неожиданная ошибка: %v", err)
+	}
+	var matches []credentialMatch
+	if err := json.Unmarshal([]byte(out), &matches); err != nil {
+		t.Fatalf("Неверный вывод json: %q, %v", out, err)
+	}
+	want := []credentialMatch{{Label: "corp-git", Login: "bob", URI: "https://git.corp:8443", Match: "host"}}
+	if !reflect.DeepEqual(matches, want) {
+		t.Errorf("Ожидалось %+v, получено %+v", want, matches)
+	}
+
+	if _, err := runWithOutput(t, vault.command().FindCmd(), "--url", "https://git.corp/repo"); ExitCode(err) != ExitNotFound {
+		t.Errorf("Ожидалась ошибка отсутствия учетных данных, получено %v", err)
+	}
+	if _, err := runWithOutput(t, vault.command().FindCmd()); ExitCode(err) != ExitUsage {
+		t.Errorf("Без --url ожидалась ошибка аргументов, получено %v", err)
+	}
+}
@@ -0,0 +1,138 @@
+package command
+
+import (
+	"fmt"
+	"github.com/SmirnovND/gophkeeper/internal/generator"
+	"github.com/spf13/cobra"
+	"math"
+	"os"
+)
+
+// Параметры генерации по умолчанию
+const (
+	defaultPasswordLength  = 20
+	defaultPassphraseWords = 6
+)
+
+// generatedItem - схема сгенерированного значения в форматах json, yaml и env
+type generatedItem struct {
+	Type        string  `json:"type"`
+	Value       string  `json:"value"`
+	EntropyBits float64 `json:"entropy_bits"`
+	Strength    string  `json:"strength"`
+}
+
+// addGenerateFlags добавляет флаги политики генерации пароля и парольной фразы
+func addGenerateFlags(cmd *cobra.Command) {
+	cmd.Flags().IntP("length", "n", defaultPasswordLength, "Длина пароля")
+	cmd.Flags().Bool("lower", true, "Использовать строчные буквы")
+	cmd.Flags().Bool("upper", true, "Использовать заглавные буквы")
+	cmd.Flags().Bool("digits", true, "Использовать цифры")
+	cmd.Flags().Bool("symbols", true, "Использовать символы")
+	cmd.Flags().Int("min-lower", 1, "Минимальное количество строчных букв")
+	cmd.Flags().Int("min-upper", 1, "Минимальное количество заглавных букв")
+	cmd.Flags().Int("min-digits", 1, "Минимальное количество цифр")
+	cmd.Flags().Int("min-symbols", 1, "Минимальное количество символов")
+	cmd.Flags().Bool("exclude-ambiguous", false, "Исключить похожие символы: "+generator.Ambiguous)
+	cmd.Flags().String("exclude", "", "Дополнительные символы, которые не должны встречаться в пароле")
+
+	cmd.Flags().Bool("passphrase", false, "Сгенерировать парольную фразу из слов вместо пароля")
+	cmd.Flags().Int("words", defaultPassphraseWords, "Количество слов парольной фразы")
+	cmd.Flags().String("separator", "-", "Разделитель слов парольной фразы")
+	cmd.Flags().Bool("capitalize", false, "Начинать слова парольной фразы с заглавной буквы")
+	cmd.Flags().Bool("digit", false, "Добавить цифру к случайному слову парольной фразы")
+}
+
+// generateFromFlags генерирует пароль или парольную фразу по флагам команды
+func generateFromFlags(cmd *cobra.Command) (generator.Result, error) {
+	flags := cmd.Flags()
+
+	var result generator.Result
+	var err error
+	if passphrase, _ := flags.GetBool("passphrase"); passphrase {
+		policy := generator.PassphrasePolicy{}
+		policy.Words, _ = flags.GetInt("words")
+		policy.Separator, _ = flags.GetString("separator")
+		policy.Capitalize, _ = flags.GetBool("capitalize")
+		policy.Digit, _ = flags.GetBool("digit")
+		result, err = generator.Passphrase(policy)
+	} else {
+		policy := generator.Policy{}
+		policy.Length, _ = flags.GetInt("length")
+		policy.ExcludeAmbiguous, _ = flags.GetBool("exclude-ambiguous")
+		policy.Exclude, _ = flags.GetString("exclude")
+		policy.Lower, policy.MinLower = classFlags(cmd, "lower")
+		policy.Upper, policy.MinUpper = classFlags(cmd, "upper")
+		policy.Digits, policy.MinDigits = classFlags(cmd, "digits")
+		policy.Symbols, policy.MinSymbols = classFlags(cmd, "symbols")
+		result, err = generator.Password(policy)
+	}
+
+	if err != nil {
+		return generator.Result{}, fmt.Errorf("%w: %v", errUsage, err)
+	}
+	return result, nil
+}
+
+// classFlags возвращает, включен ли класс символов, и минимальное количество его символов.
+// Для выключенного класса минимум по умолчанию не действует
+func classFlags(cmd *cobra.Command, name string) (bool, int) {
+	enabled, _ := cmd.Flags().GetBool(name)
+	minimum, _ := cmd.Flags().GetInt("min-" + name)
+	if !enabled && !cmd.Flags().Changed("min-"+name) {
+		minimum = 0
+	}
+	return enabled, minimum
+}
+
+// describeEntropy возвращает оценку энтропии для вывода пользователю
+func describeEntropy(result generator.Result) string {
+	return fmt.Sprintf("энтропия ~%.0f бит (%s)", result.Entropy, result.Strength())
+}
+
+// GenerateCmd создает команду генерации пароля или парольной фразы
+func (c *Command) GenerateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "generate",
+		Short: "Генерация пароля или парольной фразы",
+		Long: "Генерирует пароль из выбранных классов символов или парольную фразу из слов списка EFF (--passphrase).\n" +
+			"Значение выводится в stdout, оценка энтропии - в stderr, поэтому пароль можно подставить через $(...).\n" +
+			"Примеры:\n" +
+			"  passcli generate --length 32 --exclude-ambiguous\n" +
+			"  passcli generate --symbols=false --min-digits 4\n" +
+			"  passcli generate --passphrase --words 7 --separator ' ' --capitalize",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out, err := newPrinter(cmd)
+			if err != nil {
+				return fail("Ошибка при генерации:", err)
+			}
+			result, err := generateFromFlags(cmd)
+			if err != nil {
+				return fail("Ошибка при генерации:", err)
+			}
+
+			item := &generatedItem{
+				Type:        "password",
+				Value:       result.Value,
+				EntropyBits: math.Round(result.Entropy*10) / 10,
+				Strength:    result.Strength(),
+			}
+			if passphrase, _ := cmd.Flags().GetBool("passphrase"); passphrase {
+				item.Type = "passphrase"
+			}
+			err = out.print(item, func() {
+				fmt.Println(result.Value)
+				fmt.Fprintln(os.Stderr, "Оценка:", describeEntropy(result))
+			})
+			if err != nil {
+				return fail("Ошибка при генерации:", err)
+			}
+			return nil
+		},
+	}
+
+	addGenerateFlags(cmd)
+
+	return cmd
+}
@@ -0,0 +1,81 @@
+package command
+
+import (
+	"encoding/json"
+	"github.com/SmirnovND/gophkeeper/internal/domain"
+	"strings"
+	"testing"
+)
+
+// TestCommand_GenerateCmd тестирует вывод сгенерированного пароля с оценкой энтропии
+func TestCommand_GenerateCmd(t *testing.T) {
+	cmd := &Command{}
+	out, err := runWithOutput(t, cmd.GenerateCmd(), "--length", "12", "--symbols=false", "--min-digits", "4", "-o", "json")
+	if err != nil {
+		t.Fatalf("Неожиданная ошибка: %v", err)
+	}
+
+	var item generatedItem
+	if err := json.Unmarshal([]byte(out), &item); err != nil {
+		t.Fatalf("Неверный JSON: %v\n%s", err, out)
+	}
+	if item.Type != "password" || len(item.Value) != 12 || item.EntropyBits != 71.5 || item.Strength != "хороший" {
+		t.Errorf("Неожиданный результат: %+v", item)
+	}
+	if strings.ContainsAny(item.Value, "!#$%&*+-") || strings.IndexFunc(item.Value, func(r rune) bool { return r >= '0' && r <= '9' }) < 0 {
+		t.Errorf("Пароль не соответствует политике: '%s'", item.Value)
+	}
+
+	out, err = runWithOutput(t, cmd.GenerateCmd(), "--passphrase", "--words", "4", "--separator", ".", "--field", "value")
+	if err != nil {
+		t.Fatalf("Неожиданная ошибка: %v", err)
+	}
+	if len(strings.Split(strings.TrimSpace(out), ".")) != 4 {
+		t.Errorf("Ожидалась парольная фраза из 4 слов, получено '%s'", out)
+	}
+
+	_, err = runWithOutput(t, cmd.GenerateCmd(), "--length", "2")
+	if ExitCode(err) != ExitUsage {
+		t.Errorf("Ожидался код завершения %d для невыполнимой политики, получен %d", ExitUsage, ExitCode(err))
+	}
+}
+
+// TestCommand_SaveCredentialCmd_Generate тестирует сохранение сгенерированного пароля без его вывода
+func TestCommand_SaveCredentialCmd_Generate(t *testing.T) {
+	withStdin(t, "", false)
+
+	var saved *domain.CredentialData
+	cmd := &Command{clientUseCase: &MockDataClientUseCase{
+		SaveCredentialFunc: func(label string, credentialData *domain.CredentialData, metadata string) error {
+			saved = credentialData
+			return nil
+		},
+	}}
+
+	out, err := runWithOutput(t, cmd.SaveCredentialCmd(), "github", "--login", "alice", "--metadata", "",
+		"--generate", "--length", "24", "--exclude-ambiguous")
+	if err != nil {
+		t.Fatalf("Неожиданная ошибка: %v", err)
+	}
+	if saved == nil || saved.Login != "alice" || len(saved.Password) != 24 || strings.ContainsAny(saved.Password, "0O1lI") {
+		t.Fatalf("Неверные сохраненные данные: %+v", saved)
+	}
+	if strings.Contains(out, saved.Password) || !strings.Contains(out, "энтропия") {
+		t.Errorf("Пароль не должен выводиться без --show, вывод: %s", out)
+	}
+
+	out, err = runWithOutput(t, cmd.SaveCredentialCmd(), "github", "--login", "alice", "--metadata", "",
+		"--generate", "--show")
+	if err != nil {
+		t.Fatalf("Неожиданная ошибка: %v", err)
+	}
+	if !strings.Contains(out, "Пароль: "+saved.Password) {
+		t.Errorf("Ожидался вывод пароля с --show, вывод: %s", out)
+	}
+
+	saved = nil
+	_, err = runWithOutput(t, cmd.SaveCredentialCmd(), "github", "--login", "alice", "--generate", "--password-stdin")
+	if ExitCode(err) != ExitUsage || saved != nil {
+		t.Errorf("Ожидался отказ для --generate с --password-stdin, получена ошибка %v", err)
+	}
+}
@@ -0,0 +1,218 @@
+package command
+
+import (
+	"fmt"
+	"github.com/SmirnovND/gophkeeper/internal/domain"
+	"github.com/SmirnovND/gophkeeper/internal/gitcredential"
+	"github.com/SmirnovND/gophkeeper/internal/urimatch"
+	"github.com/spf13/cobra"
+	"os"
+	"strings"
+)
+
+// GitCredentialCmd создает команду помощника учетных данных git
+func (c *Command) GitCredentialCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "git-credential <get|store|erase>",
+		Short: "Помощник учетных данных git",
+		Long: "Реализует протокол помощников учетных данных git: читает запрос из stdin и выводит ответ в stdout.\n" +
+			"Учетные данные ищутся по адресам записей с их правилами сравнения, затем по метке\n" +
+			"git:<протокол>://[<пользователь>@]<хост>[/<путь>], затем по метке, равной хосту, затем по строке\n" +
+			"\"URL: <адрес>\" в метаинформации. store обновляет только записи помощника: с меткой git: или с тем же\n" +
+			"адресом и правилом host или starts_with; иначе создает запись с меткой git:<протокол>://<хост> и адресом\n" +
+			"репозитория. erase удаляет только записи с меткой git:.\n" +
+			"Подключение: git config --global credential.helper passcli (нужен git-credential-passcli в PATH,\n" +
+			"см. README) или git config --global credential.helper '!passcli git-credential'",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			actions := map[string]func(request *gitcredential.Credential) error{
+				"get":   c.gitCredentialGet,
+				"store": c.gitCredentialStore,
+				"erase": c.gitCredentialErase,
+			}
+			action, ok := actions[args[0]]
+			if !ok {
+				// Неизвестные операции помощник должен пропускать, git может добавить новые
+				return nil
+			}
+
+			request, err := gitcredential.Read(os.Stdin)
+			if err != nil {
+				return fail("Ошибка помощника учетных данных git:", fmt.Errorf("%w: %v", errUsage, err))
+			}
+			if err := action(request); err != nil {
+				return fail("Ошибка помощника учетных данных git:", err)
+			}
+			return nil
+		},
+	}
+
+	return cmd
+}
+
+// gitCredentialGet выводит найденные учетные данные. Если ничего не найдено, ответ пустой и git
+// обращается к следующему помощнику или запрашивает пароль у пользователя
+func (c *Command) gitCredentialGet(request *gitcredential.Credential) error {
+	_, data, err := c.findGitCredential(request)
+	if err != nil || data == nil {
+		return err
+	}
+	response := &gitcredential.Credential{Username: data.Login, Password: data.Password}
+	return response.Write(os.Stdout)
+}
+
+// gitCredentialStore сохраняет учетные данные, которые git успешно использовал. Обновляется только запись,
+// созданная помощником, у нее меняются логин и пароль; записи пользователя, подошедшие к адресу, не меняются,
+// а для запроса создается новая запись
+func (c *Command) gitCredentialStore(request *gitcredential.Credential) error {
+	if request.Password == "" {
+		return nil
+	}
+
+	items, err := c.clientUseCase.ListItems()
+	if err != nil {
+		return err
+	}
+	info, data, err := c.findOwnedGitCredential(request, items)
+	if err != nil {
+		return err
+	}
+	if info != nil {
+		if data.Password == request.Password && (request.Username == "" || data.Login == request.Username) {
+			return nil
+		}
+		updated := *data
+		if request.Username != "" {
+			updated.Login = request.Username
+		}
+		updated.Password = request.Password
+		return c.clientUseCase.SaveCredential(info.Label, &updated, info.Metadata)
+	}
+
+	taken := make(map[string]bool, len(items))
+	for _, item := range items {
+		taken[item.Label] = true
+	}
+	label := request.Label(false)
+	if taken[label] {
+		label = request.Label(true)
+	}
+	if taken[label] {
+		return fmt.Errorf("метка '%s' уже занята другой записью", label)
+	}
+
+	data = &domain.CredentialData{Login: request.Username, Password: request.Password,
+		URIs: []domain.CredentialURI{gitCredentialURI(request)}}
+	return c.clientUseCase.SaveCredential(label, data, "")
+}
+
+// gitCredentialErase удаляет учетные данные, отклоненные сервером. Удаляются только записи, созданные помощником,
+// и только если пароль в хранилище совпадает с отклоненным
+func (c *Command) gitCredentialErase(request *gitcredential.Credential) error {
+	items, err := c.clientUseCase.ListItems()
+	if err != nil {
+		return err
+	}
+	info, data, err := c.findOwnedGitCredential(request, items)
+	if err != nil || info == nil {
+		return err
+	}
+	if request.Password != "" && data.Password != request.Password {
+		return nil
+	}
+	if !strings.HasPrefix(info.Label, gitcredential.LabelPrefix) {
+		fmt.Fprintf(os.Stderr, "Учетные данные '%s' отклонены сервером, запись создана не помощником git и не удалена\n", info.Label)
+		return nil
+	}
+	return c.clientUseCase.DeleteCredential(info.Label)
+}
+
+// gitCredentialURI возвращает адрес, который store записывает в новые учетные данные: с правилом host,
+// а адрес с путем - starts_with, чтобы запись не подходила к другим репозиториям того же хоста
+func gitCredentialURI(request *gitcredential.Credential) domain.CredentialURI {
+	uri := domain.CredentialURI{URI: request.URL(), Match: urimatch.RuleHost}
+	if request.Path != "" {
+		uri.Match = urimatch.RuleStartsWith
+	}
+	return uri
+}
+
+// findGitCredential возвращает первую запись учетных данных, подходящую к запросу: сначала по адресам записей
+// с их правилами сравнения, затем по меткам запроса, затем по адресу в метаинформации. Протокол запроса
+// должен совпадать с протоколом адреса, как при сопоставлении адресов в git: учетные данные для https
+// не передаются по http. Если в запросе указано имя пользователя, логин записи должен совпадать с ним
+func (c *Command) findGitCredential(request *gitcredential.Credential) (*domain.ItemInfo, *domain.CredentialData, error) {
+	items, err := c.clientUseCase.ListItems()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	target := request.URL()
+	matches := matchCredentials(items, func(uri domain.CredentialURI) bool {
+		return urimatch.Match(uri, target) && urimatch.SameScheme(uri, target)
+	})
+	var candidates []*domain.ItemInfo
+	for _, match := range matches {
+		candidates = append(candidates, match.info)
+	}
+	candidates = append(candidates, gitCredentialsByLabel(items, request.Labels())...)
+	for i := range items {
+		if items[i].Type == domain.UserDataTypeCredential && request.MatchesMetadata(items[i].Metadata) {
+			candidates = append(candidates, &items[i])
+		}
+	}
+	return c.firstGitCredential(request, candidates)
+}
+
+// findOwnedGitCredential возвращает запись, созданную помощником для запроса: с меткой помощника
+// или с тем же адресом и правилом, которые записывает store. Записи пользователя, подошедшие к адресу
+// по домену или другому правилу, не возвращаются, чтобы store не перезаписал их пароль
+func (c *Command) findOwnedGitCredential(request *gitcredential.Credential, items []domain.ItemInfo) (*domain.ItemInfo, *domain.CredentialData, error) {
+	candidates := gitCredentialsByLabel(items, request.HelperLabels())
+	uri := gitCredentialURI(request)
+	for i := range items {
+		if items[i].Type == domain.UserDataTypeCredential && hasURI(&items[i], uri) {
+			candidates = append(candidates, &items[i])
+		}
+	}
+	return c.firstGitCredential(request, candidates)
+}
+
+// gitCredentialsByLabel возвращает учетные данные из items с метками labels в порядке labels
+func gitCredentialsByLabel(items []domain.ItemInfo, labels []string) []*domain.ItemInfo {
+	byLabel := make(map[string]*domain.ItemInfo, len(items))
+	for i := range items {
+		if items[i].Type == domain.UserDataTypeCredential {
+			byLabel[items[i].Label] = &items[i]
+		}
+	}
+
+	var found []*domain.ItemInfo
+	for _, label := range labels {
+		if info, ok := byLabel[label]; ok {
+			found = append(found, info)
+		}
+	}
+	return found
+}
+
+// firstGitCredential возвращает первую из записей candidates, логин которой совпадает с именем пользователя
+// запроса. Записи запрашиваются по очереди, каждая не более одного раза
+func (c *Command) firstGitCredential(request *gitcredential.Credential, candidates []*domain.ItemInfo) (*domain.ItemInfo, *domain.CredentialData, error) {
+	checked := make(map[string]bool, len(candidates))
+	for _, info := range candidates {
+		if checked[info.Label] {
+			continue
+		}
+		checked[info.Label] = true
+
+		data, _, err := c.clientUseCase.GetCredential(info.Label)
+		if err != nil {
+			return nil, nil, err
+		}
+		if request.Username == "" || data.Login == request.Username {
+			return info, data, nil
+		}
+	}
+	return nil, nil, nil
+}
@@ -0,0 +1,149 @@
+package command
+
+import (
+	"github.com/SmirnovND/gophkeeper/internal/domain"
+	"reflect"
+	"testing"
+)
+
+// githubTOTP - ключ TOTP записи GitHub, который не должен теряться при обновлении пароля
+const githubTOTP = "otpauth://totp/GitHub:alice?secret=JBSWY3DPEHPK3PXP&issuer=GitHub"
+
+// newGitCredentialVault возвращает хранилище с импортированными учетными данными GitHub и записью помощника
+func newGitCredentialVault() *fakeVault {
+	vault := newFakeVault()
+	save := vault.command().clientUseCase.SaveCredential
+	save("GitHub", &domain.CredentialData{Login: "alice", Password: "ghp_token", TOTP: githubTOTP}, "URL: https://github.com/login\nПапка: Работа")
+	save("git:https://git.example.com", &domain.CredentialData{Login: "bob", Password: "old"}, "URL: https://git.example.com")
+	save("Corp Git", &domain.CredentialData{Login: "erin", Password: "corp-token",
+		URIs: []domain.CredentialURI{{URI: "https://git.corp:8443", Match: "host"}}}, "")
+	return vault
+}
+
+// TestCommand_GitCredentialCmd_Get тестирует поиск учетных данных по адресам записей, метке и адресу в метаинформации
+// и то, что учетные данные для https не выдаются по http
+func TestCommand_GitCredentialCmd_Get(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"по адресу", "protocol=https\nhost=github.com\npath=org/repo.git\n\n", "username=alice\npassword=ghp_token\n"},
+		{"по метке", "protocol=https\nhost=git.example.com\nusername=bob\n", "username=bob\npassword=old\n"},
+		{"другой пользователь", "protocol=https\nhost=github.com\nusername=carol\n", ""},
+		{"неизвестный хост", "protocol=https\nhost=gitlab.com\n", ""},
+		{"по адресу записи", "protocol=https\nhost=git.corp:8443\npath=team/app.git\n", "username=erin\npassword=corp-token\n"},
+		{"адрес записи с другим портом", "protocol=https\nhost=git.corp\n", ""},
+		{"адрес записи без схемы", "protocol=https\nhost=gitea.example\n", "username=gina\npassword=gitea-token\n"},
+		{"адрес записи по http", "protocol=http\nhost=gitea.example\n", ""},
+		{"метка хоста", "protocol=https\nhost=bitbucket.org\n", "username=bert\npassword=bb-token\n"},
+		{"метка хоста по http", "protocol=http\nhost=bitbucket.org\n", ""},
+		{"метаинформация по http", "protocol=http\nhost=git.example.com\nusername=bob\n", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vault := newGitCredentialVault()
+			save := vault.command().clientUseCase.SaveCredential
+			save("Gitea", &domain.CredentialData{Login: "gina", Password: "gitea-token", URIs: []domain.CredentialURI{{URI: "gitea.example"}}}, "")
+			save("bitbucket.org", &domain.CredentialData{Login: "bert", Password: "bb-token"}, "")
+
+			withStdin(t, tt.input, false)
+			out, err := runWithOutput(t, vault.command().GitCredentialCmd(), "get")
+			if err != nil {
+				t.Fatalf("Неожиданная ошибка: %v", err)
+			}
+			if out != tt.want {
+				t.Errorf("Ожидалось %q, получено %q", tt.want, out)
+			}
+		})
+	}
+}
+
+// TestCommand_GitCredentialCmd_GetRequests тестирует, что адреса записей берутся из списка записей
+// и запрашивается только подошедшая запись
+func TestCommand_GitCredentialCmd_GetRequests(t *testing.T) {
+	vault := newGitCredentialVault()
+	cmd := vault.command()
+	mock := cmd.clientUseCase.(*MockDataClientUseCase)
+	var requested []string
+	get := mock.GetCredentialFunc
+	mock.GetCredentialFunc = func(label string) (*domain.CredentialData, string, error) {
+		requested = append(requested, label)
+		return get(label)
+	}
+
+	withStdin(t, "protocol=https\nhost=git.corp:8443\n", false)
+	out, err := runWithOutput(t, cmd.GitCredentialCmd(), "get")
+	if err != nil || out != "username=erin\npassword=corp-token\n" {
+		t.Fatalf("Ожидались учетные данные Corp Git, получено %q, %v", out, err)
+	}
+	if !reflect.DeepEqual(requested, []string{"Corp Git"}) {
+		t.Errorf("Ожидался запрос только записи Corp Git, получено %v", requested)
+	}
+}
+
+// TestCommand_GitCredentialCmd_StoreErase тестирует сохранение новых и обновление существующих учетных данных
+// и изменение только записей помощника
+func TestCommand_GitCredentialCmd_StoreErase(t *testing.T) {
+	vault := newGitCredentialVault()
+	gitlab := &domain.CredentialData{Login: "dave@example.com", Password: "web-pass", URIs: []domain.CredentialURI{{URI: "gitlab.com"}}}
+	vault.command().clientUseCase.SaveCredential("GitLab", gitlab, "")
+	run := func(action string, input string) {
+		t.Helper()
+		withStdin(t, input, false)
+		if _, err := runWithOutput(t, vault.command().GitCredentialCmd(), action); err != nil {
+			t.Fatalf("Неожиданная ошибка %s: %v", action, err)
+		}
+	}
+
+	run("store", "protocol=https\nhost=git.example.com\nusername=bob\npassword=new\n")
+	run("store", "protocol=https\nhost=git.example.com\nusername=carol\npassword=carol-token\n")
+	run("store", "protocol=https\nhost=gitlab.com\nusername=dave\npassword=glpat\n")
+	run("store", "protocol=https\nhost=github.com\nusername=alice\npassword=ghp_new\n")
+
+	want := []string{"Corp Git", "GitHub", "GitLab", "git:https://carol@git.example.com", "git:https://git.example.com",
+		"git:https://github.com", "git:https://gitlab.com"}
+	if got := vault.labels(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("Ожидались записи %v, получено %v", want, got)
+	}
+	if vault.credentials["git:https://git.example.com"].Password != "new" ||
+		!reflect.DeepEqual(vault.credentials["git:https://gitlab.com"].URIs, []domain.CredentialURI{{URI: "https://gitlab.com", Match: "host"}}) ||
+		vault.credentials["git:https://github.com"].Password != "ghp_new" {
+		t.Errorf("Учетные данные сохранены неверно: %+v", vault.items)
+	}
+	// Записи пользователя, подошедшие к адресу по домену или метаинформации, store не меняет
+	if !reflect.DeepEqual(*vault.credentials["GitHub"], domain.CredentialData{Login: "alice", Password: "ghp_token", TOTP: githubTOTP}) ||
+		vault.credentials["GitLab"] != gitlab || gitlab.Password != "web-pass" {
+		t.Errorf("Записи пользователя не должны изменяться: %+v, %+v", vault.credentials["GitHub"], vault.credentials["GitLab"])
+	}
+
+	run("store", "protocol=https\nhost=git.corp:8443\nusername=erin\npassword=corp-new\n")
+	if vault.credentials["Corp Git"].Password != "corp-new" || vault.items["git:https://git.corp:8443"].Label != "" {
+		t.Errorf("Ожидалось обновление записи с адресом помощника, получено %+v", vault.items)
+	}
+
+	run("erase", "protocol=https\nhost=gitlab.com\nusername=dave\npassword=glpat\n")
+	run("erase", "protocol=https\nhost=github.com\nusername=alice\npassword=ghp_token\n")
+	run("erase", "protocol=https\nhost=git.example.com\nusername=bob\npassword=outdated\n")
+	want = []string{"Corp Git", "GitHub", "GitLab", "git:https://carol@git.example.com", "git:https://git.example.com", "git:https://github.com"}
+	if got := vault.labels(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Ожидались записи %v, получено %v", want, got)
+	}
+}
+
+// TestHelperArgs тестирует выбор команды по имени исполняемого файла
+func TestHelperArgs(t *testing.T) {
+	if got := HelperArgs([]string{"/usr/local/bin/git-credential-passcli", "get"}); !reflect.DeepEqual(got, []string{"git-credential", "get"}) {
+		t.Errorf("Неверные аргументы помощника git: %v", got)
+	}
+	if got := HelperArgs([]string{"git-credential-passcli.exe", "store"}); got == nil {
+		t.Error("Ожидались аргументы помощника для имени с расширением .exe")
+	}
+	if got := HelperArgs([]string{"docker-credential-passcli", "list"}); !reflect.DeepEqual(got, []string{"docker-credential", "list"}) {
+		t.Errorf("Неверные аргументы помощника docker: %v", got)
+	}
+	if got := HelperArgs([]string{"passcli", "get-text"}); got != nil {
+		t.Errorf("Для passcli аргументы не должны изменяться, получено %v", got)
+	}
+}
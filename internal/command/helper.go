@@ -0,0 +1,26 @@
+package command
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// helperCommands - имена исполняемого файла, под которыми внешние программы запускают passcli как помощник,
+// и соответствующие команды passcli
+var helperCommands = map[string]string{
+	"git-credential-passcli":    "git-credential",
+	"docker-credential-passcli": "docker-credential",
+}
+
+// HelperArgs возвращает аргументы команды passcli, если исполняемый файл запущен под именем помощника,
+// например git-credential-passcli get -> git-credential get. Иначе возвращает nil
+func HelperArgs(args []string) []string {
+	if len(args) == 0 {
+		return nil
+	}
+	name := strings.TrimSuffix(filepath.Base(args[0]), ".exe")
+	if command, ok := helperCommands[name]; ok {
+		return append([]string{command}, args[1:]...)
+	}
+	return nil
+}
@@ -0,0 +1,207 @@
+package command
+
+import (
+	"fmt"
+	"github.com/SmirnovND/gophkeeper/internal/domain"
+	"github.com/SmirnovND/gophkeeper/internal/importer"
+	"github.com/spf13/cobra"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"text/tabwriter"
+)
+
+// defaultImportBatch - количество записей, сохраняемых одновременно
+const defaultImportBatch = 20
+
+// importStep - схема строки отчета импорта в форматах json, yaml и env
+type importStep struct {
+	Action string `json:"action"`
+	Type   string `json:"type"`
+	Label  string `json:"label"`
+	Source string `json:"source"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// importActionTitles - названия действий импорта в отчете
+var importActionTitles = map[string]string{
+	importer.ActionCreate:    "создать",
+	importer.ActionRename:    "переименовать",
+	importer.ActionOverwrite: "перезаписать",
+	importer.ActionSkip:      "пропустить",
+}
+
+// ImportCmd создает команду импорта записей из экспорта другого менеджера паролей
+func (c *Command) ImportCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "import <file>",
+		Short: "Импорт записей из другого менеджера паролей",
+		Long: "Импортирует записи из JSON-экспорта Bitwarden, CSV-экспортов 1Password, LastPass, Chrome, Firefox\n" +
+			"и CSV Bitwarden, а также XML-экспорта KeePass 2.x. '-' читает экспорт из stdin.\n" +
+			"Логины становятся учетными данными, карты - банковскими картами, заметки - текстом;\n" +
+			"адрес сайта, папка, дополнительные поля и заметки переносятся в метаинформацию.\n" +
+			"Без --apply команда только выводит отчет о том, что будет сделано.\n" +
+			"Совпадения меток с существующими записями разрешаются флагом --on-conflict:\n" +
+			"skip - пропустить, rename - сохранить под меткой \"label (2)\", overwrite - перезаписать",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out, err := newPrinter(cmd)
+			if err != nil {
+				return fail("Ошибка при импорте:", err)
+			}
+			format, _ := cmd.Flags().GetString("format")
+			conflict, _ := cmd.Flags().GetString("on-conflict")
+			apply, _ := cmd.Flags().GetBool("apply")
+			batch, _ := cmd.Flags().GetInt("batch-size")
+			if batch <= 0 {
+				return fail("Ошибка при импорте:", fmt.Errorf("%w: --batch-size должен быть положительным", errUsage))
+			}
+
+			result, err := parseExport(args[0], format)
+			if err != nil {
+				return fail("Ошибка при чтении экспорта:", err)
+			}
+			existing, err := c.clientUseCase.ListItems()
+			if err != nil {
+				return fail("Ошибка при получении списка записей:", err)
+			}
+			steps, err := importer.Plan(result.Entries, existing, conflict)
+			if err != nil {
+				return fail("Ошибка при импорте:", fmt.Errorf("%w: %v", errUsage, err))
+			}
+
+			if err := printImportReport(out, steps); err != nil {
+				return fail("Ошибка при импорте:", err)
+			}
+			for _, warning := range result.Warnings {
+				fmt.Fprintln(os.Stderr, "Предупреждение:", warning)
+			}
+
+			if !apply {
+				fmt.Fprintln(os.Stderr, "Пробный запуск: записи не сохранены. Для импорта повторите команду с флагом --apply")
+				return nil
+			}
+
+			imported, failed := c.applyImport(steps, batch)
+			fmt.Fprintf(os.Stderr, "Импортировано записей: %d, с ошибкой: %d\n", imported, failed)
+			if failed > 0 {
+				return fail("Ошибка при импорте:", fmt.Errorf("не удалось импортировать %d записей", failed))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().String("format", importer.Auto, "Формат экспорта: "+strings.Join(importer.Formats(), ", "))
+	cmd.Flags().String("on-conflict", importer.ConflictSkip, "Действие при совпадении метки: skip, rename или overwrite")
+	cmd.Flags().Bool("apply", false, "Сохранить записи; без флага выводится только отчет")
+	cmd.Flags().Int("batch-size", defaultImportBatch, "Количество записей, сохраняемых одновременно")
+
+	return cmd
+}
+
+// parseExport читает экспорт из файла path или из stdin
+func parseExport(path string, format string) (*importer.Result, error) {
+	var reader io.Reader = os.Stdin
+	if path != "-" {
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+		reader = file
+	}
+
+	result, err := importer.Parse(reader, format, path)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", errUsage, err)
+	}
+	return result, nil
+}
+
+// printImportReport выводит, что будет сделано с каждой записью экспорта, и итог по действиям
+func printImportReport(out *printer, steps []importer.Step) error {
+	report := make([]importStep, 0, len(steps))
+	counts := make(map[string]int)
+	for _, step := range steps {
+		report = append(report, importStep{
+			Action: step.Action,
+			Type:   step.Entry.Type,
+			Label:  step.Label,
+			Source: step.Entry.Source,
+			Reason: step.Reason,
+		})
+		counts[step.Action]++
+	}
+
+	return out.print(report, func() {
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "ДЕЙСТВИЕ\tТИП\tМЕТКА\tИСТОЧНИК")
+		for _, step := range report {
+			source := step.Source
+			if step.Reason != "" {
+				source += " (" + step.Reason + ")"
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", importActionTitles[step.Action], step.Type, step.Label, source)
+		}
+		w.Flush()
+		fmt.Printf("\nВсего: %d. Создать: %d, переименовать: %d, перезаписать: %d, пропустить: %d\n", len(report),
+			counts[importer.ActionCreate], counts[importer.ActionRename],
+			counts[importer.ActionOverwrite], counts[importer.ActionSkip])
+	})
+}
+
+// applyImport сохраняет записи пакетами по batch записей, записи пакета сохраняются одновременно.
+// Ошибки отдельных записей выводятся в stderr и не прерывают импорт
+func (c *Command) applyImport(steps []importer.Step, batch int) (int, int) {
+	var pending []importer.Step
+	for _, step := range steps {
+		if step.Action != importer.ActionSkip {
+			pending = append(pending, step)
+		}
+	}
+
+	imported, failed := 0, 0
+	for start := 0; start < len(pending); start += batch {
+		end := start + batch
+		if end > len(pending) {
+			end = len(pending)
+		}
+
+		errs := make([]error, end-start)
+		var wg sync.WaitGroup
+		for i, step := range pending[start:end] {
+			wg.Add(1)
+			go func(i int, step importer.Step) {
+				defer wg.Done()
+				errs[i] = c.saveEntry(step.Label, step.Entry)
+			}(i, step)
+		}
+		wg.Wait()
+
+		for i, err := range errs {
+			if err != nil {
+				failed++
+				fmt.Fprintf(os.Stderr, "Ошибка при сохранении '%s': %v\n", pending[start+i].Label, err)
+				continue
+			}
+			imported++
+		}
+		fmt.Fprintf(os.Stderr, "Сохранено %d из %d\n", end, len(pending))
+	}
+	return imported, failed
+}
+
+// saveEntry сохраняет запись экспорта под меткой label
+func (c *Command) saveEntry(label string, entry importer.Entry) error {
+	switch entry.Type {
+	case domain.UserDataTypeCredential:
+		return c.clientUseCase.SaveCredential(label, entry.Credential, entry.Metadata)
+	case domain.UserDataTypeCard:
+		return c.clientUseCase.SaveCard(label, entry.Card, entry.Metadata)
+	case domain.UserDataTypeText:
+		return c.clientUseCase.SaveText(label, entry.Text, entry.Metadata)
+	default:
+		return fmt.Errorf("неизвестный тип записи '%s'", entry.Type)
+	}
+}
@@ -0,0 +1,78 @@
+package command
+
+import (
+	"github.com/SmirnovND/gophkeeper/internal/domain"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// newImportCommand возвращает команду import с записью github на сервере и сохраняет метки импортированных записей
+func newImportCommand(saved *[]string) *Command {
+	var mu sync.Mutex
+	save := func(label string) {
+		mu.Lock()
+		defer mu.Unlock()
+		*saved = append(*saved, label)
+	}
+
+	return &Command{clientUseCase: &MockDataClientUseCase{
+		ListItemsFunc: func() ([]domain.ItemInfo, error) {
+			return []domain.ItemInfo{{Type: domain.UserDataTypeCredential, Label: "github"}}, nil
+		},
+		SaveCredentialFunc: func(label string, credentialData *domain.CredentialData, metadata string) error {
+			save(label)
+			return nil
+		},
+		SaveTextFunc: func(label string, textData *domain.TextData, metadata string) error {
+			save(label)
+			return nil
+		},
+	}}
+}
+
+// TestCommand_ImportCmd тестирует отчет пробного запуска и сохранение записей с --apply
+func TestCommand_ImportCmd(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "chrome.csv")
+	data := "name,url,username,password,note\n" +
+		"github,https://github.com,alice,s3cret,\n" +
+		"gitlab,https://gitlab.com,alice,pa55,\n" +
+		"wifi,,,,hunter2\n"
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var saved []string
+	out, err := runWithOutput(t, newImportCommand(&saved).ImportCmd(), path, "--on-conflict", "rename")
+	if err != nil {
+		t.Fatalf("Неожиданная ошибка: %v", err)
+	}
+	if len(saved) != 0 {
+		t.Errorf("Пробный запуск не должен сохранять записи, сохранены %v", saved)
+	}
+	for _, want := range []string{"переименовать  credential  github (2)", "создать", "Создать: 2, переименовать: 1"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Ожидалось '%s' в отчете:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "s3cret") {
+		t.Errorf("Отчет не должен содержать секреты:\n%s", out)
+	}
+
+	_, err = runWithOutput(t, newImportCommand(&saved).ImportCmd(), path, "--apply", "--batch-size", "2")
+	if err != nil {
+		t.Fatalf("Неожиданная ошибка: %v", err)
+	}
+	sort.Strings(saved)
+	if strings.Join(saved, ",") != "gitlab,wifi" {
+		t.Errorf("Ожидалось сохранение gitlab и wifi с пропуском github, сохранены %v", saved)
+	}
+
+	_, err = runWithOutput(t, newImportCommand(&saved).ImportCmd(), path, "--on-conflict", "merge")
+	if ExitCode(err) != ExitUsage {
+		t.Errorf("Ожидался код завершения %d для неизвестного способа, получен %d", ExitUsage, ExitCode(err))
+	}
+}
@@ -0,0 +1,144 @@
+package command
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/SmirnovND/gophkeeper/internal/itemtype"
+	"github.com/spf13/cobra"
+	"io"
+	"os"
+	"text/template"
+)
+
+// InjectCmd создает команду подстановки секретов в шаблон конфигурационного файла
+func (c *Command) InjectCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "inject",
+		Short: "Подстановка секретов в шаблон конфигурационного файла",
+		Long: "Заполняет шаблон Go text/template значениями полей записей и записывает результат.\n" +
+			"Функции шаблона:\n" +
+			"  {{ secret \"credential/prod-db\" \"password\" }} - поле по ссылке [type/]label[#field]\n" +
+			"  {{ credential \"prod-db\" \"login\" }}, {{ card \"corp\" \"number\" }}, {{ text \"api-token\" }},\n" +
+			"  {{ ssh_key \"deploy\" \"public_key\" }}, {{ api_token \"github\" }} и функции других типов passcli item types\n" +
+			"Без поля подставляется пароль, номер карты, текст, закрытый ключ SSH или основной секрет типа.\n" +
+			"Все записи шаблона запрашиваются одним пакетом до записи результата; если хотя бы одна ссылка\n" +
+			"не найдена, результат не записывается. Файл --output записывается атомарно с правами 0600",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			inputPath, _ := cmd.Flags().GetString("input")
+			outputPath, _ := cmd.Flags().GetString("output")
+
+			data, err := newInput().source(inputPath)
+			if err != nil {
+				return fail("Ошибка при чтении шаблона:", fmt.Errorf("%w: %v", errUsage, err))
+			}
+			rendered, err := c.renderTemplate(inputPath, string(data))
+			if err != nil {
+				return fail("Ошибка при заполнении шаблона:", err)
+			}
+
+			if outputPath == "-" {
+				if _, err := os.Stdout.Write(rendered); err != nil {
+					return fail("Ошибка при записи результата:", err)
+				}
+				return nil
+			}
+			err = writeFileAtomic(outputPath, 0o600, func(w io.Writer) error {
+				_, err := w.Write(rendered)
+				return err
+			})
+			if err != nil {
+				return fail("Ошибка при записи результата:", err)
+			}
+			fmt.Fprintf(os.Stderr, "Шаблон заполнен и сохранен в '%s'\n", outputPath)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringP("input", "i", "-", "Файл шаблона, '-' - чтение из stdin")
+	cmd.Flags().StringP("output", "o", "-", "Файл результата, '-' - вывод в stdout")
+
+	return cmd
+}
+
+// renderTemplate заполняет шаблон text. Шаблон выполняется дважды: первый проход собирает ссылки на записи,
+// которые затем запрашиваются одним пакетом, второй подставляет значения
+func (c *Command) renderTemplate(name string, text string) ([]byte, error) {
+	var refs []secretRef
+	collect := templateFuncs(func(ref secretRef) (string, error) {
+		refs = append(refs, ref)
+		return "", nil
+	})
+	tmpl, err := template.New(name).Option("missingkey=error").Funcs(collect).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", errUsage, err)
+	}
+	if err := tmpl.Execute(io.Discard, nil); err != nil {
+		return nil, fmt.Errorf("%w: %v", errUsage, err)
+	}
+
+	resolver := c.newSecretResolver()
+	if err := resolver.prefetch(refs); err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	tmpl.Funcs(templateFuncs(func(ref secretRef) (string, error) {
+		_, value, err := resolver.resolve(ref)
+		return value, err
+	}))
+	if err := tmpl.Execute(&buf, nil); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// templateFuncs возвращает функции шаблона, получающие значения полей через value
+func templateFuncs(value func(ref secretRef) (string, error)) template.FuncMap {
+	typed := func(itemType string) func(label string, fields ...string) (string, error) {
+		return func(label string, fields ...string) (string, error) {
+			field, err := templateField(fields)
+			if err != nil {
+				return "", err
+			}
+			return value(secretRef{Type: itemType, Label: label, Field: field})
+		}
+	}
+
+	funcs := template.FuncMap{
+		"secret": func(ref string, fields ...string) (string, error) {
+			parsed, err := parseSecretRef(ref)
+			if err != nil {
+				return "", err
+			}
+			field, err := templateField(fields)
+			if err != nil {
+				return "", err
+			}
+			if field != "" {
+				if parsed.Field != "" {
+					return "", fmt.Errorf("%w: поле задано и в ссылке '%s', и аргументом '%s'", errUsage, ref, field)
+				}
+				parsed.Field = field
+			}
+			return value(parsed)
+		},
+	}
+	// У каждого зарегистрированного типа есть функция с именем типа, например credential или api_token
+	for _, t := range itemtype.Types() {
+		funcs[t.Name] = typed(t.Name)
+	}
+	return funcs
+}
+
+// templateField возвращает необязательное поле - последний аргумент функции шаблона
+func templateField(fields []string) (string, error) {
+	switch len(fields) {
+	case 0:
+		return "", nil
+	case 1:
+		return fields[0], nil
+	default:
+		return "", fmt.Errorf("%w: ожидалось не более одного поля, передано %d", errUsage, len(fields))
+	}
+}
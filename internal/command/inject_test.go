@@ -0,0 +1,114 @@
+package command
+
+import (
+	"github.com/SmirnovND/gophkeeper/internal/domain"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+// newInjectCommand возвращает команду с учетными данными prod-db, картой corp и токеном API github и считает запросы к серверу
+func newInjectCommand(requests *int32) *Command {
+	return &Command{clientUseCase: &MockDataClientUseCase{
+		ListItemsFunc: func() ([]domain.ItemInfo, error) {
+			atomic.AddInt32(requests, 1)
+			return []domain.ItemInfo{
+				{Type: domain.UserDataTypeCredential, Label: "prod-db"},
+				{Type: domain.UserDataTypeCard, Label: "corp"},
+				{Type: domain.UserDataTypeAPIToken, Label: "github"},
+			}, nil
+		},
+		GetCredentialFunc: func(label string) (*domain.CredentialData, string, error) {
+			atomic.AddInt32(requests, 1)
+			return &domain.CredentialData{Login: "app", Password: "s3cret"}, "", nil
+		},
+		GetCardFunc: func(label string) (*domain.CardData, string, error) {
+			atomic.AddInt32(requests, 1)
+			return &domain.CardData{Number: "4111111111111111", Holder: "ALICE"}, "", nil
+		},
+		GetItemFunc: func(itemType string, label string) (domain.ItemData, string, error) {
+			atomic.AddInt32(requests, 1)
+			return domain.ItemData{"service": "GitHub", "token": "ghp_x"}, "", nil
+		},
+	}}
+}
+
+// TestCommand_InjectCmd тестирует заполнение шаблона с одним запросом на каждую запись и права результата
+func TestCommand_InjectCmd(t *testing.T) {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "config.tmpl")
+	output := filepath.Join(dir, "config.yaml")
+	tmpl := "db:\n" +
+		"  user: {{ credential \"prod-db\" \"login\" }}\n" +
+		"  password: {{ secret \"credential/prod-db\" \"password\" }}\n" +
+		"  dsn: postgres://{{ secret \"prod-db#login\" }}:{{ secret \"prod-db\" }}@db\n" +
+		"card: {{ card \"corp\" \"number\" }} {{ card \"corp\" \"holder\" | printf \"%q\" }}\n" +
+		"github: {{ secret \"api_token/github#service\" }} {{ api_token \"github\" }}\n"
+	if err := os.WriteFile(input, []byte(tmpl), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var requests int32
+	out, err := runWithOutput(t, newInjectCommand(&requests).InjectCmd(), "-i", input, "-o", output)
+	if err != nil {
+		t.Fatalf("Неожиданная ошибка: %v", err)
+	}
+	if out != "" {
+		t.Errorf("С файлом результата секреты не должны выводиться в stdout, получено %q", out)
+	}
+	if requests != 4 {
+		t.Errorf("Ожидалось 4 запроса к серверу (список и три записи), выполнено %d", requests)
+	}
+
+	data, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "db:\n  user: app\n  password: s3cret\n  dsn: postgres://app:s3cret@db\ncard: 4111111111111111 \"ALICE\"\ngithub: GitHub ghp_x\n"
+	if string(data) != want {
+		t.Errorf("Ожидалось:\n%s\nполучено:\n%s", want, data)
+	}
+	if info, _ := os.Stat(output); runtime.GOOS != "windows" && info.Mode().Perm() != 0o600 {
+		t.Errorf("Ожидались права 0600, получено %v", info.Mode().Perm())
+	}
+}
+
+// TestCommand_InjectCmd_MissingReference тестирует, что при отсутствующих ссылках результат не записывается
+func TestCommand_InjectCmd_MissingReference(t *testing.T) {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "config.tmpl")
+	output := filepath.Join(dir, "config.yaml")
+	tmpl := `{{ secret "prod-db" }} {{ card "prod-db" }} {{ credential "staging-db" }} {{ secret "corp#cvc" }}`
+	if err := os.WriteFile(input, []byte(tmpl), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(output, []byte("old"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var requests int32
+	_, err := runWithOutput(t, newInjectCommand(&requests).InjectCmd(), "-i", input, "-o", output)
+	if err == nil {
+		t.Fatal("Ожидалась ошибка")
+	}
+	for _, want := range []string{"card/prod-db", "credential/staging-db"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Ожидалось упоминание '%s' в ошибке: %v", want, err)
+		}
+	}
+	if data, _ := os.ReadFile(output); string(data) != "old" {
+		t.Errorf("Существующий файл не должен изменяться, получено %q", data)
+	}
+
+	// Поля проверяются после получения записей
+	if err := os.WriteFile(input, []byte(`{{ secret "corp#cvc" }}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := runWithOutput(t, newInjectCommand(&requests).InjectCmd(), "-i", input, "-o", output); err == nil ||
+		!strings.Contains(err.Error(), "cvc") {
+		t.Errorf("Ожидалась ошибка отсутствующего поля, получено: %v", err)
+	}
+}
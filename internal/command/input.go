@@ -0,0 +1,133 @@
+package command
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"github.com/mattn/go-isatty"
+	"github.com/spf13/cobra"
+	"io"
+	"os"
+	"strings"
+)
+
+// errMissingInput возвращается, когда обязательное значение не передано аргументом или флагом,
+// а запросить его у пользователя нельзя, так как stdin не является терминалом
+var errMissingInput = errors.New("не задано обязательное значение")
+
+// stdinIsTerminal сообщает, подключен ли stdin к терминалу
+var stdinIsTerminal = func() bool {
+	fd := os.Stdin.Fd()
+	return isatty.IsTerminal(fd) || isatty.IsCygwinTerminal(fd)
+}
+
+// input читает недостающие значения команды. Значения запрашиваются у пользователя, только если stdin - терминал,
+// иначе команда завершается ошибкой, а не ожидает ввода, которого в скрипте не будет.
+// Запросы выводятся в stderr, чтобы не смешиваться с результатом команды в stdout
+type input struct {
+	reader      *bufio.Reader
+	interactive bool
+}
+
+// newInput создает читателя недостающих значений из stdin
+func newInput() *input {
+	return &input{
+		reader:      bufio.NewReader(os.Stdin),
+		interactive: stdinIsTerminal(),
+	}
+}
+
+// require возвращает value, если оно задано, иначе запрашивает его у пользователя.
+// hint подсказывает, как передать значение без запроса, и указывается в сообщении об ошибке
+func (in *input) require(value string, prompt string, hint string) (string, error) {
+	if value != "" {
+		return value, nil
+	}
+	if !in.interactive {
+		return "", fmt.Errorf("%w: %s", errMissingInput, hint)
+	}
+
+	value = in.ask(prompt)
+	if value == "" {
+		return "", fmt.Errorf("%w: %s", errMissingInput, hint)
+	}
+	return value, nil
+}
+
+// optional возвращает value, если флаг задан, иначе на терминале запрашивает необязательное значение
+func (in *input) optional(value string, set bool, prompt string) string {
+	if set || value != "" || !in.interactive {
+		return value
+	}
+	return in.ask(prompt)
+}
+
+// text возвращает многострочный текст, введенный пользователем до конца ввода (Ctrl+D)
+func (in *input) text(prompt string, hint string) (string, error) {
+	if !in.interactive {
+		return "", fmt.Errorf("%w: %s", errMissingInput, hint)
+	}
+
+	fmt.Fprintln(os.Stderr, prompt)
+	content, err := io.ReadAll(in.reader)
+	if err != nil {
+		return "", err
+	}
+
+	text := strings.TrimRight(string(content), "\r\n")
+	if text == "" {
+		return "", fmt.Errorf("%w: %s", errMissingInput, hint)
+	}
+	return text, nil
+}
+
+// confirm возвращает true, если действие подтверждено флагом yes или ответом пользователя.
+// Если stdin не является терминалом, без флага команда завершается ошибкой с подсказкой hint
+func (in *input) confirm(yes bool, prompt string, hint string) (bool, error) {
+	if yes {
+		return true, nil
+	}
+	if !in.interactive {
+		return false, fmt.Errorf("%w: %s", errMissingInput, hint)
+	}
+
+	switch strings.ToLower(strings.TrimSpace(in.ask(prompt + " [y/N]"))) {
+	case "y", "yes", "д", "да":
+		return true, nil
+	default:
+		return false, nil
+	}
+}
+
+// ask выводит запрос и читает ответ пользователя
+func (in *input) ask(prompt string) string {
+	fmt.Fprintln(os.Stderr, prompt)
+	fmt.Fprint(os.Stderr, "> ")
+	return in.line()
+}
+
+// line читает строку целиком, поэтому значения с пробелами не обрезаются
+func (in *input) line() string {
+	line, _ := in.reader.ReadString('\n')
+	return strings.TrimRight(line, "\r\n")
+}
+
+// source читает содержимое файла, путь "-" означает stdin
+func (in *input) source(path string) ([]byte, error) {
+	if path == "-" {
+		return io.ReadAll(in.reader)
+	}
+	return os.ReadFile(path)
+}
+
+// labelArg возвращает метку из позиционного аргумента или флага --label
+func labelArg(cmd *cobra.Command, args []string) (string, error) {
+	label, _ := cmd.Flags().GetString("label")
+	if len(args) == 0 {
+		return label, nil
+	}
+	if label != "" && label != args[0] {
+		return "", fmt.Errorf("метка задана и аргументом '%s', и флагом --label '%s'", args[0], label)
+	}
+	return args[0], nil
+}
@@ -0,0 +1,137 @@
+package command
+
+import (
+	"errors"
+	"fmt"
+	"github.com/SmirnovND/gophkeeper/internal/domain"
+	"net/http"
+	"net/url"
+	"os"
+	"testing"
+)
+
+// TestMain считает stdin терминалом, так как тесты команд эмулируют ввод пользователя через пайп,
+// на котором нельзя отключить отображение ввода
+func TestMain(m *testing.M) {
+	stdinIsTerminal = func() bool { return true }
+	disableEcho = func(fd uintptr) (func(), error) { return func() {}, nil }
+	os.Exit(m.Run())
+}
+
+// withStdin подменяет stdin данными input и режимом терминала на время теста
+func withStdin(t *testing.T, input string, terminal bool) {
+	oldStdin, oldIsTerminal := os.Stdin, stdinIsTerminal
+	t.Cleanup(func() {
+		os.Stdin, stdinIsTerminal = oldStdin, oldIsTerminal
+	})
+
+	r, w, _ := os.Pipe()
+	go func() {
+		w.Write([]byte(input))
+		w.Close()
+	}()
+	os.Stdin = r
+	stdinIsTerminal = func() bool { return terminal }
+}
+
+// TestCommand_NonInteractive_MissingLabel тестирует отказ от запроса метки, когда stdin не является терминалом
+func TestCommand_NonInteractive_MissingLabel(t *testing.T) {
+	withStdin(t, "test_label\n", false)
+
+	mockClientUseCase := &MockDataClientUseCase{
+		GetTextFunc: func(label string) (*domain.TextData, string, error) {
+			t.Error("Команда не должна выполняться без метки")
+			return nil, "", nil
+		},
+	}
+
+	cmd := &Command{clientUseCase: mockClientUseCase}
+	getTextCmd := cmd.GetTextCmd()
+	getTextCmd.SetArgs([]string{})
+	err := getTextCmd.Execute()
+
+	if !errors.Is(err, errMissingInput) {
+		t.Errorf("Ожидалась ошибка отсутствующего значения, получена %v", err)
+	}
+	if ExitCode(err) != ExitUsage {
+		t.Errorf("Ожидался код завершения %d, получен %d", ExitUsage, ExitCode(err))
+	}
+}
+
+// TestCommand_NonInteractive_SaveCredential тестирует сохранение учетных данных из флагов и stdin
+func TestCommand_NonInteractive_SaveCredential(t *testing.T) {
+	withStdin(t, "s3cret pass\n", false)
+
+	var saved *domain.CredentialData
+	var savedLabel, savedMetadata string
+	mockClientUseCase := &MockDataClientUseCase{
+		SaveCredentialFunc: func(label string, credentialData *domain.CredentialData, metadata string) error {
+			savedLabel, saved, savedMetadata = label, credentialData, metadata
+			return nil
+		},
+	}
+
+	cmd := &Command{clientUseCase: mockClientUseCase}
+	saveCmd := cmd.SaveCredentialCmd()
+	saveCmd.SetArgs([]string{"my bank", "--login", "alice", "--password-stdin", "--metadata", "основной счет"})
+	if err := saveCmd.Execute(); err != nil {
+		t.Fatalf("Неожиданная ошибка: %v", err)
+	}
+
+	if savedLabel != "my bank" || savedMetadata != "основной счет" {
+		t.Errorf("Неожиданные метка '%s' и метаинформация '%s'", savedLabel, savedMetadata)
+	}
+	if saved == nil || saved.Login != "alice" || saved.Password != "s3cret pass" {
+		t.Errorf("Неожиданные учетные данные: %+v", saved)
+	}
+}
+
+// TestCommand_NonInteractive_SaveTextFromFile тестирует сохранение многострочного текста из stdin
+func TestCommand_NonInteractive_SaveTextFromFile(t *testing.T) {
+	withStdin(t, "первая строка\nвторая строка\n", false)
+
+	var content string
+	mockClientUseCase := &MockDataClientUseCase{
+		SaveTextFunc: func(label string, textData *domain.TextData, metadata string) error {
+			content = textData.Content
+			return nil
+		},
+	}
+
+	cmd := &Command{clientUseCase: mockClientUseCase}
+	saveCmd := cmd.SaveTextCmd()
+	saveCmd.SetArgs([]string{"--label", "notes", "--from-file", "-"})
+	if err := saveCmd.Execute(); err != nil {
+		t.Fatalf("Неожиданная ошибка: %v", err)
+	}
+
+	if content != "первая строка\nвторая строка\n" {
+		t.Errorf("Ожидался текст из stdin целиком, получено '%s'", content)
+	}
+}
+
+// TestExitCode тестирует выбор кода завершения по виду ошибки
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{name: "Success", err: nil, want: ExitOK},
+		{name: "Not found", err: &reportedError{err: fmt.Errorf("ошибка: %w", &domain.Error{Message: "не найдено", CodeValue: http.StatusNotFound})}, want: ExitNotFound},
+		{name: "Unauthorized", err: &reportedError{err: &domain.Error{Message: "код ответа: 401", CodeValue: http.StatusUnauthorized}}, want: ExitAuth},
+		{name: "Not logged in", err: &reportedError{err: fmt.Errorf("ошибка при загрузке токена: %w", domain.ErrNotLoggedIn)}, want: ExitAuth},
+		{name: "Network", err: &reportedError{err: fmt.Errorf("ошибка при выполнении запроса: %w", &url.Error{Op: "Get", URL: "http://127.0.0.1:1", Err: errors.New("connection refused")})}, want: ExitNetwork},
+		{name: "Server error", err: &reportedError{err: &domain.Error{Message: "код ответа: 500", CodeValue: http.StatusInternalServerError}}, want: ExitError},
+		{name: "Missing input", err: &reportedError{err: fmt.Errorf("%w: --label", errMissingInput)}, want: ExitUsage},
+		{name: "Invalid arguments", err: errors.New("accepts at most 1 arg(s), received 2"), want: ExitUsage},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ExitCode(tt.err); got != tt.want {
+				t.Errorf("Ожидался код завершения %d, получен %d", tt.want, got)
+			}
+		})
+	}
+}
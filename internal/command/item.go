@@ -0,0 +1,397 @@
+package command
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/SmirnovND/gophkeeper/internal/domain"
+	"github.com/SmirnovND/gophkeeper/internal/itemtype"
+	"github.com/spf13/cobra"
+	"strings"
+	"time"
+)
+
+// itemClock возвращает текущее время для вывода срока действия записи; в тестах подменяется
+var itemClock = time.Now
+
+// itemTypeInfo - описание типа записи в выводе passcli item types
+type itemTypeInfo struct {
+	Name   string          `json:"name"`
+	Title  string          `json:"title"`
+	Secret string          `json:"secret"`
+	Custom bool            `json:"custom"`
+	Fields []itemFieldInfo `json:"fields"`
+}
+
+type itemFieldInfo struct {
+	Name     string   `json:"name"`
+	Title    string   `json:"title"`
+	Kind     string   `json:"kind"`
+	Required bool     `json:"required"`
+	Options  []string `json:"options,omitempty"`
+}
+
+// structuredItem - запись структурированного типа в форматах json, yaml и env.
+// Поля выводятся в порядке описания типа между меткой и метаинформацией
+type structuredItem struct {
+	itemType *itemtype.Type
+	label    string
+	data     domain.ItemData
+	metadata string
+}
+
+// MarshalJSON сохраняет порядок полей: type, label, поля типа и metadata
+func (i *structuredItem) MarshalJSON() ([]byte, error) {
+	pairs := [][2]string{{"type", i.itemType.Name}, {"label", i.label}}
+	for _, field := range i.itemType.Fields {
+		pairs = append(pairs, [2]string{field.Name, i.data[field.Name]})
+	}
+	pairs = append(pairs, [2]string{"metadata", i.metadata})
+
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for n, pair := range pairs {
+		key, _ := json.Marshal(pair[0])
+		value, _ := json.Marshal(pair[1])
+		if n > 0 {
+			buf.WriteByte(',')
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// lookupItemType возвращает встроенный или пользовательский тип записи по имени;
+// неизвестный тип считается ошибкой аргументов
+func (c *Command) lookupItemType(name string) (*itemtype.Type, error) {
+	t, err := c.clientUseCase.ItemType(name)
+	if errors.Is(err, itemtype.ErrUnknownType) {
+		names := make([]string, 0)
+		for _, known := range itemtype.Types() {
+			names = append(names, known.Name)
+		}
+		return nil, fmt.Errorf("%w: %v, допустимы %s и типы из шаблонов passcli item template list", errUsage, err, strings.Join(names, ", "))
+	}
+	return t, err
+}
+
+// ItemCmd создает команду работы с записями структурированных типов с подкомандами types, save, get и delete
+func (c *Command) ItemCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "item",
+		Short: "Документы, банковские счета, токены API, сети Wi-Fi и лицензии",
+		Long: "Записи структурированных типов. Поля каждого типа выводит passcli item types, например:\n" +
+			"passcli item save api_token github --set service=GitHub --set expires_at=2027-01-31\n" +
+			"passcli item get api_token github --field token\n" +
+			"Собственные типы записей задаются шаблонами passcli item template",
+	}
+
+	cmd.AddCommand(c.itemTypesCmd(), c.itemSaveCmd(), c.itemGetCmd(), c.itemDeleteCmd(), c.itemTemplateCmd())
+	return cmd
+}
+
+// itemTypesCmd создает команду вывода типов записей и их полей
+func (c *Command) itemTypesCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "types",
+		Short: "Список типов записей и их полей",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out, err := newPrinter(cmd)
+			if err != nil {
+				return fail("Ошибка при получении списка типов:", err)
+			}
+
+			// Пользовательские типы выводятся, только если клиент авторизован
+			types := itemtype.Types()
+			templates, err := c.clientUseCase.ListItemTemplates()
+			if err != nil && !errors.Is(err, domain.ErrNotLoggedIn) {
+				return fail("Ошибка при получении списка типов:", err)
+			}
+			for _, template := range templates {
+				t, err := itemtype.ParseSchema(template.Name, template.Schema)
+				if err != nil {
+					return fail("Ошибка при получении списка типов:", err)
+				}
+				types = append(types, t)
+			}
+
+			infos := make([]itemTypeInfo, 0, len(types))
+			for _, t := range types {
+				info := itemTypeInfo{Name: t.Name, Title: t.Title, Secret: t.Secret, Custom: t.Custom, Fields: make([]itemFieldInfo, 0, len(t.Fields))}
+				for _, field := range t.Fields {
+					info.Fields = append(info.Fields, itemFieldInfo{Name: field.Name, Title: field.Title, Kind: field.Kind,
+						Required: field.Required, Options: field.Options})
+				}
+				infos = append(infos, info)
+			}
+
+			err = out.print(infos, func() {
+				for _, info := range infos {
+					if info.Custom {
+						fmt.Printf("\n%s (%s, шаблон):\n", info.Name, info.Title)
+					} else {
+						fmt.Printf("\n%s (%s):\n", info.Name, info.Title)
+					}
+					fmt.Println("----------------------")
+					for _, field := range info.Fields {
+						line := fmt.Sprintf("%s\t%s\t%s", field.Name, field.Kind, field.Title)
+						if field.Required {
+							line += ", обязательное"
+						}
+						if len(field.Options) > 0 {
+							line += ": " + strings.Join(field.Options, ", ")
+						}
+						fmt.Println(line)
+					}
+				}
+				fmt.Println("----------------------")
+			})
+			if err != nil {
+				return fail("Ошибка при получении списка типов:", err)
+			}
+			return nil
+		},
+	}
+}
+
+// itemSaveCmd создает команду сохранения записи структурированного типа
+func (c *Command) itemSaveCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "save <type> [label]",
+		Short: "Сохранение записи",
+		Long: "Сохранение записи. Поля задаются флагами --set имя=значение или JSON-файлом --from-file,\n" +
+			"незаданные поля запрашиваются на терминале. Секретные поля не передаются флагами, чтобы не попасть\n" +
+			"в историю команд, и вводятся без отображения или читаются из --from-file, например:\n" +
+			"passcli item save wifi home --set ssid=home --set security=WPA2\n" +
+			"echo '{\"token\":\"'$TOKEN'\"}' | passcli item save api_token github --set service=GitHub --from-file -",
+		Args: cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			itemType, err := c.lookupItemType(args[0])
+			if err != nil {
+				return fail("Ошибка при сохранении записи:", err)
+			}
+			sets, _ := cmd.Flags().GetStringArray("set")
+			fromFile, _ := cmd.Flags().GetString("from-file")
+
+			// Флаги разбираются до запросов, чтобы опечатка в имени поля не заставляла вводить данные повторно
+			data := domain.ItemData{}
+			for _, set := range sets {
+				name, value, ok := strings.Cut(set, "=")
+				field, known := itemType.Field(name)
+				switch {
+				case !ok:
+					return fail("Ошибка при сохранении записи:", fmt.Errorf("%w: флаг --set задается в виде имя=значение, получено '%s'", errUsage, set))
+				case !known:
+					return fail("Ошибка при сохранении записи:", fmt.Errorf("%w: у типа %s нет поля '%s'", errUsage, itemType.Name, name))
+				case field.Kind == itemtype.KindSecret:
+					return fail("Ошибка при сохранении записи:", fmt.Errorf("%w: секретное поле '%s' не передается флагом, используйте --from-file или ввод на терминале", errUsage, name))
+				}
+				data[name] = value
+			}
+
+			in := newInput()
+			// stdin занят JSON-файлом, поэтому недостающие поля не запрашиваются
+			if fromFile == "-" {
+				in.interactive = false
+			}
+			label, err := promptLabel(cmd, args[1:], in, fmt.Sprintf("Введите уникальное название (label) для записи типа %s:", itemType.Name))
+			if err != nil {
+				return fail("Ошибка при сохранении записи:", err)
+			}
+
+			fileData := domain.ItemData{}
+			if err := readJSONSource(cmd, in, &fileData); err != nil {
+				return fail("Ошибка при сохранении записи:", err)
+			}
+			for name, value := range fileData {
+				if _, ok := data[name]; !ok {
+					data[name] = value
+				}
+			}
+
+			for _, field := range itemType.Fields {
+				value, err := promptField(in, field, data[field.Name])
+				if err != nil {
+					return fail("Ошибка при сохранении записи:", err)
+				}
+				if value == "" {
+					delete(data, field.Name)
+					continue
+				}
+				data[field.Name] = value
+			}
+			metadata := promptMetadata(cmd, in)
+
+			// Вызываем метод сохранения записи, значения полей проверяются до отправки
+			err = c.clientUseCase.SaveItem(itemType.Name, label, data, metadata)
+			if errors.Is(err, itemtype.ErrInvalidItem) {
+				err = fmt.Errorf("%w: %v", errUsage, err)
+			}
+			if err != nil {
+				return fail("Ошибка при сохранении записи:", err)
+			}
+
+			fmt.Printf("Запись '%s' (%s) успешно сохранена!\n", label, itemType.Title)
+			return nil
+		},
+	}
+
+	addSaveFlags(cmd, "JSON-файл с полями записи, '-' для чтения из stdin")
+	cmd.Flags().StringArray("set", nil, "Значение несекретного поля в виде имя=значение, флаг повторяется")
+
+	return cmd
+}
+
+// promptField возвращает значение поля, а если оно не задано - запрашивает его на терминале.
+// Секретные поля вводятся без отображения, для перечислений в запросе перечисляются допустимые значения
+func promptField(in *input, field itemtype.Field, value string) (string, error) {
+	// Списки не вводятся построчно, они задаются флагом --set или в --from-file текстом JSON
+	if value != "" || field.Kind == itemtype.KindList {
+		return value, nil
+	}
+
+	prompt := "Введите " + strings.ToLower(field.Title)
+	switch field.Kind {
+	case itemtype.KindEnum:
+		prompt += " (" + strings.Join(field.Options, ", ") + ")"
+	case itemtype.KindDate:
+		prompt += " (ГГГГ-ММ-ДД)"
+	}
+	if !field.Required {
+		prompt += " (необязательно)"
+	}
+	prompt += ":"
+
+	if field.Kind == itemtype.KindSecret {
+		secret := secretField{
+			prompt:    prompt,
+			hint:      fmt.Sprintf("поле %s передается в --from-file", field.Name),
+			maxLength: maxSecretLength,
+		}
+		if field.Required {
+			return in.secret(false, secret)
+		}
+		return in.optionalSecret("", secret)
+	}
+	if field.Required {
+		return in.require("", prompt, fmt.Sprintf("поле %s задается флагом --set %s=... или в --from-file", field.Name, field.Name))
+	}
+	return in.optional("", false, prompt), nil
+}
+
+// itemGetCmd создает команду получения записи структурированного типа
+func (c *Command) itemGetCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "get <type> [label]",
+		Short: "Получение записи",
+		Long: "Получение записи. Значение одного поля выводится флагом --field, например:\n" +
+			"passcli item get bank_account salary --field iban",
+		Args: cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out, err := newPrinter(cmd)
+			if err != nil {
+				return fail("Ошибка при получении записи:", err)
+			}
+			itemType, err := c.lookupItemType(args[0])
+			if err != nil {
+				return fail("Ошибка при получении записи:", err)
+			}
+			label, err := promptLabel(cmd, args[1:], newInput(), fmt.Sprintf("Введите уникальное название (label) записи типа %s для получения:", itemType.Name))
+			if err != nil {
+				return fail("Ошибка при получении записи:", err)
+			}
+
+			// Вызываем метод получения записи
+			data, metadata, err := c.clientUseCase.GetItem(itemType.Name, label)
+			if err != nil {
+				return fail("Ошибка при получении записи:", err)
+			}
+
+			item := &structuredItem{itemType: itemType, label: label, data: data, metadata: metadata}
+			err = out.print(item, func() {
+				fmt.Printf("\nЗапись '%s' (%s):\n", label, itemType.Title)
+				fmt.Println("------------------")
+				for _, field := range itemType.Fields {
+					if value := data[field.Name]; value != "" {
+						fmt.Printf("%s: %s\n", field.Title, value)
+					}
+				}
+				if status := expiryStatus(itemType, data); status != "" {
+					fmt.Println("Срок действия:", status)
+				}
+				fmt.Println("------------------")
+
+				if metadata != "" {
+					fmt.Println("\nМетаинформация:")
+					fmt.Println("------------------")
+					fmt.Println(metadata)
+					fmt.Println("------------------")
+				}
+			})
+			if err != nil {
+				return fail("Ошибка при получении записи:", err)
+			}
+			return nil
+		},
+	}
+
+	addLabelFlag(cmd)
+
+	return cmd
+}
+
+// expiryStatus описывает, истек ли срок действия записи и сколько дней до него осталось
+func expiryStatus(itemType *itemtype.Type, data domain.ItemData) string {
+	date, ok := itemType.ExpiresAt(data)
+	if !ok {
+		return ""
+	}
+	now := itemClock()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	days := int(date.Sub(today).Hours() / 24)
+	switch {
+	case days < 0:
+		return "истек"
+	case days == 0:
+		return "истекает сегодня"
+	default:
+		return fmt.Sprintf("осталось %d дн.", days)
+	}
+}
+
+// itemDeleteCmd создает команду удаления записи структурированного типа
+func (c *Command) itemDeleteCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "delete <type> [label]",
+		Short: "Удаление записи",
+		Args:  cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			itemType, err := c.lookupItemType(args[0])
+			if err != nil {
+				return fail("Ошибка при удалении записи:", err)
+			}
+			label, err := promptLabel(cmd, args[1:], newInput(), fmt.Sprintf("Введите уникальное название (label) записи типа %s для удаления:", itemType.Name))
+			if err != nil {
+				return fail("Ошибка при удалении записи:", err)
+			}
+
+			// Вызываем метод удаления записи
+			err = c.clientUseCase.DeleteItem(itemType.Name, label)
+			if err != nil {
+				return fail("Ошибка при удалении записи:", err)
+			}
+
+			fmt.Println("Запись успешно удалена!")
+			return nil
+		},
+	}
+
+	addLabelFlag(cmd)
+
+	return cmd
+}
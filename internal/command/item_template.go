@@ -0,0 +1,159 @@
+package command
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/SmirnovND/gophkeeper/internal/domain"
+	"github.com/SmirnovND/gophkeeper/internal/itemtype"
+	"github.com/spf13/cobra"
+)
+
+// itemTemplateCmd создает команду управления шаблонами пользовательских типов с подкомандами save, list, get и delete
+func (c *Command) itemTemplateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "template",
+		Short: "Шаблоны собственных типов записей",
+		Long: "Шаблон описывает собственный тип записей схемой JSON Schema: объект со строковыми полями в properties.\n" +
+			"Поле с \"writeOnly\": true считается секретным, \"enum\" задает допустимые значения, \"format\" - uri или date.\n" +
+			"После сохранения шаблона записи типа работают так же, как встроенные, например:\n" +
+			"passcli item template save db_profile --from-file db_profile.json\n" +
+			"passcli item save db_profile prod --set host=db.local --set user=app",
+	}
+
+	cmd.AddCommand(c.itemTemplateSaveCmd(), c.itemTemplateListCmd(), c.itemTemplateGetCmd(), c.itemTemplateDeleteCmd())
+	return cmd
+}
+
+// itemTemplateSaveCmd создает команду сохранения шаблона
+func (c *Command) itemTemplateSaveCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "save <name>",
+		Short: "Сохранение шаблона",
+		Long: "Сохранение шаблона из JSON-файла --from-file ('-' для stdin). Шаблон с тем же именем заменяется,\n" +
+			"имя встроенного типа занять нельзя",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			fromFile, _ := cmd.Flags().GetString("from-file")
+			if fromFile == "" {
+				return fail("Ошибка при сохранении шаблона:", fmt.Errorf("%w: схема шаблона задается флагом --from-file", errMissingInput))
+			}
+			schema, err := newInput().source(fromFile)
+			if err != nil {
+				return fail("Ошибка при сохранении шаблона:", fmt.Errorf("ошибка при чтении %s: %w", fromFile, err))
+			}
+
+			// Схема проверяется до отправки, неверная схема считается ошибкой аргументов
+			err = c.clientUseCase.SaveItemTemplate(args[0], schema)
+			if errors.Is(err, itemtype.ErrInvalidSchema) {
+				err = fmt.Errorf("%w: %v", errUsage, err)
+			}
+			if err != nil {
+				return fail("Ошибка при сохранении шаблона:", err)
+			}
+
+			fmt.Printf("Шаблон '%s' успешно сохранен!\n", args[0])
+			return nil
+		},
+	}
+
+	cmd.Flags().StringP("from-file", "f", "", "JSON-файл со схемой шаблона, '-' для чтения из stdin")
+
+	return cmd
+}
+
+// itemTemplateListCmd создает команду вывода списка шаблонов
+func (c *Command) itemTemplateListCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "Список шаблонов",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out, err := newPrinter(cmd)
+			if err != nil {
+				return fail("Ошибка при получении списка шаблонов:", err)
+			}
+			templates, err := c.clientUseCase.ListItemTemplates()
+			if err != nil {
+				return fail("Ошибка при получении списка шаблонов:", err)
+			}
+			if templates == nil {
+				templates = []domain.ItemTemplate{}
+			}
+
+			err = out.print(templates, func() {
+				if len(templates) == 0 {
+					fmt.Println("Шаблоны не найдены")
+					return
+				}
+
+				fmt.Println("\nШаблоны:")
+				fmt.Println("----------------------")
+				for _, template := range templates {
+					title := template.Name
+					if t, err := itemtype.ParseSchema(template.Name, template.Schema); err == nil {
+						title = t.Title
+					}
+					fmt.Printf("%s\t%s\t%s\n", template.Name, title, template.UpdatedAt.Format("2006-01-02 15:04"))
+				}
+				fmt.Println("----------------------")
+			})
+			if err != nil {
+				return fail("Ошибка при получении списка шаблонов:", err)
+			}
+			return nil
+		},
+	}
+}
+
+// itemTemplateGetCmd создает команду вывода схемы шаблона
+func (c *Command) itemTemplateGetCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "get <name>",
+		Short: "Схема шаблона",
+		Long:  "Выводит схему шаблона в виде, пригодном для правки и повторного сохранения через save --from-file",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out, err := newPrinter(cmd)
+			if err != nil {
+				return fail("Ошибка при получении шаблона:", err)
+			}
+			template, err := c.clientUseCase.GetItemTemplate(args[0])
+			if err != nil {
+				return fail("Ошибка при получении шаблона:", err)
+			}
+
+			err = out.print(template, func() {
+				var schema bytes.Buffer
+				if json.Indent(&schema, template.Schema, "", "  ") != nil {
+					schema.Reset()
+					schema.Write(template.Schema)
+				}
+				fmt.Println(schema.String())
+			})
+			if err != nil {
+				return fail("Ошибка при получении шаблона:", err)
+			}
+			return nil
+		},
+	}
+}
+
+// itemTemplateDeleteCmd создает команду удаления шаблона. Шаблон, по которому сохранены записи, не удаляется
+func (c *Command) itemTemplateDeleteCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "delete <name>",
+		Short: "Удаление шаблона",
+		Long:  "Удаление шаблона. Пока сохранены записи его типа, сервер отказывает в удалении",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := c.clientUseCase.DeleteItemTemplate(args[0]); err != nil {
+				return fail("Ошибка при удалении шаблона:", err)
+			}
+
+			fmt.Println("Шаблон успешно удален!")
+			return nil
+		},
+	}
+}
@@ -0,0 +1,160 @@
+package command
+
+import (
+	"github.com/SmirnovND/gophkeeper/internal/domain"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestCommand_ItemSaveCmd тестирует сохранение записей с вводом на терминале и из stdin и отказ от неверных полей
+func TestCommand_ItemSaveCmd(t *testing.T) {
+	vault := newFakeVault()
+
+	withStdin(t, "hunter22\nроутер в прихожей\n", true)
+	if _, err := runWithOutput(t, vault.command().ItemCmd(), "save", "wifi", "home", "--set", "ssid=home", "--set", "security=WPA2"); err != nil {
+		t.Fatalf("Неожиданная ошибка: %v", err)
+	}
+	want := domain.ItemData{"ssid": "home", "security": "WPA2", "password": "hunter22"}
+	if got := vault.records["home"]; len(got) != len(want) || got["password"] != want["password"] || vault.items["home"].Metadata != "роутер в прихожей" {
+		t.Errorf("Ожидалась запись %v, получено %v, %+v", want, got, vault.items["home"])
+	}
+
+	withStdin(t, `{"token":"ghp_x","service":"из файла"}`, false)
+	if _, err := runWithOutput(t, vault.command().ItemCmd(), "save", "api_token", "github", "--set", "service=GitHub", "--from-file", "-"); err != nil {
+		t.Fatalf("Неожиданная ошибка: %v", err)
+	}
+	if got := vault.records["github"]; got["token"] != "ghp_x" || got["service"] != "GitHub" {
+		t.Errorf("Флаги должны дополнять файл и иметь приоритет, получено %v", got)
+	}
+
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{"неизвестный тип", []string{"save", "passport", "bad"}},
+		{"секрет во флаге", []string{"save", "api_token", "bad", "--set", "service=GitHub", "--set", "token=ghp_x"}},
+		{"неизвестное поле", []string{"save", "api_token", "bad", "--set", "owner=alice"}},
+		{"флаг без значения", []string{"save", "api_token", "bad", "--set", "service"}},
+		{"нет обязательного поля", []string{"save", "api_token", "bad", "--set", "service=GitHub"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			withStdin(t, "", false)
+			if _, err := runWithOutput(t, vault.command().ItemCmd(), tt.args...); ExitCode(err) != ExitUsage {
+				t.Errorf("Ожидалась ошибка использования, получено: %v", err)
+			}
+		})
+	}
+
+	withStdin(t, `{"token":"ghp_x"}`, false)
+	_, err := runWithOutput(t, vault.command().ItemCmd(), "save", "api_token", "bad", "--set", "service=GitHub", "--set", "expires_at=31.01.2027", "--from-file", "-")
+	if ExitCode(err) != ExitUsage {
+		t.Errorf("Неверная дата должна быть ошибкой использования, получено: %v", err)
+	}
+	if _, ok := vault.items["bad"]; ok {
+		t.Error("Неверная запись не должна сохраняться")
+	}
+}
+
+// TestCommand_ItemGetCmd тестирует порядок полей в JSON, вывод срока действия и отсутствие записи
+func TestCommand_ItemGetCmd(t *testing.T) {
+	oldClock := itemClock
+	defer func() { itemClock = oldClock }()
+	itemClock = func() time.Time { return time.Date(2027, 1, 21, 15, 0, 0, 0, time.UTC) }
+
+	vault := newFakeVault()
+	vault.command().clientUseCase.SaveItem(domain.UserDataTypeAPIToken, "github",
+		domain.ItemData{"token": "ghp_x", "service": "GitHub", "expires_at": "2027-01-31"}, "ci")
+
+	out, err := runWithOutput(t, vault.command().ItemCmd(), "get", "api_token", "github", "--output", "json")
+	if err != nil {
+		t.Fatalf("Неожиданная ошибка: %v", err)
+	}
+	want := `{
+  "type": "api_token",
+  "label": "github",
+  "service": "GitHub",
+  "token": "ghp_x",
+  "url": "",
+  "scopes": "",
+  "expires_at": "2027-01-31",
+  "metadata": "ci"
+}
+`
+	if out != want {
+		t.Errorf("Ожидался вывод\n%s\nполучено\n%s", want, out)
+	}
+
+	out, err = runWithOutput(t, vault.command().ItemCmd(), "get", "api_token", "github")
+	if err != nil || !strings.Contains(out, "Токен: ghp_x") || !strings.Contains(out, "Срок действия: осталось 10 дн.") {
+		t.Errorf("Неверный вывод таблицы: %q, %v", out, err)
+	}
+
+	if _, err := runWithOutput(t, vault.command().ItemCmd(), "get", "wifi", "github"); ExitCode(err) != ExitNotFound {
+		t.Errorf("Ожидалась ошибка отсутствия записи, получено: %v", err)
+	}
+}
+
+// TestCommand_ItemTemplateCmd тестирует сохранение шаблона, работу с записями пользовательского типа и отказ
+// от неверной схемы и удаления используемого шаблона
+func TestCommand_ItemTemplateCmd(t *testing.T) {
+	vault := newFakeVault()
+
+	withStdin(t, `{"title": "Подключение к БД", "type": "object", "properties": {"host": {"type": "string", "title": "Хост"},
+		"password": {"type": "string", "title": "Пароль", "writeOnly": true}}, "required": ["host", "password"]}`, false)
+	if _, err := runWithOutput(t, vault.command().ItemCmd(), "template", "save", "db_profile", "--from-file", "-"); err != nil {
+		t.Fatalf("Неожиданная ошибка: %v", err)
+	}
+
+	out, err := runWithOutput(t, vault.command().ItemCmd(), "types", "--output", "json")
+	if err != nil || !strings.Contains(out, `"name": "db_profile"`) || !strings.Contains(out, `"custom": true`) {
+		t.Errorf("Пользовательский тип должен выводиться в списке типов: %s, %v", out, err)
+	}
+
+	withStdin(t, `{"password":"s3cret"}`, false)
+	if _, err := runWithOutput(t, vault.command().ItemCmd(), "save", "db_profile", "prod", "--set", "host=db.local", "--from-file", "-"); err != nil {
+		t.Fatalf("Неожиданная ошибка: %v", err)
+	}
+	out, err = runWithOutput(t, vault.command().ItemCmd(), "get", "db_profile", "prod", "--field", "password")
+	if err != nil || out != "s3cret" {
+		t.Errorf("Ожидалось значение 's3cret', получено %q, %v", out, err)
+	}
+
+	// Ссылка с префиксом пользовательского типа выбирает основной секрет записи
+	ref, err := parseSecretRef("db_profile/prod")
+	if err != nil {
+		t.Fatalf("Неожиданная ошибка: %v", err)
+	}
+	if field, value, err := vault.command().newSecretResolver().resolve(ref); err != nil || field != "password" || value != "s3cret" {
+		t.Errorf("Ожидалось поле password со значением 's3cret', получено %s=%q, %v", field, value, err)
+	}
+
+	tests := []struct {
+		name string
+		args []string
+		want int
+	}{
+		{"нет обязательного поля", []string{"save", "db_profile", "bad", "--set", "host=db.local"}, ExitUsage},
+		{"имя встроенного типа", []string{"template", "save", "wifi", "--from-file", "-"}, ExitUsage},
+		{"без схемы", []string{"template", "save", "other"}, ExitUsage},
+		{"шаблон используется", []string{"template", "delete", "db_profile"}, ExitError},
+		{"нет шаблона", []string{"template", "get", "passport"}, ExitNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			withStdin(t, `{"type": "object", "properties": {"ssid": {"type": "string"}}}`, false)
+			if _, err := runWithOutput(t, vault.command().ItemCmd(), tt.args...); ExitCode(err) != tt.want {
+				t.Errorf("Ожидался код завершения %d, получено: %v", tt.want, err)
+			}
+		})
+	}
+
+	vault.command().clientUseCase.DeleteItem("db_profile", "prod")
+	if _, err := runWithOutput(t, vault.command().ItemCmd(), "template", "delete", "db_profile"); err != nil {
+		t.Fatalf("Неожиданная ошибка: %v", err)
+	}
+	if _, err := runWithOutput(t, vault.command().ItemCmd(), "get", "db_profile", "prod"); ExitCode(err) != ExitUsage {
+		t.Errorf("Тип удаленного шаблона должен быть неизвестен, получено: %v", err)
+	}
+}
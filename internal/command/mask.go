@@ -0,0 +1,104 @@
+package command
+
+import (
+	"bytes"
+	"io"
+	"sort"
+)
+
+const (
+	// minMaskLength - более короткие значения не маскируются, иначе маскировались бы случайные совпадения в выводе
+	minMaskLength = 4
+	// maskReplacement заменяет значение секрета в выводе
+	maskReplacement = "******"
+)
+
+// maskWriter заменяет значения секретов в выводе процесса. Секрет может прийти по частям в разных вызовах Write,
+// поэтому конец данных, совпадающий с началом секрета, придерживается до следующей записи или Flush
+type maskWriter struct {
+	w       io.Writer
+	secrets [][]byte
+	pending []byte
+}
+
+// newMaskWriter создает writer, маскирующий secrets в выводе w
+func newMaskWriter(w io.Writer, secrets []string) *maskWriter {
+	m := &maskWriter{w: w}
+	for _, secret := range secrets {
+		if len(secret) >= minMaskLength {
+			m.secrets = append(m.secrets, []byte(secret))
+		}
+	}
+	// Более длинный секрет маскируется целиком, даже если начинается с более короткого
+	sort.Slice(m.secrets, func(i, j int) bool {
+		return len(m.secrets[i]) > len(m.secrets[j])
+	})
+	return m
+}
+
+func (m *maskWriter) Write(p []byte) (int, error) {
+	if len(m.secrets) == 0 {
+		return m.w.Write(p)
+	}
+
+	m.pending = append(m.pending, p...)
+	out, rest := m.mask(m.pending, false)
+	m.pending = append(m.pending[:0], rest...)
+	if len(out) > 0 {
+		if _, err := m.w.Write(out); err != nil {
+			return 0, err
+		}
+	}
+	return len(p), nil
+}
+
+// Flush выводит придержанный конец данных
+func (m *maskWriter) Flush() error {
+	if len(m.pending) == 0 {
+		return nil
+	}
+	out, _ := m.mask(m.pending, true)
+	m.pending = m.pending[:0]
+	_, err := m.w.Write(out)
+	return err
+}
+
+// mask заменяет секреты в data и возвращает результат и конец data, который может оказаться началом секрета.
+// При final конец не придерживается
+func (m *maskWriter) mask(data []byte, final bool) ([]byte, []byte) {
+	out := make([]byte, 0, len(data))
+	for i := 0; i < len(data); {
+		// Начало более длинного секрета придерживается, даже если уже совпал более короткий
+		if !final && m.partial(data[i:]) {
+			return out, data[i:]
+		}
+		if secret := m.match(data[i:]); secret != nil {
+			out = append(out, maskReplacement...)
+			i += len(secret)
+			continue
+		}
+		out = append(out, data[i])
+		i++
+	}
+	return out, nil
+}
+
+// match возвращает секрет, с которого начинается data
+func (m *maskWriter) match(data []byte) []byte {
+	for _, secret := range m.secrets {
+		if bytes.HasPrefix(data, secret) {
+			return secret
+		}
+	}
+	return nil
+}
+
+// partial сообщает, является ли data началом какого-либо секрета
+func (m *maskWriter) partial(data []byte) bool {
+	for _, secret := range m.secrets {
+		if len(data) < len(secret) && bytes.HasPrefix(secret, data) {
+			return true
+		}
+	}
+	return false
+}
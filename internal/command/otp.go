@@ -0,0 +1,77 @@
+package command
+
+import (
+	"fmt"
+	"github.com/SmirnovND/gophkeeper/internal/domain"
+	"github.com/SmirnovND/gophkeeper/internal/totp"
+	"github.com/spf13/cobra"
+	"net/http"
+	"time"
+)
+
+// otpItem - текущий код TOTP учетных данных
+type otpItem struct {
+	Label     string `json:"label"`
+	Code      string `json:"code"`
+	Remaining int    `json:"remaining"`
+	Period    int    `json:"period"`
+}
+
+// otpClock возвращает время, для которого вычисляется код TOTP; в тестах подменяется
+var otpClock = time.Now
+
+// OTPCmd создает команду вывода текущего кода TOTP учетных данных
+func (c *Command) OTPCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "otp [label]",
+		Short: "Текущий код TOTP учетных данных",
+		Long: "Выводит текущий одноразовый код второго фактора учетных данных и время до его смены.\n" +
+			"Ключ TOTP сохраняется флагом --totp команды save-credential, например:\n" +
+			"passcli save-credential github --login alice --totp 'otpauth://totp/GitHub:alice?secret=JBSWY3DPEHPK3PXP&issuer=GitHub'\n" +
+			"Только код без оформления выводится флагом --field code, например: passcli otp github --field code | pbcopy",
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out, err := newPrinter(cmd)
+			if err != nil {
+				return fail("Ошибка при получении кода:", err)
+			}
+			label, err := promptLabel(cmd, args, newInput(), "Введите уникальное название (label) учетных данных:")
+			if err != nil {
+				return fail("Ошибка при получении кода:", err)
+			}
+
+			credentialData, _, err := c.clientUseCase.GetCredential(label)
+			if err != nil {
+				return fail("Ошибка при получении кода:", err)
+			}
+			if credentialData.TOTP == "" {
+				return fail("Ошибка при получении кода:",
+					&domain.Error{Message: fmt.Sprintf("у учетных данных '%s' нет ключа TOTP", label), CodeValue: http.StatusNotFound})
+			}
+			key, err := totp.Parse(credentialData.TOTP)
+			if err != nil {
+				return fail("Ошибка при получении кода:", err)
+			}
+
+			moment := otpClock()
+			item := &otpItem{Label: label, Code: key.Code(moment), Remaining: remainingSeconds(key, moment), Period: key.Period}
+			err = out.print(item, func() {
+				fmt.Println(item.Code)
+				fmt.Printf("Код сменится через %d с\n", item.Remaining)
+			})
+			if err != nil {
+				return fail("Ошибка при получении кода:", err)
+			}
+			return nil
+		},
+	}
+
+	addLabelFlag(cmd)
+
+	return cmd
+}
+
+// remainingSeconds возвращает число секунд до смены кода, неполная секунда считается целой
+func remainingSeconds(key *totp.Key, moment time.Time) int {
+	return int((key.Remaining(moment) + time.Second - 1) / time.Second)
+}
@@ -0,0 +1,66 @@
+package command
+
+import (
+	"github.com/SmirnovND/gophkeeper/internal/domain"
+	"strings"
+	"testing"
+	"time"
+)
+
+// otpURI - ключ TOTP из тестовых векторов RFC 6238 с кодами из восьми цифр
+const otpURI = "otpauth://totp/Test:alice?secret=GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ&digits=8"
+
+// TestCommand_OTPCmd тестирует вывод кода TOTP и отказ для учетных данных без ключа
+func TestCommand_OTPCmd(t *testing.T) {
+	oldClock := otpClock
+	otpClock = func() time.Time { return time.Unix(1111111109, 0) }
+	t.Cleanup(func() { otpClock = oldClock })
+
+	vault := newFakeVault()
+	vault.command().clientUseCase.SaveCredential("github", &domain.CredentialData{Login: "alice", Password: "s3cret", TOTP: otpURI}, "")
+	vault.command().clientUseCase.SaveCredential("plain", &domain.CredentialData{Login: "bob", Password: "pass"}, "")
+
+	out, err := runWithOutput(t, vault.command().OTPCmd(), "github")
+	if err != nil {
+		t.Fatalf("Неожиданная ошибка: %v", err)
+	}
+	if out != "07081804\nКод сменится через 1 с\n" {
+		t.Errorf("Неверный вывод: %q", out)
+	}
+
+	out, err = runWithOutput(t, vault.command().OTPCmd(), "github", "--field", "code")
+	if err != nil || out != "07081804" {
+		t.Errorf("Ожидался только код, получено %q, %v", out, err)
+	}
+
+	out, err = runWithOutput(t, vault.command().OTPCmd(), "github", "-o", "json")
+	if err != nil || !strings.Contains(out, `"remaining": 1`) || !strings.Contains(out, `"period": 30`) {
+		t.Errorf("Неверный вывод json: %q, %v", out, err)
+	}
+
+	if _, err := runWithOutput(t, vault.command().OTPCmd(), "plain"); ExitCode(err) != ExitNotFound {
+		t.Errorf("Ожидалась ошибка отсутствия ключа TOTP, получено: %v", err)
+	}
+}
+
+// TestCommand_SaveCredentialCmd_TOTP тестирует сохранение ключа TOTP и отказ от неверного URI до запроса пароля
+func TestCommand_SaveCredentialCmd_TOTP(t *testing.T) {
+	vault := newFakeVault()
+
+	withStdin(t, "s3cret\n", false)
+	if _, err := runWithOutput(t, vault.command().SaveCredentialCmd(), "github", "--login", "alice", "--password-stdin", "--totp", otpURI); err != nil {
+		t.Fatalf("Неожиданная ошибка: %v", err)
+	}
+	if got := vault.credentials["github"]; got == nil || got.TOTP != otpURI || got.Password != "s3cret" {
+		t.Errorf("Учетные данные сохранены неверно: %+v", got)
+	}
+
+	withStdin(t, "s3cret\n", false)
+	_, err := runWithOutput(t, vault.command().SaveCredentialCmd(), "other", "--login", "alice", "--password-stdin", "--totp", "otpauth://hotp/x?secret=AAAA")
+	if ExitCode(err) != ExitUsage {
+		t.Errorf("Ожидалась ошибка неверного ключа TOTP, получено: %v", err)
+	}
+	if _, ok := vault.items["other"]; ok {
+		t.Error("Учетные данные с неверным ключом TOTP не должны сохраняться")
+	}
+}
@@ -0,0 +1,296 @@
+package command
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/SmirnovND/gophkeeper/internal/domain"
+	"github.com/mattn/go-isatty"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Форматы вывода, задаваемые флагом --output
+const (
+	outputTable = "table"
+	outputJSON  = "json"
+	outputYAML  = "yaml"
+	outputEnv   = "env"
+)
+
+// stdoutIsTerminal сообщает, подключен ли stdout к терминалу
+var stdoutIsTerminal = func() bool {
+	fd := os.Stdout.Fd()
+	return isatty.IsTerminal(fd) || isatty.IsCygwinTerminal(fd)
+}
+
+// envKeyReplacer заменяет символы, недопустимые в имени переменной окружения
+var envKeyReplacer = regexp.MustCompile(`[^A-Z0-9_]`)
+
+// Схемы записей в форматах json, yaml и env. Поля и их порядок являются стабильным интерфейсом для скриптов
+type textItem struct {
+	Type     string `json:"type"`
+	Label    string `json:"label"`
+	Content  string `json:"content"`
+	Metadata string `json:"metadata"`
+}
+
+type cardItem struct {
+	Type       string `json:"type"`
+	Label      string `json:"label"`
+	Number     string `json:"number"`
+	Holder     string `json:"holder"`
+	ExpiryDate string `json:"expiry_date"`
+	CVV        string `json:"cvv"`
+	Metadata   string `json:"metadata"`
+}
+
+type credentialItem struct {
+	Type     string                 `json:"type"`
+	Label    string                 `json:"label"`
+	Login    string                 `json:"login"`
+	Password string                 `json:"password"`
+	TOTP     string                 `json:"totp,omitempty"`
+	URIs     []domain.CredentialURI `json:"uris,omitempty"`
+	Fields   customFields           `json:"fields,omitempty"`
+	Metadata string                 `json:"metadata"`
+	// History выводится только по запросу, чтобы прежние пароли не попадали в вывод случайно
+	History []domain.PasswordChange `json:"password_history,omitempty"`
+}
+
+// newCredentialItem возвращает схему вывода учетных данных
+func newCredentialItem(label string, data *domain.CredentialData, metadata string) *credentialItem {
+	return &credentialItem{Type: domain.UserDataTypeCredential, Label: label, Login: data.Login, Password: data.Password,
+		TOTP: data.TOTP, URIs: data.URIs, Fields: data.Fields, Metadata: metadata}
+}
+
+// customFields выводит дополнительные поля учетных данных объектом имя - значение в порядке полей,
+// чтобы на поле можно было сослаться как на fields.pin
+type customFields []domain.CustomField
+
+func (f customFields) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, field := range f {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		name, err := json.Marshal(field.Name)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(field.Value)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(name)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+type sshKeyItem struct {
+	Type        string `json:"type"`
+	Label       string `json:"label"`
+	PrivateKey  string `json:"private_key"`
+	PublicKey   string `json:"public_key"`
+	Fingerprint string `json:"fingerprint"`
+	Comment     string `json:"comment"`
+	Metadata    string `json:"metadata"`
+}
+
+// AddOutputFlags добавляет корневой команде глобальные флаги формата вывода
+func AddOutputFlags(root *cobra.Command) {
+	root.PersistentFlags().StringP("output", "o", outputTable, "Формат вывода: table, json, yaml или env")
+	root.PersistentFlags().String("field", "", "Вывести только значение поля без оформления, например password")
+}
+
+// printer выводит результат команды в формате, выбранном флагами --output и --field
+type printer struct {
+	format string
+	field  string
+}
+
+// newPrinter создает printer по флагам команды. Без глобальных флагов используется формат table
+func newPrinter(cmd *cobra.Command) (*printer, error) {
+	format, _ := cmd.Flags().GetString("output")
+	field, _ := cmd.Flags().GetString("field")
+	if format == "" {
+		format = outputTable
+	}
+
+	if err := checkFormat(format); err != nil {
+		return nil, err
+	}
+	return &printer{format: format, field: field}, nil
+}
+
+// checkFormat проверяет название формата вывода
+func checkFormat(format string) error {
+	switch format {
+	case outputTable, outputJSON, outputYAML, outputEnv:
+		return nil
+	default:
+		return fmt.Errorf("%w: неизвестный формат вывода '%s', допустимы table, json, yaml и env", errUsage, format)
+	}
+}
+
+// print выводит запись или список записей v. Формат table выводится функцией table,
+// остальные форматы строятся по JSON-представлению v
+func (p *printer) print(v interface{}, table func()) error {
+	if p.format == outputTable && p.field == "" {
+		table()
+		return nil
+	}
+
+	node, err := toNode(v)
+	if err != nil {
+		return err
+	}
+
+	if p.field != "" {
+		return p.printField(node)
+	}
+
+	switch p.format {
+	case outputJSON:
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		encoder.SetEscapeHTML(false)
+		return encoder.Encode(v)
+	case outputYAML:
+		data, err := yaml.Marshal(node)
+		if err != nil {
+			return err
+		}
+		fmt.Print(string(data))
+	case outputEnv:
+		if node.Kind != yaml.MappingNode {
+			return fmt.Errorf("%w: формат env поддерживается только для одной записи", errUsage)
+		}
+		for _, field := range flatten(node, nil) {
+			fmt.Printf("%s=%s\n", envKey(field.path), shellQuote(field.value))
+		}
+	}
+	return nil
+}
+
+// printField выводит значение поля --field без оформления. Для списка значения выводятся по одному в строке,
+// для одной записи перевод строки добавляется только на терминале, чтобы значение можно было подставить через $(...)
+func (p *printer) printField(node *yaml.Node) error {
+	if node.Kind == yaml.SequenceNode {
+		for _, item := range node.Content {
+			value, err := fieldValue(item, p.field)
+			if err != nil {
+				return err
+			}
+			fmt.Println(value)
+		}
+		return nil
+	}
+
+	value, err := fieldValue(node, p.field)
+	if err != nil {
+		return err
+	}
+	fmt.Print(value)
+	if stdoutIsTerminal() {
+		fmt.Println()
+	}
+	return nil
+}
+
+// flatField - значение вложенного поля записи вместе с путем к нему
+type flatField struct {
+	path  []string
+	value string
+}
+
+// toNode возвращает JSON-представление v в виде узла YAML, сохраняющего порядок полей
+func toNode(v interface{}) (*yaml.Node, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var document yaml.Node
+	if err := yaml.Unmarshal(data, &document); err != nil {
+		return nil, err
+	}
+
+	node := document.Content[0]
+	resetStyle(node)
+	return node, nil
+}
+
+// resetStyle убирает стиль JSON (фигурные скобки и кавычки), чтобы узел выводился в обычном блочном стиле YAML
+func resetStyle(node *yaml.Node) {
+	node.Style = 0
+	for _, child := range node.Content {
+		resetStyle(child)
+	}
+}
+
+// flatten возвращает скалярные поля записи, вложенные объекты разворачиваются в пути,
+// а элементы списков - в пути с номером элемента, например uris.0.uri
+func flatten(node *yaml.Node, prefix []string) []flatField {
+	var fields []flatField
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		path := append(append([]string{}, prefix...), node.Content[i].Value)
+		fields = append(fields, flattenValue(node.Content[i+1], path)...)
+	}
+	return fields
+}
+
+// flattenValue возвращает скалярные поля значения value, расположенного по пути path
+func flattenValue(value *yaml.Node, path []string) []flatField {
+	switch value.Kind {
+	case yaml.MappingNode:
+		return flatten(value, path)
+	case yaml.SequenceNode:
+		var fields []flatField
+		for i, item := range value.Content {
+			fields = append(fields, flattenValue(item, append(append([]string{}, path...), strconv.Itoa(i)))...)
+		}
+		return fields
+	default:
+		return []flatField{{path: path, value: scalarValue(value)}}
+	}
+}
+
+// fieldValue возвращает значение поля записи, вложенные поля задаются через точку, например quota.max_items
+func fieldValue(node *yaml.Node, field string) (string, error) {
+	var names []string
+	for _, flat := range flatten(node, nil) {
+		name := strings.Join(flat.path, ".")
+		if strings.EqualFold(name, field) {
+			return flat.value, nil
+		}
+		names = append(names, name)
+	}
+	return "", fmt.Errorf("%w: поле '%s' не найдено, доступные поля: %s", errUsage, field, strings.Join(names, ", "))
+}
+
+// scalarValue возвращает значение скалярного узла, null выводится как пустая строка
+func scalarValue(node *yaml.Node) string {
+	if node.Kind != yaml.ScalarNode || node.Tag == "!!null" {
+		return ""
+	}
+	return node.Value
+}
+
+// envKey формирует имя переменной окружения из пути к полю
+func envKey(path []string) string {
+	return envKeyReplacer.ReplaceAllString(strings.ToUpper(strings.Join(path, "_")), "_")
+}
+
+// shellQuote заключает значение в одинарные кавычки, чтобы вывод env можно было выполнить через eval
+func shellQuote(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
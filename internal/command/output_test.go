@@ -0,0 +1,146 @@
+package command
+
+import (
+	"errors"
+	"github.com/SmirnovND/gophkeeper/internal/domain"
+	"github.com/spf13/cobra"
+	"io"
+	"os"
+	"strings"
+	"testing"
+)
+
+// runWithOutput выполняет команду с глобальными флагами вывода и возвращает ее stdout
+func runWithOutput(t *testing.T, cmd *cobra.Command, args ...string) (string, error) {
+	root := &cobra.Command{Use: "passcli", SilenceErrors: true, SilenceUsage: true}
+	AddOutputFlags(root)
+	root.AddCommand(cmd)
+	return runRoot(t, root, append([]string{cmd.Name()}, args...)...)
+}
+
+// runRoot выполняет корневую команду с аргументами args и возвращает ее stdout
+func runRoot(t *testing.T, root *cobra.Command, args ...string) (string, error) {
+	root.SetArgs(args)
+
+	oldStdout, oldStderr := os.Stdout, os.Stderr
+	r, w, _ := os.Pipe()
+	devNull, _ := os.Open(os.DevNull)
+	os.Stdout, os.Stderr = w, devNull
+	defer func() {
+		os.Stdout, os.Stderr = oldStdout, oldStderr
+		devNull.Close()
+	}()
+
+	err := root.Execute()
+	w.Close()
+	out, _ := io.ReadAll(r)
+	return string(out), err
+}
+
+// newCredentialCommand возвращает команду get-credential с фиксированными учетными данными
+func newCredentialCommand() *cobra.Command {
+	mockClientUseCase := &MockDataClientUseCase{
+		GetCredentialFunc: func(label string) (*domain.CredentialData, string, error) {
+			return &domain.CredentialData{Login: "alice", Password: "it's secret"}, "рабочий", nil
+		},
+	}
+	cmd := &Command{clientUseCase: mockClientUseCase}
+	return cmd.GetCredentialCmd()
+}
+
+// TestOutput_Formats тестирует вывод записи в форматах json, yaml и env
+func TestOutput_Formats(t *testing.T) {
+	tests := []struct {
+		name   string
+		format string
+		want   string
+	}{
+		{
+			name:   "JSON",
+			format: "json",
+			want: "{\n  \"type\": \"credential\",\n  \"label\": \"bank\",\n  \"login\": \"alice\",\n" +
+				"  \"password\": \"it's secret\",\n  \"metadata\": \"рабочий\"\n}\n",
+		},
+		{
+			name:   "YAML",
+			format: "yaml",
+			want:   "type: credential\nlabel: bank\nlogin: alice\npassword: it's secret\nmetadata: рабочий\n",
+		},
+		{
+			name:   "Env",
+			format: "env",
+			want:   "TYPE='credential'\nLABEL='bank'\nLOGIN='alice'\nPASSWORD='it'\\''s secret'\nMETADATA='рабочий'\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := runWithOutput(t, newCredentialCommand(), "bank", "--output", tt.format)
+			if err != nil {
+				t.Fatalf("Неожиданная ошибка: %v", err)
+			}
+			if out != tt.want {
+				t.Errorf("Ожидался вывод:\n%s\nполучен:\n%s", tt.want, out)
+			}
+		})
+	}
+}
+
+// TestOutput_Field тестирует вывод значения одного поля без перевода строки вне терминала
+func TestOutput_Field(t *testing.T) {
+	oldIsTerminal := stdoutIsTerminal
+	defer func() { stdoutIsTerminal = oldIsTerminal }()
+	stdoutIsTerminal = func() bool { return false }
+
+	out, err := runWithOutput(t, newCredentialCommand(), "bank", "--field", "password")
+	if err != nil {
+		t.Fatalf("Неожиданная ошибка: %v", err)
+	}
+	if out != "it's secret" {
+		t.Errorf("Ожидалось значение пароля без оформления, получено '%s'", out)
+	}
+}
+
+// TestOutput_Errors тестирует неверные значения --output и --field
+func TestOutput_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{name: "Unknown format", args: []string{"bank", "--output", "xml"}},
+		{name: "Unknown field", args: []string{"bank", "--field", "cvv"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := runWithOutput(t, newCredentialCommand(), tt.args...)
+			if !errors.Is(err, errUsage) {
+				t.Errorf("Ожидалась ошибка неверных аргументов, получена %v", err)
+			}
+			if ExitCode(err) != ExitUsage {
+				t.Errorf("Ожидался код завершения %d, получен %d", ExitUsage, ExitCode(err))
+			}
+			if out != "" {
+				t.Errorf("Ожидался пустой вывод, получено '%s'", out)
+			}
+		})
+	}
+}
+
+// TestOutput_PathFlags тестирует, что у download и inject флаги --output и -o задают путь результата,
+// а не принимаются глобальным флагом формата вывода
+func TestOutput_PathFlags(t *testing.T) {
+	c := &Command{}
+	for _, cmd := range []*cobra.Command{c.DownloadCmd(), c.InjectCmd()} {
+		root := &cobra.Command{Use: "passcli"}
+		AddOutputFlags(root)
+		root.AddCommand(cmd)
+
+		if flag := cmd.LocalNonPersistentFlags().Lookup("output"); flag == nil || !strings.Contains(flag.Usage, "stdout") {
+			t.Errorf("У команды %s флаг --output должен задавать путь результата", cmd.Name())
+		}
+		if flag := cmd.LocalNonPersistentFlags().ShorthandLookup("o"); flag == nil || flag.Name != "output" {
+			t.Errorf("У команды %s флаг -o должен задавать путь результата", cmd.Name())
+		}
+	}
+}
@@ -0,0 +1,152 @@
+package command
+
+import (
+	"fmt"
+	"github.com/SmirnovND/gophkeeper/internal/domain"
+	"github.com/spf13/cobra"
+)
+
+// AddProfileFlags добавляет корневой команде глобальный флаг выбора профиля
+func AddProfileFlags(root *cobra.Command) {
+	root.PersistentFlags().String("profile", "", "Профиль подключения к серверу, по умолчанию текущий или PASSCLI_PROFILE")
+}
+
+// SelectProfile выбирает профиль по флагу --profile перед выполнением команды.
+// Формат вывода профиля используется, если флаг --output не задан
+func (c *Command) SelectProfile(cmd *cobra.Command, args []string) error {
+	profile, _ := cmd.Flags().GetString("profile")
+	if err := c.config.Select(profile); err != nil {
+		return fail("Ошибка при выборе профиля:", err)
+	}
+
+	output := cmd.Root().PersistentFlags().Lookup("output")
+	if format := c.config.GetOutput(); output != nil && !output.Changed && format != "" {
+		if err := output.Value.Set(format); err != nil {
+			return fail("Ошибка при выборе профиля:", err)
+		}
+	}
+	return nil
+}
+
+// ProfileCmd создает команду управления профилями с подкомандами add, use, list и remove
+func (c *Command) ProfileCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "profile",
+		Short: "Управление профилями подключения к серверу",
+		Long: "Профиль хранит адрес сервера, настройки TLS, формат вывода по умолчанию и собственный токен авторизации.\n" +
+			"Профиль выбирается флагом --profile, переменной PASSCLI_PROFILE или командой passcli profile use",
+		// Профилями можно управлять, даже если выбранный профиль удален
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			if err := c.config.Load(); err != nil {
+				return fail("Ошибка при чтении конфигурации:", err)
+			}
+			return nil
+		},
+	}
+
+	addCmd := &cobra.Command{
+		Use:   "add <name>",
+		Short: "Добавление профиля",
+		Long: "Добавление профиля, например:\n" +
+			"passcli profile add work --server https://vault.example.com --ca-file /etc/ssl/work-ca.pem --use",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			server, _ := cmd.Flags().GetString("server")
+			caFile, _ := cmd.Flags().GetString("ca-file")
+			insecure, _ := cmd.Flags().GetBool("insecure-skip-verify")
+			output, _ := cmd.Flags().GetString("output")
+			use, _ := cmd.Flags().GetBool("use")
+
+			if output != "" {
+				if err := checkFormat(output); err != nil {
+					return fail("Ошибка при добавлении профиля:", err)
+				}
+			}
+
+			profile := domain.Profile{
+				Name:   args[0],
+				Server: server,
+				TLS:    domain.ProfileTLS{CAFile: caFile, InsecureSkipVerify: insecure},
+				Output: output,
+			}
+			if err := c.config.AddProfile(profile); err != nil {
+				return fail("Ошибка при добавлении профиля:", err)
+			}
+			if use {
+				if err := c.config.UseProfile(profile.Name); err != nil {
+					return fail("Ошибка при выборе профиля:", err)
+				}
+			}
+
+			fmt.Printf("Профиль %s добавлен\n", profile.Name)
+			return nil
+		},
+	}
+	addCmd.Flags().String("server", "", "Адрес сервера: host:port или URL со схемой http или https")
+	addCmd.Flags().String("ca-file", "", "PEM-файл с сертификатами удостоверяющих центров сервера")
+	addCmd.Flags().Bool("insecure-skip-verify", false, "Не проверять сертификат сервера, только для тестовых стендов")
+	addCmd.Flags().StringP("output", "o", "", "Формат вывода по умолчанию: table, json, yaml или env")
+	addCmd.Flags().Bool("use", false, "Сделать профиль текущим")
+	_ = addCmd.MarkFlagRequired("server")
+	cmd.AddCommand(addCmd)
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "use <name>",
+		Short: "Выбор текущего профиля",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := c.config.UseProfile(args[0]); err != nil {
+				return fail("Ошибка при выборе профиля:", err)
+			}
+
+			fmt.Printf("Текущий профиль: %s\n", args[0])
+			return nil
+		},
+	})
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "list",
+		Short: "Список профилей",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out, err := newPrinter(cmd)
+			if err != nil {
+				return fail("Ошибка при получении списка профилей:", err)
+			}
+
+			profiles := c.config.ListProfiles()
+			err = out.print(profiles, func() {
+				fmt.Println("\nПрофили:")
+				fmt.Println("----------------------")
+				for _, profile := range profiles {
+					marker := " "
+					if profile.Current {
+						marker = "*"
+					}
+					fmt.Printf("%s %s\t%s\n", marker, profile.Name, profile.Server)
+				}
+				fmt.Println("----------------------")
+			})
+			if err != nil {
+				return fail("Ошибка при получении списка профилей:", err)
+			}
+			return nil
+		},
+	})
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "remove <name>",
+		Short: "Удаление профиля и его токена",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := c.config.RemoveProfile(args[0]); err != nil {
+				return fail("Ошибка при удалении профиля:", err)
+			}
+
+			fmt.Printf("Профиль %s удален\n", args[0])
+			return nil
+		},
+	})
+
+	return cmd
+}
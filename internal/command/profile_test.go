@@ -0,0 +1,69 @@
+package command
+
+import (
+	config "github.com/SmirnovND/gophkeeper/internal/config/client"
+	"github.com/SmirnovND/gophkeeper/internal/domain"
+	"github.com/spf13/cobra"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// newProfileRoot создает корневую команду с выбором профиля, как в passcli.
+// Значения флагов cobra сохраняются между запусками, поэтому каждый запуск использует новую команду
+func newProfileRoot() *cobra.Command {
+	cmd := &Command{
+		clientUseCase: &MockDataClientUseCase{
+			GetCredentialFunc: func(label string) (*domain.CredentialData, string, error) {
+				return &domain.CredentialData{Login: "alice", Password: "secret"}, "", nil
+			},
+		},
+		config: config.NewConfig("127.0.0.1:8080"),
+	}
+
+	root := &cobra.Command{Use: "passcli", SilenceErrors: true, SilenceUsage: true}
+	AddOutputFlags(root)
+	AddProfileFlags(root)
+	root.PersistentPreRunE = cmd.SelectProfile
+	root.AddCommand(cmd.GetCredentialCmd(), cmd.ProfileCmd())
+	return root
+}
+
+// TestCommand_Profile тестирует добавление профиля и применение его формата вывода
+func TestCommand_Profile(t *testing.T) {
+	t.Setenv(config.EnvConfig, filepath.Join(t.TempDir(), "config.yaml"))
+	t.Setenv(config.EnvProfile, "")
+	t.Setenv(config.EnvOutput, "")
+
+	if _, err := runRoot(t, newProfileRoot(), "profile", "add", "work", "--server", "https://vault.example.com", "--output", "json"); err != nil {
+		t.Fatalf("Неожиданная ошибка: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{name: "Default profile", args: []string{"get-credential", "bank"}, want: "Учетные данные:"},
+		{name: "Profile output", args: []string{"get-credential", "bank", "--profile", "work"}, want: `"login": "alice"`},
+		{name: "Flag overrides profile output", args: []string{"get-credential", "bank", "--profile", "work", "-o", "yaml"}, want: "login: alice"},
+		{name: "Profile list", args: []string{"profile", "list", "-o", "json"}, want: `"server": "https://vault.example.com"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := runRoot(t, newProfileRoot(), tt.args...)
+			if err != nil {
+				t.Fatalf("Неожиданная ошибка: %v", err)
+			}
+			if !strings.Contains(out, tt.want) {
+				t.Errorf("Ожидался вывод, содержащий '%s', получено:\n%s", tt.want, out)
+			}
+		})
+	}
+
+	_, err := runRoot(t, newProfileRoot(), "get-credential", "bank", "--profile", "missing")
+	if ExitCode(err) != ExitUsage {
+		t.Errorf("Для отсутствующего профиля ожидался код завершения %d, получен %d", ExitUsage, ExitCode(err))
+	}
+}
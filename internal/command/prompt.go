@@ -0,0 +1,213 @@
+package command
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+const (
+	// maxSecretLength - максимальная длина пароля в байтах
+	maxSecretLength = 1024
+	// maxCVVLength - максимальная длина CVV кода
+	maxCVVLength = 4
+	// maxSecretAttempts - число попыток ввода секрета, совпадающего с подтверждением
+	maxSecretAttempts = 3
+)
+
+// disableEcho отключает отображение ввода на терминале и возвращает функцию восстановления режима
+var disableEcho = terminalDisableEcho
+
+// secretField описывает запрашиваемое секретное значение
+type secretField struct {
+	// prompt - запрос значения
+	prompt string
+	// confirm - запрос повторного ввода, пустой запрос отключает подтверждение
+	confirm string
+	// hint подсказывает, как передать значение без запроса
+	hint string
+	// maxLength - максимальная длина значения в байтах
+	maxLength int
+}
+
+// Запрашиваемые командами секреты
+var (
+	loginPasswordField = secretField{
+		prompt:    "Введите пароль:",
+		hint:      "пароль передается через stdin с флагом --password-stdin",
+		maxLength: maxSecretLength,
+	}
+	registerPasswordField = secretField{
+		prompt:    "Введите пароль:",
+		confirm:   "Введите пароль еще раз:",
+		hint:      "пароль передается через stdin с флагом --password-stdin",
+		maxLength: maxSecretLength,
+	}
+	credentialPasswordField = secretField{
+		prompt:    "Введите пароль:",
+		confirm:   "Введите пароль еще раз:",
+		hint:      "пароль передается через stdin с флагом --password-stdin или полем password в --from-file",
+		maxLength: maxSecretLength,
+	}
+	exportPassphraseField = secretField{
+		prompt:    "Введите парольную фразу резервной копии:",
+		confirm:   "Введите парольную фразу еще раз:",
+		hint:      "парольная фраза передается через stdin с флагом --passphrase-stdin",
+		maxLength: maxSecretLength,
+	}
+	restorePassphraseField = secretField{
+		prompt:    "Введите парольную фразу резервной копии:",
+		hint:      "парольная фраза передается через stdin с флагом --passphrase-stdin",
+		maxLength: maxSecretLength,
+	}
+	cvvField = secretField{
+		prompt:    "Введите CVV код:",
+		hint:      "CVV код передается полем cvv в --from-file",
+		maxLength: maxCVVLength,
+	}
+)
+
+// secret возвращает секрет из первой строки stdin при fromStdin, иначе запрашивает его на терминале без отображения ввода.
+// Если stdin не является терминалом, секрет не запрашивается, а команда завершается ошибкой с подсказкой field.hint
+func (in *input) secret(fromStdin bool, field secretField) (string, error) {
+	if fromStdin {
+		value, err := in.readSecret(field.maxLength)
+		if err != nil {
+			return "", err
+		}
+		defer wipe(value)
+		if len(value) == 0 {
+			return "", fmt.Errorf("%w: stdin пуст", errMissingInput)
+		}
+		return string(value), nil
+	}
+	if !in.interactive {
+		return "", fmt.Errorf("%w: %s", errMissingInput, field.hint)
+	}
+
+	for attempt := 1; ; attempt++ {
+		value, err := in.hidden(field.prompt, field.maxLength)
+		if err != nil {
+			return "", err
+		}
+		if len(value) == 0 {
+			return "", fmt.Errorf("%w: %s", errMissingInput, field.hint)
+		}
+		if field.confirm == "" {
+			defer wipe(value)
+			return string(value), nil
+		}
+
+		check, err := in.hidden(field.confirm, field.maxLength)
+		match := err == nil && subtle.ConstantTimeCompare(value, check) == 1
+		wipe(check)
+		if match {
+			defer wipe(value)
+			return string(value), nil
+		}
+		wipe(value)
+		if err != nil {
+			return "", err
+		}
+
+		if attempt == maxSecretAttempts {
+			return "", fmt.Errorf("%w: введенные значения не совпадают", errUsage)
+		}
+		fmt.Fprintln(os.Stderr, "Введенные значения не совпадают, попробуйте еще раз")
+	}
+}
+
+// optionalSecret возвращает value, если оно задано, иначе на терминале запрашивает необязательный секрет без отображения ввода
+func (in *input) optionalSecret(value string, field secretField) (string, error) {
+	if value != "" || !in.interactive {
+		return value, nil
+	}
+
+	secret, err := in.hidden(field.prompt, field.maxLength)
+	if err != nil {
+		return "", err
+	}
+	defer wipe(secret)
+	return string(secret), nil
+}
+
+// hidden выводит запрос и читает строку с отключенным отображением ввода.
+// Режим терминала восстанавливается и при прерывании ввода через Ctrl+C
+func (in *input) hidden(prompt string, maxLength int) ([]byte, error) {
+	fmt.Fprintln(os.Stderr, prompt)
+	fmt.Fprint(os.Stderr, "> ")
+
+	restore, err := disableEcho(os.Stdin.Fd())
+	if err != nil {
+		fmt.Fprintln(os.Stderr)
+		return nil, fmt.Errorf("не удалось скрыть ввод, передайте значение через stdin: %w", err)
+	}
+
+	interrupt := make(chan os.Signal, 1)
+	done := make(chan struct{})
+	signal.Notify(interrupt, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		select {
+		case <-interrupt:
+			restore()
+			fmt.Fprintln(os.Stderr)
+			os.Exit(130)
+		case <-done:
+		}
+	}()
+
+	value, err := in.readSecret(maxLength)
+
+	signal.Stop(interrupt)
+	close(done)
+	restore()
+	// Перевод строки не отображался вместе с вводом
+	fmt.Fprintln(os.Stderr)
+
+	return value, err
+}
+
+// readSecret читает строку секрета. Буфер выделяется сразу и не растет, поэтому после wipe
+// в памяти не остается копий значения. Длинная строка дочитывается до конца,
+// чтобы ее остаток не попал в следующий запрос
+func (in *input) readSecret(maxLength int) ([]byte, error) {
+	// Дополнительный байт вмещает '\r' перед переводом строки
+	value := make([]byte, 0, maxLength+1)
+	tooLong := false
+	for {
+		b, err := in.reader.ReadByte()
+		if err == io.EOF || b == '\n' {
+			break
+		}
+		if err != nil {
+			wipe(value)
+			return nil, err
+		}
+		if len(value) == cap(value) {
+			tooLong = true
+			continue
+		}
+		value = append(value, b)
+	}
+
+	if n := len(value); n > 0 && value[n-1] == '\r' {
+		value[n-1] = 0
+		value = value[:n-1]
+	}
+	if tooLong || len(value) > maxLength {
+		wipe(value)
+		return nil, fmt.Errorf("%w: значение длиннее %d байт", errUsage, maxLength)
+	}
+	return value, nil
+}
+
+// wipe затирает буфер с секретом
+func wipe(buf []byte) {
+	buf = buf[:cap(buf)]
+	for i := range buf {
+		buf[i] = 0
+	}
+}
@@ -0,0 +1,96 @@
+package command
+
+import (
+	"bufio"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// newTestInput создает читателя значений из строки data
+func newTestInput(data string, interactive bool) *input {
+	return &input{reader: bufio.NewReader(strings.NewReader(data)), interactive: interactive}
+}
+
+// TestInput_Secret тестирует скрытый ввод секрета с подтверждением и ограничением длины
+func TestInput_Secret(t *testing.T) {
+	field := secretField{prompt: "Пароль:", confirm: "Еще раз:", hint: "--password-stdin", maxLength: 8}
+
+	tests := []struct {
+		name        string
+		input       string
+		fromStdin   bool
+		interactive bool
+		want        string
+		wantErr     error
+	}{
+		{name: "Confirmed", input: "secret\nsecret\n", interactive: true, want: "secret"},
+		{name: "Retry after mismatch", input: "secret\ntypo\nsecret\r\nsecret\r\n", interactive: true, want: "secret"},
+		{name: "Mismatch", input: "a\nb\na\nb\na\nb\n", interactive: true, wantErr: errUsage},
+		{name: "Too long", input: "123456789\n123456789\n", interactive: true, wantErr: errUsage},
+		{name: "Empty", input: "\n", interactive: true, wantErr: errMissingInput},
+		{name: "Not a terminal", input: "secret\n", interactive: false, wantErr: errMissingInput},
+		{name: "From stdin", input: "pass wd\n", fromStdin: true, want: "pass wd"},
+		{name: "From stdin too long", input: "123456789", fromStdin: true, wantErr: errUsage},
+		{name: "From empty stdin", input: "", fromStdin: true, wantErr: errMissingInput},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			withStdin(t, "", true)
+
+			got, err := newTestInput(tt.input, tt.interactive).secret(tt.fromStdin, field)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("Ожидалась ошибка %v, получена %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Неожиданная ошибка: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Ожидалось значение '%s', получено '%s'", tt.want, got)
+			}
+		})
+	}
+}
+
+// TestInput_SecretRestoresEcho тестирует отключение отображения ввода на время запроса и его восстановление
+func TestInput_SecretRestoresEcho(t *testing.T) {
+	withStdin(t, "", true)
+
+	oldDisableEcho := disableEcho
+	defer func() { disableEcho = oldDisableEcho }()
+
+	disabled, restored := 0, 0
+	disableEcho = func(fd uintptr) (func(), error) {
+		disabled++
+		return func() { restored++ }, nil
+	}
+
+	if _, err := newTestInput("1234\n", true).optionalSecret("", cvvField); err != nil {
+		t.Fatalf("Неожиданная ошибка: %v", err)
+	}
+	if disabled != 1 || restored != 1 {
+		t.Errorf("Ожидалось одно отключение и восстановление отображения ввода, получено %d и %d", disabled, restored)
+	}
+
+	disableEcho = func(fd uintptr) (func(), error) {
+		return nil, errors.New("inappropriate ioctl for device")
+	}
+	if _, err := newTestInput("1234\n", true).optionalSecret("", cvvField); err == nil {
+		t.Error("Ожидалась ошибка, если отображение ввода отключить нельзя")
+	}
+}
+
+// TestWipe тестирует затирание всего буфера секрета
+func TestWipe(t *testing.T) {
+	buf := []byte("secret")
+	wipe(buf[:2])
+	for i, b := range buf {
+		if b != 0 {
+			t.Errorf("Байт %d не затерт", i)
+		}
+	}
+}
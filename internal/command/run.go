@@ -0,0 +1,244 @@
+package command
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"github.com/spf13/cobra"
+	"os"
+	"os/exec"
+	"os/signal"
+	"regexp"
+	"strings"
+)
+
+// Коды завершения при ошибке запуска процесса, как в командной оболочке
+const (
+	exitCannotExecute   = 126
+	exitCommandNotFound = 127
+)
+
+// envNamePattern - допустимое имя переменной окружения
+var envNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// envMapping - переменная окружения и ссылка на поле записи с ее значением
+type envMapping struct {
+	Name string
+	Ref  secretRef
+}
+
+// RunCmd создает команду запуска процесса с секретами в переменных окружения
+func (c *Command) RunCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "run [flags] -- <command> [args...]",
+		Short: "Запуск процесса с секретами в переменных окружения",
+		Long: "Запускает команду, добавляя в ее окружение значения полей записей. Переменная задается флагом\n" +
+			"--env NAME=[type/]label[#field], например --env DB_PASS=credential/prod-db#password; без поля\n" +
+			"подставляется пароль, номер карты, текст или закрытый ключ SSH. Файл --env-file содержит такие же строки NAME=ref,\n" +
+			"пустые строки и строки, начинающиеся с '#', пропускаются; --env переопределяет значения из файла.\n" +
+			"Значения секретов в выводе процесса заменяются на " + maskReplacement + "; при этом вывод процесса\n" +
+			"не является терминалом, --no-mask подключает процесс к терминалу напрямую.\n" +
+			"Сигналы передаются процессу, команда завершается с его кодом завершения",
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			envs, _ := cmd.Flags().GetStringArray("env")
+			files, _ := cmd.Flags().GetStringArray("env-file")
+			noMask, _ := cmd.Flags().GetBool("no-mask")
+
+			mappings, err := envMappings(files, envs)
+			if err != nil {
+				return fail("Ошибка при запуске:", err)
+			}
+
+			resolver := c.newSecretResolver()
+			refs := make([]secretRef, 0, len(mappings))
+			for _, mapping := range mappings {
+				refs = append(refs, mapping.Ref)
+			}
+			if err := resolver.prefetch(refs); err != nil {
+				return fail("Ошибка при получении секретов:", err)
+			}
+
+			env := os.Environ()
+			secrets := make([]string, 0, len(mappings))
+			for _, mapping := range mappings {
+				_, value, err := resolver.resolve(mapping.Ref)
+				if err != nil {
+					return fail(fmt.Sprintf("Ошибка при получении значения %s:", mapping.Name), err)
+				}
+				env = append(env, mapping.Name+"="+value)
+				secrets = append(secrets, value)
+			}
+			if noMask {
+				secrets = nil
+			}
+
+			code, err := runProcess(args, env, secrets)
+			if err != nil {
+				return fail("Ошибка при запуске процесса:", err)
+			}
+			if code != 0 {
+				return &reportedError{err: &childExitError{code: code}}
+			}
+			return nil
+		},
+	}
+
+	// Флаги после имени команды относятся к ней, а не к passcli
+	cmd.Flags().SetInterspersed(false)
+	cmd.Flags().StringArray("env", nil, "Переменная окружения NAME=[type/]label[#field], флаг можно повторять")
+	cmd.Flags().StringArray("env-file", nil, "Файл со строками NAME=[type/]label[#field], флаг можно повторять")
+	cmd.Flags().Bool("no-mask", false, "Не маскировать секреты в выводе и подключить процесс к терминалу напрямую")
+
+	return cmd
+}
+
+// envMappings разбирает переменные из файлов files и флагов envs. Повторно заданная переменная
+// заменяет предыдущее значение
+func envMappings(files []string, envs []string) ([]envMapping, error) {
+	var mappings []envMapping
+	index := make(map[string]int)
+	add := func(line string, source string) error {
+		mapping, err := parseEnvMapping(line)
+		if err != nil {
+			return fmt.Errorf("%s: %w", source, err)
+		}
+		if i, ok := index[mapping.Name]; ok {
+			mappings[i] = mapping
+			return nil
+		}
+		index[mapping.Name] = len(mappings)
+		mappings = append(mappings, mapping)
+		return nil
+	}
+
+	for _, path := range files {
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", errUsage, err)
+		}
+		scanner := bufio.NewScanner(file)
+		for number := 1; scanner.Scan(); number++ {
+			line := strings.TrimSpace(scanner.Text())
+			if line == "" || strings.HasPrefix(line, "#") {
+				continue
+			}
+			if err := add(strings.TrimPrefix(line, "export "), fmt.Sprintf("%s:%d", path, number)); err != nil {
+				file.Close()
+				return nil, err
+			}
+		}
+		err = scanner.Err()
+		file.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for _, env := range envs {
+		if err := add(env, "--env"); err != nil {
+			return nil, err
+		}
+	}
+	return mappings, nil
+}
+
+// parseEnvMapping разбирает строку NAME=ref
+func parseEnvMapping(line string) (envMapping, error) {
+	name, ref, ok := strings.Cut(line, "=")
+	name, ref = strings.TrimSpace(name), strings.TrimSpace(ref)
+	if !ok || ref == "" {
+		return envMapping{}, fmt.Errorf("%w: ожидалось NAME=[type/]label[#field], получено '%s'", errUsage, line)
+	}
+	if !envNamePattern.MatchString(name) {
+		return envMapping{}, fmt.Errorf("%w: недопустимое имя переменной окружения '%s'", errUsage, name)
+	}
+	parsed, err := parseSecretRef(ref)
+	if err != nil {
+		return envMapping{}, err
+	}
+	return envMapping{Name: name, Ref: parsed}, nil
+}
+
+// runProcess запускает процесс args с окружением env, передает ему сигналы и возвращает его код завершения.
+// Значения secrets заменяются в выводе процесса; без секретов процесс наследует stdout и stderr
+func runProcess(args []string, env []string, secrets []string) (int, error) {
+	process := exec.Command(args[0], args[1:]...)
+	process.Env = env
+	process.Stdin, process.Stdout, process.Stderr = os.Stdin, os.Stdout, os.Stderr
+
+	var writers []*maskWriter
+	if len(secrets) > 0 {
+		stdout, stderr := newMaskWriter(os.Stdout, secrets), newMaskWriter(os.Stderr, secrets)
+		process.Stdout, process.Stderr = stdout, stderr
+		writers = append(writers, stdout, stderr)
+	}
+
+	group := newProcessGroup(process)
+
+	// Сигналы перехватываются до запуска, чтобы passcli не завершился раньше процесса
+	signals := make(chan os.Signal, len(forwardedSignals)+len(terminalSignals))
+	signal.Notify(signals, append(append([]os.Signal{}, forwardedSignals...), terminalSignals...)...)
+	defer signal.Stop(signals)
+
+	if err := process.Start(); err != nil {
+		if errors.Is(err, exec.ErrNotFound) {
+			fmt.Fprintln(os.Stderr, "Ошибка при запуске процесса:", err)
+			return exitCommandNotFound, nil
+		}
+		var pathErr *os.PathError
+		if errors.As(err, &pathErr) {
+			fmt.Fprintln(os.Stderr, "Ошибка при запуске процесса:", err)
+			return exitCannotExecute, nil
+		}
+		return 0, err
+	}
+
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		for {
+			select {
+			case sig := <-signals:
+				if !isForwardedSignal(sig) {
+					continue
+				}
+				// Процесс мог уже завершиться, ошибку отправки не учитываем
+				_ = group.signal(process, sig)
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	code, err := group.wait(process)
+	for _, writer := range writers {
+		_ = writer.Flush()
+	}
+	return code, err
+}
+
+// waitProcess дожидается завершения запущенного процесса и возвращает его код завершения
+func waitProcess(process *exec.Cmd) (int, error) {
+	err := process.Wait()
+
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return exitStatus(exitErr.ProcessState), nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return 0, nil
+}
+
+// isForwardedSignal сообщает, нужно ли передать сигнал запущенному процессу. Сигналы терминала процесс в консоли
+// Windows получает сам, повторная отправка доставила бы ему Ctrl+C дважды
+func isForwardedSignal(sig os.Signal) bool {
+	for _, forwarded := range forwardedSignals {
+		if sig == forwarded {
+			return true
+		}
+	}
+	return false
+}
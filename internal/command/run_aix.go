@@ -0,0 +1,12 @@
+//go:build aix
+
+package command
+
+const (
+	// waitUntraced - флаг ожидания, с которым wait сообщает и об остановке процесса. В golang.org/x/sys/unix для AIX
+	// его нет, значение из sys/wait.h
+	waitUntraced = 0x4
+	// ioctlSetForeground - запрос смены группы переднего плана терминала. unix.TIOCSPGRP для AIX не помещается в int,
+	// здесь то же значение как 32-битное число со знаком
+	ioctlSetForeground = -0x7ffb8b8a
+)
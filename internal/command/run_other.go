@@ -0,0 +1,38 @@
+//go:build !aix && !darwin && !dragonfly && !freebsd && !linux && !netbsd && !openbsd && !solaris && !zos
+
+package command
+
+import (
+	"os"
+	"os/exec"
+)
+
+// forwardedSignals - сигналы, которые команда run передает запущенному процессу
+var forwardedSignals = []os.Signal{}
+
+// terminalSignals - сигналы, которые команда run перехватывает, но не передает. Ctrl+C в консоли Windows получает
+// и запущенный процесс, а passcli продолжает ждать его завершения, чтобы вернуть его код
+var terminalSignals = []os.Signal{os.Interrupt}
+
+// processGroup - запущенный процесс. Группы процессов в консоли Windows нет, сигналы передаются самому процессу
+type processGroup struct{}
+
+// newProcessGroup возвращает группу запускаемого процесса
+func newProcessGroup(process *exec.Cmd) *processGroup {
+	return &processGroup{}
+}
+
+// signal передает сигнал запущенному процессу
+func (g *processGroup) signal(process *exec.Cmd, sig os.Signal) error {
+	return process.Process.Signal(sig)
+}
+
+// wait дожидается завершения процесса и возвращает его код завершения
+func (g *processGroup) wait(process *exec.Cmd) (int, error) {
+	return waitProcess(process)
+}
+
+// exitStatus возвращает код завершения процесса
+func exitStatus(state *os.ProcessState) int {
+	return state.ExitCode()
+}
@@ -0,0 +1,150 @@
+package command
+
+import (
+	"bytes"
+	"errors"
+	"github.com/SmirnovND/gophkeeper/internal/domain"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// TestParseSecretRef тестирует разбор ссылок на поля записей
+func TestParseSecretRef(t *testing.T) {
+	tests := []struct {
+		ref  string
+		want secretRef
+	}{
+		{"credential/prod-db#password", secretRef{Type: "credential", Label: "prod-db", Field: "password"}},
+		{"prod-db", secretRef{Label: "prod-db"}},
+		{"team/prod-db#login", secretRef{Label: "team/prod-db", Field: "login"}},
+		{"text/a#b#content", secretRef{Type: "text", Label: "a#b", Field: "content"}},
+	}
+	for _, tt := range tests {
+		got, err := parseSecretRef(tt.ref)
+		if err != nil || got != tt.want {
+			t.Errorf("parseSecretRef(%q) = %+v, %v, ожидалось %+v", tt.ref, got, err, tt.want)
+		}
+		if got.String() != tt.ref {
+			t.Errorf("Ссылка %q выводится как %q", tt.ref, got.String())
+		}
+	}
+
+	for _, ref := range []string{"", "credential/", "prod-db#"} {
+		if _, err := parseSecretRef(ref); err == nil {
+			t.Errorf("Ожидалась ошибка для ссылки %q", ref)
+		}
+	}
+}
+
+// TestMaskWriter тестирует маскирование секретов, разделенных между записями
+func TestMaskWriter(t *testing.T) {
+	var buf bytes.Buffer
+	writer := newMaskWriter(&buf, []string{"s3cret", "s3cret-long", "abc"})
+
+	for _, part := range []string{"pass=s3", "cr", "et long=s3cret-lo", "ng short=abc tail=s3c"} {
+		if _, err := writer.Write([]byte(part)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := writer.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	want := "pass=****** long=****** short=abc tail=s3c"
+	if buf.String() != want {
+		t.Errorf("Ожидалось %q, получено %q", want, buf.String())
+	}
+}
+
+// TestEnvMappings тестирует разбор файла переменных и переопределение значений флагом --env
+func TestEnvMappings(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "secrets.env")
+	data := "# база данных\nDB_USER=credential/prod-db#login\n\nexport DB_PASS = credential/prod-db\n"
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	mappings, err := envMappings([]string{path}, []string{"DB_PASS=text/db-pass", "API_KEY=api"})
+	if err != nil {
+		t.Fatalf("Неожиданная ошибка: %v", err)
+	}
+	var got []string
+	for _, mapping := range mappings {
+		got = append(got, mapping.Name+"="+mapping.Ref.String())
+	}
+	want := "DB_USER=credential/prod-db#login DB_PASS=text/db-pass API_KEY=api"
+	if strings.Join(got, " ") != want {
+		t.Errorf("Ожидалось %q, получено %q", want, strings.Join(got, " "))
+	}
+
+	for _, env := range []string{"DB_PASS", "1DB=prod-db", "DB="} {
+		if _, err := envMappings(nil, []string{env}); ExitCode(err) != ExitUsage {
+			t.Errorf("Ожидалась ошибка использования для %q, получено: %v", env, err)
+		}
+	}
+}
+
+// newRunCommand возвращает команду с учетными данными prod-db
+func newRunCommand() *Command {
+	return &Command{clientUseCase: &MockDataClientUseCase{
+		ListItemsFunc: func() ([]domain.ItemInfo, error) {
+			return []domain.ItemInfo{{Type: domain.UserDataTypeCredential, Label: "prod-db"}}, nil
+		},
+		GetCredentialFunc: func(label string) (*domain.CredentialData, string, error) {
+			return &domain.CredentialData{Login: "app", Password: "s3cret"}, "", nil
+		},
+	}}
+}
+
+// TestCommand_RunCmd тестирует передачу секретов в окружение, маскирование вывода и код завершения процесса
+func TestCommand_RunCmd(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("тест использует sh")
+	}
+
+	out, err := runWithOutput(t, newRunCommand().RunCmd(), "--env", "DB_PASS=credential/prod-db#password",
+		"--env", "DB_USER=prod-db#login", "--", "sh", "-c", `echo "$DB_USER:$DB_PASS"; exit 3`)
+	if ExitCode(err) != 3 {
+		t.Errorf("Ожидался код завершения 3, получено: %v", err)
+	}
+	if out != "app:******\n" {
+		t.Errorf("Секрет должен быть замаскирован, вывод: %q", out)
+	}
+
+	out, err = runWithOutput(t, newRunCommand().RunCmd(), "--env", "DB_PASS=prod-db", "--no-mask",
+		"sh", "-c", `echo "$DB_PASS"`)
+	if err != nil || out != "s3cret\n" {
+		t.Errorf("Без маскирования ожидался секрет в выводе, получено %q, ошибка: %v", out, err)
+	}
+
+	_, err = runWithOutput(t, newRunCommand().RunCmd(), "--env", "DB_PASS=text/prod-db", "sh", "-c", "true")
+	var statusErr *domain.Error
+	if !errors.As(err, &statusErr) || statusErr.Code() != http.StatusNotFound {
+		t.Errorf("Ожидалась ошибка отсутствия записи, получено: %v", err)
+	}
+
+	if _, err := runWithOutput(t, newRunCommand().RunCmd(), "passcli-missing-command"); ExitCode(err) != exitCommandNotFound {
+		t.Errorf("Ожидался код завершения %d, получено: %v", exitCommandNotFound, err)
+	}
+}
+
+// TestCommand_RunCmd_Signals тестирует, что процесс работает в своей группе и получает от passcli все сигналы,
+// включая SIGINT, ровно один раз
+func TestCommand_RunCmd_Signals(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("тест использует sh")
+	}
+
+	script := `[ "$(ps -o pgid= -p $$)" != "$(ps -o pgid= -p $PPID)" ] && echo GROUP; ` +
+		`trap 'echo INT; got=1' INT; trap 'echo TERM; exit 0' TERM; kill -INT $PPID; ` +
+		`i=0; while [ -z "$got" ] && [ $i -lt 50 ]; do sleep 0.1; i=$((i+1)); done; kill -TERM $PPID; ` +
+		`i=0; while [ $i -lt 50 ]; do sleep 0.1; i=$((i+1)); done`
+	out, err := runWithOutput(t, newRunCommand().RunCmd(), "--no-mask", "sh", "-c", script)
+	if err != nil || out != "GROUP\nINT\nTERM\n" {
+		t.Errorf("Ожидался запуск в отдельной группе и передача SIGINT и SIGTERM, получено %q, ошибка: %v", out, err)
+	}
+}
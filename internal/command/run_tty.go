@@ -0,0 +1,14 @@
+//go:build darwin || dragonfly || freebsd || linux || netbsd || openbsd || solaris || zos
+
+package command
+
+import (
+	"golang.org/x/sys/unix"
+)
+
+const (
+	// waitUntraced - флаг ожидания, с которым wait сообщает и об остановке процесса
+	waitUntraced = unix.WUNTRACED
+	// ioctlSetForeground - запрос смены группы переднего плана терминала
+	ioctlSetForeground = unix.TIOCSPGRP
+)
@@ -0,0 +1,124 @@
+//go:build aix || darwin || dragonfly || freebsd || linux || netbsd || openbsd || solaris || zos
+
+package command
+
+import (
+	"errors"
+	"golang.org/x/sys/unix"
+	"os"
+	"os/exec"
+	"os/signal"
+	"syscall"
+)
+
+// forwardedSignals - сигналы, которые команда run передает запущенному процессу. Процесс работает в своей группе,
+// поэтому Ctrl+C и Ctrl+\ терминала он получает только через passcli, и ни один сигнал не доставляется дважды
+var forwardedSignals = []os.Signal{
+	syscall.SIGINT, syscall.SIGQUIT, syscall.SIGTERM, syscall.SIGHUP, syscall.SIGUSR1, syscall.SIGUSR2,
+}
+
+// terminalSignals - сигналы, которые команда run перехватывает, но не передает
+var terminalSignals = []os.Signal{}
+
+// processGroup - группа, в которой работает запущенный процесс. Если passcli работает на переднем плане терминала,
+// группа процесса становится группой переднего плана: процесс читает с терминала и сам получает Ctrl+C и Ctrl+Z
+type processGroup struct {
+	// tty - терминал, группой переднего плана которого управляет passcli, или -1
+	tty    int
+	parent int
+	pgid   int
+}
+
+// newProcessGroup настраивает запуск процесса в отдельной группе процессов
+func newProcessGroup(process *exec.Cmd) *processGroup {
+	process.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+
+	group := &processGroup{tty: -1}
+	parent, err := unix.Getpgid(0)
+	if err != nil {
+		return group
+	}
+	group.parent = parent
+
+	for _, file := range []*os.File{os.Stdin, os.Stdout, os.Stderr} {
+		foreground, err := unix.IoctlGetInt(int(file.Fd()), unix.TIOCGPGRP)
+		if err != nil {
+			continue
+		}
+		if foreground == group.parent {
+			group.tty = int(file.Fd())
+			process.SysProcAttr.Foreground = true
+			process.SysProcAttr.Ctty = group.tty
+		}
+		break
+	}
+	return group
+}
+
+// signal передает сигнал всей группе запущенного процесса, как это сделал бы терминал
+func (g *processGroup) signal(process *exec.Cmd, sig os.Signal) error {
+	return syscall.Kill(-process.Process.Pid, sig.(syscall.Signal))
+}
+
+// wait дожидается завершения процесса и возвращает его код завершения. Если процесс остановлен с терминала (Ctrl+Z),
+// passcli возвращает терминал своей группе и останавливается сам, чтобы оболочка увидела остановленное задание;
+// после fg терминал снова передается процессу, и он продолжает работу
+func (g *processGroup) wait(process *exec.Cmd) (int, error) {
+	if g.tty < 0 {
+		return waitProcess(process)
+	}
+
+	g.pgid = process.Process.Pid
+	defer g.handOver(g.pgid, g.parent)
+
+	var status unix.WaitStatus
+	for {
+		_, err := unix.Wait4(g.pgid, &status, waitUntraced, nil)
+		if errors.Is(err, unix.EINTR) {
+			continue
+		}
+		if err != nil {
+			return 0, err
+		}
+		if !status.Stopped() {
+			break
+		}
+
+		g.handOver(g.pgid, g.parent)
+		_ = unix.Kill(unix.Getpid(), unix.SIGSTOP)
+		g.handOver(g.parent, g.pgid)
+		_ = unix.Kill(-g.pgid, unix.SIGCONT)
+	}
+
+	// Статус процесса уже получен, Wait только дожидается копирования вывода и освобождает ресурсы,
+	// его ошибка об отсутствии процесса не учитывается
+	_ = process.Wait()
+
+	if status.Signaled() {
+		return 128 + int(status.Signal()), nil
+	}
+	return status.ExitStatus(), nil
+}
+
+// handOver передает терминал группе to, если на переднем плане сейчас группа from. После bg passcli работает в фоне
+// и терминал у оболочки не забирает
+func (g *processGroup) handOver(from int, to int) {
+	foreground, err := unix.IoctlGetInt(g.tty, unix.TIOCGPGRP)
+	if err != nil || foreground != from {
+		return
+	}
+
+	// Смена группы переднего плана из фоновой группы без игнорирования SIGTTOU остановила бы passcli
+	signal.Ignore(syscall.SIGTTOU)
+	defer signal.Reset(syscall.SIGTTOU)
+	_ = unix.IoctlSetPointerInt(g.tty, ioctlSetForeground, to)
+}
+
+// exitStatus возвращает код завершения процесса. Для процесса, завершенного сигналом, код равен 128 + номер сигнала,
+// как в командной оболочке
+func exitStatus(state *os.ProcessState) int {
+	if status, ok := state.Sys().(syscall.WaitStatus); ok && status.Signaled() {
+		return 128 + int(status.Signal())
+	}
+	return state.ExitCode()
+}
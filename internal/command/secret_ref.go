@@ -0,0 +1,243 @@
+package command
+
+import (
+	"errors"
+	"fmt"
+	"github.com/SmirnovND/gophkeeper/internal/domain"
+	"github.com/SmirnovND/gophkeeper/internal/itemtype"
+	"gopkg.in/yaml.v3"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// defaultSecretField возвращает основной секрет записи встроенного типа itemType из описания типа
+func defaultSecretField(itemType string) (string, bool) {
+	if t, err := itemtype.Lookup(itemType); err == nil {
+		return t.Secret, true
+	}
+	return "", false
+}
+
+// secretRef - ссылка на поле записи вида [type/]label[#field], например credential/prod-db#password.
+// Без типа запись ищется по метке, без поля выбирается основной секрет записи
+type secretRef struct {
+	Type  string
+	Label string
+	Field string
+}
+
+// parseSecretRef разбирает ссылку на поле записи. Префикс считается типом, только если это встроенный тип записи,
+// поэтому метки с '/' можно указывать без типа. Префикс пользовательского типа распознается при поиске записи
+func parseSecretRef(ref string) (secretRef, error) {
+	var parsed secretRef
+	rest := ref
+	if i := strings.LastIndex(rest, "#"); i >= 0 {
+		rest, parsed.Field = rest[:i], rest[i+1:]
+		if parsed.Field == "" {
+			return secretRef{}, fmt.Errorf("%w: пустое поле в ссылке '%s'", errUsage, ref)
+		}
+	}
+	if i := strings.Index(rest, "/"); i >= 0 {
+		if _, ok := defaultSecretField(rest[:i]); ok {
+			parsed.Type, rest = rest[:i], rest[i+1:]
+		}
+	}
+	if rest == "" {
+		return secretRef{}, fmt.Errorf("%w: не указана метка в ссылке '%s'", errUsage, ref)
+	}
+	parsed.Label = rest
+	return parsed, nil
+}
+
+func (r secretRef) String() string {
+	ref := r.Label
+	if r.Type != "" {
+		ref = r.Type + "/" + ref
+	}
+	if r.Field != "" {
+		ref += "#" + r.Field
+	}
+	return ref
+}
+
+// secretResolver получает значения полей записей. Список записей и содержимое каждой записи
+// запрашиваются один раз, сколько бы ссылок на них ни было
+type secretResolver struct {
+	c     *Command
+	items map[string]domain.ItemInfo
+	nodes map[string]*yaml.Node
+}
+
+// newSecretResolver создает получатель значений полей записей
+func (c *Command) newSecretResolver() *secretResolver {
+	return &secretResolver{c: c, nodes: make(map[string]*yaml.Node)}
+}
+
+// resolve возвращает название и значение поля, на которое указывает ref
+func (r *secretResolver) resolve(ref secretRef) (string, string, error) {
+	info, err := r.lookup(ref)
+	if err != nil {
+		return "", "", err
+	}
+	node, ok := r.nodes[info.Label]
+	if !ok {
+		if node, err = r.fetch(info); err != nil {
+			return "", "", err
+		}
+		r.nodes[info.Label] = node
+	}
+
+	field := ref.Field
+	if field == "" {
+		if field, err = r.secretField(info.Type); err != nil {
+			return "", "", err
+		}
+	}
+	value, err := fieldValue(node, field)
+	if err != nil {
+		return "", "", err
+	}
+	return strings.ToLower(field), value, nil
+}
+
+// prefetch одновременно получает все записи, на которые указывают refs, и проверяет ссылки.
+// Ошибки всех ссылок возвращаются вместе, чтобы их можно было исправить за один раз
+func (r *secretResolver) prefetch(refs []secretRef) error {
+	var errs []error
+	pending := make(map[string]domain.ItemInfo)
+	for _, ref := range refs {
+		info, err := r.lookup(ref)
+		if err != nil {
+			// Без списка записей остальные ссылки проверить нельзя
+			if r.items == nil {
+				return err
+			}
+			errs = append(errs, err)
+			continue
+		}
+		if _, ok := r.nodes[info.Label]; !ok {
+			pending[info.Label] = info
+		}
+	}
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, info := range pending {
+		wg.Add(1)
+		go func(info domain.ItemInfo) {
+			defer wg.Done()
+			node, err := r.fetch(info)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				errs = append(errs, fmt.Errorf("'%s': %w", info.Label, err))
+				return
+			}
+			r.nodes[info.Label] = node
+		}(info)
+	}
+	wg.Wait()
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+
+	for _, ref := range refs {
+		if _, _, err := r.resolve(ref); err != nil {
+			errs = append(errs, fmt.Errorf("'%s': %w", ref, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// lookup возвращает сведения о записи, на которую указывает ref. Список записей запрашивается при первом вызове.
+// Метка вида type/label без записи с такой меткой ищется как запись пользовательского типа type
+func (r *secretResolver) lookup(ref secretRef) (domain.ItemInfo, error) {
+	if r.items == nil {
+		items, err := r.c.clientUseCase.ListItems()
+		if err != nil {
+			return domain.ItemInfo{}, err
+		}
+		r.items = make(map[string]domain.ItemInfo, len(items))
+		for _, item := range items {
+			r.items[item.Label] = item
+		}
+	}
+
+	info, ok := r.items[ref.Label]
+	if itemType, label, found := strings.Cut(ref.Label, "/"); !ok && ref.Type == "" && found {
+		if info, ok = r.items[label]; ok && info.Type != itemType {
+			ok = false
+		}
+	}
+	if !ok || (ref.Type != "" && ref.Type != info.Type) {
+		return domain.ItemInfo{}, &domain.Error{Message: fmt.Sprintf("запись '%s' не найдена", ref), CodeValue: http.StatusNotFound}
+	}
+	if info.Type == domain.UserDataTypeFile {
+		return domain.ItemInfo{}, fmt.Errorf("%w: у записи '%s' типа %s нет полей с секретами", errUsage, ref.Label, info.Type)
+	}
+	if _, err := r.secretField(info.Type); err != nil {
+		return domain.ItemInfo{}, err
+	}
+	return info, nil
+}
+
+// secretField возвращает основной секрет записи типа itemType, в том числе пользовательского
+func (r *secretResolver) secretField(itemType string) (string, error) {
+	if field, ok := defaultSecretField(itemType); ok {
+		return field, nil
+	}
+	t, err := r.c.clientUseCase.ItemType(itemType)
+	if err != nil {
+		return "", err
+	}
+	return t.Secret, nil
+}
+
+// fetch получает поля записи info
+func (r *secretResolver) fetch(info domain.ItemInfo) (*yaml.Node, error) {
+	var item interface{}
+	switch info.Type {
+	case domain.UserDataTypeCredential:
+		data, metadata, err := r.c.clientUseCase.GetCredential(info.Label)
+		if err != nil {
+			return nil, err
+		}
+		item = newCredentialItem(info.Label, data, metadata)
+	case domain.UserDataTypeCard:
+		data, metadata, err := r.c.clientUseCase.GetCard(info.Label)
+		if err != nil {
+			return nil, err
+		}
+		item = &cardItem{Type: info.Type, Label: info.Label, Number: data.Number, Holder: data.Holder,
+			ExpiryDate: data.ExpiryDate, CVV: data.CVV, Metadata: metadata}
+	case domain.UserDataTypeText:
+		data, metadata, err := r.c.clientUseCase.GetText(info.Label)
+		if err != nil {
+			return nil, err
+		}
+		item = &textItem{Type: info.Type, Label: info.Label, Content: data.Content, Metadata: metadata}
+	case domain.UserDataTypeSSHKey:
+		data, metadata, err := r.c.clientUseCase.GetSSHKey(info.Label)
+		if err != nil {
+			return nil, err
+		}
+		item = &sshKeyItem{Type: info.Type, Label: info.Label, PrivateKey: data.PrivateKey, PublicKey: data.PublicKey,
+			Fingerprint: data.Fingerprint, Comment: data.Comment, Metadata: metadata}
+	default:
+		t, err := r.c.clientUseCase.ItemType(info.Type)
+		if err != nil {
+			return nil, err
+		}
+		data, metadata, err := r.c.clientUseCase.GetItem(info.Type, info.Label)
+		if err != nil {
+			return nil, err
+		}
+		item = &structuredItem{itemType: t, label: info.Label, data: data, metadata: metadata}
+	}
+
+	return toNode(item)
+}
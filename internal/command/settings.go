@@ -0,0 +1,89 @@
+package command
+
+import (
+	"fmt"
+	"github.com/SmirnovND/gophkeeper/internal/domain"
+	"github.com/spf13/cobra"
+)
+
+// SettingsCmd создает команду вывода настроек аккаунта с подкомандой set для их изменения
+func (c *Command) SettingsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "settings",
+		Short: "Настройки аккаунта",
+		Long: "Выводит настройки аккаунта, которые хранятся на сервере и действуют для всех клиентов.\n" +
+			"password_history - сколько прежних паролей сервер хранит у учетных данных, 0 отключает историю",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out, err := newPrinter(cmd)
+			if err != nil {
+				return fail("Ошибка при получении настроек:", err)
+			}
+			settings, err := c.clientUseCase.Settings()
+			if err != nil {
+				return fail("Ошибка при получении настроек:", err)
+			}
+
+			err = out.print(settings, func() {
+				fmt.Println("\nНастройки аккаунта:")
+				fmt.Println("----------------------")
+				fmt.Println("История паролей:", formatPasswordHistory(settings.PasswordHistory))
+				fmt.Println("----------------------")
+			})
+			if err != nil {
+				return fail("Ошибка при получении настроек:", err)
+			}
+			return nil
+		},
+	}
+
+	cmd.AddCommand(c.settingsSetCmd())
+	return cmd
+}
+
+// settingsSetCmd создает команду изменения настроек аккаунта. Не указанные флагами настройки не меняются
+func (c *Command) settingsSetCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "set",
+		Short: "Изменение настроек аккаунта",
+		Long: "Изменяет настройки, заданные флагами, например:\n" +
+			"passcli settings set --password-history 5\n" +
+			"Уменьшение длины истории применяется к учетным данным при их следующем сохранении",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if !cmd.Flags().Changed("password-history") {
+				return fail("Ошибка при сохранении настроек:", fmt.Errorf("%w: не задано ни одной настройки, например --password-history", errUsage))
+			}
+			history, _ := cmd.Flags().GetInt("password-history")
+			if history < 0 || history > domain.MaxPasswordHistory {
+				return fail("Ошибка при сохранении настроек:",
+					fmt.Errorf("%w: длина истории паролей должна быть от 0 до %d", errUsage, domain.MaxPasswordHistory))
+			}
+
+			settings, err := c.clientUseCase.Settings()
+			if err != nil {
+				return fail("Ошибка при сохранении настроек:", err)
+			}
+			settings.PasswordHistory = history
+			if err := c.clientUseCase.SaveSettings(settings); err != nil {
+				return fail("Ошибка при сохранении настроек:", err)
+			}
+
+			fmt.Println("Настройки успешно сохранены!")
+			return nil
+		},
+	}
+
+	cmd.Flags().Int("password-history", domain.DefaultPasswordHistory,
+		fmt.Sprintf("Сколько прежних паролей хранить у учетных данных, от 0 до %d", domain.MaxPasswordHistory))
+
+	return cmd
+}
+
+// formatPasswordHistory выводит длину истории паролей, нулевая длина означает, что история не ведется
+func formatPasswordHistory(length int) string {
+	if length == 0 {
+		return "не ведется"
+	}
+	return fmt.Sprint(length)
+}
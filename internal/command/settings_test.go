@@ -0,0 +1,83 @@
+package command
+
+import (
+	"encoding/json"
+	"github.com/SmirnovND/gophkeeper/internal/domain"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestCommand_SettingsCmd тестирует вывод и изменение настроек аккаунта
+func TestCommand_SettingsCmd(t *testing.T) {
+	stored := domain.UserSettings{PasswordHistory: domain.DefaultPasswordHistory}
+	saves := 0
+	cmd := &Command{clientUseCase: &MockFileClientUseCase{
+		SettingsFunc: func() (*domain.UserSettings, error) {
+			settings := stored
+			return &settings, nil
+		},
+		SaveSettingsFunc: func(settings *domain.UserSettings) error {
+			stored = *settings
+			saves++
+			return nil
+		},
+	}}
+
+	out, err := runWithOutput(t, cmd.SettingsCmd())
+	if err != nil || !strings.Contains(out, "История паролей: 10") {
+		t.Errorf("Ожидалась длина истории паролей, получено %q, %v", out, err)
+	}
+
+	if _, err := runWithOutput(t, cmd.SettingsCmd(), "set", "--password-history", "0"); err != nil {
+		t.Fatalf("Неожиданная ошибка: %v", err)
+	}
+	out, err = runWithOutput(t, cmd.SettingsCmd(), "-o", "json")
+	var got domain.UserSettings
+	if err != nil || json.Unmarshal([]byte(out), &got) != nil || got.PasswordHistory != 0 {
+		t.Errorf("Ожидалась отключенная история паролей, получено %q, %v", out, err)
+	}
+
+	for _, args := range [][]string{
+		{"set"},
+		{"set", "--password-history", "-1"},
+		{"set", "--password-history", "101"},
+	} {
+		if _, err := runWithOutput(t, cmd.SettingsCmd(), args...); ExitCode(err) != ExitUsage {
+			t.Errorf("Для %v ожидалась ошибка аргументов, получено %v", args, err)
+		}
+	}
+	if saves != 1 {
+		t.Errorf("Ожидалось одно сохранение настроек, получено %d", saves)
+	}
+}
+
+// TestCommand_GetCredentialCmd_History тестирует вывод прежних паролей только по флагу --history
+func TestCommand_GetCredentialCmd_History(t *testing.T) {
+	vault := newFakeVault()
+	changedAt := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	vault.command().clientUseCase.SaveCredential("github", &domain.CredentialData{Login: "alice", Password: "new",
+		History: []domain.PasswordChange{{Password: "old", ChangedAt: changedAt}}}, "")
+
+	out, err := runWithOutput(t, vault.command().GetCredentialCmd(), "github")
+	if err != nil || strings.Contains(out, "old") || !strings.Contains(out, "Прежних паролей: 1") {
+		t.Errorf("Без --history ожидалось только число прежних паролей, получено %q, %v", out, err)
+	}
+	out, err = runWithOutput(t, vault.command().GetCredentialCmd(), "github", "-o", "json")
+	if err != nil || strings.Contains(out, "password_history") {
+		t.Errorf("Без --history история не должна попадать в json, получено %q, %v", out, err)
+	}
+
+	out, err = runWithOutput(t, vault.command().GetCredentialCmd(), "github", "--history", "-o", "json")
+	var item credentialItem
+	if err != nil || json.Unmarshal([]byte(out), &item) != nil {
+		t.Fatalf("Неверный вывод json: %q, %v", out, err)
+	}
+	if len(item.History) != 1 || item.History[0].Password != "old" || !item.History[0].ChangedAt.Equal(changedAt) {
+		t.Errorf("Ожидалась история из одного пароля, получено %+v", item.History)
+	}
+	out, err = runWithOutput(t, vault.command().GetCredentialCmd(), "github", "--field", "password_history.0.password", "--history")
+	if err != nil || out != "old" {
+		t.Errorf("Ожидался прежний пароль, получено %q, %v", out, err)
+	}
+}
@@ -0,0 +1,216 @@
+package command
+
+import (
+	"crypto"
+	"errors"
+	"fmt"
+	"github.com/SmirnovND/gophkeeper/internal/domain"
+	"github.com/SmirnovND/gophkeeper/internal/sshkey"
+	"github.com/spf13/cobra"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+)
+
+// agentPassphraseField - парольная фраза зашифрованного ключа SSH
+var agentPassphraseField = secretField{
+	prompt:    "Введите парольную фразу ключа:",
+	hint:      "зашифрованные ключи загружаются только при запуске на терминале",
+	maxLength: maxSecretLength,
+}
+
+// SSHAgentCmd создает команду запуска агента SSH с ключами хранилища
+func (c *Command) SSHAgentCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "ssh-agent [label...] [-- <command> [args...]]",
+		Short: "Агент SSH с ключами хранилища",
+		Long: "Запускает агент SSH с ключами записей ssh_key; без меток загружаются все ключи SSH хранилища.\n" +
+			"Ключи хранятся только в памяти агента и не записываются на диск, ssh-add не может их изменить.\n" +
+			"Без команды агент выводит в stdout строку для оболочки с SSH_AUTH_SOCK и работает до Ctrl+C.\n" +
+			"После -- агент запускает команду с SSH_AUTH_SOCK и завершается вместе с ней, например:\n" +
+			"passcli ssh-agent deploy -- git push\n" +
+			"С флагом --confirm каждая подпись подтверждается на терминале. Для зашифрованных ключей\n" +
+			"запрашивается парольная фраза, без терминала такие ключи пропускаются",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			socket, _ := cmd.Flags().GetString("socket")
+			confirm, _ := cmd.Flags().GetBool("confirm")
+
+			labels, command := args, []string(nil)
+			if dash := cmd.ArgsLenAtDash(); dash >= 0 {
+				labels, command = args[:dash], args[dash:]
+				if len(command) == 0 {
+					return fail("Ошибка при запуске агента SSH:", fmt.Errorf("%w: после -- не указана команда", errUsage))
+				}
+			}
+
+			in := newInput()
+			if confirm && (!in.interactive || command != nil) {
+				return fail("Ошибка при запуске агента SSH:", fmt.Errorf("%w: флаг --confirm требует терминала и не используется с командой", errUsage))
+			}
+
+			var confirmFunc sshkey.ConfirmFunc
+			if confirm {
+				confirmFunc = func(label string, fingerprint string) bool {
+					allowed, _ := in.confirm(false, fmt.Sprintf("Разрешить подпись ключом '%s' (%s)?", label, fingerprint), "")
+					return allowed
+				}
+			}
+			agent := sshkey.NewAgent(confirmFunc)
+			loaded, err := c.loadSSHKeys(agent, in, labels)
+			if err != nil {
+				return fail("Ошибка при загрузке ключей SSH:", err)
+			}
+			if loaded == 0 {
+				return fail("Ошибка при запуске агента SSH:", &domain.Error{Message: "в хранилище нет ключей SSH", CodeValue: http.StatusNotFound})
+			}
+
+			listener, cleanup, err := listenAgent(socket)
+			if err != nil {
+				return fail("Ошибка при запуске агента SSH:", err)
+			}
+			defer cleanup()
+			go func() {
+				if err := agent.Serve(listener); err != nil {
+					fmt.Fprintln(os.Stderr, "Ошибка агента SSH:", err)
+				}
+			}()
+
+			if command != nil {
+				code, err := runProcess(command, append(os.Environ(), "SSH_AUTH_SOCK="+listener.Addr().String()), nil)
+				if err != nil {
+					return fail("Ошибка при запуске процесса:", err)
+				}
+				if code != 0 {
+					return &reportedError{err: &childExitError{code: code}}
+				}
+				return nil
+			}
+
+			fmt.Printf("SSH_AUTH_SOCK=%s; export SSH_AUTH_SOCK;\n", listener.Addr().String())
+			fmt.Fprintf(os.Stderr, "Агент SSH запущен, загружено ключей: %d. Для остановки нажмите Ctrl+C\n", loaded)
+
+			signals := make(chan os.Signal, 1)
+			signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+			defer signal.Stop(signals)
+			<-signals
+			return nil
+		},
+	}
+
+	cmd.Flags().String("socket", "", "Путь к сокету агента, по умолчанию во временной директории")
+	cmd.Flags().Bool("confirm", false, "Подтверждать каждую подпись на терминале")
+
+	return cmd
+}
+
+// loadSSHKeys добавляет в агент ключи с метками labels или все ключи SSH хранилища и возвращает их число
+func (c *Command) loadSSHKeys(agent *sshkey.Agent, in *input, labels []string) (int, error) {
+	items, err := c.clientUseCase.ListItems()
+	if err != nil {
+		return 0, err
+	}
+
+	types := make(map[string]string, len(items))
+	for _, item := range items {
+		types[item.Label] = item.Type
+	}
+	if len(labels) == 0 {
+		for _, item := range items {
+			if item.Type == domain.UserDataTypeSSHKey {
+				labels = append(labels, item.Label)
+			}
+		}
+	}
+
+	loaded := 0
+	for _, label := range labels {
+		if types[label] != domain.UserDataTypeSSHKey {
+			return 0, &domain.Error{Message: fmt.Sprintf("ключ SSH '%s' не найден", label), CodeValue: http.StatusNotFound}
+		}
+		data, _, err := c.clientUseCase.GetSSHKey(label)
+		if err != nil {
+			return 0, err
+		}
+
+		key, err := decryptSSHKey(data, in, label)
+		if errors.Is(err, errMissingInput) {
+			fmt.Fprintf(os.Stderr, "Ключ '%s' зашифрован и пропущен: %v\n", label, err)
+			continue
+		}
+		if err != nil {
+			return 0, fmt.Errorf("ключ '%s': %w", label, err)
+		}
+		if err := agent.AddKey(label, key); err != nil {
+			return 0, fmt.Errorf("ключ '%s': %w", label, err)
+		}
+		loaded++
+	}
+	return loaded, nil
+}
+
+// decryptSSHKey разбирает закрытый ключ, запрашивая парольную фразу зашифрованного ключа
+func decryptSSHKey(data *domain.SSHKeyData, in *input, label string) (crypto.PrivateKey, error) {
+	key, err := sshkey.PrivateKey(data, nil)
+	if !errors.Is(err, sshkey.ErrPassphraseRequired) {
+		return key, err
+	}
+
+	for attempt := 1; ; attempt++ {
+		fmt.Fprintf(os.Stderr, "Ключ '%s' зашифрован\n", label)
+		passphrase, err := in.secret(false, agentPassphraseField)
+		if err != nil {
+			return nil, err
+		}
+		key, err = sshkey.PrivateKey(data, []byte(passphrase))
+		if !errors.Is(err, sshkey.ErrWrongPassphrase) || attempt == maxSecretAttempts {
+			return key, err
+		}
+		fmt.Fprintln(os.Stderr, "Неверная парольная фраза, попробуйте еще раз")
+	}
+}
+
+// listenAgent открывает сокет агента с доступом только для владельца и возвращает функцию его удаления.
+// Оставшийся от завершенного агента сокет заменяется, а сокет работающего агента - нет
+func listenAgent(socket string) (net.Listener, func(), error) {
+	dir := ""
+	if socket == "" {
+		var err error
+		if dir, err = os.MkdirTemp("", "passcli-ssh-"); err != nil {
+			return nil, nil, err
+		}
+		socket = filepath.Join(dir, "agent.sock")
+	} else if _, err := os.Stat(socket); err == nil {
+		if conn, err := net.Dial("unix", socket); err == nil {
+			conn.Close()
+			return nil, nil, fmt.Errorf("%w: сокет %s уже используется", errUsage, socket)
+		}
+		if err := os.Remove(socket); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	removeDir := func() {
+		if dir != "" {
+			os.RemoveAll(dir)
+		}
+	}
+	listener, err := net.Listen("unix", socket)
+	if err != nil {
+		removeDir()
+		return nil, nil, err
+	}
+	if err := os.Chmod(socket, 0600); err != nil {
+		listener.Close()
+		removeDir()
+		return nil, nil, err
+	}
+
+	return listener, func() {
+		// Закрытие unix-сокета удаляет его файл
+		listener.Close()
+		removeDir()
+	}, nil
+}
@@ -0,0 +1,193 @@
+package command
+
+import (
+	"errors"
+	"fmt"
+	"github.com/SmirnovND/gophkeeper/internal/domain"
+	"github.com/SmirnovND/gophkeeper/internal/sshkey"
+	"github.com/spf13/cobra"
+	"os"
+	"strings"
+)
+
+// SaveSSHKeyCmd создает команду сохранения ключа SSH
+func (c *Command) SaveSSHKeyCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "save-ssh-key [label]",
+		Short: "Сохранение ключа SSH",
+		Long: "Сохранение ключа SSH. Закрытый ключ читается из файла --from-file ('-' для stdin), а на терминале вводится до Ctrl+D.\n" +
+			"Открытый ключ и отпечаток вычисляются по закрытому ключу; для зашифрованного ключа в формате PEM\n" +
+			"открытый ключ передается файлом --public-key. С флагом --generate создается новый ключ, например:\n" +
+			"passcli save-ssh-key deploy --from-file ~/.ssh/id_ed25519\n" +
+			"passcli save-ssh-key deploy --generate --type ed25519 --comment deploy@ci",
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			fromFile, _ := cmd.Flags().GetString("from-file")
+			publicKeyFile, _ := cmd.Flags().GetString("public-key")
+			comment, _ := cmd.Flags().GetString("comment")
+			generate, _ := cmd.Flags().GetBool("generate")
+			keyType, _ := cmd.Flags().GetString("type")
+			bits, _ := cmd.Flags().GetInt("bits")
+
+			if generate && (fromFile != "" || publicKeyFile != "") {
+				return fail("Ошибка при сохранении ключа SSH:", fmt.Errorf("%w: флаг --generate нельзя использовать с --from-file и --public-key", errUsage))
+			}
+			if !generate && (cmd.Flags().Changed("type") || cmd.Flags().Changed("bits")) {
+				return fail("Ошибка при сохранении ключа SSH:", fmt.Errorf("%w: флаги --type и --bits используются только с --generate", errUsage))
+			}
+
+			// Ключ создается до запросов, чтобы неверный тип не заставлял вводить данные повторно
+			sshKeyData := &domain.SSHKeyData{Comment: comment}
+			if generate {
+				var err error
+				if sshKeyData, err = sshkey.Generate(keyType, bits, comment); err != nil {
+					return fail("Ошибка при сохранении ключа SSH:", fmt.Errorf("%w: %v", errUsage, err))
+				}
+			}
+
+			in := newInput()
+			label, err := promptLabel(cmd, args, in, "Введите уникальное название (label) для ключа SSH:")
+			if err != nil {
+				return fail("Ошибка при сохранении ключа SSH:", err)
+			}
+
+			if !generate {
+				var privateKey string
+				if fromFile != "" {
+					data, err := in.source(fromFile)
+					if err != nil {
+						return fail("Ошибка при сохранении ключа SSH:", err)
+					}
+					privateKey = string(data)
+				} else {
+					privateKey, err = in.text("Вставьте закрытый ключ, завершите ввод Ctrl+D:", "закрытый ключ передается флагом --from-file, '-' для stdin")
+					if err != nil {
+						return fail("Ошибка при сохранении ключа SSH:", err)
+					}
+				}
+				// Ключ PEM завершается переводом строки, без него ssh отказывается читать файл ключа
+				sshKeyData.PrivateKey = strings.TrimSpace(privateKey) + "\n"
+
+				if publicKeyFile != "" {
+					data, err := os.ReadFile(publicKeyFile)
+					if err != nil {
+						return fail("Ошибка при сохранении ключа SSH:", err)
+					}
+					sshKeyData.PublicKey = strings.TrimSpace(string(data))
+				}
+			}
+
+			metadata := promptMetadata(cmd, in)
+
+			// Вызываем метод сохранения ключа, открытый ключ и отпечаток заполняются перед отправкой
+			err = c.clientUseCase.SaveSSHKey(label, sshKeyData, metadata)
+			if errors.Is(err, sshkey.ErrInvalidKey) || errors.Is(err, sshkey.ErrPassphraseRequired) {
+				err = fmt.Errorf("%w: %v", errUsage, err)
+			}
+			if err != nil {
+				return fail("Ошибка при сохранении ключа SSH:", err)
+			}
+
+			fmt.Println("Ключ SSH успешно сохранен!")
+			fmt.Println("Отпечаток:", sshKeyData.Fingerprint)
+			if generate {
+				fmt.Println("Открытый ключ:", sshKeyData.PublicKey)
+			}
+			return nil
+		},
+	}
+
+	addSaveFlags(cmd, "Файл закрытого ключа, '-' для чтения из stdin")
+	cmd.Flags().String("public-key", "", "Файл открытого ключа, нужен для зашифрованного ключа в формате PEM")
+	cmd.Flags().String("comment", "", "Комментарий ключа, по умолчанию берется из открытого ключа")
+	cmd.Flags().Bool("generate", false, "Создать новый ключ вместо ввода")
+	cmd.Flags().String("type", sshkey.TypeEd25519, "Тип создаваемого ключа: ed25519, rsa или ecdsa")
+	cmd.Flags().Int("bits", 0, "Размер ключа rsa (по умолчанию 4096) или кривая ecdsa: 256, 384 или 521")
+
+	return cmd
+}
+
+// GetSSHKeyCmd создает команду получения ключа SSH
+func (c *Command) GetSSHKeyCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "get-ssh-key [label]",
+		Short: "Получение ключа SSH",
+		Long: "Получение ключа SSH. Открытый ключ для authorized_keys выводится флагом --field public_key,\n" +
+			"например: passcli get-ssh-key deploy --field public_key >> ~/.ssh/authorized_keys",
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out, err := newPrinter(cmd)
+			if err != nil {
+				return fail("Ошибка при получении ключа SSH:", err)
+			}
+			label, err := promptLabel(cmd, args, newInput(), "Введите уникальное название (label) ключа SSH для получения:")
+			if err != nil {
+				return fail("Ошибка при получении ключа SSH:", err)
+			}
+
+			// Вызываем метод получения ключа
+			sshKeyData, metadata, err := c.clientUseCase.GetSSHKey(label)
+			if err != nil {
+				return fail("Ошибка при получении ключа SSH:", err)
+			}
+
+			item := &sshKeyItem{Type: domain.UserDataTypeSSHKey, Label: label, PrivateKey: sshKeyData.PrivateKey,
+				PublicKey: sshKeyData.PublicKey, Fingerprint: sshKeyData.Fingerprint, Comment: sshKeyData.Comment, Metadata: metadata}
+			err = out.print(item, func() {
+				fmt.Println("\nПолученный ключ SSH:")
+				fmt.Println("------------------")
+				fmt.Println("Отпечаток:", sshKeyData.Fingerprint)
+				if sshKeyData.Comment != "" {
+					fmt.Println("Комментарий:", sshKeyData.Comment)
+				}
+				fmt.Println("Открытый ключ:", sshKeyData.PublicKey)
+				fmt.Println("------------------")
+				fmt.Print(sshKeyData.PrivateKey)
+				fmt.Println("------------------")
+
+				if metadata != "" {
+					fmt.Println("\nМетаинформация:")
+					fmt.Println("------------------")
+					fmt.Println(metadata)
+					fmt.Println("------------------")
+				}
+			})
+			if err != nil {
+				return fail("Ошибка при получении ключа SSH:", err)
+			}
+			return nil
+		},
+	}
+
+	addLabelFlag(cmd)
+
+	return cmd
+}
+
+// DeleteSSHKeyCmd создает команду удаления ключа SSH
+func (c *Command) DeleteSSHKeyCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "delete-ssh-key [label]",
+		Short: "Удаление ключа SSH",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			label, err := promptLabel(cmd, args, newInput(), "Введите уникальное название (label) ключа SSH для удаления:")
+			if err != nil {
+				return fail("Ошибка при удалении ключа SSH:", err)
+			}
+
+			// Вызываем метод удаления ключа
+			err = c.clientUseCase.DeleteSSHKey(label)
+			if err != nil {
+				return fail("Ошибка при удалении ключа SSH:", err)
+			}
+
+			fmt.Println("Ключ SSH успешно удален!")
+			return nil
+		},
+	}
+
+	addLabelFlag(cmd)
+
+	return cmd
+}
@@ -0,0 +1,149 @@
+package command
+
+import (
+	"github.com/SmirnovND/gophkeeper/internal/domain"
+	"github.com/SmirnovND/gophkeeper/internal/sshkey"
+	"golang.org/x/crypto/ssh/agent"
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// TestCommand_SaveSSHKeyCmd тестирует создание ключа, загрузку ключа из файла и отказ от неверных флагов и ключей
+func TestCommand_SaveSSHKeyCmd(t *testing.T) {
+	vault := newFakeVault()
+
+	withStdin(t, "", false)
+	out, err := runWithOutput(t, vault.command().SaveSSHKeyCmd(), "deploy", "--generate", "--comment", "deploy@ci", "--metadata", "ci")
+	if err != nil {
+		t.Fatalf("Неожиданная ошибка: %v", err)
+	}
+	generated := vault.sshKeys["deploy"]
+	if generated == nil || !strings.HasPrefix(generated.PublicKey, "ssh-ed25519 ") || vault.items["deploy"].Metadata != "ci" {
+		t.Fatalf("Ключ сохранен неверно: %+v", generated)
+	}
+	if !strings.Contains(out, generated.Fingerprint) || !strings.Contains(out, generated.PublicKey) {
+		t.Errorf("Ожидались отпечаток и открытый ключ в выводе, получено %q", out)
+	}
+
+	path := filepath.Join(t.TempDir(), "id_ed25519")
+	if err := os.WriteFile(path, []byte("\n"+strings.TrimSpace(generated.PrivateKey)), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	withStdin(t, "", false)
+	if _, err := runWithOutput(t, vault.command().SaveSSHKeyCmd(), "copy", "--from-file", path); err != nil {
+		t.Fatalf("Неожиданная ошибка: %v", err)
+	}
+	if saved := vault.sshKeys["copy"]; saved.PrivateKey != generated.PrivateKey || saved.Fingerprint != generated.Fingerprint {
+		t.Errorf("Ключ из файла сохранен неверно: %+v", saved)
+	}
+
+	tests := []struct {
+		name  string
+		input string
+		args  []string
+	}{
+		{"generate и from-file", "", []string{"bad", "--generate", "--from-file", path}},
+		{"type без generate", "", []string{"bad", "--from-file", path, "--type", "rsa"}},
+		{"неизвестный тип", "", []string{"bad", "--generate", "--type", "dsa"}},
+		{"не ключ", "not a key", []string{"bad", "--from-file", "-"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			withStdin(t, tt.input, false)
+			if _, err := runWithOutput(t, vault.command().SaveSSHKeyCmd(), tt.args...); ExitCode(err) != ExitUsage {
+				t.Errorf("Ожидалась ошибка использования, получено: %v", err)
+			}
+		})
+	}
+	if _, ok := vault.items["bad"]; ok {
+		t.Error("Неверный ключ не должен сохраняться")
+	}
+}
+
+// TestCommand_GetSSHKeyCmd тестирует вывод открытого ключа для authorized_keys
+func TestCommand_GetSSHKeyCmd(t *testing.T) {
+	vault := newFakeVault()
+	data, _ := sshkey.Generate(sshkey.TypeEd25519, 0, "alice@laptop")
+	vault.command().clientUseCase.SaveSSHKey("deploy", data, "")
+
+	out, err := runWithOutput(t, vault.command().GetSSHKeyCmd(), "deploy", "--field", "public_key")
+	if err != nil {
+		t.Fatalf("Неожиданная ошибка: %v", err)
+	}
+	if strings.TrimSpace(out) != data.PublicKey {
+		t.Errorf("Ожидался открытый ключ %q, получено %q", data.PublicKey, out)
+	}
+}
+
+// TestCommand_SSHAgent тестирует загрузку ключей в агент и подключение к его сокету
+func TestCommand_SSHAgent(t *testing.T) {
+	vault := newFakeVault()
+	for _, label := range []string{"deploy", "backup"} {
+		data, _ := sshkey.Generate(sshkey.TypeEd25519, 0, "")
+		vault.command().clientUseCase.SaveSSHKey(label, data, "")
+	}
+	vault.command().clientUseCase.SaveText("note", &domain.TextData{Content: "заметка"}, "")
+
+	a := sshkey.NewAgent(nil)
+	if _, err := vault.command().loadSSHKeys(a, newInput(), []string{"note"}); ExitCode(&reportedError{err: err}) != ExitNotFound {
+		t.Errorf("Ожидалась ошибка отсутствия ключа, получено: %v", err)
+	}
+	loaded, err := vault.command().loadSSHKeys(a, newInput(), nil)
+	if err != nil || loaded != 2 {
+		t.Fatalf("Ожидалась загрузка двух ключей, получено %d, %v", loaded, err)
+	}
+
+	socket := filepath.Join(t.TempDir(), "agent.sock")
+	listener, cleanup, err := listenAgent(socket)
+	if err != nil {
+		t.Skipf("Сокеты unix недоступны: %v", err)
+	}
+	go a.Serve(listener)
+
+	if info, err := os.Stat(socket); err != nil || (runtime.GOOS != "windows" && info.Mode().Perm() != 0o600) {
+		t.Errorf("Неверные права сокета: %v, %v", info, err)
+	}
+	if _, _, err := listenAgent(socket); ExitCode(&reportedError{err: err}) != ExitUsage {
+		t.Errorf("Сокет работающего агента не должен заменяться, получено: %v", err)
+	}
+
+	conn, err := net.Dial("unix", socket)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keys, err := agent.NewClient(conn).List()
+	conn.Close()
+	if err != nil || len(keys) != 2 || keys[0].Comment != "backup" {
+		t.Errorf("Неверный список ключей агента: %v, %v", keys, err)
+	}
+
+	cleanup()
+	if _, err := os.Stat(socket); !os.IsNotExist(err) {
+		t.Errorf("Сокет должен удаляться после остановки агента: %v", err)
+	}
+}
+
+// TestCommand_SSHAgentCmd_Command тестирует запуск команды с SSH_AUTH_SOCK агента
+func TestCommand_SSHAgentCmd_Command(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("тест использует sh")
+	}
+	vault := newFakeVault()
+	data, _ := sshkey.Generate(sshkey.TypeEd25519, 0, "")
+	vault.command().clientUseCase.SaveSSHKey("deploy", data, "")
+
+	withStdin(t, "", false)
+	_, err := runWithOutput(t, vault.command().SSHAgentCmd(), "deploy", "--", "sh", "-c", `test -S "$SSH_AUTH_SOCK" && exit 3`)
+	if ExitCode(err) != 3 {
+		t.Errorf("Ожидался код завершения команды 3, получено: %v", err)
+	}
+
+	withStdin(t, "", false)
+	if _, err := runWithOutput(t, vault.command().SSHAgentCmd(), "deploy", "--confirm", "--", "true"); ExitCode(err) != ExitUsage {
+		t.Errorf("Флаг --confirm без терминала должен отклоняться, получено: %v", err)
+	}
+}
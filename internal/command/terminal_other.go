@@ -0,0 +1,14 @@
+//go:build !aix && !darwin && !dragonfly && !freebsd && !linux && !netbsd && !openbsd && !solaris && !zos && !windows
+
+package command
+
+import (
+	"errors"
+	"runtime"
+)
+
+// terminalDisableEcho сообщает, что скрытый ввод на этой платформе не поддерживается.
+// Секреты в таком случае передаются через stdin с флагом --password-stdin или из файла
+func terminalDisableEcho(fd uintptr) (func(), error) {
+	return nil, errors.New("скрытый ввод не поддерживается на " + runtime.GOOS)
+}
@@ -0,0 +1,26 @@
+//go:build aix || darwin || dragonfly || freebsd || linux || netbsd || openbsd || solaris || zos
+
+package command
+
+import (
+	"golang.org/x/sys/unix"
+)
+
+// terminalDisableEcho отключает отображение вводимых символов на терминале fd, режим построчного ввода сохраняется
+func terminalDisableEcho(fd uintptr) (func(), error) {
+	termios, err := unix.IoctlGetTermios(int(fd), ioctlReadTermios)
+	if err != nil {
+		return nil, err
+	}
+
+	hidden := *termios
+	hidden.Lflag &^= unix.ECHO
+	hidden.Lflag |= unix.ICANON | unix.ISIG
+	if err := unix.IoctlSetTermios(int(fd), ioctlWriteTermios, &hidden); err != nil {
+		return nil, err
+	}
+
+	return func() {
+		_ = unix.IoctlSetTermios(int(fd), ioctlWriteTermios, termios)
+	}, nil
+}
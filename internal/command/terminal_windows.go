@@ -0,0 +1,24 @@
+//go:build windows
+
+package command
+
+import (
+	"golang.org/x/sys/windows"
+)
+
+// terminalDisableEcho отключает отображение вводимых символов в консоли fd, режим построчного ввода сохраняется
+func terminalDisableEcho(fd uintptr) (func(), error) {
+	var mode uint32
+	if err := windows.GetConsoleMode(windows.Handle(fd), &mode); err != nil {
+		return nil, err
+	}
+
+	hidden := mode&^windows.ENABLE_ECHO_INPUT | windows.ENABLE_LINE_INPUT | windows.ENABLE_PROCESSED_INPUT
+	if err := windows.SetConsoleMode(windows.Handle(fd), hidden); err != nil {
+		return nil, err
+	}
+
+	return func() {
+		_ = windows.SetConsoleMode(windows.Handle(fd), mode)
+	}, nil
+}
@@ -0,0 +1,35 @@
+package command
+
+import (
+	"fmt"
+	"github.com/SmirnovND/gophkeeper/internal/interfaces"
+	"github.com/SmirnovND/gophkeeper/internal/tui"
+	"github.com/spf13/cobra"
+)
+
+// runTUI показывает полноэкранный интерфейс в терминале пользователя
+var runTUI = func(clientUseCase interfaces.ClientUseCase) error {
+	return tui.New(clientUseCase).Run(nil)
+}
+
+// TUICmd создает команду полноэкранного интерфейса для просмотра и изменения записей
+func (c *Command) TUICmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "tui",
+		Short: "Полноэкранный интерфейс для просмотра и изменения записей",
+		Long: "Полноэкранный интерфейс: список записей с поиском, просмотр записи со скрытыми секретами,\n" +
+			"создание, изменение и удаление записей, загрузка и скачивание файлов.\n" +
+			"Клавиши: / поиск, n новая запись, e изменить, d удалить, r показать или скрыть секреты,\n" +
+			"s скачать файл, Ctrl+R обновить список, Tab переключение между поиском и списком, q выход",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if !stdinIsTerminal() || !stdoutIsTerminal() {
+				return fail("Ошибка при запуске интерфейса:", fmt.Errorf("%w: passcli tui работает только в терминале", errUsage))
+			}
+			if err := runTUI(c.clientUseCase); err != nil {
+				return fail("Ошибка интерфейса:", err)
+			}
+			return nil
+		},
+	}
+}
@@ -0,0 +1,38 @@
+package command
+
+import (
+	"errors"
+	"github.com/SmirnovND/gophkeeper/internal/interfaces"
+	"testing"
+)
+
+// TestCommand_TUICmd тестирует запуск интерфейса только в терминале
+func TestCommand_TUICmd(t *testing.T) {
+	oldRunTUI, oldIsTerminal := runTUI, stdoutIsTerminal
+	defer func() { runTUI, stdoutIsTerminal = oldRunTUI, oldIsTerminal }()
+
+	started := 0
+	runTUI = func(clientUseCase interfaces.ClientUseCase) error {
+		started++
+		return nil
+	}
+	stdoutIsTerminal = func() bool { return true }
+	cmd := &Command{clientUseCase: &MockDataClientUseCase{}}
+
+	withStdin(t, "", false)
+	err := cmd.TUICmd().Execute()
+	if !errors.Is(err, errUsage) || ExitCode(err) != ExitUsage {
+		t.Errorf("Ожидалась ошибка неверных аргументов вне терминала, получена %v", err)
+	}
+	if started != 0 {
+		t.Error("Интерфейс запущен вне терминала")
+	}
+
+	withStdin(t, "", true)
+	if err := cmd.TUICmd().Execute(); err != nil {
+		t.Fatalf("Неожиданная ошибка: %v", err)
+	}
+	if started != 1 {
+		t.Error("Интерфейс не запущен")
+	}
+}
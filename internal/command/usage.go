@@ -0,0 +1,52 @@
+package command
+
+import (
+	"fmt"
+	"github.com/SmirnovND/gophkeeper/pkg"
+	"github.com/spf13/cobra"
+)
+
+// UsageCmd создает команду для получения потребления хранилища и квоты
+func (c *Command) UsageCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "usage",
+		Short: "Потребление хранилища и квота",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out, err := newPrinter(cmd)
+			if err != nil {
+				return fail("Ошибка при получении потребления хранилища:", err)
+			}
+			usage, err := c.clientUseCase.Usage()
+			if err != nil {
+				return fail("Ошибка при получении потребления хранилища:", err)
+			}
+
+			err = out.print(usage, func() {
+				fmt.Println("\nПотребление хранилища:")
+				fmt.Println("----------------------")
+				fmt.Println("Записей:", formatLimit(fmt.Sprint(usage.Items), usage.Quota.MaxItems, fmt.Sprint(usage.Quota.MaxItems)))
+				fmt.Println("Файлов:", usage.Files)
+				fmt.Println("Объем файлов:", formatLimit(pkg.FormatBytes(usage.TotalBytes), usage.Quota.MaxTotalBytes, pkg.FormatBytes(usage.Quota.MaxTotalBytes)))
+				if usage.Quota.MaxFileSize > 0 {
+					fmt.Println("Максимальный размер файла:", pkg.FormatBytes(usage.Quota.MaxFileSize))
+				} else {
+					fmt.Println("Максимальный размер файла: без ограничений")
+				}
+				fmt.Println("----------------------")
+			})
+			if err != nil {
+				return fail("Ошибка при получении потребления хранилища:", err)
+			}
+			return nil
+		},
+	}
+}
+
+// formatLimit выводит значение вместе с лимитом, нулевой лимит означает отсутствие ограничения
+func formatLimit(value string, limit int64, limitText string) string {
+	if limit == 0 {
+		return value + " (без ограничений)"
+	}
+	return value + " из " + limitText
+}
@@ -0,0 +1,92 @@
+package command
+
+import (
+	"bytes"
+	"errors"
+	"github.com/SmirnovND/gophkeeper/internal/domain"
+	"io"
+	"os"
+	"strings"
+	"testing"
+)
+
+// TestCommand_UsageCmd_Success тестирует вывод потребления хранилища
+func TestCommand_UsageCmd_Success(t *testing.T) {
+	// Создаем буфер для перехвата вывода
+	var buf bytes.Buffer
+	oldStdout := os.Stdout
+	defer func() { os.Stdout = oldStdout }()
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+
+	// Создаем мок для ClientUseCase
+	mockClientUseCase := &MockFileClientUseCase{
+		UsageFunc: func() (*domain.Usage, error) {
+			return &domain.Usage{
+				TotalBytes: 1536,
+				Items:      3,
+				Files:      1,
+				Quota: domain.Quota{
+					MaxTotalBytes: 1048576,
+					MaxItems:      0,
+					MaxFileSize:   2048,
+				},
+			}, nil
+		},
+	}
+
+	cmd := &Command{
+		clientUseCase: mockClientUseCase,
+	}
+
+	usageCmd := cmd.UsageCmd()
+	usageCmd.RunE(usageCmd, []string{})
+
+	w.Close()
+	io.Copy(&buf, r)
+
+	output := buf.String()
+	for _, expected := range []string{
+		"Записей: 3 (без ограничений)",
+		"Файлов: 1",
+		"Объем файлов: 1.5 КБ из 1.0 МБ",
+		"Максимальный размер файла: 2.0 КБ",
+	} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Ожидалось '%s' в выводе, получено: %s", expected, output)
+		}
+	}
+}
+
+// TestCommand_UsageCmd_Error тестирует ошибку при получении потребления хранилища
+func TestCommand_UsageCmd_Error(t *testing.T) {
+	var buf bytes.Buffer
+	oldStdout := os.Stdout
+	defer func() { os.Stdout = oldStdout }()
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+	oldStderr := os.Stderr
+	defer func() { os.Stderr = oldStderr }()
+	os.Stderr = w
+
+	mockClientUseCase := &MockFileClientUseCase{
+		UsageFunc: func() (*domain.Usage, error) {
+			return nil, errors.New("ошибка сети")
+		},
+	}
+
+	cmd := &Command{
+		clientUseCase: mockClientUseCase,
+	}
+
+	usageCmd := cmd.UsageCmd()
+	usageCmd.RunE(usageCmd, []string{})
+
+	w.Close()
+	io.Copy(&buf, r)
+
+	output := buf.String()
+	if !strings.Contains(output, "Ошибка при получении потребления хранилища:") {
+		t.Errorf("Ожидалось сообщение об ошибке, получено: %s", output)
+	}
+}
@@ -0,0 +1,345 @@
+package config
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"github.com/SmirnovND/gophkeeper/internal/domain"
+	"github.com/SmirnovND/gophkeeper/internal/interfaces"
+	"gopkg.in/yaml.v3"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Переменные окружения, переопределяющие файл конфигурации и настройки выбранного профиля
+const (
+	EnvConfig             = "PASSCLI_CONFIG"
+	EnvProfile            = "PASSCLI_PROFILE"
+	EnvServer             = "PASSCLI_SERVER"
+	EnvOutput             = "PASSCLI_OUTPUT"
+	EnvCAFile             = "PASSCLI_CA_FILE"
+	EnvInsecureSkipVerify = "PASSCLI_INSECURE_SKIP_VERIFY"
+	EnvToken              = "PASSCLI_TOKEN"
+)
+
+// DefaultProfile - профиль, который используется, пока не выбран другой.
+// Если он не описан в файле конфигурации, его сервер задается при сборке клиента
+const DefaultProfile = "default"
+
+// profileName ограничивает имена профилей, так как имя входит в путь к файлу токена
+var profileName = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_.-]*$`)
+
+// Config - конфигурация passcli, хранится в config.yaml в директории конфигурации passcli
+type Config struct {
+	CurrentProfile string                     `yaml:"current_profile,omitempty"`
+	Profiles       map[string]*domain.Profile `yaml:"profiles,omitempty"`
+
+	defaultServer string
+	active        string
+}
+
+// NewConfig создает конфигурацию клиента. Файл читается при выборе профиля,
+// defaultServer - адрес сервера профиля default, заданный при сборке
+func NewConfig(defaultServer string) interfaces.ConfigClient {
+	return &Config{defaultServer: defaultServer, active: DefaultProfile}
+}
+
+// Load читает файл конфигурации, отсутствие файла означает пустую конфигурацию
+func (c *Config) Load() error {
+	path, err := c.configPath()
+	if err != nil {
+		return err
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("ошибка при чтении конфигурации %s: %w", path, err)
+	}
+	if err := yaml.Unmarshal(data, c); err != nil {
+		return fmt.Errorf("ошибка при разборе конфигурации %s: %w", path, err)
+	}
+	return nil
+}
+
+// Select читает файл конфигурации и выбирает профиль. Пустое имя означает профиль из PASSCLI_PROFILE,
+// а без нее - профиль, выбранный командой passcli profile use
+func (c *Config) Select(name string) error {
+	if err := c.Load(); err != nil {
+		return err
+	}
+
+	if name == "" {
+		name = os.Getenv(EnvProfile)
+	}
+	if name == "" {
+		name = c.CurrentProfile
+	}
+	if name == "" {
+		name = DefaultProfile
+	}
+
+	if _, ok := c.Profiles[name]; !ok && name != DefaultProfile {
+		return fmt.Errorf("%w: '%s', доступные профили: %s", domain.ErrProfileNotFound, name, strings.Join(c.profileNames(), ", "))
+	}
+	c.active = name
+	return nil
+}
+
+// GetProfileName возвращает имя выбранного профиля
+func (c *Config) GetProfileName() string {
+	return c.active
+}
+
+// GetServerAddress возвращает адрес сервера выбранного профиля: host:port или URL со схемой http или https
+func (c *Config) GetServerAddress() string {
+	if server := os.Getenv(EnvServer); server != "" {
+		return server
+	}
+	return c.profile().Server
+}
+
+// GetTLSConfig возвращает настройки TLS соединения с сервером выбранного профиля
+func (c *Config) GetTLSConfig() (*tls.Config, error) {
+	settings := c.profile().TLS
+	if caFile := os.Getenv(EnvCAFile); caFile != "" {
+		settings.CAFile = caFile
+	}
+	if insecure := os.Getenv(EnvInsecureSkipVerify); insecure != "" {
+		value, err := strconv.ParseBool(insecure)
+		if err != nil {
+			return nil, fmt.Errorf("неверное значение %s: %w", EnvInsecureSkipVerify, err)
+		}
+		settings.InsecureSkipVerify = value
+	}
+
+	tlsConfig := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: settings.InsecureSkipVerify,
+	}
+	if settings.CAFile == "" {
+		return tlsConfig, nil
+	}
+
+	pem, err := os.ReadFile(settings.CAFile)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка при чтении сертификатов %s: %w", settings.CAFile, err)
+	}
+	pool, err := x509.SystemCertPool()
+	if err != nil {
+		pool = x509.NewCertPool()
+	}
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("в файле %s нет сертификатов в формате PEM", settings.CAFile)
+	}
+	tlsConfig.RootCAs = pool
+	return tlsConfig, nil
+}
+
+// GetOutput возвращает формат вывода по умолчанию выбранного профиля, пустое значение означает формат table
+func (c *Config) GetOutput() string {
+	if output := os.Getenv(EnvOutput); output != "" {
+		return output
+	}
+	return c.profile().Output
+}
+
+// GetTokenOverride возвращает токен из PASSCLI_TOKEN, который используется вместо сохраненного при входе
+func (c *Config) GetTokenOverride() string {
+	return os.Getenv(EnvToken)
+}
+
+// GetTokenPath возвращает путь к файлу токена выбранного профиля.
+// Токен профиля default хранится там же, где до появления профилей, чтобы вход не терялся при обновлении
+func (c *Config) GetTokenPath() (string, error) {
+	return c.tokenPath(c.active)
+}
+
+// ListProfiles возвращает профили, отсортированные по имени
+func (c *Config) ListProfiles() []domain.Profile {
+	current := c.CurrentProfile
+	if current == "" {
+		current = DefaultProfile
+	}
+
+	profiles := make([]domain.Profile, 0, len(c.Profiles)+1)
+	for _, name := range c.profileNames() {
+		profile := c.lookup(name)
+		profile.Current = name == current
+		profiles = append(profiles, profile)
+	}
+	return profiles
+}
+
+// AddProfile добавляет профиль и сохраняет конфигурацию
+func (c *Config) AddProfile(profile domain.Profile) error {
+	if !profileName.MatchString(profile.Name) {
+		return fmt.Errorf("недопустимое имя профиля '%s': разрешены латинские буквы, цифры, '.', '_' и '-'", profile.Name)
+	}
+	if _, ok := c.Profiles[profile.Name]; ok {
+		return fmt.Errorf("профиль '%s' уже существует, удалите его командой passcli profile remove", profile.Name)
+	}
+	if err := validateServer(profile.Server); err != nil {
+		return err
+	}
+
+	if c.Profiles == nil {
+		c.Profiles = make(map[string]*domain.Profile)
+	}
+	profile.Current = false
+	c.Profiles[profile.Name] = &profile
+	return c.save()
+}
+
+// UseProfile делает профиль текущим и сохраняет конфигурацию
+func (c *Config) UseProfile(name string) error {
+	if _, ok := c.Profiles[name]; !ok && name != DefaultProfile {
+		return fmt.Errorf("%w: '%s'", domain.ErrProfileNotFound, name)
+	}
+	c.CurrentProfile = name
+	return c.save()
+}
+
+// RemoveProfile удаляет профиль вместе с его токеном и сохраняет конфигурацию.
+// Профиль default после удаления снова использует сервер, заданный при сборке
+func (c *Config) RemoveProfile(name string) error {
+	if _, ok := c.Profiles[name]; !ok {
+		return fmt.Errorf("%w: '%s'", domain.ErrProfileNotFound, name)
+	}
+
+	delete(c.Profiles, name)
+	if c.CurrentProfile == name {
+		c.CurrentProfile = ""
+	}
+	if err := c.save(); err != nil {
+		return err
+	}
+
+	if name == DefaultProfile {
+		return nil
+	}
+	tokenPath, err := c.tokenPath(name)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(tokenPath); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("ошибка при удалении токена профиля: %w", err)
+	}
+	return nil
+}
+
+// profile возвращает выбранный профиль
+func (c *Config) profile() domain.Profile {
+	return c.lookup(c.active)
+}
+
+// lookup возвращает профиль по имени, профиль default существует, даже если не описан в файле
+func (c *Config) lookup(name string) domain.Profile {
+	if profile, ok := c.Profiles[name]; ok {
+		result := *profile
+		result.Name = name
+		return result
+	}
+	return domain.Profile{Name: DefaultProfile, Server: c.defaultServer}
+}
+
+// profileNames возвращает отсортированные имена профилей, включая default
+func (c *Config) profileNames() []string {
+	names := []string{DefaultProfile}
+	for name := range c.Profiles {
+		if name != DefaultProfile {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names[1:])
+	return names
+}
+
+// save атомарно записывает конфигурацию, файл доступен только владельцу
+func (c *Config) save() error {
+	path, err := c.configPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+
+	var data bytes.Buffer
+	encoder := yaml.NewEncoder(&data)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(c); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".config-*.yaml")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data.Bytes()); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// configPath возвращает путь к файлу конфигурации, PASSCLI_CONFIG задает его явно
+func (c *Config) configPath() (string, error) {
+	if path := os.Getenv(EnvConfig); path != "" {
+		return path, nil
+	}
+
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "passcli", "config.yaml"), nil
+}
+
+// tokenPath возвращает путь к файлу токена профиля рядом с файлом конфигурации
+func (c *Config) tokenPath(name string) (string, error) {
+	path, err := c.configPath()
+	if err != nil {
+		return "", err
+	}
+
+	dir := filepath.Dir(path)
+	if name == DefaultProfile {
+		return filepath.Join(dir, "auth.json"), nil
+	}
+	return filepath.Join(dir, "tokens", name+".json"), nil
+}
+
+// validateServer проверяет адрес сервера профиля: host:port или URL со схемой http или https
+func validateServer(server string) error {
+	if server == "" {
+		return errors.New("не задан адрес сервера профиля")
+	}
+	if !strings.Contains(server, "://") {
+		server = "http://" + server
+	}
+
+	parsed, err := url.Parse(server)
+	if err != nil || parsed.Host == "" || (parsed.Scheme != "http" && parsed.Scheme != "https") {
+		return fmt.Errorf("неверный адрес сервера '%s', ожидается host:port или URL со схемой http или https", server)
+	}
+	return nil
+}
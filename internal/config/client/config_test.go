@@ -0,0 +1,220 @@
+package config
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"github.com/SmirnovND/gophkeeper/internal/domain"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// newTestConfig создает конфигурацию с файлом во временной директории
+func newTestConfig(t *testing.T) *Config {
+	t.Setenv(EnvConfig, filepath.Join(t.TempDir(), "passcli", "config.yaml"))
+	for _, env := range []string{EnvProfile, EnvServer, EnvOutput, EnvCAFile, EnvInsecureSkipVerify, EnvToken} {
+		t.Setenv(env, "")
+	}
+	return NewConfig("127.0.0.1:8080").(*Config)
+}
+
+func TestConfig_DefaultProfile(t *testing.T) {
+	config := newTestConfig(t)
+
+	if err := config.Select(""); err != nil {
+		t.Fatalf("Неожиданная ошибка: %v", err)
+	}
+	if config.GetProfileName() != DefaultProfile {
+		t.Errorf("Ожидался профиль default, получен '%s'", config.GetProfileName())
+	}
+	if config.GetServerAddress() != "127.0.0.1:8080" {
+		t.Errorf("Ожидался адрес сервера, заданный при сборке, получен '%s'", config.GetServerAddress())
+	}
+
+	// Токен профиля default хранится там же, где до появления профилей
+	tokenPath, _ := config.GetTokenPath()
+	if filepath.Base(tokenPath) != "auth.json" {
+		t.Errorf("Ожидался файл токена auth.json, получен '%s'", tokenPath)
+	}
+}
+
+func TestConfig_SelectPrecedence(t *testing.T) {
+	config := newTestConfig(t)
+	for _, name := range []string{"work", "home", "ci"} {
+		if err := config.AddProfile(domain.Profile{Name: name, Server: name + ".example.com:443"}); err != nil {
+			t.Fatalf("Неожиданная ошибка: %v", err)
+		}
+	}
+	if err := config.UseProfile("work"); err != nil {
+		t.Fatalf("Неожиданная ошибка: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		flag    string
+		env     string
+		profile string
+	}{
+		{name: "Current profile", profile: "work"},
+		{name: "Environment", env: "home", profile: "home"},
+		{name: "Flag", flag: "ci", env: "home", profile: "ci"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(EnvProfile, tt.env)
+
+			// Каждая команда читает конфигурацию заново
+			loaded := NewConfig("127.0.0.1:8080").(*Config)
+			if err := loaded.Select(tt.flag); err != nil {
+				t.Fatalf("Неожиданная ошибка: %v", err)
+			}
+			if loaded.GetProfileName() != tt.profile {
+				t.Errorf("Ожидался профиль '%s', получен '%s'", tt.profile, loaded.GetProfileName())
+			}
+			if loaded.GetServerAddress() != tt.profile+".example.com:443" {
+				t.Errorf("Неожиданный адрес сервера '%s'", loaded.GetServerAddress())
+			}
+		})
+	}
+
+	if err := config.Select("missing"); !errors.Is(err, domain.ErrProfileNotFound) {
+		t.Errorf("Ожидалась ошибка отсутствующего профиля, получена %v", err)
+	}
+}
+
+func TestConfig_EnvironmentOverrides(t *testing.T) {
+	config := newTestConfig(t)
+	if err := config.AddProfile(domain.Profile{Name: "work", Server: "https://vault.example.com", Output: "json"}); err != nil {
+		t.Fatalf("Неожиданная ошибка: %v", err)
+	}
+	if err := config.Select("work"); err != nil {
+		t.Fatalf("Неожиданная ошибка: %v", err)
+	}
+
+	t.Setenv(EnvServer, "https://staging.example.com")
+	t.Setenv(EnvOutput, "yaml")
+	t.Setenv(EnvToken, "ci-token")
+	t.Setenv(EnvInsecureSkipVerify, "true")
+
+	if config.GetServerAddress() != "https://staging.example.com" {
+		t.Errorf("Ожидался адрес из %s, получен '%s'", EnvServer, config.GetServerAddress())
+	}
+	if config.GetOutput() != "yaml" {
+		t.Errorf("Ожидался формат из %s, получен '%s'", EnvOutput, config.GetOutput())
+	}
+	if config.GetTokenOverride() != "ci-token" {
+		t.Errorf("Ожидался токен из %s, получен '%s'", EnvToken, config.GetTokenOverride())
+	}
+	tlsConfig, err := config.GetTLSConfig()
+	if err != nil || !tlsConfig.InsecureSkipVerify {
+		t.Errorf("Ожидалось отключение проверки сертификата из %s, ошибка %v", EnvInsecureSkipVerify, err)
+	}
+
+	t.Setenv(EnvInsecureSkipVerify, "maybe")
+	if _, err := config.GetTLSConfig(); err == nil {
+		t.Errorf("Ожидалась ошибка неверного значения %s", EnvInsecureSkipVerify)
+	}
+}
+
+func TestConfig_CAFile(t *testing.T) {
+	config := newTestConfig(t)
+	dir := t.TempDir()
+
+	server := httptest.NewTLSServer(nil)
+	defer server.Close()
+	caFile := filepath.Join(dir, "ca.pem")
+	certificate := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	if err := os.WriteFile(caFile, certificate, 0600); err != nil {
+		t.Fatalf("Не удалось записать сертификат: %v", err)
+	}
+
+	if err := config.AddProfile(domain.Profile{Name: "work", Server: server.URL, TLS: domain.ProfileTLS{CAFile: caFile}}); err != nil {
+		t.Fatalf("Неожиданная ошибка: %v", err)
+	}
+	if err := config.Select("work"); err != nil {
+		t.Fatalf("Неожиданная ошибка: %v", err)
+	}
+
+	tlsConfig, err := config.GetTLSConfig()
+	if err != nil {
+		t.Fatalf("Неожиданная ошибка: %v", err)
+	}
+	if _, err := server.Certificate().Verify(x509.VerifyOptions{Roots: tlsConfig.RootCAs}); err != nil {
+		t.Errorf("Сертификат сервера не проверяется сертификатами из ca_file: %v", err)
+	}
+
+	t.Setenv(EnvCAFile, filepath.Join(dir, "missing.pem"))
+	if _, err := config.GetTLSConfig(); err == nil {
+		t.Error("Ожидалась ошибка чтения отсутствующего файла сертификатов")
+	}
+}
+
+func TestConfig_AddProfileValidation(t *testing.T) {
+	config := newTestConfig(t)
+
+	tests := []struct {
+		name    string
+		profile domain.Profile
+	}{
+		{name: "Path in name", profile: domain.Profile{Name: "../evil", Server: "127.0.0.1:8080"}},
+		{name: "Empty server", profile: domain.Profile{Name: "work"}},
+		{name: "Unsupported scheme", profile: domain.Profile{Name: "work", Server: "ftp://vault.example.com"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := config.AddProfile(tt.profile); err == nil {
+				t.Error("Ожидалась ошибка добавления профиля")
+			}
+		})
+	}
+
+	if err := config.AddProfile(domain.Profile{Name: "work", Server: "127.0.0.1:8080"}); err != nil {
+		t.Fatalf("Неожиданная ошибка: %v", err)
+	}
+	if err := config.AddProfile(domain.Profile{Name: "work", Server: "127.0.0.1:8081"}); err == nil {
+		t.Error("Ожидалась ошибка добавления существующего профиля")
+	}
+}
+
+func TestConfig_RemoveProfile(t *testing.T) {
+	config := newTestConfig(t)
+	if err := config.AddProfile(domain.Profile{Name: "work", Server: "127.0.0.1:8080"}); err != nil {
+		t.Fatalf("Неожиданная ошибка: %v", err)
+	}
+	if err := config.UseProfile("work"); err != nil {
+		t.Fatalf("Неожиданная ошибка: %v", err)
+	}
+	if err := config.Select(""); err != nil {
+		t.Fatalf("Неожиданная ошибка: %v", err)
+	}
+
+	tokenPath, _ := config.GetTokenPath()
+	if err := os.MkdirAll(filepath.Dir(tokenPath), 0700); err != nil {
+		t.Fatalf("Не удалось создать директорию токенов: %v", err)
+	}
+	if err := os.WriteFile(tokenPath, []byte(`{"token":"work-token"}`), 0600); err != nil {
+		t.Fatalf("Не удалось записать токен: %v", err)
+	}
+
+	if err := config.RemoveProfile("work"); err != nil {
+		t.Fatalf("Неожиданная ошибка: %v", err)
+	}
+	if _, err := os.Stat(tokenPath); !os.IsNotExist(err) {
+		t.Error("Токен удаленного профиля не удален")
+	}
+
+	loaded := NewConfig("127.0.0.1:8080").(*Config)
+	if err := loaded.Select(""); err != nil {
+		t.Fatalf("Неожиданная ошибка: %v", err)
+	}
+	if loaded.GetProfileName() != DefaultProfile {
+		t.Errorf("После удаления текущего профиля ожидался профиль default, получен '%s'", loaded.GetProfileName())
+	}
+	if err := loaded.RemoveProfile("work"); !errors.Is(err, domain.ErrProfileNotFound) {
+		t.Errorf("Ожидалась ошибка отсутствующего профиля, получена %v", err)
+	}
+}
@@ -2,6 +2,7 @@ package config
 
 import (
 	"fmt"
+	"github.com/SmirnovND/gophkeeper/internal/domain"
 	"github.com/SmirnovND/gophkeeper/internal/interfaces"
 	"gopkg.in/yaml.v3"
 	"log"
@@ -12,9 +13,13 @@ import (
 var osExit = os.Exit
 
 type Config struct {
-	Db    `yaml:"db"`
-	App   `yaml:"app"`
-	Minio `yaml:"minio"`
+	Db         `yaml:"db"`
+	App        `yaml:"app"`
+	Minio      `yaml:"minio"`
+	Quota      domain.Quota          `yaml:"quota"`
+	FileTypes  domain.FileTypePolicy `yaml:"file_types"`
+	Encryption Encryption            `yaml:"encryption"`
+	Settings   domain.UserSettings   `yaml:"settings"`
 }
 
 type Db struct {
@@ -26,6 +31,15 @@ type Minio struct {
 	AccessKey  string `yaml:"access_key"`
 	SecretKey  string `yaml:"secret_key"`
 	Host       string `yaml:"host"`
+	// Secure включает HTTPS для соединения с MinIO, без него хранилище отклоняет запросы с SSE-C
+	Secure bool `yaml:"secure"`
+}
+
+// Encryption - настройки шифрования объектов в хранилище ключами пользователей (SSE-C).
+// Мастер-ключи задаются в base64 и должны быть длиной 32 байта
+type Encryption struct {
+	MasterKey          string   `yaml:"master_key"`
+	PreviousMasterKeys []string `yaml:"previous_master_keys"`
 }
 
 type App struct {
@@ -61,6 +75,35 @@ func (c *Config) GetMinioHost() string {
 	return c.Minio.Host
 }
 
+func (c *Config) GetMinioSecure() bool {
+	return c.Minio.Secure
+}
+
+// GetMasterKey возвращает текущий мастер-ключ, пустое значение отключает шифрование объектов
+func (c *Config) GetMasterKey() string {
+	return c.Encryption.MasterKey
+}
+
+// GetPreviousMasterKeys возвращает прежние мастер-ключи, нужные для переупаковки ключей пользователей после смены мастер-ключа
+func (c *Config) GetPreviousMasterKeys() []string {
+	return c.Encryption.PreviousMasterKeys
+}
+
+// GetDefaultQuota возвращает квоту по умолчанию для пользователей без индивидуальных настроек
+func (c *Config) GetDefaultQuota() domain.Quota {
+	return c.Quota
+}
+
+// GetDefaultSettings возвращает настройки аккаунта по умолчанию для пользователей, не менявших их
+func (c *Config) GetDefaultSettings() domain.UserSettings {
+	return c.Settings
+}
+
+// GetFileTypePolicy возвращает политику разрешенных и запрещенных типов загружаемых файлов
+func (c *Config) GetFileTypePolicy() domain.FileTypePolicy {
+	return c.FileTypes
+}
+
 func NewConfig() interfaces.ConfigServer {
 	defer func() {
 		if err := recover(); err != nil {
@@ -83,6 +126,9 @@ func (c *Config) LoadConfig(patch string) {
 		return
 	}
 
+	// Значения по умолчанию, которые не заменяются нулями при отсутствии секции в файле
+	c.Settings.PasswordHistory = domain.DefaultPasswordHistory
+
 	decoder := yaml.NewDecoder(file)
 	err = decoder.Decode(&c)
 	if err != nil {
@@ -2,6 +2,7 @@ package config
 
 import (
 	"bytes"
+	"github.com/SmirnovND/gophkeeper/internal/domain"
 	"log"
 	"os"
 	"strings"
@@ -76,6 +77,76 @@ minio:
 	}
 }
 
+func TestConfig_LoadConfig_FileTypes(t *testing.T) {
+	yamlContent := `
+file_types:
+  allow: ["image/*", "application/pdf"]
+  deny: ["image/svg+xml"]
+`
+	configPath := createTempConfigFile(t, yamlContent)
+	defer os.Remove(configPath)
+
+	config := &Config{}
+	config.LoadConfig(configPath)
+
+	policy := config.GetFileTypePolicy()
+	if !policy.Allows("image/png") || !policy.Allows("application/pdf") {
+		t.Error("Ожидалось, что разрешенные типы пропускаются политикой")
+	}
+	if policy.Allows("image/svg+xml") {
+		t.Error("Ожидалось, что запрещенный тип отклоняется политикой")
+	}
+	if policy.Allows("application/x-msdownload") {
+		t.Error("Ожидалось, что тип вне списка разрешенных отклоняется политикой")
+	}
+}
+
+func TestConfig_LoadConfig_Encryption(t *testing.T) {
+	yamlContent := `
+minio:
+  secure: true
+encryption:
+  master_key: "current"
+  previous_master_keys: ["old1", "old2"]
+`
+	configPath := createTempConfigFile(t, yamlContent)
+	defer os.Remove(configPath)
+
+	config := &Config{}
+	config.LoadConfig(configPath)
+
+	if !config.GetMinioSecure() {
+		t.Error("Ожидалось GetMinioSecure()=true")
+	}
+	if config.GetMasterKey() != "current" {
+		t.Errorf("Ожидалось GetMasterKey()='current', получено '%s'", config.GetMasterKey())
+	}
+	if len(config.GetPreviousMasterKeys()) != 2 || config.GetPreviousMasterKeys()[1] != "old2" {
+		t.Errorf("Ожидалось два прежних мастер-ключа, получено %v", config.GetPreviousMasterKeys())
+	}
+}
+
+func TestConfig_LoadConfig_Settings(t *testing.T) {
+	configPath := createTempConfigFile(t, "app:\n  run_addr: \":8080\"\n")
+	defer os.Remove(configPath)
+
+	config := &Config{}
+	config.LoadConfig(configPath)
+	if config.GetDefaultSettings().PasswordHistory != domain.DefaultPasswordHistory {
+		t.Errorf("Без секции settings ожидалась история из %d паролей, получено %d",
+			domain.DefaultPasswordHistory, config.GetDefaultSettings().PasswordHistory)
+	}
+
+	configPath = createTempConfigFile(t, "settings:\n  password_history: 0\n")
+	defer os.Remove(configPath)
+
+	config = &Config{}
+	config.LoadConfig(configPath)
+	if config.GetDefaultSettings().PasswordHistory != 0 {
+		t.Errorf("Ожидалось отключение истории паролей, получено %d", config.GetDefaultSettings().PasswordHistory)
+	}
+}
+
 func TestConfig_LoadConfig_InvalidFile(t *testing.T) {
 	// Тест на обработку несуществующего файла
 	// Поскольку LoadConfig вызывает log.Fatal при ошибке, мы перехватываем вывод лога
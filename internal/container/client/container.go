@@ -2,6 +2,7 @@ package client
 
 import (
 	"github.com/SmirnovND/gophkeeper/internal/command"
+	config "github.com/SmirnovND/gophkeeper/internal/config/client"
 	"github.com/SmirnovND/gophkeeper/internal/interfaces"
 	"github.com/SmirnovND/gophkeeper/internal/repo"
 	"github.com/SmirnovND/gophkeeper/internal/service"
@@ -15,18 +16,23 @@ type Container struct {
 	container *dig.Container
 }
 
+// NewContainer создает контейнер клиента, serverAddress - адрес сервера профиля default, заданный при сборке
 func NewContainer(serverAddress string) *Container {
 	c := &Container{container: dig.New()}
-	c.provideDependencies()
+	c.provideDependencies(serverAddress)
 	c.provideRepo()
-	c.provideService(serverAddress)
+	c.provideService()
 	c.provideUsecase()
 	c.provideCommand()
 	return c
 }
 
 // provideDependencies - функция, регистрирующая зависимости
-func (c *Container) provideDependencies() {
+func (c *Container) provideDependencies(serverAddress string) {
+	// Регистрируем конфигурацию профилей
+	c.container.Provide(func() interfaces.ConfigClient {
+		return config.NewConfig(serverAddress)
+	})
 }
 
 func (c *Container) provideUsecase() {
@@ -34,15 +40,12 @@ func (c *Container) provideUsecase() {
 }
 
 func (c *Container) provideRepo() {
-	c.container.Provide(repo.NewTokenStorage)
+	c.container.Provide(repo.NewProfileTokenStorage)
 }
 
-func (c *Container) provideService(serverAddress string) {
+func (c *Container) provideService() {
 	c.container.Provide(service.NewTokenService)
-
-	c.container.Provide(func() interfaces.ClientService {
-		return service.NewClientService(serverAddress)
-	})
+	c.container.Provide(service.NewProfileClientService)
 }
 
 func (c *Container) provideCommand() {
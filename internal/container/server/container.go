@@ -4,6 +4,7 @@ import (
 	"fmt"
 	config "github.com/SmirnovND/gophkeeper/internal/config/server"
 	"github.com/SmirnovND/gophkeeper/internal/controllers"
+	"github.com/SmirnovND/gophkeeper/internal/domain"
 	"github.com/SmirnovND/gophkeeper/internal/interfaces"
 	"github.com/SmirnovND/gophkeeper/internal/repo"
 	"github.com/SmirnovND/gophkeeper/internal/service"
@@ -35,6 +36,10 @@ func NewContainer() *Container {
 func (c *Container) provideDependencies() {
 	// Регистрируем конфигурацию
 	c.container.Provide(config.NewConfig)
+	// Регистрируем политику типов загружаемых файлов
+	c.container.Provide(func(configServer interfaces.ConfigServer) domain.FileTypePolicy {
+		return configServer.GetFileTypePolicy()
+	})
 	c.container.Provide(func(configServer interfaces.ConfigServer) *sqlx.DB {
 		return db.NewDB(configServer.GetDBDsn())
 	})
@@ -46,8 +51,9 @@ func (c *Container) provideDependencies() {
 
 	c.container.Provide(func(configServer interfaces.ConfigServer) *minio.Client {
 		client, err := minio.New(configServer.GetMinioHost(), &minio.Options{
-			Creds:  credentials.NewStaticV4(configServer.GetMinioAccessKey(), configServer.GetMinioSecretKey(), ""),
-			Secure: false, // Без HTTPS для локальной установки
+			Creds: credentials.NewStaticV4(configServer.GetMinioAccessKey(), configServer.GetMinioSecretKey(), ""),
+			// Без HTTPS допустимо только для локальной установки, SSE-C требует HTTPS
+			Secure: configServer.GetMinioSecure(),
 		})
 		if err != nil {
 			// Исправление: добавляем сообщение об ошибке в вызов panic
@@ -75,11 +81,16 @@ func (c *Container) provideUsecase() {
 	c.container.Provide(usecase.NewAuthUseCase)
 	c.container.Provide(usecase.NewCloudUseCase)
 	c.container.Provide(usecase.NewDataUseCase)
+	c.container.Provide(usecase.NewUserUseCase)
 }
 
 func (c *Container) provideRepo() {
 	c.container.Provide(repo.NewUserRepo)
 	c.container.Provide(repo.NewUserDataRepo)
+	c.container.Provide(repo.NewQuotaRepo)
+	c.container.Provide(repo.NewUserKeyRepo)
+	c.container.Provide(repo.NewItemTemplateRepo)
+	c.container.Provide(repo.NewSettingsRepo)
 }
 
 func (c *Container) provideService() {
@@ -88,6 +99,36 @@ func (c *Container) provideService() {
 	c.container.Provide(service.NewDataService)
 	c.container.Provide(service.NewJwtService)
 
+	c.container.Provide(func(
+		quotaRepo interfaces.QuotaRepo,
+		dataRepo interfaces.UserDataRepo,
+		userRepo interfaces.UserRepo,
+		configServer interfaces.ConfigServer,
+	) interfaces.QuotaService {
+		return service.NewQuotaService(quotaRepo, dataRepo, userRepo, configServer.GetDefaultQuota())
+	})
+
+	c.container.Provide(func(
+		settingsRepo interfaces.SettingsRepo,
+		userRepo interfaces.UserRepo,
+		configServer interfaces.ConfigServer,
+	) interfaces.SettingsService {
+		return service.NewSettingsService(settingsRepo, userRepo, configServer.GetDefaultSettings())
+	})
+
+	c.container.Provide(func(
+		keyRepo interfaces.UserKeyRepo,
+		userRepo interfaces.UserRepo,
+		configServer interfaces.ConfigServer,
+	) interfaces.KeyService {
+		keyService, err := service.NewKeyService(keyRepo, userRepo, configServer.GetMasterKey(), configServer.GetPreviousMasterKeys())
+		if err != nil {
+			panic(fmt.Sprintf("Ошибка загрузки мастер-ключа: %v", err))
+		}
+
+		return keyService
+	})
+
 	c.container.Provide(func(minio *minio.Client, configServer interfaces.ConfigServer) interfaces.CloudService {
 		return service.NewCloud(minio, configServer.GetMinioBucketName())
 	})
@@ -98,6 +139,7 @@ func (c *Container) provideController() {
 	c.container.Provide(controllers.NewAuthController)
 	c.container.Provide(controllers.NewFileController)
 	c.container.Provide(controllers.NewDataController)
+	c.container.Provide(controllers.NewUserController)
 }
 
 // Invoke - функция для вызова и инжекта зависимостей
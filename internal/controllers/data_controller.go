@@ -4,8 +4,11 @@ import (
 	"encoding/json"
 	"github.com/SmirnovND/gophkeeper/internal/domain"
 	"github.com/SmirnovND/gophkeeper/internal/interfaces"
+	"github.com/SmirnovND/gophkeeper/internal/itemtype"
 	"github.com/go-chi/chi/v5"
+	"io"
 	"net/http"
+	"strconv"
 )
 
 // DataController контроллер для работы с данными пользователя
@@ -20,278 +23,316 @@ func NewDataController(dataUseCase interfaces.DataUseCase) *DataController {
 	}
 }
 
-// SaveCredential сохраняет учетные данные (логин/пароль)
-// @Summary Сохранить учетные данные
-// @Description Сохраняет пару логин/пароль пользователя
-// @Tags data
+// SaveItem сохраняет запись любого зарегистрированного типа
+// @Summary Сохранить запись
+// @Description Сохраняет запись типа из реестра: credential, card, text, ssh_key, identity, bank_account, api_token, wifi или license,
+// @Description или пользовательского типа из шаблона. Поля записи передаются в data и проверяются по описанию типа
+// @Tags items
 // @Accept json
 // @Produce json
 // @Param Authorization header string true "Bearer токен"
+// @Param type path string true "Тип записи"
 // @Param label path string true "Метка для идентификации данных"
-// @Param credential body object true "Учетные данные с метаинформацией"
+// @Param item body object true "Поля записи в data с метаинформацией"
 // @Success 200 {object} map[string]string
 // @Failure 400 {object} map[string]string
 // @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 413 {object} map[string]string
 // @Failure 500 {object} map[string]string
-// @Router /api/data/credential/{label} [post]
-func (c *DataController) SaveCredential(w http.ResponseWriter, r *http.Request) {
-	// Получаем метку из URL
-	label := chi.URLParam(r, "label")
-	if label == "" {
-		http.Error(w, "метка не предоставлена", http.StatusBadRequest)
-		return
-	}
-
-	// Получаем данные из тела запроса
-	var requestData struct {
-		CredentialData *domain.CredentialData `json:"credential_data"`
-		Metadata       string                 `json:"metadata"`
-	}
-
-	decoder := json.NewDecoder(r.Body)
-	if err := decoder.Decode(&requestData); err != nil {
-		http.Error(w, "ошибка при декодировании JSON: "+err.Error(), http.StatusBadRequest)
-		return
-	}
-
-	if requestData.CredentialData == nil {
-		http.Error(w, "учетные данные не предоставлены", http.StatusBadRequest)
-		return
-	}
-
-	c.dataUseCase.SaveCredential(w, r, label, requestData.CredentialData, requestData.Metadata)
+// @Router /api/v2/items/{type}/{label} [post]
+func (c *DataController) SaveItem(w http.ResponseWriter, r *http.Request) {
+	c.saveItem(w, r, "data", nil)
 }
 
-// GetCredential получает учетные данные (логин/пароль)
-// @Summary Получить учетные данные
-// @Description Получает пару логин/пароль пользователя по метке
-// @Tags data
+// GetItem получает запись любого зарегистрированного типа
+// @Summary Получить запись
+// @Description Получает поля записи в data по типу и метке. С redact=true значения секретных полей заменяются маской,
+// @Description у номера карты остаются видны последние четыре цифры
+// @Tags items
 // @Accept json
 // @Produce json
 // @Param Authorization header string true "Bearer токен"
+// @Param type path string true "Тип записи"
 // @Param label path string true "Метка для идентификации данных"
-// @Success 200 {object} object "Учетные данные с метаинформацией"
+// @Param redact query bool false "Скрыть значения секретных полей"
+// @Success 200 {object} object "Поля записи в data с метаинформацией"
 // @Failure 400 {object} map[string]string
 // @Failure 401 {object} map[string]string
 // @Failure 404 {object} map[string]string
 // @Failure 500 {object} map[string]string
-// @Router /api/data/credential/{label} [get]
-func (c *DataController) GetCredential(w http.ResponseWriter, r *http.Request) {
-	// Получаем метку из URL
-	label := chi.URLParam(r, "label")
+// @Router /api/v2/items/{type}/{label} [get]
+func (c *DataController) GetItem(w http.ResponseWriter, r *http.Request) {
+	// Получаем тип и метку из URL
+	itemType, label := chi.URLParam(r, "type"), chi.URLParam(r, "label")
 	if label == "" {
 		http.Error(w, "метка не предоставлена", http.StatusBadRequest)
 		return
 	}
 
-	c.dataUseCase.GetCredential(w, r, label)
+	view := domain.ItemView{DataKey: "data"}
+	if value := r.URL.Query().Get("redact"); value != "" {
+		redact, err := strconv.ParseBool(value)
+		if err != nil {
+			http.Error(w, "неверное значение redact: "+value, http.StatusBadRequest)
+			return
+		}
+		view.Redact = redact
+	}
+
+	c.dataUseCase.GetItem(w, r, itemType, label, view)
 }
 
-// SaveCard сохраняет данные кредитной карты
-// @Summary Сохранить данные кредитной карты
-// @Description Сохраняет данные кредитной карты пользователя
-// @Tags data
+// DeleteItem удаляет запись любого зарегистрированного типа
+// @Summary Удалить запись
+// @Description Удаляет запись по типу и метке
+// @Tags items
 // @Accept json
 // @Produce json
 // @Param Authorization header string true "Bearer токен"
+// @Param type path string true "Тип записи"
 // @Param label path string true "Метка для идентификации данных"
-// @Param card body object true "Данные кредитной карты с метаинформацией"
 // @Success 200 {object} map[string]string
 // @Failure 400 {object} map[string]string
 // @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
 // @Failure 500 {object} map[string]string
-// @Router /api/data/card/{label} [post]
-func (c *DataController) SaveCard(w http.ResponseWriter, r *http.Request) {
-	// Получаем метку из URL
-	label := chi.URLParam(r, "label")
+// @Router /api/v2/items/{type}/{label} [delete]
+// @Router /api/data/{type}/{label} [delete]
+func (c *DataController) DeleteItem(w http.ResponseWriter, r *http.Request) {
+	// Получаем тип и метку из URL
+	itemType, label := chi.URLParam(r, "type"), chi.URLParam(r, "label")
 	if label == "" {
 		http.Error(w, "метка не предоставлена", http.StatusBadRequest)
 		return
 	}
 
-	// Получаем данные из тела запроса
-	var requestData struct {
-		CardData *domain.CardData `json:"card_data"`
-		Metadata string           `json:"metadata"`
-	}
-
-	decoder := json.NewDecoder(r.Body)
-	if err := decoder.Decode(&requestData); err != nil {
-		http.Error(w, "ошибка при декодировании JSON: "+err.Error(), http.StatusBadRequest)
-		return
-	}
-
-	if requestData.CardData == nil {
-		http.Error(w, "данные карты не предоставлены", http.StatusBadRequest)
-		return
-	}
-
-	c.dataUseCase.SaveCard(w, r, label, requestData.CardData, requestData.Metadata)
+	c.dataUseCase.DeleteItem(w, r, itemType, label)
 }
 
-// GetCard получает данные кредитной карты
-// @Summary Получить данные кредитной карты
-// @Description Получает данные кредитной карты пользователя по метке
+// SaveLegacyItem сохраняет запись через API v1. Поля записи передаются под ключом типа:
+// credential_data, card_data, text_data, ssh_key_data или item_data у остальных типов
+// @Summary Сохранить запись (API v1)
+// @Description Устаревший маршрут, используйте /api/v2/items/{type}/{label}
+// @Description Поля-списки, например uris и fields учетных данных, передаются списками JSON
 // @Tags data
 // @Accept json
 // @Produce json
 // @Param Authorization header string true "Bearer токен"
+// @Param type path string true "Тип записи"
 // @Param label path string true "Метка для идентификации данных"
-// @Success 200 {object} object "Данные кредитной карты с метаинформацией"
+// @Param item body object true "Поля записи под ключом типа с метаинформацией"
+// @Success 200 {object} map[string]string
 // @Failure 400 {object} map[string]string
 // @Failure 401 {object} map[string]string
 // @Failure 404 {object} map[string]string
+// @Failure 413 {object} map[string]string
 // @Failure 500 {object} map[string]string
-// @Router /api/data/card/{label} [get]
-func (c *DataController) GetCard(w http.ResponseWriter, r *http.Request) {
-	// Получаем метку из URL
-	label := chi.URLParam(r, "label")
-	if label == "" {
-		http.Error(w, "метка не предоставлена", http.StatusBadRequest)
+// @Router /api/data/{type}/{label} [post]
+func (c *DataController) SaveLegacyItem(w http.ResponseWriter, r *http.Request) {
+	t, err := itemtype.Lookup(chi.URLParam(r, "type"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
 		return
 	}
 
-	c.dataUseCase.GetCard(w, r, label)
+	c.saveItem(w, r, t.LegacyKey(), t)
 }
 
-// SaveText сохраняет текстовые данные
-// @Summary Сохранить текстовые данные
-// @Description Сохраняет произвольный текст пользователя
+// GetLegacyItem получает запись через API v1. Поля записи возвращаются под ключом типа
+// @Summary Получить запись (API v1)
+// @Description Устаревший маршрут, используйте /api/v2/items/{type}/{label}
+// @Description Поля-списки, например uris и fields учетных данных, передаются списками JSON
 // @Tags data
 // @Accept json
 // @Produce json
 // @Param Authorization header string true "Bearer токен"
+// @Param type path string true "Тип записи"
 // @Param label path string true "Метка для идентификации данных"
-// @Param text body object true "Текстовые данные с метаинформацией"
-// @Success 200 {object} map[string]string
+// @Success 200 {object} object "Поля записи под ключом типа с метаинформацией"
 // @Failure 400 {object} map[string]string
 // @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
 // @Failure 500 {object} map[string]string
-// @Router /api/data/text/{label} [post]
-func (c *DataController) SaveText(w http.ResponseWriter, r *http.Request) {
-	// Получаем метку из URL
-	label := chi.URLParam(r, "label")
+// @Router /api/data/{type}/{label} [get]
+func (c *DataController) GetLegacyItem(w http.ResponseWriter, r *http.Request) {
+	// Получаем тип и метку из URL
+	itemType, label := chi.URLParam(r, "type"), chi.URLParam(r, "label")
 	if label == "" {
 		http.Error(w, "метка не предоставлена", http.StatusBadRequest)
 		return
 	}
 
-	// Получаем данные из тела запроса
-	var requestData struct {
-		TextData *domain.TextData `json:"text_data"`
-		Metadata string           `json:"metadata"`
+	t, err := itemtype.Lookup(itemType)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
 	}
 
+	c.dataUseCase.GetItem(w, r, itemType, label, domain.ItemView{DataKey: t.LegacyKey(), Lists: true})
+}
+
+// saveItem читает поля записи из тела запроса под ключом dataKey и сохраняет запись. Для API v1 передается
+// legacy - тип записи, поля-списки которого принимаются списками JSON
+func (c *DataController) saveItem(w http.ResponseWriter, r *http.Request, dataKey string, legacy *itemtype.Type) {
+	// Получаем тип и метку из URL
+	itemType, label := chi.URLParam(r, "type"), chi.URLParam(r, "label")
+	if label == "" {
+		http.Error(w, "метка не предоставлена", http.StatusBadRequest)
+		return
+	}
+
+	// Получаем данные из тела запроса
+	var requestData map[string]json.RawMessage
+
 	decoder := json.NewDecoder(r.Body)
 	if err := decoder.Decode(&requestData); err != nil {
 		http.Error(w, "ошибка при декодировании JSON: "+err.Error(), http.StatusBadRequest)
 		return
 	}
 
-	if requestData.TextData == nil {
-		http.Error(w, "текстовые данные не предоставлены", http.StatusBadRequest)
+	var data domain.ItemData
+	if raw, ok := requestData[dataKey]; ok && legacy != nil {
+		var values map[string]json.RawMessage
+		if err := json.Unmarshal(raw, &values); err != nil {
+			http.Error(w, "ошибка при декодировании JSON: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		if values != nil {
+			parsed, err := legacy.ParseValues(values)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			data = parsed
+		}
+	} else if ok {
+		if err := json.Unmarshal(raw, &data); err != nil {
+			http.Error(w, "ошибка при декодировании JSON: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	if data == nil {
+		http.Error(w, "поля записи не предоставлены в "+dataKey, http.StatusBadRequest)
 		return
 	}
 
-	c.dataUseCase.SaveText(w, r, label, requestData.TextData, requestData.Metadata)
+	var metadata string
+	if raw, ok := requestData["metadata"]; ok {
+		if err := json.Unmarshal(raw, &metadata); err != nil {
+			http.Error(w, "ошибка при декодировании JSON: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	c.dataUseCase.SaveItem(w, r, itemType, label, data, metadata)
 }
 
-// GetText получает текстовые данные
-// @Summary Получить текстовые данные
-// @Description Получает произвольный текст пользователя по метке
-// @Tags data
-// @Accept json
+// ListItems возвращает список всех записей пользователя
+// @Summary Получить список записей
+// @Description Возвращает тип, метку, метаданные и даты изменения всех записей пользователя без их содержимого
+// @Tags items
 // @Produce json
 // @Param Authorization header string true "Bearer токен"
-// @Param label path string true "Метка для идентификации данных"
-// @Success 200 {object} object "Текстовые данные с метаинформацией"
-// @Failure 400 {object} map[string]string
+// @Success 200 {array} domain.ItemInfo
 // @Failure 401 {object} map[string]string
-// @Failure 404 {object} map[string]string
 // @Failure 500 {object} map[string]string
-// @Router /api/data/text/{label} [get]
-func (c *DataController) GetText(w http.ResponseWriter, r *http.Request) {
-	// Получаем метку из URL
-	label := chi.URLParam(r, "label")
-	if label == "" {
-		http.Error(w, "метка не предоставлена", http.StatusBadRequest)
-		return
-	}
-
-	c.dataUseCase.GetText(w, r, label)
+// @Router /api/v2/items [get]
+// @Router /api/data [get]
+func (c *DataController) ListItems(w http.ResponseWriter, r *http.Request) {
+	c.dataUseCase.ListItems(w, r)
 }
 
-// DeleteCredential удаляет учетные данные (логин/пароль)
-// @Summary Удалить учетные данные
-// @Description Удаляет пару логин/пароль пользователя по метке
-// @Tags data
+// SaveItemTemplate сохраняет шаблон пользовательского типа записей
+// @Summary Сохранить шаблон типа записей
+// @Description Сохраняет пользовательский тип, описанный подмножеством JSON Schema: объект со строковыми полями в properties.
+// @Description Вид поля задают enum, writeOnly (секрет), format uri или date, остальные поля - текст. Шаблон с тем же именем заменяется
+// @Tags templates
 // @Accept json
 // @Produce json
 // @Param Authorization header string true "Bearer токен"
-// @Param label path string true "Метка для идентификации данных"
-// @Success 200 {object} map[string]string
+// @Param name path string true "Имя типа"
+// @Param schema body object true "Схема шаблона"
+// @Success 200 {object} domain.ItemTemplate
 // @Failure 400 {object} map[string]string
 // @Failure 401 {object} map[string]string
-// @Failure 404 {object} map[string]string
 // @Failure 500 {object} map[string]string
-// @Router /api/data/credential/{label} [delete]
-func (c *DataController) DeleteCredential(w http.ResponseWriter, r *http.Request) {
-	// Получаем метку из URL
-	label := chi.URLParam(r, "label")
-	if label == "" {
-		http.Error(w, "метка не предоставлена", http.StatusBadRequest)
+// @Router /api/v2/templates/{name} [post]
+func (c *DataController) SaveItemTemplate(w http.ResponseWriter, r *http.Request) {
+	name := chi.URLParam(r, "name")
+	if name == "" {
+		http.Error(w, "имя шаблона не предоставлено", http.StatusBadRequest)
 		return
 	}
 
-	c.dataUseCase.DeleteCredential(w, r, label)
+	// Схема передается телом запроса целиком
+	schema, err := io.ReadAll(http.MaxBytesReader(w, r.Body, itemtype.MaxSchemaSize))
+	if err != nil {
+		http.Error(w, "ошибка при чтении схемы: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if !json.Valid(schema) {
+		http.Error(w, "схема шаблона не является JSON", http.StatusBadRequest)
+		return
+	}
+
+	c.dataUseCase.SaveItemTemplate(w, r, name, schema)
 }
 
-// DeleteCard удаляет данные кредитной карты
-// @Summary Удалить данные кредитной карты
-// @Description Удаляет данные кредитной карты пользователя по метке
-// @Tags data
-// @Accept json
+// GetItemTemplate получает шаблон пользовательского типа записей
+// @Summary Получить шаблон типа записей
+// @Description Возвращает имя, схему и даты изменения шаблона
+// @Tags templates
 // @Produce json
 // @Param Authorization header string true "Bearer токен"
-// @Param label path string true "Метка для идентификации данных"
-// @Success 200 {object} map[string]string
-// @Failure 400 {object} map[string]string
+// @Param name path string true "Имя типа"
+// @Success 200 {object} domain.ItemTemplate
 // @Failure 401 {object} map[string]string
 // @Failure 404 {object} map[string]string
 // @Failure 500 {object} map[string]string
-// @Router /api/data/card/{label} [delete]
-func (c *DataController) DeleteCard(w http.ResponseWriter, r *http.Request) {
-	// Получаем метку из URL
-	label := chi.URLParam(r, "label")
-	if label == "" {
-		http.Error(w, "метка не предоставлена", http.StatusBadRequest)
+// @Router /api/v2/templates/{name} [get]
+func (c *DataController) GetItemTemplate(w http.ResponseWriter, r *http.Request) {
+	name := chi.URLParam(r, "name")
+	if name == "" {
+		http.Error(w, "имя шаблона не предоставлено", http.StatusBadRequest)
 		return
 	}
 
-	c.dataUseCase.DeleteCard(w, r, label)
+	c.dataUseCase.GetItemTemplate(w, r, name)
 }
 
-// DeleteText удаляет текстовые данные
-// @Summary Удалить текстовые данные
-// @Description Удаляет произвольный текст пользователя по метке
-// @Tags data
-// @Accept json
+// ListItemTemplates возвращает все шаблоны пользовательских типов записей
+// @Summary Получить список шаблонов типов записей
+// @Tags templates
 // @Produce json
 // @Param Authorization header string true "Bearer токен"
-// @Param label path string true "Метка для идентификации данных"
+// @Success 200 {array} domain.ItemTemplate
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v2/templates [get]
+func (c *DataController) ListItemTemplates(w http.ResponseWriter, r *http.Request) {
+	c.dataUseCase.ListItemTemplates(w, r)
+}
+
+// DeleteItemTemplate удаляет шаблон пользовательского типа записей
+// @Summary Удалить шаблон типа записей
+// @Description Шаблон, по которому сохранены записи, не удаляется
+// @Tags templates
+// @Produce json
+// @Param Authorization header string true "Bearer токен"
+// @Param name path string true "Имя типа"
 // @Success 200 {object} map[string]string
-// @Failure 400 {object} map[string]string
 // @Failure 401 {object} map[string]string
 // @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
 // @Failure 500 {object} map[string]string
-// @Router /api/data/text/{label} [delete]
-func (c *DataController) DeleteText(w http.ResponseWriter, r *http.Request) {
-	// Получаем метку из URL
-	label := chi.URLParam(r, "label")
-	if label == "" {
-		http.Error(w, "метка не предоставлена", http.StatusBadRequest)
+// @Router /api/v2/templates/{name} [delete]
+func (c *DataController) DeleteItemTemplate(w http.ResponseWriter, r *http.Request) {
+	name := chi.URLParam(r, "name")
+	if name == "" {
+		http.Error(w, "имя шаблона не предоставлено", http.StatusBadRequest)
 		return
 	}
 
-	c.dataUseCase.DeleteText(w, r, label)
+	c.dataUseCase.DeleteItemTemplate(w, r, name)
 }
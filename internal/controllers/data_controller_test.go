@@ -5,6 +5,7 @@ import (
 	"context"
 	"encoding/json"
 	"github.com/SmirnovND/gophkeeper/internal/domain"
+	"github.com/SmirnovND/gophkeeper/internal/itemtype"
 	"github.com/go-chi/chi/v5"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -18,40 +19,36 @@ type MockDataUseCase struct {
 	mock.Mock
 }
 
-func (m *MockDataUseCase) SaveCredential(w http.ResponseWriter, r *http.Request, label string, credentialData *domain.CredentialData, metadata string) {
-	m.Called(w, r, label, credentialData, metadata)
+func (m *MockDataUseCase) SaveItem(w http.ResponseWriter, r *http.Request, itemType string, label string, data domain.ItemData, metadata string) {
+	m.Called(w, r, itemType, label, data, metadata)
 }
 
-func (m *MockDataUseCase) GetCredential(w http.ResponseWriter, r *http.Request, label string) {
-	m.Called(w, r, label)
+func (m *MockDataUseCase) GetItem(w http.ResponseWriter, r *http.Request, itemType string, label string, view domain.ItemView) {
+	m.Called(w, r, itemType, label, view)
 }
 
-func (m *MockDataUseCase) DeleteCredential(w http.ResponseWriter, r *http.Request, label string) {
-	m.Called(w, r, label)
+func (m *MockDataUseCase) DeleteItem(w http.ResponseWriter, r *http.Request, itemType string, label string) {
+	m.Called(w, r, itemType, label)
 }
 
-func (m *MockDataUseCase) SaveCard(w http.ResponseWriter, r *http.Request, label string, cardData *domain.CardData, metadata string) {
-	m.Called(w, r, label, cardData, metadata)
+func (m *MockDataUseCase) ListItems(w http.ResponseWriter, r *http.Request) {
+	m.Called(w, r)
 }
 
-func (m *MockDataUseCase) GetCard(w http.ResponseWriter, r *http.Request, label string) {
-	m.Called(w, r, label)
+func (m *MockDataUseCase) SaveItemTemplate(w http.ResponseWriter, r *http.Request, name string, schema json.RawMessage) {
+	m.Called(w, r, name, schema)
 }
 
-func (m *MockDataUseCase) DeleteCard(w http.ResponseWriter, r *http.Request, label string) {
-	m.Called(w, r, label)
+func (m *MockDataUseCase) GetItemTemplate(w http.ResponseWriter, r *http.Request, name string) {
+	m.Called(w, r, name)
 }
 
-func (m *MockDataUseCase) SaveText(w http.ResponseWriter, r *http.Request, label string, textData *domain.TextData, metadata string) {
-	m.Called(w, r, label, textData, metadata)
+func (m *MockDataUseCase) ListItemTemplates(w http.ResponseWriter, r *http.Request) {
+	m.Called(w, r)
 }
 
-func (m *MockDataUseCase) GetText(w http.ResponseWriter, r *http.Request, label string) {
-	m.Called(w, r, label)
-}
-
-func (m *MockDataUseCase) DeleteText(w http.ResponseWriter, r *http.Request, label string) {
-	m.Called(w, r, label)
+func (m *MockDataUseCase) DeleteItemTemplate(w http.ResponseWriter, r *http.Request, name string) {
+	m.Called(w, r, name)
 }
 
 // Вспомогательная функция для создания запроса с параметрами URL
@@ -70,649 +67,328 @@ func createRequestWithURLParam(method, path, paramName, paramValue string, body
 	return req, rr
 }
 
-// Тесты для методов работы с учетными данными
-
-func TestDataController_SaveCredential(t *testing.T) {
-	// Arrange
-	mockDataUseCase := new(MockDataUseCase)
-	controller := NewDataController(mockDataUseCase)
-
-	// Создаем тестовые данные
-	label := "test-label"
-	credentialData := domain.CredentialData{
-		Login:    "testuser",
-		Password: "password123",
-	}
-	metadata := "test metadata"
-
-	// Создаем JSON из данных
-	requestData := struct {
-		CredentialData *domain.CredentialData `json:"credential_data"`
-		Metadata       string                 `json:"metadata"`
-	}{
-		CredentialData: &credentialData,
-		Metadata:       metadata,
-	}
-	jsonData, _ := json.Marshal(requestData)
-
-	// Создаем запрос с параметрами URL
-	req, rr := createRequestWithURLParam("POST", "/api/data/credential/"+label, "label", label, jsonData)
-
-	// Настраиваем поведение мока
-	mockDataUseCase.On("SaveCredential", mock.Anything, mock.Anything, label, mock.MatchedBy(func(c *domain.CredentialData) bool {
-		return c.Login == credentialData.Login && c.Password == credentialData.Password
-	}), metadata)
-
-	// Act
-	controller.SaveCredential(rr, req)
-
-	// Assert
-	mockDataUseCase.AssertExpectations(t)
+// createItemRequest создает запрос к записи с параметрами URL type и label
+func createItemRequest(method, path, itemType, label string, body []byte) (*http.Request, *httptest.ResponseRecorder) {
+	req, rr := createRequestWithURLParam(method, path, "label", label, body)
+	chi.RouteContext(req.Context()).URLParams.Add("type", itemType)
+	return req, rr
 }
 
-func TestDataController_GetCredential(t *testing.T) {
+func TestNewDataController(t *testing.T) {
 	// Arrange
 	mockDataUseCase := new(MockDataUseCase)
-	controller := NewDataController(mockDataUseCase)
-
-	// Создаем тестовые данные
-	label := "test-label"
-
-	// Создаем запрос с параметрами URL
-	req, rr := createRequestWithURLParam("GET", "/api/data/credential/"+label, "label", label, nil)
-
-	// Настраиваем поведение мока
-	mockDataUseCase.On("GetCredential", mock.Anything, mock.Anything, label)
 
 	// Act
-	controller.GetCredential(rr, req)
-
-	// Assert
-	mockDataUseCase.AssertExpectations(t)
-}
-
-func TestDataController_DeleteCredential(t *testing.T) {
-	// Arrange
-	mockDataUseCase := new(MockDataUseCase)
 	controller := NewDataController(mockDataUseCase)
 
-	// Создаем тестовые данные
-	label := "test-label"
-
-	// Создаем запрос с параметрами URL
-	req, rr := createRequestWithURLParam("DELETE", "/api/data/credential/"+label, "label", label, nil)
-
-	// Настраиваем поведение мока
-	mockDataUseCase.On("DeleteCredential", mock.Anything, mock.Anything, label)
-
-	// Act
-	controller.DeleteCredential(rr, req)
-
-	// Assert
-	mockDataUseCase.AssertExpectations(t)
-}
-
-// Тесты для методов работы с данными кредитных карт
-
-func TestDataController_SaveCard(t *testing.T) {
-	// Arrange
-	mockDataUseCase := new(MockDataUseCase)
-	controller := NewDataController(mockDataUseCase)
-
-	// Создаем тестовые данные
-	label := "test-label"
-	cardData := domain.CardData{
-		Number:     "1234567890123456",
-		Holder:     "Test User",
-		ExpiryDate: "12/25",
-		CVV:        "123",
-	}
-	metadata := "card metadata"
-
-	// Создаем JSON из данных
-	requestData := struct {
-		CardData *domain.CardData `json:"card_data"`
-		Metadata string           `json:"metadata"`
-	}{
-		CardData: &cardData,
-		Metadata: metadata,
-	}
-	jsonData, _ := json.Marshal(requestData)
-
-	// Создаем запрос с параметрами URL
-	req, rr := createRequestWithURLParam("POST", "/api/data/card/"+label, "label", label, jsonData)
-
-	// Настраиваем поведение мока
-	mockDataUseCase.On("SaveCard", mock.Anything, mock.Anything, label, mock.MatchedBy(func(c *domain.CardData) bool {
-		return c.Number == cardData.Number && c.Holder == cardData.Holder &&
-			c.ExpiryDate == cardData.ExpiryDate && c.CVV == cardData.CVV
-	}), metadata)
-
-	// Act
-	controller.SaveCard(rr, req)
-
 	// Assert
-	mockDataUseCase.AssertExpectations(t)
+	assert.NotNil(t, controller)
+	assert.Equal(t, mockDataUseCase, controller.dataUseCase)
 }
 
-func TestDataController_GetCard(t *testing.T) {
-	// Arrange
-	mockDataUseCase := new(MockDataUseCase)
-	controller := NewDataController(mockDataUseCase)
-
-	// Создаем тестовые данные
-	label := "test-label"
-
-	// Создаем запрос с параметрами URL
-	req, rr := createRequestWithURLParam("GET", "/api/data/card/"+label, "label", label, nil)
-
-	// Настраиваем поведение мока
-	mockDataUseCase.On("GetCard", mock.Anything, mock.Anything, label)
-
-	// Act
-	controller.GetCard(rr, req)
-
-	// Assert
-	mockDataUseCase.AssertExpectations(t)
-}
+// Тесты для маршрутов API v2
 
-func TestDataController_DeleteCard(t *testing.T) {
+func TestDataController_SaveItem(t *testing.T) {
 	// Arrange
 	mockDataUseCase := new(MockDataUseCase)
 	controller := NewDataController(mockDataUseCase)
 
 	// Создаем тестовые данные
-	label := "test-label"
+	itemData := domain.ItemData{"ssid": "home", "security": "WPA2", "password": "hunter22"}
+	jsonData, _ := json.Marshal(map[string]interface{}{"data": itemData, "metadata": "роутер"})
 
 	// Создаем запрос с параметрами URL
-	req, rr := createRequestWithURLParam("DELETE", "/api/data/card/"+label, "label", label, nil)
+	req, rr := createItemRequest("POST", "/api/v2/items/wifi/home", domain.UserDataTypeWiFi, "home", jsonData)
 
 	// Настраиваем поведение мока
-	mockDataUseCase.On("DeleteCard", mock.Anything, mock.Anything, label)
+	mockDataUseCase.On("SaveItem", mock.Anything, mock.Anything, domain.UserDataTypeWiFi, "home", itemData, "роутер")
 
 	// Act
-	controller.DeleteCard(rr, req)
+	controller.SaveItem(rr, req)
 
 	// Assert
 	mockDataUseCase.AssertExpectations(t)
 }
 
-// Тесты для методов работы с текстовыми данными
-
-func TestDataController_SaveText(t *testing.T) {
-	// Arrange
-	mockDataUseCase := new(MockDataUseCase)
-	controller := NewDataController(mockDataUseCase)
-
-	// Создаем тестовые данные
-	label := "test-label"
-	textData := domain.TextData{
-		Content: "This is a test text content",
-	}
-	metadata := "text metadata"
-
-	// Создаем JSON из данных
-	requestData := struct {
-		TextData *domain.TextData `json:"text_data"`
-		Metadata string           `json:"metadata"`
+func TestDataController_SaveItem_BadRequest(t *testing.T) {
+	tests := []struct {
+		name  string
+		label string
+		body  string
 	}{
-		TextData: &textData,
-		Metadata: metadata,
+		{"нет метки", "", `{"data":{"ssid":"home"}}`},
+		{"неверный JSON", "home", `{"data": {"ssid":}}`},
+		{"нет полей записи", "home", `{"metadata":"роутер"}`},
+		{"поля под ключом API v1", "home", `{"item_data":{"ssid":"home"}}`},
+		{"неверная метаинформация", "home", `{"data":{"ssid":"home"},"metadata":1}`},
 	}
-	jsonData, _ := json.Marshal(requestData)
-
-	// Создаем запрос с параметрами URL
-	req, rr := createRequestWithURLParam("POST", "/api/data/text/"+label, "label", label, jsonData)
-
-	// Настраиваем поведение мока
-	mockDataUseCase.On("SaveText", mock.Anything, mock.Anything, label, mock.MatchedBy(func(t *domain.TextData) bool {
-		return t.Content == textData.Content
-	}), metadata)
-
-	// Act
-	controller.SaveText(rr, req)
-
-	// Assert
-	mockDataUseCase.AssertExpectations(t)
-}
-
-func TestDataController_GetText(t *testing.T) {
-	// Arrange
-	mockDataUseCase := new(MockDataUseCase)
-	controller := NewDataController(mockDataUseCase)
-
-	// Создаем тестовые данные
-	label := "test-label"
-
-	// Создаем запрос с параметрами URL
-	req, rr := createRequestWithURLParam("GET", "/api/data/text/"+label, "label", label, nil)
-
-	// Настраиваем поведение мока
-	mockDataUseCase.On("GetText", mock.Anything, mock.Anything, label)
-
-	// Act
-	controller.GetText(rr, req)
-
-	// Assert
-	mockDataUseCase.AssertExpectations(t)
-}
-
-func TestDataController_DeleteText(t *testing.T) {
-	// Arrange
-	mockDataUseCase := new(MockDataUseCase)
-	controller := NewDataController(mockDataUseCase)
-
-	// Создаем тестовые данные
-	label := "test-label"
-
-	// Создаем запрос с параметрами URL
-	req, rr := createRequestWithURLParam("DELETE", "/api/data/text/"+label, "label", label, nil)
-
-	// Настраиваем поведение мока
-	mockDataUseCase.On("DeleteText", mock.Anything, mock.Anything, label)
-
-	// Act
-	controller.DeleteText(rr, req)
-
-	// Assert
-	mockDataUseCase.AssertExpectations(t)
-}
-
-// Тесты для проверки обработки ошибок
-
-func TestDataController_SaveCredential_MissingLabel(t *testing.T) {
-	// Arrange
-	mockDataUseCase := new(MockDataUseCase)
-	controller := NewDataController(mockDataUseCase)
-
-	// Создаем тестовые данные
-	credentialData := domain.CredentialData{
-		Login:    "testuser",
-		Password: "password123",
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			mockDataUseCase := new(MockDataUseCase)
+			controller := NewDataController(mockDataUseCase)
+			req, rr := createItemRequest("POST", "/api/v2/items/wifi/"+tt.label, domain.UserDataTypeWiFi, tt.label, []byte(tt.body))
+
+			// Act
+			controller.SaveItem(rr, req)
+
+			// Assert
+			assert.Equal(t, http.StatusBadRequest, rr.Code)
+			mockDataUseCase.AssertNotCalled(t, "SaveItem")
+		})
 	}
-	metadata := "test metadata"
+}
 
-	// Создаем JSON из данных
-	requestData := struct {
-		CredentialData *domain.CredentialData `json:"credential_data"`
-		Metadata       string                 `json:"metadata"`
+func TestDataController_GetItem(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		view  domain.ItemView
 	}{
-		CredentialData: &credentialData,
-		Metadata:       metadata,
+		{"без скрытия", "", domain.ItemView{DataKey: "data"}},
+		{"со скрытием", "?redact=true", domain.ItemView{DataKey: "data", Redact: true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			mockDataUseCase := new(MockDataUseCase)
+			controller := NewDataController(mockDataUseCase)
+			req, rr := createItemRequest("GET", "/api/v2/items/card/visa"+tt.query, domain.UserDataTypeCard, "visa", nil)
+
+			// Настраиваем поведение мока
+			mockDataUseCase.On("GetItem", mock.Anything, mock.Anything, domain.UserDataTypeCard, "visa", tt.view)
+
+			// Act
+			controller.GetItem(rr, req)
+
+			// Assert
+			mockDataUseCase.AssertExpectations(t)
+		})
 	}
-	jsonData, _ := json.Marshal(requestData)
-
-	// Создаем запрос без параметра label
-	req, _ := http.NewRequest("POST", "/api/data/credential/", bytes.NewBuffer(jsonData))
-	req.Header.Set("Content-Type", "application/json")
-
-	// Создаем ResponseRecorder для записи ответа
-	rr := httptest.NewRecorder()
-
-	// Act
-	controller.SaveCredential(rr, req)
-
-	// Assert
-	assert.Equal(t, http.StatusBadRequest, rr.Code)
-	mockDataUseCase.AssertNotCalled(t, "SaveCredential")
-}
-
-func TestDataController_SaveCredential_InvalidJSON(t *testing.T) {
-	// Arrange
-	mockDataUseCase := new(MockDataUseCase)
-	controller := NewDataController(mockDataUseCase)
-
-	// Создаем тестовые данные
-	label := "test-label"
-
-	// Создаем некорректный JSON
-	invalidJSON := []byte(`{"credential_data": {"login": "testuser", "password":}, "metadata": "test"}`)
-
-	// Создаем запрос с параметрами URL
-	req, rr := createRequestWithURLParam("POST", "/api/data/credential/"+label, "label", label, invalidJSON)
-
-	// Act
-	controller.SaveCredential(rr, req)
-
-	// Assert
-	assert.Equal(t, http.StatusBadRequest, rr.Code)
-	mockDataUseCase.AssertNotCalled(t, "SaveCredential")
 }
 
-func TestDataController_SaveCredential_MissingCredentialData(t *testing.T) {
+func TestDataController_GetItem_InvalidRedact(t *testing.T) {
 	// Arrange
 	mockDataUseCase := new(MockDataUseCase)
 	controller := NewDataController(mockDataUseCase)
-
-	// Создаем тестовые данные
-	label := "test-label"
-	
-	// Создаем JSON без данных учетной записи
-	requestData := struct {
-		CredentialData *domain.CredentialData `json:"credential_data"`
-		Metadata       string                 `json:"metadata"`
-	}{
-		CredentialData: nil,
-		Metadata:       "test metadata",
-	}
-	jsonData, _ := json.Marshal(requestData)
-
-	// Создаем запрос с параметрами URL
-	req, rr := createRequestWithURLParam("POST", "/api/data/credential/"+label, "label", label, jsonData)
+	req, rr := createItemRequest("GET", "/api/v2/items/card/visa?redact=maybe", domain.UserDataTypeCard, "visa", nil)
 
 	// Act
-	controller.SaveCredential(rr, req)
+	controller.GetItem(rr, req)
 
 	// Assert
 	assert.Equal(t, http.StatusBadRequest, rr.Code)
-	mockDataUseCase.AssertNotCalled(t, "SaveCredential")
+	mockDataUseCase.AssertNotCalled(t, "GetItem")
 }
 
-func TestDataController_GetCredential_MissingLabel(t *testing.T) {
+func TestDataController_DeleteItem(t *testing.T) {
 	// Arrange
 	mockDataUseCase := new(MockDataUseCase)
 	controller := NewDataController(mockDataUseCase)
+	req, rr := createItemRequest("DELETE", "/api/v2/items/text/note", domain.UserDataTypeText, "note", nil)
 
-	// Создаем запрос без параметра label
-	req, _ := http.NewRequest("GET", "/api/data/credential/", nil)
-	req.Header.Set("Content-Type", "application/json")
-
-	// Создаем ResponseRecorder для записи ответа
-	rr := httptest.NewRecorder()
+	// Настраиваем поведение мока
+	mockDataUseCase.On("DeleteItem", mock.Anything, mock.Anything, domain.UserDataTypeText, "note")
 
 	// Act
-	controller.GetCredential(rr, req)
+	controller.DeleteItem(rr, req)
 
 	// Assert
-	assert.Equal(t, http.StatusBadRequest, rr.Code)
-	mockDataUseCase.AssertNotCalled(t, "GetCredential")
+	mockDataUseCase.AssertExpectations(t)
 }
 
-func TestDataController_DeleteCredential_MissingLabel(t *testing.T) {
+func TestDataController_DeleteItem_MissingLabel(t *testing.T) {
 	// Arrange
 	mockDataUseCase := new(MockDataUseCase)
 	controller := NewDataController(mockDataUseCase)
-
-	// Создаем запрос без параметра label
-	req, _ := http.NewRequest("DELETE", "/api/data/credential/", nil)
-	req.Header.Set("Content-Type", "application/json")
-
-	// Создаем ResponseRecorder для записи ответа
-	rr := httptest.NewRecorder()
+	req, rr := createItemRequest("DELETE", "/api/v2/items/text/", domain.UserDataTypeText, "", nil)
 
 	// Act
-	controller.DeleteCredential(rr, req)
+	controller.DeleteItem(rr, req)
 
 	// Assert
 	assert.Equal(t, http.StatusBadRequest, rr.Code)
-	mockDataUseCase.AssertNotCalled(t, "DeleteCredential")
+	mockDataUseCase.AssertNotCalled(t, "DeleteItem")
 }
 
-func TestDataController_SaveCard_MissingLabel(t *testing.T) {
-	// Arrange
-	mockDataUseCase := new(MockDataUseCase)
-	controller := NewDataController(mockDataUseCase)
+// Тесты для маршрутов API v1
 
-	// Создаем тестовые данные
-	cardData := domain.CardData{
-		Number:     "1234567890123456",
-		Holder:     "Test User",
-		ExpiryDate: "12/25",
-		CVV:        "123",
-	}
-	metadata := "card metadata"
-
-	// Создаем JSON из данных
-	requestData := struct {
-		CardData *domain.CardData `json:"card_data"`
-		Metadata string           `json:"metadata"`
+func TestDataController_SaveLegacyItem(t *testing.T) {
+	tests := []struct {
+		itemType string
+		key      string
+		data     domain.ItemData
 	}{
-		CardData: &cardData,
-		Metadata: metadata,
+		{domain.UserDataTypeCredential, "credential_data", domain.ItemData{"login": "testuser", "password": "password123"}},
+		{domain.UserDataTypeCard, "card_data", domain.ItemData{"number": "4111111111111111", "holder": "ALICE", "expiry_date": "12/27", "cvv": "123"}},
+		{domain.UserDataTypeText, "text_data", domain.ItemData{"content": "заметка"}},
+		{domain.UserDataTypeSSHKey, "ssh_key_data", domain.ItemData{"private_key": "key", "public_key": "ssh-ed25519 AAAA", "fingerprint": "SHA256:x"}},
+		{domain.UserDataTypeWiFi, "item_data", domain.ItemData{"ssid": "home", "security": "WPA2", "password": "hunter22"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.itemType, func(t *testing.T) {
+			// Arrange
+			mockDataUseCase := new(MockDataUseCase)
+			controller := NewDataController(mockDataUseCase)
+			jsonData, _ := json.Marshal(map[string]interface{}{tt.key: tt.data, "metadata": "test metadata"})
+			req, rr := createItemRequest("POST", "/api/data/"+tt.itemType+"/test-label", tt.itemType, "test-label", jsonData)
+
+			// Настраиваем поведение мока
+			mockDataUseCase.On("SaveItem", mock.Anything, mock.Anything, tt.itemType, "test-label", tt.data, "test metadata")
+
+			// Act
+			controller.SaveLegacyItem(rr, req)
+
+			// Assert
+			mockDataUseCase.AssertExpectations(t)
+		})
 	}
-	jsonData, _ := json.Marshal(requestData)
-
-	// Создаем запрос без параметра label
-	req, _ := http.NewRequest("POST", "/api/data/card/", bytes.NewBuffer(jsonData))
-	req.Header.Set("Content-Type", "application/json")
-
-	// Создаем ResponseRecorder для записи ответа
-	rr := httptest.NewRecorder()
-
-	// Act
-	controller.SaveCard(rr, req)
-
-	// Assert
-	assert.Equal(t, http.StatusBadRequest, rr.Code)
-	mockDataUseCase.AssertNotCalled(t, "SaveCard")
 }
 
-func TestDataController_SaveCard_InvalidJSON(t *testing.T) {
+// TestDataController_SaveLegacyItem_Lists проверяет, что поля-списки API v1 принимаются списками JSON
+func TestDataController_SaveLegacyItem_Lists(t *testing.T) {
 	// Arrange
 	mockDataUseCase := new(MockDataUseCase)
 	controller := NewDataController(mockDataUseCase)
+	body := `{"credential_data":{"login":"alice","password":"x","uris":[{"uri": "https://github.com", "match": "host"}]}}`
+	req, rr := createItemRequest("POST", "/api/data/credential/github", domain.UserDataTypeCredential, "github", []byte(body))
 
-	// Создаем тестовые данные
-	label := "test-label"
-
-	// Создаем некорректный JSON
-	invalidJSON := []byte(`{"card_data": {"number": "1234567890123456", "holder":}, "metadata": "test"}`)
-
-	// Создаем запрос с параметрами URL
-	req, rr := createRequestWithURLParam("POST", "/api/data/card/"+label, "label", label, invalidJSON)
+	want := domain.ItemData{"login": "alice", "password": "x", "uris": `[{"uri":"https://github.com","match":"host"}]`}
+	mockDataUseCase.On("SaveItem", mock.Anything, mock.Anything, domain.UserDataTypeCredential, "github", want, "")
 
 	// Act
-	controller.SaveCard(rr, req)
+	controller.SaveLegacyItem(rr, req)
 
 	// Assert
-	assert.Equal(t, http.StatusBadRequest, rr.Code)
-	mockDataUseCase.AssertNotCalled(t, "SaveCard")
+	mockDataUseCase.AssertExpectations(t)
 }
 
-func TestDataController_SaveCard_MissingCardData(t *testing.T) {
-	// Arrange
-	mockDataUseCase := new(MockDataUseCase)
-	controller := NewDataController(mockDataUseCase)
-
-	// Создаем тестовые данные
-	label := "test-label"
-	
-	// Создаем JSON без данных карты
-	requestData := struct {
-		CardData *domain.CardData `json:"card_data"`
-		Metadata string           `json:"metadata"`
+func TestDataController_SaveLegacyItem_BadRequest(t *testing.T) {
+	tests := []struct {
+		name     string
+		itemType string
+		body     string
+		code     int
 	}{
-		CardData: nil,
-		Metadata: "test metadata",
+		{"неизвестный тип", "file", `{"item_data":{"name":"a"}}`, http.StatusNotFound},
+		{"поля под ключом API v2", domain.UserDataTypeCredential, `{"data":{"login":"testuser"}}`, http.StatusBadRequest},
+		{"нет учетных данных", domain.UserDataTypeCredential, `{"credential_data":null,"metadata":"test metadata"}`, http.StatusBadRequest},
+		{"неверный JSON", domain.UserDataTypeCard, `{"card_data": {"number":}}`, http.StatusBadRequest},
+		{"список в строковом поле", domain.UserDataTypeCredential, `{"credential_data":{"login":["alice"]}}`, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			mockDataUseCase := new(MockDataUseCase)
+			controller := NewDataController(mockDataUseCase)
+			req, rr := createItemRequest("POST", "/api/data/"+tt.itemType+"/test-label", tt.itemType, "test-label", []byte(tt.body))
+
+			// Act
+			controller.SaveLegacyItem(rr, req)
+
+			// Assert
+			assert.Equal(t, tt.code, rr.Code)
+			mockDataUseCase.AssertNotCalled(t, "SaveItem")
+		})
 	}
-	jsonData, _ := json.Marshal(requestData)
-
-	// Создаем запрос с параметрами URL
-	req, rr := createRequestWithURLParam("POST", "/api/data/card/"+label, "label", label, jsonData)
-
-	// Act
-	controller.SaveCard(rr, req)
-
-	// Assert
-	assert.Equal(t, http.StatusBadRequest, rr.Code)
-	mockDataUseCase.AssertNotCalled(t, "SaveCard")
 }
 
-func TestDataController_GetCard_MissingLabel(t *testing.T) {
+func TestDataController_GetLegacyItem(t *testing.T) {
 	// Arrange
 	mockDataUseCase := new(MockDataUseCase)
 	controller := NewDataController(mockDataUseCase)
+	req, rr := createItemRequest("GET", "/api/data/credential/test-label", domain.UserDataTypeCredential, "test-label", nil)
 
-	// Создаем запрос без параметра label
-	req, _ := http.NewRequest("GET", "/api/data/card/", nil)
-	req.Header.Set("Content-Type", "application/json")
-
-	// Создаем ResponseRecorder для записи ответа
-	rr := httptest.NewRecorder()
+	// Настраиваем поведение мока
+	mockDataUseCase.On("GetItem", mock.Anything, mock.Anything, domain.UserDataTypeCredential, "test-label",
+		domain.ItemView{DataKey: "credential_data", Lists: true})
 
 	// Act
-	controller.GetCard(rr, req)
+	controller.GetLegacyItem(rr, req)
 
 	// Assert
-	assert.Equal(t, http.StatusBadRequest, rr.Code)
-	mockDataUseCase.AssertNotCalled(t, "GetCard")
+	mockDataUseCase.AssertExpectations(t)
 }
 
-func TestDataController_DeleteCard_MissingLabel(t *testing.T) {
+func TestDataController_GetLegacyItem_UnknownType(t *testing.T) {
 	// Arrange
 	mockDataUseCase := new(MockDataUseCase)
 	controller := NewDataController(mockDataUseCase)
-
-	// Создаем запрос без параметра label
-	req, _ := http.NewRequest("DELETE", "/api/data/card/", nil)
-	req.Header.Set("Content-Type", "application/json")
-
-	// Создаем ResponseRecorder для записи ответа
-	rr := httptest.NewRecorder()
+	req, rr := createItemRequest("GET", "/api/data/file/test-label", domain.UserDataTypeFile, "test-label", nil)
 
 	// Act
-	controller.DeleteCard(rr, req)
+	controller.GetLegacyItem(rr, req)
 
 	// Assert
-	assert.Equal(t, http.StatusBadRequest, rr.Code)
-	mockDataUseCase.AssertNotCalled(t, "DeleteCard")
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+	mockDataUseCase.AssertNotCalled(t, "GetItem")
 }
 
-func TestDataController_SaveText_MissingLabel(t *testing.T) {
-	// Arrange
-	mockDataUseCase := new(MockDataUseCase)
-	controller := NewDataController(mockDataUseCase)
-
-	// Создаем тестовые данные
-	textData := domain.TextData{
-		Content: "This is a test text content",
-	}
-	metadata := "text metadata"
-
-	// Создаем JSON из данных
-	requestData := struct {
-		TextData *domain.TextData `json:"text_data"`
-		Metadata string           `json:"metadata"`
-	}{
-		TextData: &textData,
-		Metadata: metadata,
-	}
-	jsonData, _ := json.Marshal(requestData)
-
-	// Создаем запрос без параметра label
-	req, _ := http.NewRequest("POST", "/api/data/text/", bytes.NewBuffer(jsonData))
-	req.Header.Set("Content-Type", "application/json")
-
-	// Создаем ResponseRecorder для записи ответа
-	rr := httptest.NewRecorder()
-
-	// Act
-	controller.SaveText(rr, req)
-
-	// Assert
-	assert.Equal(t, http.StatusBadRequest, rr.Code)
-	mockDataUseCase.AssertNotCalled(t, "SaveText")
-}
+// Тесты для маршрутов шаблонов пользовательских типов
 
-func TestDataController_SaveText_InvalidJSON(t *testing.T) {
+func TestDataController_SaveItemTemplate(t *testing.T) {
 	// Arrange
 	mockDataUseCase := new(MockDataUseCase)
 	controller := NewDataController(mockDataUseCase)
+	schema := []byte(`{"type": "object", "properties": {"host": {"type": "string"}}}`)
+	req, rr := createRequestWithURLParam("POST", "/api/v2/templates/db_profile", "name", "db_profile", schema)
 
-	// Создаем тестовые данные
-	label := "test-label"
-
-	// Создаем некорректный JSON
-	invalidJSON := []byte(`{"text_data": {"content":}, "metadata": "test"}`)
-
-	// Создаем запрос с параметрами URL
-	req, rr := createRequestWithURLParam("POST", "/api/data/text/"+label, "label", label, invalidJSON)
+	// Настраиваем поведение мока
+	mockDataUseCase.On("SaveItemTemplate", mock.Anything, mock.Anything, "db_profile", json.RawMessage(schema))
 
 	// Act
-	controller.SaveText(rr, req)
+	controller.SaveItemTemplate(rr, req)
 
 	// Assert
-	assert.Equal(t, http.StatusBadRequest, rr.Code)
-	mockDataUseCase.AssertNotCalled(t, "SaveText")
+	mockDataUseCase.AssertExpectations(t)
 }
 
-func TestDataController_SaveText_MissingTextData(t *testing.T) {
-	// Arrange
-	mockDataUseCase := new(MockDataUseCase)
-	controller := NewDataController(mockDataUseCase)
-
-	// Создаем тестовые данные
-	label := "test-label"
-	
-	// Создаем JSON без текстовых данных
-	requestData := struct {
-		TextData *domain.TextData `json:"text_data"`
-		Metadata string           `json:"metadata"`
+func TestDataController_SaveItemTemplate_BadRequest(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		body     []byte
 	}{
-		TextData: nil,
-		Metadata: "test metadata",
+		{"нет имени", "", []byte(`{"type": "object"}`)},
+		{"не JSON", "db_profile", []byte(`{"type": `)},
+		{"пустое тело", "db_profile", nil},
+		{"слишком большая схема", "db_profile", bytes.Repeat([]byte(" "), itemtype.MaxSchemaSize+1)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			mockDataUseCase := new(MockDataUseCase)
+			controller := NewDataController(mockDataUseCase)
+			req, rr := createRequestWithURLParam("POST", "/api/v2/templates/"+tt.template, "name", tt.template, tt.body)
+
+			// Act
+			controller.SaveItemTemplate(rr, req)
+
+			// Assert
+			assert.Equal(t, http.StatusBadRequest, rr.Code)
+			mockDataUseCase.AssertNotCalled(t, "SaveItemTemplate")
+		})
 	}
-	jsonData, _ := json.Marshal(requestData)
-
-	// Создаем запрос с параметрами URL
-	req, rr := createRequestWithURLParam("POST", "/api/data/text/"+label, "label", label, jsonData)
-
-	// Act
-	controller.SaveText(rr, req)
-
-	// Assert
-	assert.Equal(t, http.StatusBadRequest, rr.Code)
-	mockDataUseCase.AssertNotCalled(t, "SaveText")
-}
-
-func TestDataController_GetText_MissingLabel(t *testing.T) {
-	// Arrange
-	mockDataUseCase := new(MockDataUseCase)
-	controller := NewDataController(mockDataUseCase)
-
-	// Создаем запрос без параметра label
-	req, _ := http.NewRequest("GET", "/api/data/text/", nil)
-	req.Header.Set("Content-Type", "application/json")
-
-	// Создаем ResponseRecorder для записи ответа
-	rr := httptest.NewRecorder()
-
-	// Act
-	controller.GetText(rr, req)
-
-	// Assert
-	assert.Equal(t, http.StatusBadRequest, rr.Code)
-	mockDataUseCase.AssertNotCalled(t, "GetText")
 }
 
-func TestDataController_DeleteText_MissingLabel(t *testing.T) {
+func TestDataController_ItemTemplates(t *testing.T) {
 	// Arrange
 	mockDataUseCase := new(MockDataUseCase)
 	controller := NewDataController(mockDataUseCase)
 
-	// Создаем запрос без параметра label
-	req, _ := http.NewRequest("DELETE", "/api/data/text/", nil)
-	req.Header.Set("Content-Type", "application/json")
-
-	// Создаем ResponseRecorder для записи ответа
-	rr := httptest.NewRecorder()
+	// Настраиваем поведение мока
+	mockDataUseCase.On("GetItemTemplate", mock.Anything, mock.Anything, "db_profile")
+	mockDataUseCase.On("DeleteItemTemplate", mock.Anything, mock.Anything, "db_profile")
+	mockDataUseCase.On("ListItemTemplates", mock.Anything, mock.Anything)
 
 	// Act
-	controller.DeleteText(rr, req)
+	req, rr := createRequestWithURLParam("GET", "/api/v2/templates/db_profile", "name", "db_profile", nil)
+	controller.GetItemTemplate(rr, req)
+	req, rr = createRequestWithURLParam("DELETE", "/api/v2/templates/db_profile", "name", "db_profile", nil)
+	controller.DeleteItemTemplate(rr, req)
+	req, rr = createRequestWithURLParam("GET", "/api/v2/templates", "name", "", nil)
+	controller.ListItemTemplates(rr, req)
 
 	// Assert
-	assert.Equal(t, http.StatusBadRequest, rr.Code)
-	mockDataUseCase.AssertNotCalled(t, "DeleteText")
-}
-
-func TestNewDataController(t *testing.T) {
-	// Arrange
-	mockDataUseCase := new(MockDataUseCase)
-	
-	// Act
-	controller := NewDataController(mockDataUseCase)
-	
-	// Assert
-	assert.NotNil(t, controller)
-	assert.Equal(t, mockDataUseCase, controller.dataUseCase)
+	mockDataUseCase.AssertExpectations(t)
 }
@@ -67,3 +67,116 @@ func (f *FileController) HandleDownloadFile(w http.ResponseWriter, r *http.Reque
 	// Генерируем ссылку для скачивания
 	f.FileUseCase.GenerateDownloadLink(w, r, label)
 }
+
+// HandleDeleteFile godoc
+// @Summary Удаление файла
+// @Description Удаляет файл из хранилища вместе с его метаданными
+// @Tags files
+// @Produce json
+// @Security ApiKeyAuth
+// @Param Authorization header string true "Bearer токен авторизации"
+// @Param label query string true "Метка файла"
+// @Success 200 {object} map[string]string "Файл удален"
+// @Failure 400 {object} map[string]string "Ошибка в формате запроса"
+// @Failure 401 {object} map[string]string "Пользователь не авторизован"
+// @Failure 404 {object} map[string]string "Файл не найден"
+// @Failure 500 {object} map[string]string "Внутренняя ошибка сервера"
+// @Router /api/file [delete]
+func (f *FileController) HandleDeleteFile(w http.ResponseWriter, r *http.Request) {
+	label := r.URL.Query().Get("label")
+	if label == "" {
+		http.Error(w, "Не указана метка файла", http.StatusBadRequest)
+		return
+	}
+
+	f.FileUseCase.DeleteFile(w, r, label)
+}
+
+// HandleVerifyFile godoc
+// @Summary Проверка типа загруженного файла
+// @Description Определяет тип загруженного файла по содержимому и проверяет его политикой типов файлов. Файл запрещенного типа удаляется вместе с метаданными
+// @Tags files
+// @Produce json
+// @Security ApiKeyAuth
+// @Param Authorization header string true "Bearer токен авторизации"
+// @Param label query string true "Метка файла"
+// @Success 200 {object} map[string]string "Тип файла разрешен"
+// @Failure 400 {object} map[string]string "Ошибка в формате запроса"
+// @Failure 401 {object} map[string]string "Пользователь не авторизован"
+// @Failure 404 {object} map[string]string "Файл не найден"
+// @Failure 415 {object} map[string]string "Тип файла запрещен, файл удален"
+// @Failure 500 {object} map[string]string "Внутренняя ошибка сервера"
+// @Router /api/file/verify [post]
+func (f *FileController) HandleVerifyFile(w http.ResponseWriter, r *http.Request) {
+	label := r.URL.Query().Get("label")
+	if label == "" {
+		http.Error(w, "Не указана метка файла", http.StatusBadRequest)
+		return
+	}
+
+	f.FileUseCase.VerifyFile(w, r, label)
+}
+
+// HandleRenameFile godoc
+// @Summary Переименование файла
+// @Description Меняет метку файла и переносит объект в хранилище под новое имя
+// @Tags files
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param Authorization header string true "Bearer токен авторизации"
+// @Param rename body domain.FileRename true "Текущая и новая метки файла"
+// @Success 200 {object} map[string]string "Файл переименован"
+// @Failure 400 {object} map[string]string "Ошибка в формате запроса"
+// @Failure 401 {object} map[string]string "Пользователь не авторизован"
+// @Failure 404 {object} map[string]string "Файл не найден"
+// @Failure 409 {object} map[string]string "Новая метка уже занята"
+// @Failure 500 {object} map[string]string "Внутренняя ошибка сервера"
+// @Router /api/file/rename [post]
+func (f *FileController) HandleRenameFile(w http.ResponseWriter, r *http.Request) {
+	rename, err := paramsparser.JSONParse[domain.FileRename](w, r)
+	if err != nil {
+		return
+	}
+
+	f.FileUseCase.RenameFile(w, r, rename)
+}
+
+// HandleListFiles godoc
+// @Summary Список файлов
+// @Description Возвращает сведения обо всех файлах пользователя
+// @Tags files
+// @Produce json
+// @Security ApiKeyAuth
+// @Param Authorization header string true "Bearer токен авторизации"
+// @Success 200 {array} domain.FileInfo "Список файлов"
+// @Failure 401 {object} map[string]string "Пользователь не авторизован"
+// @Failure 500 {object} map[string]string "Внутренняя ошибка сервера"
+// @Router /api/file/list [get]
+func (f *FileController) HandleListFiles(w http.ResponseWriter, r *http.Request) {
+	f.FileUseCase.ListFiles(w, r)
+}
+
+// HandleFileInfo godoc
+// @Summary Сведения о файле
+// @Description Возвращает расширение, размер, метаинформацию и даты изменения файла
+// @Tags files
+// @Produce json
+// @Security ApiKeyAuth
+// @Param Authorization header string true "Bearer токен авторизации"
+// @Param label query string true "Метка файла"
+// @Success 200 {object} domain.FileInfo "Сведения о файле"
+// @Failure 400 {object} map[string]string "Ошибка в формате запроса"
+// @Failure 401 {object} map[string]string "Пользователь не авторизован"
+// @Failure 404 {object} map[string]string "Файл не найден"
+// @Failure 500 {object} map[string]string "Внутренняя ошибка сервера"
+// @Router /api/file/info [get]
+func (f *FileController) HandleFileInfo(w http.ResponseWriter, r *http.Request) {
+	label := r.URL.Query().Get("label")
+	if label == "" {
+		http.Error(w, "Не указана метка файла", http.StatusBadRequest)
+		return
+	}
+
+	f.FileUseCase.GetFileInfo(w, r, label)
+}
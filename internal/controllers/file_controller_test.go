@@ -24,6 +24,26 @@ func (m *MockCloudUseCase) GenerateDownloadLink(w http.ResponseWriter, r *http.R
 	m.Called(w, r, label)
 }
 
+func (m *MockCloudUseCase) DeleteFile(w http.ResponseWriter, r *http.Request, label string) {
+	m.Called(w, r, label)
+}
+
+func (m *MockCloudUseCase) VerifyFile(w http.ResponseWriter, r *http.Request, label string) {
+	m.Called(w, r, label)
+}
+
+func (m *MockCloudUseCase) RenameFile(w http.ResponseWriter, r *http.Request, rename *domain.FileRename) {
+	m.Called(w, r, rename)
+}
+
+func (m *MockCloudUseCase) ListFiles(w http.ResponseWriter, r *http.Request) {
+	m.Called(w, r)
+}
+
+func (m *MockCloudUseCase) GetFileInfo(w http.ResponseWriter, r *http.Request, label string) {
+	m.Called(w, r, label)
+}
+
 // Тест для HandleUploadFile
 func TestFileController_HandleUploadFile(t *testing.T) {
 	// Arrange
@@ -125,4 +145,131 @@ func TestFileController_HandleDownloadFile_MissingLabel(t *testing.T) {
 	// Assert
 	assert.Equal(t, http.StatusBadRequest, rr.Code)
 	mockCloudUseCase.AssertNotCalled(t, "GenerateDownloadLink")
-}
\ No newline at end of file
+}
+// Тест для HandleDeleteFile
+func TestFileController_HandleDeleteFile(t *testing.T) {
+	// Arrange
+	mockCloudUseCase := new(MockCloudUseCase)
+	controller := NewFileController(mockCloudUseCase)
+
+	req, _ := http.NewRequest("DELETE", "/api/file?label=test-file", nil)
+	rr := httptest.NewRecorder()
+
+	mockCloudUseCase.On("DeleteFile", mock.Anything, mock.Anything, "test-file")
+
+	// Act
+	controller.HandleDeleteFile(rr, req)
+
+	// Assert
+	mockCloudUseCase.AssertExpectations(t)
+}
+
+// Тест для HandleDeleteFile с пустой меткой
+func TestFileController_HandleDeleteFile_EmptyLabel(t *testing.T) {
+	// Arrange
+	mockCloudUseCase := new(MockCloudUseCase)
+	controller := NewFileController(mockCloudUseCase)
+
+	req, _ := http.NewRequest("DELETE", "/api/file", nil)
+	rr := httptest.NewRecorder()
+
+	// Act
+	controller.HandleDeleteFile(rr, req)
+
+	// Assert
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	mockCloudUseCase.AssertNotCalled(t, "DeleteFile", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// Тест для HandleVerifyFile
+func TestFileController_HandleVerifyFile(t *testing.T) {
+	// Arrange
+	mockCloudUseCase := new(MockCloudUseCase)
+	controller := NewFileController(mockCloudUseCase)
+
+	req, _ := http.NewRequest("POST", "/api/file/verify?label=test-file", nil)
+	rr := httptest.NewRecorder()
+
+	mockCloudUseCase.On("VerifyFile", mock.Anything, mock.Anything, "test-file")
+
+	// Act
+	controller.HandleVerifyFile(rr, req)
+
+	// Assert
+	mockCloudUseCase.AssertExpectations(t)
+}
+
+// Тест для HandleVerifyFile с пустой меткой
+func TestFileController_HandleVerifyFile_EmptyLabel(t *testing.T) {
+	// Arrange
+	mockCloudUseCase := new(MockCloudUseCase)
+	controller := NewFileController(mockCloudUseCase)
+
+	req, _ := http.NewRequest("POST", "/api/file/verify", nil)
+	rr := httptest.NewRecorder()
+
+	// Act
+	controller.HandleVerifyFile(rr, req)
+
+	// Assert
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	mockCloudUseCase.AssertNotCalled(t, "VerifyFile", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// Тест для HandleRenameFile
+func TestFileController_HandleRenameFile(t *testing.T) {
+	// Arrange
+	mockCloudUseCase := new(MockCloudUseCase)
+	controller := NewFileController(mockCloudUseCase)
+
+	jsonData, _ := json.Marshal(domain.FileRename{Label: "old", NewLabel: "new"})
+	req, _ := http.NewRequest("POST", "/api/file/rename", bytes.NewBuffer(jsonData))
+	req.Header.Set("Content-Type", "application/json")
+	rr := httptest.NewRecorder()
+
+	mockCloudUseCase.On("RenameFile", mock.Anything, mock.Anything, mock.MatchedBy(func(f *domain.FileRename) bool {
+		return f.Label == "old" && f.NewLabel == "new"
+	}))
+
+	// Act
+	controller.HandleRenameFile(rr, req)
+
+	// Assert
+	mockCloudUseCase.AssertExpectations(t)
+}
+
+// Тест для HandleListFiles
+func TestFileController_HandleListFiles(t *testing.T) {
+	// Arrange
+	mockCloudUseCase := new(MockCloudUseCase)
+	controller := NewFileController(mockCloudUseCase)
+
+	req, _ := http.NewRequest("GET", "/api/file/list", nil)
+	rr := httptest.NewRecorder()
+
+	mockCloudUseCase.On("ListFiles", mock.Anything, mock.Anything)
+
+	// Act
+	controller.HandleListFiles(rr, req)
+
+	// Assert
+	mockCloudUseCase.AssertExpectations(t)
+}
+
+// Тест для HandleFileInfo
+func TestFileController_HandleFileInfo(t *testing.T) {
+	// Arrange
+	mockCloudUseCase := new(MockCloudUseCase)
+	controller := NewFileController(mockCloudUseCase)
+
+	req, _ := http.NewRequest("GET", "/api/file/info?label=test-file", nil)
+	rr := httptest.NewRecorder()
+
+	mockCloudUseCase.On("GetFileInfo", mock.Anything, mock.Anything, "test-file")
+
+	// Act
+	controller.HandleFileInfo(rr, req)
+
+	// Assert
+	mockCloudUseCase.AssertExpectations(t)
+}
@@ -0,0 +1,65 @@
+package controllers
+
+import (
+	"github.com/SmirnovND/gophkeeper/internal/interfaces"
+	"net/http"
+)
+
+type UserController struct {
+	UserUseCase interfaces.UserUseCase
+}
+
+func NewUserController(UserUseCase interfaces.UserUseCase) *UserController {
+	return &UserController{
+		UserUseCase: UserUseCase,
+	}
+}
+
+// HandleUsage godoc
+// @Summary Потребление хранилища
+// @Description Возвращает количество записей, файлов, суммарный размер файлов и действующую квоту пользователя
+// @Tags user
+// @Produce json
+// @Security ApiKeyAuth
+// @Param Authorization header string true "Bearer токен авторизации"
+// @Success 200 {object} domain.Usage "Текущее потребление и квота"
+// @Failure 401 {object} map[string]string "Пользователь не авторизован"
+// @Failure 500 {object} map[string]string "Внутренняя ошибка сервера"
+// @Router /api/user/usage [get]
+func (u *UserController) HandleUsage(w http.ResponseWriter, r *http.Request) {
+	u.UserUseCase.GetUsage(w, r)
+}
+
+// HandleGetSettings godoc
+// @Summary Настройки аккаунта
+// @Description Возвращает действующие настройки аккаунта: заданные пользователем или значения сервера по умолчанию
+// @Tags user
+// @Produce json
+// @Security ApiKeyAuth
+// @Param Authorization header string true "Bearer токен авторизации"
+// @Success 200 {object} domain.UserSettings "Настройки аккаунта"
+// @Failure 401 {object} map[string]string "Пользователь не авторизован"
+// @Failure 500 {object} map[string]string "Внутренняя ошибка сервера"
+// @Router /api/user/settings [get]
+func (u *UserController) HandleGetSettings(w http.ResponseWriter, r *http.Request) {
+	u.UserUseCase.GetSettings(w, r)
+}
+
+// HandleSaveSettings godoc
+// @Summary Изменить настройки аккаунта
+// @Description Сохраняет настройки аккаунта. password_history - сколько прежних паролей хранится у учетных данных, от 0 до 100.
+// @Description Уменьшение длины истории применяется к записи при ее следующем сохранении
+// @Tags user
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param Authorization header string true "Bearer токен авторизации"
+// @Param settings body domain.UserSettings true "Настройки аккаунта"
+// @Success 200 {object} domain.UserSettings "Сохраненные настройки"
+// @Failure 400 {object} map[string]string "Недопустимые настройки"
+// @Failure 401 {object} map[string]string "Пользователь не авторизован"
+// @Failure 500 {object} map[string]string "Внутренняя ошибка сервера"
+// @Router /api/user/settings [put]
+func (u *UserController) HandleSaveSettings(w http.ResponseWriter, r *http.Request) {
+	u.UserUseCase.SaveSettings(w, r)
+}
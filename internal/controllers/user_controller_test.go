@@ -0,0 +1,65 @@
+package controllers
+
+import (
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// Создаем мок для UserUseCase
+type MockUserUseCase struct {
+	mock.Mock
+}
+
+func (m *MockUserUseCase) GetUsage(w http.ResponseWriter, r *http.Request) {
+	m.Called(w, r)
+}
+
+func (m *MockUserUseCase) GetSettings(w http.ResponseWriter, r *http.Request) {
+	m.Called(w, r)
+}
+
+func (m *MockUserUseCase) SaveSettings(w http.ResponseWriter, r *http.Request) {
+	m.Called(w, r)
+}
+
+// Тест для HandleUsage
+func TestUserController_HandleUsage(t *testing.T) {
+	// Arrange
+	mockUserUseCase := new(MockUserUseCase)
+	controller := NewUserController(mockUserUseCase)
+
+	req, _ := http.NewRequest("GET", "/api/user/usage", nil)
+	rr := httptest.NewRecorder()
+
+	mockUserUseCase.On("GetUsage", mock.Anything, mock.Anything)
+
+	// Act
+	controller.HandleUsage(rr, req)
+
+	// Assert
+	mockUserUseCase.AssertExpectations(t)
+}
+
+// Тест для HandleGetSettings и HandleSaveSettings
+func TestUserController_HandleSettings(t *testing.T) {
+	// Arrange
+	mockUserUseCase := new(MockUserUseCase)
+	controller := NewUserController(mockUserUseCase)
+
+	getReq, _ := http.NewRequest("GET", "/api/user/settings", nil)
+	putReq, _ := http.NewRequest("PUT", "/api/user/settings", strings.NewReader(`{"password_history":5}`))
+	rr := httptest.NewRecorder()
+
+	mockUserUseCase.On("GetSettings", mock.Anything, getReq)
+	mockUserUseCase.On("SaveSettings", mock.Anything, putReq)
+
+	// Act
+	controller.HandleGetSettings(rr, getReq)
+	controller.HandleSaveSettings(rr, putReq)
+
+	// Assert
+	mockUserUseCase.AssertExpectations(t)
+}
@@ -0,0 +1,88 @@
+// Package dockercredential реализует протокол помощников учетных данных docker (docker-credential-helpers):
+// запросы и ответы в JSON через stdin и stdout, метки записей для адресов реестров
+package dockercredential
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// LabelPrefix - префикс меток учетных данных реестров
+const LabelPrefix = "docker:"
+
+// maxRequestSize ограничивает размер запроса docker
+const maxRequestSize = 64 * 1024
+
+// Сообщения об ошибках, которые docker распознает по тексту ответа помощника
+var (
+	ErrNotFound         = errors.New("credentials not found in native keychain")
+	ErrMissingServerURL = errors.New("no credentials server URL")
+	ErrMissingUsername  = errors.New("no credentials username")
+)
+
+// Credentials - учетные данные реестра. Для токена доступа docker передает имя пользователя "<token>"
+type Credentials struct {
+	ServerURL string `json:"ServerURL"`
+	Username  string `json:"Username"`
+	Secret    string `json:"Secret"`
+}
+
+// ReadServerURL читает адрес реестра - запрос операций get и erase
+func ReadServerURL(r io.Reader) (string, error) {
+	data, err := io.ReadAll(io.LimitReader(r, maxRequestSize))
+	if err != nil {
+		return "", err
+	}
+	serverURL := strings.TrimSpace(string(data))
+	if serverURL == "" {
+		return "", ErrMissingServerURL
+	}
+	return serverURL, nil
+}
+
+// ReadCredentials читает учетные данные - запрос операции store
+func ReadCredentials(r io.Reader) (*Credentials, error) {
+	var credentials Credentials
+	if err := json.NewDecoder(io.LimitReader(r, maxRequestSize)).Decode(&credentials); err != nil {
+		return nil, fmt.Errorf("неверный запрос docker: %w", err)
+	}
+	credentials.ServerURL = strings.TrimSpace(credentials.ServerURL)
+	if credentials.ServerURL == "" {
+		return nil, ErrMissingServerURL
+	}
+	if credentials.Username == "" {
+		return nil, ErrMissingUsername
+	}
+	return &credentials, nil
+}
+
+// Label возвращает метку учетных данных реестра serverURL
+func Label(serverURL string) string {
+	return LabelPrefix + serverURL
+}
+
+// ServerURL возвращает адрес реестра по метке и false, если метка не относится к учетным данным реестров
+func ServerURL(label string) (string, bool) {
+	serverURL, ok := strings.CutPrefix(label, LabelPrefix)
+	return serverURL, ok && serverURL != ""
+}
+
+// SameRegistry сообщает, указывают ли адреса на один реестр. docker передает адрес то со схемой, то без нее,
+// например https://registry.example.com/ и registry.example.com
+func SameRegistry(a string, b string) bool {
+	return normalize(a) == normalize(b)
+}
+
+func normalize(serverURL string) string {
+	if _, rest, ok := strings.Cut(serverURL, "://"); ok {
+		serverURL = rest
+	}
+	host, path, _ := strings.Cut(strings.TrimRight(serverURL, "/"), "/")
+	if path != "" {
+		return strings.ToLower(host) + "/" + path
+	}
+	return strings.ToLower(host)
+}
@@ -0,0 +1,72 @@
+package dockercredential
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestReadServerURL тестирует разбор запроса операций get и erase
+func TestReadServerURL(t *testing.T) {
+	serverURL, err := ReadServerURL(strings.NewReader("https://index.docker.io/v1/\n"))
+	if err != nil || serverURL != "https://index.docker.io/v1/" {
+		t.Errorf("Адрес разобран неверно: %q, %v", serverURL, err)
+	}
+	if _, err := ReadServerURL(strings.NewReader(" \n")); err != ErrMissingServerURL {
+		t.Errorf("Ожидалась ошибка %v, получено %v", ErrMissingServerURL, err)
+	}
+}
+
+// TestReadCredentials тестирует разбор запроса операции store
+func TestReadCredentials(t *testing.T) {
+	credentials, err := ReadCredentials(strings.NewReader(`{"ServerURL":"ghcr.io","Username":"alice","Secret":"token"}`))
+	if err != nil || *credentials != (Credentials{ServerURL: "ghcr.io", Username: "alice", Secret: "token"}) {
+		t.Errorf("Учетные данные разобраны неверно: %+v, %v", credentials, err)
+	}
+
+	tests := []struct {
+		input string
+		want  error
+	}{
+		{`{"Username":"alice","Secret":"token"}`, ErrMissingServerURL},
+		{`{"ServerURL":"ghcr.io","Secret":"token"}`, ErrMissingUsername},
+	}
+	for _, tt := range tests {
+		if _, err := ReadCredentials(strings.NewReader(tt.input)); err != tt.want {
+			t.Errorf("Для запроса %s ожидалась ошибка %v, получено %v", tt.input, tt.want, err)
+		}
+	}
+	if _, err := ReadCredentials(strings.NewReader("ghcr.io")); err == nil {
+		t.Error("Ожидалась ошибка для запроса не в JSON")
+	}
+}
+
+// TestSameRegistry тестирует сравнение адресов реестров в разной форме
+func TestSameRegistry(t *testing.T) {
+	tests := []struct {
+		a    string
+		b    string
+		want bool
+	}{
+		{"https://Registry.Example.com/", "registry.example.com", true},
+		{"https://index.docker.io/v1/", "index.docker.io/v1", true},
+		{"registry.example.com:5000", "registry.example.com", false},
+		{"ghcr.io", "registry.example.com", false},
+	}
+	for _, tt := range tests {
+		if got := SameRegistry(tt.a, tt.b); got != tt.want {
+			t.Errorf("SameRegistry(%q, %q) = %v, ожидалось %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+// TestServerURL тестирует получение адреса реестра по метке
+func TestServerURL(t *testing.T) {
+	if serverURL, ok := ServerURL(Label("ghcr.io")); !ok || serverURL != "ghcr.io" {
+		t.Errorf("Адрес получен неверно: %q, %v", serverURL, ok)
+	}
+	for _, label := range []string{"docker:", "git:https://ghcr.io", "ghcr.io"} {
+		if _, ok := ServerURL(label); ok {
+			t.Errorf("Метка %q не должна относиться к учетным данным реестров", label)
+		}
+	}
+}
@@ -1,9 +1,45 @@
 package domain
 
-// CredentialData представляет собой структуру для хранения пары логин/пароль
+import "time"
+
+// CredentialData представляет собой структуру для хранения пары логин/пароль.
+// TOTP - необязательный ключ второго фактора в формате URI otpauth://totp/...,
+// URIs - адреса, для которых подходят учетные данные, Fields - дополнительные поля.
+// History - прежние пароли, начиная с последнего; ее ведет сервер при смене пароля
 type CredentialData struct {
-	Login    string `json:"login"`
-	Password string `json:"password"`
+	Login    string           `json:"login"`
+	Password string           `json:"password"`
+	TOTP     string           `json:"totp,omitempty"`
+	URIs     []CredentialURI  `json:"uris,omitempty"`
+	Fields   []CustomField    `json:"fields,omitempty"`
+	History  []PasswordChange `json:"password_history,omitempty"`
+}
+
+// PasswordChange - прежний пароль учетных данных и время, когда его сменили
+type PasswordChange struct {
+	Password  string    `json:"password"`
+	ChangedAt time.Time `json:"changed_at"`
+}
+
+// CredentialURI - адрес учетных данных и правило, по которому с ним сравнивается адрес страницы.
+// Пустое правило означает сравнение по домену, правила описаны в пакете urimatch
+type CredentialURI struct {
+	URI   string `json:"uri"`
+	Match string `json:"match,omitempty"`
+}
+
+// Виды дополнительных полей учетных данных
+const (
+	CustomFieldText    = "text"    // Произвольный текст
+	CustomFieldHidden  = "hidden"  // Секрет, скрывается так же, как пароль
+	CustomFieldBoolean = "boolean" // Флаг со значением true или false
+)
+
+// CustomField - именованное дополнительное поле учетных данных, например контрольный вопрос или номер договора
+type CustomField struct {
+	Name  string `json:"name"`
+	Type  string `json:"type"`
+	Value string `json:"value"`
 }
 
 // CardData представляет собой структуру для хранения данных кредитной карты
@@ -19,13 +55,57 @@ type TextData struct {
 	Content string `json:"content"`
 }
 
+// SSHKeyData представляет собой структуру для хранения ключа SSH. PublicKey хранится в формате authorized_keys,
+// Fingerprint - отпечаток SHA256 открытого ключа
+type SSHKeyData struct {
+	PrivateKey  string `json:"private_key"`
+	PublicKey   string `json:"public_key"`
+	Fingerprint string `json:"fingerprint"`
+	Comment     string `json:"comment"`
+}
+
+// ItemData представляет собой содержимое записи структурированного типа: значения полей по их именам.
+// Набор полей и их проверка задаются описанием типа в пакете itemtype
+type ItemData map[string]string
+
+// ItemView задает представление записи в ответе сервера
+type ItemView struct {
+	// DataKey - ключ содержимого записи: data в API v2, ключ типа, например credential_data, в API v1
+	DataKey string
+	// Redact - заменить значения секретных полей маской
+	Redact bool
+	// Lists - выводить значения полей-списков JSON-значениями, а не текстом JSON, как ожидают клиенты API v1
+	Lists bool
+}
+
 type FileData struct {
-	Name      string `json:"name" binding:"required"`
-	Extension string `json:"extension" binding:"required"`
-	Metadata  string `json:"metadata"`
+	Name         string `json:"name" binding:"required"`
+	Extension    string `json:"extension" binding:"required"`
+	Size         int64  `json:"size" binding:"required"`
+	MimeType     string `json:"mime_type"`
+	OriginalName string `json:"original_name"`
+	Metadata     string `json:"metadata"`
+	// Encrypted выставляет сервер, клиент не может его задать
+	Encrypted bool `json:"-"`
 }
 
+// ProgressFunc получает число переданных байт файла и его полный размер, 0 если размер неизвестен
+type ProgressFunc func(done int64, total int64)
+
 type FileDataResponse struct {
-	Url         string `json:"url" binding:"required"`
-	Description string `json:"description" binding:"required"`
+	Url         string            `json:"url" binding:"required"`
+	FormData    map[string]string `json:"form_data" binding:"required"`
+	Description string            `json:"description" binding:"required"`
+}
+
+// DownloadLink представляет собой ссылку на скачивание файла и заголовки, которые нужно передать в запросе
+type DownloadLink struct {
+	URL     string            `json:"url"`
+	Headers map[string]string `json:"headers,omitempty"`
+}
+
+// FileRename представляет собой запрос на переименование файла
+type FileRename struct {
+	Label    string `json:"label" binding:"required"`
+	NewLabel string `json:"new_label" binding:"required"`
 }
@@ -4,6 +4,10 @@ import "errors"
 
 var ErrNotFound = errors.New("not found")
 var ErrInsufficientFunds = errors.New("insufficient funds")
+var ErrAlreadyExists = errors.New("already exists")
+
+// ErrNotLoggedIn возвращается клиентом, когда сохраненный токен авторизации отсутствует
+var ErrNotLoggedIn = errors.New("требуется авторизация, выполните passcli login")
 
 type Error struct {
 	Message   string
@@ -0,0 +1,52 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+)
+
+var ErrFileTypeNotAllowed = errors.New("тип файла запрещен")
+
+// FileTypePolicy описывает разрешенные и запрещенные типы загружаемых файлов.
+// Шаблоны задаются как MIME-тип целиком ("application/pdf"), группа ("image/*") или "*".
+// Запрет имеет приоритет над разрешением, пустой список разрешений допускает любые типы
+type FileTypePolicy struct {
+	Allow []string `yaml:"allow"`
+	Deny  []string `yaml:"deny"`
+}
+
+// Allows проверяет, разрешена ли загрузка файла с типом mimeType
+func (p FileTypePolicy) Allows(mimeType string) bool {
+	if i := strings.Index(mimeType, ";"); i != -1 {
+		mimeType = mimeType[:i]
+	}
+	mimeType = strings.ToLower(strings.TrimSpace(mimeType))
+
+	for _, pattern := range p.Deny {
+		if matchMimeType(pattern, mimeType) {
+			return false
+		}
+	}
+
+	if len(p.Allow) == 0 {
+		return true
+	}
+	for _, pattern := range p.Allow {
+		if matchMimeType(pattern, mimeType) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchMimeType сравнивает тип содержимого с шаблоном политики
+func matchMimeType(pattern string, mimeType string) bool {
+	pattern = strings.ToLower(strings.TrimSpace(pattern))
+	if pattern == "*" || pattern == "*/*" {
+		return true
+	}
+	if strings.HasSuffix(pattern, "/*") {
+		return strings.HasPrefix(mimeType, strings.TrimSuffix(pattern, "*"))
+	}
+	return pattern == mimeType
+}
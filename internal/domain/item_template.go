@@ -0,0 +1,19 @@
+package domain
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// ItemTemplate представляет собой пользовательский тип записи, описанный подмножеством JSON Schema.
+// Имя шаблона служит типом его записей, схема разбирается itemtype.ParseSchema
+type ItemTemplate struct {
+	Name      string          `json:"name" db:"name"`
+	Schema    json.RawMessage `json:"schema" db:"schema" swaggertype:"object"`
+	CreatedAt time.Time       `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time       `json:"updated_at" db:"updated_at"`
+}
+
+// ErrTemplateInUse возвращается при удалении шаблона, по которому сохранены записи
+var ErrTemplateInUse = errors.New("по шаблону сохранены записи")
@@ -0,0 +1,26 @@
+package domain
+
+import "errors"
+
+// ErrProfileNotFound возвращается клиентом, когда выбранный профиль отсутствует в конфигурации
+var ErrProfileNotFound = errors.New("профиль не найден")
+
+// Profile - именованный профиль подключения passcli к серверу.
+// Токен авторизации хранится отдельно от файла конфигурации, у каждого профиля свой
+type Profile struct {
+	Name   string     `json:"name" yaml:"-"`
+	Server string     `json:"server" yaml:"server"`
+	TLS    ProfileTLS `json:"tls" yaml:"tls,omitempty"`
+	// Output - формат вывода по умолчанию: table, json, yaml или env
+	Output string `json:"output" yaml:"output,omitempty"`
+	// Current отмечает профиль, выбранный командой passcli profile use
+	Current bool `json:"current" yaml:"-"`
+}
+
+// ProfileTLS - настройки TLS соединения с сервером
+type ProfileTLS struct {
+	// CAFile - PEM-файл с сертификатами удостоверяющих центров в дополнение к системным
+	CAFile string `json:"ca_file" yaml:"ca_file,omitempty"`
+	// InsecureSkipVerify отключает проверку сертификата сервера, только для тестовых стендов
+	InsecureSkipVerify bool `json:"insecure_skip_verify" yaml:"insecure_skip_verify,omitempty"`
+}
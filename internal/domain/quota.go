@@ -0,0 +1,56 @@
+package domain
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// ErrQuotaExceeded возвращается, когда операция превышает квоту пользователя
+var ErrQuotaExceeded = errors.New("превышена квота")
+
+// Quota описывает ограничения хранилища пользователя.
+// Нулевое значение поля означает отсутствие ограничения.
+type Quota struct {
+	MaxTotalBytes int64 `json:"max_total_bytes" yaml:"max_total_bytes"`
+	MaxItems      int64 `json:"max_items" yaml:"max_items"`
+	MaxFileSize   int64 `json:"max_file_size" yaml:"max_file_size"`
+}
+
+// Usage описывает текущее потребление хранилища пользователем
+type Usage struct {
+	TotalBytes int64 `json:"total_bytes"`
+	Items      int64 `json:"items"`
+	Files      int64 `json:"files"`
+	Quota      Quota `json:"quota"`
+}
+
+// CheckSave проверяет, что сохранение записи при потреблении usage не превысит квоту. size - размер сохраняемого
+// файла, для остальных записей 0. existing - перезаписываемая запись с той же меткой или nil: перезапись
+// не увеличивает количество записей, а размер перезаписываемого файла освобождается.
+// Возвращает ошибку, обернутую в ErrQuotaExceeded, если квота будет превышена
+func (q Quota) CheckSave(usage *Usage, existing *UserData, size int64) error {
+	if q.MaxFileSize > 0 && size > q.MaxFileSize {
+		return fmt.Errorf("%w: размер файла %d байт больше допустимого %d байт", ErrQuotaExceeded, size, q.MaxFileSize)
+	}
+
+	// Обновление существующей записи допустимо даже при исчерпанной квоте на количество записей
+	if existing == nil && q.MaxItems > 0 && usage.Items+1 > q.MaxItems {
+		return fmt.Errorf("%w: достигнуто максимальное количество записей %d", ErrQuotaExceeded, q.MaxItems)
+	}
+
+	if size == 0 || q.MaxTotalBytes == 0 {
+		return nil
+	}
+	totalBytes := usage.TotalBytes + size
+	if existing != nil && existing.Type == UserDataTypeFile {
+		var fileMetadata FileMetadata
+		if err := json.Unmarshal(existing.Data, &fileMetadata); err == nil {
+			totalBytes -= fileMetadata.Size
+		}
+	}
+	if totalBytes > q.MaxTotalBytes {
+		return fmt.Errorf("%w: суммарный размер файлов превысит %d байт", ErrQuotaExceeded, q.MaxTotalBytes)
+	}
+	return nil
+}
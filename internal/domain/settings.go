@@ -0,0 +1,20 @@
+package domain
+
+import "errors"
+
+// Ограничения истории паролей учетных данных
+const (
+	// DefaultPasswordHistory - количество прежних паролей, которое хранится, если сервер не настроен иначе
+	DefaultPasswordHistory = 10
+	// MaxPasswordHistory - наибольшее допустимое количество хранимых прежних паролей
+	MaxPasswordHistory = 100
+)
+
+// ErrInvalidSettings возвращается при недопустимых значениях настроек аккаунта
+var ErrInvalidSettings = errors.New("неверные настройки")
+
+// UserSettings описывает настройки аккаунта, которые пользователь меняет сам.
+// PasswordHistory - сколько прежних паролей хранить в учетных данных, 0 отключает историю
+type UserSettings struct {
+	PasswordHistory int `json:"password_history" yaml:"password_history"`
+}
@@ -10,7 +10,15 @@ const (
 	UserDataTypeCredential = "credential" // Учетные данные (логин/пароль)
 	UserDataTypeCard       = "card"       // Данные банковской карты
 	UserDataTypeText       = "text"       // Произвольный текст
+	UserDataTypeSSHKey     = "ssh_key"    // Ключ SSH
 	UserDataTypeFile       = "file"       // Файл
+
+	// Структурированные типы, поля которых описаны в пакете itemtype
+	UserDataTypeIdentity    = "identity"     // Документ, удостоверяющий личность
+	UserDataTypeBankAccount = "bank_account" // Банковский счет
+	UserDataTypeAPIToken    = "api_token"    // Токен API со сроком действия
+	UserDataTypeWiFi        = "wifi"         // Сеть Wi-Fi
+	UserDataTypeLicense     = "license"      // Лицензия на программное обеспечение
 )
 
 // UserData представляет собой структуру для хранения данных пользователя
@@ -27,6 +35,35 @@ type UserData struct {
 
 // FileMetadata представляет собой структуру для хранения метаданных файла
 type FileMetadata struct {
-	FileName  string `json:"file_name"`
-	Extension string `json:"extension"`
+	FileName     string `json:"file_name"`
+	Extension    string `json:"extension"`
+	Size         int64  `json:"size"`
+	MimeType     string `json:"mime_type"`
+	OriginalName string `json:"original_name"`
+	// Encrypted - объект зашифрован в хранилище ключом пользователя (SSE-C)
+	Encrypted bool `json:"encrypted"`
+}
+
+// ItemInfo представляет собой сведения о записи пользователя любого типа без ее содержимого.
+// URIs - адреса учетных данных, по ним клиент подбирает запись к адресу, не запрашивая каждую запись
+type ItemInfo struct {
+	Type      string          `json:"type"`
+	Label     string          `json:"label"`
+	Metadata  string          `json:"metadata"`
+	URIs      []CredentialURI `json:"uris,omitempty"`
+	CreatedAt time.Time       `json:"created_at"`
+	UpdatedAt time.Time       `json:"updated_at"`
+}
+
+// FileInfo представляет собой сведения о файле пользователя
+type FileInfo struct {
+	Label        string    `json:"label"`
+	Extension    string    `json:"extension"`
+	Size         int64     `json:"size"`
+	MimeType     string    `json:"mime_type"`
+	OriginalName string    `json:"original_name"`
+	Encrypted    bool      `json:"encrypted"`
+	Metadata     string    `json:"metadata"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
 }
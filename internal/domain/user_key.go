@@ -0,0 +1,16 @@
+package domain
+
+import "errors"
+
+// ErrUnknownMasterKey возвращается, когда ключ пользователя обернут мастер-ключом, которого нет в конфигурации
+var ErrUnknownMasterKey = errors.New("неизвестный мастер-ключ")
+
+// ErrEncryptionDisabled возвращается при обращении к зашифрованному объекту, когда шифрование на сервере отключено
+var ErrEncryptionDisabled = errors.New("шифрование объектов отключено")
+
+// UserKey представляет собой ключ данных пользователя, обернутый мастер-ключом сервера
+type UserKey struct {
+	UserID      string `db:"user_id"`
+	WrappedKey  []byte `db:"wrapped_key"`
+	MasterKeyID string `db:"master_key_id"`
+}
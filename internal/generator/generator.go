@@ -0,0 +1,258 @@
+package generator
+
+import (
+	"crypto/rand"
+	_ "embed"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
+	"unicode"
+)
+
+// Наборы символов классов пароля
+const (
+	Lower   = "abcdefghijklmnopqrstuvwxyz"
+	Upper   = "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+	Digits  = "0123456789"
+	Symbols = "!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~"
+)
+
+// Ambiguous - символы, которые легко спутать друг с другом при чтении и ручном вводе
+const Ambiguous = "0OoIl1|`'\""
+
+// Ограничения длины пароля и количества слов парольной фразы
+const (
+	MaxLength = 1024
+	MaxWords  = 64
+)
+
+// wordlistData - большой список слов EFF для diceware, 7776 слов по пять бросков кубика:
+// https://www.eff.org/deeplinks/2016/07/new-wordlists-random-passphrases (CC BY 3.0)
+//
+//go:embed eff_large_wordlist.txt
+var wordlistData string
+
+// wordlist - слова списка EFF в порядке бросков кубика
+var wordlist = parseWordlist(wordlistData)
+
+// Policy - требования к паролю. Класс символов участвует в пароле, если он включен,
+// Min* задают минимальное количество символов класса
+type Policy struct {
+	Length           int
+	Lower            bool
+	Upper            bool
+	Digits           bool
+	Symbols          bool
+	MinLower         int
+	MinUpper         int
+	MinDigits        int
+	MinSymbols       int
+	ExcludeAmbiguous bool
+	// Exclude - дополнительные символы, которые не должны встречаться в пароле
+	Exclude string
+}
+
+// PassphrasePolicy - требования к парольной фразе из слов списка EFF
+type PassphrasePolicy struct {
+	Words      int
+	Separator  string
+	Capitalize bool
+	// Digit добавляет случайную цифру к случайному слову
+	Digit bool
+}
+
+// Result - сгенерированное значение и оценка его энтропии в битах
+type Result struct {
+	Value   string
+	Entropy float64
+}
+
+// Strength возвращает словесную оценку стойкости по энтропии
+func (r Result) Strength() string {
+	switch {
+	case r.Entropy < 40:
+		return "слабый"
+	case r.Entropy < 60:
+		return "средний"
+	case r.Entropy < 80:
+		return "хороший"
+	default:
+		return "сильный"
+	}
+}
+
+// class - класс символов пароля с минимальным количеством
+type class struct {
+	name  string
+	chars []rune
+	min   int
+}
+
+// Password генерирует пароль по политике p криптографически стойким генератором.
+// Энтропия оценивается как длина, умноженная на log2 размера алфавита
+func Password(p Policy) (Result, error) {
+	if p.Length <= 0 || p.Length > MaxLength {
+		return Result{}, fmt.Errorf("длина пароля должна быть от 1 до %d", MaxLength)
+	}
+
+	exclude := p.Exclude
+	if p.ExcludeAmbiguous {
+		exclude += Ambiguous
+	}
+
+	all := []struct {
+		name    string
+		enabled bool
+		chars   string
+		min     int
+	}{
+		{"строчные буквы", p.Lower, Lower, p.MinLower},
+		{"заглавные буквы", p.Upper, Upper, p.MinUpper},
+		{"цифры", p.Digits, Digits, p.MinDigits},
+		{"символы", p.Symbols, Symbols, p.MinSymbols},
+	}
+
+	var classes []class
+	var alphabet []rune
+	required := 0
+	for _, c := range all {
+		if c.min < 0 {
+			return Result{}, fmt.Errorf("минимальное количество для класса '%s' не может быть отрицательным", c.name)
+		}
+		if !c.enabled {
+			if c.min > 0 {
+				return Result{}, fmt.Errorf("класс '%s' выключен, но для него задано минимальное количество", c.name)
+			}
+			continue
+		}
+
+		chars := without(c.chars, exclude)
+		if len(chars) == 0 {
+			return Result{}, fmt.Errorf("в классе '%s' не осталось символов после исключений", c.name)
+		}
+		classes = append(classes, class{name: c.name, chars: chars, min: c.min})
+		alphabet = append(alphabet, chars...)
+		required += c.min
+	}
+	if len(classes) == 0 {
+		return Result{}, errors.New("не выбран ни один класс символов")
+	}
+	if required > p.Length {
+		return Result{}, fmt.Errorf("сумма минимальных количеств (%d) больше длины пароля (%d)", required, p.Length)
+	}
+
+	password := make([]rune, 0, p.Length)
+	for _, c := range classes {
+		for i := 0; i < c.min; i++ {
+			r, err := pick(c.chars)
+			if err != nil {
+				return Result{}, err
+			}
+			password = append(password, r)
+		}
+	}
+	for len(password) < p.Length {
+		r, err := pick(alphabet)
+		if err != nil {
+			return Result{}, err
+		}
+		password = append(password, r)
+	}
+	if err := shuffle(password); err != nil {
+		return Result{}, err
+	}
+
+	return Result{Value: string(password), Entropy: float64(p.Length) * math.Log2(float64(len(alphabet)))}, nil
+}
+
+// Passphrase генерирует парольную фразу из случайных слов списка EFF. Энтропия каждого слова - log2(7776),
+// около 12.9 бит, случайная цифра добавляет выбор цифры и слова
+func Passphrase(p PassphrasePolicy) (Result, error) {
+	if p.Words <= 0 || p.Words > MaxWords {
+		return Result{}, fmt.Errorf("количество слов должно быть от 1 до %d", MaxWords)
+	}
+
+	words := make([]string, p.Words)
+	for i := range words {
+		index, err := randomInt(len(wordlist))
+		if err != nil {
+			return Result{}, err
+		}
+		words[i] = wordlist[index]
+		if p.Capitalize {
+			runes := []rune(words[i])
+			runes[0] = unicode.ToUpper(runes[0])
+			words[i] = string(runes)
+		}
+	}
+
+	entropy := float64(p.Words) * math.Log2(float64(len(wordlist)))
+	if p.Digit {
+		word, err := randomInt(len(words))
+		if err != nil {
+			return Result{}, err
+		}
+		digit, err := randomInt(len(Digits))
+		if err != nil {
+			return Result{}, err
+		}
+		words[word] += string(Digits[digit])
+		entropy += math.Log2(float64(len(words) * len(Digits)))
+	}
+
+	return Result{Value: strings.Join(words, p.Separator), Entropy: entropy}, nil
+}
+
+// parseWordlist разбирает список слов в формате "11111<Tab>слово"
+func parseWordlist(data string) []string {
+	var words []string
+	for _, line := range strings.Split(data, "\n") {
+		if _, word, ok := strings.Cut(strings.TrimSpace(line), "\t"); ok {
+			words = append(words, word)
+		}
+	}
+	return words
+}
+
+// without возвращает символы chars, не входящие в exclude
+func without(chars string, exclude string) []rune {
+	var result []rune
+	for _, r := range chars {
+		if !strings.ContainsRune(exclude, r) {
+			result = append(result, r)
+		}
+	}
+	return result
+}
+
+// pick возвращает случайный символ из chars
+func pick(chars []rune) (rune, error) {
+	index, err := randomInt(len(chars))
+	if err != nil {
+		return 0, err
+	}
+	return chars[index], nil
+}
+
+// shuffle перемешивает символы алгоритмом Фишера-Йетса, чтобы обязательные символы не стояли в начале
+func shuffle(chars []rune) error {
+	for i := len(chars) - 1; i > 0; i-- {
+		j, err := randomInt(i + 1)
+		if err != nil {
+			return err
+		}
+		chars[i], chars[j] = chars[j], chars[i]
+	}
+	return nil
+}
+
+// randomInt возвращает равномерно распределенное случайное число от 0 до n-1
+func randomInt(n int) (int, error) {
+	value, err := rand.Int(rand.Reader, big.NewInt(int64(n)))
+	if err != nil {
+		return 0, fmt.Errorf("ошибка генератора случайных чисел: %w", err)
+	}
+	return int(value.Int64()), nil
+}
@@ -0,0 +1,130 @@
+package generator
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+// count возвращает количество символов value из набора chars
+func count(value string, chars string) int {
+	n := 0
+	for _, r := range value {
+		if strings.ContainsRune(chars, r) {
+			n++
+		}
+	}
+	return n
+}
+
+// TestWordlist тестирует встроенный список слов EFF
+func TestWordlist(t *testing.T) {
+	if len(wordlist) != 7776 {
+		t.Fatalf("Ожидалось 7776 слов, получено %d", len(wordlist))
+	}
+	unique := make(map[string]bool, len(wordlist))
+	for _, word := range wordlist {
+		if word == "" || unique[word] {
+			t.Fatalf("Пустое или повторяющееся слово '%s'", word)
+		}
+		unique[word] = true
+	}
+}
+
+// TestPassword тестирует длину, классы символов, минимальные количества и исключения
+func TestPassword(t *testing.T) {
+	policy := Policy{
+		Length:           16,
+		Lower:            true,
+		Upper:            true,
+		Digits:           true,
+		Symbols:          false,
+		MinUpper:         3,
+		MinDigits:        5,
+		ExcludeAmbiguous: true,
+		Exclude:          "xyz",
+	}
+
+	for i := 0; i < 200; i++ {
+		result, err := Password(policy)
+		if err != nil {
+			t.Fatalf("Неожиданная ошибка: %v", err)
+		}
+		value := result.Value
+		if len([]rune(value)) != 16 {
+			t.Fatalf("Ожидалась длина 16, получен пароль '%s'", value)
+		}
+		if count(value, Upper) < 3 || count(value, Digits) < 5 {
+			t.Fatalf("Не выполнены минимальные количества: '%s'", value)
+		}
+		if count(value, Symbols) != 0 || count(value, Ambiguous+"xyz") != 0 {
+			t.Fatalf("Пароль содержит исключенные символы: '%s'", value)
+		}
+	}
+
+	// 26+26+10 символов без 0, O, o, I, l, 1, x, y, z
+	result, _ := Password(policy)
+	if want := 16 * math.Log2(53); math.Abs(result.Entropy-want) > 1e-9 {
+		t.Errorf("Ожидалась энтропия %.2f, получена %.2f", want, result.Entropy)
+	}
+}
+
+// TestPassword_Errors тестирует отказ от невыполнимых политик
+func TestPassword_Errors(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy Policy
+	}{
+		{name: "Zero length", policy: Policy{Length: 0, Lower: true}},
+		{name: "Too long", policy: Policy{Length: MaxLength + 1, Lower: true}},
+		{name: "No classes", policy: Policy{Length: 10}},
+		{name: "Minimum of disabled class", policy: Policy{Length: 10, Lower: true, MinDigits: 1}},
+		{name: "Minimums exceed length", policy: Policy{Length: 3, Lower: true, Upper: true, MinLower: 2, MinUpper: 2}},
+		{name: "Negative minimum", policy: Policy{Length: 3, Lower: true, MinLower: -1}},
+		{name: "Empty class", policy: Policy{Length: 10, Digits: true, Exclude: "23456789", ExcludeAmbiguous: true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Password(tt.policy); err == nil {
+				t.Error("Ожидалась ошибка")
+			}
+		})
+	}
+}
+
+// TestPassphrase тестирует парольную фразу из слов списка и оценку ее энтропии
+func TestPassphrase(t *testing.T) {
+	result, err := Passphrase(PassphrasePolicy{Words: 5, Separator: " "})
+	if err != nil {
+		t.Fatalf("Неожиданная ошибка: %v", err)
+	}
+	words := strings.Split(result.Value, " ")
+	if len(words) != 5 {
+		t.Fatalf("Ожидалось 5 слов, получено '%s'", result.Value)
+	}
+	for _, word := range words {
+		found := false
+		for _, listed := range wordlist {
+			found = found || listed == word
+		}
+		if !found {
+			t.Errorf("Слово '%s' не из списка EFF", word)
+		}
+	}
+	if math.Abs(result.Entropy-5*math.Log2(7776)) > 1e-9 || result.Strength() != "хороший" {
+		t.Errorf("Неверная оценка энтропии: %.2f (%s)", result.Entropy, result.Strength())
+	}
+
+	result, err = Passphrase(PassphrasePolicy{Words: 3, Separator: "-", Capitalize: true, Digit: true})
+	if err != nil {
+		t.Fatalf("Неожиданная ошибка: %v", err)
+	}
+	if count(result.Value, Digits) != 1 || count(result.Value, Upper) < 3 {
+		t.Errorf("Ожидались заглавные буквы и одна цифра: '%s'", result.Value)
+	}
+
+	if _, err := Passphrase(PassphrasePolicy{Words: 0}); err == nil {
+		t.Error("Ожидалась ошибка для пустой парольной фразы")
+	}
+}
@@ -0,0 +1,161 @@
+// Package gitcredential реализует протокол помощников учетных данных git (git-credential(1)):
+// разбор запроса из stdin, вывод ответа и сопоставление запроса с метками и адресами записей
+package gitcredential
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+)
+
+// LabelPrefix - префикс меток записей, созданных помощником
+const LabelPrefix = "git:"
+
+// maxRequestSize ограничивает размер запроса git
+const maxRequestSize = 64 * 1024
+
+// Credential - атрибуты запроса git. Path передается, только если включен credential.useHttpPath
+type Credential struct {
+	Protocol string
+	Host     string
+	Path     string
+	Username string
+	Password string
+}
+
+// Read читает атрибуты key=value до пустой строки или конца ввода. Неизвестные атрибуты пропускаются,
+// атрибут url раскладывается на протокол, хост, путь и имя пользователя
+func Read(r io.Reader) (*Credential, error) {
+	credential := &Credential{}
+	scanner := bufio.NewScanner(io.LimitReader(r, maxRequestSize))
+	for scanner.Scan() {
+		line := strings.TrimSuffix(scanner.Text(), "\r")
+		if line == "" {
+			break
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			return nil, fmt.Errorf("неверная строка запроса git '%s'", line)
+		}
+
+		switch key {
+		case "protocol":
+			credential.Protocol = value
+		case "host":
+			credential.Host = value
+		case "path":
+			credential.Path = strings.Trim(value, "/")
+		case "username":
+			credential.Username = value
+		case "password":
+			credential.Password = value
+		case "url":
+			if err := credential.setURL(value); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if credential.Protocol == "" || credential.Host == "" {
+		return nil, errors.New("в запросе git нет протокола или хоста")
+	}
+	return credential, nil
+}
+
+// setURL заполняет атрибуты из адреса, явно переданные атрибуты git передает после url и они имеют приоритет
+func (c *Credential) setURL(value string) error {
+	parsed, err := url.Parse(value)
+	if err != nil {
+		return fmt.Errorf("неверный адрес в запросе git: %w", err)
+	}
+	c.Protocol, c.Host, c.Path = parsed.Scheme, parsed.Host, strings.Trim(parsed.Path, "/")
+	if parsed.User != nil {
+		c.Username = parsed.User.Username()
+	}
+	return nil
+}
+
+// Write выводит имя пользователя и пароль в формате ответа на запрос get. Пустое имя пользователя не выводится,
+// тогда git использует имя из адреса или запрашивает его
+func (c *Credential) Write(w io.Writer) error {
+	for _, value := range []string{c.Username, c.Password} {
+		if strings.ContainsAny(value, "\n\x00") {
+			return errors.New("значение содержит перевод строки или нулевой байт и не может быть передано git")
+		}
+	}
+	if c.Username != "" {
+		if _, err := fmt.Fprintf(w, "username=%s\n", c.Username); err != nil {
+			return err
+		}
+	}
+	_, err := fmt.Fprintf(w, "password=%s\n", c.Password)
+	return err
+}
+
+// URL возвращает адрес запроса без имени пользователя
+func (c *Credential) URL() string {
+	address := c.Protocol + "://" + c.Host
+	if c.Path != "" {
+		address += "/" + c.Path
+	}
+	return address
+}
+
+// Label возвращает метку новой записи для запроса store. Если метка без имени пользователя занята
+// учетными данными другого пользователя, используется метка с именем пользователя
+func (c *Credential) Label(withUsername bool) string {
+	if withUsername && c.Username != "" {
+		return LabelPrefix + c.Protocol + "://" + url.PathEscape(c.Username) + "@" + strings.TrimPrefix(c.URL(), c.Protocol+"://")
+	}
+	return LabelPrefix + c.URL()
+}
+
+// Labels возвращает метки, под которыми ищутся учетные данные, в порядке приоритета:
+// метки помощника с путем и без него, затем метка, равная хосту. Метка, равная хосту, считается адресом https
+// и для других протоколов не используется, чтобы токен не передавался по открытому каналу
+func (c *Credential) Labels() []string {
+	if !strings.EqualFold(c.Protocol, "https") {
+		return c.HelperLabels()
+	}
+	return append(c.HelperLabels(), c.Host)
+}
+
+// HelperLabels возвращает метки записей, которые помощник мог создать для запроса: с путем и без него,
+// с именем пользователя и без него
+func (c *Credential) HelperLabels() []string {
+	var labels []string
+	scoped := []*Credential{c}
+	if c.Path != "" {
+		scoped = append(scoped, &Credential{Protocol: c.Protocol, Host: c.Host, Username: c.Username})
+	}
+	for _, s := range scoped {
+		if c.Username != "" {
+			labels = append(labels, s.Label(true))
+		}
+		labels = append(labels, s.Label(false))
+	}
+	return labels
+}
+
+// MatchesMetadata сообщает, указан ли в метаинформации записи адрес запроса: строка "URL: <адрес>"
+// с тем же протоколом и хостом. Путь адреса не учитывается: в импортированных записях это обычно
+// страница входа, а не репозиторий
+func (c *Credential) MatchesMetadata(metadata string) bool {
+	for _, line := range strings.Split(metadata, "\n") {
+		key, value, ok := strings.Cut(line, ":")
+		if !ok || !strings.EqualFold(strings.TrimSpace(key), "url") {
+			continue
+		}
+		parsed, err := url.Parse(strings.TrimSpace(value))
+		if err == nil && strings.EqualFold(parsed.Scheme, c.Protocol) && strings.EqualFold(parsed.Host, c.Host) {
+			return true
+		}
+	}
+	return false
+}
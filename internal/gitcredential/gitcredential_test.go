@@ -0,0 +1,91 @@
+package gitcredential
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestRead тестирует разбор запроса git
+func TestRead(t *testing.T) {
+	input := "capability[]=authtype\nprotocol=https\nhost=git.example.com:8443\npath=team/repo.git\n" +
+		"username=alice\nwwwauth[]=Basic realm=\"git\"\n\nprotocol=ignored\n"
+	credential, err := Read(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("Неожиданная ошибка: %v", err)
+	}
+	want := &Credential{Protocol: "https", Host: "git.example.com:8443", Path: "team/repo.git", Username: "alice"}
+	if !reflect.DeepEqual(credential, want) {
+		t.Errorf("Ожидалось %+v, получено %+v", want, credential)
+	}
+
+	credential, err = Read(strings.NewReader("url=https://bob@github.com/org/repo\n"))
+	if err != nil || credential.URL() != "https://github.com/org/repo" || credential.Username != "bob" {
+		t.Errorf("Адрес разобран неверно: %+v, %v", credential, err)
+	}
+
+	for _, input := range []string{"host=github.com\n", "protocol https\n"} {
+		if _, err := Read(strings.NewReader(input)); err == nil {
+			t.Errorf("Ожидалась ошибка для запроса %q", input)
+		}
+	}
+}
+
+// TestCredential_Labels тестирует порядок меток для поиска учетных данных
+func TestCredential_Labels(t *testing.T) {
+	credential := &Credential{Protocol: "https", Host: "github.com", Path: "org/repo.git", Username: "alice"}
+	want := []string{
+		"git:https://alice@github.com/org/repo.git",
+		"git:https://github.com/org/repo.git",
+		"git:https://alice@github.com",
+		"git:https://github.com",
+		"github.com",
+	}
+	if got := credential.Labels(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Ожидалось %v, получено %v", want, got)
+	}
+
+	credential = &Credential{Protocol: "http", Host: "github.com"}
+	if got, want := credential.Labels(), []string{"git:http://github.com"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Для http метка, равная хосту, не должна использоваться: ожидалось %v, получено %v", want, got)
+	}
+}
+
+// TestCredential_MatchesMetadata тестирует сопоставление протокола и хоста запроса со строкой URL метаинформации
+func TestCredential_MatchesMetadata(t *testing.T) {
+	tests := []struct {
+		path     string
+		metadata string
+		want     bool
+	}{
+		{"", "Папка: Работа\nURL: https://github.com/login", true},
+		{"org/repo.git", "url: https://github.com/org", true},
+		{"org/repo.git", "URL: https://github.com/other", true},
+		{"", "URL: http://github.com", false},
+		{"", "URL: github.com", false},
+		{"", "URL: https://gitlab.com", false},
+		{"", "адрес https://github.com", false},
+	}
+	for _, tt := range tests {
+		credential := &Credential{Protocol: "https", Host: "github.com", Path: tt.path}
+		if got := credential.MatchesMetadata(tt.metadata); got != tt.want {
+			t.Errorf("MatchesMetadata(%q) для пути %q = %v, ожидалось %v", tt.metadata, tt.path, got, tt.want)
+		}
+	}
+}
+
+// TestCredential_Write тестирует формат ответа и отказ от значений с переводом строки
+func TestCredential_Write(t *testing.T) {
+	var buf bytes.Buffer
+	if err := (&Credential{Username: "alice", Password: "s3cret"}).Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != "username=alice\npassword=s3cret\n" {
+		t.Errorf("Неверный ответ: %q", buf.String())
+	}
+
+	if err := (&Credential{Password: "s3cret\nhost=evil"}).Write(&buf); err == nil {
+		t.Error("Ожидалась ошибка для пароля с переводом строки")
+	}
+}
@@ -0,0 +1,180 @@
+package importer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/SmirnovND/gophkeeper/internal/domain"
+	"github.com/SmirnovND/gophkeeper/internal/urimatch"
+	"strings"
+)
+
+// Типы записей Bitwarden
+const (
+	bitwardenLogin    = 1
+	bitwardenNote     = 2
+	bitwardenCard     = 3
+	bitwardenIdentity = 4
+)
+
+// Типы пользовательских полей Bitwarden
+const (
+	bitwardenFieldText    = 0
+	bitwardenFieldHidden  = 1
+	bitwardenFieldBoolean = 2
+)
+
+// bitwardenExport - незашифрованный JSON-экспорт Bitwarden
+type bitwardenExport struct {
+	Encrypted bool `json:"encrypted"`
+	Folders   []struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	} `json:"folders"`
+	Items []bitwardenItem `json:"items"`
+}
+
+type bitwardenItem struct {
+	Type     int     `json:"type"`
+	Name     string  `json:"name"`
+	Notes    string  `json:"notes"`
+	FolderID *string `json:"folderId"`
+	Fields   []struct {
+		Name  string `json:"name"`
+		Value string `json:"value"`
+		Type  int    `json:"type"`
+	} `json:"fields"`
+	Login *struct {
+		Username string `json:"username"`
+		Password string `json:"password"`
+		Totp     string `json:"totp"`
+		URIs     []struct {
+			URI   string `json:"uri"`
+			Match *int   `json:"match"`
+		} `json:"uris"`
+	} `json:"login"`
+	Card *struct {
+		CardholderName string `json:"cardholderName"`
+		Brand          string `json:"brand"`
+		Number         string `json:"number"`
+		ExpMonth       string `json:"expMonth"`
+		ExpYear        string `json:"expYear"`
+		Code           string `json:"code"`
+	} `json:"card"`
+	Identity map[string]*string `json:"identity"`
+}
+
+// bitwardenMatchRules - правила сравнения адресов Bitwarden по их номерам, null означает правило по умолчанию
+var bitwardenMatchRules = []string{urimatch.RuleDomain, urimatch.RuleHost, urimatch.RuleStartsWith,
+	urimatch.RuleExact, urimatch.RuleRegex, urimatch.RuleNever}
+
+// bitwardenIdentityFields - ключи полей личных данных Bitwarden в порядке вывода и их названия
+var bitwardenIdentityFields = []field{
+	{"title", "Обращение"}, {"firstName", "Имя"}, {"middleName", "Отчество"}, {"lastName", "Фамилия"},
+	{"company", "Компания"}, {"email", "Email"}, {"phone", "Телефон"},
+	{"address1", "Адрес"}, {"address2", "Адрес"}, {"address3", "Адрес"},
+	{"city", "Город"}, {"state", "Регион"}, {"postalCode", "Индекс"}, {"country", "Страна"},
+	{"username", "Имя пользователя"}, {"ssn", "SSN"}, {"passportNumber", "Паспорт"}, {"licenseNumber", "Водительское удостоверение"},
+}
+
+// parseBitwarden разбирает JSON-экспорт Bitwarden: логины, заметки, карты и личные данные
+func parseBitwarden(data []byte, result *Result) error {
+	var export bitwardenExport
+	if err := json.Unmarshal(data, &export); err != nil {
+		return fmt.Errorf("неверный JSON-экспорт Bitwarden: %w", err)
+	}
+	if export.Encrypted {
+		return errors.New("зашифрованный экспорт Bitwarden не поддерживается, выполните экспорт в формате JSON без шифрования")
+	}
+
+	folders := make(map[string]string, len(export.Folders))
+	for _, folder := range export.Folders {
+		folders[folder.ID] = folder.Name
+	}
+
+	for _, item := range export.Items {
+		folder := ""
+		if item.FolderID != nil {
+			folder = folders[*item.FolderID]
+		}
+		src := source(folder, item.Name)
+
+		// Пользовательские поля логинов переносятся в дополнительные поля учетных данных. У остальных записей
+		// текстовые поля и флажки переносятся в метаинформацию, а скрытые поля являются секретами и не переносятся
+		fields := []field{{"Папка", folder}}
+		if item.Type != bitwardenLogin {
+			for _, f := range item.Fields {
+				switch f.Type {
+				case bitwardenFieldText, bitwardenFieldBoolean:
+					fields = append(fields, field{f.Name, f.Value})
+				case bitwardenFieldHidden:
+					result.warn("%s: скрытое поле '%s' не импортировано", src, f.Name)
+				}
+			}
+		}
+
+		switch item.Type {
+		case bitwardenLogin:
+			if item.Login == nil {
+				result.warn("%s: пустая запись пропущена", src)
+				continue
+			}
+			var uris []string
+			for _, uri := range item.Login.URIs {
+				uris = append(uris, uri.URI)
+			}
+			site := ""
+			if len(uris) > 0 {
+				site = uris[0]
+			}
+			meta := metadata(item.Notes, append([]field{{"URL", strings.Join(uris, " ")}}, fields...)...)
+			entry := credentialEntry(label(item.Name, site), item.Login.Username, item.Login.Password, meta, src)
+			result.addTOTP(&entry, item.Login.Totp)
+			for _, uri := range item.Login.URIs {
+				match := ""
+				if uri.Match != nil && *uri.Match >= 0 && *uri.Match < len(bitwardenMatchRules) {
+					match = bitwardenMatchRules[*uri.Match]
+				}
+				result.addURI(&entry, uri.URI, match)
+			}
+			for _, f := range item.Fields {
+				switch f.Type {
+				case bitwardenFieldText:
+					entry.Credential.Fields = append(entry.Credential.Fields, domain.CustomField{Name: f.Name, Type: domain.CustomFieldText, Value: f.Value})
+				case bitwardenFieldHidden:
+					entry.Credential.Fields = append(entry.Credential.Fields, domain.CustomField{Name: f.Name, Type: domain.CustomFieldHidden, Value: f.Value})
+				case bitwardenFieldBoolean:
+					entry.Credential.Fields = append(entry.Credential.Fields, domain.CustomField{Name: f.Name, Type: domain.CustomFieldBoolean, Value: f.Value})
+				}
+			}
+			result.Entries = append(result.Entries, entry)
+		case bitwardenNote:
+			result.Entries = append(result.Entries, textEntry(label(item.Name, ""), item.Notes, metadata("", fields...), src))
+		case bitwardenCard:
+			if item.Card == nil {
+				result.warn("%s: пустая запись пропущена", src)
+				continue
+			}
+			card := &domain.CardData{
+				Number:     item.Card.Number,
+				Holder:     item.Card.CardholderName,
+				ExpiryDate: expiryDate(item.Card.ExpMonth, item.Card.ExpYear),
+				CVV:        item.Card.Code,
+			}
+			meta := metadata(item.Notes, append([]field{{"Платежная система", item.Card.Brand}}, fields...)...)
+			result.Entries = append(result.Entries, cardEntry(label(item.Name, ""), card, meta, src))
+		case bitwardenIdentity:
+			var lines []string
+			for _, f := range bitwardenIdentityFields {
+				if value := item.Identity[f.name]; value != nil && strings.TrimSpace(*value) != "" {
+					lines = append(lines, f.value+": "+strings.TrimSpace(*value))
+				}
+			}
+			result.Entries = append(result.Entries,
+				textEntry(label(item.Name, ""), strings.Join(lines, "\n"), metadata(item.Notes, fields...), src))
+		default:
+			result.warn("%s: неизвестный тип записи Bitwarden %d пропущен", src, item.Type)
+		}
+	}
+	return nil
+}
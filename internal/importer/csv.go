@@ -0,0 +1,153 @@
+package importer
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"github.com/SmirnovND/gophkeeper/internal/domain"
+	"strings"
+	"time"
+)
+
+// Назначение колонок CSV-экспорта
+const (
+	columnName     = "name"
+	columnURL      = "url"
+	columnUsername = "username"
+	columnPassword = "password"
+	columnNotes    = "notes"
+	columnFolder   = "folder"
+	columnOTP      = "otp"
+	columnType     = "type"
+)
+
+// csvColumns сопоставляет заголовки колонок экспортов 1Password, LastPass, Chrome, Firefox и CSV-экспорта Bitwarden
+// с их назначением. Заголовки сравниваются без учета регистра
+var csvColumns = map[string]string{
+	"title":          columnName,
+	"name":           columnName,
+	"url":            columnURL,
+	"website":        columnURL,
+	"login_uri":      columnURL,
+	"username":       columnUsername,
+	"login_username": columnUsername,
+	"password":       columnPassword,
+	"login_password": columnPassword,
+	"notes":          columnNotes,
+	"note":           columnNotes,
+	"extra":          columnNotes,
+	"grouping":       columnFolder,
+	"folder":         columnFolder,
+	"tags":           columnFolder,
+	"otpauth":        columnOTP,
+	"totp":           columnOTP,
+	"login_totp":     columnOTP,
+	"type":           columnType,
+}
+
+// lastPassNoteURL - адрес, которым LastPass отмечает защищенные заметки
+const lastPassNoteURL = "http://sn"
+
+// parseCSV разбирает CSV-экспорт с заголовком. Записи с логином или паролем становятся учетными данными,
+// заметки - текстом, карты из заметок LastPass - банковскими картами
+func parseCSV(data []byte, result *Result) error {
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.FieldsPerRecord = -1
+	records, err := reader.ReadAll()
+	if err != nil {
+		return fmt.Errorf("неверный CSV-экспорт: %w", err)
+	}
+	if len(records) == 0 {
+		return nil
+	}
+
+	columns := make(map[string]int)
+	for i, header := range records[0] {
+		if purpose, ok := csvColumns[strings.ToLower(strings.TrimSpace(header))]; ok {
+			if _, exists := columns[purpose]; !exists {
+				columns[purpose] = i
+			}
+		}
+	}
+	_, hasPassword := columns[columnPassword]
+	_, hasNotes := columns[columnNotes]
+	if !hasPassword && !hasNotes {
+		return fmt.Errorf("в заголовке CSV нет колонок password или notes: %s", strings.Join(records[0], ","))
+	}
+
+	for n, record := range records[1:] {
+		value := func(purpose string) string {
+			if i, ok := columns[purpose]; ok && i < len(record) {
+				return record[i]
+			}
+			return ""
+		}
+
+		site, notes, folder := value(columnURL), value(columnNotes), value(columnFolder)
+		name := label(value(columnName), site)
+		if name == "" {
+			name = fmt.Sprintf("import-%d", n+1)
+		}
+		src := source(folder, name)
+
+		username, password := value(columnUsername), value(columnPassword)
+		isNote := site == lastPassNoteURL || strings.EqualFold(value(columnType), "note")
+		switch {
+		case isNote && strings.HasPrefix(notes, "NoteType:Credit Card"):
+			card, rest := lastPassCard(notes)
+			result.Entries = append(result.Entries, cardEntry(name, card, metadata(rest, field{"Папка", folder}), src))
+		case isNote:
+			result.Entries = append(result.Entries, textEntry(name, notes, metadata("", field{"Папка", folder}), src))
+		case username != "" || password != "":
+			meta := metadata(notes, field{"URL", site}, field{"Папка", folder})
+			entry := credentialEntry(name, username, password, meta, src)
+			result.addTOTP(&entry, value(columnOTP))
+			result.addURI(&entry, site, "")
+			result.Entries = append(result.Entries, entry)
+		case strings.TrimSpace(notes) != "":
+			result.Entries = append(result.Entries, textEntry(name, notes, metadata("", field{"URL", site}, field{"Папка", folder}), src))
+		default:
+			result.warn("%s: пустая запись пропущена", src)
+		}
+	}
+	return nil
+}
+
+// lastPassCard разбирает заметку LastPass типа Credit Card. Возвращает карту и поля, не относящиеся к ней
+func lastPassCard(note string) (*domain.CardData, string) {
+	card := &domain.CardData{}
+	var rest []string
+	for _, line := range strings.Split(note, "\n") {
+		key, value, _ := strings.Cut(line, ":")
+		value = strings.TrimSpace(value)
+		switch key {
+		case "NoteType", "Language":
+		case "Name on Card":
+			card.Holder = value
+		case "Number":
+			card.Number = value
+		case "Security Code":
+			card.CVV = value
+		case "Expiration Date":
+			card.ExpiryDate = lastPassExpiry(value)
+		default:
+			if value != "" && value != "," {
+				rest = append(rest, line)
+			}
+		}
+	}
+	return card, strings.Join(rest, "\n")
+}
+
+// lastPassExpiry приводит дату LastPass вида "January,2027" к виду MM/YY
+func lastPassExpiry(value string) string {
+	month, year, ok := strings.Cut(value, ",")
+	if !ok || month == "" {
+		return ""
+	}
+	parsed, err := time.Parse("January", strings.TrimSpace(month))
+	if err != nil {
+		return value
+	}
+	return expiryDate(fmt.Sprint(int(parsed.Month())), year)
+}
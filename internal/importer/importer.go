@@ -0,0 +1,219 @@
+package importer
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/SmirnovND/gophkeeper/internal/domain"
+	"github.com/SmirnovND/gophkeeper/internal/totp"
+	"github.com/SmirnovND/gophkeeper/internal/urimatch"
+	"io"
+	"net/url"
+	"path/filepath"
+	"strings"
+)
+
+// Форматы экспорта. Экспорты 1Password, LastPass, Chrome и Firefox разбираются общим разбором CSV по заголовку
+const (
+	Auto        = "auto"
+	Bitwarden   = "bitwarden"
+	KeePass     = "keepass"
+	CSV         = "csv"
+	OnePassword = "1password"
+	LastPass    = "lastpass"
+	Chrome      = "chrome"
+	Firefox     = "firefox"
+)
+
+// Formats возвращает названия поддерживаемых форматов
+func Formats() []string {
+	return []string{Auto, Bitwarden, KeePass, CSV, OnePassword, LastPass, Chrome, Firefox}
+}
+
+// Entry - запись экспорта, приведенная к типу gophkeeper. Заполнено только поле содержимого типа записи
+type Entry struct {
+	Type       string
+	Label      string
+	Credential *domain.CredentialData
+	Card       *domain.CardData
+	Text       *domain.TextData
+	Metadata   string
+	// Source - расположение записи в экспорте для отчета, например папка и название
+	Source string
+}
+
+// Result - записи экспорта и предупреждения о данных, которые не удалось перенести
+type Result struct {
+	Entries  []Entry
+	Warnings []string
+}
+
+// warn добавляет предупреждение
+func (r *Result) warn(format string, args ...interface{}) {
+	r.Warnings = append(r.Warnings, fmt.Sprintf(format, args...))
+}
+
+// Parse разбирает экспорт формата format. Для Auto формат определяется по имени файла name и содержимому
+func Parse(r io.Reader, format string, name string) (*Result, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+
+	if format == "" || format == Auto {
+		format = detectFormat(name, data)
+	}
+
+	result := &Result{}
+	switch format {
+	case Bitwarden:
+		err = parseBitwarden(data, result)
+	case KeePass:
+		err = parseKeePass(data, result)
+	case CSV, OnePassword, LastPass, Chrome, Firefox:
+		err = parseCSV(data, result)
+	default:
+		return nil, fmt.Errorf("неизвестный формат '%s', допустимы %s", format, strings.Join(Formats(), ", "))
+	}
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// detectFormat определяет формат по расширению файла, а без него - по первому символу содержимого
+func detectFormat(name string, data []byte) string {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".json":
+		return Bitwarden
+	case ".xml":
+		return KeePass
+	case ".csv":
+		return CSV
+	}
+
+	switch trimmed := bytes.TrimSpace(data); {
+	case bytes.HasPrefix(trimmed, []byte("{")):
+		return Bitwarden
+	case bytes.HasPrefix(trimmed, []byte("<")):
+		return KeePass
+	default:
+		return CSV
+	}
+}
+
+// field - именованное значение, переносимое в метаинформацию
+type field struct {
+	name  string
+	value string
+}
+
+// metadata собирает метаинформацию записи: непустые поля по одному в строке, затем заметки
+func metadata(notes string, fields ...field) string {
+	var lines []string
+	for _, f := range fields {
+		if value := strings.TrimSpace(f.value); value != "" {
+			lines = append(lines, f.name+": "+value)
+		}
+	}
+	if notes = strings.TrimSpace(notes); notes != "" {
+		lines = append(lines, notes)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// label возвращает метку записи: название, а без него - адрес сайта
+func label(name string, site string) string {
+	if name = strings.TrimSpace(name); name != "" {
+		return name
+	}
+	site = strings.TrimSpace(site)
+	if parsed, err := url.Parse(site); err == nil && parsed.Hostname() != "" {
+		return parsed.Hostname()
+	}
+	return site
+}
+
+// source возвращает расположение записи в экспорте
+func source(folder string, name string) string {
+	if folder = strings.TrimSpace(folder); folder != "" {
+		return folder + "/" + name
+	}
+	return name
+}
+
+// credentialEntry создает запись учетных данных
+func credentialEntry(name string, login string, password string, meta string, src string) Entry {
+	return Entry{
+		Type:       domain.UserDataTypeCredential,
+		Label:      name,
+		Credential: &domain.CredentialData{Login: login, Password: password},
+		Metadata:   meta,
+		Source:     src,
+	}
+}
+
+// addTOTP переносит в учетные данные записи ключ TOTP: URI otpauth:// или секрет в base32.
+// Ключ, который не удалось разобрать, например формата Steam, пропускается с предупреждением
+func (r *Result) addTOTP(entry *Entry, value string) {
+	if strings.TrimSpace(value) == "" {
+		return
+	}
+	uri, err := totp.URI(value, entry.Label)
+	if err != nil {
+		r.warn("%s: секрет TOTP не импортирован: %v", entry.Source, err)
+		return
+	}
+	entry.Credential.TOTP = uri
+}
+
+// addURI добавляет в учетные данные записи адрес сайта с правилом сравнения match.
+// Пустой адрес пропускается, а неверный - пропускается с предупреждением и остается только в метаинформации
+func (r *Result) addURI(entry *Entry, uri string, match string) {
+	credentialURI := domain.CredentialURI{URI: strings.TrimSpace(uri), Match: match}
+	if credentialURI.URI == "" {
+		return
+	}
+	if err := urimatch.Validate(credentialURI); err != nil {
+		r.warn("%s: адрес не импортирован: %v", entry.Source, err)
+		return
+	}
+	entry.Credential.URIs = append(entry.Credential.URIs, credentialURI)
+}
+
+// textEntry создает текстовую запись
+func textEntry(name string, content string, meta string, src string) Entry {
+	return Entry{
+		Type:     domain.UserDataTypeText,
+		Label:    name,
+		Text:     &domain.TextData{Content: content},
+		Metadata: meta,
+		Source:   src,
+	}
+}
+
+// cardEntry создает запись банковской карты
+func cardEntry(name string, card *domain.CardData, meta string, src string) Entry {
+	return Entry{
+		Type:     domain.UserDataTypeCard,
+		Label:    name,
+		Card:     card,
+		Metadata: meta,
+		Source:   src,
+	}
+}
+
+// expiryDate приводит месяц и год окончания действия карты к виду MM/YY
+func expiryDate(month string, year string) string {
+	month, year = strings.TrimSpace(month), strings.TrimSpace(year)
+	if month == "" && year == "" {
+		return ""
+	}
+	if len(month) == 1 {
+		month = "0" + month
+	}
+	if len(year) == 4 {
+		year = year[2:]
+	}
+	return month + "/" + year
+}
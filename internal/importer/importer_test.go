@@ -0,0 +1,223 @@
+package importer
+
+import (
+	"github.com/SmirnovND/gophkeeper/internal/domain"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const bitwardenJSON = `{
+  "encrypted": false,
+  "folders": [{"id": "f1", "name": "Работа"}],
+  "items": [
+    {"type": 1, "name": "GitHub", "notes": "рабочий аккаунт", "folderId": "f1",
+     "fields": [{"name": "team", "value": "core", "type": 0}, {"name": "recovery", "value": "xyz", "type": 1}],
+     "login": {"username": "alice", "password": "s3cret", "totp": "JBSWY3DP", "uris": [{"uri": "https://github.com", "match": null}, {"uri": "https://git.corp:8443", "match": 1}]}},
+    {"type": 2, "name": "Wi-Fi", "notes": "пароль: hunter2", "folderId": null},
+    {"type": 3, "name": "Visa", "notes": null,
+     "card": {"cardholderName": "ALICE", "brand": "Visa", "number": "4111111111111111", "expMonth": "3", "expYear": "2027", "code": "123"}},
+    {"type": 4, "name": "Паспорт", "identity": {"firstName": "Алиса", "lastName": "Иванова", "email": null}}
+  ]
+}`
+
+const lastPassCSV = "url,username,password,totp,extra,name,grouping,fav\r\n" +
+	"https://mail.example.com,bob,pa55,,notes here,Mail,Personal,0\r\n" +
+	"http://sn,,,,\"NoteType:Credit Card\nLanguage:en-US\nName on Card:BOB\nType:Visa\nNumber:5555444433331111\n" +
+	"Security Code:321\nStart Date:,\nExpiration Date:January,2028\nNotes:запасная\",Card,,0\r\n" +
+	"http://sn,,,,секретная заметка,Note,Personal,0\r\n"
+
+const firefoxCSV = `"url","username","password","httpRealm","formActionOrigin","guid","timeCreated","timePasswordChanged","timeLastUsed"
+"https://accounts.example.org","carol","p@ss",,"https://accounts.example.org","{1}","1","1","1"
+`
+
+const keePassXML = `<?xml version="1.0" encoding="utf-8" standalone="yes"?>
+<KeePassFile>
+  <Meta><RecycleBinUUID>BIN</RecycleBinUUID></Meta>
+  <Root>
+    <Group>
+      <UUID>ROOT</UUID><Name>Database</Name>
+      <Entry>
+        <String><Key>Title</Key><Value>Server</Value></String>
+        <String><Key>UserName</Key><Value>root</Value></String>
+        <String><Key>Password</Key><Value ProtectInMemory="True">toor</Value></String>
+        <String><Key>URL</Key><Value>ssh://10.0.0.1</Value></String>
+        <String><Key>Port</Key><Value>2222</Value></String>
+        <String><Key>PIN</Key><Value ProtectInMemory="True">0000</Value></String>
+        <History><Entry><String><Key>Title</Key><Value>Old</Value></String></Entry></History>
+      </Entry>
+      <Group>
+        <UUID>G1</UUID><Name>Заметки</Name>
+        <Entry><String><Key>Title</Key><Value>Ключи</Value></String><String><Key>Notes</Key><Value>abc</Value></String></Entry>
+      </Group>
+      <Group>
+        <UUID>BIN</UUID><Name>Recycle Bin</Name>
+        <Entry><String><Key>Title</Key><Value>Deleted</Value></String><String><Key>Password</Key><Value>x</Value></String></Entry>
+      </Group>
+    </Group>
+  </Root>
+</KeePassFile>`
+
+// TestParse_Bitwarden тестирует перенос логинов, заметок, карт и личных данных Bitwarden
+func TestParse_Bitwarden(t *testing.T) {
+	result, err := Parse(strings.NewReader(bitwardenJSON), Auto, "export.json")
+	if err != nil {
+		t.Fatalf("Неожиданная ошибка: %v", err)
+	}
+	if len(result.Entries) != 4 {
+		t.Fatalf("Ожидалось 4 записи, получено %d", len(result.Entries))
+	}
+
+	login := result.Entries[0]
+	if login.Type != domain.UserDataTypeCredential || login.Label != "GitHub" ||
+		login.Credential.Login != "alice" || login.Credential.Password != "s3cret" || login.Source != "Работа/GitHub" {
+		t.Errorf("Неверная запись учетных данных: %+v", login)
+	}
+	if want := "URL: https://github.com https://git.corp:8443\nПапка: Работа\nрабочий аккаунт"; login.Metadata != want {
+		t.Errorf("Ожидалась метаинформация %q, получена %q", want, login.Metadata)
+	}
+	wantURIs := []domain.CredentialURI{{URI: "https://github.com"}, {URI: "https://git.corp:8443", Match: "host"}}
+	if !reflect.DeepEqual(login.Credential.URIs, wantURIs) {
+		t.Errorf("Ожидались адреса %+v, получено %+v", wantURIs, login.Credential.URIs)
+	}
+	wantFields := []domain.CustomField{{Name: "team", Type: domain.CustomFieldText, Value: "core"}, {Name: "recovery", Type: domain.CustomFieldHidden, Value: "xyz"}}
+	if !reflect.DeepEqual(login.Credential.Fields, wantFields) || strings.Contains(login.Metadata, "xyz") {
+		t.Errorf("Пользовательские поля логина должны переноситься в дополнительные поля: %+v", login.Credential.Fields)
+	}
+	if want := "otpauth://totp/GitHub?secret=JBSWY3DP"; login.Credential.TOTP != want {
+		t.Errorf("Ожидался ключ TOTP %q, получен %q", want, login.Credential.TOTP)
+	}
+
+	if note := result.Entries[1]; note.Type != domain.UserDataTypeText || note.Text.Content != "пароль: hunter2" {
+		t.Errorf("Неверная текстовая запись: %+v", note)
+	}
+	card := result.Entries[2]
+	if card.Type != domain.UserDataTypeCard || card.Card.ExpiryDate != "03/27" || card.Card.CVV != "123" ||
+		card.Card.Holder != "ALICE" || card.Metadata != "Платежная система: Visa" {
+		t.Errorf("Неверная запись карты: %+v %+v", card, card.Card)
+	}
+	if identity := result.Entries[3]; identity.Text.Content != "Имя: Алиса\nФамилия: Иванова" {
+		t.Errorf("Неверные личные данные: %q", identity.Text.Content)
+	}
+
+	if _, err := Parse(strings.NewReader(`{"encrypted": true}`), Bitwarden, ""); err == nil {
+		t.Error("Ожидалась ошибка для зашифрованного экспорта")
+	}
+}
+
+// TestParse_CSV тестирует CSV-экспорты LastPass и Firefox
+func TestParse_CSV(t *testing.T) {
+	result, err := Parse(strings.NewReader(lastPassCSV), LastPass, "")
+	if err != nil {
+		t.Fatalf("Неожиданная ошибка: %v", err)
+	}
+	if len(result.Entries) != 3 {
+		t.Fatalf("Ожидалось 3 записи, получено %d", len(result.Entries))
+	}
+	if mail := result.Entries[0]; mail.Credential.Login != "bob" || mail.Metadata != "URL: https://mail.example.com\nПапка: Personal\nnotes here" ||
+		len(mail.Credential.URIs) != 1 || mail.Credential.URIs[0].URI != "https://mail.example.com" {
+		t.Errorf("Неверная запись учетных данных: %+v", mail)
+	}
+	card := result.Entries[1]
+	if card.Type != domain.UserDataTypeCard || card.Card.Number != "5555444433331111" || card.Card.CVV != "321" ||
+		card.Card.ExpiryDate != "01/28" || card.Metadata != "Type:Visa\nNotes:запасная" {
+		t.Errorf("Неверная запись карты: %+v %+v", card, card.Card)
+	}
+	if note := result.Entries[2]; note.Type != domain.UserDataTypeText || note.Text.Content != "секретная заметка" {
+		t.Errorf("Неверная текстовая запись: %+v", note)
+	}
+
+	// Firefox не экспортирует названия, метка берется из адреса сайта
+	result, err = Parse(strings.NewReader("\xef\xbb\xbf"+firefoxCSV), Auto, "logins.csv")
+	if err != nil {
+		t.Fatalf("Неожиданная ошибка: %v", err)
+	}
+	if len(result.Entries) != 1 || result.Entries[0].Label != "accounts.example.org" || result.Entries[0].Credential.Password != "p@ss" {
+		t.Errorf("Неверные записи Firefox: %+v", result.Entries)
+	}
+
+	if _, err := Parse(strings.NewReader("a,b\n1,2\n"), CSV, ""); err == nil {
+		t.Error("Ожидалась ошибка для CSV без колонок password и notes")
+	}
+}
+
+// TestParse_KeePass тестирует группы, защищенные поля, историю и корзину KeePass
+func TestParse_KeePass(t *testing.T) {
+	result, err := Parse(strings.NewReader(keePassXML), Auto, "")
+	if err != nil {
+		t.Fatalf("Неожиданная ошибка: %v", err)
+	}
+	if len(result.Entries) != 2 {
+		t.Fatalf("Ожидалось 2 записи без истории и корзины, получено %+v", result.Entries)
+	}
+
+	server := result.Entries[0]
+	if server.Label != "Server" || server.Credential.Password != "toor" || server.Metadata != "URL: ssh://10.0.0.1\nPort: 2222" {
+		t.Errorf("Неверная запись учетных данных: %+v", server)
+	}
+	if len(result.Warnings) != 1 || !strings.Contains(result.Warnings[0], "PIN") {
+		t.Errorf("Ожидалось предупреждение о защищенном поле, получено %v", result.Warnings)
+	}
+	if note := result.Entries[1]; note.Type != domain.UserDataTypeText || note.Source != "Заметки/Ключи" || note.Text.Content != "abc" {
+		t.Errorf("Неверная текстовая запись: %+v", note)
+	}
+
+	if _, err := Parse(strings.NewReader("\x03\xd9\xa2\x9a..."), KeePass, "db.kdbx"); err == nil {
+		t.Error("Ожидалась ошибка для зашифрованной базы")
+	}
+}
+
+// TestPlan тестирует разрешение совпадений меток с сервером и внутри экспорта
+func TestPlan(t *testing.T) {
+	entries := []Entry{
+		{Type: domain.UserDataTypeCredential, Label: "github"},
+		{Type: domain.UserDataTypeCredential, Label: "github"},
+		{Type: domain.UserDataTypeText, Label: "photo"},
+		{Type: domain.UserDataTypeText, Label: "new"},
+	}
+	existing := []domain.ItemInfo{
+		{Type: domain.UserDataTypeCredential, Label: "github"},
+		{Type: domain.UserDataTypeCredential, Label: "github (2)"},
+		{Type: domain.UserDataTypeFile, Label: "photo"},
+	}
+
+	tests := []struct {
+		conflict string
+		actions  []string
+		labels   []string
+	}{
+		{
+			conflict: ConflictSkip,
+			actions:  []string{ActionSkip, ActionSkip, ActionSkip, ActionCreate},
+			labels:   []string{"github", "github", "photo", "new"},
+		},
+		{
+			conflict: ConflictRename,
+			actions:  []string{ActionRename, ActionRename, ActionRename, ActionCreate},
+			labels:   []string{"github (3)", "github (4)", "photo (2)", "new"},
+		},
+		{
+			conflict: ConflictOverwrite,
+			actions:  []string{ActionOverwrite, ActionRename, ActionSkip, ActionCreate},
+			labels:   []string{"github", "github (3)", "photo", "new"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.conflict, func(t *testing.T) {
+			steps, err := Plan(entries, existing, tt.conflict)
+			if err != nil {
+				t.Fatalf("Неожиданная ошибка: %v", err)
+			}
+			for i, step := range steps {
+				if step.Action != tt.actions[i] || step.Label != tt.labels[i] {
+					t.Errorf("Запись %d: ожидалось %s '%s', получено %s '%s'", i, tt.actions[i], tt.labels[i], step.Action, step.Label)
+				}
+			}
+		})
+	}
+
+	if _, err := Plan(entries, existing, "merge"); err == nil {
+		t.Error("Ожидалась ошибка для неизвестного способа")
+	}
+}
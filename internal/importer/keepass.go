@@ -0,0 +1,119 @@
+package importer
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// kdbxSignature - начало зашифрованной базы KeePass
+var kdbxSignature = []byte{0x03, 0xd9, 0xa2, 0x9a}
+
+// Стандартные поля записи KeePass
+const (
+	keePassTitle    = "Title"
+	keePassUserName = "UserName"
+	keePassPassword = "Password"
+	keePassURL      = "URL"
+	keePassNotes    = "Notes"
+)
+
+// keePassFile - XML-экспорт KeePass 2.x. Значения защищенных полей в экспорте не зашифрованы
+type keePassFile struct {
+	Meta struct {
+		RecycleBinUUID string `xml:"RecycleBinUUID"`
+	} `xml:"Meta"`
+	Root struct {
+		Groups []keePassGroup `xml:"Group"`
+	} `xml:"Root"`
+}
+
+// keePassGroup - группа записей. Вложенные элементы History не разбираются, поэтому старые версии записей пропускаются
+type keePassGroup struct {
+	UUID    string         `xml:"UUID"`
+	Name    string         `xml:"Name"`
+	Entries []keePassEntry `xml:"Entry"`
+	Groups  []keePassGroup `xml:"Group"`
+}
+
+type keePassEntry struct {
+	Strings []struct {
+		Key   string `xml:"Key"`
+		Value struct {
+			Text      string `xml:",chardata"`
+			Protected bool   `xml:"ProtectInMemory,attr"`
+		} `xml:"Value"`
+	} `xml:"String"`
+}
+
+// parseKeePass разбирает XML-экспорт KeePass. Дополнительные поля записи переносятся в метаинформацию,
+// корзина пропускается
+func parseKeePass(data []byte, result *Result) error {
+	if bytes.HasPrefix(data, kdbxSignature) {
+		return errors.New("файл .kdbx зашифрован, выполните в KeePass экспорт в формате KeePass XML (2.x)")
+	}
+
+	var file keePassFile
+	if err := xml.Unmarshal(data, &file); err != nil {
+		return fmt.Errorf("неверный XML-экспорт KeePass: %w", err)
+	}
+
+	var walk func(group keePassGroup, path []string)
+	walk = func(group keePassGroup, path []string) {
+		if file.Meta.RecycleBinUUID != "" && group.UUID == file.Meta.RecycleBinUUID {
+			return
+		}
+		folder := strings.Join(path, "/")
+
+		for _, entry := range group.Entries {
+			values := make(map[string]string)
+			for _, s := range entry.Strings {
+				values[s.Key] = s.Value.Text
+			}
+			name := label(values[keePassTitle], values[keePassURL])
+			src := source(folder, name)
+			if name == "" {
+				result.warn("%s: запись без названия и адреса пропущена", folder)
+				continue
+			}
+
+			// Защищенные дополнительные поля являются секретами и в метаинформацию не переносятся
+			var custom []field
+			for _, s := range entry.Strings {
+				switch {
+				case s.Key == keePassTitle, s.Key == keePassUserName, s.Key == keePassPassword,
+					s.Key == keePassURL, s.Key == keePassNotes:
+				case s.Value.Protected:
+					result.warn("%s: защищенное поле '%s' не импортировано", src, s.Key)
+				default:
+					custom = append(custom, field{s.Key, s.Value.Text})
+				}
+			}
+
+			fields := append([]field{{"URL", values[keePassURL]}, {"Папка", folder}}, custom...)
+			username, password, notes := values[keePassUserName], values[keePassPassword], values[keePassNotes]
+			switch {
+			case username != "" || password != "":
+				entry := credentialEntry(name, username, password, metadata(notes, fields...), src)
+				result.addURI(&entry, values[keePassURL], "")
+				result.Entries = append(result.Entries, entry)
+			case strings.TrimSpace(notes) != "":
+				result.Entries = append(result.Entries, textEntry(name, notes, metadata("", fields...), src))
+			default:
+				result.warn("%s: пустая запись пропущена", src)
+			}
+		}
+
+		for _, child := range group.Groups {
+			walk(child, append(append([]string{}, path...), child.Name))
+		}
+	}
+
+	// Корневая группа называется по имени базы, в путь папок она не входит
+	for _, root := range file.Root.Groups {
+		walk(root, nil)
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package importer
+
+import (
+	"fmt"
+	"github.com/SmirnovND/gophkeeper/internal/domain"
+)
+
+// Способы разрешения совпадения метки импортируемой записи с существующей
+const (
+	ConflictSkip      = "skip"
+	ConflictRename    = "rename"
+	ConflictOverwrite = "overwrite"
+)
+
+// Действия с импортируемой записью
+const (
+	ActionCreate    = "create"
+	ActionRename    = "rename"
+	ActionOverwrite = "overwrite"
+	ActionSkip      = "skip"
+)
+
+// Step - действие с записью экспорта. Label - метка, под которой запись будет сохранена
+type Step struct {
+	Entry  Entry
+	Action string
+	Label  string
+	// Reason поясняет пропуск записи
+	Reason string
+}
+
+// Plan решает, что делать с каждой записью экспорта с учетом записей existing, уже сохраненных на сервере.
+// Совпадения меток внутри самого экспорта всегда разрешаются переименованием, файлы на сервере не перезаписываются
+func Plan(entries []Entry, existing []domain.ItemInfo, conflict string) ([]Step, error) {
+	switch conflict {
+	case ConflictSkip, ConflictRename, ConflictOverwrite:
+	default:
+		return nil, fmt.Errorf("неизвестный способ разрешения совпадений '%s', допустимы skip, rename и overwrite", conflict)
+	}
+
+	stored := make(map[string]string, len(existing))
+	for _, item := range existing {
+		stored[item.Label] = item.Type
+	}
+	// taken - метки, занятые на сервере или предыдущими записями экспорта
+	taken := make(map[string]bool, len(existing)+len(entries))
+	for label := range stored {
+		taken[label] = true
+	}
+	imported := make(map[string]bool, len(entries))
+
+	steps := make([]Step, 0, len(entries))
+	for _, entry := range entries {
+		step := Step{Entry: entry, Action: ActionCreate, Label: entry.Label}
+		storedType, onServer := stored[entry.Label]
+
+		switch {
+		case !taken[entry.Label]:
+		case imported[entry.Label], conflict == ConflictRename:
+			step.Action, step.Label = ActionRename, freeLabel(entry.Label, taken)
+		case conflict == ConflictSkip:
+			step.Action, step.Reason = ActionSkip, "метка уже используется"
+		case onServer && storedType == domain.UserDataTypeFile:
+			step.Action, step.Reason = ActionSkip, "метка занята файлом, файлы не перезаписываются"
+		default:
+			step.Action = ActionOverwrite
+		}
+
+		if step.Action != ActionSkip {
+			taken[step.Label] = true
+			imported[step.Label] = true
+		}
+		steps = append(steps, step)
+	}
+	return steps, nil
+}
+
+// freeLabel возвращает свободную метку вида "label (2)"
+func freeLabel(label string, taken map[string]bool) string {
+	for i := 2; ; i++ {
+		candidate := fmt.Sprintf("%s (%d)", label, i)
+		if !taken[candidate] {
+			return candidate
+		}
+	}
+}
@@ -7,6 +7,9 @@ type Command interface {
 	RegisterCmd() *cobra.Command
 	UploadCmd() *cobra.Command
 	DownloadCmd() *cobra.Command
+	UsageCmd() *cobra.Command
+	SettingsCmd() *cobra.Command
+	FileCmd() *cobra.Command
 	
 	// Команды для работы с текстовыми данными
 	SaveTextCmd() *cobra.Command
@@ -22,7 +25,52 @@ type Command interface {
 	SaveCredentialCmd() *cobra.Command
 	GetCredentialCmd() *cobra.Command
 	DeleteCredentialCmd() *cobra.Command
+	FindCmd() *cobra.Command
 	
+	// Текущий код TOTP учетных данных
+	OTPCmd() *cobra.Command
+
+	// Команды для работы с ключами SSH и агент SSH с ключами хранилища
+	SaveSSHKeyCmd() *cobra.Command
+	GetSSHKeyCmd() *cobra.Command
+	DeleteSSHKeyCmd() *cobra.Command
+	SSHAgentCmd() *cobra.Command
+
+	// Команды для работы с записями структурированных типов
+	ItemCmd() *cobra.Command
+
 	// Команда для получения информации о версии
 	VersionCmd() *cobra.Command
+
+	// Команда управления профилями и выбор профиля перед выполнением команды
+	ProfileCmd() *cobra.Command
+	SelectProfile(cmd *cobra.Command, args []string) error
+
+	// Полноэкранный интерфейс для просмотра и изменения записей
+	TUICmd() *cobra.Command
+
+	// Копирование секрета записи в буфер обмена с последующей очисткой
+	CopyCmd() *cobra.Command
+
+	// Генерация пароля или парольной фразы
+	GenerateCmd() *cobra.Command
+
+	// Импорт записей из другого менеджера паролей
+	ImportCmd() *cobra.Command
+
+	// Экспорт хранилища в зашифрованную резервную копию и восстановление из нее
+	ExportCmd() *cobra.Command
+	RestoreBackupCmd() *cobra.Command
+
+	// Запуск процесса с секретами в переменных окружения
+	RunCmd() *cobra.Command
+
+	// Подстановка секретов в шаблон конфигурационного файла
+	InjectCmd() *cobra.Command
+
+	// Помощник учетных данных git
+	GitCredentialCmd() *cobra.Command
+
+	// Помощник учетных данных docker
+	DockerCredentialCmd() *cobra.Command
 }
@@ -1,5 +1,10 @@
 package interfaces
 
+import (
+	"crypto/tls"
+	"github.com/SmirnovND/gophkeeper/internal/domain"
+)
+
 type ConfigServer interface {
 	GetJwtSecret() string
 	GetDBDsn() string
@@ -8,4 +13,35 @@ type ConfigServer interface {
 	GetMinioAccessKey() string
 	GetMinioSecretKey() string
 	GetMinioHost() string
+	GetMinioSecure() bool
+	GetMasterKey() string
+	GetPreviousMasterKeys() []string
+	GetDefaultQuota() domain.Quota
+	GetDefaultSettings() domain.UserSettings
+	GetFileTypePolicy() domain.FileTypePolicy
+}
+
+// ConfigClient описывает конфигурацию passcli: именованные профили подключения к серверу
+// и их переопределения переменными окружения PASSCLI_*
+type ConfigClient interface {
+	// Load читает файл конфигурации.
+	Load() error
+	// Select читает файл конфигурации и выбирает профиль, пустое имя означает текущий профиль.
+	Select(name string) error
+
+	GetProfileName() string
+	GetServerAddress() string
+	GetTLSConfig() (*tls.Config, error)
+	GetOutput() string
+	GetTokenOverride() string
+	GetTokenPath() (string, error)
+
+	// ListProfiles возвращает профили, отсортированные по имени.
+	ListProfiles() []domain.Profile
+	// AddProfile добавляет профиль и сохраняет конфигурацию.
+	AddProfile(profile domain.Profile) error
+	// UseProfile делает профиль текущим и сохраняет конфигурацию.
+	UseProfile(name string) error
+	// RemoveProfile удаляет профиль вместе с его токеном и сохраняет конфигурацию.
+	RemoveProfile(name string) error
 }
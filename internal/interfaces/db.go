@@ -8,6 +8,8 @@ import (
 // В interfaces/db.go
 type DB interface {
 	QueryRow(query string, args ...any) *sqlx.Row
+	Queryx(query string, args ...any) (*sqlx.Rows, error)
 	Ping() error
 	Exec(query string, args ...any) (sql.Result, error)
+	Beginx() (*sqlx.Tx, error)
 }
@@ -1,6 +1,9 @@
 package interfaces
 
-import "github.com/SmirnovND/gophkeeper/internal/domain"
+import (
+	"encoding/json"
+	"github.com/SmirnovND/gophkeeper/internal/domain"
+)
 
 // UserRepo описывает интерфейс для работы с пользователями.
 type UserRepo interface {
@@ -19,6 +22,12 @@ type UserDataRepo interface {
 	// Возвращает ошибку, если произошла ошибка при сохранении.
 	SaveUserData(userData *domain.UserData) error
 
+	// SaveUserDataChecked сохраняет данные пользователя так же, как SaveUserData, если check не вернул ошибку.
+	// check получает потребление хранилища пользователем и перезаписываемую запись с той же меткой или nil.
+	// Проверка и сохранение выполняются в одной транзакции под блокировкой пользователя, поэтому параллельные
+	// сохранения одного пользователя выполняются по очереди. Ошибка check возвращается без изменений.
+	SaveUserDataChecked(userData *domain.UserData, check func(usage *domain.Usage, existing *domain.UserData) error) error
+
 	// FindUserDataByLabel ищет данные пользователя по метке.
 	// Возвращает данные и nil, если данные найдены.
 	// Возвращает nil и ошибку, если данные не найдены или произошла другая ошибка.
@@ -32,6 +41,70 @@ type UserDataRepo interface {
 	// DeleteUserData удаляет данные пользователя по ID.
 	// Возвращает ошибку, если произошла ошибка при удалении.
 	DeleteUserData(id string) error
+
+	// ListUserDataByType возвращает все данные пользователя указанного типа, отсортированные по метке.
+	ListUserDataByType(userID string, dataType string) ([]*domain.UserData, error)
+
+	// ListUserData возвращает все записи пользователя без их содержимого, отсортированные по метке.
+	ListUserData(userID string) ([]*domain.UserData, error)
+
+	// RenameUserData меняет метку и содержимое записи по ID.
+	// Возвращает domain.ErrNotFound, если запись не найдена.
+	RenameUserData(id string, label string, data json.RawMessage) error
+}
+
+// QuotaRepo описывает интерфейс для работы с квотами и потреблением хранилища.
+type QuotaRepo interface {
+	// GetUserQuota возвращает квоту пользователя с учетом индивидуальных настроек.
+	// Если индивидуальных настроек нет, возвращает defaults.
+	GetUserQuota(userID string, defaults domain.Quota) (*domain.Quota, error)
+
+	// GetUsage возвращает количество записей, файлов и суммарный размер файлов пользователя.
+	GetUsage(userID string) (*domain.Usage, error)
+}
+
+// SettingsRepo описывает интерфейс для работы с настройками аккаунтов.
+type SettingsRepo interface {
+	// GetUserSettings возвращает настройки пользователя.
+	// Если пользователь не менял настройки, возвращает defaults.
+	GetUserSettings(userID string, defaults domain.UserSettings) (*domain.UserSettings, error)
+
+	// SaveUserSettings сохраняет настройки пользователя.
+	SaveUserSettings(userID string, settings *domain.UserSettings) error
+}
+
+// UserKeyRepo описывает интерфейс для работы с ключами данных пользователей.
+type UserKeyRepo interface {
+	// GetUserKey возвращает обернутый ключ пользователя.
+	// Возвращает domain.ErrNotFound, если ключа нет.
+	GetUserKey(userID string) (*domain.UserKey, error)
+
+	// SaveUserKey сохраняет ключ пользователя, если его еще нет.
+	// Существующий ключ не перезаписывается, чтобы не потерять доступ к уже зашифрованным объектам.
+	SaveUserKey(userKey *domain.UserKey) error
+
+	// UpdateUserKey заменяет обернутый ключ пользователя, например после смены мастер-ключа.
+	UpdateUserKey(userKey *domain.UserKey) error
+
+	// ListUserKeysNotWrappedWith возвращает ключи, обернутые мастер-ключом, отличным от masterKeyID.
+	ListUserKeysNotWrappedWith(masterKeyID string) ([]*domain.UserKey, error)
+}
+
+// ItemTemplateRepo описывает интерфейс для работы с шаблонами пользовательских типов записей.
+type ItemTemplateRepo interface {
+	// SaveItemTemplate сохраняет шаблон пользователя, шаблон с тем же именем заменяется.
+	SaveItemTemplate(userID string, template *domain.ItemTemplate) error
+
+	// GetItemTemplate возвращает шаблон пользователя по имени.
+	// Возвращает domain.ErrNotFound, если шаблона нет.
+	GetItemTemplate(userID string, name string) (*domain.ItemTemplate, error)
+
+	// ListItemTemplates возвращает все шаблоны пользователя, отсортированные по имени.
+	ListItemTemplates(userID string) ([]*domain.ItemTemplate, error)
+
+	// DeleteItemTemplate удаляет шаблон пользователя по имени.
+	// Возвращает domain.ErrNotFound, если шаблона нет.
+	DeleteItemTemplate(userID string, name string) error
 }
 
 // TokenStorage описывает интерфейс для хранения и управления токеном авторизации.
@@ -1,7 +1,9 @@
 package interfaces
 
 import (
+	"encoding/json"
 	"github.com/SmirnovND/gophkeeper/internal/domain"
+	"github.com/SmirnovND/gophkeeper/internal/itemtype"
 	"net/http"
 	"os"
 )
@@ -49,33 +51,108 @@ type ClientService interface {
 	// Register выполняет запрос к API сервера для регистрации пользователя и получения токена
 	Register(login string, password string) (string, error)
 
-	GetUploadLink(label string, extension string, metadata string, token string) (string, error)
+	// GetUploadLink запрашивает ссылку и поля формы для загрузки описанного в fileData файла
+	GetUploadLink(fileData *domain.FileData, token string) (*domain.FileDataResponse, error)
 
-	GetDownloadLink(label string, token string) (string, *domain.FileMetadata, string, error)
+	// GetDownloadLink запрашивает ссылку на скачивание файла, его метаданные и метаинформацию
+	GetDownloadLink(label string, token string) (*domain.DownloadLink, *domain.FileMetadata, string, error)
 
-	SendFileToServer(url string, file *os.File) (string, error)
+	// SendFileToServer загружает файл в хранилище POST-запросом по выданной ссылке
+	SendFileToServer(upload *domain.FileDataResponse, file *os.File) (string, error)
 
-	DownloadFileFromServer(url string, outputPath string) error
+	// VerifyFile просит сервер проверить тип загруженного файла по его содержимому
+	VerifyFile(label string, token string) error
 
-	// Методы для работы с текстовыми данными
-	SaveText(label string, textData *domain.TextData, metadata string, token string) error
-	GetText(label string, token string) (*domain.TextData, string, error)
-	DeleteText(label string, token string) error
+	// DownloadFileFromServer скачивает файл по ссылке link в outputPath с правами perm, путь "-" означает вывод в stdout
+	DownloadFileFromServer(link *domain.DownloadLink, outputPath string, perm os.FileMode) error
 
-	// Методы для работы с данными кредитных карт
-	SaveCard(label string, cardData *domain.CardData, metadata string, token string) error
-	GetCard(label string, token string) (*domain.CardData, string, error)
-	DeleteCard(label string, token string) error
+	// SetProgress задает функцию, которая получает ход загрузки и скачивания файлов, nil отключает ее
+	SetProgress(progress domain.ProgressFunc)
 
-	// Методы для работы с учетными данными
-	SaveCredential(label string, credentialData *domain.CredentialData, metadata string, token string) error
-	GetCredential(label string, token string) (*domain.CredentialData, string, error)
-	DeleteCredential(label string, token string) error
+	// ListItems получает сведения обо всех записях пользователя без их содержимого
+	ListItems(token string) ([]domain.ItemInfo, error)
+
+	// Методы для управления файлами
+	ListFiles(token string) ([]domain.FileInfo, error)
+	GetFileInfo(label string, token string) (*domain.FileInfo, error)
+	RenameFile(label string, newLabel string, token string) error
+	DeleteFile(label string, token string) error
+
+	// GetUsage получает текущее потребление хранилища и квоту пользователя
+	GetUsage(token string) (*domain.Usage, error)
+
+	// Методы для работы с настройками аккаунта пользователя
+	GetSettings(token string) (*domain.UserSettings, error)
+	SaveSettings(settings *domain.UserSettings, token string) error
+
+	// Методы для работы с записями всех типов, описанных в пакете itemtype, через API v2
+	SaveItem(itemType string, label string, data domain.ItemData, metadata string, token string) error
+	GetItem(itemType string, label string, token string) (domain.ItemData, string, error)
+	DeleteItem(itemType string, label string, token string) error
+
+	// Методы для работы с шаблонами пользовательских типов записей
+	SaveItemTemplate(name string, schema json.RawMessage, token string) error
+	GetItemTemplate(name string, token string) (*domain.ItemTemplate, error)
+	ListItemTemplates(token string) ([]domain.ItemTemplate, error)
+	DeleteItemTemplate(name string, token string) error
 }
 
 type CloudService interface {
-	GenerateUploadLink(fileName string) (string, error)
-	GenerateDownloadLink(fileName string) (string, error)
+	// GenerateUploadLink возвращает URL и поля формы для POST-загрузки файла размером не более maxSize байт
+	// с типом содержимого contentType. Непустой key включает шифрование объекта ключом пользователя (SSE-C)
+	GenerateUploadLink(fileName string, maxSize int64, contentType string, key []byte) (string, map[string]string, error)
+	// GenerateDownloadLink возвращает ссылку на скачивание объекта и заголовки SSE-C, если объект зашифрован ключом key
+	GenerateDownloadLink(fileName string, key []byte) (*domain.DownloadLink, error)
+	// DeleteFile удаляет объект из хранилища
+	DeleteFile(fileName string) error
+	// RenameFile переносит объект в хранилище под новое имя, key - ключ SSE-C объекта или nil
+	RenameFile(fileName string, newFileName string, key []byte) error
+	// ReadFileHead возвращает первые n байт объекта, key - ключ SSE-C объекта или nil.
+	// Отсутствующий объект возвращается ошибкой domain.ErrNotFound
+	ReadFileHead(fileName string, n int64, key []byte) ([]byte, error)
+}
+
+// KeyService определяет интерфейс для управления ключами данных пользователей, обернутыми мастер-ключом сервера
+type KeyService interface {
+	// Enabled сообщает, включено ли шифрование объектов
+	Enabled() bool
+
+	// CreateUserKey генерирует ключ данных для нового пользователя
+	CreateUserKey(login string) error
+
+	// GetUserKey возвращает ключ данных пользователя или nil, если шифрование отключено
+	GetUserKey(login string) ([]byte, error)
+
+	// RotateMasterKey переупаковывает текущим мастер-ключом ключи, обернутые прежними мастер-ключами,
+	// и возвращает их количество
+	RotateMasterKey() (int, error)
+}
+
+// QuotaService определяет интерфейс для проверки квот и учета потребления хранилища
+type QuotaService interface {
+	// GetUsage возвращает текущее потребление хранилища и действующую квоту
+	GetUsage(login string) (*domain.Usage, error)
+
+	// CheckFileUpload проверяет, что загрузка файла не превысит квоту.
+	// Возвращает ошибку, обернутую в domain.ErrQuotaExceeded, если квота будет превышена
+	CheckFileUpload(login string, label string, size int64) error
+
+	// CheckItemSave проверяет, что сохранение записи не превысит квоту на количество записей.
+	// Возвращает ошибку, обернутую в domain.ErrQuotaExceeded, если квота будет превышена
+	CheckItemSave(login string, label string) error
+
+	// GetUserQuota возвращает действующую квоту пользователя по его ID
+	GetUserQuota(userID string) (*domain.Quota, error)
+}
+
+// SettingsService определяет интерфейс для работы с настройками аккаунта
+type SettingsService interface {
+	// GetSettings возвращает действующие настройки пользователя с учетом значений по умолчанию
+	GetSettings(login string) (*domain.UserSettings, error)
+
+	// SaveSettings сохраняет настройки пользователя.
+	// Возвращает ошибку, обернутую в domain.ErrInvalidSettings, если значения недопустимы
+	SaveSettings(login string, settings *domain.UserSettings) error
 }
 
 // DataService определяет интерфейс для работы с данными пользователя
@@ -84,21 +161,27 @@ type DataService interface {
 	SaveFileMetadata(login string, label string, fileData *domain.FileData, metadata string) error
 	GetFileMetadata(login string, label string) (*domain.FileMetadata, string, error)
 	DeleteFileMetadata(login string, label string) error
-
-	// Методы для работы с учетными данными (логин/пароль)
-	SaveCredential(login string, label string, credentialData *domain.CredentialData, metadata string) error
-	GetCredential(login string, label string) (*domain.CredentialData, string, error)
-	DeleteCredential(login string, label string) error
-
-	// Методы для работы с данными кредитных карт
-	SaveCard(login string, label string, cardData *domain.CardData, metadata string) error
-	GetCard(login string, label string) (*domain.CardData, string, error)
-	DeleteCard(login string, label string) error
-
-	// Методы для работы с текстовыми данными
-	SaveText(login string, label string, textData *domain.TextData, metadata string) error
-	GetText(login string, label string) (*domain.TextData, string, error)
-	DeleteText(login string, label string) error
+	ListFiles(login string) ([]domain.FileInfo, error)
+	GetFileInfo(login string, label string) (*domain.FileInfo, error)
+	// RenameFileMetadata меняет метку файла и возвращает метаданные файла до переименования
+	RenameFileMetadata(login string, label string, newLabel string) (*domain.FileMetadata, error)
+
+	// ListItems возвращает сведения обо всех записях пользователя без их содержимого
+	ListItems(login string) ([]domain.ItemInfo, error)
+
+	// Методы для работы с записями встроенных типов из пакета itemtype и пользовательских типов из шаблонов
+	SaveItem(login string, itemType string, label string, data domain.ItemData, metadata string) error
+	GetItem(login string, itemType string, label string) (domain.ItemData, string, error)
+	DeleteItem(login string, itemType string, label string) error
+
+	// ItemType возвращает описание встроенного типа или пользовательского типа из шаблона пользователя
+	ItemType(login string, itemType string) (*itemtype.Type, error)
+
+	// Методы для работы с шаблонами пользовательских типов записей
+	SaveItemTemplate(login string, name string, schema json.RawMessage) (*domain.ItemTemplate, error)
+	GetItemTemplate(login string, name string) (*domain.ItemTemplate, error)
+	ListItemTemplates(login string) ([]domain.ItemTemplate, error)
+	DeleteItemTemplate(login string, name string) error
 }
 
 type JwtService interface {
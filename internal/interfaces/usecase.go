@@ -1,7 +1,9 @@
 package interfaces
 
 import (
+	"encoding/json"
 	"github.com/SmirnovND/gophkeeper/internal/domain"
+	"github.com/SmirnovND/gophkeeper/internal/itemtype"
 	"net/http"
 )
 
@@ -17,17 +19,56 @@ type AuthUseCase interface {
 	ValidateToken(token string) (*domain.Claims, error)
 }
 
+// UserUseCase определяет интерфейс для работы с данными аккаунта пользователя
+type UserUseCase interface {
+	// GetUsage возвращает текущее потребление хранилища и квоту пользователя
+	GetUsage(w http.ResponseWriter, r *http.Request)
+	// GetSettings возвращает действующие настройки аккаунта пользователя
+	GetSettings(w http.ResponseWriter, r *http.Request)
+	// SaveSettings сохраняет настройки аккаунта пользователя
+	SaveSettings(w http.ResponseWriter, r *http.Request)
+}
+
 type ClientUseCase interface {
 	Login(username string, password string) error
 	Register(username string, password string, passwordCheck string) error
-	Upload(filePath string, label string) (string, error)
-	Download(label string) error
+	// Upload загружает файл на сервер, путь "-" означает чтение из stdin
+	Upload(filePath string, label string, metadata string) (string, error)
+	// UploadDir упаковывает директорию в tar-архив и загружает его как один файл
+	UploadDir(dirPath string, label string, metadata string) (string, error)
+	// Download скачивает файл в outputPath, пустой путь - в директорию загрузок, "-" - в stdout
+	Download(label string, outputPath string) error
+	// Extract скачивает архив директории и распаковывает его в destDir
+	Extract(label string, destDir string) error
+
+	// Методы для управления файлами
+	ListFiles() ([]domain.FileInfo, error)
+	FileInfo(label string) (*domain.FileInfo, error)
+	RenameFile(label string, newLabel string) error
+	DeleteFile(label string) error
+
+	// Usage возвращает текущее потребление хранилища и квоту пользователя
+	Usage() (*domain.Usage, error)
+	// Settings возвращает действующие настройки аккаунта пользователя
+	Settings() (*domain.UserSettings, error)
+	// SaveSettings сохраняет настройки аккаунта пользователя
+	SaveSettings(settings *domain.UserSettings) error
+
+	// ListItems возвращает сведения обо всех записях пользователя без их содержимого
+	ListItems() ([]domain.ItemInfo, error)
+	// SetProgress задает функцию, которая получает ход загрузки и скачивания файлов, nil отключает ее
+	SetProgress(progress domain.ProgressFunc)
 	
 	// Методы для работы с текстовыми данными
 	SaveText(label string, textData *domain.TextData, metadata string) error
 	GetText(label string) (*domain.TextData, string, error)
 	DeleteText(label string) error
 	
+	// Методы для работы с ключами SSH
+	SaveSSHKey(label string, sshKeyData *domain.SSHKeyData, metadata string) error
+	GetSSHKey(label string) (*domain.SSHKeyData, string, error)
+	DeleteSSHKey(label string) error
+	
 	// Методы для работы с данными кредитных карт
 	SaveCard(label string, cardData *domain.CardData, metadata string) error
 	GetCard(label string) (*domain.CardData, string, error)
@@ -37,23 +78,46 @@ type ClientUseCase interface {
 	SaveCredential(label string, credentialData *domain.CredentialData, metadata string) error
 	GetCredential(label string) (*domain.CredentialData, string, error)
 	DeleteCredential(label string) error
+	
+	// Методы для работы с записями любых типов, описанных в пакете itemtype. Методы встроенных типов выше
+	// преобразуют их содержимое в значения полей и обратно
+	SaveItem(itemType string, label string, data domain.ItemData, metadata string) error
+	GetItem(itemType string, label string) (domain.ItemData, string, error)
+	DeleteItem(itemType string, label string) error
+	// ItemType возвращает описание встроенного типа или пользовательского типа, заданного шаблоном
+	ItemType(itemType string) (*itemtype.Type, error)
+
+	// Методы для работы с шаблонами пользовательских типов записей
+	SaveItemTemplate(name string, schema json.RawMessage) error
+	GetItemTemplate(name string) (*domain.ItemTemplate, error)
+	ListItemTemplates() ([]domain.ItemTemplate, error)
+	DeleteItemTemplate(name string) error
 }
 
 type CloudUseCase interface {
 	GenerateUploadLink(w http.ResponseWriter, r *http.Request, fileData *domain.FileData)
 	GenerateDownloadLink(w http.ResponseWriter, r *http.Request, label string)
+	DeleteFile(w http.ResponseWriter, r *http.Request, label string)
+	RenameFile(w http.ResponseWriter, r *http.Request, rename *domain.FileRename)
+	// VerifyFile проверяет тип загруженного файла по содержимому и удаляет файл запрещенного типа
+	VerifyFile(w http.ResponseWriter, r *http.Request, label string)
+	ListFiles(w http.ResponseWriter, r *http.Request)
+	GetFileInfo(w http.ResponseWriter, r *http.Request, label string)
 }
 
 type DataUseCase interface {
-	SaveCredential(w http.ResponseWriter, r *http.Request, label string, credentialData *domain.CredentialData, metadata string)
-	GetCredential(w http.ResponseWriter, r *http.Request, label string)
-	DeleteCredential(w http.ResponseWriter, r *http.Request, label string)
+	// Методы для работы с записями всех типов, описанных в пакете itemtype. Представление записи в ответе
+	// задает view: ключ содержимого в API v1 и v2 различается
+	SaveItem(w http.ResponseWriter, r *http.Request, itemType string, label string, data domain.ItemData, metadata string)
+	GetItem(w http.ResponseWriter, r *http.Request, itemType string, label string, view domain.ItemView)
+	DeleteItem(w http.ResponseWriter, r *http.Request, itemType string, label string)
 
-	SaveCard(w http.ResponseWriter, r *http.Request, label string, cardData *domain.CardData, metadata string)
-	GetCard(w http.ResponseWriter, r *http.Request, label string)
-	DeleteCard(w http.ResponseWriter, r *http.Request, label string)
+	// ListItems возвращает сведения обо всех записях пользователя без их содержимого
+	ListItems(w http.ResponseWriter, r *http.Request)
 
-	SaveText(w http.ResponseWriter, r *http.Request, label string, textData *domain.TextData, metadata string)
-	GetText(w http.ResponseWriter, r *http.Request, label string)
-	DeleteText(w http.ResponseWriter, r *http.Request, label string)
+	// Методы для работы с шаблонами пользовательских типов записей
+	SaveItemTemplate(w http.ResponseWriter, r *http.Request, name string, schema json.RawMessage)
+	GetItemTemplate(w http.ResponseWriter, r *http.Request, name string)
+	ListItemTemplates(w http.ResponseWriter, r *http.Request)
+	DeleteItemTemplate(w http.ResponseWriter, r *http.Request, name string)
 }
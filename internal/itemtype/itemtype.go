@@ -0,0 +1,311 @@
+// Package itemtype описывает структурированные типы записей: их поля, виды полей и проверку значений.
+// Тип добавляется описанием в реестре или шаблоном пользователя, а сохранение, получение, формы и вывод
+// строятся по этому описанию
+package itemtype
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/SmirnovND/gophkeeper/internal/domain"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Виды полей
+const (
+	KindText   = "text"   // Произвольная строка
+	KindSecret = "secret" // Секрет: не передается флагами, вводится без отображения и скрывается в интерфейсе
+	KindURL    = "url"    // Адрес со схемой и хостом
+	KindDate   = "date"   // Дата в формате DateLayout
+	KindEnum   = "enum"   // Одно из значений Options
+	KindList   = "list"   // Список JSON, например адреса учетных данных; проверяется проверкой типа
+)
+
+// DateLayout - формат значений полей KindDate
+const DateLayout = "2006-01-02"
+
+// maxValueLength - максимальная длина значения поля в байтах, если у поля не задана своя
+const maxValueLength = 8192
+
+// Mask заменяет значение секретного поля в скрытом представлении записи, длина маски не зависит от длины секрета
+const Mask = "••••••••"
+
+// legacyKey - ключ содержимого записи в теле запросов API v1 для типов без собственного ключа
+const legacyKey = "item_data"
+
+var (
+	// ErrUnknownType - тип записи не зарегистрирован
+	ErrUnknownType = errors.New("неизвестный тип записи")
+	// ErrInvalidItem - значения полей не соответствуют описанию типа
+	ErrInvalidItem = errors.New("неверные данные записи")
+)
+
+// Field - поле записи структурированного типа
+type Field struct {
+	// Name - имя поля в JSON и флагах
+	Name string
+	// Title - название поля в формах и выводе
+	Title    string
+	Kind     string
+	Required bool
+	// Options - допустимые значения поля KindEnum
+	Options []string
+	// MaxLength - максимальная длина значения в байтах, ноль означает maxValueLength
+	MaxLength int
+	// redact скрывает значение секретного поля частично, по умолчанию оно заменяется на Mask целиком
+	redact func(value string) string
+}
+
+// Type - структурированный тип записи
+type Type struct {
+	// Name - имя типа в API, командах и ссылках на секреты
+	Name string
+	// Title - название типа в выводе
+	Title  string
+	Fields []Field
+	// Secret - поле, которое подставляется по умолчанию в ссылках на секреты и копируется passcli copy
+	Secret string
+	// Expiry - поле KindDate с датой окончания действия, пустое, если у типа нет срока действия
+	Expiry string
+	// Builtin - у типа есть собственные команды и формы, поэтому общие формы его не предлагают
+	Builtin bool
+	// Legacy - ключ содержимого записи в API v1, пустой означает item_data
+	Legacy string
+	// Custom - тип описан шаблоном пользователя (ParseSchema) и не входит в реестр
+	Custom bool
+	// check выполняет проверки, зависящие от нескольких полей или формата значения
+	check func(data domain.ItemData) error
+}
+
+// registry - зарегистрированные типы по имени
+var registry = map[string]*Type{}
+
+// register добавляет тип в реестр
+func register(t *Type) {
+	if _, ok := registry[t.Name]; ok {
+		panic("itemtype: тип " + t.Name + " зарегистрирован дважды")
+	}
+	registry[t.Name] = t
+}
+
+// Lookup возвращает тип по имени
+func Lookup(name string) (*Type, error) {
+	t, ok := registry[name]
+	if !ok {
+		return nil, fmt.Errorf("%w '%s'", ErrUnknownType, name)
+	}
+	return t, nil
+}
+
+// Types возвращает зарегистрированные типы, упорядоченные по имени
+func Types() []*Type {
+	types := make([]*Type, 0, len(registry))
+	for _, t := range registry {
+		types = append(types, t)
+	}
+	sort.Slice(types, func(i, j int) bool { return types[i].Name < types[j].Name })
+	return types
+}
+
+// Field возвращает поле типа по имени
+func (t *Type) Field(name string) (Field, bool) {
+	for _, field := range t.Fields {
+		if field.Name == name {
+			return field, true
+		}
+	}
+	return Field{}, false
+}
+
+// Validate проверяет значения полей: неизвестные поля, обязательные поля, длину и формат по виду поля,
+// а затем проверки самого типа
+func (t *Type) Validate(data domain.ItemData) error {
+	for name := range data {
+		if _, ok := t.Field(name); !ok {
+			return fmt.Errorf("%w: у типа %s нет поля '%s'", ErrInvalidItem, t.Name, name)
+		}
+	}
+	for _, field := range t.Fields {
+		value := data[field.Name]
+		if value == "" {
+			if field.Required {
+				return fmt.Errorf("%w: не заполнено обязательное поле '%s'", ErrInvalidItem, field.Name)
+			}
+			continue
+		}
+		if err := field.validate(value); err != nil {
+			return fmt.Errorf("%w: поле '%s': %v", ErrInvalidItem, field.Name, err)
+		}
+	}
+	if t.check != nil {
+		if err := t.check(data); err != nil {
+			return fmt.Errorf("%w: %w", ErrInvalidItem, err)
+		}
+	}
+	return nil
+}
+
+// ExpiresAt возвращает дату окончания действия записи, если у типа есть срок действия и он заполнен
+func (t *Type) ExpiresAt(data domain.ItemData) (time.Time, bool) {
+	if t.Expiry == "" || data[t.Expiry] == "" {
+		return time.Time{}, false
+	}
+	date, err := time.Parse(DateLayout, data[t.Expiry])
+	if err != nil {
+		return time.Time{}, false
+	}
+	return date, true
+}
+
+// LegacyKey возвращает ключ содержимого записи в теле запросов и ответов API v1
+func (t *Type) LegacyKey() string {
+	if t.Legacy == "" {
+		return legacyKey
+	}
+	return t.Legacy
+}
+
+// Redact возвращает копию записи, в которой значения секретных полей скрыты
+func (t *Type) Redact(data domain.ItemData) domain.ItemData {
+	redacted := make(domain.ItemData, len(data))
+	for name, value := range data {
+		field, ok := t.Field(name)
+		switch {
+		case !ok || value == "":
+			redacted[name] = value
+		case field.redact != nil:
+			redacted[name] = field.redact(value)
+		case field.Kind == KindSecret:
+			redacted[name] = Mask
+		default:
+			redacted[name] = value
+		}
+	}
+	return redacted
+}
+
+// Values возвращает значения полей записи для ответа API v1: значения полей KindList - списками JSON,
+// остальные - строками, как их передавали клиенты до появления списков
+func (t *Type) Values(data domain.ItemData) map[string]interface{} {
+	values := make(map[string]interface{}, len(data))
+	for name, value := range data {
+		if field, ok := t.Field(name); ok && field.Kind == KindList && json.Valid([]byte(value)) {
+			values[name] = json.RawMessage(value)
+			continue
+		}
+		values[name] = value
+	}
+	return values
+}
+
+// ParseValues разбирает значения полей записи из запроса API v1, обратно Values. Значения полей KindList
+// принимаются списками JSON или их текстом, значения остальных полей должны быть строками
+func (t *Type) ParseValues(values map[string]json.RawMessage) (domain.ItemData, error) {
+	data := make(domain.ItemData, len(values))
+	for name, raw := range values {
+		var value string
+		if err := json.Unmarshal(raw, &value); err == nil {
+			data[name] = value
+			continue
+		}
+		field, ok := t.Field(name)
+		if !ok || field.Kind != KindList {
+			return nil, fmt.Errorf("%w: значение поля '%s' должно быть строкой", ErrInvalidItem, name)
+		}
+		var compact bytes.Buffer
+		if err := json.Compact(&compact, raw); err != nil {
+			return nil, fmt.Errorf("%w: поле '%s': %v", ErrInvalidItem, name, err)
+		}
+		if compact.String() != "null" {
+			data[name] = compact.String()
+		}
+	}
+	return data, nil
+}
+
+// Encode преобразует содержимое записи встроенного типа, например *domain.CredentialData, в значения полей.
+// Поля структуры сопоставляются полям типа по тегам json, списки записываются в значения полей текстом JSON
+func Encode(value interface{}) (domain.ItemData, error) {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка при маршалинге записи: %w", err)
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return nil, fmt.Errorf("ошибка при преобразовании записи: %w", err)
+	}
+	data := make(domain.ItemData, len(fields))
+	for name, field := range fields {
+		var value string
+		if err := json.Unmarshal(field, &value); err != nil {
+			value = string(field)
+		}
+		data[name] = value
+	}
+	return data, nil
+}
+
+// Decode заполняет содержимое записи встроенного типа значениями полей, обратно Encode.
+// Значения полей KindList передаются в value списками, остальные - строками
+func (t *Type) Decode(data domain.ItemData, value interface{}) error {
+	fields := make(map[string]json.RawMessage, len(data))
+	for name, v := range data {
+		if field, ok := t.Field(name); ok && field.Kind == KindList {
+			if v != "" {
+				fields[name] = json.RawMessage(v)
+			}
+			continue
+		}
+		raw, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Errorf("ошибка при маршалинге записи: %w", err)
+		}
+		fields[name] = raw
+	}
+	raw, err := json.Marshal(fields)
+	if err != nil {
+		return fmt.Errorf("ошибка при маршалинге записи: %w", err)
+	}
+	if err := json.Unmarshal(raw, value); err != nil {
+		return fmt.Errorf("ошибка при преобразовании записи: %w", err)
+	}
+	return nil
+}
+
+// validate проверяет непустое значение поля по его виду
+func (f Field) validate(value string) error {
+	limit := f.MaxLength
+	if limit == 0 {
+		limit = maxValueLength
+	}
+	if len(value) > limit {
+		return fmt.Errorf("значение длиннее %d байт", limit)
+	}
+	switch f.Kind {
+	case KindURL:
+		parsed, err := url.Parse(value)
+		if err != nil || parsed.Scheme == "" || parsed.Host == "" {
+			return errors.New("ожидается адрес со схемой, например https://example.com")
+		}
+	case KindDate:
+		if _, err := time.Parse(DateLayout, value); err != nil {
+			return errors.New("ожидается дата в формате ГГГГ-ММ-ДД")
+		}
+	case KindEnum:
+		for _, option := range f.Options {
+			if value == option {
+				return nil
+			}
+		}
+		return fmt.Errorf("допустимы значения %s", strings.Join(f.Options, ", "))
+	case KindList:
+		if !json.Valid([]byte(value)) || !strings.HasPrefix(strings.TrimSpace(value), "[") {
+			return errors.New("ожидается список JSON")
+		}
+	}
+	return nil
+}
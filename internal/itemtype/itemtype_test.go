@@ -0,0 +1,182 @@
+package itemtype
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/SmirnovND/gophkeeper/internal/domain"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// TestLookup тестирует поиск зарегистрированных типов
+func TestLookup(t *testing.T) {
+	for _, name := range []string{domain.UserDataTypeCredential, domain.UserDataTypeCard, domain.UserDataTypeText,
+		domain.UserDataTypeSSHKey, domain.UserDataTypeIdentity, domain.UserDataTypeBankAccount,
+		domain.UserDataTypeAPIToken, domain.UserDataTypeWiFi, domain.UserDataTypeLicense} {
+		itemType, err := Lookup(name)
+		if err != nil {
+			t.Fatalf("Тип %s не зарегистрирован: %v", name, err)
+		}
+		if _, ok := itemType.Field(itemType.Secret); !ok {
+			t.Errorf("У типа %s нет поля основного секрета %s", name, itemType.Secret)
+		}
+	}
+	for _, name := range []string{domain.UserDataTypeFile, "passport"} {
+		if _, err := Lookup(name); !errors.Is(err, ErrUnknownType) {
+			t.Errorf("Ожидалась ошибка %v для %s, получено %v", ErrUnknownType, name, err)
+		}
+	}
+	if types := Types(); len(types) != 9 || types[0].Name != domain.UserDataTypeAPIToken {
+		t.Errorf("Неверный список типов: %v", types)
+	}
+}
+
+// TestType_Validate тестирует проверку полей по их виду и проверки типов
+func TestType_Validate(t *testing.T) {
+	tests := []struct {
+		name     string
+		itemType string
+		data     domain.ItemData
+		valid    bool
+	}{
+		{"токен", domain.UserDataTypeAPIToken, domain.ItemData{"service": "GitHub", "token": "ghp_x", "url": "https://api.github.com", "expires_at": "2027-01-31"}, true},
+		{"неизвестное поле", domain.UserDataTypeAPIToken, domain.ItemData{"service": "GitHub", "token": "ghp_x", "owner": "alice"}, false},
+		{"нет обязательного поля", domain.UserDataTypeAPIToken, domain.ItemData{"service": "GitHub"}, false},
+		{"неверная дата", domain.UserDataTypeAPIToken, domain.ItemData{"service": "GitHub", "token": "x", "expires_at": "31.01.2027"}, false},
+		{"адрес без схемы", domain.UserDataTypeAPIToken, domain.ItemData{"service": "GitHub", "token": "x", "url": "api.github.com"}, false},
+		{"документ", domain.UserDataTypeIdentity, domain.ItemData{"document_type": "passport", "number": "4509 123456", "full_name": "Алиса", "issue_date": "2020-01-01", "expiry_date": "2030-01-01"}, true},
+		{"неизвестный вид документа", domain.UserDataTypeIdentity, domain.ItemData{"document_type": "visa", "number": "1", "full_name": "Алиса"}, false},
+		{"выдан после окончания", domain.UserDataTypeIdentity, domain.ItemData{"document_type": "passport", "number": "1", "full_name": "Алиса", "issue_date": "2031-01-01", "expiry_date": "2030-01-01"}, false},
+		{"счет", domain.UserDataTypeBankAccount, domain.ItemData{"bank": "ING", "account_number": "1", "iban": "GB82 WEST 1234 5698 7654 32", "bic": "INGBNL2A"}, true},
+		{"неверный IBAN", domain.UserDataTypeBankAccount, domain.ItemData{"bank": "ING", "account_number": "1", "iban": "GB83WEST12345698765432"}, false},
+		{"неверный BIC", domain.UserDataTypeBankAccount, domain.ItemData{"bank": "ING", "account_number": "1", "bic": "ING"}, false},
+		{"сеть", domain.UserDataTypeWiFi, domain.ItemData{"ssid": "home", "security": SecurityWPA2, "password": "hunter22"}, true},
+		{"открытая сеть", domain.UserDataTypeWiFi, domain.ItemData{"ssid": "cafe", "security": SecurityOpen}, true},
+		{"защищенная сеть без пароля", domain.UserDataTypeWiFi, domain.ItemData{"ssid": "home", "security": SecurityWPA3}, false},
+		{"открытая сеть с паролем", domain.UserDataTypeWiFi, domain.ItemData{"ssid": "cafe", "security": SecurityOpen, "password": "x"}, false},
+		{"учетные данные", domain.UserDataTypeCredential, domain.ItemData{"login": "alice", "password": "secret"}, true},
+		{"неверный ключ TOTP", domain.UserDataTypeCredential, domain.ItemData{"login": "alice", "totp": "otpauth://hotp/x"}, false},
+		{"адреса и поля", domain.UserDataTypeCredential, domain.ItemData{"login": "alice", "uris": `[{"uri":"github.com"},{"uri":"https://git.corp:8443","match":"host"}]`, "fields": `[{"name":"pin","type":"hidden","value":"1234"},{"name":"2fa","type":"boolean","value":"true"}]`}, true},
+		{"адреса не списком", domain.UserDataTypeCredential, domain.ItemData{"login": "alice", "uris": `{"uri":"github.com"}`}, false},
+		{"неизвестное правило адреса", domain.UserDataTypeCredential, domain.ItemData{"login": "alice", "uris": `[{"uri":"github.com","match":"wildcard"}]`}, false},
+		{"повтор дополнительного поля", domain.UserDataTypeCredential, domain.ItemData{"login": "alice", "fields": `[{"name":"pin","type":"text"},{"name":"pin","type":"hidden"}]`}, false},
+		{"неизвестный вид поля", domain.UserDataTypeCredential, domain.ItemData{"login": "alice", "fields": `[{"name":"pin","type":"secret"}]`}, false},
+		{"история паролей", domain.UserDataTypeCredential, domain.ItemData{"login": "alice", "password_history": `[{"password":"old","changed_at":"2026-10-01T12:00:00Z"}]`}, true},
+		{"пароль в истории без времени смены", domain.UserDataTypeCredential, domain.ItemData{"login": "alice", "password_history": `[{"password":"old"}]`}, false},
+		{"неверный флаг", domain.UserDataTypeCredential, domain.ItemData{"login": "alice", "fields": `[{"name":"2fa","type":"boolean","value":"yes"}]`}, false},
+		{"ключ SSH без открытого ключа", domain.UserDataTypeSSHKey, domain.ItemData{"private_key": "x"}, false},
+		{"лицензия", domain.UserDataTypeLicense, domain.ItemData{"product": "IDE", "license_key": "AAAA-BBBB", "purchase_date": "2024-05-01"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			itemType, _ := Lookup(tt.itemType)
+			err := itemType.Validate(tt.data)
+			if tt.valid && err != nil {
+				t.Errorf("Неожиданная ошибка: %v", err)
+			}
+			if !tt.valid && !errors.Is(err, ErrInvalidItem) {
+				t.Errorf("Ожидалась ошибка %v, получено %v", ErrInvalidItem, err)
+			}
+		})
+	}
+}
+
+// TestType_ExpiresAt тестирует получение срока действия записи
+func TestType_ExpiresAt(t *testing.T) {
+	itemType, _ := Lookup(domain.UserDataTypeAPIToken)
+	date, ok := itemType.ExpiresAt(domain.ItemData{"expires_at": "2027-01-31"})
+	if !ok || !date.Equal(time.Date(2027, 1, 31, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Неверный срок действия: %v, %v", date, ok)
+	}
+	if _, ok := itemType.ExpiresAt(domain.ItemData{}); ok {
+		t.Error("Без даты срок действия не должен возвращаться")
+	}
+
+	wifi, _ := Lookup(domain.UserDataTypeWiFi)
+	if _, ok := wifi.ExpiresAt(domain.ItemData{"ssid": "home"}); ok {
+		t.Error("У сети Wi-Fi нет срока действия")
+	}
+}
+
+// TestType_Redact тестирует скрытие секретных полей и частичное скрытие номера карты
+func TestType_Redact(t *testing.T) {
+	card, _ := Lookup(domain.UserDataTypeCard)
+	data := domain.ItemData{"number": "4111 1111 1111 1234", "holder": "ALICE", "cvv": "123"}
+	redacted := card.Redact(data)
+	if redacted["number"] != "•••• 1234" || redacted["cvv"] != Mask || redacted["holder"] != "ALICE" {
+		t.Errorf("Неверно скрыта запись: %v", redacted)
+	}
+	if data["cvv"] != "123" {
+		t.Error("Исходная запись не должна меняться")
+	}
+
+	// Скрываются только значения дополнительных полей вида hidden
+	credential, _ := Lookup(domain.UserDataTypeCredential)
+	redacted = credential.Redact(domain.ItemData{"fields": `[{"name":"pin","type":"hidden","value":"1234"},{"name":"note","type":"text","value":"x"}]`})
+	if redacted["fields"] != `[{"name":"pin","type":"hidden","value":"`+Mask+`"},{"name":"note","type":"text","value":"x"}]` {
+		t.Errorf("Неверно скрыты дополнительные поля: %s", redacted["fields"])
+	}
+	redacted = credential.Redact(domain.ItemData{"password_history": `[{"password":"old","changed_at":"2026-10-01T12:00:00Z"}]`})
+	if redacted["password_history"] != `[{"password":"`+Mask+`","changed_at":"2026-10-01T12:00:00Z"}]` {
+		t.Errorf("Неверно скрыта история паролей: %s", redacted["password_history"])
+	}
+	if legacy := card.LegacyKey(); legacy != "card_data" {
+		t.Errorf("Неверный ключ API v1: %s", legacy)
+	}
+	wifi, _ := Lookup(domain.UserDataTypeWiFi)
+	if legacy := wifi.LegacyKey(); legacy != "item_data" {
+		t.Errorf("Неверный ключ API v1: %s", legacy)
+	}
+}
+
+// TestEncode тестирует преобразование содержимого встроенного типа в значения полей и обратно
+func TestEncode(t *testing.T) {
+	credential := &domain.CredentialData{
+		Login:    "alice",
+		Password: "secret",
+		URIs:     []domain.CredentialURI{{URI: "github.com"}},
+		Fields:   []domain.CustomField{{Name: "pin", Type: domain.CustomFieldHidden, Value: "1234"}},
+	}
+	data, err := Encode(credential)
+	if err != nil {
+		t.Fatalf("Неожиданная ошибка: %v", err)
+	}
+	if len(data) != 4 || data["login"] != "alice" || data["password"] != "secret" || data["uris"] != `[{"uri":"github.com"}]` {
+		t.Errorf("Неверные значения полей: %v", data)
+	}
+
+	credentialType, _ := Lookup(domain.UserDataTypeCredential)
+	var decoded domain.CredentialData
+	if err := credentialType.Decode(data, &decoded); err != nil || !reflect.DeepEqual(decoded, *credential) {
+		t.Errorf("Ожидалось %+v, получено %+v, %v", *credential, decoded, err)
+	}
+}
+
+// TestType_ParseValues тестирует разбор и вывод полей-списков в представлении API v1
+func TestType_ParseValues(t *testing.T) {
+	credential, _ := Lookup(domain.UserDataTypeCredential)
+	values := map[string]json.RawMessage{
+		"login":  json.RawMessage(`"alice"`),
+		"uris":   json.RawMessage(`[ {"uri": "https://github.com"} ]`),
+		"fields": json.RawMessage(`"[{\"name\":\"pin\",\"type\":\"hidden\",\"value\":\"1234\"}]"`),
+	}
+	data, err := credential.ParseValues(values)
+	if err != nil {
+		t.Fatalf("Неожиданная ошибка: %v", err)
+	}
+	want := domain.ItemData{"login": "alice", "uris": `[{"uri":"https://github.com"}]`,
+		"fields": `[{"name":"pin","type":"hidden","value":"1234"}]`}
+	if !reflect.DeepEqual(data, want) {
+		t.Errorf("Ожидалось %v, получено %v", want, data)
+	}
+
+	raw, _ := json.Marshal(credential.Values(data))
+	if string(raw) != `{"fields":[{"name":"pin","type":"hidden","value":"1234"}],"login":"alice","uris":[{"uri":"https://github.com"}]}` {
+		t.Errorf("Неверные значения полей: %s", raw)
+	}
+
+	if _, err := credential.ParseValues(map[string]json.RawMessage{"login": json.RawMessage(`["alice"]`)}); !errors.Is(err, ErrInvalidItem) {
+		t.Errorf("Для списка в строковом поле ожидалась ошибка %v, получено %v", ErrInvalidItem, err)
+	}
+}
@@ -0,0 +1,202 @@
+package itemtype
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/SmirnovND/gophkeeper/internal/domain"
+	"regexp"
+)
+
+// Ограничения шаблонов пользовательских типов
+const (
+	// MaxSchemaSize - максимальный размер схемы шаблона в байтах
+	MaxSchemaSize = 64 << 10
+	// maxSchemaFields - максимальное количество полей пользовательского типа
+	maxSchemaFields = 64
+)
+
+// ErrInvalidSchema - схема шаблона не соответствует поддерживаемому подмножеству JSON Schema
+var ErrInvalidSchema = errors.New("неверная схема шаблона")
+
+// namePattern - допустимые имена пользовательских типов и их полей
+var namePattern = regexp.MustCompile(`^[a-z][a-z0-9_]{0,31}$`)
+
+// schemaDocument - поддерживаемые ключевые слова схемы шаблона. Остальные ключевые слова отклоняются,
+// чтобы ограничение схемы, которое сервер не проверяет, не осталось незамеченным
+type schemaDocument struct {
+	Schema               string          `json:"$schema"`
+	Title                string          `json:"title"`
+	Description          string          `json:"description"`
+	Type                 string          `json:"type"`
+	Properties           json.RawMessage `json:"properties"`
+	Required             []string        `json:"required"`
+	AdditionalProperties *bool           `json:"additionalProperties"`
+}
+
+// schemaProperty - поддерживаемые ключевые слова поля схемы. Вид поля определяется так:
+// enum - KindEnum, writeOnly - KindSecret, format uri - KindURL, format date - KindDate, иначе KindText
+type schemaProperty struct {
+	Type        string   `json:"type"`
+	Title       string   `json:"title"`
+	Description string   `json:"description"`
+	Format      string   `json:"format"`
+	Enum        []string `json:"enum"`
+	WriteOnly   bool     `json:"writeOnly"`
+	MaxLength   int      `json:"maxLength"`
+}
+
+// ParseSchema строит пользовательский тип name по схеме шаблона - подмножеству JSON Schema:
+// объект со строковыми полями в properties, списком обязательных полей required и без дополнительных полей.
+// Поля типа следуют в порядке properties. Основной секрет типа - первое секретное поле или первое поле
+func ParseSchema(name string, schema []byte) (*Type, error) {
+	if !namePattern.MatchString(name) {
+		return nil, fmt.Errorf("%w: имя типа '%s' должно начинаться с латинской буквы и содержать "+
+			"только строчные латинские буквы, цифры и '_', не длиннее 32 символов", ErrInvalidSchema, name)
+	}
+	if _, err := Lookup(name); err == nil || name == domain.UserDataTypeFile {
+		return nil, fmt.Errorf("%w: имя '%s' занято встроенным типом", ErrInvalidSchema, name)
+	}
+	if len(schema) > MaxSchemaSize {
+		return nil, fmt.Errorf("%w: схема больше %d байт", ErrInvalidSchema, MaxSchemaSize)
+	}
+
+	var document schemaDocument
+	if err := decodeStrict(schema, &document); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSchema, err)
+	}
+	if document.Type != "object" {
+		return nil, fmt.Errorf("%w: ожидается \"type\": \"object\"", ErrInvalidSchema)
+	}
+	if document.AdditionalProperties != nil && *document.AdditionalProperties {
+		return nil, fmt.Errorf("%w: дополнительные поля не поддерживаются, уберите \"additionalProperties\": true", ErrInvalidSchema)
+	}
+
+	names, properties, err := decodeProperties(document.Properties)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSchema, err)
+	}
+	if len(names) == 0 {
+		return nil, fmt.Errorf("%w: в properties нет полей", ErrInvalidSchema)
+	}
+	if len(names) > maxSchemaFields {
+		return nil, fmt.Errorf("%w: полей больше %d", ErrInvalidSchema, maxSchemaFields)
+	}
+
+	t := &Type{Name: name, Title: document.Title, Custom: true}
+	if t.Title == "" {
+		t.Title = name
+	}
+	for _, fieldName := range names {
+		field, err := properties[fieldName].field(fieldName)
+		if err != nil {
+			return nil, fmt.Errorf("%w: поле '%s': %v", ErrInvalidSchema, fieldName, err)
+		}
+		if t.Secret == "" && field.Kind == KindSecret {
+			t.Secret = field.Name
+		}
+		t.Fields = append(t.Fields, field)
+	}
+	if t.Secret == "" {
+		t.Secret = t.Fields[0].Name
+	}
+
+	for _, required := range document.Required {
+		if _, ok := properties[required]; !ok {
+			return nil, fmt.Errorf("%w: обязательного поля '%s' нет в properties", ErrInvalidSchema, required)
+		}
+		for i := range t.Fields {
+			if t.Fields[i].Name == required {
+				t.Fields[i].Required = true
+			}
+		}
+	}
+	return t, nil
+}
+
+// field строит поле типа по описанию поля схемы
+func (p schemaProperty) field(name string) (Field, error) {
+	if !namePattern.MatchString(name) {
+		return Field{}, errors.New("имя поля должно начинаться с латинской буквы и содержать только строчные " +
+			"латинские буквы, цифры и '_', не длиннее 32 символов")
+	}
+	if p.Type != "string" {
+		return Field{}, errors.New("поддерживаются только поля \"type\": \"string\"")
+	}
+	if p.MaxLength < 0 || p.MaxLength > maxValueLength {
+		return Field{}, fmt.Errorf("maxLength должен быть от 0 до %d, 0 - длина по умолчанию", maxValueLength)
+	}
+
+	field := Field{Name: name, Title: p.Title, Kind: KindText, MaxLength: p.MaxLength}
+	if field.Title == "" {
+		field.Title = name
+	}
+	switch {
+	case len(p.Enum) > 0:
+		if p.WriteOnly || p.Format != "" {
+			return Field{}, errors.New("поле enum не может быть секретным или иметь format")
+		}
+		field.Kind, field.Options = KindEnum, p.Enum
+	case p.WriteOnly:
+		if p.Format != "" {
+			return Field{}, errors.New("секретное поле не может иметь format")
+		}
+		field.Kind = KindSecret
+	case p.Format == "uri":
+		field.Kind = KindURL
+	case p.Format == "date":
+		field.Kind = KindDate
+	case p.Format != "":
+		return Field{}, fmt.Errorf("неподдерживаемый format '%s', допустимы uri и date", p.Format)
+	}
+	return field, nil
+}
+
+// decodeProperties разбирает properties схемы и возвращает имена полей в порядке их следования в схеме
+func decodeProperties(raw json.RawMessage) ([]string, map[string]schemaProperty, error) {
+	if len(raw) == 0 {
+		return nil, nil, errors.New("не заданы properties")
+	}
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	if token, err := decoder.Token(); err != nil || token != json.Delim('{') {
+		return nil, nil, errors.New("properties должен быть объектом")
+	}
+
+	names := make([]string, 0)
+	properties := make(map[string]schemaProperty)
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, nil, err
+		}
+		name := token.(string)
+		if _, ok := properties[name]; ok {
+			return nil, nil, fmt.Errorf("поле '%s' описано дважды", name)
+		}
+		var value json.RawMessage
+		if err := decoder.Decode(&value); err != nil {
+			return nil, nil, err
+		}
+		var property schemaProperty
+		if err := decodeStrict(value, &property); err != nil {
+			return nil, nil, fmt.Errorf("поле '%s': %v", name, err)
+		}
+		names = append(names, name)
+		properties[name] = property
+	}
+	return names, properties, nil
+}
+
+// decodeStrict разбирает JSON и отклоняет неизвестные ключевые слова
+func decodeStrict(data []byte, value interface{}) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(value); err != nil {
+		return err
+	}
+	if decoder.More() {
+		return errors.New("лишние данные после схемы")
+	}
+	return nil
+}
@@ -0,0 +1,146 @@
+package itemtype
+
+import (
+	"errors"
+	"fmt"
+	"github.com/SmirnovND/gophkeeper/internal/domain"
+	"strings"
+	"testing"
+)
+
+// dbProfileSchema - схема профиля подключения к базе данных из примера в README
+const dbProfileSchema = `{
+	"$schema": "https://json-schema.org/draft/2020-12/schema",
+	"title": "Подключение к БД",
+	"type": "object",
+	"properties": {
+		"host": {"type": "string", "title": "Хост"},
+		"port": {"type": "string", "title": "Порт", "maxLength": 5},
+		"user": {"type": "string", "title": "Пользователь"},
+		"password": {"type": "string", "title": "Пароль", "writeOnly": true},
+		"ssl_mode": {"type": "string", "title": "Режим SSL", "enum": ["disable", "require", "verify-full"]},
+		"console": {"type": "string", "format": "uri"},
+		"rotated_at": {"type": "string", "format": "date"}
+	},
+	"required": ["host", "user", "password"],
+	"additionalProperties": false
+}`
+
+func TestParseSchema(t *testing.T) {
+	dbProfile, err := ParseSchema("db_profile", []byte(dbProfileSchema))
+	if err != nil {
+		t.Fatalf("Ошибка при разборе схемы: %v", err)
+	}
+	if dbProfile.Title != "Подключение к БД" || dbProfile.Secret != "password" || !dbProfile.Custom || dbProfile.Builtin {
+		t.Errorf("Неверное описание типа: %+v", dbProfile)
+	}
+
+	// Поля следуют в порядке properties
+	want := []struct {
+		name     string
+		kind     string
+		required bool
+	}{
+		{"host", KindText, true},
+		{"port", KindText, false},
+		{"user", KindText, true},
+		{"password", KindSecret, true},
+		{"ssl_mode", KindEnum, false},
+		{"console", KindURL, false},
+		{"rotated_at", KindDate, false},
+	}
+	if len(dbProfile.Fields) != len(want) {
+		t.Fatalf("Ожидалось %d полей, получено %d", len(want), len(dbProfile.Fields))
+	}
+	for i, field := range dbProfile.Fields {
+		if field.Name != want[i].name || field.Kind != want[i].kind || field.Required != want[i].required {
+			t.Errorf("Поле %d: ожидалось %+v, получено %+v", i, want[i], field)
+		}
+	}
+	if field, _ := dbProfile.Field("console"); field.Title != "console" {
+		t.Errorf("Без title название поля совпадает с именем, получено '%s'", field.Title)
+	}
+
+	tests := []struct {
+		name  string
+		data  domain.ItemData
+		valid bool
+	}{
+		{"профиль", domain.ItemData{"host": "db.local", "port": "5432", "user": "app", "password": "s3cret", "ssl_mode": "require"}, true},
+		{"нет пароля", domain.ItemData{"host": "db.local", "user": "app"}, false},
+		{"неизвестный режим SSL", domain.ItemData{"host": "db.local", "user": "app", "password": "x", "ssl_mode": "prefer"}, false},
+		{"слишком длинный порт", domain.ItemData{"host": "db.local", "user": "app", "password": "x", "port": "543210"}, false},
+		{"адрес без схемы", domain.ItemData{"host": "db.local", "user": "app", "password": "x", "console": "db.local"}, false},
+		{"лишнее поле", domain.ItemData{"host": "db.local", "user": "app", "password": "x", "database": "app"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := dbProfile.Validate(tt.data)
+			if tt.valid && err != nil {
+				t.Errorf("Ожидалась верная запись, получено %v", err)
+			}
+			if !tt.valid && !errors.Is(err, ErrInvalidItem) {
+				t.Errorf("Ожидалась ошибка %v, получено %v", ErrInvalidItem, err)
+			}
+		})
+	}
+
+	// Без секретных полей основным секретом считается первое поле
+	plain, err := ParseSchema("plain", []byte(`{"type": "object", "properties": {"value": {"type": "string"}}}`))
+	if err != nil || plain.Secret != "value" || plain.Title != "plain" {
+		t.Errorf("Неверное описание типа без секретов: %+v, %v", plain, err)
+	}
+}
+
+func TestParseSchema_Invalid(t *testing.T) {
+	tests := []struct {
+		name     string
+		typeName string
+		schema   string
+	}{
+		{"имя встроенного типа", "wifi", `{"type": "object", "properties": {"ssid": {"type": "string"}}}`},
+		{"имя file", "file", `{"type": "object", "properties": {"name": {"type": "string"}}}`},
+		{"неверное имя", "DB-profile", `{"type": "object", "properties": {"host": {"type": "string"}}}`},
+		{"неверный JSON", "db", `{"type": "object",`},
+		{"не объект", "db", `{"type": "string"}`},
+		{"нет полей", "db", `{"type": "object", "properties": {}}`},
+		{"дополнительные поля", "db", `{"type": "object", "properties": {"host": {"type": "string"}}, "additionalProperties": true}`},
+		{"неподдерживаемое ключевое слово", "db", `{"type": "object", "properties": {"host": {"type": "string"}}, "allOf": []}`},
+		{"неподдерживаемое ключевое слово поля", "db", `{"type": "object", "properties": {"port": {"type": "string", "pattern": "^[0-9]+$"}}}`},
+		{"поле не строка", "db", `{"type": "object", "properties": {"port": {"type": "integer"}}}`},
+		{"неизвестный format", "db", `{"type": "object", "properties": {"mail": {"type": "string", "format": "email"}}}`},
+		{"секретное поле с format", "db", `{"type": "object", "properties": {"url": {"type": "string", "format": "uri", "writeOnly": true}}}`},
+		{"неверное имя поля", "db", `{"type": "object", "properties": {"Host Name": {"type": "string"}}}`},
+		{"поле описано дважды", "db", `{"type": "object", "properties": {"host": {"type": "string"}, "host": {"type": "string"}}}`},
+		{"неизвестное обязательное поле", "db", `{"type": "object", "properties": {"host": {"type": "string"}}, "required": ["port"]}`},
+		{"отрицательный maxLength", "db", `{"type": "object", "properties": {"host": {"type": "string", "maxLength": -1}}}`},
+		{"maxLength больше допустимого", "db", `{"type": "object", "properties": {"host": {"type": "string", "maxLength": 8193}}}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseSchema(tt.typeName, []byte(tt.schema)); !errors.Is(err, ErrInvalidSchema) {
+				t.Errorf("Ожидалась ошибка %v, получено %v", ErrInvalidSchema, err)
+			}
+		})
+	}
+}
+
+// TestParseSchema_MaxLength тестирует граничные значения maxLength: 0 означает длину по умолчанию
+func TestParseSchema_MaxLength(t *testing.T) {
+	for _, maxLength := range []int{0, 1, maxValueLength} {
+		schema := fmt.Sprintf(`{"type": "object", "properties": {"host": {"type": "string", "maxLength": %d}}}`, maxLength)
+		parsed, err := ParseSchema("db", []byte(schema))
+		if err != nil {
+			t.Errorf("Неожиданная ошибка для maxLength %d: %v", maxLength, err)
+			continue
+		}
+		if parsed.Fields[0].MaxLength != maxLength {
+			t.Errorf("Ожидалось maxLength %d, получено %d", maxLength, parsed.Fields[0].MaxLength)
+		}
+	}
+
+	_, err := ParseSchema("db", []byte(`{"type": "object", "properties": {"host": {"type": "string", "maxLength": 8193}}}`))
+	if err == nil || !strings.Contains(err.Error(), "от 0 до 8192") {
+		t.Errorf("Ожидалась ошибка с допустимым диапазоном, получено %v", err)
+	}
+}
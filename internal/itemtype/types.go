@@ -0,0 +1,365 @@
+package itemtype
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/SmirnovND/gophkeeper/internal/domain"
+	"github.com/SmirnovND/gophkeeper/internal/sshkey"
+	"github.com/SmirnovND/gophkeeper/internal/totp"
+	"github.com/SmirnovND/gophkeeper/internal/urimatch"
+	"math/big"
+	"regexp"
+	"strings"
+)
+
+// Режимы защиты сети Wi-Fi
+const (
+	SecurityWPA3 = "WPA3"
+	SecurityWPA2 = "WPA2"
+	SecurityWPA  = "WPA"
+	SecurityWEP  = "WEP"
+	SecurityOpen = "open"
+)
+
+var (
+	// ibanPattern - код страны, контрольные цифры и номер счета из латинских букв и цифр
+	ibanPattern = regexp.MustCompile(`^[A-Z]{2}[0-9]{2}[A-Z0-9]{11,30}$`)
+	// bicPattern - код банка, страны, города и необязательный код филиала
+	bicPattern = regexp.MustCompile(`^[A-Z]{6}[A-Z0-9]{2}([A-Z0-9]{3})?$`)
+)
+
+// Ограничения значений полей встроенных типов
+const (
+	// maxTextLength - максимальная длина произвольного текста в байтах
+	maxTextLength = 1 << 20
+	// maxListItems - максимальное количество адресов и дополнительных полей учетных данных
+	maxListItems = 100
+	// maxFieldNameLength - максимальная длина имени дополнительного поля в байтах
+	maxFieldNameLength = 64
+)
+
+func init() {
+	// Встроенные типы хранятся в формате структур domain.CredentialData, domain.CardData, domain.TextData
+	// и domain.SSHKeyData, поэтому имена полей совпадают с их тегами json
+	register(&Type{
+		Name:  domain.UserDataTypeCredential,
+		Title: "учетные данные",
+		Fields: []Field{
+			{Name: "login", Title: "Логин", Kind: KindText},
+			{Name: "password", Title: "Пароль", Kind: KindSecret},
+			{Name: "totp", Title: "Ключ TOTP", Kind: KindSecret},
+			{Name: "uris", Title: "Адреса", Kind: KindList},
+			{Name: "fields", Title: "Дополнительные поля", Kind: KindList, redact: redactCustomFields},
+			{Name: "password_history", Title: "История паролей", Kind: KindList, redact: redactPasswordHistory},
+		},
+		Secret:  "password",
+		Builtin: true,
+		Legacy:  "credential_data",
+		check:   checkCredential,
+	})
+
+	register(&Type{
+		Name:  domain.UserDataTypeCard,
+		Title: "банковская карта",
+		Fields: []Field{
+			{Name: "number", Title: "Номер", Kind: KindSecret, redact: redactCardNumber},
+			{Name: "holder", Title: "Владелец", Kind: KindText},
+			{Name: "expiry_date", Title: "Срок действия", Kind: KindText},
+			{Name: "cvv", Title: "CVV", Kind: KindSecret},
+		},
+		Secret:  "number",
+		Builtin: true,
+		Legacy:  "card_data",
+	})
+
+	register(&Type{
+		Name:  domain.UserDataTypeText,
+		Title: "текст",
+		Fields: []Field{
+			{Name: "content", Title: "Текст", Kind: KindSecret, MaxLength: maxTextLength},
+		},
+		Secret:  "content",
+		Builtin: true,
+		Legacy:  "text_data",
+	})
+
+	register(&Type{
+		Name:  domain.UserDataTypeSSHKey,
+		Title: "ключ SSH",
+		Fields: []Field{
+			{Name: "private_key", Title: "Закрытый ключ", Kind: KindSecret, Required: true},
+			{Name: "public_key", Title: "Открытый ключ", Kind: KindText, Required: true},
+			{Name: "fingerprint", Title: "Отпечаток", Kind: KindText, Required: true},
+			{Name: "comment", Title: "Комментарий", Kind: KindText},
+		},
+		Secret:  "private_key",
+		Builtin: true,
+		Legacy:  "ssh_key_data",
+		check:   checkSSHKey,
+	})
+
+	register(&Type{
+		Name:  domain.UserDataTypeIdentity,
+		Title: "документ",
+		Fields: []Field{
+			{Name: "document_type", Title: "Вид документа", Kind: KindEnum, Required: true,
+				Options: []string{"passport", "id_card", "driver_license", "residence_permit", "other"}},
+			{Name: "number", Title: "Номер", Kind: KindSecret, Required: true},
+			{Name: "full_name", Title: "Имя владельца", Kind: KindText, Required: true},
+			{Name: "birth_date", Title: "Дата рождения", Kind: KindDate},
+			{Name: "country", Title: "Страна", Kind: KindText},
+			{Name: "issued_by", Title: "Кем выдан", Kind: KindText},
+			{Name: "issue_date", Title: "Дата выдачи", Kind: KindDate},
+			{Name: "expiry_date", Title: "Действителен до", Kind: KindDate},
+		},
+		Secret: "number",
+		Expiry: "expiry_date",
+		check:  checkDateOrder("issue_date", "expiry_date"),
+	})
+
+	register(&Type{
+		Name:  domain.UserDataTypeBankAccount,
+		Title: "банковский счет",
+		Fields: []Field{
+			{Name: "bank", Title: "Банк", Kind: KindText, Required: true},
+			{Name: "holder", Title: "Владелец счета", Kind: KindText},
+			{Name: "account_number", Title: "Номер счета", Kind: KindSecret, Required: true},
+			{Name: "iban", Title: "IBAN", Kind: KindSecret},
+			{Name: "bic", Title: "BIC/SWIFT", Kind: KindText},
+			{Name: "routing_number", Title: "Код банка", Kind: KindText},
+		},
+		Secret: "account_number",
+		check:  checkBankAccount,
+	})
+
+	register(&Type{
+		Name:  domain.UserDataTypeAPIToken,
+		Title: "токен API",
+		Fields: []Field{
+			{Name: "service", Title: "Сервис", Kind: KindText, Required: true},
+			{Name: "token", Title: "Токен", Kind: KindSecret, Required: true},
+			{Name: "url", Title: "Адрес API", Kind: KindURL},
+			{Name: "scopes", Title: "Права", Kind: KindText},
+			{Name: "expires_at", Title: "Действует до", Kind: KindDate},
+		},
+		Secret: "token",
+		Expiry: "expires_at",
+	})
+
+	register(&Type{
+		Name:  domain.UserDataTypeWiFi,
+		Title: "сеть Wi-Fi",
+		Fields: []Field{
+			{Name: "ssid", Title: "Имя сети (SSID)", Kind: KindText, Required: true},
+			{Name: "security", Title: "Защита", Kind: KindEnum, Required: true,
+				Options: []string{SecurityWPA3, SecurityWPA2, SecurityWPA, SecurityWEP, SecurityOpen}},
+			{Name: "password", Title: "Пароль", Kind: KindSecret},
+		},
+		Secret: "password",
+		check:  checkWiFi,
+	})
+
+	register(&Type{
+		Name:  domain.UserDataTypeLicense,
+		Title: "лицензия",
+		Fields: []Field{
+			{Name: "product", Title: "Продукт", Kind: KindText, Required: true},
+			{Name: "license_key", Title: "Лицензионный ключ", Kind: KindSecret, Required: true},
+			{Name: "version", Title: "Версия", Kind: KindText},
+			{Name: "licensee", Title: "Владелец лицензии", Kind: KindText},
+			{Name: "email", Title: "Email регистрации", Kind: KindText},
+			{Name: "purchase_date", Title: "Дата покупки", Kind: KindDate},
+			{Name: "expiry_date", Title: "Действует до", Kind: KindDate},
+			{Name: "url", Title: "Страница продукта", Kind: KindURL},
+		},
+		Secret: "license_key",
+		Expiry: "expiry_date",
+		check:  checkDateOrder("purchase_date", "expiry_date"),
+	})
+}
+
+// checkCredential проверяет ключ TOTP, если он задан
+func checkCredential(data domain.ItemData) error {
+	if data["totp"] != "" {
+		if _, err := totp.Parse(data["totp"]); err != nil {
+			return err
+		}
+	}
+	if data["uris"] != "" {
+		var uris []domain.CredentialURI
+		if err := decodeStrict([]byte(data["uris"]), &uris); err != nil {
+			return fmt.Errorf("поле 'uris': %v", err)
+		}
+		if len(uris) > maxListItems {
+			return fmt.Errorf("адресов больше %d", maxListItems)
+		}
+		for _, uri := range uris {
+			if err := urimatch.Validate(uri); err != nil {
+				return err
+			}
+		}
+	}
+	if data["fields"] != "" {
+		var fields []domain.CustomField
+		if err := decodeStrict([]byte(data["fields"]), &fields); err != nil {
+			return fmt.Errorf("поле 'fields': %v", err)
+		}
+		if err := checkCustomFields(fields); err != nil {
+			return err
+		}
+	}
+	if data["password_history"] != "" {
+		var history []domain.PasswordChange
+		if err := decodeStrict([]byte(data["password_history"]), &history); err != nil {
+			return fmt.Errorf("поле 'password_history': %v", err)
+		}
+		return checkPasswordHistory(history)
+	}
+	return nil
+}
+
+// checkPasswordHistory проверяет историю паролей: не длиннее domain.MaxPasswordHistory, у каждого пароля
+// задано время смены
+func checkPasswordHistory(history []domain.PasswordChange) error {
+	if len(history) > domain.MaxPasswordHistory {
+		return fmt.Errorf("в истории больше %d паролей", domain.MaxPasswordHistory)
+	}
+	for i, change := range history {
+		switch {
+		case change.Password == "" || len(change.Password) > maxValueLength:
+			return fmt.Errorf("пароль %d в истории должен быть непустым и не длиннее %d байт", i+1, maxValueLength)
+		case change.ChangedAt.IsZero():
+			return fmt.Errorf("у пароля %d в истории не задано время смены", i+1)
+		}
+	}
+	return nil
+}
+
+// redactPasswordHistory скрывает прежние пароли, оставляя время их смены
+func redactPasswordHistory(value string) string {
+	var history []domain.PasswordChange
+	if err := json.Unmarshal([]byte(value), &history); err != nil {
+		return Mask
+	}
+	for i := range history {
+		history[i].Password = Mask
+	}
+	redacted, err := json.Marshal(history)
+	if err != nil {
+		return Mask
+	}
+	return string(redacted)
+}
+
+// checkCustomFields проверяет дополнительные поля учетных данных: имена заполнены и не повторяются,
+// вид поля известен, а значение флага - true или false
+func checkCustomFields(fields []domain.CustomField) error {
+	if len(fields) > maxListItems {
+		return fmt.Errorf("дополнительных полей больше %d", maxListItems)
+	}
+	names := make(map[string]bool, len(fields))
+	for _, field := range fields {
+		switch {
+		case strings.TrimSpace(field.Name) == "" || len(field.Name) > maxFieldNameLength:
+			return fmt.Errorf("имя дополнительного поля должно быть непустым и не длиннее %d байт", maxFieldNameLength)
+		case names[field.Name]:
+			return fmt.Errorf("дополнительное поле '%s' задано дважды", field.Name)
+		case field.Type != domain.CustomFieldText && field.Type != domain.CustomFieldHidden && field.Type != domain.CustomFieldBoolean:
+			return fmt.Errorf("неизвестный вид '%s' дополнительного поля '%s', допустимы %s, %s и %s", field.Type, field.Name,
+				domain.CustomFieldText, domain.CustomFieldHidden, domain.CustomFieldBoolean)
+		case field.Type == domain.CustomFieldBoolean && field.Value != "" && field.Value != "true" && field.Value != "false":
+			return fmt.Errorf("значение флага '%s' должно быть true или false", field.Name)
+		case len(field.Value) > maxValueLength:
+			return fmt.Errorf("значение дополнительного поля '%s' длиннее %d байт", field.Name, maxValueLength)
+		}
+		names[field.Name] = true
+	}
+	return nil
+}
+
+// redactCustomFields скрывает значения дополнительных полей вида hidden, остальные поля остаются видимыми
+func redactCustomFields(value string) string {
+	var fields []domain.CustomField
+	if err := json.Unmarshal([]byte(value), &fields); err != nil {
+		return Mask
+	}
+	for i := range fields {
+		if fields[i].Type == domain.CustomFieldHidden && fields[i].Value != "" {
+			fields[i].Value = Mask
+		}
+	}
+	redacted, err := json.Marshal(fields)
+	if err != nil {
+		return Mask
+	}
+	return string(redacted)
+}
+
+// checkSSHKey проверяет, что закрытый ключ записан в PEM, а открытый ключ и отпечаток соответствуют друг другу
+func checkSSHKey(data domain.ItemData) error {
+	return sshkey.Validate(&domain.SSHKeyData{PrivateKey: data["private_key"], PublicKey: data["public_key"],
+		Fingerprint: data["fingerprint"], Comment: data["comment"]})
+}
+
+// redactCardNumber оставляет видимыми последние четыре цифры номера карты
+func redactCardNumber(number string) string {
+	digits := []rune(strings.ReplaceAll(number, " ", ""))
+	if len(digits) <= 4 {
+		return Mask
+	}
+	return "•••• " + string(digits[len(digits)-4:])
+}
+
+// checkDateOrder возвращает проверку того, что дата поля from не позже даты поля to.
+// Формат дат уже проверен, поэтому строки ГГГГ-ММ-ДД сравниваются напрямую
+func checkDateOrder(from string, to string) func(data domain.ItemData) error {
+	return func(data domain.ItemData) error {
+		if data[from] != "" && data[to] != "" && data[from] > data[to] {
+			return fmt.Errorf("дата '%s' позже даты '%s'", from, to)
+		}
+		return nil
+	}
+}
+
+// checkBankAccount проверяет формат BIC и контрольные цифры IBAN по ISO 13616
+func checkBankAccount(data domain.ItemData) error {
+	if bic := data["bic"]; bic != "" && !bicPattern.MatchString(strings.ToUpper(bic)) {
+		return errors.New("BIC должен состоять из 8 или 11 латинских букв и цифр")
+	}
+	if iban := data["iban"]; iban != "" && !validIBAN(iban) {
+		return errors.New("неверный IBAN: не сходятся контрольные цифры или формат")
+	}
+	return nil
+}
+
+// validIBAN проверяет IBAN: четыре первых символа переносятся в конец, буквы заменяются числами 10-35,
+// а остаток от деления полученного числа на 97 должен быть равен 1. Пробелы допускаются
+func validIBAN(iban string) bool {
+	iban = strings.ToUpper(strings.Join(strings.Fields(iban), ""))
+	if !ibanPattern.MatchString(iban) {
+		return false
+	}
+
+	var digits strings.Builder
+	for _, r := range iban[4:] + iban[:4] {
+		if r >= 'A' && r <= 'Z' {
+			fmt.Fprintf(&digits, "%d", r-'A'+10)
+		} else {
+			digits.WriteRune(r)
+		}
+	}
+	number, ok := new(big.Int).SetString(digits.String(), 10)
+	return ok && new(big.Int).Mod(number, big.NewInt(97)).Int64() == 1
+}
+
+// checkWiFi проверяет, что пароль задан для защищенной сети и не задан для открытой
+func checkWiFi(data domain.ItemData) error {
+	switch {
+	case data["security"] == SecurityOpen && data["password"] != "":
+		return errors.New("у открытой сети не может быть пароля")
+	case data["security"] != SecurityOpen && data["password"] == "":
+		return fmt.Errorf("для сети с защитой %s нужен пароль", data["security"])
+	}
+	return nil
+}
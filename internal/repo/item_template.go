@@ -0,0 +1,96 @@
+package repo
+
+import (
+	"database/sql"
+	"fmt"
+	"github.com/SmirnovND/gophkeeper/internal/domain"
+	"github.com/SmirnovND/gophkeeper/internal/interfaces"
+)
+
+// ItemTemplateRepo реализует интерфейс interfaces.ItemTemplateRepo
+type ItemTemplateRepo struct {
+	db interfaces.DB
+}
+
+// NewItemTemplateRepo создает новый экземпляр ItemTemplateRepo
+func NewItemTemplateRepo(db interfaces.DB) interfaces.ItemTemplateRepo {
+	return &ItemTemplateRepo{
+		db: db,
+	}
+}
+
+// SaveItemTemplate сохраняет шаблон пользователя, шаблон с тем же именем заменяется
+func (r *ItemTemplateRepo) SaveItemTemplate(userID string, template *domain.ItemTemplate) error {
+	query := `INSERT INTO "item_template" (user_id, name, schema)
+              VALUES ($1, $2, $3)
+              ON CONFLICT (user_id, name) DO UPDATE SET schema = EXCLUDED.schema, updated_at = NOW()
+              RETURNING created_at, updated_at`
+
+	err := r.db.QueryRow(query, userID, template.Name, template.Schema).Scan(&template.CreatedAt, &template.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("error saving item template: %w", err)
+	}
+
+	return nil
+}
+
+// GetItemTemplate возвращает шаблон пользователя по имени
+func (r *ItemTemplateRepo) GetItemTemplate(userID string, name string) (*domain.ItemTemplate, error) {
+	query := `SELECT name, schema, created_at, updated_at FROM "item_template" WHERE user_id = $1 AND name = $2`
+
+	template := &domain.ItemTemplate{}
+	err := r.db.QueryRow(query, userID, name).Scan(&template.Name, &template.Schema, &template.CreatedAt, &template.UpdatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrNotFound
+		}
+		return nil, fmt.Errorf("error querying item template: %w", err)
+	}
+
+	return template, nil
+}
+
+// ListItemTemplates возвращает все шаблоны пользователя, отсортированные по имени
+func (r *ItemTemplateRepo) ListItemTemplates(userID string) ([]*domain.ItemTemplate, error) {
+	query := `SELECT name, schema, created_at, updated_at FROM "item_template" WHERE user_id = $1 ORDER BY name`
+
+	rows, err := r.db.Queryx(query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("error querying item templates: %w", err)
+	}
+	defer rows.Close()
+
+	result := make([]*domain.ItemTemplate, 0)
+	for rows.Next() {
+		template := &domain.ItemTemplate{}
+		if err := rows.Scan(&template.Name, &template.Schema, &template.CreatedAt, &template.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("error scanning item template: %w", err)
+		}
+		result = append(result, template)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating item templates: %w", err)
+	}
+
+	return result, nil
+}
+
+// DeleteItemTemplate удаляет шаблон пользователя по имени
+func (r *ItemTemplateRepo) DeleteItemTemplate(userID string, name string) error {
+	query := `DELETE FROM "item_template" WHERE user_id = $1 AND name = $2`
+
+	result, err := r.db.Exec(query, userID, name)
+	if err != nil {
+		return fmt.Errorf("error deleting item template: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error getting affected rows: %w", err)
+	}
+	if rows == 0 {
+		return domain.ErrNotFound
+	}
+
+	return nil
+}
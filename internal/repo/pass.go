@@ -2,6 +2,7 @@ package repo
 
 import (
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"github.com/SmirnovND/gophkeeper/internal/domain"
 	"github.com/SmirnovND/gophkeeper/internal/interfaces"
@@ -56,6 +57,35 @@ func (r *UserDataRepo) SaveUserData(userData *domain.UserData) error {
 	return nil
 }
 
+// SaveUserDataChecked сохраняет данные пользователя, если check не вернул ошибку. Строка пользователя блокируется
+// до конца транзакции: параллельное сохранение ждет блокировку, и его проверка видит уже сохраненную запись
+func (r *UserDataRepo) SaveUserDataChecked(
+	userData *domain.UserData,
+	check func(usage *domain.Usage, existing *domain.UserData) error,
+) error {
+	return inTransaction(r.db, func(tx interfaces.DB) error {
+		if _, err := tx.Exec(`SELECT id FROM "users" WHERE id = $1 FOR UPDATE`, userData.UserID); err != nil {
+			return fmt.Errorf("error locking user: %w", err)
+		}
+
+		usage, err := NewQuotaRepo(tx).GetUsage(userData.UserID)
+		if err != nil {
+			return err
+		}
+
+		txRepo := &UserDataRepo{db: tx}
+		existing, err := txRepo.FindUserDataByLabel(userData.UserID, userData.Label)
+		if err != nil && err != domain.ErrNotFound {
+			return fmt.Errorf("error checking existing user data: %w", err)
+		}
+
+		if err := check(usage, existing); err != nil {
+			return err
+		}
+		return txRepo.SaveUserData(userData)
+	})
+}
+
 // FindUserDataByLabel ищет данные пользователя по метке
 func (r *UserDataRepo) FindUserDataByLabel(userID, label string) (*domain.UserData, error) {
 	query := `SELECT id, user_id, label, type, data, metadata, created_at, updated_at
@@ -155,3 +185,97 @@ func (r *UserDataRepo) DeleteUserDataByUserIDAndLabel(userID, label string) erro
 
 	return nil
 }
+
+// ListUserDataByType возвращает все данные пользователя указанного типа
+func (r *UserDataRepo) ListUserDataByType(userID string, dataType string) ([]*domain.UserData, error) {
+	query := `SELECT id, user_id, label, type, data, metadata, created_at, updated_at
+              FROM "user_data"
+              WHERE user_id = $1 AND type = $2
+              ORDER BY label`
+	rows, err := r.db.Queryx(query, userID, dataType)
+	if err != nil {
+		return nil, fmt.Errorf("error querying user data by type: %w", err)
+	}
+	defer rows.Close()
+
+	result := make([]*domain.UserData, 0)
+	for rows.Next() {
+		userData := &domain.UserData{}
+		err := rows.Scan(
+			&userData.ID,
+			&userData.UserID,
+			&userData.Label,
+			&userData.Type,
+			&userData.Data,
+			&userData.Metadata,
+			&userData.CreatedAt,
+			&userData.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning user data: %w", err)
+		}
+		result = append(result, userData)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating user data: %w", err)
+	}
+
+	return result, nil
+}
+
+// ListUserData возвращает все записи пользователя без их содержимого, отсортированные по метке
+func (r *UserDataRepo) ListUserData(userID string) ([]*domain.UserData, error) {
+	query := `SELECT id, user_id, label, type, metadata, created_at, updated_at
+              FROM "user_data"
+              WHERE user_id = $1
+              ORDER BY label`
+	rows, err := r.db.Queryx(query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("error querying user data: %w", err)
+	}
+	defer rows.Close()
+
+	result := make([]*domain.UserData, 0)
+	for rows.Next() {
+		userData := &domain.UserData{}
+		err := rows.Scan(
+			&userData.ID,
+			&userData.UserID,
+			&userData.Label,
+			&userData.Type,
+			&userData.Metadata,
+			&userData.CreatedAt,
+			&userData.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning user data: %w", err)
+		}
+		result = append(result, userData)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating user data: %w", err)
+	}
+
+	return result, nil
+}
+
+// RenameUserData меняет метку и содержимое записи по ID
+func (r *UserDataRepo) RenameUserData(id string, label string, data json.RawMessage) error {
+	query := `UPDATE "user_data" SET label = $1, data = $2 WHERE id = $3`
+
+	result, err := r.db.Exec(query, label, data, id)
+	if err != nil {
+		return fmt.Errorf("error renaming user data: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error getting rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return domain.ErrNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,58 @@
+package repo
+
+import (
+	"database/sql"
+	"fmt"
+	"github.com/SmirnovND/gophkeeper/internal/domain"
+	"github.com/SmirnovND/gophkeeper/internal/interfaces"
+)
+
+// QuotaRepo реализует интерфейс interfaces.QuotaRepo
+type QuotaRepo struct {
+	db interfaces.DB
+}
+
+// NewQuotaRepo создает новый экземпляр QuotaRepo
+func NewQuotaRepo(db interfaces.DB) interfaces.QuotaRepo {
+	return &QuotaRepo{
+		db: db,
+	}
+}
+
+// GetUserQuota возвращает квоту пользователя.
+// Поля, не переопределенные в таблице user_quota, берутся из defaults.
+func (r *QuotaRepo) GetUserQuota(userID string, defaults domain.Quota) (*domain.Quota, error) {
+	query := `SELECT COALESCE(max_total_bytes, $2), COALESCE(max_items, $3), COALESCE(max_file_size, $4)
+              FROM "user_quota"
+              WHERE user_id = $1`
+
+	quota := &domain.Quota{}
+	err := r.db.QueryRow(query, userID, defaults.MaxTotalBytes, defaults.MaxItems, defaults.MaxFileSize).
+		Scan(&quota.MaxTotalBytes, &quota.MaxItems, &quota.MaxFileSize)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return &defaults, nil
+		}
+		return nil, fmt.Errorf("error querying user quota: %w", err)
+	}
+
+	return quota, nil
+}
+
+// GetUsage возвращает текущее потребление хранилища пользователем
+func (r *QuotaRepo) GetUsage(userID string) (*domain.Usage, error) {
+	query := `SELECT COUNT(*),
+                     COUNT(*) FILTER (WHERE type = $2),
+                     COALESCE(SUM((data->>'size')::BIGINT) FILTER (WHERE type = $2), 0)
+              FROM "user_data"
+              WHERE user_id = $1`
+
+	usage := &domain.Usage{}
+	err := r.db.QueryRow(query, userID, domain.UserDataTypeFile).
+		Scan(&usage.Items, &usage.Files, &usage.TotalBytes)
+	if err != nil {
+		return nil, fmt.Errorf("error querying user usage: %w", err)
+	}
+
+	return usage, nil
+}
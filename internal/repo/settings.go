@@ -0,0 +1,52 @@
+package repo
+
+import (
+	"database/sql"
+	"fmt"
+	"github.com/SmirnovND/gophkeeper/internal/domain"
+	"github.com/SmirnovND/gophkeeper/internal/interfaces"
+)
+
+// SettingsRepo реализует интерфейс interfaces.SettingsRepo
+type SettingsRepo struct {
+	db interfaces.DB
+}
+
+// NewSettingsRepo создает новый экземпляр SettingsRepo
+func NewSettingsRepo(db interfaces.DB) interfaces.SettingsRepo {
+	return &SettingsRepo{
+		db: db,
+	}
+}
+
+// GetUserSettings возвращает настройки пользователя.
+// Поля, не заданные в таблице user_settings, берутся из defaults.
+func (r *SettingsRepo) GetUserSettings(userID string, defaults domain.UserSettings) (*domain.UserSettings, error) {
+	query := `SELECT COALESCE(password_history, $2)
+              FROM "user_settings"
+              WHERE user_id = $1`
+
+	settings := &domain.UserSettings{}
+	err := r.db.QueryRow(query, userID, defaults.PasswordHistory).Scan(&settings.PasswordHistory)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return &defaults, nil
+		}
+		return nil, fmt.Errorf("error querying user settings: %w", err)
+	}
+
+	return settings, nil
+}
+
+// SaveUserSettings сохраняет настройки пользователя, прежние настройки заменяются
+func (r *SettingsRepo) SaveUserSettings(userID string, settings *domain.UserSettings) error {
+	query := `INSERT INTO "user_settings" (user_id, password_history)
+              VALUES ($1, $2)
+              ON CONFLICT (user_id) DO UPDATE SET password_history = EXCLUDED.password_history`
+
+	if _, err := r.db.Exec(query, userID, settings.PasswordHistory); err != nil {
+		return fmt.Errorf("error saving user settings: %w", err)
+	}
+
+	return nil
+}
@@ -2,14 +2,17 @@ package repo
 
 import (
 	"encoding/json"
-	"fmt"
+	"errors"
+	"github.com/SmirnovND/gophkeeper/internal/domain"
 	"github.com/SmirnovND/gophkeeper/internal/interfaces"
+	"io/fs"
 	"os"
 	"path/filepath"
 )
 
 // TokenStorage - структура для хранения JWT-токена.
 type TokenStorage struct {
+	config interfaces.ConfigClient
 }
 
 // AuthData - структура для хранения данных авторизации (токена).
@@ -22,10 +25,15 @@ func NewTokenStorage() interfaces.TokenStorage {
 	return &TokenStorage{}
 }
 
+// NewProfileTokenStorage создает хранилище токена выбранного профиля passcli.
+func NewProfileTokenStorage(config interfaces.ConfigClient) interfaces.TokenStorage {
+	return &TokenStorage{config: config}
+}
+
 // SaveToken сохраняет токен.
 func (s *TokenStorage) SaveToken(token string) error {
 	// Получаем путь к файлу с конфигурацией
-	configPath, err := getConfigPath()
+	configPath, err := s.path()
 	if err != nil {
 		return err
 	}
@@ -39,8 +47,8 @@ func (s *TokenStorage) SaveToken(token string) error {
 	// Создаем структуру с токеном
 	authData := AuthData{Token: token}
 
-	// Сохраняем данные
-	file, err := os.Create(configPath)
+	// Сохраняем данные, токен доступен только владельцу
+	file, err := os.OpenFile(configPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
 	if err != nil {
 		return err
 	}
@@ -52,14 +60,22 @@ func (s *TokenStorage) SaveToken(token string) error {
 
 // LoadToken загружает токен.
 func (s *TokenStorage) LoadToken() (string, error) {
+	// Токен из окружения заменяет сохраненный, например в CI
+	if s.config != nil && s.config.GetTokenOverride() != "" {
+		return s.config.GetTokenOverride(), nil
+	}
+
 	// Получаем путь к файлу с конфигурацией
-	configPath, err := getConfigPath()
+	configPath, err := s.path()
 	if err != nil {
 		return "", err
 	}
 
-	// Открываем файл
+	// Открываем файл, его отсутствие означает, что вход не выполнялся
 	file, err := os.Open(configPath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return "", domain.ErrNotLoggedIn
+	}
 	if err != nil {
 		return "", err
 	}
@@ -73,11 +89,19 @@ func (s *TokenStorage) LoadToken() (string, error) {
 
 	// Возвращаем токен
 	if authData.Token == "" {
-		return "", fmt.Errorf("token not found")
+		return "", domain.ErrNotLoggedIn
 	}
 	return authData.Token, nil
 }
 
+// path возвращает путь к файлу токена выбранного профиля.
+func (s *TokenStorage) path() (string, error) {
+	if s.config != nil {
+		return s.config.GetTokenPath()
+	}
+	return getConfigPath()
+}
+
 // getConfigPath возвращает путь к файлу конфигурации.
 var getConfigPath = func() (string, error) {
 	dir, err := os.UserConfigDir()
@@ -2,6 +2,8 @@ package repo
 
 import (
 	"encoding/json"
+	config "github.com/SmirnovND/gophkeeper/internal/config/client"
+	"github.com/SmirnovND/gophkeeper/internal/domain"
 	"github.com/stretchr/testify/assert"
 	"os"
 	"path/filepath"
@@ -60,7 +62,7 @@ func TestTokenStorage_LoadToken_FileNotExists(t *testing.T) {
 
 	// Пытаемся загрузить токен из несуществующего файла
 	_, err := storage.LoadToken()
-	assert.Error(t, err)
+	assert.ErrorIs(t, err, domain.ErrNotLoggedIn)
 }
 
 // Тест для LoadToken - некорректный формат файла
@@ -119,7 +121,7 @@ func TestTokenStorage_LoadToken_EmptyToken(t *testing.T) {
 	// Пытаемся загрузить пустой токен
 	_, err := storage.LoadToken()
 	assert.Error(t, err)
-	assert.Contains(t, err.Error(), "token not found")
+	assert.ErrorIs(t, err, domain.ErrNotLoggedIn)
 }
 
 // Тест для SaveToken - ошибка создания директории
@@ -139,3 +141,33 @@ func TestTokenStorage_SaveToken_DirectoryError(t *testing.T) {
 	err := storage.SaveToken("test-token")
 	assert.Error(t, err)
 }
+
+// Тест для токенов профилей - у каждого профиля свой токен, PASSCLI_TOKEN заменяет сохраненный
+func TestTokenStorage_ProfileTokens(t *testing.T) {
+	t.Setenv(config.EnvConfig, filepath.Join(t.TempDir(), "config.yaml"))
+	t.Setenv(config.EnvProfile, "")
+	t.Setenv(config.EnvToken, "")
+
+	profiles := config.NewConfig("127.0.0.1:8080")
+	assert.NoError(t, profiles.AddProfile(domain.Profile{Name: "work", Server: "127.0.0.1:8081"}))
+
+	// Сохраняем токены в профилях default и work
+	for _, name := range []string{"default", "work"} {
+		selected := config.NewConfig("127.0.0.1:8080")
+		assert.NoError(t, selected.Select(name))
+		assert.NoError(t, NewProfileTokenStorage(selected).SaveToken(name+"-token"))
+	}
+
+	for _, name := range []string{"default", "work"} {
+		selected := config.NewConfig("127.0.0.1:8080")
+		assert.NoError(t, selected.Select(name))
+		token, err := NewProfileTokenStorage(selected).LoadToken()
+		assert.NoError(t, err)
+		assert.Equal(t, name+"-token", token)
+	}
+
+	t.Setenv(config.EnvToken, "ci-token")
+	token, err := NewProfileTokenStorage(profiles).LoadToken()
+	assert.NoError(t, err)
+	assert.Equal(t, "ci-token", token)
+}
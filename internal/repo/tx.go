@@ -0,0 +1,43 @@
+package repo
+
+import (
+	"fmt"
+	"github.com/SmirnovND/gophkeeper/internal/interfaces"
+	"github.com/jmoiron/sqlx"
+)
+
+// txDB - транзакция, через которую репозитории выполняют запросы вместо соединения с базой данных
+type txDB struct {
+	*sqlx.Tx
+}
+
+func (t txDB) QueryRow(query string, args ...any) *sqlx.Row {
+	return t.Tx.QueryRowx(query, args...)
+}
+
+// Ping не проверяет соединение: транзакция уже выполняется на установленном соединении
+func (t txDB) Ping() error {
+	return nil
+}
+
+func (t txDB) Beginx() (*sqlx.Tx, error) {
+	return nil, fmt.Errorf("nested transactions are not supported")
+}
+
+// inTransaction выполняет fn в транзакции: при ошибке fn транзакция откатывается, иначе фиксируется
+func inTransaction(db interfaces.DB, fn func(tx interfaces.DB) error) error {
+	tx, err := db.Beginx()
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %w", err)
+	}
+
+	if err := fn(txDB{tx}); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error committing transaction: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package repo
+
+import (
+	"database/sql"
+	"fmt"
+	"github.com/SmirnovND/gophkeeper/internal/domain"
+	"github.com/SmirnovND/gophkeeper/internal/interfaces"
+)
+
+// UserKeyRepo реализует интерфейс interfaces.UserKeyRepo
+type UserKeyRepo struct {
+	db interfaces.DB
+}
+
+// NewUserKeyRepo создает новый экземпляр UserKeyRepo
+func NewUserKeyRepo(db interfaces.DB) interfaces.UserKeyRepo {
+	return &UserKeyRepo{
+		db: db,
+	}
+}
+
+// GetUserKey возвращает обернутый ключ пользователя
+func (r *UserKeyRepo) GetUserKey(userID string) (*domain.UserKey, error) {
+	query := `SELECT user_id, wrapped_key, master_key_id FROM "user_key" WHERE user_id = $1`
+
+	userKey := &domain.UserKey{}
+	err := r.db.QueryRow(query, userID).Scan(&userKey.UserID, &userKey.WrappedKey, &userKey.MasterKeyID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrNotFound
+		}
+		return nil, fmt.Errorf("error querying user key: %w", err)
+	}
+
+	return userKey, nil
+}
+
+// SaveUserKey сохраняет ключ пользователя, существующий ключ не перезаписывается
+func (r *UserKeyRepo) SaveUserKey(userKey *domain.UserKey) error {
+	query := `INSERT INTO "user_key" (user_id, wrapped_key, master_key_id)
+              VALUES ($1, $2, $3)
+              ON CONFLICT (user_id) DO NOTHING`
+
+	_, err := r.db.Exec(query, userKey.UserID, userKey.WrappedKey, userKey.MasterKeyID)
+	if err != nil {
+		return fmt.Errorf("error saving user key: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateUserKey заменяет обернутый ключ пользователя
+func (r *UserKeyRepo) UpdateUserKey(userKey *domain.UserKey) error {
+	query := `UPDATE "user_key" SET wrapped_key = $1, master_key_id = $2, updated_at = NOW() WHERE user_id = $3`
+
+	result, err := r.db.Exec(query, userKey.WrappedKey, userKey.MasterKeyID, userKey.UserID)
+	if err != nil {
+		return fmt.Errorf("error updating user key: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error getting affected rows: %w", err)
+	}
+	if rows == 0 {
+		return domain.ErrNotFound
+	}
+
+	return nil
+}
+
+// ListUserKeysNotWrappedWith возвращает ключи, обернутые мастер-ключом, отличным от masterKeyID
+func (r *UserKeyRepo) ListUserKeysNotWrappedWith(masterKeyID string) ([]*domain.UserKey, error) {
+	query := `SELECT user_id, wrapped_key, master_key_id FROM "user_key" WHERE master_key_id <> $1`
+
+	rows, err := r.db.Queryx(query, masterKeyID)
+	if err != nil {
+		return nil, fmt.Errorf("error querying user keys: %w", err)
+	}
+	defer rows.Close()
+
+	result := make([]*domain.UserKey, 0)
+	for rows.Next() {
+		userKey := &domain.UserKey{}
+		if err := rows.Scan(&userKey.UserID, &userKey.WrappedKey, &userKey.MasterKeyID); err != nil {
+			return nil, fmt.Errorf("error scanning user key: %w", err)
+		}
+		result = append(result, userKey)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating user keys: %w", err)
+	}
+
+	return result, nil
+}
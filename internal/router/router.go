@@ -16,16 +16,19 @@ func Handler(diContainer *server.Container) http.Handler {
 	var AuthController *controllers.AuthController
 	var FileController *controllers.FileController
 	var DataController *controllers.DataController
+	var UserController *controllers.UserController
 	var cf interfaces.ConfigServer
 	err := diContainer.Invoke(func(
 		c interfaces.ConfigServer,
 		authControl *controllers.AuthController,
 		fileControl *controllers.FileController,
 		dataControl *controllers.DataController,
+		userControl *controllers.UserController,
 	) {
 		AuthController = authControl
 		FileController = fileControl
 		DataController = dataControl
+		UserController = userControl
 		cf = c
 	})
 	if err != nil {
@@ -43,6 +46,18 @@ func Handler(diContainer *server.Container) http.Handler {
 	r.Post("/api/user/register", AuthController.HandleRegisterJSON)
 	r.Post("/api/user/login", AuthController.HandleLoginJSON)
 
+	r.Get("/api/user/usage", func(w http.ResponseWriter, r *http.Request) {
+		auth.AuthMiddleware(cf.GetJwtSecret(), http.HandlerFunc(UserController.HandleUsage)).ServeHTTP(w, r)
+	})
+
+	r.Get("/api/user/settings", func(w http.ResponseWriter, r *http.Request) {
+		auth.AuthMiddleware(cf.GetJwtSecret(), http.HandlerFunc(UserController.HandleGetSettings)).ServeHTTP(w, r)
+	})
+
+	r.Put("/api/user/settings", func(w http.ResponseWriter, r *http.Request) {
+		auth.AuthMiddleware(cf.GetJwtSecret(), http.HandlerFunc(UserController.HandleSaveSettings)).ServeHTTP(w, r)
+	})
+
 	r.Post("/api/file/upload", func(w http.ResponseWriter, r *http.Request) {
 		auth.AuthMiddleware(cf.GetJwtSecret(), http.HandlerFunc(FileController.HandleUploadFile)).ServeHTTP(w, r)
 	})
@@ -51,6 +66,26 @@ func Handler(diContainer *server.Container) http.Handler {
 		auth.AuthMiddleware(cf.GetJwtSecret(), http.HandlerFunc(FileController.HandleDownloadFile)).ServeHTTP(w, r)
 	})
 
+	r.Get("/api/file/list", func(w http.ResponseWriter, r *http.Request) {
+		auth.AuthMiddleware(cf.GetJwtSecret(), http.HandlerFunc(FileController.HandleListFiles)).ServeHTTP(w, r)
+	})
+
+	r.Get("/api/file/info", func(w http.ResponseWriter, r *http.Request) {
+		auth.AuthMiddleware(cf.GetJwtSecret(), http.HandlerFunc(FileController.HandleFileInfo)).ServeHTTP(w, r)
+	})
+
+	r.Post("/api/file/rename", func(w http.ResponseWriter, r *http.Request) {
+		auth.AuthMiddleware(cf.GetJwtSecret(), http.HandlerFunc(FileController.HandleRenameFile)).ServeHTTP(w, r)
+	})
+
+	r.Post("/api/file/verify", func(w http.ResponseWriter, r *http.Request) {
+		auth.AuthMiddleware(cf.GetJwtSecret(), http.HandlerFunc(FileController.HandleVerifyFile)).ServeHTTP(w, r)
+	})
+
+	r.Delete("/api/file", func(w http.ResponseWriter, r *http.Request) {
+		auth.AuthMiddleware(cf.GetJwtSecret(), http.HandlerFunc(FileController.HandleDeleteFile)).ServeHTTP(w, r)
+	})
+
 	// Маршруты для работы с данными пользователя
 	r.Route("/api/data", func(r chi.Router) {
 		// Применяем middleware аутентификации ко всем маршрутам данных
@@ -60,25 +95,47 @@ func Handler(diContainer *server.Container) http.Handler {
 			})
 		})
 
-		// Маршруты для работы с учетными данными (логин/пароль)
-		r.Route("/credential/{label}", func(r chi.Router) {
-			r.Post("/", DataController.SaveCredential)
-			r.Get("/", DataController.GetCredential)
-			r.Delete("/", DataController.DeleteCredential)
+		// Список всех записей пользователя
+		r.Get("/", DataController.ListItems)
+
+		// Маршруты API v1 сохранены для совместимости: записи всех типов из пакета itemtype обрабатываются
+		// общими контроллерами, а поля записи передаются под ключом типа, например credential_data
+		r.Route("/{type}/{label}", func(r chi.Router) {
+			r.Post("/", DataController.SaveLegacyItem)
+			r.Get("/", DataController.GetLegacyItem)
+			r.Delete("/", DataController.DeleteItem)
+		})
+	})
+
+	// Маршруты API v2 для записей всех типов, описанных в пакете itemtype или шаблонами пользователя
+	r.Route("/api/v2/items", func(r chi.Router) {
+		r.Use(func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				auth.AuthMiddleware(cf.GetJwtSecret(), next).ServeHTTP(w, r)
+			})
 		})
 
-		// Маршруты для работы с данными кредитных карт
-		r.Route("/card/{label}", func(r chi.Router) {
-			r.Post("/", DataController.SaveCard)
-			r.Get("/", DataController.GetCard)
-			r.Delete("/", DataController.DeleteCard)
+		r.Get("/", DataController.ListItems)
+		r.Route("/{type}/{label}", func(r chi.Router) {
+			r.Post("/", DataController.SaveItem)
+			r.Get("/", DataController.GetItem)
+			r.Delete("/", DataController.DeleteItem)
+		})
+	})
+
+	// Шаблоны пользовательских типов записей
+	r.Route("/api/v2/templates", func(r chi.Router) {
+		r.Use(func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				auth.AuthMiddleware(cf.GetJwtSecret(), next).ServeHTTP(w, r)
+			})
 		})
 
-		// Маршруты для работы с текстовыми данными
-		r.Route("/text/{label}", func(r chi.Router) {
-			r.Post("/", DataController.SaveText)
-			r.Get("/", DataController.GetText)
-			r.Delete("/", DataController.DeleteText)
+		r.Get("/", DataController.ListItemTemplates)
+		r.Route("/{name}", func(r chi.Router) {
+			r.Post("/", DataController.SaveItemTemplate)
+			r.Get("/", DataController.GetItemTemplate)
+			r.Delete("/", DataController.DeleteItemTemplate)
 		})
 	})
 
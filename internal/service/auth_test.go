@@ -47,6 +47,30 @@ func (m *MockConfigServer) GetMinioHost() string {
 	return ""
 }
 
+func (m *MockConfigServer) GetDefaultQuota() domain.Quota {
+	return domain.Quota{}
+}
+
+func (m *MockConfigServer) GetDefaultSettings() domain.UserSettings {
+	return domain.UserSettings{}
+}
+
+func (m *MockConfigServer) GetFileTypePolicy() domain.FileTypePolicy {
+	return domain.FileTypePolicy{}
+}
+
+func (m *MockConfigServer) GetMinioSecure() bool {
+	return false
+}
+
+func (m *MockConfigServer) GetMasterKey() string {
+	return ""
+}
+
+func (m *MockConfigServer) GetPreviousMasterKeys() []string {
+	return nil
+}
+
 func TestGenerateToken(t *testing.T) {
 	// Arrange
 	mockConfig := NewMockConfigServer()
@@ -9,13 +9,19 @@ import (
 	"github.com/SmirnovND/gophkeeper/internal/interfaces"
 	"io"
 	"io/ioutil"
+	"mime/multipart"
 	"net/http"
+	neturl "net/url"
 	"os"
+	"strings"
+	"sync"
 )
 
 type ClientService struct {
 	client     *http.Client
 	serverAddr string
+	config     interfaces.ConfigClient
+	progress   domain.ProgressFunc
 }
 
 func NewClientService(serverAddr string) interfaces.ClientService {
@@ -25,6 +31,88 @@ func NewClientService(serverAddr string) interfaces.ClientService {
 	}
 }
 
+// NewProfileClientService создает клиент сервера выбранного профиля. Профиль выбирается после разбора флагов,
+// поэтому адрес сервера и настройки TLS берутся из конфигурации в момент запроса
+func NewProfileClientService(config interfaces.ConfigClient) interfaces.ClientService {
+	return &ClientService{
+		client: &http.Client{Transport: &profileTransport{config: config}},
+		config: config,
+	}
+}
+
+// baseURL возвращает адрес сервера со схемой, адрес без схемы означает http
+func (c *ClientService) baseURL() string {
+	serverAddr := c.serverAddr
+	if c.config != nil {
+		serverAddr = c.config.GetServerAddress()
+	}
+	if !strings.Contains(serverAddr, "://") {
+		serverAddr = "http://" + serverAddr
+	}
+	return strings.TrimRight(serverAddr, "/")
+}
+
+// profileTransport выполняет запросы с настройками TLS выбранного профиля
+type profileTransport struct {
+	config    interfaces.ConfigClient
+	once      sync.Once
+	transport http.RoundTripper
+	err       error
+}
+
+func (t *profileTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.once.Do(func() {
+		tlsConfig, err := t.config.GetTLSConfig()
+		if err != nil {
+			t.err = err
+			return
+		}
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.TLSClientConfig = tlsConfig
+		t.transport = transport
+	})
+	if t.err != nil {
+		return nil, t.err
+	}
+	return t.transport.RoundTrip(req)
+}
+
+// SetProgress задает функцию, которая получает ход загрузки и скачивания файлов, nil отключает ее
+func (c *ClientService) SetProgress(progress domain.ProgressFunc) {
+	c.progress = progress
+}
+
+// progressReader сообщает о числе прочитанных байт после каждого чтения
+type progressReader struct {
+	reader   io.Reader
+	done     int64
+	total    int64
+	progress domain.ProgressFunc
+}
+
+func (r *progressReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	if n > 0 {
+		r.done += int64(n)
+		r.progress(r.done, r.total)
+	}
+	return n, err
+}
+
+// withProgress оборачивает reader, если задана функция хода передачи
+func (c *ClientService) withProgress(reader io.Reader, total int64) io.Reader {
+	if c.progress == nil {
+		return reader
+	}
+	return &progressReader{reader: reader, total: total, progress: c.progress}
+}
+
+// statusError возвращает ошибку неуспешного ответа сервера вместе с кодом ответа,
+// по которому клиент отличает отсутствие записи и ошибку авторизации от прочих ошибок
+func statusError(statusCode int, format string, args ...interface{}) error {
+	return &domain.Error{Message: fmt.Sprintf(format, args...), CodeValue: statusCode}
+}
+
 func (c *ClientService) sendRequest(method, url string, data interface{}) (*http.Response, error) {
 	jsonData, err := json.Marshal(data)
 	if err != nil {
@@ -47,14 +135,14 @@ func (c *ClientService) sendRequest(method, url string, data interface{}) (*http
 
 func (c *ClientService) Login(login string, password string) (string, error) {
 	credentials := domain.Credentials{Login: login, Password: password}
-	resp, err := c.sendRequest("POST", "http://"+c.serverAddr+"/api/user/login", credentials)
+	resp, err := c.sendRequest("POST", c.baseURL()+"/api/user/login", credentials)
 	if err != nil {
 		return "", err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("ошибка аутентификации, код ответа: %d", resp.StatusCode)
+		return "", statusError(resp.StatusCode, "ошибка аутентификации, код ответа: %d", resp.StatusCode)
 	}
 
 	token := resp.Header.Get("Authorization")
@@ -67,7 +155,7 @@ func (c *ClientService) Login(login string, password string) (string, error) {
 
 func (c *ClientService) Register(login, password string) (string, error) {
 	credentials := domain.Credentials{Login: login, Password: password}
-	resp, err := c.sendRequest("POST", "http://"+c.serverAddr+"/api/user/register", credentials)
+	resp, err := c.sendRequest("POST", c.baseURL()+"/api/user/register", credentials)
 	if err != nil {
 		return "", err
 	}
@@ -76,7 +164,7 @@ func (c *ClientService) Register(login, password string) (string, error) {
 	if resp.StatusCode == http.StatusConflict {
 		return "", fmt.Errorf("пользователь с таким логином уже существует")
 	} else if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("ошибка регистрации, код ответа: %d", resp.StatusCode)
+		return "", statusError(resp.StatusCode, "ошибка регистрации, код ответа: %d", resp.StatusCode)
 	}
 
 	token := resp.Header.Get("Authorization")
@@ -87,31 +175,20 @@ func (c *ClientService) Register(login, password string) (string, error) {
 	return token, nil
 }
 
-func (c *ClientService) GetUploadLink(label string, extension string, metadata string, token string) (string, error) {
+func (c *ClientService) GetUploadLink(fileData *domain.FileData, token string) (*domain.FileDataResponse, error) {
 	// Запрос на получение ссылки для загрузки файла
-	url := "http://" + c.serverAddr + "/api/file/upload"
-
-	// Создаем структуру для запроса с параметрами из аргументов функции
-	requestData := struct {
-		Name      string `json:"name"`
-		Extension string `json:"extension"`
-		Metadata  string `json:"metadata"`
-	}{
-		Name:      label,
-		Extension: extension,
-		Metadata:  metadata,
-	}
+	url := c.baseURL() + "/api/file/upload"
 
 	// Преобразуем структуру в JSON
-	jsonData, err := json.Marshal(requestData)
+	jsonData, err := json.Marshal(fileData)
 	if err != nil {
-		return "", fmt.Errorf("ошибка при маршалинге данных: %w", err)
+		return nil, fmt.Errorf("ошибка при маршалинге данных: %w", err)
 	}
 
 	// Создаем запрос вместо использования http.Post для добавления заголовка авторизации
 	req, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonData))
 	if err != nil {
-		return "", fmt.Errorf("ошибка при создании запроса: %w", err)
+		return nil, fmt.Errorf("ошибка при создании запроса: %w", err)
 	}
 
 	// Устанавливаем заголовки
@@ -121,39 +198,41 @@ func (c *ClientService) GetUploadLink(label string, extension string, metadata s
 	// Выполняем запрос
 	resp, err := c.client.Do(req)
 	if err != nil {
-		return "", errors.New(fmt.Sprintf("Ошибка при запросе к серверу: %v\n", err))
+		return nil, fmt.Errorf("ошибка при запросе к серверу: %w", err)
 	}
 	defer resp.Body.Close()
 
 	// Проверяем статус ответа
-	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("ошибка при получении ссылки для загрузки, код ответа: %d", resp.StatusCode)
+	if resp.StatusCode == http.StatusRequestEntityTooLarge {
+		message, _ := ioutil.ReadAll(resp.Body)
+		return nil, fmt.Errorf("%w: %s", domain.ErrQuotaExceeded, strings.TrimSpace(string(message)))
+	} else if resp.StatusCode == http.StatusUnsupportedMediaType {
+		return nil, fmt.Errorf("%w: %s", domain.ErrFileTypeNotAllowed, fileData.MimeType)
+	} else if resp.StatusCode != http.StatusOK {
+		return nil, statusError(resp.StatusCode, "ошибка при получении ссылки для загрузки, код ответа: %d", resp.StatusCode)
 	}
 
 	// Чтение ответа сервера
 	respBody, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
-		return "", errors.New(fmt.Sprintf("Ошибка при чтении ответа сервера: %v\n", err))
+		return nil, errors.New(fmt.Sprintf("Ошибка при чтении ответа сервера: %v\n", err))
 	}
 
-	// Извлекаем URL из ответа
-	var response struct {
-		URL         string `json:"url"`
-		Description string `json:"description"`
-	}
+	// Извлекаем URL и поля формы из ответа
+	var response domain.FileDataResponse
 	if err := json.Unmarshal(respBody, &response); err != nil {
-		return "", errors.New(fmt.Sprintf("Ошибка при парсинге ответа: %v\n", err))
+		return nil, errors.New(fmt.Sprintf("Ошибка при парсинге ответа: %v\n", err))
 	}
-	
+
 	// Проверяем, что URL не пустой
-	if response.URL == "" {
-		return "", errors.New("URL для загрузки не найден в ответе")
+	if response.Url == "" {
+		return nil, errors.New("URL для загрузки не найден в ответе")
 	}
 
-	return response.URL, nil
+	return &response, nil
 }
 
-func (c *ClientService) SendFileToServer(url string, file *os.File) (string, error) {
+func (c *ClientService) SendFileToServer(upload *domain.FileDataResponse, file *os.File) (string, error) {
 	// Получаем информацию о файле для определения его размера
 	fileInfo, err := file.Stat()
 	if err != nil {
@@ -166,38 +245,89 @@ func (c *ClientService) SendFileToServer(url string, file *os.File) (string, err
 		return "", errors.New(fmt.Sprintf("Ошибка при перемещении указателя файла: %v\n", err))
 	}
 
+	// Формируем multipart-тело: поля политики, затем файл. Файл передается потоком,
+	// поэтому заранее собираем только заголовки частей и закрывающую границу
+	var head bytes.Buffer
+	writer := multipart.NewWriter(&head)
+	for key, value := range upload.FormData {
+		if err := writer.WriteField(key, value); err != nil {
+			return "", errors.New(fmt.Sprintf("Ошибка при формировании формы загрузки: %v\n", err))
+		}
+	}
+	if _, err := writer.CreateFormFile("file", fileInfo.Name()); err != nil {
+		return "", errors.New(fmt.Sprintf("Ошибка при формировании формы загрузки: %v\n", err))
+	}
+	tail := fmt.Sprintf("\r\n--%s--\r\n", writer.Boundary())
+
+	body := io.MultiReader(&head, c.withProgress(file, fileInfo.Size()), strings.NewReader(tail))
+
 	// Загрузка файла
-	req, err := http.NewRequest("PUT", url, file)
+	req, err := http.NewRequest("POST", upload.Url, body)
 	if err != nil {
 		return "", errors.New(fmt.Sprintf("Ошибка при подготовке запроса на загрузку: %v\n", err))
 	}
 
 	// Устанавливаем заголовки
-	req.Header.Set("Content-Type", "application/octet-stream")
-	req.ContentLength = fileInfo.Size() // Устанавливаем размер файла в заголовке Content-Length
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	req.ContentLength = int64(head.Len()) + fileInfo.Size() + int64(len(tail))
 
 	client := &http.Client{}
 	fileUploadResp, err := client.Do(req)
 	if err != nil {
-		return "", errors.New(fmt.Sprintf("Ошибка при загрузке файла: %v\n", err))
+		return "", fmt.Errorf("ошибка при загрузке файла: %w", err)
 	}
 	defer fileUploadResp.Body.Close()
 
-	if fileUploadResp.StatusCode == http.StatusOK {
+	switch fileUploadResp.StatusCode {
+	case http.StatusOK, http.StatusCreated, http.StatusNoContent:
 		return "Файл успешно загружен!", nil
-	} else {
+	default:
 		return "", errors.New(fmt.Sprintf("Ошибка при загрузке файла: %s\n", fileUploadResp.Status))
 	}
 }
 
-func (c *ClientService) GetDownloadLink(label string, token string) (string, *domain.FileMetadata, string, error) {
-	// Формируем URL для запроса на получение ссылки для скачивания
-	url := fmt.Sprintf("http://%s/api/file/download?label=%s", c.serverAddr, label)
+// GetUsage получает текущее потребление хранилища и квоту пользователя
+func (c *ClientService) GetUsage(token string) (*domain.Usage, error) {
+	url := c.baseURL() + "/api/user/usage"
+
+	// Создаем запрос
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка при создании запроса: %w", err)
+	}
+
+	// Устанавливаем заголовок авторизации
+	req.Header.Set("Authorization", token)
+
+	// Выполняем запрос
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка при выполнении запроса: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// Проверяем статус ответа
+	if resp.StatusCode != http.StatusOK {
+		return nil, statusError(resp.StatusCode, "ошибка при получении потребления хранилища, код ответа: %d", resp.StatusCode)
+	}
+
+	// Десериализуем данные
+	var usage domain.Usage
+	if err := json.NewDecoder(resp.Body).Decode(&usage); err != nil {
+		return nil, fmt.Errorf("ошибка при десериализации данных: %w", err)
+	}
+
+	return &usage, nil
+}
+
+// GetSettings получает действующие настройки аккаунта пользователя
+func (c *ClientService) GetSettings(token string) (*domain.UserSettings, error) {
+	url := c.baseURL() + "/api/user/settings"
 
 	// Создаем запрос
 	req, err := http.NewRequest("GET", url, nil)
 	if err != nil {
-		return "", nil, "", fmt.Errorf("ошибка при создании запроса: %w", err)
+		return nil, fmt.Errorf("ошибка при создании запроса: %w", err)
 	}
 
 	// Устанавливаем заголовок авторизации
@@ -206,42 +336,124 @@ func (c *ClientService) GetDownloadLink(label string, token string) (string, *do
 	// Выполняем запрос
 	resp, err := c.client.Do(req)
 	if err != nil {
-		return "", nil, "", fmt.Errorf("ошибка при выполнении запроса: %w", err)
+		return nil, fmt.Errorf("ошибка при выполнении запроса: %w", err)
 	}
 	defer resp.Body.Close()
 
 	// Проверяем статус ответа
 	if resp.StatusCode != http.StatusOK {
-		return "", nil, "", fmt.Errorf("ошибка при получении ссылки для скачивания, код ответа: %d", resp.StatusCode)
+		return nil, statusError(resp.StatusCode, "ошибка при получении настроек, код ответа: %d", resp.StatusCode)
+	}
+
+	// Десериализуем данные
+	var settings domain.UserSettings
+	if err := json.NewDecoder(resp.Body).Decode(&settings); err != nil {
+		return nil, fmt.Errorf("ошибка при десериализации данных: %w", err)
+	}
+
+	return &settings, nil
+}
+
+// SaveSettings сохраняет настройки аккаунта пользователя
+func (c *ClientService) SaveSettings(settings *domain.UserSettings, token string) error {
+	url := c.baseURL() + "/api/user/settings"
+
+	// Сериализуем настройки
+	body, err := json.Marshal(settings)
+	if err != nil {
+		return fmt.Errorf("ошибка при сериализации данных: %w", err)
+	}
+
+	// Создаем запрос
+	req, err := http.NewRequest("PUT", url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("ошибка при создании запроса: %w", err)
+	}
+
+	// Устанавливаем заголовки
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", token)
+
+	// Выполняем запрос
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("ошибка при выполнении запроса: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// Проверяем статус ответа
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return nil
+	case http.StatusBadRequest:
+		message, _ := ioutil.ReadAll(resp.Body)
+		return statusError(resp.StatusCode, "настройки отклонены сервером: %s", strings.TrimSpace(string(message)))
+	default:
+		return statusError(resp.StatusCode, "ошибка при сохранении настроек, код ответа: %d", resp.StatusCode)
+	}
+}
+
+func (c *ClientService) GetDownloadLink(label string, token string) (*domain.DownloadLink, *domain.FileMetadata, string, error) {
+	// Формируем URL для запроса на получение ссылки для скачивания
+	url := fmt.Sprintf("%s/api/file/download?label=%s", c.baseURL(), neturl.PathEscape(label))
+
+	// Создаем запрос
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, nil, "", fmt.Errorf("ошибка при создании запроса: %w", err)
+	}
+
+	// Устанавливаем заголовок авторизации
+	req.Header.Set("Authorization", token)
+
+	// Выполняем запрос
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, nil, "", fmt.Errorf("ошибка при выполнении запроса: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// Проверяем статус ответа
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil, "", statusError(http.StatusNotFound, "файл не найден")
+	} else if resp.StatusCode != http.StatusOK {
+		return nil, nil, "", statusError(resp.StatusCode, "ошибка при получении ссылки для скачивания, код ответа: %d", resp.StatusCode)
 	}
 
 	// Чтение ответа сервера
 	respBody, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
-		return "", nil, "", fmt.Errorf("ошибка при чтении ответа сервера: %w", err)
+		return nil, nil, "", fmt.Errorf("ошибка при чтении ответа сервера: %w", err)
 	}
 
 	// Извлекаем URL, метаданные и метаинформацию из ответа
 	var response struct {
 		URL         string              `json:"url"`
+		Headers     map[string]string   `json:"headers"`
 		Description string              `json:"description"`
 		Metadata    domain.FileMetadata `json:"metadata"`
 		MetaInfo    string              `json:"meta_info"`
 	}
 	if err := json.Unmarshal(respBody, &response); err != nil {
-		return "", nil, "", fmt.Errorf("ошибка при парсинге ответа: %w", err)
+		return nil, nil, "", fmt.Errorf("ошибка при парсинге ответа: %w", err)
 	}
 
-	return response.URL, &response.Metadata, response.MetaInfo, nil
+	link := &domain.DownloadLink{URL: response.URL, Headers: response.Headers}
+	return link, &response.Metadata, response.MetaInfo, nil
 }
 
-func (c *ClientService) DownloadFileFromServer(url string, outputPath string) error {
+func (c *ClientService) DownloadFileFromServer(link *domain.DownloadLink, outputPath string, perm os.FileMode) error {
 	// Создаем запрос на скачивание файла
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequest("GET", link.URL, nil)
 	if err != nil {
 		return fmt.Errorf("ошибка при создании запроса на скачивание: %w", err)
 	}
 
+	// Для зашифрованного на сервере файла передаем заголовки SSE-C
+	for name, value := range link.Headers {
+		req.Header.Set(name, value)
+	}
+
 	// Выполняем запрос
 	resp, err := c.client.Do(req)
 	if err != nil {
@@ -251,18 +463,30 @@ func (c *ClientService) DownloadFileFromServer(url string, outputPath string) er
 
 	// Проверяем статус ответа
 	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("ошибка при скачивании файла, код ответа: %d", resp.StatusCode)
+		return statusError(resp.StatusCode, "ошибка при скачивании файла, код ответа: %d", resp.StatusCode)
 	}
 
-	// Создаем файл для сохранения скачанных данных
-	outputFile, err := os.Create(outputPath)
-	if err != nil {
-		return fmt.Errorf("ошибка при создании файла для сохранения: %w", err)
+	// Путь "-" означает вывод содержимого файла в stdout
+	var output io.Writer = os.Stdout
+	if outputPath != "-" {
+		// Создаем файл для сохранения скачанных данных с заданными правами
+		outputFile, err := os.OpenFile(outputPath, os.O_RDWR|os.O_CREATE|os.O_TRUNC, perm)
+		if err != nil {
+			return fmt.Errorf("ошибка при создании файла для сохранения: %w", err)
+		}
+		defer outputFile.Close()
+
+		// Права существующего файла OpenFile не меняет, поэтому закрытые права выставляем явно
+		if perm&0o077 == 0 {
+			if err := outputFile.Chmod(perm); err != nil {
+				return fmt.Errorf("ошибка при установке прав файла: %w", err)
+			}
+		}
+		output = outputFile
 	}
-	defer outputFile.Close()
 
 	// Копируем данные из ответа в файл
-	_, err = io.Copy(outputFile, resp.Body)
+	_, err = io.Copy(output, c.withProgress(resp.Body, resp.ContentLength))
 	if err != nil {
 		return fmt.Errorf("ошибка при сохранении файла: %w", err)
 	}
@@ -270,99 +494,153 @@ func (c *ClientService) DownloadFileFromServer(url string, outputPath string) er
 	return nil
 }
 
-// SaveText сохраняет текстовые данные
-func (c *ClientService) SaveText(label string, textData *domain.TextData, metadata string, token string) error {
-	url := fmt.Sprintf("http://%s/api/data/text/%s", c.serverAddr, label)
+// ListFiles получает список файлов пользователя
+func (c *ClientService) ListFiles(token string) ([]domain.FileInfo, error) {
+	url := c.baseURL() + "/api/file/list"
 
-	// Создаем структуру для запроса, включающую метаинформацию
-	requestData := struct {
-		TextData *domain.TextData `json:"text_data"`
-		Metadata string           `json:"metadata"`
-	}{
-		TextData: textData,
-		Metadata: metadata,
+	// Создаем запрос
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка при создании запроса: %w", err)
 	}
 
-	// Преобразуем данные в JSON
-	jsonData, err := json.Marshal(requestData)
+	// Устанавливаем заголовок авторизации
+	req.Header.Set("Authorization", token)
+
+	// Выполняем запрос
+	resp, err := c.client.Do(req)
 	if err != nil {
-		return fmt.Errorf("ошибка при маршалинге данных: %w", err)
+		return nil, fmt.Errorf("ошибка при выполнении запроса: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// Проверяем статус ответа
+	if resp.StatusCode != http.StatusOK {
+		return nil, statusError(resp.StatusCode, "ошибка при получении списка файлов, код ответа: %d", resp.StatusCode)
+	}
+
+	// Десериализуем данные
+	var files []domain.FileInfo
+	if err := json.NewDecoder(resp.Body).Decode(&files); err != nil {
+		return nil, fmt.Errorf("ошибка при десериализации данных: %w", err)
 	}
 
+	return files, nil
+}
+
+// ListItems получает сведения обо всех записях пользователя без их содержимого
+func (c *ClientService) ListItems(token string) ([]domain.ItemInfo, error) {
+	url := c.baseURL() + "/api/v2/items"
+
 	// Создаем запрос
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonData))
+	req, err := http.NewRequest("GET", url, nil)
 	if err != nil {
-		return fmt.Errorf("ошибка при создании запроса: %w", err)
+		return nil, fmt.Errorf("ошибка при создании запроса: %w", err)
 	}
 
-	// Устанавливаем заголовки
-	req.Header.Set("Content-Type", "application/json")
+	// Устанавливаем заголовок авторизации
 	req.Header.Set("Authorization", token)
 
 	// Выполняем запрос
 	resp, err := c.client.Do(req)
 	if err != nil {
-		return fmt.Errorf("ошибка при выполнении запроса: %w", err)
+		return nil, fmt.Errorf("ошибка при выполнении запроса: %w", err)
 	}
 	defer resp.Body.Close()
 
 	// Проверяем статус ответа
 	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("ошибка при сохранении текстовых данных, код ответа: %d", resp.StatusCode)
+		return nil, statusError(resp.StatusCode, "ошибка при получении списка записей, код ответа: %d", resp.StatusCode)
 	}
 
-	return nil
+	// Десериализуем данные
+	var items []domain.ItemInfo
+	if err := json.NewDecoder(resp.Body).Decode(&items); err != nil {
+		return nil, fmt.Errorf("ошибка при десериализации данных: %w", err)
+	}
+
+	return items, nil
 }
 
-// GetText получает текстовые данные
-func (c *ClientService) GetText(label string, token string) (*domain.TextData, string, error) {
-	url := fmt.Sprintf("http://%s/api/data/text/%s", c.serverAddr, label)
+// GetFileInfo получает сведения о файле
+func (c *ClientService) GetFileInfo(label string, token string) (*domain.FileInfo, error) {
+	url := fmt.Sprintf("%s/api/file/info?label=%s", c.baseURL(), neturl.QueryEscape(label))
 
 	// Создаем запрос
 	req, err := http.NewRequest("GET", url, nil)
 	if err != nil {
-		return nil, "", fmt.Errorf("ошибка при создании запроса: %w", err)
+		return nil, fmt.Errorf("ошибка при создании запроса: %w", err)
 	}
 
-	// Устанавливаем заголовки
-	req.Header.Set("Content-Type", "application/json")
+	// Устанавливаем заголовок авторизации
 	req.Header.Set("Authorization", token)
 
 	// Выполняем запрос
 	resp, err := c.client.Do(req)
 	if err != nil {
-		return nil, "", fmt.Errorf("ошибка при выполнении запроса: %w", err)
+		return nil, fmt.Errorf("ошибка при выполнении запроса: %w", err)
 	}
 	defer resp.Body.Close()
 
 	// Проверяем статус ответа
 	if resp.StatusCode == http.StatusNotFound {
-		return nil, "", fmt.Errorf("текстовые данные не найдены")
+		return nil, statusError(http.StatusNotFound, "файл не найден")
 	} else if resp.StatusCode != http.StatusOK {
-		return nil, "", fmt.Errorf("ошибка при получении текстовых данных, код ответа: %d", resp.StatusCode)
+		return nil, statusError(resp.StatusCode, "ошибка при получении сведений о файле, код ответа: %d", resp.StatusCode)
 	}
 
-	// Читаем ответ
-	body, err := ioutil.ReadAll(resp.Body)
+	// Десериализуем данные
+	var fileInfo domain.FileInfo
+	if err := json.NewDecoder(resp.Body).Decode(&fileInfo); err != nil {
+		return nil, fmt.Errorf("ошибка при десериализации данных: %w", err)
+	}
+
+	return &fileInfo, nil
+}
+
+// RenameFile меняет метку файла
+func (c *ClientService) RenameFile(label string, newLabel string, token string) error {
+	url := c.baseURL() + "/api/file/rename"
+
+	// Преобразуем данные в JSON
+	jsonData, err := json.Marshal(domain.FileRename{Label: label, NewLabel: newLabel})
 	if err != nil {
-		return nil, "", fmt.Errorf("ошибка при чтении ответа: %w", err)
+		return fmt.Errorf("ошибка при маршалинге данных: %w", err)
 	}
 
-	// Десериализуем данные
-	var response struct {
-		TextData *domain.TextData `json:"text_data"`
-		Metadata string           `json:"metadata"`
+	// Создаем запрос
+	req, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return fmt.Errorf("ошибка при создании запроса: %w", err)
 	}
-	if err := json.Unmarshal(body, &response); err != nil {
-		return nil, "", fmt.Errorf("ошибка при десериализации данных: %w", err)
+
+	// Устанавливаем заголовки
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", token)
+
+	// Выполняем запрос
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("ошибка при выполнении запроса: %w", err)
 	}
+	defer resp.Body.Close()
 
-	return response.TextData, response.Metadata, nil
+	// Проверяем статус ответа
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return nil
+	case http.StatusNotFound:
+		return statusError(http.StatusNotFound, "файл не найден")
+	case http.StatusConflict:
+		return fmt.Errorf("метка '%s' уже используется", newLabel)
+	default:
+		return statusError(resp.StatusCode, "ошибка при переименовании файла, код ответа: %d", resp.StatusCode)
+	}
 }
 
-// DeleteText удаляет текстовые данные
-func (c *ClientService) DeleteText(label string, token string) error {
-	url := fmt.Sprintf("http://%s/api/data/text/%s", c.serverAddr, label)
+// DeleteFile удаляет файл
+func (c *ClientService) DeleteFile(label string, token string) error {
+	url := fmt.Sprintf("%s/api/file?label=%s", c.baseURL(), neturl.QueryEscape(label))
 
 	// Создаем запрос
 	req, err := http.NewRequest("DELETE", url, nil)
@@ -370,8 +648,7 @@ func (c *ClientService) DeleteText(label string, token string) error {
 		return fmt.Errorf("ошибка при создании запроса: %w", err)
 	}
 
-	// Устанавливаем заголовки
-	req.Header.Set("Content-Type", "application/json")
+	// Устанавливаем заголовок авторизации
 	req.Header.Set("Authorization", token)
 
 	// Выполняем запрос
@@ -383,24 +660,60 @@ func (c *ClientService) DeleteText(label string, token string) error {
 
 	// Проверяем статус ответа
 	if resp.StatusCode == http.StatusNotFound {
-		return fmt.Errorf("текстовые данные не найдены")
+		return statusError(http.StatusNotFound, "файл не найден")
 	} else if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("ошибка при удалении текстовых данных, код ответа: %d", resp.StatusCode)
+		return statusError(resp.StatusCode, "ошибка при удалении файла, код ответа: %d", resp.StatusCode)
 	}
 
 	return nil
 }
 
-// SaveCard сохраняет данные кредитной карты
-func (c *ClientService) SaveCard(label string, cardData *domain.CardData, metadata string, token string) error {
-	url := fmt.Sprintf("http://%s/api/data/card/%s", c.serverAddr, label)
+// VerifyFile просит сервер проверить тип загруженного файла по его содержимому.
+// Файл запрещенного типа сервер удаляет, и возвращается ошибка domain.ErrFileTypeNotAllowed
+func (c *ClientService) VerifyFile(label string, token string) error {
+	url := fmt.Sprintf("%s/api/file/verify?label=%s", c.baseURL(), neturl.QueryEscape(label))
+
+	// Создаем запрос
+	req, err := http.NewRequest("POST", url, nil)
+	if err != nil {
+		return fmt.Errorf("ошибка при создании запроса: %w", err)
+	}
+
+	// Устанавливаем заголовок авторизации
+	req.Header.Set("Authorization", token)
+
+	// Выполняем запрос
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("ошибка при выполнении запроса: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// Проверяем статус ответа, в ответе 415 сервер указывает тип, определенный по содержимому
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return nil
+	case http.StatusUnsupportedMediaType:
+		message, _ := ioutil.ReadAll(resp.Body)
+		mimeType := strings.TrimPrefix(strings.TrimSpace(string(message)), domain.ErrFileTypeNotAllowed.Error()+": ")
+		return fmt.Errorf("%w: %s", domain.ErrFileTypeNotAllowed, mimeType)
+	case http.StatusNotFound:
+		return statusError(http.StatusNotFound, "файл не найден")
+	default:
+		return statusError(resp.StatusCode, "ошибка при проверке файла, код ответа: %d", resp.StatusCode)
+	}
+}
+
+// SaveItem сохраняет запись зарегистрированного типа
+func (c *ClientService) SaveItem(itemType string, label string, data domain.ItemData, metadata string, token string) error {
+	url := fmt.Sprintf("%s/api/v2/items/%s/%s", c.baseURL(), neturl.PathEscape(itemType), neturl.PathEscape(label))
 
 	// Создаем структуру для запроса, включающую метаинформацию
 	requestData := struct {
-		CardData *domain.CardData `json:"card_data"`
-		Metadata string           `json:"metadata"`
+		Data     domain.ItemData `json:"data"`
+		Metadata string          `json:"metadata"`
 	}{
-		CardData: cardData,
+		Data:     data,
 		Metadata: metadata,
 	}
 
@@ -428,16 +741,23 @@ func (c *ClientService) SaveCard(label string, cardData *domain.CardData, metada
 	defer resp.Body.Close()
 
 	// Проверяем статус ответа
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("ошибка при сохранении данных карты, код ответа: %d", resp.StatusCode)
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return nil
+	case http.StatusRequestEntityTooLarge:
+		message, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("%w: %s", domain.ErrQuotaExceeded, strings.TrimSpace(string(message)))
+	case http.StatusBadRequest, http.StatusNotFound:
+		message, _ := ioutil.ReadAll(resp.Body)
+		return statusError(resp.StatusCode, "запись отклонена сервером: %s", strings.TrimSpace(string(message)))
+	default:
+		return statusError(resp.StatusCode, "ошибка при сохранении записи, код ответа: %d", resp.StatusCode)
 	}
-
-	return nil
 }
 
-// GetCard получает данные кредитной карты
-func (c *ClientService) GetCard(label string, token string) (*domain.CardData, string, error) {
-	url := fmt.Sprintf("http://%s/api/data/card/%s", c.serverAddr, label)
+// GetItem получает запись зарегистрированного типа
+func (c *ClientService) GetItem(itemType string, label string, token string) (domain.ItemData, string, error) {
+	url := fmt.Sprintf("%s/api/v2/items/%s/%s", c.baseURL(), neturl.PathEscape(itemType), neturl.PathEscape(label))
 
 	// Создаем запрос
 	req, err := http.NewRequest("GET", url, nil)
@@ -458,9 +778,9 @@ func (c *ClientService) GetCard(label string, token string) (*domain.CardData, s
 
 	// Проверяем статус ответа
 	if resp.StatusCode == http.StatusNotFound {
-		return nil, "", fmt.Errorf("данные карты не найдены")
+		return nil, "", statusError(http.StatusNotFound, "запись '%s' типа %s не найдена", label, itemType)
 	} else if resp.StatusCode != http.StatusOK {
-		return nil, "", fmt.Errorf("ошибка при получении данных карты, код ответа: %d", resp.StatusCode)
+		return nil, "", statusError(resp.StatusCode, "ошибка при получении записи, код ответа: %d", resp.StatusCode)
 	}
 
 	// Читаем ответ
@@ -471,19 +791,19 @@ func (c *ClientService) GetCard(label string, token string) (*domain.CardData, s
 
 	// Десериализуем данные
 	var response struct {
-		CardData *domain.CardData `json:"card_data"`
-		Metadata string           `json:"metadata"`
+		Data     domain.ItemData `json:"data"`
+		Metadata string          `json:"metadata"`
 	}
 	if err := json.Unmarshal(body, &response); err != nil {
 		return nil, "", fmt.Errorf("ошибка при десериализации данных: %w", err)
 	}
 
-	return response.CardData, response.Metadata, nil
+	return response.Data, response.Metadata, nil
 }
 
-// DeleteCard удаляет данные кредитной карты
-func (c *ClientService) DeleteCard(label string, token string) error {
-	url := fmt.Sprintf("http://%s/api/data/card/%s", c.serverAddr, label)
+// DeleteItem удаляет запись зарегистрированного типа
+func (c *ClientService) DeleteItem(itemType string, label string, token string) error {
+	url := fmt.Sprintf("%s/api/v2/items/%s/%s", c.baseURL(), neturl.PathEscape(itemType), neturl.PathEscape(label))
 
 	// Создаем запрос
 	req, err := http.NewRequest("DELETE", url, nil)
@@ -504,35 +824,20 @@ func (c *ClientService) DeleteCard(label string, token string) error {
 
 	// Проверяем статус ответа
 	if resp.StatusCode == http.StatusNotFound {
-		return fmt.Errorf("данные карты не найдены")
+		return statusError(http.StatusNotFound, "запись '%s' типа %s не найдена", label, itemType)
 	} else if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("ошибка при удалении данных карты, код ответа: %d", resp.StatusCode)
+		return statusError(resp.StatusCode, "ошибка при удалении записи, код ответа: %d", resp.StatusCode)
 	}
 
 	return nil
 }
 
-// SaveCredential сохраняет учетные данные
-func (c *ClientService) SaveCredential(label string, credentialData *domain.CredentialData, metadata string, token string) error {
-	url := fmt.Sprintf("http://%s/api/data/credential/%s", c.serverAddr, label)
-
-	// Создаем структуру для запроса, включающую метаинформацию
-	requestData := struct {
-		CredentialData *domain.CredentialData `json:"credential_data"`
-		Metadata       string                 `json:"metadata"`
-	}{
-		CredentialData: credentialData,
-		Metadata:       metadata,
-	}
-
-	// Преобразуем данные в JSON
-	jsonData, err := json.Marshal(requestData)
-	if err != nil {
-		return fmt.Errorf("ошибка при маршалинге данных: %w", err)
-	}
+// SaveItemTemplate сохраняет шаблон пользовательского типа записей
+func (c *ClientService) SaveItemTemplate(name string, schema json.RawMessage, token string) error {
+	url := fmt.Sprintf("%s/api/v2/templates/%s", c.baseURL(), neturl.PathEscape(name))
 
-	// Создаем запрос
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonData))
+	// Создаем запрос, схема передается телом целиком
+	req, err := http.NewRequest("POST", url, bytes.NewReader(schema))
 	if err != nil {
 		return fmt.Errorf("ошибка при создании запроса: %w", err)
 	}
@@ -549,62 +854,90 @@ func (c *ClientService) SaveCredential(label string, credentialData *domain.Cred
 	defer resp.Body.Close()
 
 	// Проверяем статус ответа
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("ошибка при сохранении учетных данных, код ответа: %d", resp.StatusCode)
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return nil
+	case http.StatusBadRequest:
+		message, _ := ioutil.ReadAll(resp.Body)
+		return statusError(resp.StatusCode, "шаблон отклонен сервером: %s", strings.TrimSpace(string(message)))
+	default:
+		return statusError(resp.StatusCode, "ошибка при сохранении шаблона, код ответа: %d", resp.StatusCode)
 	}
-
-	return nil
 }
 
-// GetCredential получает учетные данные
-func (c *ClientService) GetCredential(label string, token string) (*domain.CredentialData, string, error) {
-	url := fmt.Sprintf("http://%s/api/data/credential/%s", c.serverAddr, label)
+// GetItemTemplate получает шаблон пользовательского типа записей
+func (c *ClientService) GetItemTemplate(name string, token string) (*domain.ItemTemplate, error) {
+	url := fmt.Sprintf("%s/api/v2/templates/%s", c.baseURL(), neturl.PathEscape(name))
 
 	// Создаем запрос
 	req, err := http.NewRequest("GET", url, nil)
 	if err != nil {
-		return nil, "", fmt.Errorf("ошибка при создании запроса: %w", err)
+		return nil, fmt.Errorf("ошибка при создании запроса: %w", err)
 	}
 
-	// Устанавливаем заголовки
-	req.Header.Set("Content-Type", "application/json")
+	// Устанавливаем заголовок авторизации
 	req.Header.Set("Authorization", token)
 
 	// Выполняем запрос
 	resp, err := c.client.Do(req)
 	if err != nil {
-		return nil, "", fmt.Errorf("ошибка при выполнении запроса: %w", err)
+		return nil, fmt.Errorf("ошибка при выполнении запроса: %w", err)
 	}
 	defer resp.Body.Close()
 
 	// Проверяем статус ответа
 	if resp.StatusCode == http.StatusNotFound {
-		return nil, "", fmt.Errorf("учетные данные не найдены")
+		return nil, statusError(http.StatusNotFound, "шаблон '%s' не найден", name)
 	} else if resp.StatusCode != http.StatusOK {
-		return nil, "", fmt.Errorf("ошибка при получении учетных данных, код ответа: %d", resp.StatusCode)
+		return nil, statusError(resp.StatusCode, "ошибка при получении шаблона, код ответа: %d", resp.StatusCode)
 	}
 
-	// Читаем ответ
-	body, err := ioutil.ReadAll(resp.Body)
+	// Десериализуем данные
+	var template domain.ItemTemplate
+	if err := json.NewDecoder(resp.Body).Decode(&template); err != nil {
+		return nil, fmt.Errorf("ошибка при десериализации данных: %w", err)
+	}
+
+	return &template, nil
+}
+
+// ListItemTemplates получает все шаблоны пользовательских типов записей
+func (c *ClientService) ListItemTemplates(token string) ([]domain.ItemTemplate, error) {
+	url := c.baseURL() + "/api/v2/templates"
+
+	// Создаем запрос
+	req, err := http.NewRequest("GET", url, nil)
 	if err != nil {
-		return nil, "", fmt.Errorf("ошибка при чтении ответа: %w", err)
+		return nil, fmt.Errorf("ошибка при создании запроса: %w", err)
 	}
 
-	// Десериализуем данные
-	var response struct {
-		CredentialData *domain.CredentialData `json:"credential_data"`
-		Metadata       string                 `json:"metadata"`
+	// Устанавливаем заголовок авторизации
+	req.Header.Set("Authorization", token)
+
+	// Выполняем запрос
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка при выполнении запроса: %w", err)
 	}
-	if err := json.Unmarshal(body, &response); err != nil {
-		return nil, "", fmt.Errorf("ошибка при десериализации данных: %w", err)
+	defer resp.Body.Close()
+
+	// Проверяем статус ответа
+	if resp.StatusCode != http.StatusOK {
+		return nil, statusError(resp.StatusCode, "ошибка при получении списка шаблонов, код ответа: %d", resp.StatusCode)
+	}
+
+	// Десериализуем данные
+	var templates []domain.ItemTemplate
+	if err := json.NewDecoder(resp.Body).Decode(&templates); err != nil {
+		return nil, fmt.Errorf("ошибка при десериализации данных: %w", err)
 	}
 
-	return response.CredentialData, response.Metadata, nil
+	return templates, nil
 }
 
-// DeleteCredential удаляет учетные данные
-func (c *ClientService) DeleteCredential(label string, token string) error {
-	url := fmt.Sprintf("http://%s/api/data/credential/%s", c.serverAddr, label)
+// DeleteItemTemplate удаляет шаблон пользовательского типа записей
+func (c *ClientService) DeleteItemTemplate(name string, token string) error {
+	url := fmt.Sprintf("%s/api/v2/templates/%s", c.baseURL(), neturl.PathEscape(name))
 
 	// Создаем запрос
 	req, err := http.NewRequest("DELETE", url, nil)
@@ -612,8 +945,7 @@ func (c *ClientService) DeleteCredential(label string, token string) error {
 		return fmt.Errorf("ошибка при создании запроса: %w", err)
 	}
 
-	// Устанавливаем заголовки
-	req.Header.Set("Content-Type", "application/json")
+	// Устанавливаем заголовок авторизации
 	req.Header.Set("Authorization", token)
 
 	// Выполняем запрос
@@ -624,11 +956,15 @@ func (c *ClientService) DeleteCredential(label string, token string) error {
 	defer resp.Body.Close()
 
 	// Проверяем статус ответа
-	if resp.StatusCode == http.StatusNotFound {
-		return fmt.Errorf("учетные данные не найдены")
-	} else if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("ошибка при удалении учетных данных, код ответа: %d", resp.StatusCode)
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return nil
+	case http.StatusNotFound:
+		return statusError(http.StatusNotFound, "шаблон '%s' не найден", name)
+	case http.StatusConflict:
+		message, _ := ioutil.ReadAll(resp.Body)
+		return statusError(resp.StatusCode, "%s", strings.TrimSpace(string(message)))
+	default:
+		return statusError(resp.StatusCode, "ошибка при удалении шаблона, код ответа: %d", resp.StatusCode)
 	}
-
-	return nil
 }
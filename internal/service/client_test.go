@@ -2,597 +2,253 @@ package service
 
 import (
 	"encoding/json"
+	"encoding/pem"
+	"errors"
+	config "github.com/SmirnovND/gophkeeper/internal/config/client"
 	"github.com/SmirnovND/gophkeeper/internal/domain"
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
 	"testing"
 	"time"
 )
 
-// Тестирование методов для работы с текстовыми данными
-func TestClientService_TextData(t *testing.T) {
+// Тестирование методов для работы с записями зарегистрированных типов через API v2
+func TestClientService_ItemData(t *testing.T) {
 	// Создаем тестовый сервер
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		// Проверяем заголовок Authorization
 		if r.Header.Get("Authorization") != "test-token" {
-			t.Errorf("Ожидался Authorization test-token, получен %s", r.Header.Get("Authorization"))
-		}
-
-		// Проверяем заголовок Content-Type
-		if r.Method == "POST" && r.Header.Get("Content-Type") != "application/json" {
-			t.Errorf("Ожидался Content-Type application/json, получен %s", r.Header.Get("Content-Type"))
+			w.WriteHeader(http.StatusUnauthorized)
+			return
 		}
 
 		// Обрабатываем разные методы и пути
-		if r.Method == "POST" && r.URL.Path == "/api/data/text/test-text" {
-			// Сохранение текстовых данных
-			body, err := ioutil.ReadAll(r.Body)
-			if err != nil {
-				t.Fatalf("Ошибка при чтении тела запроса: %v", err)
+		switch {
+		case r.Method == "POST" && r.URL.Path == "/api/v2/items/card/test-card":
+			// Сохранение записи
+			if r.Header.Get("Content-Type") != "application/json" {
+				t.Errorf("Ожидался Content-Type application/json, получен %s", r.Header.Get("Content-Type"))
 			}
-
 			var requestData struct {
-				TextData *domain.TextData `json:"text_data"`
-				Metadata string           `json:"metadata"`
+				Data     domain.ItemData `json:"data"`
+				Metadata string          `json:"metadata"`
 			}
-			if err := json.Unmarshal(body, &requestData); err != nil {
+			if err := json.NewDecoder(r.Body).Decode(&requestData); err != nil {
 				t.Fatalf("Ошибка при декодировании JSON: %v", err)
 			}
-
-			if requestData.TextData == nil || requestData.TextData.Content != "test text content" {
-				content := ""
-				if requestData.TextData != nil {
-					content = requestData.TextData.Content
-				}
-				t.Errorf("Ожидался текст 'test text content', получен '%s'", content)
+			if requestData.Data["number"] != "1234 5678 9012 3456" || requestData.Metadata != "test metadata" {
+				t.Errorf("Неверные данные запроса: %+v", requestData)
 			}
-
 			w.WriteHeader(http.StatusOK)
-		} else if r.Method == "GET" && r.URL.Path == "/api/data/text/test-text" {
-			// Получение текстовых данных
-			response := struct {
-				TextData *domain.TextData `json:"text_data"`
-				Metadata string           `json:"metadata"`
-			}{
-				TextData: &domain.TextData{
-					Content: "test text content",
-				},
-				Metadata: "",
-			}
-			responseJSON, _ := json.Marshal(response)
+		case r.Method == "GET" && r.URL.Path == "/api/v2/items/card/test-card":
+			// Получение записи
 			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"data":{"number":"1234 5678 9012 3456","cvv":"123"},"metadata":"test metadata"}`))
+		case r.Method == "DELETE" && r.URL.Path == "/api/v2/items/card/test-card":
+			// Удаление записи
 			w.WriteHeader(http.StatusOK)
-			w.Write(responseJSON)
-		} else if r.Method == "DELETE" && r.URL.Path == "/api/data/text/test-text" {
-			// Удаление текстовых данных
-			w.WriteHeader(http.StatusOK)
-		} else {
-			t.Errorf("Неожиданный запрос: %s %s", r.Method, r.URL.Path)
-			w.WriteHeader(http.StatusNotFound)
-		}
-	}))
-	defer server.Close()
-
-	// Создаем клиентский сервис с адресом тестового сервера
-	serverAddr := server.URL[7:] // Убираем "http://" из URL
-	clientService := NewClientService(serverAddr)
-
-	// Тестируем SaveText
-	textData := &domain.TextData{
-		Content: "test text content",
-	}
-	err := clientService.SaveText("test-text", textData, "", "test-token")
-	if err != nil {
-		t.Fatalf("Ошибка при вызове SaveText: %v", err)
-	}
-
-	// Тестируем GetText
-	retrievedTextData, _, err := clientService.GetText("test-text", "test-token")
-	if err != nil {
-		t.Fatalf("Ошибка при вызове GetText: %v", err)
-	}
-	if retrievedTextData.Content != "test text content" {
-		t.Errorf("Ожидался текст 'test text content', получен '%s'", retrievedTextData.Content)
-	}
-
-	// Тестируем DeleteText
-	err = clientService.DeleteText("test-text", "test-token")
-	if err != nil {
-		t.Fatalf("Ошибка при вызове DeleteText: %v", err)
-	}
-
-	// Тестируем ошибки в методах работы с текстовыми данными
-	// Создаем тестовый сервер для проверки ошибок
-	textErrorServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		// Проверяем заголовок Authorization
-		if r.Header.Get("Authorization") != "test-token" {
-			w.WriteHeader(http.StatusUnauthorized)
-			return
-		}
-
-		// Обрабатываем разные методы и пути
-		if r.Method == "POST" && r.URL.Path == "/api/data/text/error-text" {
-			// Возвращаем ошибку сервера при сохранении
-			w.WriteHeader(http.StatusInternalServerError)
-		} else if r.Method == "GET" && r.URL.Path == "/api/data/text/not-found-text" {
-			// Возвращаем ошибку "не найдено" при получении
-			w.WriteHeader(http.StatusNotFound)
-		} else if r.Method == "GET" && r.URL.Path == "/api/data/text/error-text" {
-			// Возвращаем ошибку сервера при получении
-			w.WriteHeader(http.StatusInternalServerError)
-		} else if r.Method == "GET" && r.URL.Path == "/api/data/text/invalid-json" {
+		case r.Method == "POST" && r.URL.Path == "/api/v2/items/card/invalid-card":
+			// Сервер отклоняет неверные поля записи
+			http.Error(w, "поле 'number' обязательно", http.StatusBadRequest)
+		case r.Method == "GET" && r.URL.Path == "/api/v2/items/card/invalid-json":
 			// Возвращаем некорректный JSON при получении
 			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(http.StatusOK)
 			w.Write([]byte("{invalid json}"))
-		} else if r.Method == "DELETE" && r.URL.Path == "/api/data/text/not-found-text" {
-			// Возвращаем ошибку "не найдено" при удалении
-			w.WriteHeader(http.StatusNotFound)
-		} else if r.Method == "DELETE" && r.URL.Path == "/api/data/text/error-text" {
-			// Возвращаем ошибку сервера при удалении
+		case r.URL.Path == "/api/v2/items/card/error-card":
+			// Возвращаем ошибку сервера
 			w.WriteHeader(http.StatusInternalServerError)
-		}
-	}))
-	defer textErrorServer.Close()
-
-	// Создаем клиентский сервис с адресом тестового сервера для ошибок
-	textErrorServerAddr := textErrorServer.URL[7:] // Убираем "http://" из URL
-	textErrorClientService := NewClientService(textErrorServerAddr)
-
-	// Тестируем ошибки в SaveText
-	// Тест на ошибку авторизации
-	err = textErrorClientService.SaveText("test-text", &domain.TextData{Content: "test"}, "", "invalid-token")
-	if err == nil {
-		t.Error("Ожидалась ошибка авторизации при сохранении текста, но ее не было")
-	}
-
-	// Тест на ошибку сервера
-	err = textErrorClientService.SaveText("error-text", &domain.TextData{Content: "test"}, "", "test-token")
-	if err == nil {
-		t.Error("Ожидалась ошибка сервера при сохранении текста, но ее не было")
-	}
-
-	// Тестируем ошибки в GetText
-	// Тест на ошибку авторизации
-	_, _, err = textErrorClientService.GetText("test-text", "invalid-token")
-	if err == nil {
-		t.Error("Ожидалась ошибка авторизации при получении текста, но ее не было")
-	}
-
-	// Тест на ошибку "не найдено"
-	_, _, err = textErrorClientService.GetText("not-found-text", "test-token")
-	if err == nil || err.Error() != "текстовые данные не найдены" {
-		t.Errorf("Ожидалась ошибка 'текстовые данные не найдены', получено: %v", err)
-	}
-
-	// Тест на ошибку сервера
-	_, _, err = textErrorClientService.GetText("error-text", "test-token")
-	if err == nil {
-		t.Error("Ожидалась ошибка сервера при получении текста, но ее не было")
-	}
-
-	// Тест на некорректный JSON
-	_, _, err = textErrorClientService.GetText("invalid-json", "test-token")
-	if err == nil {
-		t.Error("Ожидалась ошибка при парсинге JSON, но ее не было")
-	}
-
-	// Тестируем ошибки в DeleteText
-	// Тест на ошибку авторизации
-	err = textErrorClientService.DeleteText("test-text", "invalid-token")
-	if err == nil {
-		t.Error("Ожидалась ошибка авторизации при удалении текста, но ее не было")
-	}
-
-	// Тест на ошибку "не найдено"
-	err = textErrorClientService.DeleteText("not-found-text", "test-token")
-	if err == nil || err.Error() != "текстовые данные не найдены" {
-		t.Errorf("Ожидалась ошибка 'текстовые данные не найдены', получено: %v", err)
-	}
-
-	// Тест на ошибку сервера
-	err = textErrorClientService.DeleteText("error-text", "test-token")
-	if err == nil {
-		t.Error("Ожидалась ошибка сервера при удалении текста, но ее не было")
-	}
-}
-
-// Тестирование методов для работы с данными кредитных карт
-func TestClientService_CardData(t *testing.T) {
-	// Создаем тестовый сервер
-	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		// Проверяем заголовок Authorization
-		if r.Header.Get("Authorization") != "test-token" {
-			t.Errorf("Ожидался Authorization test-token, получен %s", r.Header.Get("Authorization"))
-		}
-
-		// Проверяем заголовок Content-Type
-		if r.Method == "POST" && r.Header.Get("Content-Type") != "application/json" {
-			t.Errorf("Ожидался Content-Type application/json, получен %s", r.Header.Get("Content-Type"))
-		}
-
-		// Обрабатываем разные методы и пути
-		if r.Method == "POST" && r.URL.Path == "/api/data/card/test-card" {
-			// Сохранение данных карты
-			body, err := ioutil.ReadAll(r.Body)
-			if err != nil {
-				t.Fatalf("Ошибка при чтении тела запроса: %v", err)
-			}
-
-			var requestData struct {
-				CardData *domain.CardData `json:"card_data"`
-				Metadata string           `json:"metadata"`
-			}
-			if err := json.Unmarshal(body, &requestData); err != nil {
-				t.Fatalf("Ошибка при декодировании JSON: %v", err)
-			}
-
-			if requestData.CardData == nil || requestData.CardData.Number != "1234567890123456" {
-				number := ""
-				if requestData.CardData != nil {
-					number = requestData.CardData.Number
-				}
-				t.Errorf("Ожидался номер карты '1234567890123456', получен '%s'", number)
-			}
-
-			w.WriteHeader(http.StatusOK)
-		} else if r.Method == "GET" && r.URL.Path == "/api/data/card/test-card" {
-			// Получение данных карты
-			response := struct {
-				CardData *domain.CardData `json:"card_data"`
-				Metadata string           `json:"metadata"`
-			}{
-				CardData: &domain.CardData{
-					Number:     "1234567890123456",
-					Holder:     "Test User",
-					ExpiryDate: "12/25",
-					CVV:        "123",
-				},
-				Metadata: "test metadata",
-			}
-			responseJSON, _ := json.Marshal(response)
-			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(http.StatusOK)
-			w.Write(responseJSON)
-		} else if r.Method == "DELETE" && r.URL.Path == "/api/data/card/test-card" {
-			// Удаление данных карты
-			w.WriteHeader(http.StatusOK)
-		} else {
-			t.Errorf("Неожиданный запрос: %s %s", r.Method, r.URL.Path)
+		default:
 			w.WriteHeader(http.StatusNotFound)
 		}
 	}))
 	defer server.Close()
 
 	// Создаем клиентский сервис с адресом тестового сервера
-	serverAddr := server.URL[7:] // Убираем "http://" из URL
-	clientService := NewClientService(serverAddr)
+	clientService := NewClientService(server.URL[7:])
 
-	// Тестируем SaveCard
-	cardData := &domain.CardData{
-		Number:     "1234567890123456",
-		Holder:     "Test User",
-		ExpiryDate: "12/25",
-		CVV:        "123",
-	}
-	err := clientService.SaveCard("test-card", cardData, "test metadata", "test-token")
-	if err != nil {
-		t.Fatalf("Ошибка при вызове SaveCard: %v", err)
+	// Тестируем SaveItem
+	cardData := domain.ItemData{"number": "1234 5678 9012 3456", "cvv": "123"}
+	if err := clientService.SaveItem(domain.UserDataTypeCard, "test-card", cardData, "test metadata", "test-token"); err != nil {
+		t.Fatalf("Ошибка при вызове SaveItem: %v", err)
 	}
 
-	// Тестируем GetCard
-	retrievedCardData, metadata, err := clientService.GetCard("test-card", "test-token")
+	// Тестируем GetItem
+	data, metadata, err := clientService.GetItem(domain.UserDataTypeCard, "test-card", "test-token")
 	if err != nil {
-		t.Fatalf("Ошибка при вызове GetCard: %v", err)
+		t.Fatalf("Ошибка при вызове GetItem: %v", err)
 	}
-	if retrievedCardData.Number != "1234567890123456" {
-		t.Errorf("Ожидался номер карты '1234567890123456', получен '%s'", retrievedCardData.Number)
+	if data["number"] != "1234 5678 9012 3456" || data["cvv"] != "123" {
+		t.Errorf("Неверное содержимое записи: %v", data)
 	}
 	if metadata != "test metadata" {
-		t.Errorf("Ожидались метаданные 'test metadata', получены '%s'", metadata)
+		t.Errorf("Ожидалась метаинформация 'test metadata', получена '%s'", metadata)
 	}
 
-	// Тестируем DeleteCard
-	err = clientService.DeleteCard("test-card", "test-token")
-	if err != nil {
-		t.Fatalf("Ошибка при вызове DeleteCard: %v", err)
+	// Тестируем DeleteItem
+	if err := clientService.DeleteItem(domain.UserDataTypeCard, "test-card", "test-token"); err != nil {
+		t.Fatalf("Ошибка при вызове DeleteItem: %v", err)
 	}
 
-	// Тестируем ошибки в методах работы с данными карт
-	// Создаем тестовый сервер для проверки ошибок
-	cardErrorServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		// Проверяем заголовок Authorization
-		if r.Header.Get("Authorization") != "test-token" {
-			w.WriteHeader(http.StatusUnauthorized)
-			return
-		}
-
-		// Обрабатываем разные методы и пути
-		if r.Method == "POST" && r.URL.Path == "/api/data/card/error-card" {
-			// Возвращаем ошибку сервера при сохранении
-			w.WriteHeader(http.StatusInternalServerError)
-		} else if r.Method == "GET" && r.URL.Path == "/api/data/card/not-found-card" {
-			// Возвращаем ошибку "не найдено" при получении
-			w.WriteHeader(http.StatusNotFound)
-		} else if r.Method == "GET" && r.URL.Path == "/api/data/card/error-card" {
-			// Возвращаем ошибку сервера при получении
-			w.WriteHeader(http.StatusInternalServerError)
-		} else if r.Method == "GET" && r.URL.Path == "/api/data/card/invalid-json" {
-			// Возвращаем некорректный JSON при получении
-			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(http.StatusOK)
-			w.Write([]byte("{invalid json}"))
-		} else if r.Method == "DELETE" && r.URL.Path == "/api/data/card/not-found-card" {
-			// Возвращаем ошибку "не найдено" при удалении
-			w.WriteHeader(http.StatusNotFound)
-		} else if r.Method == "DELETE" && r.URL.Path == "/api/data/card/error-card" {
-			// Возвращаем ошибку сервера при удалении
-			w.WriteHeader(http.StatusInternalServerError)
-		}
-	}))
-	defer cardErrorServer.Close()
-
-	// Создаем клиентский сервис с адресом тестового сервера для ошибок
-	cardErrorServerAddr := cardErrorServer.URL[7:] // Убираем "http://" из URL
-	cardErrorClientService := NewClientService(cardErrorServerAddr)
-
-	// Тестируем ошибки в SaveCard
-	// Тест на ошибку авторизации
-	err = cardErrorClientService.SaveCard("test-card", &domain.CardData{Number: "1234"}, "", "invalid-token")
-	if err == nil {
-		t.Error("Ожидалась ошибка авторизации при сохранении данных карты, но ее не было")
+	// Тестируем ошибки
+	var statusErr *domain.Error
+	err = clientService.SaveItem(domain.UserDataTypeCard, "invalid-card", domain.ItemData{}, "", "test-token")
+	if !errors.As(err, &statusErr) || statusErr.Code() != http.StatusBadRequest || !strings.Contains(err.Error(), "поле 'number' обязательно") {
+		t.Errorf("Ожидалась ошибка с кодом 400 и причиной отказа, получена %v", err)
 	}
-
-	// Тест на ошибку сервера
-	err = cardErrorClientService.SaveCard("error-card", &domain.CardData{Number: "1234"}, "", "test-token")
-	if err == nil {
-		t.Error("Ожидалась ошибка сервера при сохранении данных карты, но ее не было")
+	if err := clientService.SaveItem(domain.UserDataTypeCard, "error-card", cardData, "", "test-token"); err == nil {
+		t.Error("Ожидалась ошибка сервера при сохранении записи, но ее не было")
 	}
-
-	// Тестируем ошибки в GetCard
-	// Тест на ошибку авторизации
-	_, _, err = cardErrorClientService.GetCard("test-card", "invalid-token")
-	if err == nil {
-		t.Error("Ожидалась ошибка авторизации при получении данных карты, но ее не было")
+	if err := clientService.SaveItem(domain.UserDataTypeCard, "test-card", cardData, "", "invalid-token"); err == nil {
+		t.Error("Ожидалась ошибка авторизации при сохранении записи, но ее не было")
 	}
 
-	// Тест на ошибку "не найдено"
-	_, _, err = cardErrorClientService.GetCard("not-found-card", "test-token")
-	if err == nil || err.Error() != "данные карты не найдены" {
-		t.Errorf("Ожидалась ошибка 'данные карты не найдены', получено: %v", err)
+	_, _, err = clientService.GetItem(domain.UserDataTypeCard, "not-found-card", "test-token")
+	if !errors.As(err, &statusErr) || statusErr.Code() != http.StatusNotFound {
+		t.Errorf("Ожидалась ошибка с кодом 404, получена %v", err)
 	}
-
-	// Тест на ошибку сервера
-	_, _, err = cardErrorClientService.GetCard("error-card", "test-token")
-	if err == nil {
-		t.Error("Ожидалась ошибка сервера при получении данных карты, но ее не было")
+	if _, _, err := clientService.GetItem(domain.UserDataTypeCard, "error-card", "test-token"); err == nil {
+		t.Error("Ожидалась ошибка сервера при получении записи, но ее не было")
 	}
-
-	// Тест на некорректный JSON
-	_, _, err = cardErrorClientService.GetCard("invalid-json", "test-token")
-	if err == nil {
+	if _, _, err := clientService.GetItem(domain.UserDataTypeCard, "invalid-json", "test-token"); err == nil {
 		t.Error("Ожидалась ошибка при парсинге JSON, но ее не было")
 	}
 
-	// Тестируем ошибки в DeleteCard
-	// Тест на ошибку авторизации
-	err = cardErrorClientService.DeleteCard("test-card", "invalid-token")
-	if err == nil {
-		t.Error("Ожидалась ошибка авторизации при удалении данных карты, но ее не было")
+	err = clientService.DeleteItem(domain.UserDataTypeCard, "not-found-card", "test-token")
+	if !errors.As(err, &statusErr) || statusErr.Code() != http.StatusNotFound {
+		t.Errorf("Ожидалась ошибка с кодом 404, получена %v", err)
 	}
-
-	// Тест на ошибку "не найдено"
-	err = cardErrorClientService.DeleteCard("not-found-card", "test-token")
-	if err == nil || err.Error() != "данные карты не найдены" {
-		t.Errorf("Ожидалась ошибка 'данные карты не найдены', получено: %v", err)
-	}
-
-	// Тест на ошибку сервера
-	err = cardErrorClientService.DeleteCard("error-card", "test-token")
-	if err == nil {
-		t.Error("Ожидалась ошибка сервера при удалении данных карты, но ее не было")
+	if err := clientService.DeleteItem(domain.UserDataTypeCard, "error-card", "test-token"); err == nil {
+		t.Error("Ожидалась ошибка сервера при удалении записи, но ее не было")
 	}
 }
 
-// Тестирование методов для работы с учетными данными
-func TestClientService_CredentialData(t *testing.T) {
+// TestClientService_ItemTemplates тестирует запросы шаблонов пользовательских типов записей
+func TestClientService_ItemTemplates(t *testing.T) {
+	schema := `{"type":"object","properties":{"host":{"type":"string"}}}`
+
 	// Создаем тестовый сервер
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		// Проверяем заголовок Authorization
 		if r.Header.Get("Authorization") != "test-token" {
-			t.Errorf("Ожидался Authorization test-token, получен %s", r.Header.Get("Authorization"))
-		}
-
-		// Проверяем заголовок Content-Type
-		if r.Method == "POST" && r.Header.Get("Content-Type") != "application/json" {
-			t.Errorf("Ожидался Content-Type application/json, получен %s", r.Header.Get("Content-Type"))
+			w.WriteHeader(http.StatusUnauthorized)
+			return
 		}
 
-		// Обрабатываем разные методы и пути
-		if r.Method == "POST" && r.URL.Path == "/api/data/credential/test-credential" {
-			// Сохранение учетных данных
-			body, err := ioutil.ReadAll(r.Body)
-			if err != nil {
-				t.Fatalf("Ошибка при чтении тела запроса: %v", err)
+		switch {
+		case r.Method == "POST" && r.URL.Path == "/api/v2/templates/db_profile":
+			// Схема передается телом запроса без изменений
+			body, _ := ioutil.ReadAll(r.Body)
+			if string(body) != schema {
+				t.Errorf("Неверное тело запроса: %s", body)
 			}
-
-			var requestData struct {
-				CredentialData *domain.CredentialData `json:"credential_data"`
-				Metadata       string                 `json:"metadata"`
-			}
-			if err := json.Unmarshal(body, &requestData); err != nil {
-				t.Fatalf("Ошибка при декодировании JSON: %v", err)
-			}
-
-			if requestData.CredentialData == nil || requestData.CredentialData.Login != "testuser" {
-				login := ""
-				if requestData.CredentialData != nil {
-					login = requestData.CredentialData.Login
-				}
-				t.Errorf("Ожидался логин 'testuser', получен '%s'", login)
-			}
-
 			w.WriteHeader(http.StatusOK)
-		} else if r.Method == "GET" && r.URL.Path == "/api/data/credential/test-credential" {
-			// Получение учетных данных
-			response := struct {
-				CredentialData *domain.CredentialData `json:"credential_data"`
-				Metadata       string                 `json:"metadata"`
-			}{
-				CredentialData: &domain.CredentialData{
-					Login:    "testuser",
-					Password: "testpass",
-				},
-				Metadata: "test metadata",
-			}
-			responseJSON, _ := json.Marshal(response)
+		case r.Method == "POST" && r.URL.Path == "/api/v2/templates/wifi":
+			http.Error(w, "неверная схема шаблона: имя 'wifi' занято встроенным типом", http.StatusBadRequest)
+		case r.Method == "GET" && r.URL.Path == "/api/v2/templates/db_profile":
 			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"name":"db_profile","schema":` + schema + `}`))
+		case r.Method == "GET" && r.URL.Path == "/api/v2/templates":
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`[{"name":"db_profile","schema":` + schema + `}]`))
+		case r.Method == "DELETE" && r.URL.Path == "/api/v2/templates/db_profile":
 			w.WriteHeader(http.StatusOK)
-			w.Write(responseJSON)
-		} else if r.Method == "DELETE" && r.URL.Path == "/api/data/credential/test-credential" {
-			// Удаление учетных данных
-			w.WriteHeader(http.StatusOK)
-		} else {
-			t.Errorf("Неожиданный запрос: %s %s", r.Method, r.URL.Path)
+		case r.Method == "DELETE" && r.URL.Path == "/api/v2/templates/in_use":
+			http.Error(w, "шаблон используется записями", http.StatusConflict)
+		default:
 			w.WriteHeader(http.StatusNotFound)
 		}
 	}))
 	defer server.Close()
 
-	// Создаем клиентский сервис с адресом тестового сервера
-	serverAddr := server.URL[7:] // Убираем "http://" из URL
-	clientService := NewClientService(serverAddr)
+	clientService := NewClientService(server.URL[7:])
 
-	// Тестируем SaveCredential
-	credentialData := &domain.CredentialData{
-		Login:    "testuser",
-		Password: "testpass",
+	if err := clientService.SaveItemTemplate("db_profile", json.RawMessage(schema), "test-token"); err != nil {
+		t.Fatalf("Ошибка при вызове SaveItemTemplate: %v", err)
 	}
-	err := clientService.SaveCredential("test-credential", credentialData, "test metadata", "test-token")
-	if err != nil {
-		t.Fatalf("Ошибка при вызове SaveCredential: %v", err)
+	template, err := clientService.GetItemTemplate("db_profile", "test-token")
+	if err != nil || template.Name != "db_profile" || string(template.Schema) != schema {
+		t.Errorf("Неверный шаблон: %+v, %v", template, err)
 	}
-
-	// Тестируем GetCredential
-	retrievedCredentialData, metadata, err := clientService.GetCredential("test-credential", "test-token")
-	if err != nil {
-		t.Fatalf("Ошибка при вызове GetCredential: %v", err)
+	templates, err := clientService.ListItemTemplates("test-token")
+	if err != nil || len(templates) != 1 || templates[0].Name != "db_profile" {
+		t.Errorf("Неверный список шаблонов: %+v, %v", templates, err)
+	}
+	if err := clientService.DeleteItemTemplate("db_profile", "test-token"); err != nil {
+		t.Fatalf("Ошибка при вызове DeleteItemTemplate: %v", err)
 	}
-	if retrievedCredentialData.Login != "testuser" {
-		t.Errorf("Ожидался логин 'testuser', получен '%s'", retrievedCredentialData.Login)
+
+	// Тестируем ошибки
+	var statusErr *domain.Error
+	err = clientService.SaveItemTemplate("wifi", json.RawMessage(schema), "test-token")
+	if !errors.As(err, &statusErr) || statusErr.Code() != http.StatusBadRequest || !strings.Contains(err.Error(), "занято встроенным типом") {
+		t.Errorf("Ожидалась ошибка с кодом 400 и причиной отказа, получена %v", err)
 	}
-	if retrievedCredentialData.Password != "testpass" {
-		t.Errorf("Ожидался пароль 'testpass', получен '%s'", retrievedCredentialData.Password)
+	_, err = clientService.GetItemTemplate("passport", "test-token")
+	if !errors.As(err, &statusErr) || statusErr.Code() != http.StatusNotFound {
+		t.Errorf("Ожидалась ошибка с кодом 404, получена %v", err)
 	}
-	if metadata != "test metadata" {
-		t.Errorf("Ожидались метаданные 'test metadata', получены '%s'", metadata)
+	err = clientService.DeleteItemTemplate("in_use", "test-token")
+	if !errors.As(err, &statusErr) || statusErr.Code() != http.StatusConflict || !strings.Contains(err.Error(), "используется") {
+		t.Errorf("Ожидалась ошибка с кодом 409 и причиной отказа, получена %v", err)
 	}
-
-	// Тестируем DeleteCredential
-	err = clientService.DeleteCredential("test-credential", "test-token")
-	if err != nil {
-		t.Fatalf("Ошибка при вызове DeleteCredential: %v", err)
+	if _, err := clientService.ListItemTemplates("invalid-token"); !errors.As(err, &statusErr) || statusErr.Code() != http.StatusUnauthorized {
+		t.Errorf("Ожидалась ошибка с кодом 401, получена %v", err)
 	}
+}
 
-	// Тестируем ошибки в методах работы с учетными данными
-	// Создаем тестовый сервер для проверки ошибок
-	credentialErrorServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		// Проверяем заголовок Authorization
-		if r.Header.Get("Authorization") != "test-token" {
+// Тестирование внутреннего метода sendRequest
+func TestClientService_Settings(t *testing.T) {
+	// Создаем тестовый сервер
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "test-token" || r.URL.Path != "/api/user/settings" {
 			w.WriteHeader(http.StatusUnauthorized)
 			return
 		}
 
-		// Обрабатываем разные методы и пути
-		if r.Method == "POST" && r.URL.Path == "/api/data/credential/error-credential" {
-			// Возвращаем ошибку сервера при сохранении
-			w.WriteHeader(http.StatusInternalServerError)
-		} else if r.Method == "GET" && r.URL.Path == "/api/data/credential/not-found-credential" {
-			// Возвращаем ошибку "не найдено" при получении
-			w.WriteHeader(http.StatusNotFound)
-		} else if r.Method == "GET" && r.URL.Path == "/api/data/credential/error-credential" {
-			// Возвращаем ошибку сервера при получении
-			w.WriteHeader(http.StatusInternalServerError)
-		} else if r.Method == "GET" && r.URL.Path == "/api/data/credential/invalid-json" {
-			// Возвращаем некорректный JSON при получении
+		switch r.Method {
+		case "GET":
 			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(http.StatusOK)
-			w.Write([]byte("{invalid json}"))
-		} else if r.Method == "DELETE" && r.URL.Path == "/api/data/credential/not-found-credential" {
-			// Возвращаем ошибку "не найдено" при удалении
-			w.WriteHeader(http.StatusNotFound)
-		} else if r.Method == "DELETE" && r.URL.Path == "/api/data/credential/error-credential" {
-			// Возвращаем ошибку сервера при удалении
-			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(`{"password_history":10}`))
+		case "PUT":
+			var settings domain.UserSettings
+			json.NewDecoder(r.Body).Decode(&settings)
+			if settings.PasswordHistory > domain.MaxPasswordHistory {
+				http.Error(w, "неверные настройки: длина истории паролей должна быть от 0 до 100", http.StatusBadRequest)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(settings)
 		}
 	}))
-	defer credentialErrorServer.Close()
-
-	// Создаем клиентский сервис с адресом тестового сервера для ошибок
-	credentialErrorServerAddr := credentialErrorServer.URL[7:] // Убираем "http://" из URL
-	credentialErrorClientService := NewClientService(credentialErrorServerAddr)
-
-	// Тестируем ошибки в SaveCredential
-	// Тест на ошибку авторизации
-	err = credentialErrorClientService.SaveCredential("test-credential", &domain.CredentialData{Login: "test"}, "", "invalid-token")
-	if err == nil {
-		t.Error("Ожидалась ошибка авторизации при сохранении учетных данных, но ее не было")
-	}
-
-	// Тест на ошибку сервера
-	err = credentialErrorClientService.SaveCredential("error-credential", &domain.CredentialData{Login: "test"}, "", "test-token")
-	if err == nil {
-		t.Error("Ожидалась ошибка сервера при сохранении учетных данных, но ее не было")
-	}
-
-	// Тестируем ошибки в GetCredential
-	// Тест на ошибку авторизации
-	_, _, err = credentialErrorClientService.GetCredential("test-credential", "invalid-token")
-	if err == nil {
-		t.Error("Ожидалась ошибка авторизации при получении учетных данных, но ее не было")
-	}
-
-	// Тест на ошибку "не найдено"
-	_, _, err = credentialErrorClientService.GetCredential("not-found-credential", "test-token")
-	if err == nil || err.Error() != "учетные данные не найдены" {
-		t.Errorf("Ожидалась ошибка 'учетные данные не найдены', получено: %v", err)
-	}
+	defer server.Close()
 
-	// Тест на ошибку сервера
-	_, _, err = credentialErrorClientService.GetCredential("error-credential", "test-token")
-	if err == nil {
-		t.Error("Ожидалась ошибка сервера при получении учетных данных, но ее не было")
-	}
+	clientService := NewClientService(server.URL[7:])
 
-	// Тест на некорректный JSON
-	_, _, err = credentialErrorClientService.GetCredential("invalid-json", "test-token")
-	if err == nil {
-		t.Error("Ожидалась ошибка при парсинге JSON, но ее не было")
+	settings, err := clientService.GetSettings("test-token")
+	if err != nil || settings.PasswordHistory != 10 {
+		t.Errorf("Неверные настройки: %+v, %v", settings, err)
 	}
-
-	// Тестируем ошибки в DeleteCredential
-	// Тест на ошибку авторизации
-	err = credentialErrorClientService.DeleteCredential("test-credential", "invalid-token")
-	if err == nil {
-		t.Error("Ожидалась ошибка авторизации при удалении учетных данных, но ее не было")
+	if err := clientService.SaveSettings(&domain.UserSettings{PasswordHistory: 3}, "test-token"); err != nil {
+		t.Errorf("Ошибка при вызове SaveSettings: %v", err)
 	}
 
-	// Тест на ошибку "не найдено"
-	err = credentialErrorClientService.DeleteCredential("not-found-credential", "test-token")
-	if err == nil || err.Error() != "учетные данные не найдены" {
-		t.Errorf("Ожидалась ошибка 'учетные данные не найдены', получено: %v", err)
+	// Тестируем ошибки
+	var statusErr *domain.Error
+	err = clientService.SaveSettings(&domain.UserSettings{PasswordHistory: 1000}, "test-token")
+	if !errors.As(err, &statusErr) || statusErr.Code() != http.StatusBadRequest || !strings.Contains(err.Error(), "от 0 до 100") {
+		t.Errorf("Ожидалась ошибка с кодом 400 и причиной отказа, получена %v", err)
 	}
-
-	// Тест на ошибку сервера
-	err = credentialErrorClientService.DeleteCredential("error-credential", "test-token")
-	if err == nil {
-		t.Error("Ожидалась ошибка сервера при удалении учетных данных, но ее не было")
+	_, err = clientService.GetSettings("wrong-token")
+	if !errors.As(err, &statusErr) || statusErr.Code() != http.StatusUnauthorized {
+		t.Errorf("Ожидалась ошибка с кодом 401, получена %v", err)
 	}
 }
 
-// Тестирование внутреннего метода sendRequest
 func TestClientService_SendRequest(t *testing.T) {
 	// Создаем тестовый сервер
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -942,11 +598,13 @@ func TestClientService_FileOperations(t *testing.T) {
 			// Отправляем ответ с URL для скачивания
 			response := struct {
 				URL         string              `json:"url"`
+				Headers     map[string]string   `json:"headers"`
 				Description string              `json:"description"`
 				Metadata    domain.FileMetadata `json:"metadata"`
 				MetaInfo    string              `json:"meta_info"`
 			}{
 				URL:         "http://example.com/download",
+				Headers:     map[string]string{"X-Amz-Server-Side-Encryption-Customer-Algorithm": "AES256"},
 				Description: "Download URL",
 				Metadata: domain.FileMetadata{
 					FileName:  "test-file",
@@ -958,25 +616,36 @@ func TestClientService_FileOperations(t *testing.T) {
 			w.Header().Set("Content-Type", "application/json")
 			w.WriteHeader(http.StatusOK)
 			w.Write(responseJSON)
-		} else if r.Method == "PUT" && r.URL.Path == "/upload" {
-			// Проверяем заголовок Content-Type для загрузки файла
-			if r.Header.Get("Content-Type") != "application/octet-stream" {
-				t.Errorf("Ожидался Content-Type application/octet-stream, получен %s", r.Header.Get("Content-Type"))
+		} else if r.Method == "POST" && r.URL.Path == "/upload" {
+			// Разбираем multipart-форму загрузки файла
+			if err := r.ParseMultipartForm(1 << 20); err != nil {
+				t.Fatalf("Ошибка при разборе формы: %v", err)
+			}
+
+			// Проверяем поля политики
+			if r.FormValue("policy") != "test-policy" {
+				t.Errorf("Ожидалось поле формы policy 'test-policy', получено '%s'", r.FormValue("policy"))
 			}
 
 			// Читаем содержимое файла
-			body, err := ioutil.ReadAll(r.Body)
+			file, _, err := r.FormFile("file")
 			if err != nil {
-				t.Fatalf("Ошибка при чтении тела запроса: %v", err)
+				t.Fatalf("Ошибка при чтении файла из формы: %v", err)
 			}
+			body, _ := ioutil.ReadAll(file)
 
 			// Проверяем содержимое файла
 			if string(body) != "test file content" {
 				t.Errorf("Ожидалось содержимое 'test file content', получено '%s'", string(body))
 			}
 
-			w.WriteHeader(http.StatusOK)
+			w.WriteHeader(http.StatusNoContent)
 		} else if r.Method == "GET" && r.URL.Path == "/download" {
+			// Заголовки SSE-C из ссылки должны передаваться в запросе
+			if r.URL.Query().Get("sse") != "" && r.Header.Get("X-Amz-Server-Side-Encryption-Customer-Key") != "a2V5" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
 			// Отправляем тестовое содержимое файла
 			w.Header().Set("Content-Type", "application/octet-stream")
 			w.WriteHeader(http.StatusOK)
@@ -993,12 +662,12 @@ func TestClientService_FileOperations(t *testing.T) {
 	clientService := NewClientService(serverAddr)
 
 	// Тестируем GetUploadLink
-	url, err := clientService.GetUploadLink("test-file", "txt", "test metadata", "test-token")
+	upload, err := clientService.GetUploadLink(&domain.FileData{Name: "test-file", Extension: "txt", Size: 17, MimeType: "text/plain", Metadata: "test metadata"}, "test-token")
 	if err != nil {
 		t.Fatalf("Ошибка при вызове GetUploadLink: %v", err)
 	}
-	if url != "http://example.com/upload" {
-		t.Errorf("Ожидался URL 'http://example.com/upload', получен '%s'", url)
+	if upload.Url != "http://example.com/upload" {
+		t.Errorf("Ожидался URL 'http://example.com/upload', получен '%s'", upload.Url)
 	}
 
 	// Тестируем ошибки в GetUploadLink
@@ -1027,6 +696,18 @@ func TestClientService_FileOperations(t *testing.T) {
 				t.Fatalf("Ошибка при декодировании JSON: %v", err)
 			}
 
+			// Возвращаем запрет типа файла для определенного имени файла
+			if requestData.Name == "exe-file" {
+				http.Error(w, "тип файла запрещен", http.StatusUnsupportedMediaType)
+				return
+			}
+
+			// Возвращаем превышение квоты для определенного имени файла
+			if requestData.Name == "quota-file" {
+				http.Error(w, "превышена квота", http.StatusRequestEntityTooLarge)
+				return
+			}
+
 			// Возвращаем ошибку сервера для определенного имени файла
 			if requestData.Name == "error-file" {
 				w.WriteHeader(http.StatusInternalServerError)
@@ -1076,36 +757,48 @@ func TestClientService_FileOperations(t *testing.T) {
 	uploadErrorClientService := NewClientService(uploadErrorServerAddr)
 
 	// Тест на ошибку сервера
-	_, err = uploadErrorClientService.GetUploadLink("error-file", "txt", "test metadata", "test-token")
+	_, err = uploadErrorClientService.GetUploadLink(&domain.FileData{Name: "error-file", Extension: "txt", Size: 17, MimeType: "text/plain", Metadata: "test metadata"}, "test-token")
 	if err == nil {
 		t.Error("Ожидалась ошибка при получении ссылки для загрузки, но ее не было")
 	}
 
 	// Тест на ошибку авторизации
-	_, err = uploadErrorClientService.GetUploadLink("test-file", "txt", "test metadata", "invalid-token")
+	_, err = uploadErrorClientService.GetUploadLink(&domain.FileData{Name: "test-file", Extension: "txt", Size: 17, MimeType: "text/plain", Metadata: "test metadata"}, "invalid-token")
 	if err == nil {
 		t.Error("Ожидалась ошибка авторизации, но ее не было")
 	}
 
 	// Тест на некорректный JSON
-	_, err = uploadErrorClientService.GetUploadLink("invalid-json", "txt", "test metadata", "test-token")
+	_, err = uploadErrorClientService.GetUploadLink(&domain.FileData{Name: "invalid-json", Extension: "txt", Size: 17, MimeType: "text/plain", Metadata: "test metadata"}, "test-token")
 	if err == nil {
 		t.Error("Ожидалась ошибка при парсинге JSON, но ее не было")
 	}
 
 	// Тест на отсутствие URL в ответе
-	_, err = uploadErrorClientService.GetUploadLink("no-url", "txt", "test metadata", "test-token")
+	_, err = uploadErrorClientService.GetUploadLink(&domain.FileData{Name: "no-url", Extension: "txt", Size: 17, MimeType: "text/plain", Metadata: "test metadata"}, "test-token")
 	if err == nil {
 		t.Error("Ожидалась ошибка при получении URL из ответа, но ее не было")
 	}
 
 	// Тест на успешный ответ
-	url, err = uploadErrorClientService.GetUploadLink("success-file", "txt", "test metadata", "test-token")
+	upload, err = uploadErrorClientService.GetUploadLink(&domain.FileData{Name: "success-file", Extension: "txt", Size: 17, MimeType: "text/plain", Metadata: "test metadata"}, "test-token")
 	if err != nil {
 		t.Fatalf("Ошибка при вызове GetUploadLink: %v", err)
 	}
-	if url != "http://example.com/upload" {
-		t.Errorf("Ожидался URL 'http://example.com/upload', получен '%s'", url)
+	if upload.Url != "http://example.com/upload" {
+		t.Errorf("Ожидался URL 'http://example.com/upload', получен '%s'", upload.Url)
+	}
+
+	// Тест на превышение квоты
+	_, err = uploadErrorClientService.GetUploadLink(&domain.FileData{Name: "quota-file", Extension: "txt", Size: 17, MimeType: "text/plain", Metadata: "test metadata"}, "test-token")
+	if !errors.Is(err, domain.ErrQuotaExceeded) {
+		t.Errorf("Ожидалась ошибка превышения квоты, получена %v", err)
+	}
+
+	// Тест на запрещенный тип файла
+	_, err = uploadErrorClientService.GetUploadLink(&domain.FileData{Name: "exe-file", Extension: "exe", Size: 17, MimeType: "application/x-msdownload"}, "test-token")
+	if !errors.Is(err, domain.ErrFileTypeNotAllowed) {
+		t.Errorf("Ожидалась ошибка запрета типа файла, получена %v", err)
 	}
 
 	// Тестируем SendFileToServer
@@ -1124,7 +817,7 @@ func TestClientService_FileOperations(t *testing.T) {
 	tempFile.Seek(0, 0) // Перемещаем указатель в начало файла
 
 	// Тестируем отправку файла
-	message, err := clientService.SendFileToServer(server.URL+"/upload", tempFile)
+	message, err := clientService.SendFileToServer(&domain.FileDataResponse{Url: server.URL + "/upload", FormData: map[string]string{"policy": "test-policy"}}, tempFile)
 	if err != nil {
 		t.Fatalf("Ошибка при вызове SendFileToServer: %v", err)
 	}
@@ -1135,7 +828,7 @@ func TestClientService_FileOperations(t *testing.T) {
 	// Тестируем ошибки в SendFileToServer
 	// Создаем тестовый сервер для проверки ошибок
 	fileErrorServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		if r.Method == "PUT" && r.URL.Path == "/upload-error" {
+		if r.Method == "POST" && r.URL.Path == "/upload-error" {
 			// Возвращаем ошибку сервера
 			w.WriteHeader(http.StatusInternalServerError)
 		}
@@ -1144,7 +837,7 @@ func TestClientService_FileOperations(t *testing.T) {
 
 	// Тест на ошибку при отправке файла (ошибка сервера)
 	tempFile.Seek(0, 0) // Перемещаем указатель в начало файла
-	_, err = clientService.SendFileToServer(fileErrorServer.URL+"/upload-error", tempFile)
+	_, err = clientService.SendFileToServer(&domain.FileDataResponse{Url: fileErrorServer.URL + "/upload-error"}, tempFile)
 	if err == nil {
 		t.Error("Ожидалась ошибка при отправке файла, но ее не было")
 	}
@@ -1158,7 +851,7 @@ func TestClientService_FileOperations(t *testing.T) {
 	invalidFile.Close()           // Закрываем файл
 	os.Remove(invalidFile.Name()) // Удаляем файл, чтобы вызвать ошибку при Stat
 
-	_, err = clientService.SendFileToServer(server.URL+"/upload", invalidFile)
+	_, err = clientService.SendFileToServer(&domain.FileDataResponse{Url: server.URL + "/upload", FormData: map[string]string{"policy": "test-policy"}}, invalidFile)
 	if err == nil {
 		t.Error("Ожидалась ошибка при получении информации о файле, но ее не было")
 	}
@@ -1172,13 +865,13 @@ func TestClientService_FileOperations(t *testing.T) {
 	closedFile.Close() // Закрываем файл
 	defer os.Remove(closedFile.Name())
 
-	_, err = clientService.SendFileToServer(server.URL+"/upload", closedFile)
+	_, err = clientService.SendFileToServer(&domain.FileDataResponse{Url: server.URL + "/upload", FormData: map[string]string{"policy": "test-policy"}}, closedFile)
 	if err == nil {
 		t.Error("Ожидалась ошибка при перемещении указателя файла, но ее не было")
 	}
 
 	// Тест на ошибку при создании запроса
-	_, err = clientService.SendFileToServer("://invalid-url", tempFile)
+	_, err = clientService.SendFileToServer(&domain.FileDataResponse{Url: "://invalid-url"}, tempFile)
 	if err == nil {
 		t.Error("Ожидалась ошибка при создании запроса, но ее не было")
 	}
@@ -1193,7 +886,7 @@ func TestClientService_FileOperations(t *testing.T) {
 	defer os.Remove(downloadTempFile.Name())
 
 	// Тестируем скачивание файла
-	err = clientService.DownloadFileFromServer(server.URL+"/download", downloadTempFile.Name())
+	err = clientService.DownloadFileFromServer(&domain.DownloadLink{URL: server.URL + "/download"}, downloadTempFile.Name(), 0600)
 	if err != nil {
 		t.Fatalf("Ошибка при вызове DownloadFileFromServer: %v", err)
 	}
@@ -1207,6 +900,24 @@ func TestClientService_FileOperations(t *testing.T) {
 		t.Errorf("Ожидалось содержимое 'downloaded file content', получено '%s'", string(downloadedContent))
 	}
 
+	// Проверяем права доступа к скачанному файлу
+	if stat, err := os.Stat(downloadTempFile.Name()); err != nil || stat.Mode().Perm() != 0600 {
+		t.Errorf("Ожидались права 0600 для скачанного файла, получено %v", stat.Mode().Perm())
+	}
+
+	// Тестируем скачивание зашифрованного файла с заголовками SSE-C
+	encryptedLink := &domain.DownloadLink{
+		URL:     server.URL + "/download?sse=1",
+		Headers: map[string]string{"X-Amz-Server-Side-Encryption-Customer-Key": "a2V5"},
+	}
+	if err := clientService.DownloadFileFromServer(encryptedLink, downloadTempFile.Name(), 0600); err != nil {
+		t.Errorf("Ошибка при скачивании зашифрованного файла: %v", err)
+	}
+	encryptedLink.Headers = nil
+	if err := clientService.DownloadFileFromServer(encryptedLink, downloadTempFile.Name(), 0600); err == nil {
+		t.Error("Ожидалась ошибка при скачивании зашифрованного файла без заголовков SSE-C")
+	}
+
 	// Тестируем GetDownloadLink
 	downloadURL, fileMetadata, metaInfo, err := clientService.GetDownloadLink("test-file", "test-token")
 	if err != nil {
@@ -1276,7 +987,7 @@ func TestClientService_FileOperations(t *testing.T) {
 
 	// Тестируем ошибки в SendFileToServer
 	// Тест с некорректным URL
-	_, err = clientService.SendFileToServer("http://invalid-url", tempFile)
+	_, err = clientService.SendFileToServer(&domain.FileDataResponse{Url: "http://invalid-url"}, tempFile)
 	if err == nil {
 		t.Error("Ожидалась ошибка при отправке файла на некорректный URL, но ее не было")
 	}
@@ -1285,7 +996,7 @@ func TestClientService_FileOperations(t *testing.T) {
 	invalidFileTest, err := os.Open("/non-existent-file.txt")
 	if err == nil {
 		defer invalidFileTest.Close()
-		_, err = clientService.SendFileToServer(server.URL+"/upload", invalidFileTest)
+		_, err = clientService.SendFileToServer(&domain.FileDataResponse{Url: server.URL + "/upload", FormData: map[string]string{"policy": "test-policy"}}, invalidFileTest)
 		if err == nil {
 			t.Error("Ожидалась ошибка при отправке некорректного файла, но ее не было")
 		}
@@ -1293,18 +1004,21 @@ func TestClientService_FileOperations(t *testing.T) {
 
 	// Тестируем ошибки в DownloadFileFromServer
 	// Тест с некорректным URL
-	err = clientService.DownloadFileFromServer("http://invalid-url", downloadTempFile.Name())
+	err = clientService.DownloadFileFromServer(&domain.DownloadLink{URL: "http://invalid-url"}, downloadTempFile.Name(), 0600)
 	if err == nil {
 		t.Error("Ожидалась ошибка при скачивании файла с некорректного URL, но ее не было")
 	}
 
 	// Тест с некорректным путем для сохранения
-	err = clientService.DownloadFileFromServer(server.URL+"/download", "/invalid/path/for/saving.txt")
+	err = clientService.DownloadFileFromServer(&domain.DownloadLink{URL: server.URL + "/download"}, "/invalid/path/for/saving.txt", 0644)
 	if err == nil {
 		t.Error("Ожидалась ошибка при сохранении файла по некорректному пути, но ее не было")
 	}
-	if downloadURL != "http://example.com/download" {
-		t.Errorf("Ожидался URL 'http://example.com/download', получен '%s'", downloadURL)
+	if downloadURL.URL != "http://example.com/download" {
+		t.Errorf("Ожидался URL 'http://example.com/download', получен '%s'", downloadURL.URL)
+	}
+	if downloadURL.Headers["X-Amz-Server-Side-Encryption-Customer-Algorithm"] != "AES256" {
+		t.Errorf("Ожидались заголовки SSE-C в ссылке, получено %v", downloadURL.Headers)
 	}
 	if fileMetadata.FileName != "test-file" || fileMetadata.Extension != "txt" {
 		t.Errorf("Ожидались имя файла 'test-file' и расширение 'txt', получены '%s' и '%s'", fileMetadata.FileName, fileMetadata.Extension)
@@ -1313,3 +1027,266 @@ func TestClientService_FileOperations(t *testing.T) {
 		t.Errorf("Ожидались метаданные 'test metadata', получены '%s'", metaInfo)
 	}
 }
+
+// TestClientService_FileManagement тестирует методы управления файлами
+func TestClientService_FileManagement(t *testing.T) {
+	// Создаем тестовый сервер
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "test-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/api/file/list":
+			json.NewEncoder(w).Encode([]domain.FileInfo{
+				{Label: "a", Extension: "txt", Size: 10},
+				{Label: "b", Extension: "bin", Size: 20},
+			})
+		case r.Method == "GET" && r.URL.Path == "/api/file/info":
+			if r.URL.Query().Get("label") != "my file" {
+				http.Error(w, "Файл не найден", http.StatusNotFound)
+				return
+			}
+			json.NewEncoder(w).Encode(domain.FileInfo{Label: "my file", Extension: "txt", Size: 10, Metadata: "meta"})
+		case r.Method == "POST" && r.URL.Path == "/api/file/rename":
+			var rename domain.FileRename
+			json.NewDecoder(r.Body).Decode(&rename)
+			if rename.NewLabel == "taken" {
+				w.WriteHeader(http.StatusConflict)
+				return
+			}
+			if rename.Label != "old" || rename.NewLabel != "new" {
+				t.Errorf("Ожидалось переименование 'old' в 'new', получено '%s' в '%s'", rename.Label, rename.NewLabel)
+			}
+			w.WriteHeader(http.StatusOK)
+		case r.Method == "POST" && r.URL.Path == "/api/file/verify":
+			if r.URL.Query().Get("label") == "setup.exe" {
+				http.Error(w, domain.ErrFileTypeNotAllowed.Error()+": application/x-msdownload", http.StatusUnsupportedMediaType)
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+		case r.Method == "DELETE" && r.URL.Path == "/api/file":
+			if r.URL.Query().Get("label") != "test-file" {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+		default:
+			t.Errorf("Неожиданный запрос: %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	clientService := NewClientService(server.URL[7:])
+
+	// Тестируем ListFiles
+	files, err := clientService.ListFiles("test-token")
+	if err != nil {
+		t.Fatalf("Ошибка при вызове ListFiles: %v", err)
+	}
+	if len(files) != 2 || files[1].Label != "b" {
+		t.Errorf("Неверный список файлов: %+v", files)
+	}
+
+	// Тестируем GetFileInfo, метка экранируется в параметре запроса
+	fileInfo, err := clientService.GetFileInfo("my file", "test-token")
+	if err != nil {
+		t.Fatalf("Ошибка при вызове GetFileInfo: %v", err)
+	}
+	if fileInfo.Metadata != "meta" || fileInfo.Size != 10 {
+		t.Errorf("Неверные сведения о файле: %+v", fileInfo)
+	}
+	if _, err := clientService.GetFileInfo("missing", "test-token"); err == nil {
+		t.Error("Ожидалась ошибка для несуществующего файла")
+	}
+
+	// Тестируем RenameFile
+	if err := clientService.RenameFile("old", "new", "test-token"); err != nil {
+		t.Errorf("Ошибка при вызове RenameFile: %v", err)
+	}
+	if err := clientService.RenameFile("old", "taken", "test-token"); err == nil || !strings.Contains(err.Error(), "уже используется") {
+		t.Errorf("Ожидалась ошибка занятой метки, получено: %v", err)
+	}
+
+	// Тестируем VerifyFile, тип запрещенного файла берется из ответа сервера
+	if err := clientService.VerifyFile("test-file", "test-token"); err != nil {
+		t.Errorf("Ошибка при вызове VerifyFile: %v", err)
+	}
+	err = clientService.VerifyFile("setup.exe", "test-token")
+	if !errors.Is(err, domain.ErrFileTypeNotAllowed) || !strings.HasSuffix(err.Error(), ": application/x-msdownload") {
+		t.Errorf("Ожидалась ошибка запрещенного типа файла, получено: %v", err)
+	}
+
+	// Тестируем DeleteFile
+	if err := clientService.DeleteFile("test-file", "test-token"); err != nil {
+		t.Errorf("Ошибка при вызове DeleteFile: %v", err)
+	}
+	if err := clientService.DeleteFile("missing", "test-token"); err == nil {
+		t.Error("Ожидалась ошибка для несуществующего файла")
+	}
+
+	// Тестируем ошибку авторизации
+	if _, err := clientService.ListFiles("invalid-token"); err == nil {
+		t.Error("Ожидалась ошибка авторизации")
+	}
+}
+
+// TestClientService_ListItemsAndProgress тестирует список записей и ход загрузки и скачивания файлов
+func TestClientService_ListItemsAndProgress(t *testing.T) {
+	content := strings.Repeat("x", 64*1024)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/api/v2/items":
+			if r.Header.Get("Authorization") != "test-token" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			json.NewEncoder(w).Encode([]domain.ItemInfo{
+				{Type: domain.UserDataTypeCredential, Label: "bank"},
+				{Type: domain.UserDataTypeFile, Label: "photo"},
+			})
+		case r.Method == "POST" && r.URL.Path == "/upload":
+			ioutil.ReadAll(r.Body)
+			w.WriteHeader(http.StatusNoContent)
+		case r.Method == "GET" && r.URL.Path == "/download":
+			w.Header().Set("Content-Length", strconv.Itoa(len(content)))
+			w.Write([]byte(content))
+		default:
+			t.Errorf("Неожиданный запрос: %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	clientService := NewClientService(server.URL[7:])
+
+	items, err := clientService.ListItems("test-token")
+	if err != nil {
+		t.Fatalf("Ошибка при вызове ListItems: %v", err)
+	}
+	if len(items) != 2 || items[1].Type != domain.UserDataTypeFile {
+		t.Errorf("Неверный список записей: %+v", items)
+	}
+	if _, err := clientService.ListItems("invalid-token"); err == nil {
+		t.Error("Ожидалась ошибка авторизации")
+	}
+
+	var done, total int64
+	clientService.SetProgress(func(d int64, tl int64) {
+		if d < done {
+			t.Errorf("Ход передачи уменьшился с %d до %d", done, d)
+		}
+		done, total = d, tl
+	})
+
+	dir := t.TempDir()
+	file, err := os.Create(filepath.Join(dir, "upload.txt"))
+	if err != nil {
+		t.Fatalf("Не удалось создать файл: %v", err)
+	}
+	defer file.Close()
+	file.WriteString(content)
+
+	if _, err := clientService.SendFileToServer(&domain.FileDataResponse{Url: server.URL + "/upload"}, file); err != nil {
+		t.Fatalf("Ошибка при вызове SendFileToServer: %v", err)
+	}
+	if done != int64(len(content)) || total != int64(len(content)) {
+		t.Errorf("Ожидался ход загрузки %d из %d, получено %d из %d", len(content), len(content), done, total)
+	}
+
+	done, total = 0, 0
+	link := &domain.DownloadLink{URL: server.URL + "/download"}
+	if err := clientService.DownloadFileFromServer(link, filepath.Join(dir, "download.txt"), 0600); err != nil {
+		t.Fatalf("Ошибка при вызове DownloadFileFromServer: %v", err)
+	}
+	if done != int64(len(content)) || total != int64(len(content)) {
+		t.Errorf("Ожидался ход скачивания %d из %d, получено %d из %d", len(content), len(content), done, total)
+	}
+}
+
+// TestClientService_ErrorClasses тестирует коды ответа в ошибках клиента и экранирование меток с пробелами
+func TestClientService_ErrorClasses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Header.Get("Authorization") != "test-token":
+			w.WriteHeader(http.StatusUnauthorized)
+		case r.URL.Path == "/api/v2/items/text/my note":
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"data":{"content":"text"}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	clientService := NewClientService(server.URL[7:])
+
+	textData, _, err := clientService.GetItem(domain.UserDataTypeText, "my note", "test-token")
+	if err != nil {
+		t.Fatalf("Ошибка при получении записи с пробелом в метке: %v", err)
+	}
+	if textData["content"] != "text" {
+		t.Errorf("Ожидался текст 'text', получен '%s'", textData["content"])
+	}
+
+	var statusErr *domain.Error
+	_, _, err = clientService.GetItem(domain.UserDataTypeText, "missing", "test-token")
+	if !errors.As(err, &statusErr) || statusErr.Code() != http.StatusNotFound {
+		t.Errorf("Ожидалась ошибка с кодом 404, получена %v", err)
+	}
+	if err != nil && err.Error() != "запись 'missing' типа text не найдена" {
+		t.Errorf("Сообщение об ошибке не должно меняться, получено '%s'", err.Error())
+	}
+
+	err = clientService.DeleteItem(domain.UserDataTypeCard, "card", "wrong-token")
+	if !errors.As(err, &statusErr) || statusErr.Code() != http.StatusUnauthorized {
+		t.Errorf("Ожидалась ошибка с кодом 401, получена %v", err)
+	}
+
+	// Недоступный сервер
+	unavailable := NewClientService("127.0.0.1:1")
+	_, err = unavailable.ListFiles("test-token")
+	var urlErr *url.Error
+	if !errors.As(err, &urlErr) {
+		t.Errorf("Ожидалась сетевая ошибка, получена %v", err)
+	}
+}
+
+// Тестирование подключения к серверу выбранного профиля по HTTPS с сертификатом из ca_file
+func TestClientService_Profile(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data":{"content":"text"}}`))
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	caFile := filepath.Join(dir, "ca.pem")
+	certificate := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	if err := os.WriteFile(caFile, certificate, 0600); err != nil {
+		t.Fatalf("Не удалось записать сертификат: %v", err)
+	}
+
+	t.Setenv(config.EnvConfig, filepath.Join(dir, "config.yaml"))
+	t.Setenv(config.EnvServer, server.URL)
+	t.Setenv(config.EnvCAFile, "")
+	t.Setenv(config.EnvInsecureSkipVerify, "")
+
+	// Без сертификата удостоверяющего центра сервер не проходит проверку
+	untrusted := config.NewConfig("127.0.0.1:1")
+	if _, _, err := NewProfileClientService(untrusted).GetItem(domain.UserDataTypeText, "note", "test-token"); err == nil {
+		t.Error("Ожидалась ошибка проверки сертификата сервера")
+	}
+
+	t.Setenv(config.EnvCAFile, caFile)
+	trusted := config.NewConfig("127.0.0.1:1")
+	textData, _, err := NewProfileClientService(trusted).GetItem(domain.UserDataTypeText, "note", "test-token")
+	if err != nil {
+		t.Fatalf("Ошибка при получении записи по HTTPS: %v", err)
+	}
+	if textData["content"] != "text" {
+		t.Errorf("Ожидался текст 'text', получен '%s'", textData["content"])
+	}
+}
@@ -2,7 +2,12 @@ package service
 
 import (
 	"context"
+	"github.com/SmirnovND/gophkeeper/internal/domain"
 	"github.com/SmirnovND/gophkeeper/internal/interfaces"
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/encrypt"
+	"io"
+	"net/http"
 	"net/url"
 	"time"
 )
@@ -19,22 +24,137 @@ func NewCloud(minio interfaces.MinioClientInterface, bucketName string) interfac
 	}
 }
 
-func (c *Cloud) GenerateUploadLink(fileName string) (string, error) {
+func (c *Cloud) GenerateUploadLink(fileName string, maxSize int64, contentType string, key []byte) (string, map[string]string, error) {
 	ctx := context.Background()
-	presignedURL, err := c.minio.PresignedPutObject(ctx, c.bucketName, fileName, 15*time.Minute)
+
+	// Политика POST-загрузки ограничивает размер файла на стороне хранилища
+	policy := minio.NewPostPolicy()
+	if err := policy.SetBucket(c.bucketName); err != nil {
+		return "", nil, err
+	}
+	if err := policy.SetKey(fileName); err != nil {
+		return "", nil, err
+	}
+	if err := policy.SetExpires(time.Now().UTC().Add(15 * time.Minute)); err != nil {
+		return "", nil, err
+	}
+	if err := policy.SetContentLengthRange(0, maxSize); err != nil {
+		return "", nil, err
+	}
+	// Тип содержимого сохраняется в объекте и возвращается при скачивании
+	if err := policy.SetContentType(contentType); err != nil {
+		return "", nil, err
+	}
+	// Объект шифруется ключом пользователя, поля SSE-C добавляются в политику и форму загрузки
+	if key != nil {
+		sse, err := encrypt.NewSSEC(key)
+		if err != nil {
+			return "", nil, err
+		}
+		policy.SetEncryption(sse)
+	}
+
+	presignedURL, formData, err := c.minio.PresignedPostPolicy(ctx, policy)
 	if err != nil {
-		return "", err
+		return "", nil, err
 	}
-	return presignedURL.String(), nil
+	return presignedURL.String(), formData, nil
 }
 
-func (c *Cloud) GenerateDownloadLink(fileName string) (string, error) {
+func (c *Cloud) GenerateDownloadLink(fileName string, key []byte) (*domain.DownloadLink, error) {
 	ctx := context.Background()
 	// Устанавливаем срок действия ссылки на 15 минут
 	reqParams := make(url.Values)
-	presignedURL, err := c.minio.PresignedGetObject(ctx, c.bucketName, fileName, 15*time.Minute, reqParams)
+
+	if key == nil {
+		presignedURL, err := c.minio.PresignedGetObject(ctx, c.bucketName, fileName, 15*time.Minute, reqParams)
+		if err != nil {
+			return nil, err
+		}
+		return &domain.DownloadLink{URL: presignedURL.String()}, nil
+	}
+
+	// Для зашифрованного объекта заголовки SSE-C входят в подпись и должны быть переданы клиентом при скачивании
+	sse, err := encrypt.NewSSEC(key)
+	if err != nil {
+		return nil, err
+	}
+	headers := make(http.Header)
+	sse.Marshal(headers)
+
+	presignedURL, err := c.minio.PresignHeader(ctx, http.MethodGet, c.bucketName, fileName, 15*time.Minute, reqParams, headers)
+	if err != nil {
+		return nil, err
+	}
+
+	link := &domain.DownloadLink{URL: presignedURL.String(), Headers: make(map[string]string, len(headers))}
+	for name := range headers {
+		link.Headers[name] = headers.Get(name)
+	}
+	return link, nil
+}
+
+func (c *Cloud) DeleteFile(fileName string) error {
+	ctx := context.Background()
+	return c.minio.RemoveObject(ctx, c.bucketName, fileName, minio.RemoveObjectOptions{})
+}
+
+func (c *Cloud) RenameFile(fileName string, newFileName string, key []byte) error {
+	ctx := context.Background()
+
+	dst := minio.CopyDestOptions{Bucket: c.bucketName, Object: newFileName}
+	src := minio.CopySrcOptions{Bucket: c.bucketName, Object: fileName}
+	// Зашифрованный объект копируется с тем же ключом, перешифровка не требуется
+	if key != nil {
+		sse, err := encrypt.NewSSEC(key)
+		if err != nil {
+			return err
+		}
+		dst.Encryption = sse
+		src.Encryption = sse
+	}
+
+	// В S3 нет переименования, поэтому копируем объект под новым именем и удаляем исходный
+	_, err := c.minio.CopyObject(ctx, dst, src)
+	if err != nil {
+		// Файл мог быть не загружен по выданной ссылке, тогда переносить нечего
+		if minio.ToErrorResponse(err).Code == "NoSuchKey" {
+			return nil
+		}
+		return err
+	}
+	return c.minio.RemoveObject(ctx, c.bucketName, fileName, minio.RemoveObjectOptions{})
+}
+
+func (c *Cloud) ReadFileHead(fileName string, n int64, key []byte) ([]byte, error) {
+	ctx := context.Background()
+
+	// Запрашивается только начало объекта, весь файл сервер не скачивает
+	opts := minio.GetObjectOptions{}
+	if err := opts.SetRange(0, n-1); err != nil {
+		return nil, err
+	}
+	if key != nil {
+		sse, err := encrypt.NewSSEC(key)
+		if err != nil {
+			return nil, err
+		}
+		opts.ServerSideEncryption = sse
+	}
+
+	object, err := c.minio.GetObject(ctx, c.bucketName, fileName, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer object.Close()
+
+	head, err := io.ReadAll(io.LimitReader(object, n))
 	if err != nil {
-		return "", err
+		// Файл мог быть не загружен по выданной ссылке
+		if minio.ToErrorResponse(err).Code == "NoSuchKey" {
+			return nil, domain.ErrNotFound
+		}
+		return nil, err
 	}
-	return presignedURL.String(), nil
+	return head, nil
 }
@@ -1,29 +1,45 @@
 package service
 
 import (
+	"bytes"
 	"context"
+	"encoding/base64"
+	"errors"
+	"github.com/SmirnovND/gophkeeper/internal/domain"
 	"github.com/SmirnovND/gophkeeper/internal/interfaces"
 	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+	"net/http"
+	"net/http/httptest"
 	"net/url"
+	"strings"
 	"testing"
 	"time"
 )
 
 // MinioClientInterface определяет интерфейс для методов minio.Client, которые мы используем
 type MinioClientInterface interface {
-	PresignedPutObject(ctx context.Context, bucketName, objectName string, expires time.Duration) (*url.URL, error)
+	PresignedPostPolicy(ctx context.Context, policy *minio.PostPolicy) (*url.URL, map[string]string, error)
 	PresignedGetObject(ctx context.Context, bucketName, objectName string, expires time.Duration, reqParams url.Values) (*url.URL, error)
+	PresignHeader(ctx context.Context, method, bucketName, objectName string, expires time.Duration, reqParams url.Values, extraHeaders http.Header) (*url.URL, error)
+	GetObject(ctx context.Context, bucketName, objectName string, opts minio.GetObjectOptions) (*minio.Object, error)
+	CopyObject(ctx context.Context, dst minio.CopyDestOptions, src minio.CopySrcOptions) (minio.UploadInfo, error)
+	RemoveObject(ctx context.Context, bucketName, objectName string, opts minio.RemoveObjectOptions) error
 }
 
 // MockMinioClient - мок для MinioClientInterface
 type MockMinioClient struct {
-	PresignedPutObjectFunc func(ctx context.Context, bucketName, objectName string, expires time.Duration) (*url.URL, error)
-	PresignedGetObjectFunc func(ctx context.Context, bucketName, objectName string, expires time.Duration, reqParams url.Values) (*url.URL, error)
+	PresignedPostPolicyFunc func(ctx context.Context, policy *minio.PostPolicy) (*url.URL, map[string]string, error)
+	PresignedGetObjectFunc  func(ctx context.Context, bucketName, objectName string, expires time.Duration, reqParams url.Values) (*url.URL, error)
+	PresignHeaderFunc       func(ctx context.Context, method, bucketName, objectName string, expires time.Duration, reqParams url.Values, extraHeaders http.Header) (*url.URL, error)
+	GetObjectFunc           func(ctx context.Context, bucketName, objectName string, opts minio.GetObjectOptions) (*minio.Object, error)
+	CopyObjectFunc          func(ctx context.Context, dst minio.CopyDestOptions, src minio.CopySrcOptions) (minio.UploadInfo, error)
+	RemoveObjectFunc        func(ctx context.Context, bucketName, objectName string, opts minio.RemoveObjectOptions) error
 }
 
-// PresignedPutObject - мок для метода PresignedPutObject
-func (m *MockMinioClient) PresignedPutObject(ctx context.Context, bucketName, objectName string, expires time.Duration) (*url.URL, error) {
-	return m.PresignedPutObjectFunc(ctx, bucketName, objectName, expires)
+// PresignedPostPolicy - мок для метода PresignedPostPolicy
+func (m *MockMinioClient) PresignedPostPolicy(ctx context.Context, policy *minio.PostPolicy) (*url.URL, map[string]string, error) {
+	return m.PresignedPostPolicyFunc(ctx, policy)
 }
 
 // PresignedGetObject - мок для метода PresignedGetObject
@@ -31,25 +47,49 @@ func (m *MockMinioClient) PresignedGetObject(ctx context.Context, bucketName, ob
 	return m.PresignedGetObjectFunc(ctx, bucketName, objectName, expires, reqParams)
 }
 
+// PresignHeader - мок для метода PresignHeader
+func (m *MockMinioClient) PresignHeader(ctx context.Context, method, bucketName, objectName string, expires time.Duration, reqParams url.Values, extraHeaders http.Header) (*url.URL, error) {
+	return m.PresignHeaderFunc(ctx, method, bucketName, objectName, expires, reqParams, extraHeaders)
+}
+
+// GetObject - мок для метода GetObject
+func (m *MockMinioClient) GetObject(ctx context.Context, bucketName, objectName string, opts minio.GetObjectOptions) (*minio.Object, error) {
+	return m.GetObjectFunc(ctx, bucketName, objectName, opts)
+}
+
+// CopyObject - мок для метода CopyObject
+func (m *MockMinioClient) CopyObject(ctx context.Context, dst minio.CopyDestOptions, src minio.CopySrcOptions) (minio.UploadInfo, error) {
+	return m.CopyObjectFunc(ctx, dst, src)
+}
+
+// RemoveObject - мок для метода RemoveObject
+func (m *MockMinioClient) RemoveObject(ctx context.Context, bucketName, objectName string, opts minio.RemoveObjectOptions) error {
+	return m.RemoveObjectFunc(ctx, bucketName, objectName, opts)
+}
+
 // TestCloud_GenerateUploadLink тестирует метод GenerateUploadLink
 func TestCloud_GenerateUploadLink(t *testing.T) {
 	// Создаем URL для тестирования
-	testURL, _ := url.Parse("https://example.com/upload/test-file.txt")
+	testURL, _ := url.Parse("https://example.com/test-bucket")
 
 	// Создаем мок для minio.Client
 	mockMinioClient := &MockMinioClient{
-		PresignedPutObjectFunc: func(ctx context.Context, bucketName, objectName string, expires time.Duration) (*url.URL, error) {
-			// Проверяем параметры
-			if bucketName != "test-bucket" {
-				t.Errorf("Ожидалось имя бакета 'test-bucket', получено '%s'", bucketName)
+		PresignedPostPolicyFunc: func(ctx context.Context, policy *minio.PostPolicy) (*url.URL, map[string]string, error) {
+			// Проверяем условия политики
+			policyJSON := policy.String()
+			if !strings.Contains(policyJSON, `"$bucket","test-bucket"`) {
+				t.Errorf("Ожидалось имя бакета 'test-bucket' в политике, получено '%s'", policyJSON)
 			}
-			if objectName != "test-file.txt" {
-				t.Errorf("Ожидалось имя объекта 'test-file.txt', получено '%s'", objectName)
+			if !strings.Contains(policyJSON, `"$key","test-file.txt"`) {
+				t.Errorf("Ожидалось имя объекта 'test-file.txt' в политике, получено '%s'", policyJSON)
 			}
-			if expires != 15*time.Minute {
-				t.Errorf("Ожидалось время жизни ссылки 15 минут, получено %v", expires)
+			if !strings.Contains(policyJSON, `["content-length-range", 0, 1024]`) {
+				t.Errorf("Ожидалось ограничение размера 1024 байт в политике, получено '%s'", policyJSON)
 			}
-			return testURL, nil
+			if !strings.Contains(policyJSON, `"$Content-Type","text/plain"`) {
+				t.Errorf("Ожидался тип содержимого 'text/plain' в политике, получено '%s'", policyJSON)
+			}
+			return testURL, map[string]string{"key": "test-file.txt", "policy": "test-policy"}, nil
 		},
 	}
 
@@ -60,14 +100,30 @@ func TestCloud_GenerateUploadLink(t *testing.T) {
 	}
 
 	// Вызываем метод GenerateUploadLink
-	url, err := cloud.GenerateUploadLink("test-file.txt")
+	url, formData, err := cloud.GenerateUploadLink("test-file.txt", 1024, "text/plain", nil)
 
 	// Проверяем результаты
 	if err != nil {
 		t.Fatalf("Ошибка при вызове GenerateUploadLink: %v", err)
 	}
-	if url != "https://example.com/upload/test-file.txt" {
-		t.Errorf("Ожидался URL 'https://example.com/upload/test-file.txt', получен '%s'", url)
+	if url != "https://example.com/test-bucket" {
+		t.Errorf("Ожидался URL 'https://example.com/test-bucket', получен '%s'", url)
+	}
+	if formData["policy"] != "test-policy" {
+		t.Errorf("Ожидалось поле формы policy 'test-policy', получено '%s'", formData["policy"])
+	}
+}
+
+// TestCloud_GenerateUploadLink_InvalidSize тестирует отказ при неположительном размере файла
+func TestCloud_GenerateUploadLink_InvalidSize(t *testing.T) {
+	cloud := &Cloud{
+		minio:      &MockMinioClient{},
+		bucketName: "test-bucket",
+	}
+
+	_, _, err := cloud.GenerateUploadLink("test-file.txt", 0, "text/plain", nil)
+	if err == nil {
+		t.Fatal("Ожидалась ошибка для нулевого размера файла, но ее не было")
 	}
 }
 
@@ -81,8 +137,8 @@ func TestCloud_GenerateUploadLink_Error(t *testing.T) {
 	}
 
 	mockMinioClient := &MockMinioClient{
-		PresignedPutObjectFunc: func(ctx context.Context, bucketName, objectName string, expires time.Duration) (*url.URL, error) {
-			return nil, expectedError
+		PresignedPostPolicyFunc: func(ctx context.Context, policy *minio.PostPolicy) (*url.URL, map[string]string, error) {
+			return nil, nil, expectedError
 		},
 	}
 
@@ -93,7 +149,7 @@ func TestCloud_GenerateUploadLink_Error(t *testing.T) {
 	}
 
 	// Вызываем метод GenerateUploadLink
-	_, err := cloud.GenerateUploadLink("test-file.txt")
+	_, _, err := cloud.GenerateUploadLink("test-file.txt", 1024, "text/plain", nil)
 
 	// Проверяем, что возникла ошибка
 	if err == nil {
@@ -146,14 +202,17 @@ func TestCloud_GenerateDownloadLink(t *testing.T) {
 	}
 
 	// Вызываем метод GenerateDownloadLink
-	url, err := cloud.GenerateDownloadLink("test-file.txt")
+	link, err := cloud.GenerateDownloadLink("test-file.txt", nil)
 
 	// Проверяем результаты
 	if err != nil {
 		t.Fatalf("Ошибка при вызове GenerateDownloadLink: %v", err)
 	}
-	if url != "https://example.com/download/test-file.txt" {
-		t.Errorf("Ожидался URL 'https://example.com/download/test-file.txt', получен '%s'", url)
+	if link.URL != "https://example.com/download/test-file.txt" {
+		t.Errorf("Ожидался URL 'https://example.com/download/test-file.txt', получен '%s'", link.URL)
+	}
+	if len(link.Headers) != 0 {
+		t.Errorf("Для незашифрованного объекта не ожидались заголовки, получено %v", link.Headers)
 	}
 }
 
@@ -179,7 +238,7 @@ func TestCloud_GenerateDownloadLink_Error(t *testing.T) {
 	}
 
 	// Вызываем метод GenerateDownloadLink
-	_, err := cloud.GenerateDownloadLink("test-file.txt")
+	_, err := cloud.GenerateDownloadLink("test-file.txt", nil)
 
 	// Проверяем, что возникла ошибка
 	if err == nil {
@@ -203,8 +262,8 @@ func TestCloud_GenerateDownloadLink_Error(t *testing.T) {
 func TestNewCloud(t *testing.T) {
 	// Создаем мок для MinioClientInterface
 	mockMinioClient := &MockMinioClient{
-		PresignedPutObjectFunc: func(ctx context.Context, bucketName, objectName string, expires time.Duration) (*url.URL, error) {
-			return nil, nil
+		PresignedPostPolicyFunc: func(ctx context.Context, policy *minio.PostPolicy) (*url.URL, map[string]string, error) {
+			return nil, nil, nil
 		},
 		PresignedGetObjectFunc: func(ctx context.Context, bucketName, objectName string, expires time.Duration, reqParams url.Values) (*url.URL, error) {
 			return nil, nil
@@ -225,3 +284,189 @@ func TestNewCloud(t *testing.T) {
 		t.Fatal("Возвращенный объект не реализует интерфейс CloudService")
 	}
 }
+
+// TestCloud_DeleteFile тестирует метод DeleteFile
+func TestCloud_DeleteFile(t *testing.T) {
+	removed := ""
+	mockMinioClient := &MockMinioClient{
+		RemoveObjectFunc: func(ctx context.Context, bucketName, objectName string, opts minio.RemoveObjectOptions) error {
+			if bucketName != "test-bucket" {
+				t.Errorf("Ожидалось имя бакета 'test-bucket', получено '%s'", bucketName)
+			}
+			removed = objectName
+			return nil
+		},
+	}
+
+	cloud := &Cloud{
+		minio:      mockMinioClient,
+		bucketName: "test-bucket",
+	}
+
+	if err := cloud.DeleteFile("test-file.txt"); err != nil {
+		t.Fatalf("Неожиданная ошибка: %v", err)
+	}
+	if removed != "test-file.txt" {
+		t.Errorf("Ожидалось удаление 'test-file.txt', удален '%s'", removed)
+	}
+}
+
+// TestCloud_RenameFile тестирует метод RenameFile
+func TestCloud_RenameFile(t *testing.T) {
+	var copiedFrom, copiedTo, removed string
+	mockMinioClient := &MockMinioClient{
+		CopyObjectFunc: func(ctx context.Context, dst minio.CopyDestOptions, src minio.CopySrcOptions) (minio.UploadInfo, error) {
+			copiedFrom = src.Object
+			copiedTo = dst.Object
+			return minio.UploadInfo{}, nil
+		},
+		RemoveObjectFunc: func(ctx context.Context, bucketName, objectName string, opts minio.RemoveObjectOptions) error {
+			removed = objectName
+			return nil
+		},
+	}
+
+	cloud := &Cloud{
+		minio:      mockMinioClient,
+		bucketName: "test-bucket",
+	}
+
+	if err := cloud.RenameFile("old.txt", "new.txt", nil); err != nil {
+		t.Fatalf("Неожиданная ошибка: %v", err)
+	}
+	if copiedFrom != "old.txt" || copiedTo != "new.txt" {
+		t.Errorf("Ожидалось копирование 'old.txt' в 'new.txt', получено '%s' -> '%s'", copiedFrom, copiedTo)
+	}
+	if removed != "old.txt" {
+		t.Errorf("Ожидалось удаление 'old.txt', удален '%s'", removed)
+	}
+}
+
+// TestCloud_RenameFile_NoSuchKey проверяет, что отсутствие объекта в хранилище не является ошибкой
+func TestCloud_RenameFile_NoSuchKey(t *testing.T) {
+	mockMinioClient := &MockMinioClient{
+		CopyObjectFunc: func(ctx context.Context, dst minio.CopyDestOptions, src minio.CopySrcOptions) (minio.UploadInfo, error) {
+			return minio.UploadInfo{}, minio.ErrorResponse{Code: "NoSuchKey"}
+		},
+		RemoveObjectFunc: func(ctx context.Context, bucketName, objectName string, opts minio.RemoveObjectOptions) error {
+			t.Error("Удаление не должно вызываться, если объекта нет")
+			return nil
+		},
+	}
+
+	cloud := &Cloud{
+		minio:      mockMinioClient,
+		bucketName: "test-bucket",
+	}
+
+	if err := cloud.RenameFile("old.txt", "new.txt", nil); err != nil {
+		t.Fatalf("Неожиданная ошибка: %v", err)
+	}
+}
+
+// TestCloud_SSEC тестирует шифрование объектов ключом пользователя
+func TestCloud_SSEC(t *testing.T) {
+	key := bytes.Repeat([]byte{7}, 32)
+	testURL, _ := url.Parse("https://example.com/test-bucket/test-file.txt")
+
+	mockMinioClient := &MockMinioClient{
+		PresignedPostPolicyFunc: func(ctx context.Context, policy *minio.PostPolicy) (*url.URL, map[string]string, error) {
+			return testURL, map[string]string{}, nil
+		},
+		PresignedGetObjectFunc: func(ctx context.Context, bucketName, objectName string, expires time.Duration, reqParams url.Values) (*url.URL, error) {
+			t.Error("Для зашифрованного объекта заголовки SSE-C должны входить в подпись")
+			return testURL, nil
+		},
+		PresignHeaderFunc: func(ctx context.Context, method, bucketName, objectName string, expires time.Duration, reqParams url.Values, extraHeaders http.Header) (*url.URL, error) {
+			if method != http.MethodGet {
+				t.Errorf("Ожидался метод GET, получен %s", method)
+			}
+			if extraHeaders.Get("X-Amz-Server-Side-Encryption-Customer-Key") == "" {
+				t.Error("Ожидался заголовок с ключом SSE-C")
+			}
+			return testURL, nil
+		},
+		CopyObjectFunc: func(ctx context.Context, dst minio.CopyDestOptions, src minio.CopySrcOptions) (minio.UploadInfo, error) {
+			if dst.Encryption == nil || src.Encryption == nil {
+				t.Error("Ожидалось копирование с ключом SSE-C для источника и назначения")
+			}
+			return minio.UploadInfo{}, nil
+		},
+		RemoveObjectFunc: func(ctx context.Context, bucketName, objectName string, opts minio.RemoveObjectOptions) error {
+			return nil
+		},
+	}
+
+	cloud := &Cloud{
+		minio:      mockMinioClient,
+		bucketName: "test-bucket",
+	}
+
+	if _, _, err := cloud.GenerateUploadLink("test-file.txt", 1024, "text/plain", key); err != nil {
+		t.Fatalf("Ошибка при вызове GenerateUploadLink: %v", err)
+	}
+
+	link, err := cloud.GenerateDownloadLink("test-file.txt", key)
+	if err != nil {
+		t.Fatalf("Ошибка при вызове GenerateDownloadLink: %v", err)
+	}
+	if link.Headers["X-Amz-Server-Side-Encryption-Customer-Algorithm"] != "AES256" {
+		t.Errorf("Ожидались заголовки SSE-C в ссылке, получено %v", link.Headers)
+	}
+	if link.Headers["X-Amz-Server-Side-Encryption-Customer-Key"] != base64.StdEncoding.EncodeToString(key) {
+		t.Error("Ожидался ключ пользователя в заголовках SSE-C")
+	}
+
+	if err := cloud.RenameFile("old.txt", "new.txt", key); err != nil {
+		t.Fatalf("Ошибка при вызове RenameFile: %v", err)
+	}
+
+	// Ключ неверной длины отклоняется
+	if _, _, err := cloud.GenerateUploadLink("test-file.txt", 1024, "text/plain", []byte("short")); err == nil {
+		t.Error("Ожидалась ошибка для ключа неверной длины")
+	}
+}
+
+// TestCloud_ReadFileHead тестирует чтение начала объекта запросом с диапазоном и ключом SSE-C
+func TestCloud_ReadFileHead(t *testing.T) {
+	key := bytes.Repeat([]byte{7}, 32)
+	storage := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/test-bucket/file.bin" {
+			w.Header().Set("Content-Type", "application/xml")
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?><Error><Code>NoSuchKey</Code><Message>not found</Message></Error>`))
+			return
+		}
+		if r.Header.Get("Range") != "bytes=0-3" {
+			t.Errorf("Ожидался диапазон bytes=0-3, получен %q", r.Header.Get("Range"))
+		}
+		if r.Header.Get("X-Amz-Server-Side-Encryption-Customer-Key") != base64.StdEncoding.EncodeToString(key) {
+			t.Error("Ожидался ключ пользователя в заголовках SSE-C")
+		}
+		w.Header().Set("Content-Range", "bytes 0-3/10")
+		w.Header().Set("Last-Modified", "Mon, 19 Oct 2026 08:00:00 GMT")
+		w.Header().Set("ETag", `"etag"`)
+		w.WriteHeader(http.StatusPartialContent)
+		w.Write([]byte("MZ\x90\x00"))
+	}))
+	defer storage.Close()
+
+	client, err := minio.New(strings.TrimPrefix(storage.URL, "https://"), &minio.Options{
+		Creds:     credentials.NewStaticV4("access", "secret", ""),
+		Secure:    true,
+		Region:    "us-east-1",
+		Transport: storage.Client().Transport,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cloud := &Cloud{minio: &MockMinioClient{GetObjectFunc: client.GetObject}, bucketName: "test-bucket"}
+
+	head, err := cloud.ReadFileHead("file.bin", 4, key)
+	if err != nil || string(head) != "MZ\x90\x00" {
+		t.Errorf("Ожидалось начало объекта, получено %q, %v", head, err)
+	}
+	if _, err := cloud.ReadFileHead("missing.bin", 4, key); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("Ожидалась ошибка domain.ErrNotFound, получено %v", err)
+	}
+}
@@ -2,25 +2,57 @@ package service
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/SmirnovND/gophkeeper/internal/domain"
 	"github.com/SmirnovND/gophkeeper/internal/interfaces"
+	"github.com/SmirnovND/gophkeeper/internal/itemtype"
+	"time"
 )
 
 // DataService реализует интерфейс для работы с данными пользователя
 type DataService struct {
-	repo     interfaces.UserDataRepo
-	userRepo interfaces.UserRepo
+	repo            interfaces.UserDataRepo
+	userRepo        interfaces.UserRepo
+	templateRepo    interfaces.ItemTemplateRepo
+	settingsService interfaces.SettingsService
+	quotaService    interfaces.QuotaService
 }
 
 // NewDataService создает новый экземпляр DataService
-func NewDataService(repo interfaces.UserDataRepo, userRepo interfaces.UserRepo) interfaces.DataService {
+func NewDataService(
+	repo interfaces.UserDataRepo,
+	userRepo interfaces.UserRepo,
+	templateRepo interfaces.ItemTemplateRepo,
+	settingsService interfaces.SettingsService,
+	quotaService interfaces.QuotaService,
+) interfaces.DataService {
 	return &DataService{
-		repo:     repo,
-		userRepo: userRepo,
+		repo:            repo,
+		userRepo:        userRepo,
+		templateRepo:    templateRepo,
+		settingsService: settingsService,
+		quotaService:    quotaService,
 	}
 }
 
+// saveUserData сохраняет запись, проверяя квоту пользователя в одной транзакции с сохранением, чтобы
+// параллельные сохранения не превысили ее. size - размер сохраняемого файла, для остальных записей 0
+func (c *DataService) saveUserData(userData *domain.UserData, size int64) error {
+	if c.quotaService == nil {
+		return c.repo.SaveUserData(userData)
+	}
+
+	quota, err := c.quotaService.GetUserQuota(userData.UserID)
+	if err != nil {
+		return err
+	}
+
+	return c.repo.SaveUserDataChecked(userData, func(usage *domain.Usage, existing *domain.UserData) error {
+		return quota.CheckSave(usage, existing, size)
+	})
+}
+
 // SaveFileMetadata сохраняет метаданные файла
 func (c *DataService) SaveFileMetadata(login string, label string, fileData *domain.FileData, metadata string) error {
 	// Получаем пользователя по логину
@@ -29,10 +61,14 @@ func (c *DataService) SaveFileMetadata(login string, label string, fileData *dom
 		return fmt.Errorf("Ошибка при поиске пользователя: %w", err)
 	}
 
-	// Создаем метаданные файла (сохраняем только имя и расширение, URL не сохраняем)
+	// Создаем метаданные файла (сохраняем имя, расширение, размер и тип содержимого, URL не сохраняем)
 	fileMetadata := domain.FileMetadata{
-		FileName:  fileData.Name,
-		Extension: fileData.Extension,
+		FileName:     fileData.Name,
+		Extension:    fileData.Extension,
+		Size:         fileData.Size,
+		MimeType:     fileData.MimeType,
+		OriginalName: fileData.OriginalName,
+		Encrypted:    fileData.Encrypted,
 	}
 
 	// Преобразуем метаданные в JSON
@@ -51,7 +87,7 @@ func (c *DataService) SaveFileMetadata(login string, label string, fileData *dom
 	}
 
 	// Сохраняем запись в базе данных
-	err = c.repo.SaveUserData(userData)
+	err = c.saveUserData(userData, fileData.Size)
 	if err != nil {
 		return fmt.Errorf("ошибка при сохранении метаданных файла: %w", err)
 	}
@@ -88,296 +124,485 @@ func (c *DataService) GetFileMetadata(login string, label string) (*domain.FileM
 	return &fileMetadata, userData.Metadata, nil
 }
 
-// SaveCredential сохраняет учетные данные (логин/пароль)
-func (c *DataService) SaveCredential(login string, label string, credentialData *domain.CredentialData, metadata string) error {
+// ListFiles возвращает сведения обо всех файлах пользователя
+func (c *DataService) ListFiles(login string) ([]domain.FileInfo, error) {
 	// Получаем пользователя по логину
 	user, err := c.userRepo.FindUser(login)
 	if err != nil {
-		return fmt.Errorf("ошибка при поиске пользователя: %w", err)
+		return nil, fmt.Errorf("ошибка при поиске пользователя: %w", err)
 	}
 
-	// Преобразуем данные в JSON
-	dataJSON, err := json.Marshal(credentialData)
+	// Получаем все записи пользователя с типом file
+	items, err := c.repo.ListUserDataByType(user.Id, domain.UserDataTypeFile)
 	if err != nil {
-		return fmt.Errorf("ошибка при маршалинге учетных данных: %w", err)
-	}
-
-	// Создаем запись в таблице user_data
-	userData := &domain.UserData{
-		UserID:   user.Id,
-		Label:    label,
-		Type:     domain.UserDataTypeCredential,
-		Data:     dataJSON,
-		Metadata: metadata,
+		return nil, fmt.Errorf("ошибка при получении списка файлов: %w", err)
 	}
 
-	// Сохраняем запись в базе данных
-	err = c.repo.SaveUserData(userData)
-	if err != nil {
-		return fmt.Errorf("ошибка при сохранении учетных данных: %w", err)
+	files := make([]domain.FileInfo, 0, len(items))
+	for _, userData := range items {
+		fileInfo, err := toFileInfo(userData)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, *fileInfo)
 	}
 
-	return nil
+	return files, nil
 }
 
-// GetCredential получает учетные данные (логин/пароль)
-func (c *DataService) GetCredential(login string, label string) (*domain.CredentialData, string, error) {
+// ListItems возвращает сведения обо всех записях пользователя без их содержимого
+func (c *DataService) ListItems(login string) ([]domain.ItemInfo, error) {
 	// Получаем пользователя по логину
 	user, err := c.userRepo.FindUser(login)
 	if err != nil {
-		return nil, "", fmt.Errorf("ошибка при поиске пользователя: %w", err)
+		return nil, fmt.Errorf("ошибка при поиске пользователя: %w", err)
 	}
 
-	// Получаем данные пользователя по метке и типу
-	userData, err := c.repo.GetUserDataByLabelAndType(user.Id, label, domain.UserDataTypeCredential)
+	items, err := c.repo.ListUserData(user.Id)
 	if err != nil {
-		return nil, "", fmt.Errorf("ошибка при получении учетных данных: %w", err)
+		return nil, fmt.Errorf("ошибка при получении списка записей: %w", err)
+	}
+	uris, err := c.credentialURIs(user.Id)
+	if err != nil {
+		return nil, err
 	}
 
-	// Если данные не найдены
-	if userData == nil {
-		return nil, "", fmt.Errorf("учетные данные не найдены")
+	result := make([]domain.ItemInfo, 0, len(items))
+	for _, userData := range items {
+		info := domain.ItemInfo{
+			Type:      userData.Type,
+			Label:     userData.Label,
+			Metadata:  userData.Metadata,
+			CreatedAt: userData.CreatedAt,
+			UpdatedAt: userData.UpdatedAt,
+		}
+		if userData.Type == domain.UserDataTypeCredential {
+			info.URIs = uris[userData.Label]
+		}
+		result = append(result, info)
 	}
 
-	// Десериализуем данные из JSON
-	var credentialData domain.CredentialData
-	err = json.Unmarshal(userData.Data, &credentialData)
+	return result, nil
+}
+
+// credentialURIs возвращает адреса учетных данных пользователя по меткам записей
+func (c *DataService) credentialURIs(userID string) (map[string][]domain.CredentialURI, error) {
+	credentials, err := c.repo.ListUserDataByType(userID, domain.UserDataTypeCredential)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка при получении списка учетных данных: %w", err)
+	}
+
+	uris := make(map[string][]domain.CredentialURI)
+	for _, userData := range credentials {
+		var data domain.ItemData
+		if err := json.Unmarshal(userData.Data, &data); err != nil {
+			return nil, fmt.Errorf("ошибка при десериализации записи: %w", err)
+		}
+		if data["uris"] == "" {
+			continue
+		}
+		var list []domain.CredentialURI
+		if err := json.Unmarshal([]byte(data["uris"]), &list); err != nil {
+			return nil, fmt.Errorf("ошибка при разборе адресов учетных данных '%s': %w", userData.Label, err)
+		}
+		uris[userData.Label] = list
+	}
+	return uris, nil
+}
+
+// GetFileInfo возвращает сведения о файле пользователя
+func (c *DataService) GetFileInfo(login string, label string) (*domain.FileInfo, error) {
+	// Получаем пользователя по логину
+	user, err := c.userRepo.FindUser(login)
 	if err != nil {
-		return nil, "", fmt.Errorf("ошибка при десериализации учетных данных: %w", err)
+		return nil, fmt.Errorf("ошибка при поиске пользователя: %w", err)
 	}
 
-	return &credentialData, userData.Metadata, nil
+	// Получаем данные пользователя по метке и типу
+	userData, err := c.repo.GetUserDataByLabelAndType(user.Id, label, domain.UserDataTypeFile)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка при получении метаданных файла: %w", err)
+	}
+
+	return toFileInfo(userData)
 }
 
-// SaveCard сохраняет данные кредитной карты
-func (c *DataService) SaveCard(login string, label string, cardData *domain.CardData, metadata string) error {
+// RenameFileMetadata меняет метку файла и возвращает метаданные файла до переименования
+func (c *DataService) RenameFileMetadata(login string, label string, newLabel string) (*domain.FileMetadata, error) {
 	// Получаем пользователя по логину
 	user, err := c.userRepo.FindUser(login)
 	if err != nil {
-		return fmt.Errorf("ошибка при поиске пользователя: %w", err)
+		return nil, fmt.Errorf("ошибка при поиске пользователя: %w", err)
 	}
 
-	// Преобразуем данные в JSON
-	dataJSON, err := json.Marshal(cardData)
+	// Получаем данные пользователя по метке и типу
+	userData, err := c.repo.GetUserDataByLabelAndType(user.Id, label, domain.UserDataTypeFile)
 	if err != nil {
-		return fmt.Errorf("ошибка при маршалинге данных карты: %w", err)
+		return nil, fmt.Errorf("ошибка при получении метаданных файла: %w", err)
 	}
 
-	// Создаем запись в таблице user_data
-	userData := &domain.UserData{
-		UserID:   user.Id,
-		Label:    label,
-		Type:     domain.UserDataTypeCard,
-		Data:     dataJSON,
-		Metadata: metadata,
+	// Новая метка не должна быть занята другой записью
+	_, err = c.repo.FindUserDataByLabel(user.Id, newLabel)
+	if err == nil {
+		return nil, fmt.Errorf("метка '%s' уже используется: %w", newLabel, domain.ErrAlreadyExists)
+	}
+	if !errors.Is(err, domain.ErrNotFound) {
+		return nil, fmt.Errorf("ошибка при проверке метки: %w", err)
 	}
 
-	// Сохраняем запись в базе данных
-	err = c.repo.SaveUserData(userData)
+	// Десериализуем метаданные из JSON
+	var fileMetadata domain.FileMetadata
+	err = json.Unmarshal(userData.Data, &fileMetadata)
 	if err != nil {
-		return fmt.Errorf("ошибка при сохранении данных карты: %w", err)
+		return nil, fmt.Errorf("ошибка при десериализации метаданных файла: %w", err)
 	}
 
-	return nil
+	// Имя файла в хранилище формируется из метки, поэтому меняем и его
+	renamed := fileMetadata
+	renamed.FileName = newLabel
+	metadataJSON, err := json.Marshal(renamed)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка при маршалинге метаданных файла: %w", err)
+	}
+
+	err = c.repo.RenameUserData(userData.ID, newLabel, metadataJSON)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка при переименовании файла: %w", err)
+	}
+
+	return &fileMetadata, nil
 }
 
-// GetCard получает данные кредитной карты
-func (c *DataService) GetCard(login string, label string) (*domain.CardData, string, error) {
+// toFileInfo преобразует запись пользователя с типом file в сведения о файле
+func toFileInfo(userData *domain.UserData) (*domain.FileInfo, error) {
+	var fileMetadata domain.FileMetadata
+	err := json.Unmarshal(userData.Data, &fileMetadata)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка при десериализации метаданных файла: %w", err)
+	}
+
+	return &domain.FileInfo{
+		Label:        userData.Label,
+		Extension:    fileMetadata.Extension,
+		Size:         fileMetadata.Size,
+		MimeType:     fileMetadata.MimeType,
+		OriginalName: fileMetadata.OriginalName,
+		Encrypted:    fileMetadata.Encrypted,
+		Metadata:     userData.Metadata,
+		CreatedAt:    userData.CreatedAt,
+		UpdatedAt:    userData.UpdatedAt,
+	}, nil
+}
+
+// DeleteFileMetadata удаляет метаданные файла
+func (c *DataService) DeleteFileMetadata(login string, label string) error {
 	// Получаем пользователя по логину
 	user, err := c.userRepo.FindUser(login)
 	if err != nil {
-		return nil, "", fmt.Errorf("ошибка при поиске пользователя: %w", err)
+		return fmt.Errorf("ошибка при поиске пользователя: %w", err)
 	}
 
-	// Получаем данные пользователя по метке и типу
-	userData, err := c.repo.GetUserDataByLabelAndType(user.Id, label, domain.UserDataTypeCard)
+	// Удаляем данные пользователя по метке и типу
+	userData, err := c.repo.GetUserDataByLabelAndType(user.Id, label, domain.UserDataTypeFile)
 	if err != nil {
-		return nil, "", fmt.Errorf("ошибка при получении данных карты: %w", err)
+		return fmt.Errorf("ошибка при получении метаданных файла: %w", err)
 	}
 
 	// Если данные не найдены
 	if userData == nil {
-		return nil, "", fmt.Errorf("данные карты не найдены")
+		return fmt.Errorf("метаданные файла не найдены")
 	}
 
-	// Десериализуем данные из JSON
-	var cardData domain.CardData
-	err = json.Unmarshal(userData.Data, &cardData)
+	// Удаляем запись
+	err = c.repo.DeleteUserData(userData.ID)
 	if err != nil {
-		return nil, "", fmt.Errorf("ошибка при десериализации данных карты: %w", err)
+		return fmt.Errorf("ошибка при удалении метаданных файла: %w", err)
 	}
 
-	return &cardData, userData.Metadata, nil
+	return nil
 }
 
-// SaveText сохраняет текстовые данные
-func (c *DataService) SaveText(login string, label string, textData *domain.TextData, metadata string) error {
+// SaveItem сохраняет запись встроенного или пользовательского типа после проверки ее полей по описанию типа
+func (c *DataService) SaveItem(login string, itemType string, label string, data domain.ItemData, metadata string) error {
 	// Получаем пользователя по логину
 	user, err := c.userRepo.FindUser(login)
 	if err != nil {
 		return fmt.Errorf("ошибка при поиске пользователя: %w", err)
 	}
 
+	t, err := c.lookupType(user.Id, itemType)
+	if err != nil {
+		return err
+	}
+	if err := t.Validate(data); err != nil {
+		return err
+	}
+	if t.Name == domain.UserDataTypeCredential && c.settingsService != nil {
+		if err := c.keepPasswordHistory(login, user.Id, label, data); err != nil {
+			return err
+		}
+	}
+
 	// Преобразуем данные в JSON
-	dataJSON, err := json.Marshal(textData)
+	dataJSON, err := json.Marshal(data)
 	if err != nil {
-		return fmt.Errorf("ошибка при маршалинге текстовых данных: %w", err)
+		return fmt.Errorf("ошибка при маршалинге записи: %w", err)
 	}
 
 	// Создаем запись в таблице user_data
 	userData := &domain.UserData{
 		UserID:   user.Id,
 		Label:    label,
-		Type:     domain.UserDataTypeText,
+		Type:     t.Name,
 		Data:     dataJSON,
 		Metadata: metadata,
 	}
 
 	// Сохраняем запись в базе данных
-	err = c.repo.SaveUserData(userData)
+	err = c.saveUserData(userData, 0)
 	if err != nil {
-		return fmt.Errorf("ошибка при сохранении текстовых данных: %w", err)
+		return fmt.Errorf("ошибка при сохранении записи: %w", err)
 	}
 
 	return nil
 }
 
-// GetText получает текстовые данные
-func (c *DataService) GetText(login string, label string) (*domain.TextData, string, error) {
-	// Получаем пользователя по логину
-	user, err := c.userRepo.FindUser(login)
+// keepPasswordHistory ведет историю паролей сохраняемых учетных данных: если пароль меняется, прежний пароль
+// со временем смены добавляется в начало истории. История из data заменяет сохраненную, а без нее сохраненная
+// история переносится в новую версию записи. История обрезается до длины из настроек пользователя
+func (c *DataService) keepPasswordHistory(login string, userID string, label string, data domain.ItemData) error {
+	history, err := passwordHistory(data)
 	if err != nil {
-		return nil, "", fmt.Errorf("ошибка при поиске пользователя: %w", err)
+		return err
 	}
 
-	// Получаем данные пользователя по метке и типу
-	userData, err := c.repo.GetUserDataByLabelAndType(user.Id, label, domain.UserDataTypeText)
-	if err != nil {
-		return nil, "", fmt.Errorf("ошибка при получении текстовых данных: %w", err)
+	existing, err := c.repo.GetUserDataByLabelAndType(userID, label, domain.UserDataTypeCredential)
+	if err != nil && !errors.Is(err, domain.ErrNotFound) {
+		return fmt.Errorf("ошибка при получении записи: %w", err)
+	}
+	if err == nil && existing != nil {
+		var previous domain.ItemData
+		if err := json.Unmarshal(existing.Data, &previous); err != nil {
+			return fmt.Errorf("ошибка при десериализации записи: %w", err)
+		}
+		if data["password_history"] == "" {
+			if history, err = passwordHistory(previous); err != nil {
+				return err
+			}
+		}
+		if previous["password"] != "" && previous["password"] != data["password"] {
+			history = append([]domain.PasswordChange{{Password: previous["password"], ChangedAt: time.Now().UTC()}}, history...)
+		}
 	}
 
-	// Если данные не найдены
-	if userData == nil {
-		return nil, "", fmt.Errorf("текстовые данные не найдены")
+	settings, err := c.settingsService.GetSettings(login)
+	if err != nil {
+		return err
+	}
+	if len(history) > settings.PasswordHistory {
+		history = history[:settings.PasswordHistory]
+	}
+	if len(history) == 0 {
+		delete(data, "password_history")
+		return nil
 	}
 
-	// Десериализуем данные из JSON
-	var textData domain.TextData
-	err = json.Unmarshal(userData.Data, &textData)
+	historyJSON, err := json.Marshal(history)
 	if err != nil {
-		return nil, "", fmt.Errorf("ошибка при десериализации текстовых данных: %w", err)
+		return fmt.Errorf("ошибка при маршалинге истории паролей: %w", err)
 	}
+	data["password_history"] = string(historyJSON)
+	return nil
+}
 
-	return &textData, userData.Metadata, nil
+// passwordHistory возвращает историю паролей из значений полей учетных данных
+func passwordHistory(data domain.ItemData) ([]domain.PasswordChange, error) {
+	var history []domain.PasswordChange
+	if data["password_history"] == "" {
+		return history, nil
+	}
+	if err := json.Unmarshal([]byte(data["password_history"]), &history); err != nil {
+		return nil, fmt.Errorf("ошибка при разборе истории паролей: %w", err)
+	}
+	return history, nil
 }
 
-// DeleteFileMetadata удаляет метаданные файла
-func (c *DataService) DeleteFileMetadata(login string, label string) error {
-	// Получаем пользователя по логину
-	user, err := c.userRepo.FindUser(login)
+// GetItem получает запись зарегистрированного типа. Отсутствие записи возвращается ошибкой domain.ErrNotFound
+func (c *DataService) GetItem(login string, itemType string, label string) (domain.ItemData, string, error) {
+	userData, err := c.findItem(login, itemType, label)
 	if err != nil {
-		return fmt.Errorf("ошибка при поиске пользователя: %w", err)
+		return nil, "", err
 	}
 
-	// Удаляем данные пользователя по метке и типу
-	userData, err := c.repo.GetUserDataByLabelAndType(user.Id, label, domain.UserDataTypeFile)
+	// Десериализуем данные из JSON
+	var data domain.ItemData
+	err = json.Unmarshal(userData.Data, &data)
 	if err != nil {
-		return fmt.Errorf("ошибка при получении метаданных файла: %w", err)
+		return nil, "", fmt.Errorf("ошибка при десериализации записи: %w", err)
 	}
 
-	// Если данные не найдены
-	if userData == nil {
-		return fmt.Errorf("метаданные файла не найдены")
+	return data, userData.Metadata, nil
+}
+
+// DeleteItem удаляет запись зарегистрированного типа
+func (c *DataService) DeleteItem(login string, itemType string, label string) error {
+	userData, err := c.findItem(login, itemType, label)
+	if err != nil {
+		return err
 	}
 
 	// Удаляем запись
 	err = c.repo.DeleteUserData(userData.ID)
 	if err != nil {
-		return fmt.Errorf("ошибка при удалении метаданных файла: %w", err)
+		return fmt.Errorf("ошибка при удалении записи: %w", err)
 	}
 
 	return nil
 }
 
-// DeleteCredential удаляет учетные данные (логин/пароль)
-func (c *DataService) DeleteCredential(login string, label string) error {
+// findItem находит запись зарегистрированного типа по метке
+func (c *DataService) findItem(login string, itemType string, label string) (*domain.UserData, error) {
 	// Получаем пользователя по логину
 	user, err := c.userRepo.FindUser(login)
 	if err != nil {
-		return fmt.Errorf("ошибка при поиске пользователя: %w", err)
+		return nil, fmt.Errorf("ошибка при поиске пользователя: %w", err)
 	}
 
-	// Удаляем данные пользователя по метке и типу
-	userData, err := c.repo.GetUserDataByLabelAndType(user.Id, label, domain.UserDataTypeCredential)
+	t, err := c.lookupType(user.Id, itemType)
 	if err != nil {
-		return fmt.Errorf("ошибка при получении учетных данных: %w", err)
+		return nil, err
 	}
 
-	// Если данные не найдены
-	if userData == nil {
-		return fmt.Errorf("учетные данные не найдены")
+	// Получаем данные пользователя по метке и типу
+	userData, err := c.repo.GetUserDataByLabelAndType(user.Id, label, t.Name)
+	if errors.Is(err, domain.ErrNotFound) || (err == nil && userData == nil) {
+		return nil, fmt.Errorf("запись '%s' (%s) не найдена: %w", label, t.Title, domain.ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("ошибка при получении записи: %w", err)
 	}
 
-	// Удаляем запись
-	err = c.repo.DeleteUserData(userData.ID)
+	return userData, nil
+}
+
+// ItemType возвращает описание встроенного типа или пользовательского типа из шаблона пользователя
+func (c *DataService) ItemType(login string, itemType string) (*itemtype.Type, error) {
+	// Встроенные типы не зависят от пользователя
+	if t, err := itemtype.Lookup(itemType); err == nil {
+		return t, nil
+	}
+
+	// Получаем пользователя по логину
+	user, err := c.userRepo.FindUser(login)
 	if err != nil {
-		return fmt.Errorf("ошибка при удалении учетных данных: %w", err)
+		return nil, fmt.Errorf("ошибка при поиске пользователя: %w", err)
 	}
 
-	return nil
+	return c.lookupType(user.Id, itemType)
 }
 
-// DeleteCard удаляет данные кредитной карты
-func (c *DataService) DeleteCard(login string, label string) error {
+// lookupType находит тип записи в реестре, а если его там нет, - среди шаблонов пользователя
+func (c *DataService) lookupType(userID string, itemType string) (*itemtype.Type, error) {
+	t, err := itemtype.Lookup(itemType)
+	if err == nil || c.templateRepo == nil {
+		return t, err
+	}
+
+	template, templateErr := c.templateRepo.GetItemTemplate(userID, itemType)
+	if errors.Is(templateErr, domain.ErrNotFound) {
+		return nil, err
+	}
+	if templateErr != nil {
+		return nil, fmt.Errorf("ошибка при получении шаблона: %w", templateErr)
+	}
+
+	return itemtype.ParseSchema(template.Name, template.Schema)
+}
+
+// SaveItemTemplate сохраняет шаблон пользовательского типа после проверки его схемы
+func (c *DataService) SaveItemTemplate(login string, name string, schema json.RawMessage) (*domain.ItemTemplate, error) {
+	if _, err := itemtype.ParseSchema(name, schema); err != nil {
+		return nil, err
+	}
+
 	// Получаем пользователя по логину
 	user, err := c.userRepo.FindUser(login)
 	if err != nil {
-		return fmt.Errorf("ошибка при поиске пользователя: %w", err)
+		return nil, fmt.Errorf("ошибка при поиске пользователя: %w", err)
 	}
 
-	// Удаляем данные пользователя по метке и типу
-	userData, err := c.repo.GetUserDataByLabelAndType(user.Id, label, domain.UserDataTypeCard)
+	template := &domain.ItemTemplate{Name: name, Schema: schema}
+	err = c.templateRepo.SaveItemTemplate(user.Id, template)
 	if err != nil {
-		return fmt.Errorf("ошибка при получении данных карты: %w", err)
+		return nil, fmt.Errorf("ошибка при сохранении шаблона: %w", err)
 	}
 
-	// Если данные не найдены
-	if userData == nil {
-		return fmt.Errorf("данные карты не найдены")
+	return template, nil
+}
+
+// GetItemTemplate получает шаблон пользовательского типа. Отсутствие шаблона возвращается ошибкой domain.ErrNotFound
+func (c *DataService) GetItemTemplate(login string, name string) (*domain.ItemTemplate, error) {
+	// Получаем пользователя по логину
+	user, err := c.userRepo.FindUser(login)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка при поиске пользователя: %w", err)
 	}
 
-	// Удаляем запись
-	err = c.repo.DeleteUserData(userData.ID)
+	template, err := c.templateRepo.GetItemTemplate(user.Id, name)
+	if errors.Is(err, domain.ErrNotFound) {
+		return nil, fmt.Errorf("шаблон '%s' не найден: %w", name, domain.ErrNotFound)
+	}
 	if err != nil {
-		return fmt.Errorf("ошибка при удалении данных карты: %w", err)
+		return nil, fmt.Errorf("ошибка при получении шаблона: %w", err)
 	}
 
-	return nil
+	return template, nil
 }
 
-// DeleteText удаляет текстовые данные
-func (c *DataService) DeleteText(login string, label string) error {
+// ListItemTemplates возвращает все шаблоны пользовательских типов
+func (c *DataService) ListItemTemplates(login string) ([]domain.ItemTemplate, error) {
 	// Получаем пользователя по логину
 	user, err := c.userRepo.FindUser(login)
 	if err != nil {
-		return fmt.Errorf("ошибка при поиске пользователя: %w", err)
+		return nil, fmt.Errorf("ошибка при поиске пользователя: %w", err)
 	}
 
-	// Удаляем данные пользователя по метке и типу
-	userData, err := c.repo.GetUserDataByLabelAndType(user.Id, label, domain.UserDataTypeText)
+	templates, err := c.templateRepo.ListItemTemplates(user.Id)
 	if err != nil {
-		return fmt.Errorf("ошибка при получении текстовых данных: %w", err)
+		return nil, fmt.Errorf("ошибка при получении списка шаблонов: %w", err)
 	}
 
-	// Если данные не найдены
-	if userData == nil {
-		return fmt.Errorf("текстовые данные не найдены")
+	result := make([]domain.ItemTemplate, 0, len(templates))
+	for _, template := range templates {
+		result = append(result, *template)
 	}
 
-	// Удаляем запись
-	err = c.repo.DeleteUserData(userData.ID)
+	return result, nil
+}
+
+// DeleteItemTemplate удаляет шаблон пользовательского типа. Шаблон, по которому сохранены записи,
+// не удаляется, иначе записи нельзя было бы прочитать по их типу
+func (c *DataService) DeleteItemTemplate(login string, name string) error {
+	// Получаем пользователя по логину
+	user, err := c.userRepo.FindUser(login)
+	if err != nil {
+		return fmt.Errorf("ошибка при поиске пользователя: %w", err)
+	}
+
+	items, err := c.repo.ListUserDataByType(user.Id, name)
+	if err != nil {
+		return fmt.Errorf("ошибка при проверке записей шаблона: %w", err)
+	}
+	if len(items) > 0 {
+		return fmt.Errorf("шаблон '%s' используется записями (%d), сначала удалите их: %w", name, len(items), domain.ErrTemplateInUse)
+	}
+
+	err = c.templateRepo.DeleteItemTemplate(user.Id, name)
+	if errors.Is(err, domain.ErrNotFound) {
+		return fmt.Errorf("шаблон '%s' не найден: %w", name, domain.ErrNotFound)
+	}
 	if err != nil {
-		return fmt.Errorf("ошибка при удалении текстовых данных: %w", err)
+		return fmt.Errorf("ошибка при удалении шаблона: %w", err)
 	}
 
 	return nil
@@ -5,6 +5,9 @@ import (
 	"errors"
 	"github.com/SmirnovND/gophkeeper/internal/domain"
 	"github.com/SmirnovND/gophkeeper/internal/interfaces"
+	"github.com/SmirnovND/gophkeeper/internal/itemtype"
+	"github.com/SmirnovND/gophkeeper/internal/sshkey"
+	"github.com/SmirnovND/gophkeeper/internal/totp"
 	"testing"
 	"time"
 )
@@ -16,7 +19,7 @@ func TestNewDataService(t *testing.T) {
 	mockUserDataRepo := &MockUserDataRepo{}
 
 	// Вызываем функцию NewDataService
-	dataService := NewDataService(mockUserDataRepo, mockUserRepo)
+	dataService := NewDataService(mockUserDataRepo, mockUserRepo, NewMockItemTemplateRepo(), &MockSettingsService{}, nil)
 
 	// Проверяем, что возвращенный объект не nil
 	if dataService == nil {
@@ -97,6 +100,60 @@ func TestDataService_SaveFileMetadata(t *testing.T) {
 	}
 }
 
+// TestDataService_SaveFileMetadata_Quota тестирует проверку квоты при сохранении по потреблению,
+// полученному репозиторием под блокировкой пользователя, а не при предварительной проверке
+func TestDataService_SaveFileMetadata_Quota(t *testing.T) {
+	tests := []struct {
+		name      string
+		usage     domain.Usage
+		wantQuota bool
+	}{
+		{"Квота не превышена", domain.Usage{Items: 1, TotalBytes: 50}, false},
+		// Параллельное сохранение заняло последнюю запись после предварительной проверки
+		{"Превышено количество записей", domain.Usage{Items: 2}, true},
+		{"Превышен суммарный размер", domain.Usage{Items: 1, TotalBytes: 80}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			saved := false
+			mockUserDataRepo := &MockUserDataRepo{
+				SaveUserDataCheckedFunc: func(userData *domain.UserData, check func(usage *domain.Usage, existing *domain.UserData) error) error {
+					usage := tt.usage
+					if err := check(&usage, nil); err != nil {
+						return err
+					}
+					saved = true
+					return nil
+				},
+			}
+			mockUserRepo := &MockUserRepo{
+				FindUserFunc: func(login string) (*domain.User, error) {
+					return &domain.User{Id: "user123"}, nil
+				},
+			}
+			quotaService := newTestQuotaService(domain.Usage{}, domain.Quota{MaxItems: 2, MaxTotalBytes: 100}, nil)
+
+			dataService := NewDataService(mockUserDataRepo, mockUserRepo, NewMockItemTemplateRepo(), &MockSettingsService{}, quotaService)
+			fileData := &domain.FileData{Name: "test-file", Extension: "txt", Size: 30}
+			err := dataService.SaveFileMetadata("testuser", "test-file", fileData, "")
+
+			if tt.wantQuota {
+				if !errors.Is(err, domain.ErrQuotaExceeded) {
+					t.Errorf("Ожидалась ошибка превышения квоты, получено: %v", err)
+				}
+				if saved {
+					t.Error("Запись не должна сохраняться при превышении квоты")
+				}
+				return
+			}
+			if err != nil || !saved {
+				t.Errorf("Ожидалось сохранение записи, ошибка: %v", err)
+			}
+		})
+	}
+}
+
 // TestDataService_SaveFileMetadata_UserNotFound тестирует метод SaveFileMetadata с ошибкой "пользователь не найден"
 func TestDataService_SaveFileMetadata_UserNotFound(t *testing.T) {
 	// Создаем моки для репозиториев
@@ -472,1432 +529,656 @@ func TestDataService_DeleteFileMetadata(t *testing.T) {
 	})
 }
 
-// TestDataService_SaveCredential тестирует метод SaveCredential
-func TestDataService_SaveCredential(t *testing.T) {
-	// Тест успешного сохранения
-	t.Run("Success", func(t *testing.T) {
-		// Создаем моки для репозиториев
-		mockUserRepo := &MockUserRepo{
-			FindUserFunc: func(login string) (*domain.User, error) {
-				// Проверяем параметры
-				if login != "testuser" {
-					t.Errorf("Ожидался логин 'testuser', получен '%s'", login)
+// TestDataService_SaveItem_Builtin тестирует сохранение встроенных типов с проверкой ключей TOTP и SSH
+func TestDataService_SaveItem_Builtin(t *testing.T) {
+	mockUserRepo := &MockUserRepo{
+		FindUserFunc: func(login string) (*domain.User, error) {
+			return &domain.User{Id: "user123", Credentials: domain.Credentials{Login: login}}, nil
+		},
+	}
+
+	var saved *domain.UserData
+	mockUserDataRepo := &MockUserDataRepo{
+		SaveUserDataFunc: func(userData *domain.UserData) error {
+			saved = userData
+			return nil
+		},
+	}
+
+	dataService := &DataService{
+		repo:     mockUserDataRepo,
+		userRepo: mockUserRepo,
+	}
+
+	sshKeyData, err := sshkey.Generate(sshkey.TypeEd25519, 0, "deploy")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sshKey, err := itemtype.Encode(sshKeyData)
+	if err != nil {
+		t.Fatal(err)
+	}
+	invalidKey := domain.ItemData{}
+	for name, value := range sshKey {
+		invalidKey[name] = value
+	}
+	invalidKey["fingerprint"] = "SHA256:other"
+
+	tests := []struct {
+		name     string
+		itemType string
+		data     domain.ItemData
+		err      error
+	}{
+		{"учетные данные", domain.UserDataTypeCredential, domain.ItemData{"login": "alice", "password": "s3cret"}, nil},
+		{"учетные данные с TOTP", domain.UserDataTypeCredential, domain.ItemData{"login": "alice", "password": "s3cret", "totp": "otpauth://totp/GitHub:alice?secret=JBSWY3DPEHPK3PXP"}, nil},
+		{"неверный ключ TOTP", domain.UserDataTypeCredential, domain.ItemData{"login": "alice", "totp": "otpauth://totp/GitHub:alice?secret=JBSWY3DPEHPK3PXP&digits=12"}, totp.ErrInvalidURI},
+		{"карта", domain.UserDataTypeCard, domain.ItemData{"number": "4111 1111 1111 1111", "holder": "ALICE", "expiry_date": "12/27", "cvv": "123"}, nil},
+		{"текст", domain.UserDataTypeText, domain.ItemData{"content": "заметка"}, nil},
+		{"ключ SSH", domain.UserDataTypeSSHKey, sshKey, nil},
+		{"неверный ключ SSH", domain.UserDataTypeSSHKey, invalidKey, sshkey.ErrInvalidKey},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			saved = nil
+			err := dataService.SaveItem("testuser", tt.itemType, "item", tt.data, "test metadata")
+			if tt.err != nil {
+				if !errors.Is(err, tt.err) {
+					t.Errorf("Ожидалась ошибка %v, получено %v", tt.err, err)
 				}
-				return &domain.User{
-					Id: "user123",
-					Credentials: domain.Credentials{
-						Login:    "testuser",
-						PassHash: "hash",
-					},
-				}, nil
+				if saved != nil {
+					t.Error("Неверная запись не должна сохраняться")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Ошибка при вызове SaveItem: %v", err)
+			}
+			if saved.Type != tt.itemType || saved.UserID != "user123" || saved.Metadata != "test metadata" {
+				t.Fatalf("Запись сохранена неверно: %+v", saved)
+			}
+			var savedData domain.ItemData
+			if err := json.Unmarshal(saved.Data, &savedData); err != nil || len(savedData) != len(tt.data) {
+				t.Errorf("Ожидались данные %v, получено %v, %v", tt.data, savedData, err)
+			}
+		})
+	}
+}
+
+// TestDataService_SaveItem_Errors тестирует ошибки поиска пользователя и сохранения записи
+func TestDataService_SaveItem_Errors(t *testing.T) {
+	data := domain.ItemData{"content": "заметка"}
+
+	dataService := &DataService{
+		repo: &MockUserDataRepo{},
+		userRepo: &MockUserRepo{
+			FindUserFunc: func(login string) (*domain.User, error) {
+				return nil, errors.New("пользователь не найден")
 			},
-		}
+		},
+	}
+	if err := dataService.SaveItem("testuser", domain.UserDataTypeText, "note", data, ""); err == nil {
+		t.Error("Ожидалась ошибка поиска пользователя, но ее не было")
+	}
 
-		mockUserDataRepo := &MockUserDataRepo{
+	dataService = &DataService{
+		repo: &MockUserDataRepo{
 			SaveUserDataFunc: func(userData *domain.UserData) error {
-				// Проверяем параметры
-				if userData.UserID != "user123" {
-					t.Errorf("Ожидался UserID 'user123', получен '%s'", userData.UserID)
-				}
-				if userData.Label != "test-credential" {
-					t.Errorf("Ожидалась метка 'test-credential', получена '%s'", userData.Label)
+				return errors.New("ошибка при сохранении данных")
+			},
+		},
+		userRepo: &MockUserRepo{
+			FindUserFunc: func(login string) (*domain.User, error) {
+				return &domain.User{Id: "user123"}, nil
+			},
+		},
+	}
+	if err := dataService.SaveItem("testuser", domain.UserDataTypeText, "note", data, ""); err == nil {
+		t.Error("Ожидалась ошибка сохранения записи, но ее не было")
+	}
+}
+
+// TestDataService_GetItem тестирует получение записи встроенного типа
+func TestDataService_GetItem(t *testing.T) {
+	tests := []struct {
+		name     string
+		findUser func(login string) (*domain.User, error)
+		getData  func(userID, label string, dataType string) (*domain.UserData, error)
+		notFound bool
+		wantErr  bool
+	}{
+		{
+			name: "Success",
+			getData: func(userID, label string, dataType string) (*domain.UserData, error) {
+				if userID != "user123" || label != "test-credential" || dataType != domain.UserDataTypeCredential {
+					t.Errorf("Неожиданные аргументы: %s, %s, %s", userID, label, dataType)
 				}
-				if userData.Type != domain.UserDataTypeCredential {
-					t.Errorf("Ожидался тип '%s', получен '%s'", domain.UserDataTypeCredential, userData.Type)
+				return &domain.UserData{
+					ID:       "data123",
+					UserID:   userID,
+					Label:    label,
+					Type:     dataType,
+					Data:     []byte(`{"login":"service-login","password":"service-password"}`),
+					Metadata: "test metadata",
+				}, nil
+			},
+		},
+		{
+			name: "UserNotFound",
+			findUser: func(login string) (*domain.User, error) {
+				return nil, errors.New("пользователь не найден")
+			},
+			wantErr: true,
+		},
+		{
+			name: "GetDataError",
+			getData: func(userID, label string, dataType string) (*domain.UserData, error) {
+				return nil, errors.New("ошибка при получении данных")
+			},
+			wantErr: true,
+		},
+		{
+			name: "DataNotFound",
+			getData: func(userID, label string, dataType string) (*domain.UserData, error) {
+				return nil, nil
+			},
+			notFound: true,
+			wantErr:  true,
+		},
+		{
+			name: "UnmarshalError",
+			getData: func(userID, label string, dataType string) (*domain.UserData, error) {
+				return &domain.UserData{ID: "data123", Data: []byte("invalid json")}, nil
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			findUser := tt.findUser
+			if findUser == nil {
+				findUser = func(login string) (*domain.User, error) {
+					return &domain.User{Id: "user123", Credentials: domain.Credentials{Login: login}}, nil
 				}
+			}
+			dataService := &DataService{
+				repo:     &MockUserDataRepo{GetUserDataByLabelAndTypeFunc: tt.getData},
+				userRepo: &MockUserRepo{FindUserFunc: findUser},
+			}
 
-				// Проверяем данные
-				var credentialData domain.CredentialData
-				if err := json.Unmarshal(userData.Data, &credentialData); err != nil {
-					t.Fatalf("Ошибка при десериализации данных: %v", err)
+			data, metadata, err := dataService.GetItem("testuser", domain.UserDataTypeCredential, "test-credential")
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("Ожидалась ошибка, но ее не было")
 				}
-				if credentialData.Login != "service-login" {
-					t.Errorf("Ожидался логин 'service-login', получен '%s'", credentialData.Login)
-				}
-				if credentialData.Password != "service-password" {
-					t.Errorf("Ожидался пароль 'service-password', получен '%s'", credentialData.Password)
+				if errors.Is(err, domain.ErrNotFound) != tt.notFound {
+					t.Errorf("Неверный класс ошибки: %v", err)
 				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Ошибка при вызове GetItem: %v", err)
+			}
+			if data["login"] != "service-login" || data["password"] != "service-password" {
+				t.Errorf("Неверное содержимое записи: %v", data)
+			}
+			if metadata != "test metadata" {
+				t.Errorf("Ожидалась метаинформация 'test metadata', получена '%s'", metadata)
+			}
+		})
+	}
+}
+
+// TestDataService_DeleteItem тестирует удаление записи встроенного типа
+func TestDataService_DeleteItem(t *testing.T) {
+	found := func(userID, label string, dataType string) (*domain.UserData, error) {
+		return &domain.UserData{ID: "data123", UserID: userID, Label: label, Type: dataType}, nil
+	}
 
+	tests := []struct {
+		name       string
+		findUser   func(login string) (*domain.User, error)
+		getData    func(userID, label string, dataType string) (*domain.UserData, error)
+		deleteData func(id string) error
+		notFound   bool
+		wantErr    bool
+	}{
+		{
+			name:    "Success",
+			getData: found,
+			deleteData: func(id string) error {
+				if id != "data123" {
+					t.Errorf("Ожидался ID 'data123', получен '%s'", id)
+				}
 				return nil
 			},
-		}
+		},
+		{
+			name: "UserNotFound",
+			findUser: func(login string) (*domain.User, error) {
+				return nil, errors.New("пользователь не найден")
+			},
+			wantErr: true,
+		},
+		{
+			name: "GetDataError",
+			getData: func(userID, label string, dataType string) (*domain.UserData, error) {
+				return nil, errors.New("ошибка при получении данных")
+			},
+			wantErr: true,
+		},
+		{
+			name: "DataNotFound",
+			getData: func(userID, label string, dataType string) (*domain.UserData, error) {
+				return nil, domain.ErrNotFound
+			},
+			notFound: true,
+			wantErr:  true,
+		},
+		{
+			name:    "DeleteError",
+			getData: found,
+			deleteData: func(id string) error {
+				return errors.New("ошибка при удалении данных")
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			findUser := tt.findUser
+			if findUser == nil {
+				findUser = func(login string) (*domain.User, error) {
+					return &domain.User{Id: "user123", Credentials: domain.Credentials{Login: login}}, nil
+				}
+			}
+			dataService := &DataService{
+				repo: &MockUserDataRepo{
+					GetUserDataByLabelAndTypeFunc: tt.getData,
+					DeleteUserDataFunc:            tt.deleteData,
+				},
+				userRepo: &MockUserRepo{FindUserFunc: findUser},
+			}
 
-		// Создаем экземпляр DataService
-		dataService := &DataService{
-			repo:     mockUserDataRepo,
-			userRepo: mockUserRepo,
-		}
+			err := dataService.DeleteItem("testuser", domain.UserDataTypeCard, "test-card")
+			if !tt.wantErr {
+				if err != nil {
+					t.Fatalf("Ошибка при вызове DeleteItem: %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatal("Ожидалась ошибка, но ее не было")
+			}
+			if errors.Is(err, domain.ErrNotFound) != tt.notFound {
+				t.Errorf("Неверный класс ошибки: %v", err)
+			}
+		})
+	}
+}
 
-		// Вызываем метод SaveCredential
-		credentialData := &domain.CredentialData{
-			Login:    "service-login",
-			Password: "service-password",
-		}
-		err := dataService.SaveCredential("testuser", "test-credential", credentialData, "test metadata")
+// TestDataService_ListFiles тестирует метод ListFiles
+func TestDataService_ListFiles(t *testing.T) {
+	mockUserRepo := &MockUserRepo{
+		FindUserFunc: func(login string) (*domain.User, error) {
+			return &domain.User{Id: "user123"}, nil
+		},
+	}
 
-		// Проверяем результаты
-		if err != nil {
-			t.Fatalf("Ошибка при вызове SaveCredential: %v", err)
-		}
-	})
+	fileData, _ := json.Marshal(domain.FileMetadata{FileName: "test-file", Extension: "txt", Size: 42})
+	mockUserDataRepo := &MockUserDataRepo{
+		ListUserDataByTypeFunc: func(userID string, dataType string) ([]*domain.UserData, error) {
+			if userID != "user123" || dataType != domain.UserDataTypeFile {
+				t.Errorf("Неожиданные параметры: '%s', '%s'", userID, dataType)
+			}
+			return []*domain.UserData{
+				{ID: "1", Label: "test-file", Type: domain.UserDataTypeFile, Data: fileData, Metadata: "meta"},
+			}, nil
+		},
+	}
 
-	// Тест ошибки при поиске пользователя
-	t.Run("UserNotFound", func(t *testing.T) {
-		// Создаем моки для репозиториев
-		mockUserRepo := &MockUserRepo{
-			FindUserFunc: func(login string) (*domain.User, error) {
-				return nil, errors.New("пользователь не найден")
-			},
-		}
+	dataService := NewDataService(mockUserDataRepo, mockUserRepo, NewMockItemTemplateRepo(), &MockSettingsService{}, nil)
 
-		mockUserDataRepo := &MockUserDataRepo{}
+	files, err := dataService.ListFiles("testuser")
+	if err != nil {
+		t.Fatalf("Неожиданная ошибка: %v", err)
+	}
+	if len(files) != 1 {
+		t.Fatalf("Ожидался 1 файл, получено %d", len(files))
+	}
+	if files[0].Label != "test-file" || files[0].Extension != "txt" || files[0].Size != 42 || files[0].Metadata != "meta" {
+		t.Errorf("Неверные сведения о файле: %+v", files[0])
+	}
+}
 
-		// Создаем экземпляр DataService
-		dataService := &DataService{
-			repo:     mockUserDataRepo,
-			userRepo: mockUserRepo,
-		}
+// TestDataService_ListItems тестирует метод ListItems
+func TestDataService_ListItems(t *testing.T) {
+	mockUserRepo := &MockUserRepo{
+		FindUserFunc: func(login string) (*domain.User, error) {
+			return &domain.User{Id: "user123"}, nil
+		},
+	}
 
-		// Создаем данные для сохранения
-		credentialData := &domain.CredentialData{
-			Login:    "service-login",
-			Password: "service-password",
-		}
+	mockUserDataRepo := &MockUserDataRepo{
+		ListUserDataFunc: func(userID string) ([]*domain.UserData, error) {
+			if userID != "user123" {
+				t.Errorf("Неожиданный ID пользователя: '%s'", userID)
+			}
+			return []*domain.UserData{
+				{ID: "1", Label: "bank", Type: domain.UserDataTypeCredential, Metadata: "рабочий"},
+				{ID: "2", Label: "visa", Type: domain.UserDataTypeCard},
+			}, nil
+		},
+		ListUserDataByTypeFunc: func(userID string, dataType string) ([]*domain.UserData, error) {
+			if dataType != domain.UserDataTypeCredential {
+				t.Errorf("Неожиданный тип записей: '%s'", dataType)
+			}
+			return []*domain.UserData{
+				{ID: "1", Label: "bank", Type: domain.UserDataTypeCredential,
+					Data: json.RawMessage(`{"login":"u","password":"p","uris":"[{\"uri\":\"https://bank.example\",\"match\":\"host\"}]"}`)},
+			}, nil
+		},
+	}
 
-		// Вызываем метод SaveCredential
-		err := dataService.SaveCredential("testuser", "test-credential", credentialData, "test metadata")
+	dataService := NewDataService(mockUserDataRepo, mockUserRepo, NewMockItemTemplateRepo(), &MockSettingsService{}, nil)
 
-		// Проверяем результаты
-		if err == nil {
-			t.Fatal("Ожидалась ошибка, но ее не было")
-		}
-	})
+	items, err := dataService.ListItems("testuser")
+	if err != nil {
+		t.Fatalf("Неожиданная ошибка: %v", err)
+	}
+	if len(items) != 2 {
+		t.Fatalf("Ожидалось 2 записи, получено %d", len(items))
+	}
+	if items[0].Label != "bank" || items[0].Type != domain.UserDataTypeCredential || items[0].Metadata != "рабочий" {
+		t.Errorf("Неверные сведения о записи: %+v", items[0])
+	}
+	if len(items[0].URIs) != 1 || items[0].URIs[0] != (domain.CredentialURI{URI: "https://bank.example", Match: "host"}) || items[1].URIs != nil {
+		t.Errorf("Ожидались адреса только у учетных данных: %+v", items)
+	}
 
-	// Тест ошибки при сохранении данных
-	t.Run("SaveError", func(t *testing.T) {
-		// Создаем моки для репозиториев
-		mockUserRepo := &MockUserRepo{
-			FindUserFunc: func(login string) (*domain.User, error) {
-				return &domain.User{
-					Id: "user123",
-					Credentials: domain.Credentials{
-						Login:    "testuser",
-						PassHash: "hash",
-					},
-				}, nil
-			},
-		}
+	mockUserDataRepo.ListUserDataFunc = func(userID string) ([]*domain.UserData, error) {
+		return nil, errors.New("database error")
+	}
+	if _, err := dataService.ListItems("testuser"); err == nil {
+		t.Error("Ожидалась ошибка, но ее не было")
+	}
+}
 
-		mockUserDataRepo := &MockUserDataRepo{
-			SaveUserDataFunc: func(userData *domain.UserData) error {
-				return errors.New("ошибка при сохранении данных")
-			},
-		}
+// TestDataService_RenameFileMetadata тестирует метод RenameFileMetadata
+func TestDataService_RenameFileMetadata(t *testing.T) {
+	mockUserRepo := &MockUserRepo{
+		FindUserFunc: func(login string) (*domain.User, error) {
+			return &domain.User{Id: "user123"}, nil
+		},
+	}
 
-		// Создаем экземпляр DataService
-		dataService := &DataService{
-			repo:     mockUserDataRepo,
-			userRepo: mockUserRepo,
-		}
+	fileData, _ := json.Marshal(domain.FileMetadata{FileName: "old", Extension: "txt", Size: 42})
+	renamed := false
+	mockUserDataRepo := &MockUserDataRepo{
+		GetUserDataByLabelAndTypeFunc: func(userID, label string, dataType string) (*domain.UserData, error) {
+			return &domain.UserData{ID: "data123", Label: label, Type: dataType, Data: fileData}, nil
+		},
+		FindUserDataByLabelFunc: func(userID, label string) (*domain.UserData, error) {
+			if label == "taken" {
+				return &domain.UserData{ID: "other"}, nil
+			}
+			return nil, domain.ErrNotFound
+		},
+		RenameUserDataFunc: func(id string, label string, data json.RawMessage) error {
+			renamed = true
+			if id != "data123" || label != "new" {
+				t.Errorf("Неожиданные параметры: '%s', '%s'", id, label)
+			}
+			var fileMetadata domain.FileMetadata
+			json.Unmarshal(data, &fileMetadata)
+			if fileMetadata.FileName != "new" || fileMetadata.Size != 42 {
+				t.Errorf("Неверные метаданные после переименования: %+v", fileMetadata)
+			}
+			return nil
+		},
+	}
 
-		// Создаем данные для сохранения
-		credentialData := &domain.CredentialData{
-			Login:    "service-login",
-			Password: "service-password",
-		}
+	dataService := NewDataService(mockUserDataRepo, mockUserRepo, NewMockItemTemplateRepo(), &MockSettingsService{}, nil)
 
-		// Вызываем метод SaveCredential
-		err := dataService.SaveCredential("testuser", "test-credential", credentialData, "test metadata")
+	fileMetadata, err := dataService.RenameFileMetadata("testuser", "old", "new")
+	if err != nil {
+		t.Fatalf("Неожиданная ошибка: %v", err)
+	}
+	if !renamed {
+		t.Error("Ожидался вызов RenameUserData")
+	}
+	if fileMetadata.FileName != "old" {
+		t.Errorf("Ожидались метаданные до переименования, получено имя '%s'", fileMetadata.FileName)
+	}
 
-		// Проверяем результаты
-		if err == nil {
-			t.Fatal("Ожидалась ошибка, но ее не было")
-		}
-	})
+	// Новая метка занята другой записью
+	_, err = dataService.RenameFileMetadata("testuser", "old", "taken")
+	if !errors.Is(err, domain.ErrAlreadyExists) {
+		t.Errorf("Ожидалась ошибка занятой метки, получено: %v", err)
+	}
 }
 
-// TestDataService_GetCredential тестирует метод GetCredential
-func TestDataService_GetCredential(t *testing.T) {
-	// Тест успешного получения данных
-	t.Run("Success", func(t *testing.T) {
-		// Создаем моки для репозиториев
-		mockUserRepo := &MockUserRepo{
-			FindUserFunc: func(login string) (*domain.User, error) {
-				// Проверяем параметры
-				if login != "testuser" {
-					t.Errorf("Ожидался логин 'testuser', получен '%s'", login)
-				}
-				return &domain.User{
-					Id: "user123",
-					Credentials: domain.Credentials{
-						Login:    "testuser",
-						PassHash: "hash",
-					},
-				}, nil
-			},
-		}
-
-		// Создаем данные учетной записи
-		credentialData := domain.CredentialData{
-			Login:    "service-login",
-			Password: "service-password",
-		}
-		credentialDataJSON, _ := json.Marshal(credentialData)
-
-		mockUserDataRepo := &MockUserDataRepo{
-			GetUserDataByLabelAndTypeFunc: func(userID, label string, dataType string) (*domain.UserData, error) {
-				// Проверяем параметры
-				if userID != "user123" {
-					t.Errorf("Ожидался UserID 'user123', получен '%s'", userID)
-				}
-				if label != "test-credential" {
-					t.Errorf("Ожидалась метка 'test-credential', получена '%s'", label)
-				}
-				if dataType != domain.UserDataTypeCredential {
-					t.Errorf("Ожидался тип '%s', получен '%s'", domain.UserDataTypeCredential, dataType)
-				}
-
-				return &domain.UserData{
-					ID:        "data123",
-					UserID:    "user123",
-					Label:     "test-credential",
-					Type:      domain.UserDataTypeCredential,
-					Data:      credentialDataJSON,
-					Metadata:  "test metadata",
-					CreatedAt: time.Now(),
-					UpdatedAt: time.Now(),
-				}, nil
-			},
-		}
-
-		// Создаем экземпляр DataService
-		dataService := &DataService{
-			repo:     mockUserDataRepo,
-			userRepo: mockUserRepo,
-		}
-
-		// Вызываем метод GetCredential
-		result, metadata, err := dataService.GetCredential("testuser", "test-credential")
-
-		// Проверяем результаты
-		if err != nil {
-			t.Fatalf("Ошибка при вызове GetCredential: %v", err)
-		}
-		if result == nil {
-			t.Fatal("Результат не должен быть nil")
-		}
-		if result.Login != "service-login" {
-			t.Errorf("Ожидался логин 'service-login', получен '%s'", result.Login)
-		}
-		if result.Password != "service-password" {
-			t.Errorf("Ожидался пароль 'service-password', получен '%s'", result.Password)
-		}
-		if metadata != "test metadata" {
-			t.Errorf("Ожидались метаданные 'test metadata', получены '%s'", metadata)
-		}
-	})
-
-	// Тест ошибки при поиске пользователя
-	t.Run("UserNotFound", func(t *testing.T) {
-		// Создаем моки для репозиториев
-		mockUserRepo := &MockUserRepo{
-			FindUserFunc: func(login string) (*domain.User, error) {
-				return nil, errors.New("пользователь не найден")
-			},
-		}
-
-		mockUserDataRepo := &MockUserDataRepo{}
-
-		// Создаем экземпляр DataService
-		dataService := &DataService{
-			repo:     mockUserDataRepo,
-			userRepo: mockUserRepo,
-		}
-
-		// Вызываем метод GetCredential
-		_, _, err := dataService.GetCredential("testuser", "test-credential")
-
-		// Проверяем результаты
-		if err == nil {
-			t.Fatal("Ожидалась ошибка, но ее не было")
-		}
-	})
-
-	// Тест ошибки при получении данных
-	t.Run("GetDataError", func(t *testing.T) {
-		// Создаем моки для репозиториев
-		mockUserRepo := &MockUserRepo{
-			FindUserFunc: func(login string) (*domain.User, error) {
-				return &domain.User{
-					Id: "user123",
-					Credentials: domain.Credentials{
-						Login:    "testuser",
-						PassHash: "hash",
-					},
-				}, nil
-			},
-		}
-
-		mockUserDataRepo := &MockUserDataRepo{
-			GetUserDataByLabelAndTypeFunc: func(userID, label string, dataType string) (*domain.UserData, error) {
-				return nil, errors.New("ошибка при получении данных")
-			},
-		}
-
-		// Создаем экземпляр DataService
-		dataService := &DataService{
-			repo:     mockUserDataRepo,
-			userRepo: mockUserRepo,
-		}
-
-		// Вызываем метод GetCredential
-		_, _, err := dataService.GetCredential("testuser", "test-credential")
-
-		// Проверяем результаты
-		if err == nil {
-			t.Fatal("Ожидалась ошибка, но ее не было")
-		}
-	})
-
-	// Тест ошибки при отсутствии данных
-	t.Run("DataNotFound", func(t *testing.T) {
-		// Создаем моки для репозиториев
-		mockUserRepo := &MockUserRepo{
-			FindUserFunc: func(login string) (*domain.User, error) {
-				return &domain.User{
-					Id: "user123",
-					Credentials: domain.Credentials{
-						Login:    "testuser",
-						PassHash: "hash",
-					},
-				}, nil
-			},
-		}
-
-		mockUserDataRepo := &MockUserDataRepo{
-			GetUserDataByLabelAndTypeFunc: func(userID, label string, dataType string) (*domain.UserData, error) {
-				return nil, nil
-			},
-		}
-
-		// Создаем экземпляр DataService
-		dataService := &DataService{
-			repo:     mockUserDataRepo,
-			userRepo: mockUserRepo,
-		}
-
-		// Вызываем метод GetCredential
-		_, _, err := dataService.GetCredential("testuser", "test-credential")
-
-		// Проверяем результаты
-		if err == nil {
-			t.Fatal("Ожидалась ошибка, но ее не было")
-		}
-	})
-
-	// Тест ошибки при демаршалинге данных
-	t.Run("UnmarshalError", func(t *testing.T) {
-		// Создаем моки для репозиториев
-		mockUserRepo := &MockUserRepo{
-			FindUserFunc: func(login string) (*domain.User, error) {
-				return &domain.User{
-					Id: "user123",
-					Credentials: domain.Credentials{
-						Login:    "testuser",
-						PassHash: "hash",
-					},
-				}, nil
-			},
-		}
-
-		mockUserDataRepo := &MockUserDataRepo{
-			GetUserDataByLabelAndTypeFunc: func(userID, label string, dataType string) (*domain.UserData, error) {
-				return &domain.UserData{
-					ID:       "data123",
-					UserID:   "user123",
-					Label:    "test-credential",
-					Type:     domain.UserDataTypeCredential,
-					Data:     []byte("invalid json"),
-					Metadata: "test metadata",
-				}, nil
-			},
-		}
-
-		// Создаем экземпляр DataService
-		dataService := &DataService{
-			repo:     mockUserDataRepo,
-			userRepo: mockUserRepo,
-		}
-
-		// Вызываем метод GetCredential
-		_, _, err := dataService.GetCredential("testuser", "test-credential")
-
-		// Проверяем результаты
-		if err == nil {
-			t.Fatal("Ожидалась ошибка, но ее не было")
-		}
-	})
-}
-
-// TestDataService_DeleteCredential тестирует метод DeleteCredential
-func TestDataService_DeleteCredential(t *testing.T) {
-	// Тест успешного удаления
-	t.Run("Success", func(t *testing.T) {
-		// Создаем моки для репозиториев
-		mockUserRepo := &MockUserRepo{
-			FindUserFunc: func(login string) (*domain.User, error) {
-				// Проверяем параметры
-				if login != "testuser" {
-					t.Errorf("Ожидался логин 'testuser', получен '%s'", login)
-				}
-				return &domain.User{
-					Id: "user123",
-					Credentials: domain.Credentials{
-						Login:    "testuser",
-						PassHash: "hash",
-					},
-				}, nil
-			},
-		}
-
-		mockUserDataRepo := &MockUserDataRepo{
-			GetUserDataByLabelAndTypeFunc: func(userID, label string, dataType string) (*domain.UserData, error) {
-				// Проверяем параметры
-				if userID != "user123" {
-					t.Errorf("Ожидался UserID 'user123', получен '%s'", userID)
-				}
-				if label != "test-credential" {
-					t.Errorf("Ожидалась метка 'test-credential', получена '%s'", label)
-				}
-				if dataType != domain.UserDataTypeCredential {
-					t.Errorf("Ожидался тип '%s', получен '%s'", domain.UserDataTypeCredential, dataType)
-				}
-
-				return &domain.UserData{
-					ID:     "data123",
-					UserID: "user123",
-					Label:  "test-credential",
-					Type:   domain.UserDataTypeCredential,
-				}, nil
-			},
-			DeleteUserDataFunc: func(id string) error {
-				// Проверяем параметры
-				if id != "data123" {
-					t.Errorf("Ожидался ID 'data123', получен '%s'", id)
-				}
-				return nil
-			},
-		}
-
-		// Создаем экземпляр DataService
-		dataService := &DataService{
-			repo:     mockUserDataRepo,
-			userRepo: mockUserRepo,
-		}
-
-		// Вызываем метод DeleteCredential
-		err := dataService.DeleteCredential("testuser", "test-credential")
-
-		// Проверяем результаты
-		if err != nil {
-			t.Fatalf("Ошибка при вызове DeleteCredential: %v", err)
-		}
-	})
-
-	// Тест ошибки при поиске пользователя
-	t.Run("UserNotFound", func(t *testing.T) {
-		// Создаем моки для репозиториев
-		mockUserRepo := &MockUserRepo{
-			FindUserFunc: func(login string) (*domain.User, error) {
-				return nil, errors.New("пользователь не найден")
-			},
-		}
-
-		mockUserDataRepo := &MockUserDataRepo{}
-
-		// Создаем экземпляр DataService
-		dataService := &DataService{
-			repo:     mockUserDataRepo,
-			userRepo: mockUserRepo,
-		}
-
-		// Вызываем метод DeleteCredential
-		err := dataService.DeleteCredential("testuser", "test-credential")
-
-		// Проверяем результаты
-		if err == nil {
-			t.Fatal("Ожидалась ошибка, но ее не было")
-		}
-	})
-
-	// Тест ошибки при получении данных
-	t.Run("GetDataError", func(t *testing.T) {
-		// Создаем моки для репозиториев
-		mockUserRepo := &MockUserRepo{
-			FindUserFunc: func(login string) (*domain.User, error) {
-				return &domain.User{
-					Id: "user123",
-					Credentials: domain.Credentials{
-						Login:    "testuser",
-						PassHash: "hash",
-					},
-				}, nil
-			},
-		}
-
-		mockUserDataRepo := &MockUserDataRepo{
-			GetUserDataByLabelAndTypeFunc: func(userID, label string, dataType string) (*domain.UserData, error) {
-				return nil, errors.New("ошибка при получении данных")
-			},
-		}
-
-		// Создаем экземпляр DataService
-		dataService := &DataService{
-			repo:     mockUserDataRepo,
-			userRepo: mockUserRepo,
-		}
-
-		// Вызываем метод DeleteCredential
-		err := dataService.DeleteCredential("testuser", "test-credential")
-
-		// Проверяем результаты
-		if err == nil {
-			t.Fatal("Ожидалась ошибка, но ее не было")
-		}
-	})
-
-	// Тест ошибки при отсутствии данных
-	t.Run("DataNotFound", func(t *testing.T) {
-		// Создаем моки для репозиториев
-		mockUserRepo := &MockUserRepo{
-			FindUserFunc: func(login string) (*domain.User, error) {
-				return &domain.User{
-					Id: "user123",
-					Credentials: domain.Credentials{
-						Login:    "testuser",
-						PassHash: "hash",
-					},
-				}, nil
-			},
-		}
-
-		mockUserDataRepo := &MockUserDataRepo{
-			GetUserDataByLabelAndTypeFunc: func(userID, label string, dataType string) (*domain.UserData, error) {
-				return nil, nil
-			},
-		}
-
-		// Создаем экземпляр DataService
-		dataService := &DataService{
-			repo:     mockUserDataRepo,
-			userRepo: mockUserRepo,
-		}
-
-		// Вызываем метод DeleteCredential
-		err := dataService.DeleteCredential("testuser", "test-credential")
-
-		// Проверяем результаты
-		if err == nil {
-			t.Fatal("Ожидалась ошибка, но ее не было")
-		}
-	})
-
-	// Тест ошибки при удалении данных
-	t.Run("DeleteError", func(t *testing.T) {
-		// Создаем моки для репозиториев
-		mockUserRepo := &MockUserRepo{
-			FindUserFunc: func(login string) (*domain.User, error) {
-				return &domain.User{
-					Id: "user123",
-					Credentials: domain.Credentials{
-						Login:    "testuser",
-						PassHash: "hash",
-					},
-				}, nil
-			},
-		}
-
-		mockUserDataRepo := &MockUserDataRepo{
-			GetUserDataByLabelAndTypeFunc: func(userID, label string, dataType string) (*domain.UserData, error) {
-				return &domain.UserData{
-					ID:     "data123",
-					UserID: "user123",
-					Label:  "test-credential",
-					Type:   domain.UserDataTypeCredential,
-				}, nil
-			},
-			DeleteUserDataFunc: func(id string) error {
-				return errors.New("ошибка при удалении данных")
-			},
-		}
-
-		// Создаем экземпляр DataService
-		dataService := &DataService{
-			repo:     mockUserDataRepo,
-			userRepo: mockUserRepo,
-		}
-
-		// Вызываем метод DeleteCredential
-		err := dataService.DeleteCredential("testuser", "test-credential")
-
-		// Проверяем результаты
-		if err == nil {
-			t.Fatal("Ожидалась ошибка, но ее не было")
-		}
-	})
-}
-
-// TestDataService_SaveCard тестирует метод SaveCard
-func TestDataService_SaveCard(t *testing.T) {
-	// Создаем моки для репозиториев
-	mockUserRepo := &MockUserRepo{
-		FindUserFunc: func(login string) (*domain.User, error) {
-			// Проверяем параметры
-			if login != "testuser" {
-				t.Errorf("Ожидался логин 'testuser', получен '%s'", login)
-			}
-			return &domain.User{
-				Id: "user123",
-				Credentials: domain.Credentials{
-					Login:    "testuser",
-					PassHash: "hash",
-				},
-			}, nil
-		},
-	}
-
-	mockUserDataRepo := &MockUserDataRepo{
-		SaveUserDataFunc: func(userData *domain.UserData) error {
-			// Проверяем параметры
-			if userData.UserID != "user123" {
-				t.Errorf("Ожидался UserID 'user123', получен '%s'", userData.UserID)
-			}
-			if userData.Label != "test-card" {
-				t.Errorf("Ожидалась метка 'test-card', получена '%s'", userData.Label)
-			}
-			if userData.Type != domain.UserDataTypeCard {
-				t.Errorf("Ожидался тип '%s', получен '%s'", domain.UserDataTypeCard, userData.Type)
-			}
-
-			// Проверяем данные
-			var cardData domain.CardData
-			if err := json.Unmarshal(userData.Data, &cardData); err != nil {
-				t.Fatalf("Ошибка при десериализации данных: %v", err)
-			}
-			if cardData.Number != "1234567890123456" {
-				t.Errorf("Ожидался номер карты '1234567890123456', получен '%s'", cardData.Number)
-			}
-			if cardData.Holder != "Test User" {
-				t.Errorf("Ожидался держатель карты 'Test User', получен '%s'", cardData.Holder)
-			}
-			if cardData.ExpiryDate != "12/25" {
-				t.Errorf("Ожидался срок действия '12/25', получен '%s'", cardData.ExpiryDate)
-			}
-			if cardData.CVV != "123" {
-				t.Errorf("Ожидался CVV '123', получен '%s'", cardData.CVV)
-			}
-
-			return nil
-		},
-	}
-
-	// Создаем экземпляр DataService
-	dataService := &DataService{
-		repo:     mockUserDataRepo,
-		userRepo: mockUserRepo,
-	}
-
-	// Вызываем метод SaveCard
-	cardData := &domain.CardData{
-		Number:     "1234567890123456",
-		Holder:     "Test User",
-		ExpiryDate: "12/25",
-		CVV:        "123",
-	}
-	err := dataService.SaveCard("testuser", "test-card", cardData, "")
-
-	// Проверяем результаты
-	if err != nil {
-		t.Fatalf("Ошибка при вызове SaveCard: %v", err)
-	}
-}
-
-// TestDataService_GetCard тестирует метод GetCard
-func TestDataService_GetCard(t *testing.T) {
-	// Тест успешного получения данных карты
-	t.Run("Success", func(t *testing.T) {
-		// Создаем моки для репозиториев
-		mockUserRepo := &MockUserRepo{
-			FindUserFunc: func(login string) (*domain.User, error) {
-				// Проверяем параметры
-				if login != "testuser" {
-					t.Errorf("Ожидался логин 'testuser', получен '%s'", login)
-				}
-				return &domain.User{
-					Id: "user123",
-					Credentials: domain.Credentials{
-						Login:    "testuser",
-						PassHash: "hash",
-					},
-				}, nil
-			},
-		}
-
-		// Создаем данные карты
-		cardData := domain.CardData{
-			Number:     "1234567890123456",
-			Holder:     "Test User",
-			ExpiryDate: "12/25",
-			CVV:        "123",
-		}
-		cardDataJSON, _ := json.Marshal(cardData)
-
-		mockUserDataRepo := &MockUserDataRepo{
-			GetUserDataByLabelAndTypeFunc: func(userID, label string, dataType string) (*domain.UserData, error) {
-				// Проверяем параметры
-				if userID != "user123" {
-					t.Errorf("Ожидался UserID 'user123', получен '%s'", userID)
-				}
-				if label != "test-card" {
-					t.Errorf("Ожидалась метка 'test-card', получена '%s'", label)
-				}
-				if dataType != domain.UserDataTypeCard {
-					t.Errorf("Ожидался тип '%s', получен '%s'", domain.UserDataTypeCard, dataType)
-				}
-
-				return &domain.UserData{
-					ID:        "data123",
-					UserID:    "user123",
-					Label:     "test-card",
-					Type:      domain.UserDataTypeCard,
-					Data:      cardDataJSON,
-					CreatedAt: time.Now(),
-					UpdatedAt: time.Now(),
-					Metadata:  "test metadata",
-				}, nil
-			},
-		}
-
-		// Создаем экземпляр DataService
-		dataService := &DataService{
-			repo:     mockUserDataRepo,
-			userRepo: mockUserRepo,
-		}
-
-		// Вызываем метод GetCard
-		result, metadata, err := dataService.GetCard("testuser", "test-card")
-
-		// Проверяем результаты
-		if err != nil {
-			t.Fatalf("Ошибка при вызове GetCard: %v", err)
-		}
-		if result == nil {
-			t.Fatal("Результат не должен быть nil")
-		}
-		if result.Number != "1234567890123456" {
-			t.Errorf("Ожидался номер карты '1234567890123456', получен '%s'", result.Number)
-		}
-		if result.Holder != "Test User" {
-			t.Errorf("Ожидался держатель карты 'Test User', получен '%s'", result.Holder)
-		}
-		if result.ExpiryDate != "12/25" {
-			t.Errorf("Ожидался срок действия '12/25', получен '%s'", result.ExpiryDate)
-		}
-		if result.CVV != "123" {
-			t.Errorf("Ожидался CVV '123', получен '%s'", result.CVV)
-		}
-		if metadata != "test metadata" {
-			t.Errorf("Ожидались метаданные 'test metadata', получены '%s'", metadata)
-		}
-	})
-
-	// Тест ошибки при поиске пользователя
-	t.Run("UserNotFound", func(t *testing.T) {
-		// Создаем моки для репозиториев
-		mockUserRepo := &MockUserRepo{
-			FindUserFunc: func(login string) (*domain.User, error) {
-				return nil, errors.New("пользователь не найден")
-			},
-		}
-
-		mockUserDataRepo := &MockUserDataRepo{}
-
-		// Создаем экземпляр DataService
-		dataService := &DataService{
-			repo:     mockUserDataRepo,
-			userRepo: mockUserRepo,
-		}
-
-		// Вызываем метод GetCard
-		_, _, err := dataService.GetCard("testuser", "test-card")
-
-		// Проверяем результаты
-		if err == nil {
-			t.Fatal("Ожидалась ошибка, но ее не было")
-		}
-	})
-
-	// Тест ошибки при получении данных
-	t.Run("GetDataError", func(t *testing.T) {
-		// Создаем моки для репозиториев
-		mockUserRepo := &MockUserRepo{
-			FindUserFunc: func(login string) (*domain.User, error) {
-				return &domain.User{
-					Id: "user123",
-					Credentials: domain.Credentials{
-						Login:    "testuser",
-						PassHash: "hash",
-					},
-				}, nil
-			},
-		}
-
-		mockUserDataRepo := &MockUserDataRepo{
-			GetUserDataByLabelAndTypeFunc: func(userID, label string, dataType string) (*domain.UserData, error) {
-				return nil, errors.New("ошибка при получении данных")
-			},
-		}
-
-		// Создаем экземпляр DataService
-		dataService := &DataService{
-			repo:     mockUserDataRepo,
-			userRepo: mockUserRepo,
-		}
-
-		// Вызываем метод GetCard
-		_, _, err := dataService.GetCard("testuser", "test-card")
-
-		// Проверяем результаты
-		if err == nil {
-			t.Fatal("Ожидалась ошибка, но ее не было")
-		}
-	})
-
-	// Тест ошибки при отсутствии данных
-	t.Run("DataNotFound", func(t *testing.T) {
-		// Создаем моки для репозиториев
-		mockUserRepo := &MockUserRepo{
-			FindUserFunc: func(login string) (*domain.User, error) {
-				return &domain.User{
-					Id: "user123",
-					Credentials: domain.Credentials{
-						Login:    "testuser",
-						PassHash: "hash",
-					},
-				}, nil
-			},
-		}
-
-		mockUserDataRepo := &MockUserDataRepo{
-			GetUserDataByLabelAndTypeFunc: func(userID, label string, dataType string) (*domain.UserData, error) {
-				return nil, nil
-			},
-		}
-
-		// Создаем экземпляр DataService
-		dataService := &DataService{
-			repo:     mockUserDataRepo,
-			userRepo: mockUserRepo,
-		}
-
-		// Вызываем метод GetCard
-		_, _, err := dataService.GetCard("testuser", "test-card")
-
-		// Проверяем результаты
-		if err == nil {
-			t.Fatal("Ожидалась ошибка, но ее не было")
-		}
-	})
-
-	// Тест ошибки при десериализации данных
-	t.Run("UnmarshalError", func(t *testing.T) {
-		// Создаем моки для репозиториев
-		mockUserRepo := &MockUserRepo{
-			FindUserFunc: func(login string) (*domain.User, error) {
-				return &domain.User{
-					Id: "user123",
-					Credentials: domain.Credentials{
-						Login:    "testuser",
-						PassHash: "hash",
-					},
-				}, nil
-			},
-		}
-
-		mockUserDataRepo := &MockUserDataRepo{
-			GetUserDataByLabelAndTypeFunc: func(userID, label string, dataType string) (*domain.UserData, error) {
-				return &domain.UserData{
-					ID:     "data123",
-					UserID: "user123",
-					Label:  "test-card",
-					Type:   domain.UserDataTypeCard,
-					Data:   []byte("invalid json"), // Некорректный JSON для вызова ошибки десериализации
-				}, nil
-			},
-		}
-
-		// Создаем экземпляр DataService
-		dataService := &DataService{
-			repo:     mockUserDataRepo,
-			userRepo: mockUserRepo,
-		}
-
-		// Вызываем метод GetCard
-		_, _, err := dataService.GetCard("testuser", "test-card")
-
-		// Проверяем результаты
-		if err == nil {
-			t.Fatal("Ожидалась ошибка, но ее не было")
-		}
-	})
-}
-
-// TestDataService_DeleteCard тестирует метод DeleteCard
-func TestDataService_DeleteCard(t *testing.T) {
-	// Тест успешного удаления
-	t.Run("Success", func(t *testing.T) {
-		// Создаем моки для репозиториев
-		mockUserRepo := &MockUserRepo{
-			FindUserFunc: func(login string) (*domain.User, error) {
-				// Проверяем параметры
-				if login != "testuser" {
-					t.Errorf("Ожидался логин 'testuser', получен '%s'", login)
-				}
-				return &domain.User{
-					Id: "user123",
-					Credentials: domain.Credentials{
-						Login:    "testuser",
-						PassHash: "hash",
-					},
-				}, nil
-			},
-		}
-
-		mockUserDataRepo := &MockUserDataRepo{
-			GetUserDataByLabelAndTypeFunc: func(userID, label string, dataType string) (*domain.UserData, error) {
-				// Проверяем параметры
-				if userID != "user123" {
-					t.Errorf("Ожидался UserID 'user123', получен '%s'", userID)
-				}
-				if label != "test-card" {
-					t.Errorf("Ожидалась метка 'test-card', получена '%s'", label)
-				}
-				if dataType != domain.UserDataTypeCard {
-					t.Errorf("Ожидался тип '%s', получен '%s'", domain.UserDataTypeCard, dataType)
-				}
-
-				return &domain.UserData{
-					ID:     "data123",
-					UserID: "user123",
-					Label:  "test-card",
-					Type:   domain.UserDataTypeCard,
-				}, nil
-			},
-			DeleteUserDataFunc: func(id string) error {
-				// Проверяем параметры
-				if id != "data123" {
-					t.Errorf("Ожидался ID 'data123', получен '%s'", id)
-				}
-				return nil
-			},
-		}
-
-		// Создаем экземпляр DataService
-		dataService := &DataService{
-			repo:     mockUserDataRepo,
-			userRepo: mockUserRepo,
-		}
-
-		// Вызываем метод DeleteCard
-		err := dataService.DeleteCard("testuser", "test-card")
-
-		// Проверяем результаты
-		if err != nil {
-			t.Fatalf("Ошибка при вызове DeleteCard: %v", err)
-		}
-	})
-
-	// Тест ошибки при поиске пользователя
-	t.Run("UserNotFound", func(t *testing.T) {
-		// Создаем моки для репозиториев
-		mockUserRepo := &MockUserRepo{
-			FindUserFunc: func(login string) (*domain.User, error) {
-				return nil, errors.New("пользователь не найден")
-			},
-		}
-
-		mockUserDataRepo := &MockUserDataRepo{}
-
-		// Создаем экземпляр DataService
-		dataService := &DataService{
-			repo:     mockUserDataRepo,
-			userRepo: mockUserRepo,
-		}
-
-		// Вызываем метод DeleteCard
-		err := dataService.DeleteCard("testuser", "test-card")
-
-		// Проверяем результаты
-		if err == nil {
-			t.Fatal("Ожидалась ошибка, но ее не было")
-		}
-	})
-
-	// Тест ошибки при получении данных
-	t.Run("GetDataError", func(t *testing.T) {
-		// Создаем моки для репозиториев
-		mockUserRepo := &MockUserRepo{
-			FindUserFunc: func(login string) (*domain.User, error) {
-				return &domain.User{
-					Id: "user123",
-					Credentials: domain.Credentials{
-						Login:    "testuser",
-						PassHash: "hash",
-					},
-				}, nil
-			},
-		}
-
-		mockUserDataRepo := &MockUserDataRepo{
-			GetUserDataByLabelAndTypeFunc: func(userID, label string, dataType string) (*domain.UserData, error) {
-				return nil, errors.New("ошибка при получении данных")
-			},
-		}
-
-		// Создаем экземпляр DataService
-		dataService := &DataService{
-			repo:     mockUserDataRepo,
-			userRepo: mockUserRepo,
-		}
-
-		// Вызываем метод DeleteCard
-		err := dataService.DeleteCard("testuser", "test-card")
-
-		// Проверяем результаты
-		if err == nil {
-			t.Fatal("Ожидалась ошибка, но ее не было")
-		}
-	})
-
-	// Тест ошибки при отсутствии данных
-	t.Run("DataNotFound", func(t *testing.T) {
-		// Создаем моки для репозиториев
-		mockUserRepo := &MockUserRepo{
-			FindUserFunc: func(login string) (*domain.User, error) {
-				return &domain.User{
-					Id: "user123",
-					Credentials: domain.Credentials{
-						Login:    "testuser",
-						PassHash: "hash",
-					},
-				}, nil
-			},
-		}
-
-		mockUserDataRepo := &MockUserDataRepo{
-			GetUserDataByLabelAndTypeFunc: func(userID, label string, dataType string) (*domain.UserData, error) {
-				return nil, nil
-			},
-		}
-
-		// Создаем экземпляр DataService
-		dataService := &DataService{
-			repo:     mockUserDataRepo,
-			userRepo: mockUserRepo,
-		}
-
-		// Вызываем метод DeleteCard
-		err := dataService.DeleteCard("testuser", "test-card")
-
-		// Проверяем результаты
-		if err == nil {
-			t.Fatal("Ожидалась ошибка, но ее не было")
-		}
-	})
-
-	// Тест ошибки при удалении данных
-	t.Run("DeleteError", func(t *testing.T) {
-		// Создаем моки для репозиториев
-		mockUserRepo := &MockUserRepo{
-			FindUserFunc: func(login string) (*domain.User, error) {
-				return &domain.User{
-					Id: "user123",
-					Credentials: domain.Credentials{
-						Login:    "testuser",
-						PassHash: "hash",
-					},
-				}, nil
-			},
-		}
-
-		mockUserDataRepo := &MockUserDataRepo{
-			GetUserDataByLabelAndTypeFunc: func(userID, label string, dataType string) (*domain.UserData, error) {
-				return &domain.UserData{
-					ID:     "data123",
-					UserID: "user123",
-					Label:  "test-card",
-					Type:   domain.UserDataTypeCard,
-				}, nil
-			},
-			DeleteUserDataFunc: func(id string) error {
-				return errors.New("ошибка при удалении данных")
-			},
-		}
-
-		// Создаем экземпляр DataService
-		dataService := &DataService{
-			repo:     mockUserDataRepo,
-			userRepo: mockUserRepo,
-		}
-
-		// Вызываем метод DeleteCard
-		err := dataService.DeleteCard("testuser", "test-card")
-
-		// Проверяем результаты
-		if err == nil {
-			t.Fatal("Ожидалась ошибка, но ее не было")
-		}
-	})
-}
-
-// TestDataService_SaveText тестирует метод SaveText
-func TestDataService_SaveText(t *testing.T) {
-	// Создаем моки для репозиториев
-	mockUserRepo := &MockUserRepo{
-		FindUserFunc: func(login string) (*domain.User, error) {
-			// Проверяем параметры
-			if login != "testuser" {
-				t.Errorf("Ожидался логин 'testuser', получен '%s'", login)
-			}
-			return &domain.User{
-				Id: "user123",
-				Credentials: domain.Credentials{
-					Login:    "testuser",
-					PassHash: "hash",
-				},
-			}, nil
-		},
-	}
-
-	mockUserDataRepo := &MockUserDataRepo{
-		SaveUserDataFunc: func(userData *domain.UserData) error {
-			// Проверяем параметры
-			if userData.UserID != "user123" {
-				t.Errorf("Ожидался UserID 'user123', получен '%s'", userData.UserID)
-			}
-			if userData.Label != "test-text" {
-				t.Errorf("Ожидалась метка 'test-text', получена '%s'", userData.Label)
-			}
-			if userData.Type != domain.UserDataTypeText {
-				t.Errorf("Ожидался тип '%s', получен '%s'", domain.UserDataTypeText, userData.Type)
-			}
-
-			// Проверяем данные
-			var textData domain.TextData
-			if err := json.Unmarshal(userData.Data, &textData); err != nil {
-				t.Fatalf("Ошибка при десериализации данных: %v", err)
-			}
-			if textData.Content != "test text content" {
-				t.Errorf("Ожидался текст 'test text content', получен '%s'", textData.Content)
-			}
-
-			return nil
-		},
-	}
-
-	// Создаем экземпляр DataService
-	dataService := &DataService{
-		repo:     mockUserDataRepo,
-		userRepo: mockUserRepo,
-	}
-
-	// Вызываем метод SaveText
-	textData := &domain.TextData{
-		Content: "test text content",
-	}
-	err := dataService.SaveText("testuser", "test-text", textData, "")
-
-	// Проверяем результаты
-	if err != nil {
-		t.Fatalf("Ошибка при вызове SaveText: %v", err)
-	}
-}
-
-// TestDataService_GetText тестирует метод GetText
-func TestDataService_GetText(t *testing.T) {
-	// Создаем моки для репозиториев
-	mockUserRepo := &MockUserRepo{
-		FindUserFunc: func(login string) (*domain.User, error) {
-			// Проверяем параметры
-			if login != "testuser" {
-				t.Errorf("Ожидался логин 'testuser', получен '%s'", login)
-			}
-			return &domain.User{
-				Id: "user123",
-				Credentials: domain.Credentials{
-					Login:    "testuser",
-					PassHash: "hash",
-				},
-			}, nil
+// TestDataService_SaveItem тестирует сохранение записи структурированного типа и отказ от неверных полей и типов
+func TestDataService_SaveItem(t *testing.T) {
+	mockUserRepo := &MockUserRepo{
+		FindUserFunc: func(login string) (*domain.User, error) {
+			return &domain.User{Id: "user123", Credentials: domain.Credentials{Login: login}}, nil
 		},
 	}
 
-	// Создаем текстовые данные
-	textData := domain.TextData{
-		Content: "test text content",
-	}
-	textDataJSON, _ := json.Marshal(textData)
-
+	var saved *domain.UserData
 	mockUserDataRepo := &MockUserDataRepo{
-		GetUserDataByLabelAndTypeFunc: func(userID, label string, dataType string) (*domain.UserData, error) {
-			// Проверяем параметры
-			if userID != "user123" {
-				t.Errorf("Ожидался UserID 'user123', получен '%s'", userID)
-			}
-			if label != "test-text" {
-				t.Errorf("Ожидалась метка 'test-text', получена '%s'", label)
-			}
-			if dataType != domain.UserDataTypeText {
-				t.Errorf("Ожидался тип '%s', получен '%s'", domain.UserDataTypeText, dataType)
-			}
-
-			return &domain.UserData{
-				ID:        "data123",
-				UserID:    "user123",
-				Label:     "test-text",
-				Type:      domain.UserDataTypeText,
-				Data:      textDataJSON,
-				CreatedAt: time.Now(),
-				UpdatedAt: time.Now(),
-			}, nil
+		SaveUserDataFunc: func(userData *domain.UserData) error {
+			saved = userData
+			return nil
 		},
 	}
 
-	// Создаем экземпляр DataService
 	dataService := &DataService{
 		repo:     mockUserDataRepo,
 		userRepo: mockUserRepo,
 	}
 
-	// Вызываем метод GetText
-	result, _, err := dataService.GetText("testuser", "test-text")
+	data := domain.ItemData{"ssid": "home", "security": "WPA2", "password": "hunter22"}
+	if err := dataService.SaveItem("testuser", domain.UserDataTypeWiFi, "home", data, ""); err != nil {
+		t.Fatalf("Ошибка при вызове SaveItem: %v", err)
+	}
+	if saved == nil || saved.Type != domain.UserDataTypeWiFi || saved.Label != "home" {
+		t.Fatalf("Запись сохранена неверно: %+v", saved)
+	}
+	var savedData domain.ItemData
+	if err := json.Unmarshal(saved.Data, &savedData); err != nil || savedData["password"] != "hunter22" {
+		t.Errorf("Ожидались данные %v, получено %v, %v", data, savedData, err)
+	}
 
-	// Проверяем результаты
-	if err != nil {
-		t.Fatalf("Ошибка при вызове GetText: %v", err)
+	saved = nil
+	if err := dataService.SaveItem("testuser", domain.UserDataTypeWiFi, "cafe", domain.ItemData{"ssid": "cafe", "security": "WPA2"}, ""); !errors.Is(err, itemtype.ErrInvalidItem) {
+		t.Errorf("Ожидалась ошибка %v, получено %v", itemtype.ErrInvalidItem, err)
 	}
-	if result == nil {
-		t.Fatal("Результат не должен быть nil")
+	if err := dataService.SaveItem("testuser", "passport", "cafe", data, ""); !errors.Is(err, itemtype.ErrUnknownType) {
+		t.Errorf("Ожидалась ошибка %v, получено %v", itemtype.ErrUnknownType, err)
 	}
-	if result.Content != "test text content" {
-		t.Errorf("Ожидался текст 'test text content', получен '%s'", result.Content)
+	if saved != nil {
+		t.Error("Неверная запись не должна сохраняться")
 	}
 }
 
-// TestDataService_DeleteText тестирует метод DeleteText
-func TestDataService_DeleteText(t *testing.T) {
-	// Тест успешного удаления
-	t.Run("Success", func(t *testing.T) {
-		// Создаем моки для репозиториев
-		mockUserRepo := &MockUserRepo{
-			FindUserFunc: func(login string) (*domain.User, error) {
-				// Проверяем параметры
-				if login != "testuser" {
-					t.Errorf("Ожидался логин 'testuser', получен '%s'", login)
-				}
-				return &domain.User{
-					Id: "user123",
-					Credentials: domain.Credentials{
-						Login:    "testuser",
-						PassHash: "hash",
-					},
-				}, nil
-			},
-		}
-
-		mockUserDataRepo := &MockUserDataRepo{
-			GetUserDataByLabelAndTypeFunc: func(userID, label string, dataType string) (*domain.UserData, error) {
-				// Проверяем параметры
-				if userID != "user123" {
-					t.Errorf("Ожидался UserID 'user123', получен '%s'", userID)
-				}
-				if label != "test-text" {
-					t.Errorf("Ожидалась метка 'test-text', получена '%s'", label)
-				}
-				if dataType != domain.UserDataTypeText {
-					t.Errorf("Ожидался тип '%s', получен '%s'", domain.UserDataTypeText, dataType)
-				}
-
-				return &domain.UserData{
-					ID:     "data123",
-					UserID: "user123",
-					Label:  "test-text",
-					Type:   domain.UserDataTypeText,
-				}, nil
-			},
-			DeleteUserDataFunc: func(id string) error {
-				// Проверяем параметры
-				if id != "data123" {
-					t.Errorf("Ожидался ID 'data123', получен '%s'", id)
-				}
-				return nil
-			},
-		}
-
-		// Создаем экземпляр DataService
-		dataService := &DataService{
-			repo:     mockUserDataRepo,
-			userRepo: mockUserRepo,
-		}
-
-		// Вызываем метод DeleteText
-		err := dataService.DeleteText("testuser", "test-text")
+// TestDataService_GetItem_NotFound тестирует ошибку domain.ErrNotFound для отсутствующей записи
+func TestDataService_GetItem_NotFound(t *testing.T) {
+	mockUserRepo := &MockUserRepo{
+		FindUserFunc: func(login string) (*domain.User, error) {
+			return &domain.User{Id: "user123"}, nil
+		},
+	}
+	mockUserDataRepo := &MockUserDataRepo{
+		GetUserDataByLabelAndTypeFunc: func(userID, label string, dataType string) (*domain.UserData, error) {
+			if dataType != domain.UserDataTypeLicense {
+				t.Errorf("Ожидался тип %s, получен %s", domain.UserDataTypeLicense, dataType)
+			}
+			return nil, domain.ErrNotFound
+		},
+	}
 
-		// Проверяем результаты
-		if err != nil {
-			t.Fatalf("Ошибка при вызове DeleteText: %v", err)
-		}
-	})
+	dataService := NewDataService(mockUserDataRepo, mockUserRepo, NewMockItemTemplateRepo(), &MockSettingsService{}, nil)
 
-	// Тест ошибки при поиске пользователя
-	t.Run("UserNotFound", func(t *testing.T) {
-		// Создаем моки для репозиториев
-		mockUserRepo := &MockUserRepo{
-			FindUserFunc: func(login string) (*domain.User, error) {
-				return nil, errors.New("пользователь не найден")
-			},
-		}
+	if _, _, err := dataService.GetItem("testuser", domain.UserDataTypeLicense, "ide"); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("Ожидалась ошибка %v, получено %v", domain.ErrNotFound, err)
+	}
+}
 
-		mockUserDataRepo := &MockUserDataRepo{}
+// TestDataService_ItemTemplates тестирует шаблоны пользовательских типов и сохранение записей по ним
+func TestDataService_ItemTemplates(t *testing.T) {
+	mockUserRepo := &MockUserRepo{
+		FindUserFunc: func(login string) (*domain.User, error) {
+			return &domain.User{Id: "user-" + login}, nil
+		},
+	}
 
-		// Создаем экземпляр DataService
-		dataService := &DataService{
-			repo:     mockUserDataRepo,
-			userRepo: mockUserRepo,
-		}
+	var stored []*domain.UserData
+	mockUserDataRepo := &MockUserDataRepo{
+		SaveUserDataFunc: func(userData *domain.UserData) error {
+			stored = append(stored, userData)
+			return nil
+		},
+		ListUserDataByTypeFunc: func(userID string, dataType string) ([]*domain.UserData, error) {
+			result := make([]*domain.UserData, 0)
+			for _, userData := range stored {
+				if userData.UserID == userID && userData.Type == dataType {
+					result = append(result, userData)
+				}
+			}
+			return result, nil
+		},
+	}
 
-		// Вызываем метод DeleteText
-		err := dataService.DeleteText("testuser", "test-text")
+	dataService := NewDataService(mockUserDataRepo, mockUserRepo, NewMockItemTemplateRepo(), &MockSettingsService{}, nil)
 
-		// Проверяем результаты
-		if err == nil {
-			t.Fatal("Ожидалась ошибка, но ее не было")
-		}
-	})
+	schema := json.RawMessage(`{"type": "object", "properties": {"host": {"type": "string"}, ` +
+		`"password": {"type": "string", "writeOnly": true}}, "required": ["host"]}`)
+	if _, err := dataService.SaveItemTemplate("alice", "db_profile", json.RawMessage(`{"type": "array"}`)); !errors.Is(err, itemtype.ErrInvalidSchema) {
+		t.Errorf("Ожидалась ошибка %v, получено %v", itemtype.ErrInvalidSchema, err)
+	}
+	if _, err := dataService.SaveItemTemplate("alice", "db_profile", schema); err != nil {
+		t.Fatalf("Ошибка при вызове SaveItemTemplate: %v", err)
+	}
 
-	// Тест ошибки при получении данных
-	t.Run("GetDataError", func(t *testing.T) {
-		// Создаем моки для репозиториев
-		mockUserRepo := &MockUserRepo{
-			FindUserFunc: func(login string) (*domain.User, error) {
-				return &domain.User{
-					Id: "user123",
-					Credentials: domain.Credentials{
-						Login:    "testuser",
-						PassHash: "hash",
-					},
-				}, nil
-			},
-		}
+	// Тип описан шаблоном только у его владельца
+	dbProfile, err := dataService.ItemType("alice", "db_profile")
+	if err != nil || !dbProfile.Custom || dbProfile.Secret != "password" {
+		t.Fatalf("Неверное описание типа: %+v, %v", dbProfile, err)
+	}
+	if _, err := dataService.ItemType("bob", "db_profile"); !errors.Is(err, itemtype.ErrUnknownType) {
+		t.Errorf("Ожидалась ошибка %v, получено %v", itemtype.ErrUnknownType, err)
+	}
+	if err := dataService.SaveItem("bob", "db_profile", "prod", domain.ItemData{"host": "db.local"}, ""); !errors.Is(err, itemtype.ErrUnknownType) {
+		t.Errorf("Ожидалась ошибка %v, получено %v", itemtype.ErrUnknownType, err)
+	}
 
-		mockUserDataRepo := &MockUserDataRepo{
-			GetUserDataByLabelAndTypeFunc: func(userID, label string, dataType string) (*domain.UserData, error) {
-				return nil, errors.New("ошибка при получении данных")
-			},
-		}
+	// Записи проверяются по схеме шаблона
+	if err := dataService.SaveItem("alice", "db_profile", "prod", domain.ItemData{"password": "s3cret"}, ""); !errors.Is(err, itemtype.ErrInvalidItem) {
+		t.Errorf("Ожидалась ошибка %v, получено %v", itemtype.ErrInvalidItem, err)
+	}
+	if err := dataService.SaveItem("alice", "db_profile", "prod", domain.ItemData{"host": "db.local", "password": "s3cret"}, ""); err != nil {
+		t.Fatalf("Ошибка при вызове SaveItem: %v", err)
+	}
+	if len(stored) != 1 || stored[0].Type != "db_profile" {
+		t.Fatalf("Запись сохранена неверно: %+v", stored)
+	}
 
-		// Создаем экземпляр DataService
-		dataService := &DataService{
-			repo:     mockUserDataRepo,
-			userRepo: mockUserRepo,
-		}
+	templates, err := dataService.ListItemTemplates("alice")
+	if err != nil || len(templates) != 1 || templates[0].Name != "db_profile" {
+		t.Errorf("Неверный список шаблонов: %+v, %v", templates, err)
+	}
+	if _, err := dataService.GetItemTemplate("bob", "db_profile"); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("Ожидалась ошибка %v, получено %v", domain.ErrNotFound, err)
+	}
 
-		// Вызываем метод DeleteText
-		err := dataService.DeleteText("testuser", "test-text")
+	// Шаблон с записями не удаляется
+	if err := dataService.DeleteItemTemplate("alice", "db_profile"); !errors.Is(err, domain.ErrTemplateInUse) {
+		t.Errorf("Ожидалась ошибка %v, получено %v", domain.ErrTemplateInUse, err)
+	}
+	stored = nil
+	if err := dataService.DeleteItemTemplate("alice", "db_profile"); err != nil {
+		t.Fatalf("Ошибка при вызове DeleteItemTemplate: %v", err)
+	}
+	if err := dataService.DeleteItemTemplate("alice", "db_profile"); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("Ожидалась ошибка %v, получено %v", domain.ErrNotFound, err)
+	}
+}
 
-		// Проверяем результаты
-		if err == nil {
-			t.Fatal("Ожидалась ошибка, но ее не было")
-		}
-	})
+// TestDataService_PasswordHistory тестирует ведение истории паролей при сохранении учетных данных:
+// прежний пароль переносится в историю только при смене пароля, а история обрезается по настройкам пользователя
+func TestDataService_PasswordHistory(t *testing.T) {
+	mockUserRepo := &MockUserRepo{
+		FindUserFunc: func(login string) (*domain.User, error) {
+			return &domain.User{Id: "user123"}, nil
+		},
+	}
 
-	// Тест ошибки при отсутствии данных
-	t.Run("DataNotFound", func(t *testing.T) {
-		// Создаем моки для репозиториев
-		mockUserRepo := &MockUserRepo{
-			FindUserFunc: func(login string) (*domain.User, error) {
-				return &domain.User{
-					Id: "user123",
-					Credentials: domain.Credentials{
-						Login:    "testuser",
-						PassHash: "hash",
-					},
-				}, nil
-			},
-		}
+	stored := make(map[string]*domain.UserData)
+	mockUserDataRepo := &MockUserDataRepo{
+		SaveUserDataFunc: func(userData *domain.UserData) error {
+			stored[userData.Label] = userData
+			return nil
+		},
+		GetUserDataByLabelAndTypeFunc: func(userID, label string, dataType string) (*domain.UserData, error) {
+			userData, ok := stored[label]
+			if !ok || userData.Type != dataType {
+				return nil, domain.ErrNotFound
+			}
+			return userData, nil
+		},
+	}
 
-		mockUserDataRepo := &MockUserDataRepo{
-			GetUserDataByLabelAndTypeFunc: func(userID, label string, dataType string) (*domain.UserData, error) {
-				return nil, nil
-			},
-		}
+	retention := 2
+	settingsService := &MockSettingsService{
+		GetSettingsFunc: func(login string) (*domain.UserSettings, error) {
+			return &domain.UserSettings{PasswordHistory: retention}, nil
+		},
+	}
+	dataService := NewDataService(mockUserDataRepo, mockUserRepo, NewMockItemTemplateRepo(), settingsService, nil)
 
-		// Создаем экземпляр DataService
-		dataService := &DataService{
-			repo:     mockUserDataRepo,
-			userRepo: mockUserRepo,
+	history := func() []domain.PasswordChange {
+		var data domain.ItemData
+		if err := json.Unmarshal(stored["github"].Data, &data); err != nil {
+			t.Fatal(err)
 		}
-
-		// Вызываем метод DeleteText
-		err := dataService.DeleteText("testuser", "test-text")
-
-		// Проверяем результаты
-		if err == nil {
-			t.Fatal("Ожидалась ошибка, но ее не было")
+		changes, err := passwordHistory(data)
+		if err != nil {
+			t.Fatal(err)
 		}
-	})
-
-	// Тест ошибки при удалении данных
-	t.Run("DeleteError", func(t *testing.T) {
-		// Создаем моки для репозиториев
-		mockUserRepo := &MockUserRepo{
-			FindUserFunc: func(login string) (*domain.User, error) {
-				return &domain.User{
-					Id: "user123",
-					Credentials: domain.Credentials{
-						Login:    "testuser",
-						PassHash: "hash",
-					},
-				}, nil
-			},
+		return changes
+	}
+	save := func(password string) {
+		t.Helper()
+		if err := dataService.SaveItem("testuser", domain.UserDataTypeCredential, "github", domain.ItemData{"login": "alice", "password": password}, ""); err != nil {
+			t.Fatalf("Ошибка при вызове SaveItem: %v", err)
 		}
+	}
 
-		mockUserDataRepo := &MockUserDataRepo{
-			GetUserDataByLabelAndTypeFunc: func(userID, label string, dataType string) (*domain.UserData, error) {
-				return &domain.UserData{
-					ID:     "data123",
-					UserID: "user123",
-					Label:  "test-text",
-					Type:   domain.UserDataTypeText,
-				}, nil
-			},
-			DeleteUserDataFunc: func(id string) error {
-				return errors.New("ошибка при удалении данных")
-			},
-		}
+	save("first")
+	if changes := history(); len(changes) != 0 {
+		t.Fatalf("У новой записи не должно быть истории: %+v", changes)
+	}
 
-		// Создаем экземпляр DataService
-		dataService := &DataService{
-			repo:     mockUserDataRepo,
-			userRepo: mockUserRepo,
-		}
+	before := time.Now().UTC()
+	save("second")
+	save("second")
+	changes := history()
+	if len(changes) != 1 || changes[0].Password != "first" || changes[0].ChangedAt.Before(before) {
+		t.Fatalf("Ожидался один прежний пароль first, получено %+v", changes)
+	}
 
-		// Вызываем метод DeleteText
-		err := dataService.DeleteText("testuser", "test-text")
+	save("third")
+	save("fourth")
+	changes = history()
+	if len(changes) != 2 || changes[0].Password != "third" || changes[1].Password != "second" {
+		t.Errorf("Ожидалась история third, second, получено %+v", changes)
+	}
 
-		// Проверяем результаты
-		if err == nil {
-			t.Fatal("Ожидалась ошибка, но ее не было")
-		}
-	})
+	retention = 0
+	save("fifth")
+	if changes := history(); len(changes) != 0 {
+		t.Errorf("С нулевой длиной история не должна храниться: %+v", changes)
+	}
 }
@@ -0,0 +1,239 @@
+package service
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/SmirnovND/gophkeeper/internal/domain"
+	"github.com/SmirnovND/gophkeeper/internal/interfaces"
+)
+
+// Длина мастер-ключа и ключа данных пользователя, SSE-C требует AES-256
+const dataKeySize = 32
+
+// KeyService управляет ключами данных пользователей, обернутыми мастер-ключом сервера
+type KeyService struct {
+	repo     interfaces.UserKeyRepo
+	userRepo interfaces.UserRepo
+	// currentID - отпечаток текущего мастер-ключа, пустой при отключенном шифровании
+	currentID  string
+	masterKeys map[string]cipher.AEAD
+}
+
+// NewKeyService создает новый экземпляр KeyService.
+// Пустой masterKey отключает шифрование объектов, previousMasterKeys нужны для переупаковки ключей после смены мастер-ключа
+func NewKeyService(
+	repo interfaces.UserKeyRepo,
+	userRepo interfaces.UserRepo,
+	masterKey string,
+	previousMasterKeys []string,
+) (interfaces.KeyService, error) {
+	k := &KeyService{
+		repo:       repo,
+		userRepo:   userRepo,
+		masterKeys: make(map[string]cipher.AEAD),
+	}
+
+	if masterKey == "" {
+		return k, nil
+	}
+
+	for i, encoded := range append([]string{masterKey}, previousMasterKeys...) {
+		id, aead, err := parseMasterKey(encoded)
+		if err != nil {
+			return nil, err
+		}
+		if i == 0 {
+			k.currentID = id
+		}
+		k.masterKeys[id] = aead
+	}
+
+	return k, nil
+}
+
+// parseMasterKey разбирает мастер-ключ в base64 и возвращает его отпечаток и шифр для обертки ключей
+func parseMasterKey(encoded string) (string, cipher.AEAD, error) {
+	key, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", nil, fmt.Errorf("мастер-ключ должен быть в base64: %w", err)
+	}
+	if len(key) != dataKeySize {
+		return "", nil, fmt.Errorf("мастер-ключ должен быть длиной %d байт, получено %d", dataKeySize, len(key))
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return "", nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return "", nil, err
+	}
+
+	sum := sha256.Sum256(key)
+	return hex.EncodeToString(sum[:8]), aead, nil
+}
+
+// Enabled сообщает, включено ли шифрование объектов
+func (k *KeyService) Enabled() bool {
+	return k.currentID != ""
+}
+
+// CreateUserKey генерирует ключ данных для пользователя при регистрации
+func (k *KeyService) CreateUserKey(login string) error {
+	if !k.Enabled() {
+		return nil
+	}
+
+	user, err := k.userRepo.FindUser(login)
+	if err != nil {
+		return fmt.Errorf("ошибка при поиске пользователя: %w", err)
+	}
+
+	_, err = k.createUserKey(user.Id)
+	return err
+}
+
+// GetUserKey возвращает ключ данных пользователя для SSE-C.
+// При отключенном шифровании возвращает nil. Пользователям, зарегистрированным до включения шифрования,
+// ключ создается при первом обращении, а ключ, обернутый прежним мастер-ключом, переупаковывается текущим
+func (k *KeyService) GetUserKey(login string) ([]byte, error) {
+	if !k.Enabled() {
+		return nil, nil
+	}
+
+	user, err := k.userRepo.FindUser(login)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка при поиске пользователя: %w", err)
+	}
+
+	userKey, err := k.repo.GetUserKey(user.Id)
+	if errors.Is(err, domain.ErrNotFound) {
+		return k.createUserKey(user.Id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("ошибка при получении ключа пользователя: %w", err)
+	}
+
+	dataKey, err := k.unwrap(userKey)
+	if err != nil {
+		return nil, err
+	}
+
+	if userKey.MasterKeyID != k.currentID {
+		if err := k.rewrap(userKey, dataKey); err != nil {
+			return nil, err
+		}
+	}
+
+	return dataKey, nil
+}
+
+// RotateMasterKey переупаковывает текущим мастер-ключом все ключи пользователей, обернутые прежними мастер-ключами.
+// Ключи данных при этом не меняются, поэтому объекты в хранилище перезагружать не нужно.
+// Возвращает количество переупакованных ключей
+func (k *KeyService) RotateMasterKey() (int, error) {
+	if !k.Enabled() {
+		return 0, nil
+	}
+
+	userKeys, err := k.repo.ListUserKeysNotWrappedWith(k.currentID)
+	if err != nil {
+		return 0, fmt.Errorf("ошибка при получении ключей пользователей: %w", err)
+	}
+
+	rotated := 0
+	for _, userKey := range userKeys {
+		dataKey, err := k.unwrap(userKey)
+		if err != nil {
+			return rotated, err
+		}
+		if err := k.rewrap(userKey, dataKey); err != nil {
+			return rotated, err
+		}
+		rotated++
+	}
+
+	return rotated, nil
+}
+
+// createUserKey генерирует и сохраняет ключ данных пользователя.
+// Если ключ уже создан параллельным запросом, возвращается сохраненный ключ
+func (k *KeyService) createUserKey(userID string) ([]byte, error) {
+	dataKey := make([]byte, dataKeySize)
+	if _, err := rand.Read(dataKey); err != nil {
+		return nil, fmt.Errorf("ошибка при генерации ключа: %w", err)
+	}
+
+	userKey, err := k.wrap(userID, dataKey)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := k.repo.SaveUserKey(userKey); err != nil {
+		return nil, fmt.Errorf("ошибка при сохранении ключа пользователя: %w", err)
+	}
+
+	// Существующий ключ не перезаписывается, поэтому читаем ключ, который в итоге сохранен
+	saved, err := k.repo.GetUserKey(userID)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка при получении ключа пользователя: %w", err)
+	}
+
+	return k.unwrap(saved)
+}
+
+// rewrap заменяет обертку ключа пользователя на текущий мастер-ключ
+func (k *KeyService) rewrap(userKey *domain.UserKey, dataKey []byte) error {
+	rewrapped, err := k.wrap(userKey.UserID, dataKey)
+	if err != nil {
+		return err
+	}
+	if err := k.repo.UpdateUserKey(rewrapped); err != nil {
+		return fmt.Errorf("ошибка при обновлении ключа пользователя: %w", err)
+	}
+	return nil
+}
+
+// wrap шифрует ключ данных текущим мастер-ключом.
+// Идентификатор пользователя используется как дополнительные данные, чтобы обертку нельзя было перенести на другого пользователя
+func (k *KeyService) wrap(userID string, dataKey []byte) (*domain.UserKey, error) {
+	aead := k.masterKeys[k.currentID]
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("ошибка при генерации nonce: %w", err)
+	}
+
+	return &domain.UserKey{
+		UserID:      userID,
+		WrappedKey:  aead.Seal(nonce, nonce, dataKey, []byte(userID)),
+		MasterKeyID: k.currentID,
+	}, nil
+}
+
+// unwrap расшифровывает ключ данных мастер-ключом, которым он был обернут
+func (k *KeyService) unwrap(userKey *domain.UserKey) ([]byte, error) {
+	aead, ok := k.masterKeys[userKey.MasterKeyID]
+	if !ok {
+		return nil, fmt.Errorf("%w: ключ пользователя %s обернут мастер-ключом %s", domain.ErrUnknownMasterKey, userKey.UserID, userKey.MasterKeyID)
+	}
+
+	if len(userKey.WrappedKey) < aead.NonceSize() {
+		return nil, fmt.Errorf("поврежден ключ пользователя %s", userKey.UserID)
+	}
+	nonce, sealed := userKey.WrappedKey[:aead.NonceSize()], userKey.WrappedKey[aead.NonceSize():]
+
+	dataKey, err := aead.Open(nil, nonce, sealed, []byte(userKey.UserID))
+	if err != nil {
+		return nil, fmt.Errorf("ошибка при расшифровке ключа пользователя %s: %w", userKey.UserID, err)
+	}
+
+	return dataKey, nil
+}
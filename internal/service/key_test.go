@@ -0,0 +1,191 @@
+package service
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"github.com/SmirnovND/gophkeeper/internal/domain"
+	"testing"
+)
+
+// testMasterKey возвращает мастер-ключ в base64, заполненный байтом b
+func testMasterKey(b byte) string {
+	return base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{b}, dataKeySize))
+}
+
+// newTestKeyService создает KeyService с пользователями, идентификатор которых совпадает с логином
+func newTestKeyService(t *testing.T, repo *MockUserKeyRepo, masterKey string, previous ...string) *KeyService {
+	userRepo := &MockUserRepo{
+		FindUserFunc: func(login string) (*domain.User, error) {
+			return &domain.User{Id: login}, nil
+		},
+	}
+
+	keyService, err := NewKeyService(repo, userRepo, masterKey, previous)
+	if err != nil {
+		t.Fatalf("Неожиданная ошибка: %v", err)
+	}
+	return keyService.(*KeyService)
+}
+
+// TestNewKeyService_InvalidMasterKey тестирует отказ при некорректном мастер-ключе
+func TestNewKeyService_InvalidMasterKey(t *testing.T) {
+	for _, masterKey := range []string{"not base64!", base64.StdEncoding.EncodeToString([]byte("short"))} {
+		if _, err := NewKeyService(NewMockUserKeyRepo(), &MockUserRepo{}, masterKey, nil); err == nil {
+			t.Errorf("Ожидалась ошибка для мастер-ключа '%s'", masterKey)
+		}
+	}
+}
+
+// TestKeyService_Disabled тестирует работу без мастер-ключа
+func TestKeyService_Disabled(t *testing.T) {
+	repo := NewMockUserKeyRepo()
+	keyService := newTestKeyService(t, repo, "")
+
+	if keyService.Enabled() {
+		t.Error("Шифрование не должно быть включено без мастер-ключа")
+	}
+	if err := keyService.CreateUserKey("user1"); err != nil {
+		t.Fatalf("Неожиданная ошибка: %v", err)
+	}
+	key, err := keyService.GetUserKey("user1")
+	if err != nil || key != nil {
+		t.Errorf("Ожидалось отсутствие ключа, получено %v, %v", key, err)
+	}
+	if len(repo.keys) != 0 {
+		t.Error("Ключи не должны сохраняться при отключенном шифровании")
+	}
+}
+
+// TestKeyService_UserKey тестирует создание и получение ключа пользователя
+func TestKeyService_UserKey(t *testing.T) {
+	repo := NewMockUserKeyRepo()
+	keyService := newTestKeyService(t, repo, testMasterKey(1))
+
+	if err := keyService.CreateUserKey("user1"); err != nil {
+		t.Fatalf("Неожиданная ошибка: %v", err)
+	}
+
+	key, err := keyService.GetUserKey("user1")
+	if err != nil {
+		t.Fatalf("Неожиданная ошибка: %v", err)
+	}
+	if len(key) != dataKeySize {
+		t.Errorf("Ожидался ключ длиной %d байт, получено %d", dataKeySize, len(key))
+	}
+
+	// Ключ хранится только в обернутом виде
+	if bytes.Contains(repo.keys["user1"].WrappedKey, key) {
+		t.Error("Ключ пользователя не должен храниться в открытом виде")
+	}
+
+	// Повторное создание не меняет ключ, иначе зашифрованные объекты станут недоступны
+	if err := keyService.CreateUserKey("user1"); err != nil {
+		t.Fatalf("Неожиданная ошибка: %v", err)
+	}
+	again, _ := keyService.GetUserKey("user1")
+	if !bytes.Equal(key, again) {
+		t.Error("Ключ пользователя не должен меняться")
+	}
+
+	// Пользователю без ключа ключ создается при первом обращении
+	lazy, err := keyService.GetUserKey("user2")
+	if err != nil || len(lazy) != dataKeySize {
+		t.Fatalf("Ожидалось создание ключа при первом обращении, получено %v, %v", lazy, err)
+	}
+	if bytes.Equal(key, lazy) {
+		t.Error("Ключи разных пользователей должны различаться")
+	}
+}
+
+// TestKeyService_WrappedKeyBoundToUser тестирует, что обертку нельзя перенести на другого пользователя
+func TestKeyService_WrappedKeyBoundToUser(t *testing.T) {
+	repo := NewMockUserKeyRepo()
+	keyService := newTestKeyService(t, repo, testMasterKey(1))
+
+	if _, err := keyService.GetUserKey("victim"); err != nil {
+		t.Fatalf("Неожиданная ошибка: %v", err)
+	}
+
+	stolen := *repo.keys["victim"]
+	stolen.UserID = "attacker"
+	repo.keys["attacker"] = &stolen
+
+	if _, err := keyService.GetUserKey("attacker"); err == nil {
+		t.Error("Ожидалась ошибка при расшифровке чужой обертки ключа")
+	}
+}
+
+// TestKeyService_RotateMasterKey тестирует переупаковку ключей после смены мастер-ключа
+func TestKeyService_RotateMasterKey(t *testing.T) {
+	repo := NewMockUserKeyRepo()
+	oldService := newTestKeyService(t, repo, testMasterKey(1))
+
+	key1, _ := oldService.GetUserKey("user1")
+	key2, _ := oldService.GetUserKey("user2")
+	oldID := oldService.currentID
+
+	// Новый мастер-ключ, прежний указан в previous_master_keys
+	newService := newTestKeyService(t, repo, testMasterKey(2), testMasterKey(1))
+
+	rotated, err := newService.RotateMasterKey()
+	if err != nil {
+		t.Fatalf("Неожиданная ошибка: %v", err)
+	}
+	if rotated != 2 {
+		t.Errorf("Ожидалась переупаковка 2 ключей, получено %d", rotated)
+	}
+	for _, userKey := range repo.keys {
+		if userKey.MasterKeyID == oldID {
+			t.Error("Ключ пользователя остался обернут прежним мастер-ключом")
+		}
+	}
+
+	// Ключи данных не меняются, поэтому объекты в хранилище остаются доступны
+	after1, _ := newService.GetUserKey("user1")
+	after2, _ := newService.GetUserKey("user2")
+	if !bytes.Equal(key1, after1) || !bytes.Equal(key2, after2) {
+		t.Error("Ключи данных не должны меняться при смене мастер-ключа")
+	}
+
+	// Повторная переупаковка ничего не делает
+	if rotated, _ := newService.RotateMasterKey(); rotated != 0 {
+		t.Errorf("Ожидалось 0 переупакованных ключей, получено %d", rotated)
+	}
+
+	// После удаления прежнего мастер-ключа из конфигурации ключи остаются доступны
+	onlyNew := newTestKeyService(t, repo, testMasterKey(2))
+	if after, err := onlyNew.GetUserKey("user1"); err != nil || !bytes.Equal(key1, after) {
+		t.Errorf("Ожидался прежний ключ данных, получено %v", err)
+	}
+}
+
+// TestKeyService_LazyRewrap тестирует переупаковку ключа при обращении к нему
+func TestKeyService_LazyRewrap(t *testing.T) {
+	repo := NewMockUserKeyRepo()
+	key, _ := newTestKeyService(t, repo, testMasterKey(1)).GetUserKey("user1")
+
+	newService := newTestKeyService(t, repo, testMasterKey(2), testMasterKey(1))
+	after, err := newService.GetUserKey("user1")
+	if err != nil || !bytes.Equal(key, after) {
+		t.Fatalf("Ожидался прежний ключ данных, получено %v", err)
+	}
+	if repo.keys["user1"].MasterKeyID != newService.currentID {
+		t.Error("Ожидалась переупаковка ключа текущим мастер-ключом")
+	}
+}
+
+// TestKeyService_UnknownMasterKey тестирует ошибку при отсутствии мастер-ключа, которым обернут ключ пользователя
+func TestKeyService_UnknownMasterKey(t *testing.T) {
+	repo := NewMockUserKeyRepo()
+	newTestKeyService(t, repo, testMasterKey(1)).GetUserKey("user1")
+
+	keyService := newTestKeyService(t, repo, testMasterKey(2))
+
+	if _, err := keyService.GetUserKey("user1"); !errors.Is(err, domain.ErrUnknownMasterKey) {
+		t.Errorf("Ожидалась ошибка неизвестного мастер-ключа, получена %v", err)
+	}
+	if _, err := keyService.RotateMasterKey(); !errors.Is(err, domain.ErrUnknownMasterKey) {
+		t.Errorf("Ожидалась ошибка неизвестного мастер-ключа, получена %v", err)
+	}
+}
@@ -1,8 +1,11 @@
 package service
 
 import (
+	"encoding/json"
 	"github.com/SmirnovND/gophkeeper/internal/domain"
 	"net/http"
+	"sort"
+	"strings"
 )
 
 // MockUserRepo - мок для интерфейса UserRepo
@@ -58,9 +61,13 @@ func (m *MockAuthService) SetResponseAuthData(w http.ResponseWriter, token strin
 // MockUserDataRepo - мок для интерфейса UserDataRepo
 type MockUserDataRepo struct {
 	SaveUserDataFunc              func(userData *domain.UserData) error
+	SaveUserDataCheckedFunc       func(userData *domain.UserData, check func(usage *domain.Usage, existing *domain.UserData) error) error
 	FindUserDataByLabelFunc       func(userID, label string) (*domain.UserData, error)
 	GetUserDataByLabelAndTypeFunc func(userID, label string, dataType string) (*domain.UserData, error)
 	DeleteUserDataFunc            func(id string) error
+	ListUserDataByTypeFunc        func(userID string, dataType string) ([]*domain.UserData, error)
+	RenameUserDataFunc            func(id string, label string, data json.RawMessage) error
+	ListUserDataFunc              func(userID string) ([]*domain.UserData, error)
 }
 
 // SaveUserData - реализация метода SaveUserData для мока
@@ -68,6 +75,11 @@ func (m *MockUserDataRepo) SaveUserData(userData *domain.UserData) error {
 	return m.SaveUserDataFunc(userData)
 }
 
+// SaveUserDataChecked - реализация метода SaveUserDataChecked для мока
+func (m *MockUserDataRepo) SaveUserDataChecked(userData *domain.UserData, check func(usage *domain.Usage, existing *domain.UserData) error) error {
+	return m.SaveUserDataCheckedFunc(userData, check)
+}
+
 // FindUserDataByLabel - реализация метода FindUserDataByLabel для мока
 func (m *MockUserDataRepo) FindUserDataByLabel(userID, label string) (*domain.UserData, error) {
 	return m.FindUserDataByLabelFunc(userID, label)
@@ -81,4 +93,175 @@ func (m *MockUserDataRepo) GetUserDataByLabelAndType(userID, label string, dataT
 // DeleteUserData - реализация метода DeleteUserData для мока
 func (m *MockUserDataRepo) DeleteUserData(id string) error {
 	return m.DeleteUserDataFunc(id)
-}
\ No newline at end of file
+}
+
+// ListUserDataByType - реализация метода ListUserDataByType для мока
+func (m *MockUserDataRepo) ListUserDataByType(userID string, dataType string) ([]*domain.UserData, error) {
+	return m.ListUserDataByTypeFunc(userID, dataType)
+}
+
+// RenameUserData - реализация метода RenameUserData для мока
+func (m *MockUserDataRepo) RenameUserData(id string, label string, data json.RawMessage) error {
+	return m.RenameUserDataFunc(id, label, data)
+}
+
+// ListUserData - реализация метода ListUserData для мока
+func (m *MockUserDataRepo) ListUserData(userID string) ([]*domain.UserData, error) {
+	return m.ListUserDataFunc(userID)
+}
+// MockQuotaRepo - мок для интерфейса QuotaRepo
+type MockQuotaRepo struct {
+	GetUserQuotaFunc func(userID string, defaults domain.Quota) (*domain.Quota, error)
+	GetUsageFunc     func(userID string) (*domain.Usage, error)
+}
+
+// GetUserQuota - реализация метода GetUserQuota для мока
+func (m *MockQuotaRepo) GetUserQuota(userID string, defaults domain.Quota) (*domain.Quota, error) {
+	return m.GetUserQuotaFunc(userID, defaults)
+}
+
+// GetUsage - реализация метода GetUsage для мока
+func (m *MockQuotaRepo) GetUsage(userID string) (*domain.Usage, error) {
+	return m.GetUsageFunc(userID)
+}
+
+// MockUserKeyRepo - мок для интерфейса UserKeyRepo, хранящий ключи в памяти
+type MockUserKeyRepo struct {
+	keys map[string]*domain.UserKey
+}
+
+// NewMockUserKeyRepo создает пустое хранилище ключей
+func NewMockUserKeyRepo() *MockUserKeyRepo {
+	return &MockUserKeyRepo{keys: make(map[string]*domain.UserKey)}
+}
+
+// GetUserKey - реализация метода GetUserKey для мока
+func (m *MockUserKeyRepo) GetUserKey(userID string) (*domain.UserKey, error) {
+	userKey, ok := m.keys[userID]
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+	saved := *userKey
+	return &saved, nil
+}
+
+// SaveUserKey - реализация метода SaveUserKey для мока
+func (m *MockUserKeyRepo) SaveUserKey(userKey *domain.UserKey) error {
+	if _, ok := m.keys[userKey.UserID]; !ok {
+		saved := *userKey
+		m.keys[userKey.UserID] = &saved
+	}
+	return nil
+}
+
+// UpdateUserKey - реализация метода UpdateUserKey для мока
+func (m *MockUserKeyRepo) UpdateUserKey(userKey *domain.UserKey) error {
+	if _, ok := m.keys[userKey.UserID]; !ok {
+		return domain.ErrNotFound
+	}
+	saved := *userKey
+	m.keys[userKey.UserID] = &saved
+	return nil
+}
+
+// ListUserKeysNotWrappedWith - реализация метода ListUserKeysNotWrappedWith для мока
+func (m *MockUserKeyRepo) ListUserKeysNotWrappedWith(masterKeyID string) ([]*domain.UserKey, error) {
+	result := make([]*domain.UserKey, 0)
+	for _, userKey := range m.keys {
+		if userKey.MasterKeyID != masterKeyID {
+			saved := *userKey
+			result = append(result, &saved)
+		}
+	}
+	return result, nil
+}
+
+// MockItemTemplateRepo - мок для интерфейса ItemTemplateRepo, хранящий шаблоны в памяти
+type MockItemTemplateRepo struct {
+	templates map[string]*domain.ItemTemplate
+}
+
+// NewMockItemTemplateRepo создает пустое хранилище шаблонов
+func NewMockItemTemplateRepo() *MockItemTemplateRepo {
+	return &MockItemTemplateRepo{templates: make(map[string]*domain.ItemTemplate)}
+}
+
+// SaveItemTemplate - реализация метода SaveItemTemplate для мока
+func (m *MockItemTemplateRepo) SaveItemTemplate(userID string, template *domain.ItemTemplate) error {
+	saved := *template
+	m.templates[userID+"/"+template.Name] = &saved
+	return nil
+}
+
+// GetItemTemplate - реализация метода GetItemTemplate для мока
+func (m *MockItemTemplateRepo) GetItemTemplate(userID string, name string) (*domain.ItemTemplate, error) {
+	template, ok := m.templates[userID+"/"+name]
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+	saved := *template
+	return &saved, nil
+}
+
+// ListItemTemplates - реализация метода ListItemTemplates для мока
+func (m *MockItemTemplateRepo) ListItemTemplates(userID string) ([]*domain.ItemTemplate, error) {
+	result := make([]*domain.ItemTemplate, 0)
+	for key, template := range m.templates {
+		if strings.HasPrefix(key, userID+"/") {
+			saved := *template
+			result = append(result, &saved)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result, nil
+}
+
+// DeleteItemTemplate - реализация метода DeleteItemTemplate для мока
+func (m *MockItemTemplateRepo) DeleteItemTemplate(userID string, name string) error {
+	if _, ok := m.templates[userID+"/"+name]; !ok {
+		return domain.ErrNotFound
+	}
+	delete(m.templates, userID+"/"+name)
+	return nil
+}
+
+// MockSettingsRepo - мок для интерфейса SettingsRepo, хранящий настройки в памяти
+type MockSettingsRepo struct {
+	settings map[string]domain.UserSettings
+}
+
+// NewMockSettingsRepo создает пустое хранилище настроек
+func NewMockSettingsRepo() *MockSettingsRepo {
+	return &MockSettingsRepo{settings: make(map[string]domain.UserSettings)}
+}
+
+// GetUserSettings - реализация метода GetUserSettings для мока
+func (m *MockSettingsRepo) GetUserSettings(userID string, defaults domain.UserSettings) (*domain.UserSettings, error) {
+	settings, ok := m.settings[userID]
+	if !ok {
+		return &defaults, nil
+	}
+	return &settings, nil
+}
+
+// SaveUserSettings - реализация метода SaveUserSettings для мока
+func (m *MockSettingsRepo) SaveUserSettings(userID string, settings *domain.UserSettings) error {
+	m.settings[userID] = *settings
+	return nil
+}
+
+// MockSettingsService - мок для интерфейса SettingsService
+type MockSettingsService struct {
+	GetSettingsFunc  func(login string) (*domain.UserSettings, error)
+	SaveSettingsFunc func(login string, settings *domain.UserSettings) error
+}
+
+// GetSettings - реализация метода GetSettings для мока
+func (m *MockSettingsService) GetSettings(login string) (*domain.UserSettings, error) {
+	return m.GetSettingsFunc(login)
+}
+
+// SaveSettings - реализация метода SaveSettings для мока
+func (m *MockSettingsService) SaveSettings(login string, settings *domain.UserSettings) error {
+	return m.SaveSettingsFunc(login, settings)
+}
@@ -0,0 +1,109 @@
+package service
+
+import (
+	"fmt"
+	"github.com/SmirnovND/gophkeeper/internal/domain"
+	"github.com/SmirnovND/gophkeeper/internal/interfaces"
+)
+
+// QuotaService реализует интерфейс для проверки квот пользователя
+type QuotaService struct {
+	repo     interfaces.QuotaRepo
+	dataRepo interfaces.UserDataRepo
+	userRepo interfaces.UserRepo
+	defaults domain.Quota
+}
+
+// NewQuotaService создает новый экземпляр QuotaService
+func NewQuotaService(
+	repo interfaces.QuotaRepo,
+	dataRepo interfaces.UserDataRepo,
+	userRepo interfaces.UserRepo,
+	defaults domain.Quota,
+) interfaces.QuotaService {
+	return &QuotaService{
+		repo:     repo,
+		dataRepo: dataRepo,
+		userRepo: userRepo,
+		defaults: defaults,
+	}
+}
+
+// GetUsage возвращает текущее потребление хранилища вместе с действующей квотой
+func (q *QuotaService) GetUsage(login string) (*domain.Usage, error) {
+	user, err := q.userRepo.FindUser(login)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка при поиске пользователя: %w", err)
+	}
+
+	return q.getUsage(user.Id)
+}
+
+// CheckFileUpload проверяет, что загрузка файла размером size под меткой label не превысит квоту.
+// Проверка выполняется до выдачи ссылки, окончательно квота проверяется при сохранении метаданных файла
+func (q *QuotaService) CheckFileUpload(login string, label string, size int64) error {
+	return q.checkSave(login, label, size)
+}
+
+// CheckItemSave проверяет, что сохранение записи под меткой label не превысит квоту на количество записей.
+// Окончательно квота проверяется при сохранении записи
+func (q *QuotaService) CheckItemSave(login string, label string) error {
+	return q.checkSave(login, label, 0)
+}
+
+// GetUserQuota возвращает действующую квоту пользователя по его ID
+func (q *QuotaService) GetUserQuota(userID string) (*domain.Quota, error) {
+	quota, err := q.repo.GetUserQuota(userID, q.defaults)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка при получении квоты: %w", err)
+	}
+	return quota, nil
+}
+
+// checkSave проверяет квоту для сохранения под меткой label записи с файлом размером size
+func (q *QuotaService) checkSave(login string, label string, size int64) error {
+	user, err := q.userRepo.FindUser(login)
+	if err != nil {
+		return fmt.Errorf("ошибка при поиске пользователя: %w", err)
+	}
+
+	usage, err := q.getUsage(user.Id)
+	if err != nil {
+		return err
+	}
+
+	existing, err := q.findExisting(user.Id, label)
+	if err != nil {
+		return err
+	}
+
+	return usage.Quota.CheckSave(usage, existing, size)
+}
+
+// getUsage получает потребление и квоту пользователя по его ID
+func (q *QuotaService) getUsage(userID string) (*domain.Usage, error) {
+	quota, err := q.GetUserQuota(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	usage, err := q.repo.GetUsage(userID)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка при получении потребления: %w", err)
+	}
+	usage.Quota = *quota
+
+	return usage, nil
+}
+
+// findExisting ищет запись пользователя по метке, возвращает nil, если записи нет
+func (q *QuotaService) findExisting(userID string, label string) (*domain.UserData, error) {
+	existing, err := q.dataRepo.FindUserDataByLabel(userID, label)
+	if err == domain.ErrNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("ошибка при поиске записи: %w", err)
+	}
+	return existing, nil
+}
@@ -0,0 +1,157 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/SmirnovND/gophkeeper/internal/domain"
+	"testing"
+)
+
+// newTestQuotaService создает QuotaService с заданными потреблением, квотой и существующей записью
+func newTestQuotaService(usage domain.Usage, quota domain.Quota, existing *domain.UserData) *QuotaService {
+	return &QuotaService{
+		repo: &MockQuotaRepo{
+			GetUserQuotaFunc: func(userID string, defaults domain.Quota) (*domain.Quota, error) {
+				return &quota, nil
+			},
+			GetUsageFunc: func(userID string) (*domain.Usage, error) {
+				u := usage
+				return &u, nil
+			},
+		},
+		dataRepo: &MockUserDataRepo{
+			FindUserDataByLabelFunc: func(userID, label string) (*domain.UserData, error) {
+				if existing == nil {
+					return nil, domain.ErrNotFound
+				}
+				return existing, nil
+			},
+		},
+		userRepo: &MockUserRepo{
+			FindUserFunc: func(login string) (*domain.User, error) {
+				return &domain.User{Id: "user123"}, nil
+			},
+		},
+	}
+}
+
+// TestQuotaService_GetUsage тестирует получение потребления вместе с квотой
+func TestQuotaService_GetUsage(t *testing.T) {
+	quota := domain.Quota{MaxTotalBytes: 100, MaxItems: 10, MaxFileSize: 50}
+	service := newTestQuotaService(domain.Usage{TotalBytes: 30, Items: 2, Files: 1}, quota, nil)
+
+	usage, err := service.GetUsage("testuser")
+	if err != nil {
+		t.Fatalf("Неожиданная ошибка: %v", err)
+	}
+	if usage.TotalBytes != 30 || usage.Items != 2 || usage.Files != 1 {
+		t.Errorf("Неверное потребление: %+v", usage)
+	}
+	if usage.Quota != quota {
+		t.Errorf("Ожидалась квота %+v, получена %+v", quota, usage.Quota)
+	}
+}
+
+// TestQuotaService_CheckFileUpload тестирует проверку квоты при загрузке файла
+func TestQuotaService_CheckFileUpload(t *testing.T) {
+	existingFile, _ := json.Marshal(domain.FileMetadata{FileName: "file", Extension: "txt", Size: 40})
+
+	tests := []struct {
+		name      string
+		usage     domain.Usage
+		quota     domain.Quota
+		existing  *domain.UserData
+		size      int64
+		wantQuota bool
+	}{
+		{
+			name:  "Без ограничений",
+			usage: domain.Usage{TotalBytes: 1000, Items: 100},
+			size:  1000,
+		},
+		{
+			name:      "Файл больше максимального размера",
+			quota:     domain.Quota{MaxFileSize: 50},
+			size:      51,
+			wantQuota: true,
+		},
+		{
+			name:      "Превышено количество записей",
+			usage:     domain.Usage{Items: 10},
+			quota:     domain.Quota{MaxItems: 10},
+			size:      1,
+			wantQuota: true,
+		},
+		{
+			name:     "Перезапись при исчерпанном количестве записей",
+			usage:    domain.Usage{Items: 10, TotalBytes: 40},
+			quota:    domain.Quota{MaxItems: 10},
+			existing: &domain.UserData{Type: domain.UserDataTypeFile, Data: existingFile},
+			size:     1,
+		},
+		{
+			name:      "Превышен суммарный размер",
+			usage:     domain.Usage{TotalBytes: 90},
+			quota:     domain.Quota{MaxTotalBytes: 100},
+			size:      20,
+			wantQuota: true,
+		},
+		{
+			name:     "Перезапись освобождает размер старого файла",
+			usage:    domain.Usage{TotalBytes: 90, Items: 1},
+			quota:    domain.Quota{MaxTotalBytes: 100},
+			existing: &domain.UserData{Type: domain.UserDataTypeFile, Data: existingFile},
+			size:     50,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := newTestQuotaService(tt.usage, tt.quota, tt.existing)
+			err := service.CheckFileUpload("testuser", "file", tt.size)
+			if tt.wantQuota {
+				if !errors.Is(err, domain.ErrQuotaExceeded) {
+					t.Errorf("Ожидалась ошибка превышения квоты, получено: %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("Неожиданная ошибка: %v", err)
+			}
+		})
+	}
+}
+
+// TestQuotaService_CheckItemSave тестирует проверку квоты на количество записей
+func TestQuotaService_CheckItemSave(t *testing.T) {
+	quota := domain.Quota{MaxItems: 2}
+
+	service := newTestQuotaService(domain.Usage{Items: 1}, quota, nil)
+	if err := service.CheckItemSave("testuser", "new"); err != nil {
+		t.Errorf("Неожиданная ошибка: %v", err)
+	}
+
+	service = newTestQuotaService(domain.Usage{Items: 2}, quota, nil)
+	if err := service.CheckItemSave("testuser", "new"); !errors.Is(err, domain.ErrQuotaExceeded) {
+		t.Errorf("Ожидалась ошибка превышения квоты, получено: %v", err)
+	}
+
+	service = newTestQuotaService(domain.Usage{Items: 2}, quota, &domain.UserData{Type: domain.UserDataTypeText})
+	if err := service.CheckItemSave("testuser", "existing"); err != nil {
+		t.Errorf("Обновление существующей записи не должно превышать квоту: %v", err)
+	}
+}
+
+// TestQuotaService_UserNotFound тестирует ошибку поиска пользователя
+func TestQuotaService_UserNotFound(t *testing.T) {
+	service := newTestQuotaService(domain.Usage{}, domain.Quota{}, nil)
+	service.userRepo = &MockUserRepo{
+		FindUserFunc: func(login string) (*domain.User, error) {
+			return nil, domain.ErrNotFound
+		},
+	}
+
+	if _, err := service.GetUsage("unknown"); err == nil {
+		t.Error("Ожидалась ошибка при поиске пользователя")
+	}
+}
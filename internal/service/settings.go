@@ -0,0 +1,58 @@
+package service
+
+import (
+	"fmt"
+	"github.com/SmirnovND/gophkeeper/internal/domain"
+	"github.com/SmirnovND/gophkeeper/internal/interfaces"
+)
+
+// SettingsService реализует интерфейс для работы с настройками аккаунта
+type SettingsService struct {
+	repo     interfaces.SettingsRepo
+	userRepo interfaces.UserRepo
+	defaults domain.UserSettings
+}
+
+// NewSettingsService создает новый экземпляр SettingsService
+func NewSettingsService(
+	repo interfaces.SettingsRepo,
+	userRepo interfaces.UserRepo,
+	defaults domain.UserSettings,
+) interfaces.SettingsService {
+	return &SettingsService{
+		repo:     repo,
+		userRepo: userRepo,
+		defaults: defaults,
+	}
+}
+
+// GetSettings возвращает действующие настройки пользователя
+func (s *SettingsService) GetSettings(login string) (*domain.UserSettings, error) {
+	user, err := s.userRepo.FindUser(login)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка при поиске пользователя: %w", err)
+	}
+
+	settings, err := s.repo.GetUserSettings(user.Id, s.defaults)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка при получении настроек: %w", err)
+	}
+	return settings, nil
+}
+
+// SaveSettings проверяет и сохраняет настройки пользователя
+func (s *SettingsService) SaveSettings(login string, settings *domain.UserSettings) error {
+	if settings.PasswordHistory < 0 || settings.PasswordHistory > domain.MaxPasswordHistory {
+		return fmt.Errorf("%w: длина истории паролей должна быть от 0 до %d", domain.ErrInvalidSettings, domain.MaxPasswordHistory)
+	}
+
+	user, err := s.userRepo.FindUser(login)
+	if err != nil {
+		return fmt.Errorf("ошибка при поиске пользователя: %w", err)
+	}
+
+	if err := s.repo.SaveUserSettings(user.Id, settings); err != nil {
+		return fmt.Errorf("ошибка при сохранении настроек: %w", err)
+	}
+	return nil
+}
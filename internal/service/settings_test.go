@@ -0,0 +1,38 @@
+package service
+
+import (
+	"errors"
+	"github.com/SmirnovND/gophkeeper/internal/domain"
+	"testing"
+)
+
+// TestSettingsService тестирует настройки по умолчанию, их изменение и отказ от недопустимой длины истории паролей
+func TestSettingsService(t *testing.T) {
+	mockUserRepo := &MockUserRepo{
+		FindUserFunc: func(login string) (*domain.User, error) {
+			return &domain.User{Id: "user-" + login}, nil
+		},
+	}
+	settingsService := NewSettingsService(NewMockSettingsRepo(), mockUserRepo, domain.UserSettings{PasswordHistory: 5})
+
+	settings, err := settingsService.GetSettings("alice")
+	if err != nil || settings.PasswordHistory != 5 {
+		t.Fatalf("Ожидались настройки по умолчанию, получено %+v, %v", settings, err)
+	}
+
+	if err := settingsService.SaveSettings("alice", &domain.UserSettings{PasswordHistory: 0}); err != nil {
+		t.Fatalf("Ошибка при вызове SaveSettings: %v", err)
+	}
+	if settings, _ := settingsService.GetSettings("alice"); settings.PasswordHistory != 0 {
+		t.Errorf("Настройки не сохранены: %+v", settings)
+	}
+	if settings, _ := settingsService.GetSettings("bob"); settings.PasswordHistory != 5 {
+		t.Errorf("Настройки одного пользователя не должны влиять на другого: %+v", settings)
+	}
+
+	for _, history := range []int{-1, domain.MaxPasswordHistory + 1} {
+		if err := settingsService.SaveSettings("alice", &domain.UserSettings{PasswordHistory: history}); !errors.Is(err, domain.ErrInvalidSettings) {
+			t.Errorf("Для длины истории %d ожидалась ошибка %v, получено %v", history, domain.ErrInvalidSettings, err)
+		}
+	}
+}
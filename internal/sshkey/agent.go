@@ -0,0 +1,134 @@
+package sshkey
+
+import (
+	"crypto"
+	"errors"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+	"net"
+	"sync"
+)
+
+var (
+	// ErrReadOnly возвращается на запросы ssh-add изменить набор ключей агента
+	ErrReadOnly = errors.New("агент passcli обслуживает только ключи хранилища")
+	// ErrDenied возвращается, если пользователь не подтвердил подпись
+	ErrDenied = errors.New("подпись не подтверждена")
+)
+
+// ConfirmFunc спрашивает пользователя, разрешить ли подпись ключом с меткой label
+type ConfirmFunc func(label string, fingerprint string) bool
+
+// Agent - агент SSH с ключами хранилища. Ключи хранятся только в памяти, добавить, удалить или
+// заменить их через ssh-add нельзя. Если задан confirm, каждая подпись требует подтверждения
+type Agent struct {
+	keyring agent.ExtendedAgent
+	confirm ConfirmFunc
+
+	mu     sync.Mutex
+	labels map[string]string
+}
+
+var _ agent.ExtendedAgent = (*Agent)(nil)
+
+// NewAgent создает агент без ключей. confirm равен nil, если подпись не требует подтверждения
+func NewAgent(confirm ConfirmFunc) *Agent {
+	return &Agent{
+		keyring: agent.NewKeyring().(agent.ExtendedAgent),
+		confirm: confirm,
+		labels:  make(map[string]string),
+	}
+}
+
+// AddKey добавляет закрытый ключ записи label. Метка становится комментарием ключа в ssh-add -l
+func (a *Agent) AddKey(label string, key crypto.PrivateKey) error {
+	signer, err := ssh.NewSignerFromKey(key)
+	if err != nil {
+		return err
+	}
+	if err := a.keyring.Add(agent.AddedKey{PrivateKey: key, Comment: label}); err != nil {
+		return err
+	}
+
+	a.mu.Lock()
+	a.labels[string(signer.PublicKey().Marshal())] = label
+	a.mu.Unlock()
+	return nil
+}
+
+// Serve обслуживает подключения к listener, пока он не закрыт
+func (a *Agent) Serve(listener net.Listener) error {
+	for {
+		conn, err := listener.Accept()
+		if errors.Is(err, net.ErrClosed) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		go func() {
+			defer conn.Close()
+			_ = agent.ServeAgent(a, conn)
+		}()
+	}
+}
+
+// List возвращает открытые ключи агента
+func (a *Agent) List() ([]*agent.Key, error) {
+	return a.keyring.List()
+}
+
+// Sign подписывает data ключом key
+func (a *Agent) Sign(key ssh.PublicKey, data []byte) (*ssh.Signature, error) {
+	return a.SignWithFlags(key, data, 0)
+}
+
+// SignWithFlags подписывает data ключом key после подтверждения пользователя, если оно требуется.
+// Запросы подтверждения выполняются по одному, чтобы не смешиваться на терминале
+func (a *Agent) SignWithFlags(key ssh.PublicKey, data []byte, flags agent.SignatureFlags) (*ssh.Signature, error) {
+	if a.confirm != nil {
+		a.mu.Lock()
+		label, ok := a.labels[string(key.Marshal())]
+		allowed := ok && a.confirm(label, ssh.FingerprintSHA256(key))
+		a.mu.Unlock()
+		if ok && !allowed {
+			return nil, ErrDenied
+		}
+	}
+	return a.keyring.SignWithFlags(key, data, flags)
+}
+
+// Signers возвращает подписчиков для ключей агента
+func (a *Agent) Signers() ([]ssh.Signer, error) {
+	return a.keyring.Signers()
+}
+
+// Lock блокирует агент парольной фразой ssh-add -x
+func (a *Agent) Lock(passphrase []byte) error {
+	return a.keyring.Lock(passphrase)
+}
+
+// Unlock снимает блокировку агента
+func (a *Agent) Unlock(passphrase []byte) error {
+	return a.keyring.Unlock(passphrase)
+}
+
+// Add отклоняет добавление ключа через ssh-add
+func (a *Agent) Add(key agent.AddedKey) error {
+	return ErrReadOnly
+}
+
+// Remove отклоняет удаление ключа через ssh-add
+func (a *Agent) Remove(key ssh.PublicKey) error {
+	return ErrReadOnly
+}
+
+// RemoveAll отклоняет удаление ключей через ssh-add
+func (a *Agent) RemoveAll() error {
+	return ErrReadOnly
+}
+
+// Extension не поддерживается
+func (a *Agent) Extension(extensionType string, contents []byte) ([]byte, error) {
+	return nil, agent.ErrExtensionUnsupported
+}
@@ -0,0 +1,78 @@
+package sshkey
+
+import (
+	"errors"
+	"golang.org/x/crypto/ssh/agent"
+	"net"
+	"path/filepath"
+	"testing"
+)
+
+// newAgentClient запускает агент на сокете во временной директории и возвращает подключенного клиента
+func newAgentClient(t *testing.T, a *Agent) agent.ExtendedAgent {
+	t.Helper()
+	listener, err := net.Listen("unix", filepath.Join(t.TempDir(), "agent.sock"))
+	if err != nil {
+		t.Skipf("Сокеты unix недоступны: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+	go a.Serve(listener)
+
+	conn, err := net.Dial("unix", listener.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return agent.NewClient(conn)
+}
+
+// TestAgent тестирует список ключей, подпись с подтверждением и отказ изменять ключи через ssh-add
+func TestAgent(t *testing.T) {
+	data, err := Generate(TypeEd25519, 0, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := PrivateKey(data, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var asked []string
+	allow := true
+	a := NewAgent(func(label string, fingerprint string) bool {
+		asked = append(asked, label+" "+fingerprint)
+		return allow
+	})
+	if err := a.AddKey("deploy", key); err != nil {
+		t.Fatal(err)
+	}
+	client := newAgentClient(t, a)
+
+	keys, err := client.List()
+	if err != nil || len(keys) != 1 || keys[0].Comment != "deploy" {
+		t.Fatalf("Неверный список ключей: %v, %v", keys, err)
+	}
+
+	signature, err := client.Sign(keys[0], []byte("data"))
+	if err != nil {
+		t.Fatalf("Неожиданная ошибка подписи: %v", err)
+	}
+	if err := keys[0].Verify([]byte("data"), signature); err != nil {
+		t.Errorf("Подпись не проверяется: %v", err)
+	}
+	if len(asked) != 1 || asked[0] != "deploy "+data.Fingerprint {
+		t.Errorf("Неверные запросы подтверждения: %v", asked)
+	}
+
+	allow = false
+	if _, err := client.Sign(keys[0], []byte("data")); err == nil {
+		t.Error("Ожидалась ошибка для неподтвержденной подписи")
+	}
+
+	if err := client.RemoveAll(); err == nil {
+		t.Error("Ожидалась ошибка удаления ключей")
+	}
+	if err := a.Add(agent.AddedKey{PrivateKey: key}); !errors.Is(err, ErrReadOnly) {
+		t.Errorf("Ожидалась ошибка %v, получено %v", ErrReadOnly, err)
+	}
+}
@@ -0,0 +1,204 @@
+// Package sshkey создает и проверяет ключи SSH записей ssh_key и обслуживает их агентом SSH
+package sshkey
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"github.com/SmirnovND/gophkeeper/internal/domain"
+	"golang.org/x/crypto/ssh"
+	"strings"
+)
+
+// Типы создаваемых ключей
+const (
+	TypeEd25519 = "ed25519"
+	TypeRSA     = "rsa"
+	TypeECDSA   = "ecdsa"
+)
+
+// Размеры ключей rsa в битах
+const (
+	DefaultRSABits = 4096
+	minRSABits     = 2048
+	maxRSABits     = 8192
+)
+
+var (
+	// ErrInvalidKey - ключ не разбирается или его части не соответствуют друг другу
+	ErrInvalidKey = errors.New("неверный ключ SSH")
+	// ErrPassphraseRequired - закрытый ключ зашифрован, для его использования нужна парольная фраза
+	ErrPassphraseRequired = errors.New("закрытый ключ зашифрован парольной фразой")
+	// ErrWrongPassphrase - парольная фраза не подходит к закрытому ключу
+	ErrWrongPassphrase = errors.New("неверная парольная фраза ключа")
+)
+
+// Generate создает ключ типа keyType. bits задает размер ключа rsa или кривую ecdsa (256, 384, 521),
+// 0 означает размер по умолчанию. Закрытый ключ сохраняется в формате OpenSSH без парольной фразы
+func Generate(keyType string, bits int, comment string) (*domain.SSHKeyData, error) {
+	var key crypto.PrivateKey
+	var err error
+	switch keyType {
+	case TypeEd25519:
+		if bits != 0 {
+			return nil, errors.New("размер ключа ed25519 не задается")
+		}
+		_, key, err = ed25519.GenerateKey(rand.Reader)
+	case TypeRSA:
+		if bits == 0 {
+			bits = DefaultRSABits
+		}
+		if bits < minRSABits || bits > maxRSABits {
+			return nil, fmt.Errorf("размер ключа rsa должен быть от %d до %d бит", minRSABits, maxRSABits)
+		}
+		key, err = rsa.GenerateKey(rand.Reader, bits)
+	case TypeECDSA:
+		var curve elliptic.Curve
+		switch bits {
+		case 0, 256:
+			curve = elliptic.P256()
+		case 384:
+			curve = elliptic.P384()
+		case 521:
+			curve = elliptic.P521()
+		default:
+			return nil, errors.New("размер ключа ecdsa должен быть 256, 384 или 521 бит")
+		}
+		key, err = ecdsa.GenerateKey(curve, rand.Reader)
+	default:
+		return nil, fmt.Errorf("неизвестный тип ключа '%s', допустимы %s, %s и %s", keyType, TypeEd25519, TypeRSA, TypeECDSA)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("ошибка при создании ключа: %w", err)
+	}
+
+	block, err := ssh.MarshalPrivateKey(key, comment)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка при сериализации ключа: %w", err)
+	}
+	data := &domain.SSHKeyData{PrivateKey: string(pem.EncodeToMemory(block)), Comment: comment}
+	if err := Complete(data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// Complete заполняет открытый ключ и отпечаток по закрытому ключу. Открытый ключ зашифрованного ключа OpenSSH
+// читается без парольной фразы, а для зашифрованного ключа PEM его нужно передать в PublicKey.
+// Если PublicKey задан, он должен соответствовать закрытому ключу. Комментарий берется из открытого ключа,
+// если не задан явно
+func Complete(data *domain.SSHKeyData) error {
+	if strings.ContainsAny(data.Comment, "\r\n") {
+		return fmt.Errorf("%w: комментарий содержит перевод строки", ErrInvalidKey)
+	}
+
+	var given ssh.PublicKey
+	if strings.TrimSpace(data.PublicKey) != "" {
+		public, comment, err := parsePublicKey(data.PublicKey)
+		if err != nil {
+			return err
+		}
+		given = public
+		if data.Comment == "" {
+			data.Comment = comment
+		}
+	}
+
+	public, err := publicKey(data.PrivateKey)
+	switch {
+	case errors.Is(err, ErrPassphraseRequired) && given != nil:
+		public = given
+	case err != nil:
+		return err
+	case given != nil && !equal(public, given):
+		return fmt.Errorf("%w: открытый ключ не соответствует закрытому", ErrInvalidKey)
+	}
+
+	data.PublicKey = authorizedKey(public, data.Comment)
+	data.Fingerprint = ssh.FingerprintSHA256(public)
+	return nil
+}
+
+// Validate проверяет ключ перед сохранением: закрытый ключ записан в PEM, открытый ключ разбирается,
+// а отпечаток соответствует ему. Содержимое закрытого ключа не проверяется, так как он может быть зашифрован
+func Validate(data *domain.SSHKeyData) error {
+	if block, _ := pem.Decode([]byte(data.PrivateKey)); block == nil {
+		return fmt.Errorf("%w: закрытый ключ не в формате PEM", ErrInvalidKey)
+	}
+	public, _, err := parsePublicKey(data.PublicKey)
+	if err != nil {
+		return err
+	}
+	if data.Fingerprint != ssh.FingerprintSHA256(public) {
+		return fmt.Errorf("%w: отпечаток не соответствует открытому ключу", ErrInvalidKey)
+	}
+	return nil
+}
+
+// PrivateKey разбирает закрытый ключ для подписи. Для зашифрованного ключа без парольной фразы
+// возвращается ErrPassphraseRequired
+func PrivateKey(data *domain.SSHKeyData, passphrase []byte) (crypto.PrivateKey, error) {
+	var key crypto.PrivateKey
+	var err error
+	if passphrase == nil {
+		key, err = ssh.ParseRawPrivateKey([]byte(data.PrivateKey))
+	} else {
+		key, err = ssh.ParseRawPrivateKeyWithPassphrase([]byte(data.PrivateKey), passphrase)
+	}
+
+	var missing *ssh.PassphraseMissingError
+	switch {
+	case errors.As(err, &missing):
+		return nil, ErrPassphraseRequired
+	case errors.Is(err, x509.IncorrectPasswordError):
+		return nil, ErrWrongPassphrase
+	case err != nil:
+		return nil, fmt.Errorf("%w: %v", ErrInvalidKey, err)
+	}
+	return key, nil
+}
+
+// publicKey возвращает открытый ключ закрытого ключа private
+func publicKey(private string) (ssh.PublicKey, error) {
+	signer, err := ssh.ParsePrivateKey([]byte(private))
+	var missing *ssh.PassphraseMissingError
+	if errors.As(err, &missing) {
+		if missing.PublicKey != nil {
+			return missing.PublicKey, nil
+		}
+		return nil, ErrPassphraseRequired
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidKey, err)
+	}
+	return signer.PublicKey(), nil
+}
+
+// parsePublicKey разбирает открытый ключ в формате authorized_keys
+func parsePublicKey(value string) (ssh.PublicKey, string, error) {
+	public, comment, _, _, err := ssh.ParseAuthorizedKey([]byte(value))
+	if err != nil {
+		return nil, "", fmt.Errorf("%w: открытый ключ: %v", ErrInvalidKey, err)
+	}
+	return public, comment, nil
+}
+
+// authorizedKey возвращает открытый ключ в формате authorized_keys с комментарием
+func authorizedKey(public ssh.PublicKey, comment string) string {
+	line := strings.TrimSpace(string(ssh.MarshalAuthorizedKey(public)))
+	if comment = strings.TrimSpace(comment); comment != "" {
+		line += " " + comment
+	}
+	return line
+}
+
+func equal(a ssh.PublicKey, b ssh.PublicKey) bool {
+	return string(a.Marshal()) == string(b.Marshal())
+}
@@ -0,0 +1,176 @@
+// Package totp разбирает ключи TOTP в формате URI otpauth:// и вычисляет одноразовые коды по RFC 6238
+package totp
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base32"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Алгоритмы HMAC, допустимые в параметре algorithm
+const (
+	AlgorithmSHA1   = "SHA1"
+	AlgorithmSHA256 = "SHA256"
+	AlgorithmSHA512 = "SHA512"
+)
+
+// Значения параметров по умолчанию и их допустимые границы
+const (
+	DefaultDigits = 6
+	DefaultPeriod = 30
+	minDigits     = 6
+	maxDigits     = 8
+	maxPeriod     = 300
+)
+
+// ErrInvalidURI - URI не является ключом TOTP в формате otpauth://totp/...
+var ErrInvalidURI = errors.New("неверный URI ключа TOTP")
+
+// Key - ключ TOTP: общий секрет и параметры вычисления кода
+type Key struct {
+	Secret    []byte
+	Issuer    string
+	Account   string
+	Algorithm string
+	Digits    int
+	Period    int
+}
+
+// Parse разбирает URI вида otpauth://totp/Issuer:account?secret=BASE32&issuer=Issuer&algorithm=SHA1&digits=6&period=30.
+// Обязателен только параметр secret, остальные принимают значения по умолчанию
+func Parse(uri string) (*Key, error) {
+	u, err := url.Parse(strings.TrimSpace(uri))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidURI, err)
+	}
+	if !strings.EqualFold(u.Scheme, "otpauth") {
+		return nil, fmt.Errorf("%w: ожидается схема otpauth://", ErrInvalidURI)
+	}
+	switch strings.ToLower(u.Host) {
+	case "totp":
+	case "hotp":
+		return nil, fmt.Errorf("%w: коды HOTP по счетчику не поддерживаются", ErrInvalidURI)
+	default:
+		return nil, fmt.Errorf("%w: неизвестный тип ключа '%s'", ErrInvalidURI, u.Host)
+	}
+
+	key := &Key{Algorithm: AlgorithmSHA1, Digits: DefaultDigits, Period: DefaultPeriod}
+	label := strings.TrimPrefix(u.Path, "/")
+	if i := strings.Index(label, ":"); i >= 0 {
+		key.Issuer, key.Account = strings.TrimSpace(label[:i]), strings.TrimSpace(label[i+1:])
+	} else {
+		key.Account = strings.TrimSpace(label)
+	}
+
+	query := u.Query()
+	if key.Secret, err = decodeSecret(query.Get("secret")); err != nil {
+		return nil, err
+	}
+	if issuer := query.Get("issuer"); issuer != "" {
+		key.Issuer = issuer
+	}
+	if algorithm := query.Get("algorithm"); algorithm != "" {
+		key.Algorithm = strings.ToUpper(algorithm)
+		if newHash(key.Algorithm) == nil {
+			return nil, fmt.Errorf("%w: алгоритм '%s' не поддерживается, допустимы %s, %s и %s",
+				ErrInvalidURI, algorithm, AlgorithmSHA1, AlgorithmSHA256, AlgorithmSHA512)
+		}
+	}
+	if digits := query.Get("digits"); digits != "" {
+		key.Digits, err = strconv.Atoi(digits)
+		if err != nil || key.Digits < minDigits || key.Digits > maxDigits {
+			return nil, fmt.Errorf("%w: число цифр должно быть от %d до %d", ErrInvalidURI, minDigits, maxDigits)
+		}
+	}
+	if period := query.Get("period"); period != "" {
+		key.Period, err = strconv.Atoi(period)
+		if err != nil || key.Period <= 0 || key.Period > maxPeriod {
+			return nil, fmt.Errorf("%w: период должен быть от 1 до %d секунд", ErrInvalidURI, maxPeriod)
+		}
+	}
+	return key, nil
+}
+
+// URI приводит значение к URI ключа TOTP. Значение - URI otpauth:// или голый секрет в base32, который
+// показывают сервисы и экспортируют менеджеры паролей; для секрета URI строится с учетной записью account
+func URI(value string, account string) (string, error) {
+	value = strings.TrimSpace(value)
+	if !strings.Contains(value, "://") {
+		secret := strings.TrimRight(strings.ToUpper(strings.Join(strings.Fields(value), "")), "=")
+		value = (&url.URL{
+			Scheme:   "otpauth",
+			Host:     "totp",
+			Path:     "/" + account,
+			RawQuery: url.Values{"secret": {secret}}.Encode(),
+		}).String()
+	}
+	if _, err := Parse(value); err != nil {
+		return "", err
+	}
+	return value, nil
+}
+
+// Code возвращает код, действующий в момент t
+func (k *Key) Code(t time.Time) string {
+	var counter [8]byte
+	binary.BigEndian.PutUint64(counter[:], uint64(t.Unix()/int64(k.Period)))
+
+	mac := hmac.New(newHash(k.Algorithm), k.Secret)
+	mac.Write(counter[:])
+	sum := mac.Sum(nil)
+
+	// Динамическое усечение по RFC 4226
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	modulo := uint32(1)
+	for i := 0; i < k.Digits; i++ {
+		modulo *= 10
+	}
+	return fmt.Sprintf("%0*d", k.Digits, value%modulo)
+}
+
+// Remaining возвращает время, через которое код момента t сменится
+func (k *Key) Remaining(t time.Time) time.Duration {
+	period := time.Duration(k.Period) * time.Second
+	return period - time.Duration(t.UnixNano())%period
+}
+
+// decodeSecret декодирует секрет в base32. Пробелы, строчные буквы и отсутствие дополнения '=' допускаются,
+// так как сервисы часто показывают секрет в таком виде
+func decodeSecret(secret string) ([]byte, error) {
+	secret = strings.ToUpper(strings.Join(strings.Fields(secret), ""))
+	secret = strings.TrimRight(secret, "=")
+	if secret == "" {
+		return nil, fmt.Errorf("%w: не задан параметр secret", ErrInvalidURI)
+	}
+	decoded, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(secret)
+	if err != nil || len(decoded) == 0 {
+		return nil, fmt.Errorf("%w: секрет должен быть в кодировке base32", ErrInvalidURI)
+	}
+	return decoded, nil
+}
+
+// newHash возвращает конструктор хеш-функции алгоритма, nil для неизвестного алгоритма
+func newHash(algorithm string) func() hash.Hash {
+	switch algorithm {
+	case AlgorithmSHA1:
+		return sha1.New
+	case AlgorithmSHA256:
+		return sha256.New
+	case AlgorithmSHA512:
+		return sha512.New
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,83 @@
+package totp
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// Секреты тестовых векторов RFC 6238 в base32
+const (
+	secretSHA1   = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+	secretSHA256 = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQGEZA"
+	secretSHA512 = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQGEZDGNA"
+)
+
+// TestKey_Code тестирует вычисление кодов по тестовым векторам RFC 6238
+func TestKey_Code(t *testing.T) {
+	tests := []struct {
+		uri  string
+		unix int64
+		want string
+	}{
+		{"otpauth://totp/Test?secret=" + secretSHA1 + "&digits=8", 59, "94287082"},
+		{"otpauth://totp/Test?secret=" + secretSHA1 + "&digits=8", 1111111109, "07081804"},
+		{"otpauth://totp/Test?secret=" + secretSHA256 + "&digits=8&algorithm=SHA256", 1111111111, "67062674"},
+		{"otpauth://totp/Test?secret=" + secretSHA512 + "&digits=8&algorithm=sha512", 20000000000, "47863826"},
+		{"otpauth://totp/Test?secret=" + secretSHA1, 1234567890, "005924"},
+	}
+	for _, tt := range tests {
+		key, err := Parse(tt.uri)
+		if err != nil {
+			t.Fatalf("Неожиданная ошибка для %s: %v", tt.uri, err)
+		}
+		if got := key.Code(time.Unix(tt.unix, 0)); got != tt.want {
+			t.Errorf("Для %s в %d ожидался код %s, получен %s", tt.uri, tt.unix, tt.want, got)
+		}
+	}
+}
+
+// TestParse тестирует разбор параметров и отказ от неверных URI
+func TestParse(t *testing.T) {
+	key, err := Parse("otpauth://totp/ACME%20Co:alice@example.com?secret=gezd%20gnbv%20gy3t%20qojq&issuer=ACME&period=60")
+	if err != nil {
+		t.Fatalf("Неожиданная ошибка: %v", err)
+	}
+	if key.Issuer != "ACME" || key.Account != "alice@example.com" || key.Period != 60 ||
+		key.Digits != DefaultDigits || key.Algorithm != AlgorithmSHA1 || string(key.Secret) != "1234567890" {
+		t.Errorf("Ключ разобран неверно: %+v", key)
+	}
+	if got := key.Remaining(time.Unix(125, 500000000)); got != 54500*time.Millisecond {
+		t.Errorf("Ожидалось 54.5s до смены кода, получено %v", got)
+	}
+
+	for _, uri := range []string{
+		"https://example.com/?secret=" + secretSHA1,
+		"otpauth://hotp/Test?secret=" + secretSHA1 + "&counter=1",
+		"otpauth://totp/Test",
+		"otpauth://totp/Test?secret=not-base32!",
+		"otpauth://totp/Test?secret=" + secretSHA1 + "&algorithm=MD5",
+		"otpauth://totp/Test?secret=" + secretSHA1 + "&digits=4",
+		"otpauth://totp/Test?secret=" + secretSHA1 + "&period=0",
+	} {
+		if _, err := Parse(uri); !errors.Is(err, ErrInvalidURI) {
+			t.Errorf("Для %s ожидалась ошибка %v, получено %v", uri, ErrInvalidURI, err)
+		}
+	}
+}
+
+// TestURI тестирует построение URI из голого секрета и отказ от неверных значений
+func TestURI(t *testing.T) {
+	uri, err := URI(" jbsw y3dp ", "GitHub")
+	if err != nil || uri != "otpauth://totp/GitHub?secret=JBSWY3DP" {
+		t.Errorf("Ожидался URI из секрета, получено %q, %v", uri, err)
+	}
+	if uri, err := URI("otpauth://totp/Test?secret="+secretSHA1, "other"); err != nil || uri != "otpauth://totp/Test?secret="+secretSHA1 {
+		t.Errorf("URI должен сохраняться без изменений, получено %q, %v", uri, err)
+	}
+	for _, value := range []string{"", "not-base32!", "steam://JBSWY3DP"} {
+		if _, err := URI(value, "Test"); !errors.Is(err, ErrInvalidURI) {
+			t.Errorf("Для %q ожидалась ошибка %v, получено %v", value, ErrInvalidURI, err)
+		}
+	}
+}
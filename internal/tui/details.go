@@ -0,0 +1,253 @@
+package tui
+
+import (
+	"fmt"
+	"github.com/SmirnovND/gophkeeper/internal/domain"
+	"github.com/SmirnovND/gophkeeper/internal/itemtype"
+	"github.com/SmirnovND/gophkeeper/internal/totp"
+	"github.com/SmirnovND/gophkeeper/pkg"
+	"github.com/rivo/tview"
+	"strings"
+	"time"
+)
+
+// mask заменяет секрет, длина маски не зависит от длины секрета
+const mask = "••••••••"
+
+// itemDetail - содержимое выбранной записи. Поле содержимого заполнено только для типа записи,
+// у структурированных типов - поля itemType и item
+type itemDetail struct {
+	info       domain.ItemInfo
+	credential *domain.CredentialData
+	card       *domain.CardData
+	text       *domain.TextData
+	sshKey     *domain.SSHKeyData
+	file       *domain.FileInfo
+	itemType   *itemtype.Type
+	item       domain.ItemData
+	metadata   string
+	loaded     bool
+	err        error
+}
+
+// selectItem показывает выбранную запись и загружает ее содержимое. Секреты новой записи снова скрываются
+func (a *App) selectItem() {
+	item, ok := a.current()
+	if !ok {
+		a.detail = nil
+		a.reveal = false
+		a.details.SetText("[gray]Нет записей. Нажмите n, чтобы создать запись[-]")
+		return
+	}
+	if a.detail != nil && sameItem(a.detail.info, item) {
+		return
+	}
+
+	a.detail = &itemDetail{info: item}
+	a.reveal = false
+	a.renderDetail()
+	a.loadDetail(item)
+}
+
+// loadDetail загружает содержимое записи вне потока интерфейса. Результат показывается,
+// только если запись все еще выбрана
+func (a *App) loadDetail(item domain.ItemInfo) {
+	go func() {
+		detail := &itemDetail{info: item, loaded: true}
+		switch item.Type {
+		case domain.UserDataTypeCredential:
+			detail.credential, detail.metadata, detail.err = a.clientUseCase.GetCredential(item.Label)
+		case domain.UserDataTypeCard:
+			detail.card, detail.metadata, detail.err = a.clientUseCase.GetCard(item.Label)
+		case domain.UserDataTypeText:
+			detail.text, detail.metadata, detail.err = a.clientUseCase.GetText(item.Label)
+		case domain.UserDataTypeSSHKey:
+			detail.sshKey, detail.metadata, detail.err = a.clientUseCase.GetSSHKey(item.Label)
+		case domain.UserDataTypeFile:
+			detail.file, detail.err = a.clientUseCase.FileInfo(item.Label)
+			if detail.file != nil {
+				detail.metadata = detail.file.Metadata
+			}
+		default:
+			if t, err := a.clientUseCase.ItemType(item.Type); err == nil {
+				detail.itemType = t
+				detail.item, detail.metadata, detail.err = a.clientUseCase.GetItem(item.Type, item.Label)
+				break
+			}
+			detail.metadata = item.Metadata
+		}
+
+		a.app.QueueUpdateDraw(func() {
+			if a.detail != nil && sameItem(a.detail.info, item) {
+				a.detail = detail
+				a.renderDetail()
+			}
+		})
+	}()
+}
+
+// renderDetail выводит выбранную запись, секреты скрыты, пока не нажата клавиша r
+func (a *App) renderDetail() {
+	if a.detail == nil {
+		return
+	}
+	detail := a.detail
+
+	var b strings.Builder
+	field := func(name string, value string) {
+		fmt.Fprintf(&b, "[yellow]%s:[-] %s\n", name, tview.Escape(value))
+	}
+	secret := func(name string, value string, masked string) {
+		if a.reveal {
+			field(name, value)
+			return
+		}
+		fmt.Fprintf(&b, "[yellow]%s:[-] [gray]%s[-]\n", name, masked)
+	}
+
+	field("Тип", a.typeTitle(detail.info.Type))
+	field("Метка", detail.info.Label)
+
+	switch {
+	case !detail.loaded:
+		b.WriteString("\n[gray]Загрузка...[-]\n")
+	case detail.err != nil:
+		b.WriteString("\n[red]Ошибка: " + tview.Escape(detail.err.Error()) + "[-]\n")
+	case detail.credential != nil:
+		field("Логин", detail.credential.Login)
+		secret("Пароль", detail.credential.Password, mask)
+		if detail.credential.TOTP != "" {
+			// Код не скрывается: он действует до конца периода и обновляется каждую секунду
+			if key, err := totp.Parse(detail.credential.TOTP); err == nil {
+				moment := time.Now()
+				remaining := (key.Remaining(moment) + time.Second - 1) / time.Second
+				fmt.Fprintf(&b, "[yellow]Код TOTP:[-] [green]%s[-] [gray](сменится через %d с)[-]\n", key.Code(moment), remaining)
+			} else {
+				fmt.Fprintf(&b, "[yellow]Код TOTP:[-] [red]%s[-]\n", tview.Escape(err.Error()))
+			}
+			secret("Ключ TOTP", detail.credential.TOTP, mask)
+		}
+		for _, uri := range detail.credential.URIs {
+			if uri.Match != "" {
+				field("Адрес", uri.URI+" ("+uri.Match+")")
+				continue
+			}
+			field("Адрес", uri.URI)
+		}
+		for _, custom := range detail.credential.Fields {
+			if custom.Type == domain.CustomFieldHidden {
+				secret(custom.Name, custom.Value, mask)
+				continue
+			}
+			field(custom.Name, custom.Value)
+		}
+	case detail.card != nil:
+		secret("Номер", detail.card.Number, maskCardNumber(detail.card.Number))
+		field("Владелец", detail.card.Holder)
+		field("Срок действия", detail.card.ExpiryDate)
+		secret("CVV", detail.card.CVV, mask)
+	case detail.text != nil:
+		if a.reveal {
+			b.WriteString("[yellow]Текст:[-]\n" + tview.Escape(detail.text.Content) + "\n")
+		} else {
+			fmt.Fprintf(&b, "[yellow]Текст:[-] [gray]скрыт, %d символов[-]\n", len([]rune(detail.text.Content)))
+		}
+	case detail.sshKey != nil:
+		field("Отпечаток", detail.sshKey.Fingerprint)
+		if detail.sshKey.Comment != "" {
+			field("Комментарий", detail.sshKey.Comment)
+		}
+		b.WriteString("[yellow]Открытый ключ:[-]\n" + tview.Escape(detail.sshKey.PublicKey) + "\n")
+		if a.reveal {
+			b.WriteString("[yellow]Закрытый ключ:[-]\n" + tview.Escape(detail.sshKey.PrivateKey) + "\n")
+		} else {
+			b.WriteString("[yellow]Закрытый ключ:[-] [gray]скрыт[-]\n")
+		}
+	case detail.item != nil:
+		for _, f := range detail.itemType.Fields {
+			value := detail.item[f.Name]
+			switch {
+			case value == "":
+			case f.Kind == itemtype.KindSecret:
+				secret(f.Title, value, mask)
+			default:
+				field(f.Title, value)
+			}
+		}
+		if date, ok := detail.itemType.ExpiresAt(detail.item); ok && date.Before(time.Now()) {
+			b.WriteString("[red]Срок действия истек[-]\n")
+		}
+	case detail.file != nil:
+		field("Расширение", detail.file.Extension)
+		field("Размер", pkg.FormatBytes(detail.file.Size))
+		if detail.file.MimeType != "" {
+			field("Тип содержимого", detail.file.MimeType)
+		}
+		if detail.file.OriginalName != "" {
+			field("Исходное имя", detail.file.OriginalName)
+		}
+	}
+
+	if detail.metadata != "" {
+		field("Метаинформация", detail.metadata)
+	}
+	if !detail.info.UpdatedAt.IsZero() {
+		field("Изменено", detail.info.UpdatedAt.Local().Format("2006-01-02 15:04"))
+	}
+
+	if detail.loaded && detail.err == nil && detail.info.Type != domain.UserDataTypeFile {
+		if a.reveal {
+			b.WriteString("\n[gray]r - скрыть секреты[-]")
+		} else {
+			b.WriteString("\n[gray]r - показать секреты[-]")
+		}
+	}
+	a.details.SetText(b.String()).ScrollToBeginning()
+}
+
+// hasCode сообщает, показывает ли запись код TOTP, который нужно обновлять
+func (d *itemDetail) hasCode() bool {
+	return d != nil && d.credential != nil && d.credential.TOTP != ""
+}
+
+// sameItem сравнивает записи по типу, метке и времени изменения
+func sameItem(a domain.ItemInfo, b domain.ItemInfo) bool {
+	return a.Type == b.Type && a.Label == b.Label && a.UpdatedAt.Equal(b.UpdatedAt)
+}
+
+// maskCardNumber скрывает номер карты, кроме последних четырех цифр
+func maskCardNumber(number string) string {
+	digits := []rune(strings.ReplaceAll(number, " ", ""))
+	if len(digits) <= 4 {
+		return mask
+	}
+	return "•••• " + string(digits[len(digits)-4:])
+}
+
+// typeTitle возвращает название типа записи
+func (a *App) typeTitle(itemType string) string {
+	if itemType == domain.UserDataTypeFile {
+		return "файл"
+	}
+	if t, err := a.itemType(itemType); err == nil {
+		return t.Title
+	}
+	return itemType
+}
+
+// itemType возвращает встроенный тип или пользовательский тип, загруженный вместе со списком записей.
+// В отличие от ClientUseCase.ItemType не обращается к серверу, поэтому вызывается в потоке интерфейса
+func (a *App) itemType(name string) (*itemtype.Type, error) {
+	if t, ok := a.customTypes[name]; ok {
+		return t, nil
+	}
+	return itemtype.Lookup(name)
+}
+
+// formatProgress форматирует ход передачи файла, нулевой total означает неизвестный размер
+func formatProgress(done int64, total int64) string {
+	if total <= 0 {
+		return pkg.FormatBytes(done)
+	}
+	return fmt.Sprintf("%d%% (%s из %s)", done*100/total, pkg.FormatBytes(done), pkg.FormatBytes(total))
+}
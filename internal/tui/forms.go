@@ -0,0 +1,433 @@
+package tui
+
+import (
+	"errors"
+	"fmt"
+	"github.com/SmirnovND/gophkeeper/internal/domain"
+	"github.com/SmirnovND/gophkeeper/internal/itemtype"
+	"github.com/SmirnovND/gophkeeper/internal/totp"
+	"github.com/SmirnovND/gophkeeper/pkg"
+	"github.com/rivo/tview"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Подписи полей форм, по ним же значения читаются из формы
+const (
+	fieldLabel    = "Метка"
+	fieldLogin    = "Логин"
+	fieldPassword = "Пароль"
+	fieldTOTP     = "Ключ TOTP"
+	fieldNumber   = "Номер"
+	fieldHolder   = "Владелец"
+	fieldExpiry   = "Срок действия"
+	fieldCVV      = "CVV"
+	fieldText     = "Текст"
+	fieldKey      = "Закрытый ключ"
+	fieldComment  = "Комментарий"
+	fieldPath     = "Путь к файлу"
+	fieldMetadata = "Метаинформация"
+)
+
+// Размеры окна формы
+const (
+	formWidth      = 72
+	textAreaHeight = 8
+)
+
+// itemTypes - типы записей в порядке кнопок окна создания записи
+var itemTypes = []string{
+	domain.UserDataTypeCredential,
+	domain.UserDataTypeCard,
+	domain.UserDataTypeText,
+	domain.UserDataTypeFile,
+	domain.UserDataTypeSSHKey,
+}
+
+// showNewItem предлагает выбрать тип новой записи и показывает ее форму
+func (a *App) showNewItem() {
+	modal := tview.NewModal().
+		SetText("Тип новой записи").
+		AddButtons([]string{"Учетные данные", "Карта", "Текст", "Файл", "Ключ SSH", "Другой тип", "Отмена"}).
+		SetDoneFunc(func(index int, buttonLabel string) {
+			a.closeDialog(pageConfirm)
+			switch {
+			case index >= 0 && index < len(itemTypes):
+				a.showItemForm(itemTypes[index], nil)
+			case index == len(itemTypes):
+				a.showNewStructuredItem()
+			}
+		})
+	a.pages.AddPage(pageConfirm, modal, true, true)
+	a.app.SetFocus(modal)
+}
+
+// showNewStructuredItem предлагает выбрать структурированный тип новой записи, в том числе пользовательский
+func (a *App) showNewStructuredItem() {
+	// Для встроенных типов есть собственные формы в первом диалоге
+	types := make([]*itemtype.Type, 0)
+	for _, t := range itemtype.Types() {
+		if !t.Builtin {
+			types = append(types, t)
+		}
+	}
+	custom := make([]*itemtype.Type, 0, len(a.customTypes))
+	for _, t := range a.customTypes {
+		custom = append(custom, t)
+	}
+	sort.Slice(custom, func(i, j int) bool { return custom[i].Name < custom[j].Name })
+	types = append(types, custom...)
+	buttons := make([]string, 0, len(types)+1)
+	for _, t := range types {
+		buttons = append(buttons, t.Title)
+	}
+	modal := tview.NewModal().
+		SetText("Тип новой записи").
+		AddButtons(append(buttons, "Отмена")).
+		SetDoneFunc(func(index int, buttonLabel string) {
+			a.closeDialog(pageConfirm)
+			if index >= 0 && index < len(types) {
+				a.showItemForm(types[index].Name, nil)
+			}
+		})
+	a.pages.AddPage(pageConfirm, modal, true, true)
+	a.app.SetFocus(modal)
+}
+
+// showEditItem показывает форму изменения выбранной записи, заполненную ее содержимым
+func (a *App) showEditItem() {
+	if a.detail == nil {
+		return
+	}
+	if !a.detail.loaded || a.detail.err != nil {
+		a.setStatus("[yellow]Содержимое записи еще не загружено[-]")
+		return
+	}
+	a.showItemForm(a.detail.info.Type, a.detail)
+}
+
+// showItemForm показывает форму записи типа itemType. Пустой detail означает создание записи
+func (a *App) showItemForm(itemType string, detail *itemDetail) {
+	editing := detail != nil
+	form := tview.NewForm()
+
+	label, metadata := "", ""
+	if editing {
+		label, metadata = detail.info.Label, detail.metadata
+	}
+
+	// Высота окна: рамка, отступы, кнопки и поля с промежутками между ними
+	height := 7
+	addInput := func(name string, value string) {
+		form.AddInputField(name, value, 0, nil, nil)
+		height += 2
+	}
+	addSecret := func(name string, value string) {
+		form.AddPasswordField(name, value, 0, '*', nil)
+		height += 2
+	}
+
+	title := a.typeTitle(itemType)
+	switch itemType {
+	case domain.UserDataTypeCredential:
+		data := domain.CredentialData{}
+		if editing && detail.credential != nil {
+			data = *detail.credential
+		}
+		addInput(fieldLabel, label)
+		addInput(fieldLogin, data.Login)
+		addSecret(fieldPassword, data.Password)
+		addSecret(fieldTOTP, data.TOTP)
+		addInput(fieldMetadata, metadata)
+	case domain.UserDataTypeCard:
+		data := domain.CardData{}
+		if editing && detail.card != nil {
+			data = *detail.card
+		}
+		addInput(fieldLabel, label)
+		addSecret(fieldNumber, data.Number)
+		addInput(fieldHolder, data.Holder)
+		addInput(fieldExpiry, data.ExpiryDate)
+		addSecret(fieldCVV, data.CVV)
+		addInput(fieldMetadata, metadata)
+	case domain.UserDataTypeText:
+		data := domain.TextData{}
+		if editing && detail.text != nil {
+			data = *detail.text
+		}
+		addInput(fieldLabel, label)
+		form.AddTextArea(fieldText, data.Content, 0, textAreaHeight, 0, nil)
+		height += textAreaHeight + 1
+		addInput(fieldMetadata, metadata)
+	case domain.UserDataTypeSSHKey:
+		data := domain.SSHKeyData{}
+		if editing && detail.sshKey != nil {
+			data = *detail.sshKey
+		}
+		addInput(fieldLabel, label)
+		form.AddTextArea(fieldKey, data.PrivateKey, 0, textAreaHeight, 0, nil)
+		height += textAreaHeight + 1
+		addInput(fieldComment, data.Comment)
+		addInput(fieldMetadata, metadata)
+	case domain.UserDataTypeFile:
+		// У загруженного файла можно изменить только метку
+		if !editing {
+			addInput(fieldPath, "")
+		}
+		addInput(fieldLabel, label)
+		if !editing {
+			addInput(fieldMetadata, "")
+		}
+	default:
+		t, err := a.itemType(itemType)
+		if err != nil {
+			a.setError(err)
+			return
+		}
+		data := domain.ItemData{}
+		if editing && detail.item != nil {
+			data = detail.item
+		}
+		addInput(fieldLabel, label)
+		for _, field := range t.Fields {
+			switch field.Kind {
+			case itemtype.KindSecret:
+				addSecret(field.Title, data[field.Name])
+			case itemtype.KindEnum:
+				// Необязательное перечисление можно оставить пустым
+				options := field.Options
+				if !field.Required {
+					options = append([]string{""}, options...)
+				}
+				current := 0
+				for i, option := range options {
+					if option == data[field.Name] {
+						current = i
+					}
+				}
+				form.AddDropDown(field.Title, options, current, nil)
+				height += 2
+			default:
+				addInput(field.Title, data[field.Name])
+			}
+		}
+		addInput(fieldMetadata, metadata)
+	}
+
+	form.AddButton("Сохранить", func() { a.saveItem(form, itemType, detail) })
+	form.AddButton("Отмена", func() { a.closeDialog(pageDialog) })
+	form.SetCancelFunc(func() { a.closeDialog(pageDialog) })
+
+	if editing {
+		title = "Изменение: " + title
+	} else {
+		title = "Новая запись: " + title
+	}
+	form.SetBorder(true).SetTitle(" " + title + " ")
+	a.showDialog(pageDialog, form, formWidth, height)
+}
+
+// saveItem сохраняет запись из формы. При изменении метки запись сохраняется с новой меткой,
+// а старая удаляется только после успешного сохранения, чтобы при ошибке данные не потерялись
+func (a *App) saveItem(form *tview.Form, itemType string, detail *itemDetail) {
+	value := func(name string) string {
+		switch item := form.GetFormItemByLabel(name).(type) {
+		case *tview.InputField:
+			return item.GetText()
+		case *tview.TextArea:
+			return item.GetText()
+		case *tview.DropDown:
+			_, option := item.GetCurrentOption()
+			return option
+		}
+		return ""
+	}
+
+	label := strings.TrimSpace(value(fieldLabel))
+	if label == "" {
+		a.setError(errors.New("не указана метка"))
+		return
+	}
+	oldLabel := ""
+	if detail != nil {
+		oldLabel = detail.info.Label
+	}
+	if _, exists := a.findItem(label); exists && label != oldLabel {
+		a.setError(fmt.Errorf("метка '%s' уже используется", label))
+		return
+	}
+	metadata := value(fieldMetadata)
+
+	var work func() error
+	message := "Сохранение..."
+	switch itemType {
+	case domain.UserDataTypeCredential:
+		data := &domain.CredentialData{Login: value(fieldLogin), Password: value(fieldPassword), TOTP: strings.TrimSpace(value(fieldTOTP))}
+		// Адреса и дополнительные поля в форме не редактируются и сохраняются без изменений
+		if detail != nil && detail.credential != nil {
+			data.URIs, data.Fields = detail.credential.URIs, detail.credential.Fields
+		}
+		if data.TOTP != "" {
+			if _, err := totp.Parse(data.TOTP); err != nil {
+				a.setError(err)
+				return
+			}
+		}
+		work = func() error { return a.clientUseCase.SaveCredential(label, data, metadata) }
+	case domain.UserDataTypeCard:
+		data := &domain.CardData{
+			Number:     value(fieldNumber),
+			Holder:     value(fieldHolder),
+			ExpiryDate: value(fieldExpiry),
+			CVV:        value(fieldCVV),
+		}
+		work = func() error { return a.clientUseCase.SaveCard(label, data, metadata) }
+	case domain.UserDataTypeText:
+		data := &domain.TextData{Content: value(fieldText)}
+		work = func() error { return a.clientUseCase.SaveText(label, data, metadata) }
+	case domain.UserDataTypeSSHKey:
+		// Открытый ключ и отпечаток заполняются по закрытому ключу. Прежний открытый ключ передается, пока закрытый
+		// ключ не изменен: без него нельзя сохранить зашифрованный ключ PEM
+		data := &domain.SSHKeyData{PrivateKey: value(fieldKey), Comment: strings.TrimSpace(value(fieldComment))}
+		if detail != nil && detail.sshKey != nil && detail.sshKey.PrivateKey == data.PrivateKey {
+			data.PublicKey = detail.sshKey.PublicKey
+		}
+		work = func() error { return a.clientUseCase.SaveSSHKey(label, data, metadata) }
+	case domain.UserDataTypeFile:
+		if detail != nil {
+			if label == oldLabel {
+				a.closeDialog(pageDialog)
+				return
+			}
+			work = func() error { return a.clientUseCase.RenameFile(oldLabel, label) }
+			break
+		}
+
+		path := strings.TrimSpace(value(fieldPath))
+		if path == "" {
+			a.setError(errors.New("не указан путь к файлу"))
+			return
+		}
+		message = "Загрузка файла..."
+		work = func() error {
+			_, err := a.clientUseCase.Upload(path, label, metadata)
+			return err
+		}
+	default:
+		t, err := a.itemType(itemType)
+		if err != nil {
+			a.setError(err)
+			return
+		}
+		data := domain.ItemData{}
+		for _, field := range t.Fields {
+			if v := strings.TrimSpace(value(field.Title)); v != "" {
+				data[field.Name] = v
+			}
+		}
+		// Значения проверяются до отправки, чтобы ошибка показывалась в открытой форме
+		if err := t.Validate(data); err != nil {
+			a.setError(err)
+			return
+		}
+		work = func() error { return a.clientUseCase.SaveItem(itemType, label, data, metadata) }
+	}
+
+	if detail != nil && itemType != domain.UserDataTypeFile && label != oldLabel {
+		save := work
+		work = func() error {
+			if err := save(); err != nil {
+				return err
+			}
+			return a.deleteItem(domain.ItemInfo{Type: itemType, Label: oldLabel})
+		}
+	}
+
+	a.background(message, work, func() {
+		a.closeDialog(pageDialog)
+		a.refresh(label, "Запись '"+tview.Escape(label)+"' сохранена")
+	})
+}
+
+// showDeleteItem запрашивает подтверждение и удаляет выбранную запись
+func (a *App) showDeleteItem() {
+	item, ok := a.current()
+	if !ok {
+		return
+	}
+
+	modal := tview.NewModal().
+		SetText(fmt.Sprintf("Удалить запись '%s' (%s)?", tview.Escape(item.Label), a.typeTitle(item.Type))).
+		AddButtons([]string{"Удалить", "Отмена"}).
+		SetDoneFunc(func(index int, buttonLabel string) {
+			a.closeDialog(pageConfirm)
+			if index != 0 {
+				return
+			}
+			a.background("Удаление...", func() error {
+				return a.deleteItem(item)
+			}, func() {
+				a.refresh("", "Запись '"+tview.Escape(item.Label)+"' удалена")
+			})
+		})
+	a.pages.AddPage(pageConfirm, modal, true, true)
+	a.app.SetFocus(modal)
+}
+
+// deleteItem удаляет запись на сервере
+func (a *App) deleteItem(item domain.ItemInfo) error {
+	switch item.Type {
+	case domain.UserDataTypeCredential:
+		return a.clientUseCase.DeleteCredential(item.Label)
+	case domain.UserDataTypeCard:
+		return a.clientUseCase.DeleteCard(item.Label)
+	case domain.UserDataTypeText:
+		return a.clientUseCase.DeleteText(item.Label)
+	case domain.UserDataTypeSSHKey:
+		return a.clientUseCase.DeleteSSHKey(item.Label)
+	case domain.UserDataTypeFile:
+		return a.clientUseCase.DeleteFile(item.Label)
+	default:
+		if _, err := a.clientUseCase.ItemType(item.Type); err != nil {
+			return err
+		}
+		return a.clientUseCase.DeleteItem(item.Type, item.Label)
+	}
+}
+
+// showDownload предлагает путь и скачивает выбранный файл
+func (a *App) showDownload() {
+	item, ok := a.current()
+	if !ok {
+		return
+	}
+	if item.Type != domain.UserDataTypeFile {
+		a.setStatus("[yellow]Скачать можно только файл[-]")
+		return
+	}
+
+	name := item.Label
+	if a.detail != nil && a.detail.file != nil && a.detail.file.Extension != "" {
+		name += "." + a.detail.file.Extension
+	}
+
+	form := tview.NewForm().AddInputField(fieldPath, filepath.Join(pkg.GetDownloadsDir(), name), 0, nil, nil)
+	form.AddButton("Скачать", func() {
+		path := strings.TrimSpace(form.GetFormItemByLabel(fieldPath).(*tview.InputField).GetText())
+		if path == "" {
+			a.setError(errors.New("не указан путь к файлу"))
+			return
+		}
+		a.background("Скачивание файла...", func() error {
+			return a.clientUseCase.Download(item.Label, path)
+		}, func() {
+			a.closeDialog(pageDialog)
+			a.setStatus("Файл сохранен в '" + tview.Escape(path) + "'")
+		})
+	})
+	form.AddButton("Отмена", func() { a.closeDialog(pageDialog) })
+	form.SetCancelFunc(func() { a.closeDialog(pageDialog) })
+	form.SetBorder(true).SetTitle(" Скачивание: " + tview.Escape(item.Label) + " ")
+	a.showDialog(pageDialog, form, formWidth, 9)
+}
@@ -0,0 +1,351 @@
+package tui
+
+import (
+	"fmt"
+	"github.com/SmirnovND/gophkeeper/internal/domain"
+	"github.com/SmirnovND/gophkeeper/internal/interfaces"
+	"github.com/SmirnovND/gophkeeper/internal/itemtype"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"os"
+	"strings"
+	"time"
+)
+
+// Подсказка по клавишам в строке состояния
+const helpText = "/ поиск  n новая  e правка  d удалить  r секреты  s скачать  ^R обновить  q выход"
+
+// Имена страниц поверх основного экрана
+const (
+	pageMain    = "main"
+	pageDialog  = "dialog"
+	pageConfirm = "confirm"
+)
+
+// App - полноэкранный интерфейс passcli: список записей с поиском, просмотр записи со скрытыми секретами
+// и формы создания, изменения и удаления записей. Все обращения к серверу выполняются через ClientUseCase
+type App struct {
+	clientUseCase interfaces.ClientUseCase
+
+	app     *tview.Application
+	pages   *tview.Pages
+	search  *tview.InputField
+	list    *tview.List
+	details *tview.TextView
+	status  *tview.TextView
+
+	// items - все записи пользователя, visible - записи, подходящие под строку поиска
+	items   []domain.ItemInfo
+	visible []domain.ItemInfo
+	// customTypes - пользовательские типы из шаблонов, загружаются вместе со списком записей
+	customTypes map[string]*itemtype.Type
+	// detail - содержимое выбранной записи, reveal - показаны ли секреты
+	detail *itemDetail
+	reveal bool
+	// busy - выполняется запрос к серверу, следующий запрос можно начать только после его завершения
+	busy bool
+	// lastProgress - последний показанный ход передачи файла, меняется только в фоновой операции
+	lastProgress int64
+}
+
+// New создает интерфейс, работающий с сервером через clientUseCase
+func New(clientUseCase interfaces.ClientUseCase) *App {
+	a := &App{clientUseCase: clientUseCase, app: tview.NewApplication()}
+
+	a.search = tview.NewInputField().SetLabel("Поиск: ").SetFieldBackgroundColor(tcell.ColorDefault)
+	a.search.SetChangedFunc(func(text string) { a.filter() })
+	a.search.SetDoneFunc(func(key tcell.Key) { a.app.SetFocus(a.list) })
+	a.search.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyDown {
+			a.app.SetFocus(a.list)
+			return nil
+		}
+		return event
+	})
+
+	a.list = tview.NewList().ShowSecondaryText(false).SetHighlightFullLine(true)
+	a.list.SetBorder(true).SetTitle(" Записи ")
+	a.list.SetChangedFunc(func(index int, mainText string, secondaryText string, shortcut rune) { a.selectItem() })
+	a.list.SetInputCapture(a.listKeys)
+
+	a.details = tview.NewTextView().SetDynamicColors(true).SetWrap(true)
+	a.details.SetBorder(true).SetTitle(" Запись ")
+
+	a.status = tview.NewTextView().SetDynamicColors(true)
+	a.setStatus(helpText)
+
+	body := tview.NewFlex().
+		AddItem(a.list, 0, 1, true).
+		AddItem(a.details, 0, 2, false)
+	layout := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(a.search, 1, 0, false).
+		AddItem(body, 0, 1, true).
+		AddItem(a.status, 1, 0, false)
+
+	a.pages = tview.NewPages().AddPage(pageMain, layout, true, true)
+	a.app.SetRoot(a.pages, true).SetFocus(a.list)
+	return a
+}
+
+// Run показывает интерфейс и возвращается после выхода из него. Пустой screen означает терминал пользователя
+func (a *App) Run(screen tcell.Screen) error {
+	if screen != nil {
+		a.app.SetScreen(screen)
+	}
+
+	// Сценарии ClientUseCase сообщают о ходе работы в stdout, поверх интерфейса эти сообщения не выводим
+	stdout := os.Stdout
+	if devNull, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0); err == nil {
+		os.Stdout = devNull
+		defer func() {
+			os.Stdout = stdout
+			devNull.Close()
+		}()
+	}
+
+	a.clientUseCase.SetProgress(a.progress)
+	defer a.clientUseCase.SetProgress(nil)
+
+	stopCodes := a.tickCodes()
+	defer stopCodes()
+
+	a.refresh("", helpText)
+	return a.app.Run()
+}
+
+// Stop закрывает интерфейс
+func (a *App) Stop() {
+	a.app.Stop()
+}
+
+// tickCodes раз в секунду перерисовывает запись с кодом TOTP, чтобы код и время до его смены были актуальными.
+// Возвращает функцию остановки обновления
+func (a *App) tickCodes() func() {
+	ticker := time.NewTicker(time.Second)
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				a.app.QueueUpdateDraw(func() {
+					if a.detail.hasCode() {
+						a.renderDetail()
+					}
+				})
+			case <-done:
+				return
+			}
+		}
+	}()
+	return func() {
+		ticker.Stop()
+		close(done)
+	}
+}
+
+// listKeys обрабатывает клавиши команд в списке записей
+func (a *App) listKeys(event *tcell.EventKey) *tcell.EventKey {
+	switch event.Key() {
+	case tcell.KeyTab:
+		a.app.SetFocus(a.search)
+		return nil
+	case tcell.KeyCtrlR:
+		a.refresh(a.currentLabel(), helpText)
+		return nil
+	case tcell.KeyRune:
+	default:
+		return event
+	}
+
+	switch event.Rune() {
+	case '/':
+		a.app.SetFocus(a.search)
+	case 'q':
+		a.app.Stop()
+	case 'n':
+		a.showNewItem()
+	case 'e':
+		a.showEditItem()
+	case 'd':
+		a.showDeleteItem()
+	case 's':
+		a.showDownload()
+	case 'r':
+		a.reveal = !a.reveal
+		a.renderDetail()
+	default:
+		return event
+	}
+	return nil
+}
+
+// setStatus выводит сообщение в строке состояния
+func (a *App) setStatus(message string) {
+	a.status.SetText(message)
+}
+
+// setError выводит ошибку в строке состояния
+func (a *App) setError(err error) {
+	a.status.SetText("[red]Ошибка: " + tview.Escape(err.Error()) + "[-]")
+}
+
+// background выполняет запрос к серверу вне потока интерфейса и вызывает done в потоке интерфейса,
+// если запрос выполнен без ошибки. Пока запрос выполняется, новые запросы не начинаются
+func (a *App) background(message string, work func() error, done func()) {
+	if a.busy {
+		a.setStatus("[yellow]Дождитесь завершения текущей операции[-]")
+		return
+	}
+	a.busy = true
+	a.lastProgress = -1
+	a.setStatus(message)
+
+	go func() {
+		err := work()
+		a.app.QueueUpdateDraw(func() {
+			a.busy = false
+			if err != nil {
+				a.setError(err)
+				return
+			}
+			a.setStatus(helpText)
+			if done != nil {
+				done()
+			}
+		})
+	}()
+}
+
+// progress показывает ход загрузки или скачивания файла, обновляя строку состояния не чаще раза на процент
+func (a *App) progress(done int64, total int64) {
+	step := done >> 18
+	if total > 0 {
+		step = done * 100 / total
+	}
+	if step == a.lastProgress {
+		return
+	}
+	a.lastProgress = step
+
+	message := "Передача файла: " + formatProgress(done, total)
+	a.app.QueueUpdateDraw(func() {
+		if a.busy {
+			a.setStatus(message)
+		}
+	})
+}
+
+// refresh перечитывает список записей, выбирает запись с меткой label, если она есть, и выводит message
+func (a *App) refresh(label string, message string) {
+	var items []domain.ItemInfo
+	customTypes := make(map[string]*itemtype.Type)
+	a.background("Загрузка списка записей...", func() error {
+		var err error
+		items, err = a.clientUseCase.ListItems()
+		if err != nil {
+			return err
+		}
+		templates, err := a.clientUseCase.ListItemTemplates()
+		if err != nil {
+			return err
+		}
+		for _, template := range templates {
+			t, err := itemtype.ParseSchema(template.Name, template.Schema)
+			if err != nil {
+				return err
+			}
+			customTypes[t.Name] = t
+		}
+		return nil
+	}, func() {
+		a.items, a.customTypes = items, customTypes
+		a.filter()
+		a.selectLabel(label)
+		a.setStatus(message)
+	})
+}
+
+// filter показывает записи, метка, тип или метаинформация которых содержат строку поиска
+func (a *App) filter() {
+	query := strings.ToLower(strings.TrimSpace(a.search.GetText()))
+	current := a.currentLabel()
+
+	a.visible = a.visible[:0]
+	for _, item := range a.items {
+		if query == "" ||
+			strings.Contains(strings.ToLower(item.Label), query) ||
+			strings.Contains(strings.ToLower(item.Type), query) ||
+			strings.Contains(strings.ToLower(a.typeTitle(item.Type)), query) ||
+			strings.Contains(strings.ToLower(item.Metadata), query) {
+			a.visible = append(a.visible, item)
+		}
+	}
+
+	// AddItem вызывает обработчик смены записи для первой записи, поэтому выбор восстанавливаем после заполнения списка
+	a.list.Clear()
+	for _, item := range a.visible {
+		a.list.AddItem(fmt.Sprintf("%-10s %s", item.Type, tview.Escape(item.Label)), "", 0, nil)
+	}
+	a.selectLabel(current)
+}
+
+// selectLabel выбирает в списке запись с меткой label, а если ее нет - первую запись
+func (a *App) selectLabel(label string) {
+	index := 0
+	for i, item := range a.visible {
+		if item.Label == label {
+			index = i
+			break
+		}
+	}
+	if len(a.visible) > 0 {
+		a.list.SetCurrentItem(index)
+	}
+	a.selectItem()
+}
+
+// currentLabel возвращает метку выбранной записи
+func (a *App) currentLabel() string {
+	if item, ok := a.current(); ok {
+		return item.Label
+	}
+	return ""
+}
+
+// current возвращает выбранную запись
+func (a *App) current() (domain.ItemInfo, bool) {
+	index := a.list.GetCurrentItem()
+	if index < 0 || index >= len(a.visible) || a.list.GetItemCount() != len(a.visible) {
+		return domain.ItemInfo{}, false
+	}
+	return a.visible[index], true
+}
+
+// findItem ищет запись с меткой label среди всех записей
+func (a *App) findItem(label string) (domain.ItemInfo, bool) {
+	for _, item := range a.items {
+		if item.Label == label {
+			return item, true
+		}
+	}
+	return domain.ItemInfo{}, false
+}
+
+// showDialog показывает примитив p по центру экрана поверх основного экрана
+func (a *App) showDialog(name string, p tview.Primitive, width int, height int) {
+	centered := tview.NewFlex().
+		AddItem(nil, 0, 1, false).
+		AddItem(tview.NewFlex().SetDirection(tview.FlexRow).
+			AddItem(nil, 0, 1, false).
+			AddItem(p, height, 0, true).
+			AddItem(nil, 0, 1, false), width, 0, true).
+		AddItem(nil, 0, 1, false)
+	a.pages.AddPage(name, centered, true, true)
+	a.app.SetFocus(p)
+}
+
+// closeDialog закрывает окно поверх основного экрана и возвращает фокус списку
+func (a *App) closeDialog(name string) {
+	a.pages.RemovePage(name)
+	a.app.SetFocus(a.list)
+}
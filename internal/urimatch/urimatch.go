@@ -0,0 +1,142 @@
+// Package urimatch сравнивает адрес страницы с адресами учетных данных по правилам сравнения
+package urimatch
+
+import (
+	"errors"
+	"fmt"
+	"github.com/SmirnovND/gophkeeper/internal/domain"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// Правила сравнения адреса учетных данных с адресом страницы
+const (
+	RuleDomain     = "domain"      // Хост страницы совпадает с хостом адреса или является его поддоменом
+	RuleHost       = "host"        // Совпадают хост и порт
+	RuleStartsWith = "starts_with" // Адрес страницы начинается с адреса учетных данных
+	RuleExact      = "exact"       // Адреса совпадают полностью
+	RuleRegex      = "regex"       // Адрес страницы соответствует регулярному выражению
+	RuleNever      = "never"       // Адрес хранится для справки и ни с чем не совпадает
+)
+
+// Rules - допустимые правила сравнения, первое из них применяется по умолчанию
+var Rules = []string{RuleDomain, RuleHost, RuleStartsWith, RuleExact, RuleRegex, RuleNever}
+
+// maxURILength - максимальная длина адреса или регулярного выражения в байтах
+const maxURILength = 2048
+
+// ErrInvalidURI - адрес учетных данных или его правило сравнения заданы неверно
+var ErrInvalidURI = errors.New("неверный адрес учетных данных")
+
+// defaultPorts - порты схем, которые можно не указывать в адресе
+var defaultPorts = map[string]string{"http": "80", "https": "443", "ftp": "21", "ssh": "22"}
+
+// Validate проверяет адрес и правило сравнения: правило должно быть известным, адрес правил domain и host -
+// содержать хост, а регулярное выражение - компилироваться
+func Validate(uri domain.CredentialURI) error {
+	if uri.URI == "" {
+		return fmt.Errorf("%w: пустой адрес", ErrInvalidURI)
+	}
+	if len(uri.URI) > maxURILength {
+		return fmt.Errorf("%w: адрес длиннее %d байт", ErrInvalidURI, maxURILength)
+	}
+	switch uri.Match {
+	case "", RuleDomain, RuleHost:
+		if _, err := parse(uri.URI); err != nil {
+			return fmt.Errorf("%w: '%s': %v", ErrInvalidURI, uri.URI, err)
+		}
+	case RuleRegex:
+		if _, err := regexp.Compile(uri.URI); err != nil {
+			return fmt.Errorf("%w: '%s': %v", ErrInvalidURI, uri.URI, err)
+		}
+	case RuleStartsWith, RuleExact, RuleNever:
+	default:
+		return fmt.Errorf("%w: неизвестное правило '%s', допустимы %s", ErrInvalidURI, uri.Match, strings.Join(Rules, ", "))
+	}
+	return nil
+}
+
+// Match сообщает, подходит ли адрес учетных данных uri для адреса страницы target.
+// Неверный адрес или регулярное выражение ни с чем не совпадают
+func Match(uri domain.CredentialURI, target string) bool {
+	switch uri.Match {
+	case "", RuleDomain:
+		want, err := parse(uri.URI)
+		if err != nil {
+			return false
+		}
+		got, err := parse(target)
+		if err != nil {
+			return false
+		}
+		host, page := trimWWW(want.Hostname()), trimWWW(got.Hostname())
+		return page == host || strings.HasSuffix(page, "."+host)
+	case RuleHost:
+		want, err := parse(uri.URI)
+		if err != nil {
+			return false
+		}
+		got, err := parse(target)
+		if err != nil {
+			return false
+		}
+		return want.Hostname() == got.Hostname() && port(want) == port(got)
+	case RuleStartsWith:
+		return strings.HasPrefix(target, uri.URI)
+	case RuleExact:
+		return target == uri.URI
+	case RuleRegex:
+		pattern, err := regexp.Compile(uri.URI)
+		return err == nil && pattern.MatchString(target)
+	default:
+		return false
+	}
+}
+
+// SameScheme сообщает, совпадает ли схема адреса учетных данных uri со схемой адреса target. Адрес без схемы
+// считается адресом https. Регулярное выражение сравнивается с адресом целиком вместе со схемой,
+// поэтому для правила regex схема отдельно не проверяется
+func SameScheme(uri domain.CredentialURI, target string) bool {
+	if uri.Match == RuleRegex {
+		return true
+	}
+	want, err := parse(uri.URI)
+	if err != nil {
+		return false
+	}
+	got, err := parse(target)
+	if err != nil {
+		return false
+	}
+	return strings.EqualFold(want.Scheme, got.Scheme)
+}
+
+// parse разбирает адрес; адрес без схемы, например github.com, считается адресом https
+func parse(raw string) (*url.URL, error) {
+	if !strings.Contains(raw, "://") {
+		raw = "https://" + raw
+	}
+	parsed, err := url.Parse(raw)
+	if err != nil {
+		return nil, err
+	}
+	if parsed.Hostname() == "" {
+		return nil, errors.New("в адресе нет хоста")
+	}
+	parsed.Host = strings.ToLower(parsed.Host)
+	return parsed, nil
+}
+
+// trimWWW убирает префикс www., чтобы www.example.com и example.com считались одним доменом
+func trimWWW(host string) string {
+	return strings.TrimPrefix(host, "www.")
+}
+
+// port возвращает порт адреса, а если он не указан - порт его схемы по умолчанию
+func port(u *url.URL) string {
+	if p := u.Port(); p != "" {
+		return p
+	}
+	return defaultPorts[strings.ToLower(u.Scheme)]
+}
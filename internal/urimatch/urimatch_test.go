@@ -0,0 +1,91 @@
+package urimatch
+
+import (
+	"errors"
+	"github.com/SmirnovND/gophkeeper/internal/domain"
+	"testing"
+)
+
+// TestMatch тестирует сравнение адреса страницы с адресом учетных данных по каждому правилу
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		uri    string
+		match  string
+		target string
+		want   bool
+	}{
+		{"https://github.com", "", "https://github.com/login", true},
+		{"github.com", RuleDomain, "https://gist.github.com/alice", true},
+		{"https://www.github.com", RuleDomain, "http://GitHub.com:8080/", true},
+		{"https://github.com", RuleDomain, "https://notgithub.com", false},
+		{"https://github.com", RuleDomain, "https://github.com.evil.io", false},
+		{"https://git.corp:8443", RuleHost, "https://git.corp:8443/repo", true},
+		{"https://git.corp", RuleHost, "https://git.corp:443/repo", true},
+		{"https://git.corp:8443", RuleHost, "https://git.corp/repo", false},
+		{"https://git.corp", RuleHost, "https://api.git.corp", false},
+		{"https://bank.example/online/", RuleStartsWith, "https://bank.example/online/login", true},
+		{"https://bank.example/online/", RuleStartsWith, "https://bank.example/offline", false},
+		{"https://bank.example/login", RuleExact, "https://bank.example/login", true},
+		{"https://bank.example/login", RuleExact, "https://bank.example/login?next=/", false},
+		{`^https://[a-z]+\.corp\.example/`, RuleRegex, "https://jira.corp.example/browse", true},
+		{`^https://[a-z]+\.corp\.example/`, RuleRegex, "https://corp.example/", false},
+		{"https://github.com", RuleNever, "https://github.com", false},
+		{"https://github.com", "unknown", "https://github.com", false},
+	}
+	for _, tt := range tests {
+		if got := Match(domain.CredentialURI{URI: tt.uri, Match: tt.match}, tt.target); got != tt.want {
+			t.Errorf("Match(%s, %s, %s) = %v, ожидалось %v", tt.uri, tt.match, tt.target, got, tt.want)
+		}
+	}
+}
+
+// TestSameScheme тестирует сравнение схем адреса учетных данных и адреса страницы
+func TestSameScheme(t *testing.T) {
+	tests := []struct {
+		uri    string
+		match  string
+		target string
+		want   bool
+	}{
+		{"https://github.com", RuleDomain, "https://github.com/org/repo.git", true},
+		{"https://github.com", RuleDomain, "http://github.com/org/repo.git", false},
+		{"github.com", "", "http://github.com", false},
+		{"github.com", "", "HTTPS://github.com", true},
+		{"http://git.local", RuleHost, "http://git.local", true},
+		{"https://bank.example/online/", RuleStartsWith, "http://bank.example/online/", false},
+		{`^http://git\.local/`, RuleRegex, "http://git.local/", true},
+	}
+	for _, tt := range tests {
+		if got := SameScheme(domain.CredentialURI{URI: tt.uri, Match: tt.match}, tt.target); got != tt.want {
+			t.Errorf("SameScheme(%s, %s, %s) = %v, ожидалось %v", tt.uri, tt.match, tt.target, got, tt.want)
+		}
+	}
+}
+
+// TestValidate тестирует отказ от неизвестных правил, адресов без хоста и неверных регулярных выражений
+func TestValidate(t *testing.T) {
+	valid := []domain.CredentialURI{
+		{URI: "https://github.com"},
+		{URI: "github.com", Match: RuleHost},
+		{URI: "https://bank.example/online/", Match: RuleStartsWith},
+		{URI: `^https://.*\.corp/`, Match: RuleRegex},
+		{URI: "android://com.example.app", Match: RuleNever},
+	}
+	for _, uri := range valid {
+		if err := Validate(uri); err != nil {
+			t.Errorf("Неожиданная ошибка для %+v: %v", uri, err)
+		}
+	}
+
+	invalid := []domain.CredentialURI{
+		{URI: ""},
+		{URI: "https://", Match: RuleDomain},
+		{URI: "https://github.com", Match: "wildcard"},
+		{URI: "^https://(", Match: RuleRegex},
+	}
+	for _, uri := range invalid {
+		if err := Validate(uri); !errors.Is(err, ErrInvalidURI) {
+			t.Errorf("Для %+v ожидалась ошибка %v, получено %v", uri, ErrInvalidURI, err)
+		}
+	}
+}
@@ -10,15 +10,18 @@ import (
 type AuthUseCase struct {
 	userService interfaces.UserService
 	authService interfaces.AuthService
+	keyService  interfaces.KeyService
 }
 
 func NewAuthUseCase(
 	UserService interfaces.UserService,
 	AuthService interfaces.AuthService,
+	KeyService interfaces.KeyService,
 ) interfaces.AuthUseCase {
 	return &AuthUseCase{
 		userService: UserService,
 		authService: AuthService,
+		keyService:  KeyService,
 	}
 }
 
@@ -45,6 +48,13 @@ func (a *AuthUseCase) Register(w http.ResponseWriter, credentials *domain.Creden
 		return "", fmt.Errorf("error saving user: %w", err)
 	}
 
+	// Генерируем ключ данных пользователя для шифрования объектов в хранилище
+	err = a.keyService.CreateUserKey(user.Login)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Error creating user key: %v", err), http.StatusInternalServerError)
+		return "", fmt.Errorf("error creating user key: %w", err)
+	}
+
 	token, err := a.authService.GenerateToken(user.Login)
 	if err != nil {
 		http.Error(w, fmt.Sprintf("Error generating token: %v", err), http.StatusInternalServerError)
@@ -81,8 +81,17 @@ func TestAuthUseCase_Register_Success(t *testing.T) {
 		},
 	}
 
+	// Ключ данных должен создаваться при регистрации
+	keyCreatedFor := ""
+	mockKeyService := &MockKeyService{
+		CreateUserKeyFunc: func(login string) error {
+			keyCreatedFor = login
+			return nil
+		},
+	}
+
 	// Создаем экземпляр AuthUseCase
-	authUseCase := NewAuthUseCase(mockUserService, mockAuthService)
+	authUseCase := NewAuthUseCase(mockUserService, mockAuthService, mockKeyService)
 
 	// Создаем тестовый ResponseWriter
 	w := httptest.NewRecorder()
@@ -96,6 +105,10 @@ func TestAuthUseCase_Register_Success(t *testing.T) {
 	// Вызываем метод Register
 	token, err := authUseCase.Register(w, credentials)
 
+	if keyCreatedFor != "testuser" {
+		t.Errorf("Ожидалось создание ключа для 'testuser', получено '%s'", keyCreatedFor)
+	}
+
 	// Проверяем результаты
 	if err != nil {
 		t.Fatalf("Ошибка при регистрации: %v", err)
@@ -140,7 +153,7 @@ func TestAuthUseCase_Register_UserExists(t *testing.T) {
 	mockAuthService := &MockAuthService{}
 
 	// Создаем экземпляр AuthUseCase
-	authUseCase := NewAuthUseCase(mockUserService, mockAuthService)
+	authUseCase := NewAuthUseCase(mockUserService, mockAuthService, &MockKeyService{})
 
 	// Создаем тестовый ResponseWriter
 	w := httptest.NewRecorder()
@@ -182,7 +195,7 @@ func TestAuthUseCase_Register_FindUserError(t *testing.T) {
 	mockAuthService := &MockAuthService{}
 
 	// Создаем экземпляр AuthUseCase
-	authUseCase := NewAuthUseCase(mockUserService, mockAuthService)
+	authUseCase := NewAuthUseCase(mockUserService, mockAuthService, &MockKeyService{})
 
 	// Создаем тестовый ResponseWriter
 	w := httptest.NewRecorder()
@@ -228,7 +241,7 @@ func TestAuthUseCase_Register_SaveUserError(t *testing.T) {
 	mockAuthService := &MockAuthService{}
 
 	// Создаем экземпляр AuthUseCase
-	authUseCase := NewAuthUseCase(mockUserService, mockAuthService)
+	authUseCase := NewAuthUseCase(mockUserService, mockAuthService, &MockKeyService{})
 
 	// Создаем тестовый ResponseWriter
 	w := httptest.NewRecorder()
@@ -284,7 +297,7 @@ func TestAuthUseCase_Register_GenerateTokenError(t *testing.T) {
 	}
 
 	// Создаем экземпляр AuthUseCase
-	authUseCase := NewAuthUseCase(mockUserService, mockAuthService)
+	authUseCase := NewAuthUseCase(mockUserService, mockAuthService, &MockKeyService{})
 
 	// Создаем тестовый ResponseWriter
 	w := httptest.NewRecorder()
@@ -344,7 +357,7 @@ func TestAuthUseCase_Login_Success(t *testing.T) {
 	}
 
 	// Создаем экземпляр AuthUseCase
-	authUseCase := NewAuthUseCase(mockUserService, mockAuthService)
+	authUseCase := NewAuthUseCase(mockUserService, mockAuthService, &MockKeyService{})
 
 	// Создаем тестовый ResponseWriter
 	w := httptest.NewRecorder()
@@ -397,7 +410,7 @@ func TestAuthUseCase_Login_UserNotFound(t *testing.T) {
 	mockAuthService := &MockAuthService{}
 
 	// Создаем экземпляр AuthUseCase
-	authUseCase := NewAuthUseCase(mockUserService, mockAuthService)
+	authUseCase := NewAuthUseCase(mockUserService, mockAuthService, &MockKeyService{})
 
 	// Создаем тестовый ResponseWriter
 	w := httptest.NewRecorder()
@@ -439,7 +452,7 @@ func TestAuthUseCase_Login_FindUserError(t *testing.T) {
 	mockAuthService := &MockAuthService{}
 
 	// Создаем экземпляр AuthUseCase
-	authUseCase := NewAuthUseCase(mockUserService, mockAuthService)
+	authUseCase := NewAuthUseCase(mockUserService, mockAuthService, &MockKeyService{})
 
 	// Создаем тестовый ResponseWriter
 	w := httptest.NewRecorder()
@@ -491,7 +504,7 @@ func TestAuthUseCase_Login_InvalidPassword(t *testing.T) {
 	}
 
 	// Создаем экземпляр AuthUseCase
-	authUseCase := NewAuthUseCase(mockUserService, mockAuthService)
+	authUseCase := NewAuthUseCase(mockUserService, mockAuthService, &MockKeyService{})
 
 	// Создаем тестовый ResponseWriter
 	w := httptest.NewRecorder()
@@ -547,7 +560,7 @@ func TestAuthUseCase_Login_GenerateTokenError(t *testing.T) {
 	}
 
 	// Создаем экземпляр AuthUseCase
-	authUseCase := NewAuthUseCase(mockUserService, mockAuthService)
+	authUseCase := NewAuthUseCase(mockUserService, mockAuthService, &MockKeyService{})
 
 	// Создаем тестовый ResponseWriter
 	w := httptest.NewRecorder()
@@ -593,7 +606,7 @@ func TestAuthUseCase_ValidateToken_Success(t *testing.T) {
 	}
 
 	// Создаем экземпляр AuthUseCase
-	authUseCase := NewAuthUseCase(mockUserService, mockAuthService)
+	authUseCase := NewAuthUseCase(mockUserService, mockAuthService, &MockKeyService{})
 
 	// Вызываем метод ValidateToken
 	claims, err := authUseCase.ValidateToken("test_token")
@@ -621,7 +634,7 @@ func TestAuthUseCase_ValidateToken_Error(t *testing.T) {
 	}
 
 	// Создаем экземпляр AuthUseCase
-	authUseCase := NewAuthUseCase(mockUserService, mockAuthService)
+	authUseCase := NewAuthUseCase(mockUserService, mockAuthService, &MockKeyService{})
 
 	// Вызываем метод ValidateToken
 	claims, err := authUseCase.ValidateToken("invalid_token")
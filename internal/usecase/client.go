@@ -1,20 +1,28 @@
 package usecase
 
 import (
-	"bufio"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"github.com/SmirnovND/gophkeeper/internal/domain"
 	"github.com/SmirnovND/gophkeeper/internal/interfaces"
+	"github.com/SmirnovND/gophkeeper/internal/itemtype"
+	"github.com/SmirnovND/gophkeeper/internal/sshkey"
 	"github.com/SmirnovND/gophkeeper/pkg"
+	"io"
+	"net/http"
 	"os"
 	"path/filepath"
-	"strings"
+	"sync"
 )
 
 type ClientUseCase struct {
 	TokenService  interfaces.TokenService
 	ClientService interfaces.ClientService
+
+	// templates - пользовательские типы, уже полученные с сервера
+	templatesMu sync.Mutex
+	templates   map[string]*itemtype.Type
 }
 
 func NewClientUseCase(
@@ -55,7 +63,12 @@ func (c *ClientUseCase) Register(username string, password string, passwordCheck
 }
 
 // Upload - функция для загрузки файла на сервер.
-func (c *ClientUseCase) Upload(filePath string, label string) (string, error) {
+// Путь "-" означает чтение содержимого файла из stdin.
+func (c *ClientUseCase) Upload(filePath string, label string, metadata string) (string, error) {
+	if filePath == "-" {
+		return c.uploadFromStdin(label, metadata)
+	}
+
 	// Проверяем, существует ли файл
 	fileInfo, err := os.Stat(filePath)
 	if err != nil {
@@ -67,15 +80,6 @@ func (c *ClientUseCase) Upload(filePath string, label string) (string, error) {
 		return "", errors.New("Указанный путь является директорией, а не файлом")
 	}
 
-	// Проверяем тип файла (текстовый или бинарный)
-	isText := isTextFile(filePath)
-	isBinary := isBinaryFile(filePath)
-
-	// Проверяем, что файл является текстовым или бинарным
-	if !isText && !isBinary {
-		return "", errors.New("Файл не является ни текстовым, ни бинарным")
-	}
-
 	// Открываем файл
 	file, err := os.Open(filePath)
 	if err != nil {
@@ -83,40 +87,266 @@ func (c *ClientUseCase) Upload(filePath string, label string) (string, error) {
 	}
 	defer file.Close()
 
+	// Определяем тип содержимого по первым байтам файла
+	mimeType, err := sniffMimeType(file, filePath)
+	if err != nil {
+		return "", errors.New(fmt.Sprintf("Ошибка при чтении файла: %v\n", err))
+	}
+
 	token, err := c.TokenService.LoadToken()
 	if err != nil {
-		return "", errors.New(fmt.Sprintf("Ошибка при загрузке токена: %v\n", err))
+		return "", fmt.Errorf("Ошибка при загрузке токена: %w", err)
+	}
+	// Файлы без расширения, например id_rsa, получают расширение по типу содержимого
+	extension := pkg.GetExtensionByPath(filePath)
+	if extension == "" {
+		extension = pkg.ExtensionByMimeType(mimeType)
 	}
-	// Запрашиваем метаинформацию у пользователя
-	fmt.Println("Введите метаинформацию для файла (необязательно):")
-	fmt.Print("> ")
-	var metadata string
-	reader := bufio.NewReader(os.Stdin)
-	metadata, _ = reader.ReadString('\n')
-	metadata = strings.TrimSpace(metadata)
 
 	// Получение ссылки на загрузку файла
-	url, err := c.ClientService.GetUploadLink(label, pkg.GetExtensionByPath(filePath), metadata, token)
+	upload, err := c.ClientService.GetUploadLink(&domain.FileData{
+		Name:         label,
+		Extension:    extension,
+		Size:         fileInfo.Size(),
+		MimeType:     mimeType,
+		OriginalName: filepath.Base(filePath),
+		Metadata:     metadata,
+	}, token)
 	if err != nil {
-		return "", errors.New(fmt.Sprintf("Ошибка при получении ссылки на загрузку: %v\n", err))
+		return "", fmt.Errorf("Ошибка при получении ссылки на загрузку: %w", err)
 	}
 
-	// Выводим информацию о типе файла
-	fileType := "бинарный"
-	if isText {
-		fileType = "текстовый"
+	fmt.Printf("Загрузка файла %s (%s)\n", filePath, mimeType)
+
+	return c.sendFile(upload, file, label, token)
+}
+
+// UploadDir упаковывает директорию в tar-архив и загружает его на сервер как один файл.
+// Архив собирается потоком во временный файл, так как размер нужен заранее для политики загрузки
+func (c *ClientUseCase) UploadDir(dirPath string, label string, metadata string) (string, error) {
+	if label == "" {
+		return "", errors.New("Не указана метка файла")
+	}
+
+	// Проверяем, что путь указывает на директорию
+	dirInfo, err := os.Stat(dirPath)
+	if err != nil {
+		return "", errors.New(fmt.Sprintf("Ошибка при проверке директории: %v\n", err))
+	}
+	if !dirInfo.IsDir() {
+		return "", errors.New("Указанный путь не является директорией")
+	}
+
+	token, err := c.TokenService.LoadToken()
+	if err != nil {
+		return "", fmt.Errorf("Ошибка при загрузке токена: %w", err)
 	}
-	fmt.Printf("Загрузка %s файла: %s\n", fileType, filePath)
 
-	return c.ClientService.SendFileToServer(url, file)
+	file, err := os.CreateTemp("", "passcli-dir-*.tar")
+	if err != nil {
+		return "", errors.New(fmt.Sprintf("Ошибка при создании временного файла: %v\n", err))
+	}
+	defer os.Remove(file.Name())
+	defer file.Close()
+
+	if err := pkg.WriteTar(file, dirPath); err != nil {
+		return "", errors.New(fmt.Sprintf("Ошибка при упаковке директории: %v\n", err))
+	}
+
+	size, err := file.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return "", errors.New(fmt.Sprintf("Ошибка при чтении архива: %v\n", err))
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return "", errors.New(fmt.Sprintf("Ошибка при чтении архива: %v\n", err))
+	}
+
+	absPath, err := filepath.Abs(dirPath)
+	if err != nil {
+		absPath = dirPath
+	}
+
+	upload, err := c.ClientService.GetUploadLink(&domain.FileData{
+		Name:         label,
+		Extension:    "tar",
+		Size:         size,
+		MimeType:     pkg.TarMimeType,
+		OriginalName: filepath.Base(absPath) + ".tar",
+		Metadata:     metadata,
+	}, token)
+	if err != nil {
+		return "", fmt.Errorf("Ошибка при получении ссылки на загрузку: %w", err)
+	}
+
+	fmt.Printf("Загрузка директории %s (%d байт)\n", dirPath, size)
+
+	return c.sendFile(upload, file, label, token)
+}
+
+// uploadFromStdin загружает на сервер данные из stdin.
+// Размер нужен заранее для политики загрузки, поэтому данные сначала сохраняются во временный файл
+func (c *ClientUseCase) uploadFromStdin(label string, metadata string) (string, error) {
+	if label == "" {
+		return "", errors.New("Не указана метка файла")
+	}
+
+	token, err := c.TokenService.LoadToken()
+	if err != nil {
+		return "", fmt.Errorf("Ошибка при загрузке токена: %w", err)
+	}
+
+	file, err := os.CreateTemp("", "passcli-upload-*")
+	if err != nil {
+		return "", errors.New(fmt.Sprintf("Ошибка при создании временного файла: %v\n", err))
+	}
+	defer os.Remove(file.Name())
+	defer file.Close()
+
+	size, err := io.Copy(file, os.Stdin)
+	if err != nil {
+		return "", errors.New(fmt.Sprintf("Ошибка при чтении stdin: %v\n", err))
+	}
+	if size == 0 {
+		return "", errors.New("Нет данных для загрузки в stdin")
+	}
+
+	// Определяем тип содержимого, имени файла у данных из stdin нет
+	mimeType, err := sniffMimeType(file, "")
+	if err != nil {
+		return "", errors.New(fmt.Sprintf("Ошибка при чтении временного файла: %v\n", err))
+	}
+
+	upload, err := c.ClientService.GetUploadLink(&domain.FileData{
+		Name:      label,
+		Extension: pkg.ExtensionByMimeType(mimeType),
+		Size:      size,
+		MimeType:  mimeType,
+		Metadata:  metadata,
+	}, token)
+	if err != nil {
+		return "", fmt.Errorf("Ошибка при получении ссылки на загрузку: %w", err)
+	}
+
+	return c.sendFile(upload, file, label, token)
+}
+
+// Usage получает текущее потребление хранилища и квоту пользователя
+func (c *ClientUseCase) Usage() (*domain.Usage, error) {
+	// Загружаем токен
+	token, err := c.TokenService.LoadToken()
+	if err != nil {
+		return nil, fmt.Errorf("ошибка при загрузке токена: %w", err)
+	}
+
+	usage, err := c.ClientService.GetUsage(token)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка при получении потребления хранилища: %w", err)
+	}
+
+	return usage, nil
+}
+
+// Settings получает действующие настройки аккаунта пользователя
+func (c *ClientUseCase) Settings() (*domain.UserSettings, error) {
+	// Загружаем токен
+	token, err := c.TokenService.LoadToken()
+	if err != nil {
+		return nil, fmt.Errorf("ошибка при загрузке токена: %w", err)
+	}
+
+	settings, err := c.ClientService.GetSettings(token)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка при получении настроек: %w", err)
+	}
+
+	return settings, nil
+}
+
+// SaveSettings сохраняет настройки аккаунта пользователя
+func (c *ClientUseCase) SaveSettings(settings *domain.UserSettings) error {
+	// Загружаем токен
+	token, err := c.TokenService.LoadToken()
+	if err != nil {
+		return fmt.Errorf("ошибка при загрузке токена: %w", err)
+	}
+
+	if err := c.ClientService.SaveSettings(settings, token); err != nil {
+		return fmt.Errorf("ошибка при сохранении настроек: %w", err)
+	}
+
+	return nil
 }
 
 // Download - функция для скачивания файла с сервера.
-func (c *ClientUseCase) Download(label string) error {
+// Пустой outputPath означает директорию загрузок по умолчанию, "-" - вывод в stdout
+func (c *ClientUseCase) Download(label string, outputPath string) error {
+	// Проверяем, что метка файла указана
+	if label == "" {
+		return errors.New("Не указана метка файла")
+	}
+
+	// Загружаем токен
+	token, err := c.TokenService.LoadToken()
+	if err != nil {
+		return fmt.Errorf("ошибка при загрузке токена: %w", err)
+	}
+
+	// Получаем ссылку на скачивание файла, метаданные и метаинформацию
+	downloadURL, fileMetadata, metaInfo, err := c.ClientService.GetDownloadLink(label, token)
+	if err != nil {
+		return fmt.Errorf("ошибка при получении ссылки на скачивание: %w", err)
+	}
+
+	// При выводе в stdout служебные сообщения пишем в stderr, чтобы не смешивать их с содержимым файла
+	var messages io.Writer = os.Stdout
+	if outputPath == "-" {
+		messages = os.Stderr
+	}
+
+	// Выводим метаинформацию, если она есть
+	if metaInfo != "" {
+		fmt.Fprintln(messages, "Метаинформация файла:")
+		fmt.Fprintln(messages, "------------------")
+		fmt.Fprintln(messages, metaInfo)
+		fmt.Fprintln(messages, "------------------")
+	}
+
+	if outputPath == "" {
+		// Получаем директорию загрузок
+		downloadsDir := pkg.GetDownloadsDir()
+		outputPath = filepath.Join(downloadsDir, downloadFileName(label, fileMetadata))
+	}
+
+	// Ключевой материал доступен только владельцу
+	perm := os.FileMode(0o644)
+	if pkg.IsSensitiveFile(fileMetadata.MimeType, fileMetadata.OriginalName) {
+		perm = 0o600
+	}
+
+	fmt.Fprintf(messages, "Скачивание файла с меткой '%s'\n", label)
+
+	// Скачиваем файл
+	err = c.ClientService.DownloadFileFromServer(downloadURL, outputPath, perm)
+	if err != nil {
+		return fmt.Errorf("ошибка при скачивании файла: %w", err)
+	}
+
+	if outputPath != "-" {
+		fmt.Fprintf(messages, "Файл успешно скачан и сохранен в '%s'\n", outputPath)
+	}
+	return nil
+}
+
+// Extract скачивает архив директории и распаковывает его в destDir.
+// Пустой destDir означает поддиректорию с именем метки в директории загрузок
+func (c *ClientUseCase) Extract(label string, destDir string) error {
 	// Проверяем, что метка файла указана
 	if label == "" {
 		return errors.New("Не указана метка файла")
 	}
+	if destDir == "-" {
+		return errors.New("архив нельзя распаковать в stdout")
+	}
 
 	// Загружаем токен
 	token, err := c.TokenService.LoadToken()
@@ -129,7 +359,11 @@ func (c *ClientUseCase) Download(label string) error {
 	if err != nil {
 		return fmt.Errorf("ошибка при получении ссылки на скачивание: %w", err)
 	}
-	
+
+	if fileMetadata.MimeType != pkg.TarMimeType {
+		return fmt.Errorf("файл '%s' не является архивом директории", label)
+	}
+
 	// Выводим метаинформацию, если она есть
 	if metaInfo != "" {
 		fmt.Println("Метаинформация файла:")
@@ -138,21 +372,141 @@ func (c *ClientUseCase) Download(label string) error {
 		fmt.Println("------------------")
 	}
 
-	// Получаем директорию загрузок
-	downloadsDir := pkg.GetDownloadsDir()
-	// Формируем имя файла из метки и расширения
-	fileName := fmt.Sprintf("%s.%s", label, fileMetadata.Extension)
-	outputPath := filepath.Join(downloadsDir, fileName)
+	if destDir == "" {
+		destDir = filepath.Join(pkg.GetDownloadsDir(), label)
+	}
+
+	// Архив скачивается во временный файл, доступный только владельцу
+	archive, err := os.CreateTemp("", "passcli-extract-*.tar")
+	if err != nil {
+		return fmt.Errorf("ошибка при создании временного файла: %w", err)
+	}
+	defer os.Remove(archive.Name())
+	defer archive.Close()
 
-	fmt.Printf("Скачивание файла с меткой '%s'\n", label)
+	fmt.Printf("Скачивание архива с меткой '%s'\n", label)
 
-	// Скачиваем файл
-	err = c.ClientService.DownloadFileFromServer(downloadURL, outputPath)
+	err = c.ClientService.DownloadFileFromServer(downloadURL, archive.Name(), 0o600)
 	if err != nil {
 		return fmt.Errorf("ошибка при скачивании файла: %w", err)
 	}
 
-	fmt.Printf("Файл успешно скачан и сохранен в '%s'\n", outputPath)
+	if err := pkg.ExtractTar(archive, destDir); err != nil {
+		return fmt.Errorf("ошибка при распаковке архива: %w", err)
+	}
+
+	fmt.Printf("Архив успешно распакован в '%s'\n", destDir)
+	return nil
+}
+
+// downloadFileName возвращает имя файла для сохранения: исходное имя файла,
+// а если оно неизвестно - метку с расширением
+func downloadFileName(label string, fileMetadata *domain.FileMetadata) string {
+	// Берем только имя без каталогов, чтобы файл не оказался за пределами директории загрузок
+	originalName := filepath.Base(filepath.Clean("/" + fileMetadata.OriginalName))
+	if originalName != "/" && originalName != "." {
+		return originalName
+	}
+	return fmt.Sprintf("%s.%s", label, fileMetadata.Extension)
+}
+
+// ListFiles получает список файлов пользователя
+func (c *ClientUseCase) ListFiles() ([]domain.FileInfo, error) {
+	// Загружаем токен
+	token, err := c.TokenService.LoadToken()
+	if err != nil {
+		return nil, fmt.Errorf("ошибка при загрузке токена: %w", err)
+	}
+
+	files, err := c.ClientService.ListFiles(token)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка при получении списка файлов: %w", err)
+	}
+
+	return files, nil
+}
+
+// ListItems получает сведения обо всех записях пользователя без их содержимого
+func (c *ClientUseCase) ListItems() ([]domain.ItemInfo, error) {
+	// Загружаем токен
+	token, err := c.TokenService.LoadToken()
+	if err != nil {
+		return nil, fmt.Errorf("ошибка при загрузке токена: %w", err)
+	}
+
+	items, err := c.ClientService.ListItems(token)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка при получении списка записей: %w", err)
+	}
+
+	return items, nil
+}
+
+// SetProgress задает функцию, которая получает ход загрузки и скачивания файлов
+func (c *ClientUseCase) SetProgress(progress domain.ProgressFunc) {
+	c.ClientService.SetProgress(progress)
+}
+
+// FileInfo получает сведения о файле
+func (c *ClientUseCase) FileInfo(label string) (*domain.FileInfo, error) {
+	// Проверяем, что метка файла указана
+	if label == "" {
+		return nil, errors.New("не указана метка файла")
+	}
+
+	// Загружаем токен
+	token, err := c.TokenService.LoadToken()
+	if err != nil {
+		return nil, fmt.Errorf("ошибка при загрузке токена: %w", err)
+	}
+
+	fileInfo, err := c.ClientService.GetFileInfo(label, token)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка при получении сведений о файле: %w", err)
+	}
+
+	return fileInfo, nil
+}
+
+// RenameFile меняет метку файла
+func (c *ClientUseCase) RenameFile(label string, newLabel string) error {
+	// Проверяем, что метки указаны
+	if label == "" || newLabel == "" {
+		return errors.New("не указана текущая или новая метка файла")
+	}
+
+	// Загружаем токен
+	token, err := c.TokenService.LoadToken()
+	if err != nil {
+		return fmt.Errorf("ошибка при загрузке токена: %w", err)
+	}
+
+	err = c.ClientService.RenameFile(label, newLabel, token)
+	if err != nil {
+		return fmt.Errorf("ошибка при переименовании файла: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteFile удаляет файл
+func (c *ClientUseCase) DeleteFile(label string) error {
+	// Проверяем, что метка файла указана
+	if label == "" {
+		return errors.New("не указана метка файла")
+	}
+
+	// Загружаем токен
+	token, err := c.TokenService.LoadToken()
+	if err != nil {
+		return fmt.Errorf("ошибка при загрузке токена: %w", err)
+	}
+
+	err = c.ClientService.DeleteFile(label, token)
+	if err != nil {
+		return fmt.Errorf("ошибка при удалении файла: %w", err)
+	}
+
 	return nil
 }
 
@@ -170,7 +524,7 @@ func (c *ClientUseCase) SaveText(label string, textData *domain.TextData, metada
 	}
 
 	// Сохраняем текстовые данные
-	err = c.ClientService.SaveText(label, textData, metadata, token)
+	err = c.saveTyped(domain.UserDataTypeText, label, textData, metadata, token)
 	if err != nil {
 		return fmt.Errorf("ошибка при сохранении текстовых данных: %w", err)
 	}
@@ -192,12 +546,13 @@ func (c *ClientUseCase) GetText(label string) (*domain.TextData, string, error)
 	}
 
 	// Получаем текстовые данные
-	textData, metadata, err := c.ClientService.GetText(label, token)
+	var textData domain.TextData
+	metadata, err := c.getTyped(domain.UserDataTypeText, label, token, &textData)
 	if err != nil {
 		return nil, "", fmt.Errorf("ошибка при получении текстовых данных: %w", err)
 	}
 
-	return textData, metadata, nil
+	return &textData, metadata, nil
 }
 
 // DeleteText удаляет текстовые данные
@@ -214,7 +569,7 @@ func (c *ClientUseCase) DeleteText(label string) error {
 	}
 
 	// Удаляем текстовые данные
-	err = c.ClientService.DeleteText(label, token)
+	err = c.ClientService.DeleteItem(domain.UserDataTypeText, label, token)
 	if err != nil {
 		return fmt.Errorf("ошибка при удалении текстовых данных: %w", err)
 	}
@@ -222,6 +577,76 @@ func (c *ClientUseCase) DeleteText(label string) error {
 	return nil
 }
 
+// SaveSSHKey сохраняет ключ SSH. Открытый ключ и отпечаток заполняются по закрытому ключу
+func (c *ClientUseCase) SaveSSHKey(label string, sshKeyData *domain.SSHKeyData, metadata string) error {
+	// Проверяем, что метка указана
+	if label == "" {
+		return errors.New("не указана метка для ключа SSH")
+	}
+	if err := sshkey.Complete(sshKeyData); err != nil {
+		return err
+	}
+
+	// Загружаем токен
+	token, err := c.TokenService.LoadToken()
+	if err != nil {
+		return fmt.Errorf("ошибка при загрузке токена: %w", err)
+	}
+
+	// Сохраняем ключ SSH
+	err = c.saveTyped(domain.UserDataTypeSSHKey, label, sshKeyData, metadata, token)
+	if err != nil {
+		return fmt.Errorf("ошибка при сохранении ключа SSH: %w", err)
+	}
+
+	return nil
+}
+
+// GetSSHKey получает ключ SSH
+func (c *ClientUseCase) GetSSHKey(label string) (*domain.SSHKeyData, string, error) {
+	// Проверяем, что метка указана
+	if label == "" {
+		return nil, "", errors.New("не указана метка для ключа SSH")
+	}
+
+	// Загружаем токен
+	token, err := c.TokenService.LoadToken()
+	if err != nil {
+		return nil, "", fmt.Errorf("ошибка при загрузке токена: %w", err)
+	}
+
+	// Получаем ключ SSH
+	var sshKeyData domain.SSHKeyData
+	metadata, err := c.getTyped(domain.UserDataTypeSSHKey, label, token, &sshKeyData)
+	if err != nil {
+		return nil, "", fmt.Errorf("ошибка при получении ключа SSH: %w", err)
+	}
+
+	return &sshKeyData, metadata, nil
+}
+
+// DeleteSSHKey удаляет ключ SSH
+func (c *ClientUseCase) DeleteSSHKey(label string) error {
+	// Проверяем, что метка указана
+	if label == "" {
+		return errors.New("не указана метка для ключа SSH")
+	}
+
+	// Загружаем токен
+	token, err := c.TokenService.LoadToken()
+	if err != nil {
+		return fmt.Errorf("ошибка при загрузке токена: %w", err)
+	}
+
+	// Удаляем ключ SSH
+	err = c.ClientService.DeleteItem(domain.UserDataTypeSSHKey, label, token)
+	if err != nil {
+		return fmt.Errorf("ошибка при удалении ключа SSH: %w", err)
+	}
+
+	return nil
+}
+
 // SaveCard сохраняет данные кредитной карты
 func (c *ClientUseCase) SaveCard(label string, cardData *domain.CardData, metadata string) error {
 	// Проверяем, что метка указана
@@ -236,7 +661,7 @@ func (c *ClientUseCase) SaveCard(label string, cardData *domain.CardData, metada
 	}
 
 	// Сохраняем данные карты
-	err = c.ClientService.SaveCard(label, cardData, metadata, token)
+	err = c.saveTyped(domain.UserDataTypeCard, label, cardData, metadata, token)
 	if err != nil {
 		return fmt.Errorf("ошибка при сохранении данных карты: %w", err)
 	}
@@ -258,12 +683,13 @@ func (c *ClientUseCase) GetCard(label string) (*domain.CardData, string, error)
 	}
 
 	// Получаем данные карты
-	cardData, metadata, err := c.ClientService.GetCard(label, token)
+	var cardData domain.CardData
+	metadata, err := c.getTyped(domain.UserDataTypeCard, label, token, &cardData)
 	if err != nil {
 		return nil, "", fmt.Errorf("ошибка при получении данных карты: %w", err)
 	}
 
-	return cardData, metadata, nil
+	return &cardData, metadata, nil
 }
 
 // DeleteCard удаляет данные кредитной карты
@@ -280,7 +706,7 @@ func (c *ClientUseCase) DeleteCard(label string) error {
 	}
 
 	// Удаляем данные карты
-	err = c.ClientService.DeleteCard(label, token)
+	err = c.ClientService.DeleteItem(domain.UserDataTypeCard, label, token)
 	if err != nil {
 		return fmt.Errorf("ошибка при удалении данных карты: %w", err)
 	}
@@ -302,7 +728,7 @@ func (c *ClientUseCase) SaveCredential(label string, credentialData *domain.Cred
 	}
 
 	// Сохраняем учетные данные
-	err = c.ClientService.SaveCredential(label, credentialData, metadata, token)
+	err = c.saveTyped(domain.UserDataTypeCredential, label, credentialData, metadata, token)
 	if err != nil {
 		return fmt.Errorf("ошибка при сохранении учетных данных: %w", err)
 	}
@@ -324,12 +750,13 @@ func (c *ClientUseCase) GetCredential(label string) (*domain.CredentialData, str
 	}
 
 	// Получаем учетные данные
-	credentialData, metadata, err := c.ClientService.GetCredential(label, token)
+	var credentialData domain.CredentialData
+	metadata, err := c.getTyped(domain.UserDataTypeCredential, label, token, &credentialData)
 	if err != nil {
 		return nil, "", fmt.Errorf("ошибка при получении учетных данных: %w", err)
 	}
 
-	return credentialData, metadata, nil
+	return &credentialData, metadata, nil
 }
 
 // DeleteCredential удаляет учетные данные
@@ -346,7 +773,7 @@ func (c *ClientUseCase) DeleteCredential(label string) error {
 	}
 
 	// Удаляем учетные данные
-	err = c.ClientService.DeleteCredential(label, token)
+	err = c.ClientService.DeleteItem(domain.UserDataTypeCredential, label, token)
 	if err != nil {
 		return fmt.Errorf("ошибка при удалении учетных данных: %w", err)
 	}
@@ -354,22 +781,256 @@ func (c *ClientUseCase) DeleteCredential(label string) error {
 	return nil
 }
 
-// isTextFile проверяет, является ли файл текстовым
-func isTextFile(filePath string) bool {
-	// Проверка по расширению файла (быстрый метод)
-	if ".txt" == strings.ToLower(filepath.Ext(filePath)) {
-		return true
+// SaveItem сохраняет запись зарегистрированного типа. Поля проверяются по описанию типа до запроса к серверу
+func (c *ClientUseCase) SaveItem(itemType string, label string, data domain.ItemData, metadata string) error {
+	// Проверяем, что метка указана
+	if label == "" {
+		return errors.New("не указана метка для записи")
+	}
+	t, err := c.ItemType(itemType)
+	if err != nil {
+		return err
+	}
+	if err := t.Validate(data); err != nil {
+		return err
+	}
+
+	// Загружаем токен
+	token, err := c.TokenService.LoadToken()
+	if err != nil {
+		return fmt.Errorf("ошибка при загрузке токена: %w", err)
+	}
+
+	// Сохраняем запись
+	err = c.ClientService.SaveItem(itemType, label, data, metadata, token)
+	if err != nil {
+		return fmt.Errorf("ошибка при сохранении записи: %w", err)
+	}
+
+	return nil
+}
+
+// GetItem получает запись зарегистрированного типа
+func (c *ClientUseCase) GetItem(itemType string, label string) (domain.ItemData, string, error) {
+	// Проверяем, что метка указана
+	if label == "" {
+		return nil, "", errors.New("не указана метка для записи")
+	}
+
+	// Загружаем токен
+	token, err := c.TokenService.LoadToken()
+	if err != nil {
+		return nil, "", fmt.Errorf("ошибка при загрузке токена: %w", err)
+	}
+
+	// Получаем запись
+	data, metadata, err := c.ClientService.GetItem(itemType, label, token)
+	if err != nil {
+		return nil, "", fmt.Errorf("ошибка при получении записи: %w", err)
 	}
 
-	return false
+	return data, metadata, nil
 }
 
-// isBinaryFile проверяет, является ли файл бинарным
-func isBinaryFile(filePath string) bool {
-	// Проверка по расширению файла (быстрый метод)
-	if ".bin" == strings.ToLower(filepath.Ext(filePath)) {
-		return true
+// DeleteItem удаляет запись зарегистрированного типа
+func (c *ClientUseCase) DeleteItem(itemType string, label string) error {
+	// Проверяем, что метка указана
+	if label == "" {
+		return errors.New("не указана метка для записи")
+	}
+
+	// Загружаем токен
+	token, err := c.TokenService.LoadToken()
+	if err != nil {
+		return fmt.Errorf("ошибка при загрузке токена: %w", err)
+	}
+
+	// Удаляем запись
+	err = c.ClientService.DeleteItem(itemType, label, token)
+	if err != nil {
+		return fmt.Errorf("ошибка при удалении записи: %w", err)
+	}
+
+	return nil
+}
+
+// ItemType возвращает описание встроенного типа или пользовательского типа, заданного шаблоном.
+// Шаблон запрашивается с сервера один раз и затем берется из памяти
+func (c *ClientUseCase) ItemType(itemType string) (*itemtype.Type, error) {
+	if t, err := itemtype.Lookup(itemType); err == nil || itemType == domain.UserDataTypeFile {
+		return t, err
+	}
+
+	c.templatesMu.Lock()
+	defer c.templatesMu.Unlock()
+	if t, ok := c.templates[itemType]; ok {
+		return t, nil
+	}
+
+	// Загружаем токен
+	token, err := c.TokenService.LoadToken()
+	if err != nil {
+		return nil, fmt.Errorf("ошибка при загрузке токена: %w", err)
+	}
+
+	// Получаем шаблон и строим по нему тип
+	template, err := c.ClientService.GetItemTemplate(itemType, token)
+	if err != nil {
+		var statusErr *domain.Error
+		if errors.As(err, &statusErr) && statusErr.Code() == http.StatusNotFound {
+			return nil, fmt.Errorf("%w '%s'", itemtype.ErrUnknownType, itemType)
+		}
+		return nil, fmt.Errorf("ошибка при получении шаблона: %w", err)
+	}
+	t, err := itemtype.ParseSchema(template.Name, template.Schema)
+	if err != nil {
+		return nil, err
+	}
+	if c.templates == nil {
+		c.templates = make(map[string]*itemtype.Type)
+	}
+	c.templates[itemType] = t
+	return t, nil
+}
+
+// SaveItemTemplate сохраняет шаблон пользовательского типа записей. Схема проверяется до запроса к серверу
+func (c *ClientUseCase) SaveItemTemplate(name string, schema json.RawMessage) error {
+	if _, err := itemtype.ParseSchema(name, schema); err != nil {
+		return err
+	}
+
+	// Загружаем токен
+	token, err := c.TokenService.LoadToken()
+	if err != nil {
+		return fmt.Errorf("ошибка при загрузке токена: %w", err)
+	}
+
+	// Сохраняем шаблон
+	if err := c.ClientService.SaveItemTemplate(name, schema, token); err != nil {
+		return fmt.Errorf("ошибка при сохранении шаблона: %w", err)
+	}
+	c.forgetTemplate(name)
+	return nil
+}
+
+// GetItemTemplate получает шаблон пользовательского типа записей
+func (c *ClientUseCase) GetItemTemplate(name string) (*domain.ItemTemplate, error) {
+	// Загружаем токен
+	token, err := c.TokenService.LoadToken()
+	if err != nil {
+		return nil, fmt.Errorf("ошибка при загрузке токена: %w", err)
+	}
+
+	// Получаем шаблон
+	template, err := c.ClientService.GetItemTemplate(name, token)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка при получении шаблона: %w", err)
+	}
+	return template, nil
+}
+
+// ListItemTemplates получает все шаблоны пользовательских типов записей
+func (c *ClientUseCase) ListItemTemplates() ([]domain.ItemTemplate, error) {
+	// Загружаем токен
+	token, err := c.TokenService.LoadToken()
+	if err != nil {
+		return nil, fmt.Errorf("ошибка при загрузке токена: %w", err)
+	}
+
+	// Получаем шаблоны
+	templates, err := c.ClientService.ListItemTemplates(token)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка при получении списка шаблонов: %w", err)
+	}
+	return templates, nil
+}
+
+// DeleteItemTemplate удаляет шаблон пользовательского типа записей
+func (c *ClientUseCase) DeleteItemTemplate(name string) error {
+	// Загружаем токен
+	token, err := c.TokenService.LoadToken()
+	if err != nil {
+		return fmt.Errorf("ошибка при загрузке токена: %w", err)
+	}
+
+	// Удаляем шаблон
+	if err := c.ClientService.DeleteItemTemplate(name, token); err != nil {
+		return fmt.Errorf("ошибка при удалении шаблона: %w", err)
+	}
+	c.forgetTemplate(name)
+	return nil
+}
+
+// forgetTemplate убирает из памяти тип, построенный по измененному шаблону
+func (c *ClientUseCase) forgetTemplate(name string) {
+	c.templatesMu.Lock()
+	defer c.templatesMu.Unlock()
+	delete(c.templates, name)
+}
+
+// saveTyped сохраняет запись встроенного типа, преобразуя ее содержимое в значения полей.
+// Поля проверяются по описанию типа до запроса к серверу
+func (c *ClientUseCase) saveTyped(itemType string, label string, value interface{}, metadata string, token string) error {
+	t, err := itemtype.Lookup(itemType)
+	if err != nil {
+		return err
+	}
+	data, err := itemtype.Encode(value)
+	if err != nil {
+		return err
+	}
+	if err := t.Validate(data); err != nil {
+		return err
+	}
+	return c.ClientService.SaveItem(itemType, label, data, metadata, token)
+}
+
+// getTyped получает запись встроенного типа и заполняет ее содержимым value
+func (c *ClientUseCase) getTyped(itemType string, label string, token string, value interface{}) (string, error) {
+	t, err := itemtype.Lookup(itemType)
+	if err != nil {
+		return "", err
+	}
+	data, metadata, err := c.ClientService.GetItem(itemType, label, token)
+	if err != nil {
+		return "", err
+	}
+	if err := t.Decode(data, value); err != nil {
+		return "", err
+	}
+	return metadata, nil
+}
+
+// sniffMimeType определяет тип содержимого файла по первым байтам и возвращает указатель чтения в начало
+// sendFile загружает файл по полученной ссылке и просит сервер проверить его тип по содержимому.
+// Файл запрещенного типа сервер удаляет, и загрузка завершается ошибкой
+func (c *ClientUseCase) sendFile(upload *domain.FileDataResponse, file *os.File, label string, token string) (string, error) {
+	result, err := c.ClientService.SendFileToServer(upload, file)
+	if err != nil {
+		return "", err
+	}
+
+	if err := c.ClientService.VerifyFile(label, token); err != nil {
+		return "", fmt.Errorf("Ошибка при проверке файла: %w", err)
+	}
+
+	return result, nil
+}
+
+func sniffMimeType(file *os.File, fileName string) (string, error) {
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return "", err
+	}
+
+	head := make([]byte, pkg.SniffLen)
+	n, err := io.ReadFull(file, head)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return "", err
+	}
+
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return "", err
 	}
 
-	return false
+	return pkg.DetectMimeType(head[:n], fileName), nil
 }
@@ -1,10 +1,16 @@
 package usecase
 
 import (
+	"encoding/json"
 	"errors"
 	"github.com/SmirnovND/gophkeeper/internal/domain"
+	"github.com/SmirnovND/gophkeeper/internal/itemtype"
+	"github.com/SmirnovND/gophkeeper/internal/sshkey"
+	"io"
+	"net/http"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -31,19 +37,27 @@ func (m *MockTokenServiceFixed) LoadToken() (string, error) {
 type MockClientServiceFixed struct {
 	LoginFunc                  func(login string, password string) (string, error)
 	RegisterFunc               func(login string, password string) (string, error)
-	GetUploadLinkFunc          func(label string, extension string, metadata string, token string) (string, error)
-	GetDownloadLinkFunc        func(label string, token string) (string, *domain.FileMetadata, string, error)
-	SendFileToServerFunc       func(url string, file *os.File) (string, error)
-	DownloadFileFromServerFunc func(url string, outputPath string) error
-	SaveTextFunc               func(label string, textData *domain.TextData, metadata string, token string) error
-	GetTextFunc                func(label string, token string) (*domain.TextData, string, error)
-	DeleteTextFunc             func(label string, token string) error
-	SaveCardFunc               func(label string, cardData *domain.CardData, metadata string, token string) error
-	GetCardFunc                func(label string, token string) (*domain.CardData, string, error)
-	DeleteCardFunc             func(label string, token string) error
-	SaveCredentialFunc         func(label string, credentialData *domain.CredentialData, metadata string, token string) error
-	GetCredentialFunc          func(label string, token string) (*domain.CredentialData, string, error)
-	DeleteCredentialFunc       func(label string, token string) error
+	GetUploadLinkFunc          func(fileData *domain.FileData, token string) (*domain.FileDataResponse, error)
+	GetDownloadLinkFunc        func(label string, token string) (*domain.DownloadLink, *domain.FileMetadata, string, error)
+	SendFileToServerFunc       func(upload *domain.FileDataResponse, file *os.File) (string, error)
+	VerifyFileFunc             func(label string, token string) error
+	DownloadFileFromServerFunc func(link *domain.DownloadLink, outputPath string, perm os.FileMode) error
+	SaveItemFunc               func(itemType string, label string, data domain.ItemData, metadata string, token string) error
+	GetItemFunc                func(itemType string, label string, token string) (domain.ItemData, string, error)
+	DeleteItemFunc             func(itemType string, label string, token string) error
+	GetUsageFunc               func(token string) (*domain.Usage, error)
+	GetSettingsFunc            func(token string) (*domain.UserSettings, error)
+	SaveSettingsFunc           func(settings *domain.UserSettings, token string) error
+	ListFilesFunc              func(token string) ([]domain.FileInfo, error)
+	GetFileInfoFunc            func(label string, token string) (*domain.FileInfo, error)
+	RenameFileFunc             func(label string, newLabel string, token string) error
+	DeleteFileFunc             func(label string, token string) error
+	ListItemsFunc              func(token string) ([]domain.ItemInfo, error)
+	SaveItemTemplateFunc       func(name string, schema json.RawMessage, token string) error
+	GetItemTemplateFunc        func(name string, token string) (*domain.ItemTemplate, error)
+	ListItemTemplatesFunc      func(token string) ([]domain.ItemTemplate, error)
+	DeleteItemTemplateFunc     func(name string, token string) error
+	Progress                   domain.ProgressFunc
 }
 
 func (m *MockClientServiceFixed) Login(login string, password string) (string, error) {
@@ -60,93 +74,146 @@ func (m *MockClientServiceFixed) Register(login string, password string) (string
 	return "", nil
 }
 
-func (m *MockClientServiceFixed) GetUploadLink(label string, extension string, metadata string, token string) (string, error) {
+func (m *MockClientServiceFixed) GetUploadLink(fileData *domain.FileData, token string) (*domain.FileDataResponse, error) {
 	if m.GetUploadLinkFunc != nil {
-		return m.GetUploadLinkFunc(label, extension, metadata, token)
+		return m.GetUploadLinkFunc(fileData, token)
 	}
-	return "", nil
+	return &domain.FileDataResponse{}, nil
 }
 
-func (m *MockClientServiceFixed) GetDownloadLink(label string, token string) (string, *domain.FileMetadata, string, error) {
+func (m *MockClientServiceFixed) GetDownloadLink(label string, token string) (*domain.DownloadLink, *domain.FileMetadata, string, error) {
 	if m.GetDownloadLinkFunc != nil {
 		return m.GetDownloadLinkFunc(label, token)
 	}
-	return "", nil, "", nil
+	return nil, nil, "", nil
 }
 
-func (m *MockClientServiceFixed) SendFileToServer(url string, file *os.File) (string, error) {
+func (m *MockClientServiceFixed) SendFileToServer(upload *domain.FileDataResponse, file *os.File) (string, error) {
 	if m.SendFileToServerFunc != nil {
-		return m.SendFileToServerFunc(url, file)
+		return m.SendFileToServerFunc(upload, file)
 	}
 	return "", nil
 }
 
-func (m *MockClientServiceFixed) DownloadFileFromServer(url string, outputPath string) error {
-	if m.DownloadFileFromServerFunc != nil {
-		return m.DownloadFileFromServerFunc(url, outputPath)
+func (m *MockClientServiceFixed) VerifyFile(label string, token string) error {
+	if m.VerifyFileFunc != nil {
+		return m.VerifyFileFunc(label, token)
+	}
+	return nil
+}
+
+func (m *MockClientServiceFixed) GetUsage(token string) (*domain.Usage, error) {
+	if m.GetUsageFunc != nil {
+		return m.GetUsageFunc(token)
+	}
+	return &domain.Usage{}, nil
+}
+
+func (m *MockClientServiceFixed) GetSettings(token string) (*domain.UserSettings, error) {
+	if m.GetSettingsFunc != nil {
+		return m.GetSettingsFunc(token)
+	}
+	return &domain.UserSettings{}, nil
+}
+
+func (m *MockClientServiceFixed) SaveSettings(settings *domain.UserSettings, token string) error {
+	if m.SaveSettingsFunc != nil {
+		return m.SaveSettingsFunc(settings, token)
 	}
 	return nil
 }
 
-func (m *MockClientServiceFixed) SaveText(label string, textData *domain.TextData, metadata string, token string) error {
-	if m.SaveTextFunc != nil {
-		return m.SaveTextFunc(label, textData, metadata, token)
+func (m *MockClientServiceFixed) DownloadFileFromServer(link *domain.DownloadLink, outputPath string, perm os.FileMode) error {
+	if m.DownloadFileFromServerFunc != nil {
+		return m.DownloadFileFromServerFunc(link, outputPath, perm)
 	}
 	return nil
 }
 
-func (m *MockClientServiceFixed) GetText(label string, token string) (*domain.TextData, string, error) {
-	if m.GetTextFunc != nil {
-		return m.GetTextFunc(label, token)
+func (m *MockClientServiceFixed) ListFiles(token string) ([]domain.FileInfo, error) {
+	if m.ListFilesFunc != nil {
+		return m.ListFilesFunc(token)
 	}
-	return nil, "", nil
+	return nil, nil
 }
 
-func (m *MockClientServiceFixed) DeleteText(label string, token string) error {
-	if m.DeleteTextFunc != nil {
-		return m.DeleteTextFunc(label, token)
+func (m *MockClientServiceFixed) ListItems(token string) ([]domain.ItemInfo, error) {
+	if m.ListItemsFunc != nil {
+		return m.ListItemsFunc(token)
+	}
+	return nil, nil
+}
+
+func (m *MockClientServiceFixed) SetProgress(progress domain.ProgressFunc) {
+	m.Progress = progress
+}
+
+func (m *MockClientServiceFixed) GetFileInfo(label string, token string) (*domain.FileInfo, error) {
+	if m.GetFileInfoFunc != nil {
+		return m.GetFileInfoFunc(label, token)
+	}
+	return &domain.FileInfo{}, nil
+}
+
+func (m *MockClientServiceFixed) RenameFile(label string, newLabel string, token string) error {
+	if m.RenameFileFunc != nil {
+		return m.RenameFileFunc(label, newLabel, token)
+	}
+	return nil
+}
+
+func (m *MockClientServiceFixed) DeleteFile(label string, token string) error {
+	if m.DeleteFileFunc != nil {
+		return m.DeleteFileFunc(label, token)
 	}
 	return nil
 }
 
-func (m *MockClientServiceFixed) SaveCard(label string, cardData *domain.CardData, metadata string, token string) error {
-	if m.SaveCardFunc != nil {
-		return m.SaveCardFunc(label, cardData, metadata, token)
+func (m *MockClientServiceFixed) SaveItem(itemType string, label string, data domain.ItemData, metadata string, token string) error {
+	if m.SaveItemFunc != nil {
+		return m.SaveItemFunc(itemType, label, data, metadata, token)
 	}
 	return nil
 }
 
-func (m *MockClientServiceFixed) GetCard(label string, token string) (*domain.CardData, string, error) {
-	if m.GetCardFunc != nil {
-		return m.GetCardFunc(label, token)
+func (m *MockClientServiceFixed) GetItem(itemType string, label string, token string) (domain.ItemData, string, error) {
+	if m.GetItemFunc != nil {
+		return m.GetItemFunc(itemType, label, token)
 	}
 	return nil, "", nil
 }
 
-func (m *MockClientServiceFixed) DeleteCard(label string, token string) error {
-	if m.DeleteCardFunc != nil {
-		return m.DeleteCardFunc(label, token)
+func (m *MockClientServiceFixed) DeleteItem(itemType string, label string, token string) error {
+	if m.DeleteItemFunc != nil {
+		return m.DeleteItemFunc(itemType, label, token)
 	}
 	return nil
 }
 
-func (m *MockClientServiceFixed) SaveCredential(label string, credentialData *domain.CredentialData, metadata string, token string) error {
-	if m.SaveCredentialFunc != nil {
-		return m.SaveCredentialFunc(label, credentialData, metadata, token)
+func (m *MockClientServiceFixed) SaveItemTemplate(name string, schema json.RawMessage, token string) error {
+	if m.SaveItemTemplateFunc != nil {
+		return m.SaveItemTemplateFunc(name, schema, token)
 	}
 	return nil
 }
 
-func (m *MockClientServiceFixed) GetCredential(label string, token string) (*domain.CredentialData, string, error) {
-	if m.GetCredentialFunc != nil {
-		return m.GetCredentialFunc(label, token)
+func (m *MockClientServiceFixed) GetItemTemplate(name string, token string) (*domain.ItemTemplate, error) {
+	if m.GetItemTemplateFunc != nil {
+		return m.GetItemTemplateFunc(name, token)
 	}
-	return nil, "", nil
+	return nil, &domain.Error{Message: "шаблон не найден", CodeValue: http.StatusNotFound}
 }
 
-func (m *MockClientServiceFixed) DeleteCredential(label string, token string) error {
-	if m.DeleteCredentialFunc != nil {
-		return m.DeleteCredentialFunc(label, token)
+func (m *MockClientServiceFixed) ListItemTemplates(token string) ([]domain.ItemTemplate, error) {
+	if m.ListItemTemplatesFunc != nil {
+		return m.ListItemTemplatesFunc(token)
+	}
+	return []domain.ItemTemplate{}, nil
+}
+
+func (m *MockClientServiceFixed) DeleteItemTemplate(name string, token string) error {
+	if m.DeleteItemTemplateFunc != nil {
+		return m.DeleteItemTemplateFunc(name, token)
 	}
 	return nil
 }
@@ -175,24 +242,32 @@ func TestClientUseCase_Upload_Success_Fixed(t *testing.T) {
 	}
 
 	mockClientService := &MockClientServiceFixed{
-		GetUploadLinkFunc: func(label string, extension string, metadata string, token string) (string, error) {
+		GetUploadLinkFunc: func(fileData *domain.FileData, token string) (*domain.FileDataResponse, error) {
 			// Проверяем параметры
-			if label != "test_label" {
-				t.Errorf("Ожидалась метка 'test_label', получена '%s'", label)
+			if fileData.Name != "test_label" {
+				t.Errorf("Ожидалась метка 'test_label', получена '%s'", fileData.Name)
+			}
+			if fileData.Extension != "txt" {
+				t.Errorf("Ожидалось расширение 'txt', получено '%s'", fileData.Extension)
 			}
-			if extension != "txt" {
-				t.Errorf("Ожидалось расширение 'txt', получено '%s'", extension)
+			if !strings.HasPrefix(fileData.MimeType, "text/plain") {
+				t.Errorf("Ожидался тип 'text/plain', получен '%s'", fileData.MimeType)
+			}
+			if fileData.Metadata != "test metadata" {
+				t.Errorf("Ожидалась метаинформация 'test metadata', получена '%s'", fileData.Metadata)
+			}
+			if fileData.Size != int64(len("test content")) {
+				t.Errorf("Ожидался размер %d, получен %d", len("test content"), fileData.Size)
 			}
-			// Не проверяем metadata, так как она вводится пользователем
 			if token != "test_token" {
 				t.Errorf("Ожидался токен 'test_token', получен '%s'", token)
 			}
-			return "http://example.com/upload", nil
+			return &domain.FileDataResponse{Url: "http://example.com/upload"}, nil
 		},
-		SendFileToServerFunc: func(url string, file *os.File) (string, error) {
+		SendFileToServerFunc: func(upload *domain.FileDataResponse, file *os.File) (string, error) {
 			// Проверяем параметры
-			if url != "http://example.com/upload" {
-				t.Errorf("Ожидался URL 'http://example.com/upload', получен '%s'", url)
+			if upload.Url != "http://example.com/upload" {
+				t.Errorf("Ожидался URL 'http://example.com/upload', получен '%s'", upload.Url)
 			}
 			return "success", nil
 		},
@@ -202,7 +277,7 @@ func TestClientUseCase_Upload_Success_Fixed(t *testing.T) {
 	clientUseCase := NewClientUseCase(mockTokenService, mockClientService)
 
 	// Вызываем метод Upload
-	result, err := clientUseCase.Upload(tempFile.Name(), "test_label")
+	result, err := clientUseCase.Upload(tempFile.Name(), "test_label", "test metadata")
 
 	// Проверяем результаты
 	if err != nil {
@@ -326,21 +401,21 @@ func TestClientUseCase_Download(t *testing.T) {
 		}
 
 		mockClientService := &MockClientServiceFixed{
-			GetDownloadLinkFunc: func(label string, token string) (string, *domain.FileMetadata, string, error) {
+			GetDownloadLinkFunc: func(label string, token string) (*domain.DownloadLink, *domain.FileMetadata, string, error) {
 				if label != "test-file" {
 					t.Errorf("Ожидалась метка 'test-file', получена '%s'", label)
 				}
 				if token != "test-token" {
 					t.Errorf("Ожидался токен 'test-token', получен '%s'", token)
 				}
-				return "http://example.com/download", &domain.FileMetadata{
+				return &domain.DownloadLink{URL: "http://example.com/download"}, &domain.FileMetadata{
 					FileName:  "test-file",
 					Extension: "txt",
 				}, "test metadata", nil
 			},
-			DownloadFileFromServerFunc: func(url string, outputPath string) error {
-				if url != "http://example.com/download" {
-					t.Errorf("Ожидался URL 'http://example.com/download', получен '%s'", url)
+			DownloadFileFromServerFunc: func(link *domain.DownloadLink, outputPath string, perm os.FileMode) error {
+				if link.URL != "http://example.com/download" {
+					t.Errorf("Ожидался URL 'http://example.com/download', получен '%s'", link.URL)
 				}
 				// Проверяем, что путь содержит правильное имя файла
 				expectedFilename := "test-file.txt"
@@ -352,7 +427,61 @@ func TestClientUseCase_Download(t *testing.T) {
 		}
 
 		clientUseCase := NewClientUseCase(mockTokenService, mockClientService)
-		err := clientUseCase.Download("test-file")
+		err := clientUseCase.Download("test-file", "")
+		if err != nil {
+			t.Errorf("Не ожидалась ошибка, получена: %v", err)
+		}
+	})
+
+	// Тест скачивания по указанному пути
+	t.Run("OutputPath", func(t *testing.T) {
+		mockTokenService := &MockTokenServiceFixed{
+			LoadTokenFunc: func() (string, error) {
+				return "test-token", nil
+			},
+		}
+
+		mockClientService := &MockClientServiceFixed{
+			GetDownloadLinkFunc: func(label string, token string) (*domain.DownloadLink, *domain.FileMetadata, string, error) {
+				return &domain.DownloadLink{URL: "http://example.com/download"}, &domain.FileMetadata{FileName: "test-file", Extension: "txt"}, "", nil
+			},
+			DownloadFileFromServerFunc: func(link *domain.DownloadLink, outputPath string, perm os.FileMode) error {
+				if outputPath != "/tmp/custom.out" {
+					t.Errorf("Ожидался путь '/tmp/custom.out', получен '%s'", outputPath)
+				}
+				return nil
+			},
+		}
+
+		clientUseCase := NewClientUseCase(mockTokenService, mockClientService)
+		err := clientUseCase.Download("test-file", "/tmp/custom.out")
+		if err != nil {
+			t.Errorf("Не ожидалась ошибка, получена: %v", err)
+		}
+	})
+
+	// Тест вывода в stdout
+	t.Run("Stdout", func(t *testing.T) {
+		mockTokenService := &MockTokenServiceFixed{
+			LoadTokenFunc: func() (string, error) {
+				return "test-token", nil
+			},
+		}
+
+		mockClientService := &MockClientServiceFixed{
+			GetDownloadLinkFunc: func(label string, token string) (*domain.DownloadLink, *domain.FileMetadata, string, error) {
+				return &domain.DownloadLink{URL: "http://example.com/download"}, &domain.FileMetadata{FileName: "test-file", Extension: "txt"}, "", nil
+			},
+			DownloadFileFromServerFunc: func(link *domain.DownloadLink, outputPath string, perm os.FileMode) error {
+				if outputPath != "-" {
+					t.Errorf("Ожидался путь '-', получен '%s'", outputPath)
+				}
+				return nil
+			},
+		}
+
+		clientUseCase := NewClientUseCase(mockTokenService, mockClientService)
+		err := clientUseCase.Download("test-file", "-")
 		if err != nil {
 			t.Errorf("Не ожидалась ошибка, получена: %v", err)
 		}
@@ -364,7 +493,7 @@ func TestClientUseCase_Download(t *testing.T) {
 		mockClientService := &MockClientServiceFixed{}
 
 		clientUseCase := NewClientUseCase(mockTokenService, mockClientService)
-		err := clientUseCase.Download("")
+		err := clientUseCase.Download("", "")
 		if err == nil {
 			t.Error("Ожидалась ошибка пустой метки, но ее не было")
 		}
@@ -380,7 +509,7 @@ func TestClientUseCase_Download(t *testing.T) {
 		mockClientService := &MockClientServiceFixed{}
 
 		clientUseCase := NewClientUseCase(mockTokenService, mockClientService)
-		err := clientUseCase.Download("test-file")
+		err := clientUseCase.Download("test-file", "")
 		if err == nil {
 			t.Error("Ожидалась ошибка загрузки токена, но ее не было")
 		}
@@ -394,13 +523,13 @@ func TestClientUseCase_Download(t *testing.T) {
 			},
 		}
 		mockClientService := &MockClientServiceFixed{
-			GetDownloadLinkFunc: func(label string, token string) (string, *domain.FileMetadata, string, error) {
-				return "", nil, "", errors.New("ошибка получения ссылки")
+			GetDownloadLinkFunc: func(label string, token string) (*domain.DownloadLink, *domain.FileMetadata, string, error) {
+				return nil, nil, "", errors.New("ошибка получения ссылки")
 			},
 		}
 
 		clientUseCase := NewClientUseCase(mockTokenService, mockClientService)
-		err := clientUseCase.Download("test-file")
+		err := clientUseCase.Download("test-file", "")
 		if err == nil {
 			t.Error("Ожидалась ошибка получения ссылки, но ее не было")
 		}
@@ -414,19 +543,19 @@ func TestClientUseCase_Download(t *testing.T) {
 			},
 		}
 		mockClientService := &MockClientServiceFixed{
-			GetDownloadLinkFunc: func(label string, token string) (string, *domain.FileMetadata, string, error) {
-				return "http://example.com/download", &domain.FileMetadata{
+			GetDownloadLinkFunc: func(label string, token string) (*domain.DownloadLink, *domain.FileMetadata, string, error) {
+				return &domain.DownloadLink{URL: "http://example.com/download"}, &domain.FileMetadata{
 					FileName:  "test-file",
 					Extension: "txt",
 				}, "", nil
 			},
-			DownloadFileFromServerFunc: func(url string, outputPath string) error {
+			DownloadFileFromServerFunc: func(link *domain.DownloadLink, outputPath string, perm os.FileMode) error {
 				return errors.New("ошибка скачивания файла")
 			},
 		}
 
 		clientUseCase := NewClientUseCase(mockTokenService, mockClientService)
-		err := clientUseCase.Download("test-file")
+		err := clientUseCase.Download("test-file", "")
 		if err == nil {
 			t.Error("Ожидалась ошибка скачивания файла, но ее не было")
 		}
@@ -444,12 +573,15 @@ func TestClientUseCase_SaveText(t *testing.T) {
 		}
 
 		mockClientService := &MockClientServiceFixed{
-			SaveTextFunc: func(label string, textData *domain.TextData, metadata string, token string) error {
+			SaveItemFunc: func(itemType string, label string, data domain.ItemData, metadata string, token string) error {
+				if itemType != domain.UserDataTypeText {
+					t.Errorf("Ожидался тип 'text', получен '%s'", itemType)
+				}
 				if label != "test-text" {
 					t.Errorf("Ожидалась метка 'test-text', получена '%s'", label)
 				}
-				if textData.Content != "test content" {
-					t.Errorf("Ожидался текст 'test content', получен '%s'", textData.Content)
+				if data["content"] != "test content" {
+					t.Errorf("Ожидался текст 'test content', получен '%s'", data["content"])
 				}
 				if metadata != "test metadata" {
 					t.Errorf("Ожидались метаданные 'test metadata', получены '%s'", metadata)
@@ -507,7 +639,10 @@ func TestClientUseCase_SaveText(t *testing.T) {
 			},
 		}
 		mockClientService := &MockClientServiceFixed{
-			SaveTextFunc: func(label string, textData *domain.TextData, metadata string, token string) error {
+			SaveItemFunc: func(itemType string, label string, data domain.ItemData, metadata string, token string) error {
+				if itemType != domain.UserDataTypeText {
+					t.Errorf("Ожидался тип 'text', получен '%s'", itemType)
+				}
 				return errors.New("ошибка сохранения текста")
 			},
 		}
@@ -521,6 +656,162 @@ func TestClientUseCase_SaveText(t *testing.T) {
 	})
 }
 
+// TestClientUseCase_SaveSSHKey тестирует заполнение открытого ключа и отпечатка перед отправкой
+func TestClientUseCase_SaveSSHKey(t *testing.T) {
+	generated, err := sshkey.Generate(sshkey.TypeEd25519, 0, "deploy")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		mockTokenService := &MockTokenServiceFixed{
+			LoadTokenFunc: func() (string, error) {
+				return "test-token", nil
+			},
+		}
+		mockClientService := &MockClientServiceFixed{
+			SaveItemFunc: func(itemType string, label string, data domain.ItemData, metadata string, token string) error {
+				if itemType != domain.UserDataTypeSSHKey {
+					t.Errorf("Ожидался тип 'ssh_key', получен '%s'", itemType)
+				}
+				if data["public_key"] != generated.PublicKey || data["fingerprint"] != generated.Fingerprint {
+					t.Errorf("Ожидался ключ %+v, получены поля %v", generated, data)
+				}
+				return nil
+			},
+		}
+
+		clientUseCase := NewClientUseCase(mockTokenService, mockClientService)
+		sshKeyData := &domain.SSHKeyData{PrivateKey: generated.PrivateKey, Comment: "deploy"}
+		if err := clientUseCase.SaveSSHKey("deploy", sshKeyData, ""); err != nil {
+			t.Errorf("Не ожидалась ошибка, получена: %v", err)
+		}
+	})
+
+	// Неверный ключ не отправляется на сервер
+	t.Run("InvalidKey", func(t *testing.T) {
+		mockClientService := &MockClientServiceFixed{
+			SaveItemFunc: func(itemType string, label string, data domain.ItemData, metadata string, token string) error {
+				t.Error("Неверный ключ не должен отправляться")
+				return nil
+			},
+		}
+
+		clientUseCase := NewClientUseCase(&MockTokenServiceFixed{}, mockClientService)
+		err := clientUseCase.SaveSSHKey("deploy", &domain.SSHKeyData{PrivateKey: "not a key"}, "")
+		if !errors.Is(err, sshkey.ErrInvalidKey) {
+			t.Errorf("Ожидалась ошибка %v, получена: %v", sshkey.ErrInvalidKey, err)
+		}
+	})
+}
+
+// TestClientUseCase_SaveItem тестирует отправку записи структурированного типа и проверку полей до запроса к серверу
+func TestClientUseCase_SaveItem(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		mockTokenService := &MockTokenServiceFixed{
+			LoadTokenFunc: func() (string, error) {
+				return "test-token", nil
+			},
+		}
+		mockClientService := &MockClientServiceFixed{
+			SaveItemFunc: func(itemType string, label string, data domain.ItemData, metadata string, token string) error {
+				if itemType != domain.UserDataTypeAPIToken || data["token"] != "ghp_x" || token != "test-token" {
+					t.Errorf("Неверный запрос: %s, %v, '%s'", itemType, data, token)
+				}
+				return nil
+			},
+		}
+
+		clientUseCase := NewClientUseCase(mockTokenService, mockClientService)
+		data := domain.ItemData{"service": "GitHub", "token": "ghp_x"}
+		if err := clientUseCase.SaveItem(domain.UserDataTypeAPIToken, "github", data, ""); err != nil {
+			t.Errorf("Не ожидалась ошибка, получена: %v", err)
+		}
+	})
+
+	// Неверные поля не отправляются на сервер
+	t.Run("InvalidItem", func(t *testing.T) {
+		mockClientService := &MockClientServiceFixed{
+			SaveItemFunc: func(itemType string, label string, data domain.ItemData, metadata string, token string) error {
+				t.Error("Неверная запись не должна отправляться")
+				return nil
+			},
+		}
+
+		clientUseCase := NewClientUseCase(&MockTokenServiceFixed{}, mockClientService)
+		err := clientUseCase.SaveItem(domain.UserDataTypeAPIToken, "github", domain.ItemData{"service": "GitHub", "expires_at": "завтра"}, "")
+		if !errors.Is(err, itemtype.ErrInvalidItem) {
+			t.Errorf("Ожидалась ошибка %v, получена: %v", itemtype.ErrInvalidItem, err)
+		}
+	})
+}
+
+// TestClientUseCase_CustomItemType тестирует получение пользовательского типа по шаблону и проверку шаблонов до запроса к серверу
+func TestClientUseCase_CustomItemType(t *testing.T) {
+	schema := json.RawMessage(`{"type": "object", "properties": {"host": {"type": "string"}, "password": {"type": "string", "writeOnly": true}}, "required": ["host"]}`)
+	requests := 0
+	mockClientService := &MockClientServiceFixed{
+		GetItemTemplateFunc: func(name string, token string) (*domain.ItemTemplate, error) {
+			requests++
+			if name != "db_profile" {
+				return nil, &domain.Error{Message: "шаблон не найден", CodeValue: http.StatusNotFound}
+			}
+			return &domain.ItemTemplate{Name: name, Schema: schema}, nil
+		},
+		SaveItemTemplateFunc: func(name string, schema json.RawMessage, token string) error {
+			return nil
+		},
+		SaveItemFunc: func(itemType string, label string, data domain.ItemData, metadata string, token string) error {
+			if itemType != "db_profile" || data["password"] != "s3cret" {
+				t.Errorf("Неверный запрос: %s, %v", itemType, data)
+			}
+			return nil
+		},
+	}
+	clientUseCase := NewClientUseCase(&MockTokenServiceFixed{}, mockClientService)
+
+	// Тип строится по шаблону один раз
+	for i := 0; i < 2; i++ {
+		dbProfile, err := clientUseCase.ItemType("db_profile")
+		if err != nil || dbProfile.Secret != "password" || !dbProfile.Custom {
+			t.Fatalf("Неверный тип: %+v, %v", dbProfile, err)
+		}
+	}
+	if requests != 1 {
+		t.Errorf("Ожидался 1 запрос шаблона, выполнено %d", requests)
+	}
+
+	// После изменения шаблона тип запрашивается заново
+	if err := clientUseCase.SaveItemTemplate("db_profile", schema); err != nil {
+		t.Fatalf("Не ожидалась ошибка, получена: %v", err)
+	}
+	if _, err := clientUseCase.ItemType("db_profile"); err != nil || requests != 2 {
+		t.Errorf("Ожидался повторный запрос шаблона, выполнено %d, ошибка %v", requests, err)
+	}
+
+	// Записи пользовательского типа проверяются по шаблону
+	if err := clientUseCase.SaveItem("db_profile", "prod", domain.ItemData{"host": "db.local", "password": "s3cret"}, ""); err != nil {
+		t.Errorf("Не ожидалась ошибка, получена: %v", err)
+	}
+	if err := clientUseCase.SaveItem("db_profile", "prod", domain.ItemData{"password": "s3cret"}, ""); !errors.Is(err, itemtype.ErrInvalidItem) {
+		t.Errorf("Ожидалась ошибка %v, получена: %v", itemtype.ErrInvalidItem, err)
+	}
+
+	// Тип без шаблона неизвестен
+	if _, err := clientUseCase.ItemType("passport"); !errors.Is(err, itemtype.ErrUnknownType) {
+		t.Errorf("Ожидалась ошибка %v, получена: %v", itemtype.ErrUnknownType, err)
+	}
+
+	// Неверная схема не отправляется на сервер
+	mockClientService.SaveItemTemplateFunc = func(name string, schema json.RawMessage, token string) error {
+		t.Error("Неверная схема не должна отправляться")
+		return nil
+	}
+	if err := clientUseCase.SaveItemTemplate("wifi", schema); !errors.Is(err, itemtype.ErrInvalidSchema) {
+		t.Errorf("Ожидалась ошибка %v, получена: %v", itemtype.ErrInvalidSchema, err)
+	}
+}
+
 // TestClientUseCase_GetText тестирует метод GetText
 func TestClientUseCase_GetText(t *testing.T) {
 	// Тест успешного получения текста
@@ -532,14 +823,17 @@ func TestClientUseCase_GetText(t *testing.T) {
 		}
 
 		mockClientService := &MockClientServiceFixed{
-			GetTextFunc: func(label string, token string) (*domain.TextData, string, error) {
+			GetItemFunc: func(itemType string, label string, token string) (domain.ItemData, string, error) {
+				if itemType != domain.UserDataTypeText {
+					t.Errorf("Ожидался тип 'text', получен '%s'", itemType)
+				}
 				if label != "test-text" {
 					t.Errorf("Ожидалась метка 'test-text', получена '%s'", label)
 				}
 				if token != "test-token" {
 					t.Errorf("Ожидался токен 'test-token', получен '%s'", token)
 				}
-				return &domain.TextData{Content: "test content"}, "test metadata", nil
+				return domain.ItemData{"content": "test content"}, "test metadata", nil
 			},
 		}
 
@@ -592,7 +886,10 @@ func TestClientUseCase_GetText(t *testing.T) {
 			},
 		}
 		mockClientService := &MockClientServiceFixed{
-			GetTextFunc: func(label string, token string) (*domain.TextData, string, error) {
+			GetItemFunc: func(itemType string, label string, token string) (domain.ItemData, string, error) {
+				if itemType != domain.UserDataTypeText {
+					t.Errorf("Ожидался тип 'text', получен '%s'", itemType)
+				}
 				return nil, "", errors.New("ошибка получения текста")
 			},
 		}
@@ -616,7 +913,10 @@ func TestClientUseCase_DeleteText(t *testing.T) {
 		}
 
 		mockClientService := &MockClientServiceFixed{
-			DeleteTextFunc: func(label string, token string) error {
+			DeleteItemFunc: func(itemType string, label string, token string) error {
+				if itemType != domain.UserDataTypeText {
+					t.Errorf("Ожидался тип 'text', получен '%s'", itemType)
+				}
 				if label != "test-text" {
 					t.Errorf("Ожидалась метка 'test-text', получена '%s'", label)
 				}
@@ -670,7 +970,10 @@ func TestClientUseCase_DeleteText(t *testing.T) {
 			},
 		}
 		mockClientService := &MockClientServiceFixed{
-			DeleteTextFunc: func(label string, token string) error {
+			DeleteItemFunc: func(itemType string, label string, token string) error {
+				if itemType != domain.UserDataTypeText {
+					t.Errorf("Ожидался тип 'text', получен '%s'", itemType)
+				}
 				return errors.New("ошибка удаления текста")
 			},
 		}
@@ -694,12 +997,15 @@ func TestClientUseCase_SaveCard(t *testing.T) {
 		}
 
 		mockClientService := &MockClientServiceFixed{
-			SaveCardFunc: func(label string, cardData *domain.CardData, metadata string, token string) error {
+			SaveItemFunc: func(itemType string, label string, data domain.ItemData, metadata string, token string) error {
+				if itemType != domain.UserDataTypeCard {
+					t.Errorf("Ожидался тип 'card', получен '%s'", itemType)
+				}
 				if label != "test-card" {
 					t.Errorf("Ожидалась метка 'test-card', получена '%s'", label)
 				}
-				if cardData.Number != "1234567890123456" {
-					t.Errorf("Ожидался номер карты '1234567890123456', получен '%s'", cardData.Number)
+				if data["number"] != "1234567890123456" {
+					t.Errorf("Ожидался номер карты '1234567890123456', получен '%s'", data["number"])
 				}
 				if metadata != "test metadata" {
 					t.Errorf("Ожидались метаданные 'test metadata', получены '%s'", metadata)
@@ -772,7 +1078,10 @@ func TestClientUseCase_SaveCard(t *testing.T) {
 			},
 		}
 		mockClientService := &MockClientServiceFixed{
-			SaveCardFunc: func(label string, cardData *domain.CardData, metadata string, token string) error {
+			SaveItemFunc: func(itemType string, label string, data domain.ItemData, metadata string, token string) error {
+				if itemType != domain.UserDataTypeCard {
+					t.Errorf("Ожидался тип 'card', получен '%s'", itemType)
+				}
 				return errors.New("ошибка сохранения карты")
 			},
 		}
@@ -802,18 +1111,21 @@ func TestClientUseCase_GetCard(t *testing.T) {
 		}
 
 		mockClientService := &MockClientServiceFixed{
-			GetCardFunc: func(label string, token string) (*domain.CardData, string, error) {
+			GetItemFunc: func(itemType string, label string, token string) (domain.ItemData, string, error) {
+				if itemType != domain.UserDataTypeCard {
+					t.Errorf("Ожидался тип 'card', получен '%s'", itemType)
+				}
 				if label != "test-card" {
 					t.Errorf("Ожидалась метка 'test-card', получена '%s'", label)
 				}
 				if token != "test-token" {
 					t.Errorf("Ожидался токен 'test-token', получен '%s'", token)
 				}
-				return &domain.CardData{
-					Number:     "1234567890123456",
-					Holder:     "Test User",
-					ExpiryDate: "12/25",
-					CVV:        "123",
+				return domain.ItemData{
+					"number":      "1234567890123456",
+					"holder":      "Test User",
+					"expiry_date": "12/25",
+					"cvv":         "123",
 				}, "test metadata", nil
 			},
 		}
@@ -867,7 +1179,10 @@ func TestClientUseCase_GetCard(t *testing.T) {
 			},
 		}
 		mockClientService := &MockClientServiceFixed{
-			GetCardFunc: func(label string, token string) (*domain.CardData, string, error) {
+			GetItemFunc: func(itemType string, label string, token string) (domain.ItemData, string, error) {
+				if itemType != domain.UserDataTypeCard {
+					t.Errorf("Ожидался тип 'card', получен '%s'", itemType)
+				}
 				return nil, "", errors.New("ошибка получения карты")
 			},
 		}
@@ -891,7 +1206,10 @@ func TestClientUseCase_DeleteCard(t *testing.T) {
 		}
 
 		mockClientService := &MockClientServiceFixed{
-			DeleteCardFunc: func(label string, token string) error {
+			DeleteItemFunc: func(itemType string, label string, token string) error {
+				if itemType != domain.UserDataTypeCard {
+					t.Errorf("Ожидался тип 'card', получен '%s'", itemType)
+				}
 				if label != "test-card" {
 					t.Errorf("Ожидалась метка 'test-card', получена '%s'", label)
 				}
@@ -945,7 +1263,10 @@ func TestClientUseCase_DeleteCard(t *testing.T) {
 			},
 		}
 		mockClientService := &MockClientServiceFixed{
-			DeleteCardFunc: func(label string, token string) error {
+			DeleteItemFunc: func(itemType string, label string, token string) error {
+				if itemType != domain.UserDataTypeCard {
+					t.Errorf("Ожидался тип 'card', получен '%s'", itemType)
+				}
 				return errors.New("ошибка удаления карты")
 			},
 		}
@@ -969,15 +1290,18 @@ func TestClientUseCase_SaveCredential(t *testing.T) {
 		}
 
 		mockClientService := &MockClientServiceFixed{
-			SaveCredentialFunc: func(label string, credentialData *domain.CredentialData, metadata string, token string) error {
+			SaveItemFunc: func(itemType string, label string, data domain.ItemData, metadata string, token string) error {
+				if itemType != domain.UserDataTypeCredential {
+					t.Errorf("Ожидался тип 'credential', получен '%s'", itemType)
+				}
 				if label != "test-credential" {
 					t.Errorf("Ожидалась метка 'test-credential', получена '%s'", label)
 				}
-				if credentialData.Login != "testuser" {
-					t.Errorf("Ожидался логин 'testuser', получен '%s'", credentialData.Login)
+				if data["login"] != "testuser" {
+					t.Errorf("Ожидался логин 'testuser', получен '%s'", data["login"])
 				}
-				if credentialData.Password != "testpass" {
-					t.Errorf("Ожидался пароль 'testpass', получен '%s'", credentialData.Password)
+				if data["password"] != "testpass" {
+					t.Errorf("Ожидался пароль 'testpass', получен '%s'", data["password"])
 				}
 				if metadata != "test metadata" {
 					t.Errorf("Ожидались метаданные 'test metadata', получены '%s'", metadata)
@@ -1044,7 +1368,10 @@ func TestClientUseCase_SaveCredential(t *testing.T) {
 			},
 		}
 		mockClientService := &MockClientServiceFixed{
-			SaveCredentialFunc: func(label string, credentialData *domain.CredentialData, metadata string, token string) error {
+			SaveItemFunc: func(itemType string, label string, data domain.ItemData, metadata string, token string) error {
+				if itemType != domain.UserDataTypeCredential {
+					t.Errorf("Ожидался тип 'credential', получен '%s'", itemType)
+				}
 				return errors.New("ошибка сохранения учетных данных")
 			},
 		}
@@ -1072,16 +1399,19 @@ func TestClientUseCase_GetCredential(t *testing.T) {
 		}
 
 		mockClientService := &MockClientServiceFixed{
-			GetCredentialFunc: func(label string, token string) (*domain.CredentialData, string, error) {
+			GetItemFunc: func(itemType string, label string, token string) (domain.ItemData, string, error) {
+				if itemType != domain.UserDataTypeCredential {
+					t.Errorf("Ожидался тип 'credential', получен '%s'", itemType)
+				}
 				if label != "test-credential" {
 					t.Errorf("Ожидалась метка 'test-credential', получена '%s'", label)
 				}
 				if token != "test-token" {
 					t.Errorf("Ожидался токен 'test-token', получен '%s'", token)
 				}
-				return &domain.CredentialData{
-					Login:    "testuser",
-					Password: "testpass",
+				return domain.ItemData{
+					"login":    "testuser",
+					"password": "testpass",
 				}, "test metadata", nil
 			},
 		}
@@ -1138,7 +1468,10 @@ func TestClientUseCase_GetCredential(t *testing.T) {
 			},
 		}
 		mockClientService := &MockClientServiceFixed{
-			GetCredentialFunc: func(label string, token string) (*domain.CredentialData, string, error) {
+			GetItemFunc: func(itemType string, label string, token string) (domain.ItemData, string, error) {
+				if itemType != domain.UserDataTypeCredential {
+					t.Errorf("Ожидался тип 'credential', получен '%s'", itemType)
+				}
 				return nil, "", errors.New("ошибка получения учетных данных")
 			},
 		}
@@ -1162,7 +1495,10 @@ func TestClientUseCase_DeleteCredential(t *testing.T) {
 		}
 
 		mockClientService := &MockClientServiceFixed{
-			DeleteCredentialFunc: func(label string, token string) error {
+			DeleteItemFunc: func(itemType string, label string, token string) error {
+				if itemType != domain.UserDataTypeCredential {
+					t.Errorf("Ожидался тип 'credential', получен '%s'", itemType)
+				}
 				if label != "test-credential" {
 					t.Errorf("Ожидалась метка 'test-credential', получена '%s'", label)
 				}
@@ -1216,7 +1552,10 @@ func TestClientUseCase_DeleteCredential(t *testing.T) {
 			},
 		}
 		mockClientService := &MockClientServiceFixed{
-			DeleteCredentialFunc: func(label string, token string) error {
+			DeleteItemFunc: func(itemType string, label string, token string) error {
+				if itemType != domain.UserDataTypeCredential {
+					t.Errorf("Ожидался тип 'credential', получен '%s'", itemType)
+				}
 				return errors.New("ошибка удаления учетных данных")
 			},
 		}
@@ -1228,3 +1567,101 @@ func TestClientUseCase_DeleteCredential(t *testing.T) {
 		}
 	})
 }
+
+// TestClientUseCase_UploadFromStdin тестирует загрузку файла из stdin
+func TestClientUseCase_UploadFromStdin(t *testing.T) {
+	// Подменяем stdin содержимым файла
+	oldStdin := os.Stdin
+	defer func() { os.Stdin = oldStdin }()
+	r, w, _ := os.Pipe()
+	os.Stdin = r
+	go func() {
+		w.Write([]byte("piped content"))
+		w.Close()
+	}()
+
+	mockTokenService := &MockTokenServiceFixed{
+		LoadTokenFunc: func() (string, error) {
+			return "test-token", nil
+		},
+	}
+
+	mockClientService := &MockClientServiceFixed{
+		GetUploadLinkFunc: func(fileData *domain.FileData, token string) (*domain.FileDataResponse, error) {
+			if fileData.Name != "piped" || fileData.Extension != "txt" || fileData.Size != int64(len("piped content")) {
+				t.Errorf("Неожиданные параметры: '%s', '%s', %d", fileData.Name, fileData.Extension, fileData.Size)
+			}
+			return &domain.FileDataResponse{Url: "http://example.com/upload"}, nil
+		},
+		SendFileToServerFunc: func(upload *domain.FileDataResponse, file *os.File) (string, error) {
+			file.Seek(0, 0)
+			content, _ := io.ReadAll(file)
+			if string(content) != "piped content" {
+				t.Errorf("Ожидалось содержимое 'piped content', получено '%s'", string(content))
+			}
+			return "Файл успешно загружен!", nil
+		},
+	}
+
+	clientUseCase := NewClientUseCase(mockTokenService, mockClientService)
+	result, err := clientUseCase.Upload("-", "piped", "")
+	if err != nil {
+		t.Fatalf("Не ожидалась ошибка, получена: %v", err)
+	}
+	if result != "Файл успешно загружен!" {
+		t.Errorf("Неожиданный результат: %s", result)
+	}
+}
+
+// TestClientUseCase_FileManagement тестирует методы управления файлами
+func TestClientUseCase_FileManagement(t *testing.T) {
+	mockTokenService := &MockTokenServiceFixed{
+		LoadTokenFunc: func() (string, error) {
+			return "test-token", nil
+		},
+	}
+
+	mockClientService := &MockClientServiceFixed{
+		ListFilesFunc: func(token string) ([]domain.FileInfo, error) {
+			return []domain.FileInfo{{Label: "a"}}, nil
+		},
+		GetFileInfoFunc: func(label string, token string) (*domain.FileInfo, error) {
+			return &domain.FileInfo{Label: label}, nil
+		},
+		RenameFileFunc: func(label string, newLabel string, token string) error {
+			if label != "old" || newLabel != "new" {
+				t.Errorf("Неожиданные метки: '%s', '%s'", label, newLabel)
+			}
+			return nil
+		},
+		DeleteFileFunc: func(label string, token string) error {
+			return errors.New("файл не найден")
+		},
+	}
+
+	clientUseCase := NewClientUseCase(mockTokenService, mockClientService)
+
+	files, err := clientUseCase.ListFiles()
+	if err != nil || len(files) != 1 {
+		t.Errorf("Неожиданный результат ListFiles: %v, %v", files, err)
+	}
+
+	fileInfo, err := clientUseCase.FileInfo("a")
+	if err != nil || fileInfo.Label != "a" {
+		t.Errorf("Неожиданный результат FileInfo: %v, %v", fileInfo, err)
+	}
+	if _, err := clientUseCase.FileInfo(""); err == nil {
+		t.Error("Ожидалась ошибка пустой метки")
+	}
+
+	if err := clientUseCase.RenameFile("old", "new"); err != nil {
+		t.Errorf("Неожиданная ошибка RenameFile: %v", err)
+	}
+	if err := clientUseCase.RenameFile("old", ""); err == nil {
+		t.Error("Ожидалась ошибка пустой новой метки")
+	}
+
+	if err := clientUseCase.DeleteFile("missing"); err == nil || !strings.Contains(err.Error(), "файл не найден") {
+		t.Errorf("Ожидалась ошибка удаления, получено: %v", err)
+	}
+}
@@ -2,27 +2,38 @@ package usecase
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/SmirnovND/gophkeeper/internal/domain"
 	"github.com/SmirnovND/gophkeeper/internal/interfaces"
+	"github.com/SmirnovND/gophkeeper/pkg"
 	"net/http"
 )
 
 type CloudUseCase struct {
-	cloudService interfaces.CloudService
-	dataService  interfaces.DataService
-	jwtService   interfaces.JwtService
+	cloudService   interfaces.CloudService
+	dataService    interfaces.DataService
+	quotaService   interfaces.QuotaService
+	jwtService     interfaces.JwtService
+	keyService     interfaces.KeyService
+	fileTypePolicy domain.FileTypePolicy
 }
 
 func NewCloudUseCase(
 	cloudService interfaces.CloudService,
 	dataService interfaces.DataService,
+	quotaService interfaces.QuotaService,
 	jwtService interfaces.JwtService,
+	keyService interfaces.KeyService,
+	fileTypePolicy domain.FileTypePolicy,
 ) interfaces.CloudUseCase {
 	return &CloudUseCase{
-		cloudService: cloudService,
-		dataService:  dataService,
-		jwtService:   jwtService,
+		cloudService:   cloudService,
+		dataService:    dataService,
+		quotaService:   quotaService,
+		jwtService:     jwtService,
+		keyService:     keyService,
+		fileTypePolicy: fileTypePolicy,
 	}
 }
 
@@ -34,16 +45,46 @@ func (c *CloudUseCase) GenerateUploadLink(w http.ResponseWriter, r *http.Request
 	}
 
 	// Валидация входящего объекта FileData
-	if fileData == nil || fileData.Name == "" || fileData.Extension == "" {
+	if fileData == nil || fileData.Name == "" || fileData.Extension == "" || fileData.Size <= 0 {
 		http.Error(w, "Неверные данные файла", http.StatusBadRequest)
 		return
 	}
 
+	// Клиенты, не определяющие тип содержимого, загружают файл как двоичные данные
+	if fileData.MimeType == "" {
+		fileData.MimeType = "application/octet-stream"
+	}
+
+	// Проверяем политику типов файлов
+	if !c.fileTypePolicy.Allows(fileData.MimeType) {
+		http.Error(w, fmt.Sprintf("%s: %s", domain.ErrFileTypeNotAllowed, fileData.MimeType), http.StatusUnsupportedMediaType)
+		return
+	}
+
+	// Проверяем квоту пользователя до выдачи ссылки
+	err = c.quotaService.CheckFileUpload(login, fileData.Name, fileData.Size)
+	if err != nil {
+		if errors.Is(err, domain.ErrQuotaExceeded) {
+			http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+			return
+		}
+		http.Error(w, "Ошибка при проверке квоты: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
 	// Формируем имя файла
-	fileName := fmt.Sprintf("%s_%s.%s", login, fileData.Name, fileData.Extension)
+	fileName := objectName(login, &domain.FileMetadata{FileName: fileData.Name, Extension: fileData.Extension})
+
+	// Получаем ключ пользователя, при отключенном шифровании объект хранится без SSE-C
+	key, err := c.keyService.GetUserKey(login)
+	if err != nil {
+		http.Error(w, "Ошибка при получении ключа шифрования: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	fileData.Encrypted = key != nil
 
-	// Получаем ссылку для загрузки
-	uploadLink, err := c.cloudService.GenerateUploadLink(fileName)
+	// Получаем ссылку для загрузки, хранилище примет файл не больше заявленного размера
+	uploadLink, formData, err := c.cloudService.GenerateUploadLink(fileName, fileData.Size, fileData.MimeType, key)
 
 	if err != nil {
 		http.Error(w, "Ошибка при генерации ссылки: "+err.Error(), http.StatusInternalServerError)
@@ -51,14 +92,20 @@ func (c *CloudUseCase) GenerateUploadLink(w http.ResponseWriter, r *http.Request
 	}
 
 	// Сохраняем метаданные файла в таблице user_data
+	// Квота окончательно проверяется при сохранении: параллельная загрузка могла исчерпать ее после проверки выше
 	err = c.dataService.SaveFileMetadata(login, fileData.Name, fileData, fileData.Metadata)
 	if err != nil {
+		if errors.Is(err, domain.ErrQuotaExceeded) {
+			http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+			return
+		}
 		http.Error(w, "Ошибка при сохранении метаданных файла: "+err.Error(), http.StatusInternalServerError)
 		return
 	}
 
 	response := domain.FileDataResponse{
 		Url:         uploadLink,
+		FormData:    formData,
 		Description: "Загрузи файл по этой ссылке",
 	}
 
@@ -85,28 +132,42 @@ func (c *CloudUseCase) GenerateDownloadLink(w http.ResponseWriter, r *http.Reque
 	// Получаем метаданные файла из базы данных
 	fileMetadata, metadata, err := c.dataService.GetFileMetadata(login, label)
 	if err != nil {
-		http.Error(w, "Ошибка при получении метаданных файла: "+err.Error(), http.StatusInternalServerError)
+		writeFileError(w, "Ошибка при получении метаданных файла: ", err)
 		return
 	}
 
-	// Формируем имя файла
-	fileName := fmt.Sprintf("%s_%s.%s", login, fileMetadata.FileName, fileMetadata.Extension)
+	key, err := c.objectKey(login, fileMetadata)
+	if err != nil {
+		http.Error(w, "Ошибка при получении ключа шифрования: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	// Тип проверяется по содержимому и перед скачиванием: клиент мог не запросить проверку после загрузки.
+	// Еще не загруженный файл не проверяется, как и раньше ссылка на него выдается
+	err = c.checkFileType(login, label, fileMetadata, key)
+	if err != nil && !errors.Is(err, domain.ErrNotFound) {
+		writeFileTypeError(w, err)
+		return
+	}
 
 	// Получаем ссылку для скачивания
-	downloadLink, err := c.cloudService.GenerateDownloadLink(fileName)
+	downloadLink, err := c.cloudService.GenerateDownloadLink(objectName(login, fileMetadata), key)
 	if err != nil {
 		http.Error(w, "Ошибка при генерации ссылки для скачивания: "+err.Error(), http.StatusInternalServerError)
 		return
 	}
 
-	// Создаем расширенный ответ с метаданными и метаинформацией
+	// Создаем расширенный ответ с метаданными и метаинформацией.
+	// Для зашифрованного объекта headers содержит заголовки SSE-C, которые нужно передать при скачивании
 	response := struct {
 		URL         string              `json:"url"`
+		Headers     map[string]string   `json:"headers,omitempty"`
 		Description string              `json:"description"`
 		Metadata    domain.FileMetadata `json:"metadata"`
 		MetaInfo    string              `json:"meta_info"`
 	}{
-		URL:         downloadLink,
+		URL:         downloadLink.URL,
+		Headers:     downloadLink.Headers,
 		Description: "Скачай файл по этой ссылке",
 		Metadata:    *fileMetadata,
 		MetaInfo:    metadata,
@@ -117,3 +178,236 @@ func (c *CloudUseCase) GenerateDownloadLink(w http.ResponseWriter, r *http.Reque
 	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(response)
 }
+
+func (c *CloudUseCase) DeleteFile(w http.ResponseWriter, r *http.Request, label string) {
+	login, err := c.jwtService.ExtractLoginFromToken(r.Header.Get("Authorization"))
+	if err != nil {
+		http.Error(w, "Ошибка получения логина: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if label == "" {
+		http.Error(w, "Не указана метка файла", http.StatusBadRequest)
+		return
+	}
+
+	// Получаем метаданные файла, чтобы определить имя объекта в хранилище
+	fileMetadata, _, err := c.dataService.GetFileMetadata(login, label)
+	if err != nil {
+		writeFileError(w, "Ошибка при получении метаданных файла: ", err)
+		return
+	}
+
+	// Сначала удаляем объект, чтобы не оставлять в хранилище файлы без метаданных
+	err = c.cloudService.DeleteFile(objectName(login, fileMetadata))
+	if err != nil {
+		http.Error(w, "Ошибка при удалении файла из хранилища: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	err = c.dataService.DeleteFileMetadata(login, label)
+	if err != nil {
+		writeFileError(w, "Ошибка при удалении метаданных файла: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]string{"message": "файл успешно удален"})
+}
+
+func (c *CloudUseCase) RenameFile(w http.ResponseWriter, r *http.Request, rename *domain.FileRename) {
+	login, err := c.jwtService.ExtractLoginFromToken(r.Header.Get("Authorization"))
+	if err != nil {
+		http.Error(w, "Ошибка получения логина: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if rename == nil || rename.Label == "" || rename.NewLabel == "" {
+		http.Error(w, "Не указана текущая или новая метка файла", http.StatusBadRequest)
+		return
+	}
+
+	// Меняем метку в метаданных, получаем имя файла до переименования
+	fileMetadata, err := c.dataService.RenameFileMetadata(login, rename.Label, rename.NewLabel)
+	if err != nil {
+		writeFileError(w, "Ошибка при переименовании файла: ", err)
+		return
+	}
+
+	renamed := *fileMetadata
+	renamed.FileName = rename.NewLabel
+
+	// Переносим объект в хранилище, при ошибке возвращаем прежнюю метку
+	key, err := c.objectKey(login, fileMetadata)
+	if err == nil {
+		err = c.cloudService.RenameFile(objectName(login, fileMetadata), objectName(login, &renamed), key)
+	}
+	if err != nil {
+		message := "Ошибка при переименовании файла в хранилище: " + err.Error()
+		// Без отката новая метка указывает на объект, которого под новым именем нет
+		if _, rollbackErr := c.dataService.RenameFileMetadata(login, rename.NewLabel, rename.Label); rollbackErr != nil {
+			message += "; прежняя метка не восстановлена: " + rollbackErr.Error()
+		}
+		http.Error(w, message, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]string{"message": "файл успешно переименован"})
+}
+
+// VerifyFile проверяет тип загруженного файла по его содержимому: тип, заявленный клиентом при получении
+// ссылки, политика хранилища не проверяет. Файл запрещенного типа удаляется вместе с метаданными
+func (c *CloudUseCase) VerifyFile(w http.ResponseWriter, r *http.Request, label string) {
+	login, err := c.jwtService.ExtractLoginFromToken(r.Header.Get("Authorization"))
+	if err != nil {
+		http.Error(w, "Ошибка получения логина: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if label == "" {
+		http.Error(w, "Не указана метка файла", http.StatusBadRequest)
+		return
+	}
+
+	fileMetadata, _, err := c.dataService.GetFileMetadata(login, label)
+	if err != nil {
+		writeFileError(w, "Ошибка при получении метаданных файла: ", err)
+		return
+	}
+
+	key, err := c.objectKey(login, fileMetadata)
+	if err != nil {
+		http.Error(w, "Ошибка при получении ключа шифрования: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if err := c.checkFileType(login, label, fileMetadata, key); err != nil {
+		writeFileTypeError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]string{"message": "тип файла разрешен"})
+}
+
+func (c *CloudUseCase) ListFiles(w http.ResponseWriter, r *http.Request) {
+	login, err := c.jwtService.ExtractLoginFromToken(r.Header.Get("Authorization"))
+	if err != nil {
+		http.Error(w, "Ошибка получения логина: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	files, err := c.dataService.ListFiles(login)
+	if err != nil {
+		http.Error(w, "Ошибка при получении списка файлов: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(files)
+}
+
+func (c *CloudUseCase) GetFileInfo(w http.ResponseWriter, r *http.Request, label string) {
+	login, err := c.jwtService.ExtractLoginFromToken(r.Header.Get("Authorization"))
+	if err != nil {
+		http.Error(w, "Ошибка получения логина: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if label == "" {
+		http.Error(w, "Не указана метка файла", http.StatusBadRequest)
+		return
+	}
+
+	fileInfo, err := c.dataService.GetFileInfo(login, label)
+	if err != nil {
+		writeFileError(w, "Ошибка при получении сведений о файле: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(fileInfo)
+}
+
+// objectName формирует имя объекта файла пользователя в хранилище
+func objectName(login string, fileMetadata *domain.FileMetadata) string {
+	return fmt.Sprintf("%s_%s.%s", login, fileMetadata.FileName, fileMetadata.Extension)
+}
+
+// writeFileError пишет ошибку работы с файлом в ответ с кодом, соответствующим ее причине
+func writeFileError(w http.ResponseWriter, prefix string, err error) {
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		http.Error(w, "Файл не найден", http.StatusNotFound)
+	case errors.Is(err, domain.ErrAlreadyExists):
+		http.Error(w, prefix+err.Error(), http.StatusConflict)
+	default:
+		http.Error(w, prefix+err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// checkFileType определяет тип файла по первым байтам объекта и проверяет его политикой типов файлов.
+// Файл запрещенного типа удаляется из хранилища вместе с метаданными, и возвращается ошибка
+// domain.ErrFileTypeNotAllowed. Отсутствующий в хранилище объект возвращается ошибкой domain.ErrNotFound
+func (c *CloudUseCase) checkFileType(login string, label string, fileMetadata *domain.FileMetadata, key []byte) error {
+	// Политика без списков разрешает любые типы, объект не запрашивается
+	if len(c.fileTypePolicy.Allow) == 0 && len(c.fileTypePolicy.Deny) == 0 {
+		return nil
+	}
+
+	name := objectName(login, fileMetadata)
+	head, err := c.cloudService.ReadFileHead(name, pkg.SniffLen, key)
+	if err != nil {
+		return err
+	}
+
+	// Текст без сигнатуры уточняется по расширению исходного имени файла, как на клиенте
+	fileName := fileMetadata.OriginalName
+	if fileName == "" {
+		fileName = fileMetadata.FileName + "." + fileMetadata.Extension
+	}
+	mimeType := pkg.DetectContentMimeType(head, fileName)
+	if c.fileTypePolicy.Allows(mimeType) {
+		return nil
+	}
+
+	if err := c.cloudService.DeleteFile(name); err != nil {
+		return fmt.Errorf("ошибка при удалении файла из хранилища: %w", err)
+	}
+	if err := c.dataService.DeleteFileMetadata(login, label); err != nil {
+		return fmt.Errorf("ошибка при удалении метаданных файла: %w", err)
+	}
+	return fmt.Errorf("%w: %s", domain.ErrFileTypeNotAllowed, mimeType)
+}
+
+// writeFileTypeError пишет в ответ ошибку проверки типа файла: запрещенный тип отклоняется с кодом 415
+func writeFileTypeError(w http.ResponseWriter, err error) {
+	if errors.Is(err, domain.ErrFileTypeNotAllowed) {
+		http.Error(w, err.Error(), http.StatusUnsupportedMediaType)
+		return
+	}
+	writeFileError(w, "Ошибка при проверке типа файла: ", err)
+}
+
+// objectKey возвращает ключ SSE-C объекта или nil, если объект хранится без шифрования
+func (c *CloudUseCase) objectKey(login string, fileMetadata *domain.FileMetadata) ([]byte, error) {
+	if !fileMetadata.Encrypted {
+		return nil, nil
+	}
+
+	key, err := c.keyService.GetUserKey(login)
+	if err != nil {
+		return nil, err
+	}
+	if key == nil {
+		return nil, domain.ErrEncryptionDisabled
+	}
+
+	return key, nil
+}
@@ -3,7 +3,9 @@ package usecase
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"github.com/SmirnovND/gophkeeper/internal/domain"
+	"github.com/SmirnovND/gophkeeper/internal/itemtype"
 	"github.com/stretchr/testify/assert"
 	"net/http"
 	"net/http/httptest"
@@ -13,28 +15,57 @@ import (
 
 // MockCloudService - мок для CloudService
 type MockCloudService struct {
-	GenerateUploadLinkFunc   func(fileName string) (string, error)
-	GenerateDownloadLinkFunc func(fileName string) (string, error)
+	GenerateUploadLinkFunc   func(fileName string, maxSize int64, contentType string, key []byte) (string, map[string]string, error)
+	GenerateDownloadLinkFunc func(fileName string, key []byte) (*domain.DownloadLink, error)
+	DeleteFileFunc           func(fileName string) error
+	RenameFileFunc           func(fileName string, newFileName string, key []byte) error
+	ReadFileHeadFunc         func(fileName string, n int64, key []byte) ([]byte, error)
 }
 
-func (m *MockCloudService) GenerateUploadLink(fileName string) (string, error) {
+func (m *MockCloudService) GenerateUploadLink(fileName string, maxSize int64, contentType string, key []byte) (string, map[string]string, error) {
 	if m.GenerateUploadLinkFunc != nil {
-		return m.GenerateUploadLinkFunc(fileName)
+		return m.GenerateUploadLinkFunc(fileName, maxSize, contentType, key)
 	}
-	return "", nil
+	return "", nil, nil
 }
 
-func (m *MockCloudService) GenerateDownloadLink(fileName string) (string, error) {
+func (m *MockCloudService) GenerateDownloadLink(fileName string, key []byte) (*domain.DownloadLink, error) {
 	if m.GenerateDownloadLinkFunc != nil {
-		return m.GenerateDownloadLinkFunc(fileName)
+		return m.GenerateDownloadLinkFunc(fileName, key)
 	}
-	return "", nil
+	return &domain.DownloadLink{}, nil
+}
+
+func (m *MockCloudService) DeleteFile(fileName string) error {
+	if m.DeleteFileFunc != nil {
+		return m.DeleteFileFunc(fileName)
+	}
+	return nil
+}
+
+func (m *MockCloudService) RenameFile(fileName string, newFileName string, key []byte) error {
+	if m.RenameFileFunc != nil {
+		return m.RenameFileFunc(fileName, newFileName, key)
+	}
+	return nil
+}
+
+func (m *MockCloudService) ReadFileHead(fileName string, n int64, key []byte) ([]byte, error) {
+	if m.ReadFileHeadFunc != nil {
+		return m.ReadFileHeadFunc(fileName, n, key)
+	}
+	return nil, nil
 }
 
 // MockDataServiceCloud - мок для DataService
 type MockDataServiceCloud struct {
 	SaveFileMetadataFunc func(login string, label string, fileData *domain.FileData, metadata string) error
 	GetFileMetadataFunc  func(login string, label string) (*domain.FileMetadata, string, error)
+
+	DeleteFileMetadataFunc func(login string, label string) error
+	ListFilesFunc          func(login string) ([]domain.FileInfo, error)
+	GetFileInfoFunc        func(login string, label string) (*domain.FileInfo, error)
+	RenameFileMetadataFunc func(login string, label string, newLabel string) (*domain.FileMetadata, error)
 }
 
 func (m *MockDataServiceCloud) SaveFileMetadata(login string, label string, fileData *domain.FileData, metadata string) error {
@@ -51,44 +82,69 @@ func (m *MockDataServiceCloud) GetFileMetadata(login string, label string) (*dom
 	return nil, "", nil
 }
 
-// Заглушки для остальных методов интерфейса DataService
 func (m *MockDataServiceCloud) DeleteFileMetadata(login string, label string) error {
+	if m.DeleteFileMetadataFunc != nil {
+		return m.DeleteFileMetadataFunc(login, label)
+	}
 	return nil
 }
 
-func (m *MockDataServiceCloud) SaveCredential(login string, label string, credentialData *domain.CredentialData, metadata string) error {
-	return nil
+func (m *MockDataServiceCloud) ListFiles(login string) ([]domain.FileInfo, error) {
+	if m.ListFilesFunc != nil {
+		return m.ListFilesFunc(login)
+	}
+	return nil, nil
 }
 
-func (m *MockDataServiceCloud) GetCredential(login string, label string) (*domain.CredentialData, string, error) {
-	return nil, "", nil
+func (m *MockDataServiceCloud) GetFileInfo(login string, label string) (*domain.FileInfo, error) {
+	if m.GetFileInfoFunc != nil {
+		return m.GetFileInfoFunc(login, label)
+	}
+	return nil, nil
 }
 
-func (m *MockDataServiceCloud) DeleteCredential(login string, label string) error {
-	return nil
+func (m *MockDataServiceCloud) RenameFileMetadata(login string, label string, newLabel string) (*domain.FileMetadata, error) {
+	if m.RenameFileMetadataFunc != nil {
+		return m.RenameFileMetadataFunc(login, label, newLabel)
+	}
+	return nil, nil
 }
 
-func (m *MockDataServiceCloud) SaveCard(login string, label string, cardData *domain.CardData, metadata string) error {
+// Заглушки для остальных методов интерфейса DataService
+
+func (m *MockDataServiceCloud) SaveItem(login string, itemType string, label string, data domain.ItemData, metadata string) error {
 	return nil
 }
 
-func (m *MockDataServiceCloud) GetCard(login string, label string) (*domain.CardData, string, error) {
+func (m *MockDataServiceCloud) GetItem(login string, itemType string, label string) (domain.ItemData, string, error) {
 	return nil, "", nil
 }
 
-func (m *MockDataServiceCloud) DeleteCard(login string, label string) error {
+func (m *MockDataServiceCloud) DeleteItem(login string, itemType string, label string) error {
 	return nil
 }
 
-func (m *MockDataServiceCloud) SaveText(login string, label string, textData *domain.TextData, metadata string) error {
-	return nil
+func (m *MockDataServiceCloud) ListItems(login string) ([]domain.ItemInfo, error) {
+	return nil, nil
 }
 
-func (m *MockDataServiceCloud) GetText(login string, label string) (*domain.TextData, string, error) {
-	return nil, "", nil
+func (m *MockDataServiceCloud) ItemType(login string, itemType string) (*itemtype.Type, error) {
+	return itemtype.Lookup(itemType)
+}
+
+func (m *MockDataServiceCloud) SaveItemTemplate(login string, name string, schema json.RawMessage) (*domain.ItemTemplate, error) {
+	return nil, nil
+}
+
+func (m *MockDataServiceCloud) GetItemTemplate(login string, name string) (*domain.ItemTemplate, error) {
+	return nil, nil
+}
+
+func (m *MockDataServiceCloud) ListItemTemplates(login string) ([]domain.ItemTemplate, error) {
+	return nil, nil
 }
 
-func (m *MockDataServiceCloud) DeleteText(login string, label string) error {
+func (m *MockDataServiceCloud) DeleteItemTemplate(login string, name string) error {
 	return nil
 }
 
@@ -97,7 +153,7 @@ func TestNewCloudUseCase(t *testing.T) {
 	mockCloudService := &MockCloudService{}
 	MockDataServiceCloud := &MockDataServiceCloud{}
 	MockJwtService := &MockJwtService{}
-	cloudUseCase := NewCloudUseCase(mockCloudService, MockDataServiceCloud, MockJwtService)
+	cloudUseCase := NewCloudUseCase(mockCloudService, MockDataServiceCloud, &MockQuotaService{}, MockJwtService, &MockKeyService{}, domain.FileTypePolicy{})
 
 	if cloudUseCase == nil {
 		t.Fatal("NewCloudUseCase вернул nil")
@@ -108,12 +164,18 @@ func TestNewCloudUseCase(t *testing.T) {
 func TestCloudUseCase_GenerateUploadLink_Success(t *testing.T) {
 	// Создаем моки для сервисов
 	mockCloudService := &MockCloudService{
-		GenerateUploadLinkFunc: func(fileName string) (string, error) {
+		GenerateUploadLinkFunc: func(fileName string, maxSize int64, contentType string, key []byte) (string, map[string]string, error) {
 			expectedFileName := "testuser_test-file.txt"
 			if fileName != expectedFileName {
 				t.Errorf("Ожидалось имя файла '%s', получено '%s'", expectedFileName, fileName)
 			}
-			return "https://example.com/upload/test-file.txt", nil
+			if maxSize != 1024 {
+				t.Errorf("Ожидался максимальный размер 1024, получен %d", maxSize)
+			}
+			if contentType != "application/octet-stream" {
+				t.Errorf("Ожидался тип по умолчанию 'application/octet-stream', получен '%s'", contentType)
+			}
+			return "https://example.com/upload/test-file.txt", map[string]string{"policy": "test-policy"}, nil
 		},
 	}
 
@@ -146,7 +208,9 @@ func TestCloudUseCase_GenerateUploadLink_Success(t *testing.T) {
 	cloudUseCase := &CloudUseCase{
 		cloudService: mockCloudService,
 		dataService:  MockDataServiceCloud,
+		quotaService: &MockQuotaService{},
 		jwtService:   mockJwtService,
+		keyService:   &MockKeyService{},
 	}
 
 	// Создаем тестовый HTTP запрос и ответ
@@ -158,6 +222,7 @@ func TestCloudUseCase_GenerateUploadLink_Success(t *testing.T) {
 	fileData := &domain.FileData{
 		Name:      "test-file",
 		Extension: "txt",
+		Size:      1024,
 	}
 
 	// Вызываем метод GenerateUploadLink
@@ -190,6 +255,91 @@ func TestCloudUseCase_GenerateUploadLink_Success(t *testing.T) {
 	if response.Description != expectedDescription {
 		t.Errorf("Ожидалось описание '%s', получено '%s'", expectedDescription, response.Description)
 	}
+
+	if response.FormData["policy"] != "test-policy" {
+		t.Errorf("Ожидалось поле формы policy 'test-policy', получено '%s'", response.FormData["policy"])
+	}
+}
+
+// TestCloudUseCase_GenerateUploadLink_QuotaExceeded проверяет отказ в выдаче ссылки при превышении квоты
+func TestCloudUseCase_GenerateUploadLink_QuotaExceeded(t *testing.T) {
+	mockCloudService := &MockCloudService{
+		GenerateUploadLinkFunc: func(fileName string, maxSize int64, contentType string, key []byte) (string, map[string]string, error) {
+			t.Error("Ссылка не должна генерироваться при превышении квоты")
+			return "", nil, nil
+		},
+	}
+
+	mockQuotaService := &MockQuotaService{
+		CheckFileUploadFunc: func(login string, label string, size int64) error {
+			return fmt.Errorf("%w: размер файла больше допустимого", domain.ErrQuotaExceeded)
+		},
+	}
+
+	cloudUseCase := &CloudUseCase{
+		cloudService: mockCloudService,
+		dataService:  &MockDataServiceCloud{},
+		quotaService: mockQuotaService,
+		jwtService:   &MockJwtService{},
+		keyService:   &MockKeyService{},
+	}
+
+	req := httptest.NewRequest("POST", "/api/files/upload", nil)
+	req.Header.Set("Authorization", "Bearer valid-token")
+	w := httptest.NewRecorder()
+
+	cloudUseCase.GenerateUploadLink(w, req, &domain.FileData{Name: "test-file", Extension: "txt", Size: 1024})
+
+	if w.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("Ожидался статус %d, получен %d", http.StatusRequestEntityTooLarge, w.Code)
+	}
+}
+
+// TestCloudUseCase_GenerateUploadLink_FileTypeNotAllowed проверяет отказ в выдаче ссылки для запрещенного типа файла
+func TestCloudUseCase_GenerateUploadLink_FileTypeNotAllowed(t *testing.T) {
+	mockCloudService := &MockCloudService{
+		GenerateUploadLinkFunc: func(fileName string, maxSize int64, contentType string, key []byte) (string, map[string]string, error) {
+			t.Error("Ссылка не должна генерироваться для запрещенного типа файла")
+			return "", nil, nil
+		},
+	}
+
+	policy := domain.FileTypePolicy{
+		Allow: []string{"image/*", "application/pdf"},
+		Deny:  []string{"image/svg+xml"},
+	}
+
+	cloudUseCase := &CloudUseCase{
+		cloudService:   mockCloudService,
+		dataService:    &MockDataServiceCloud{},
+		quotaService:   &MockQuotaService{},
+		jwtService:     &MockJwtService{},
+		keyService:     &MockKeyService{},
+		fileTypePolicy: policy,
+	}
+
+	tests := []struct {
+		name     string
+		mimeType string
+	}{
+		{"NotInAllowList", "application/x-msdownload"},
+		{"Denied", "image/svg+xml"},
+		{"EmptyType", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/api/files/upload", nil)
+			req.Header.Set("Authorization", "Bearer valid-token")
+			w := httptest.NewRecorder()
+
+			cloudUseCase.GenerateUploadLink(w, req, &domain.FileData{Name: "file", Extension: "bin", Size: 10, MimeType: tt.mimeType})
+
+			if w.Code != http.StatusUnsupportedMediaType {
+				t.Errorf("Ожидался статус %d, получен %d", http.StatusUnsupportedMediaType, w.Code)
+			}
+		})
+	}
 }
 
 // TestCloudUseCase_GenerateUploadLink_TokenError проверяет обработку ошибки при извлечении логина из токена
@@ -209,7 +359,9 @@ func TestCloudUseCase_GenerateUploadLink_TokenError(t *testing.T) {
 	cloudUseCase := &CloudUseCase{
 		cloudService: mockCloudService,
 		dataService:  MockDataServiceCloud,
+		quotaService: &MockQuotaService{},
 		jwtService:   mockJwtService,
+		keyService:   &MockKeyService{},
 	}
 
 	// Создаем тестовый HTTP запрос и ответ
@@ -221,6 +373,7 @@ func TestCloudUseCase_GenerateUploadLink_TokenError(t *testing.T) {
 	fileData := &domain.FileData{
 		Name:      "test-file",
 		Extension: "txt",
+		Size:      1024,
 	}
 
 	// Вызываем метод GenerateUploadLink
@@ -254,7 +407,9 @@ func TestCloudUseCase_GenerateUploadLink_InvalidFileData(t *testing.T) {
 	cloudUseCase := &CloudUseCase{
 		cloudService: mockCloudService,
 		dataService:  MockDataServiceCloud,
+		quotaService: &MockQuotaService{},
 		jwtService:   mockJwtService,
+		keyService:   &MockKeyService{},
 	}
 
 	// Создаем тестовый HTTP запрос и ответ
@@ -296,8 +451,8 @@ func TestCloudUseCase_GenerateUploadLink_InvalidFileData(t *testing.T) {
 func TestCloudUseCase_GenerateUploadLink_CloudServiceError(t *testing.T) {
 	// Создаем моки для сервисов
 	mockCloudService := &MockCloudService{
-		GenerateUploadLinkFunc: func(fileName string) (string, error) {
-			return "", errors.New("ошибка генерации ссылки")
+		GenerateUploadLinkFunc: func(fileName string, maxSize int64, contentType string, key []byte) (string, map[string]string, error) {
+			return "", nil, errors.New("ошибка генерации ссылки")
 		},
 	}
 	MockDataServiceCloud := &MockDataServiceCloud{}
@@ -313,7 +468,9 @@ func TestCloudUseCase_GenerateUploadLink_CloudServiceError(t *testing.T) {
 	cloudUseCase := &CloudUseCase{
 		cloudService: mockCloudService,
 		dataService:  MockDataServiceCloud,
+		quotaService: &MockQuotaService{},
 		jwtService:   mockJwtService,
+		keyService:   &MockKeyService{},
 	}
 
 	// Создаем тестовый HTTP запрос и ответ
@@ -325,6 +482,7 @@ func TestCloudUseCase_GenerateUploadLink_CloudServiceError(t *testing.T) {
 	fileData := &domain.FileData{
 		Name:      "test-file",
 		Extension: "txt",
+		Size:      1024,
 	}
 
 	// Вызываем метод GenerateUploadLink
@@ -345,8 +503,8 @@ func TestCloudUseCase_GenerateUploadLink_CloudServiceError(t *testing.T) {
 func TestCloudUseCase_GenerateUploadLink_DataServiceError(t *testing.T) {
 	// Создаем моки для сервисов
 	mockCloudService := &MockCloudService{
-		GenerateUploadLinkFunc: func(fileName string) (string, error) {
-			return "https://example.com/upload/test-file.txt", nil
+		GenerateUploadLinkFunc: func(fileName string, maxSize int64, contentType string, key []byte) (string, map[string]string, error) {
+			return "https://example.com/upload/test-file.txt", nil, nil
 		},
 	}
 	MockDataServiceCloud := &MockDataServiceCloud{
@@ -366,7 +524,9 @@ func TestCloudUseCase_GenerateUploadLink_DataServiceError(t *testing.T) {
 	cloudUseCase := &CloudUseCase{
 		cloudService: mockCloudService,
 		dataService:  MockDataServiceCloud,
+		quotaService: &MockQuotaService{},
 		jwtService:   mockJwtService,
+		keyService:   &MockKeyService{},
 	}
 
 	// Создаем тестовый HTTP запрос и ответ
@@ -378,6 +538,7 @@ func TestCloudUseCase_GenerateUploadLink_DataServiceError(t *testing.T) {
 	fileData := &domain.FileData{
 		Name:      "test-file",
 		Extension: "txt",
+		Size:      1024,
 	}
 
 	// Вызываем метод GenerateUploadLink
@@ -394,16 +555,40 @@ func TestCloudUseCase_GenerateUploadLink_DataServiceError(t *testing.T) {
 	}
 }
 
+// TestCloudUseCase_GenerateUploadLink_QuotaExceededOnSave проверяет ответ 413, если квоту исчерпала
+// параллельная загрузка после предварительной проверки
+func TestCloudUseCase_GenerateUploadLink_QuotaExceededOnSave(t *testing.T) {
+	cloudUseCase := &CloudUseCase{
+		cloudService: &MockCloudService{},
+		dataService: &MockDataServiceCloud{
+			SaveFileMetadataFunc: func(login string, label string, fileData *domain.FileData, metadata string) error {
+				return fmt.Errorf("ошибка при сохранении метаданных файла: %w: достигнуто максимальное количество записей 2", domain.ErrQuotaExceeded)
+			},
+		},
+		quotaService: &MockQuotaService{},
+		jwtService:   &MockJwtService{},
+		keyService:   &MockKeyService{},
+	}
+
+	req := httptest.NewRequest("POST", "/api/files/upload", nil)
+	req.Header.Set("Authorization", "Bearer valid-token")
+	w := httptest.NewRecorder()
+
+	cloudUseCase.GenerateUploadLink(w, req, &domain.FileData{Name: "test-file", Extension: "txt", Size: 1024})
+
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+}
+
 // TestCloudUseCase_GenerateDownloadLink_Success проверяет успешную генерацию ссылки для скачивания
 func TestCloudUseCase_GenerateDownloadLink_Success(t *testing.T) {
 	// Создаем моки для сервисов
 	mockCloudService := &MockCloudService{
-		GenerateDownloadLinkFunc: func(fileName string) (string, error) {
+		GenerateDownloadLinkFunc: func(fileName string, key []byte) (*domain.DownloadLink, error) {
 			expectedFileName := "testuser_test-file.txt"
 			if fileName != expectedFileName {
 				t.Errorf("Ожидалось имя файла '%s', получено '%s'", expectedFileName, fileName)
 			}
-			return "https://example.com/download/test-file.txt", nil
+			return &domain.DownloadLink{URL: "https://example.com/download/test-file.txt"}, nil
 		},
 	}
 
@@ -433,7 +618,9 @@ func TestCloudUseCase_GenerateDownloadLink_Success(t *testing.T) {
 	cloudUseCase := &CloudUseCase{
 		cloudService: mockCloudService,
 		dataService:  MockDataServiceCloud,
+		quotaService: &MockQuotaService{},
 		jwtService:   mockJwtService,
+		keyService:   &MockKeyService{},
 	}
 
 	// Создаем тестовый HTTP запрос и ответ
@@ -502,7 +689,9 @@ func TestCloudUseCase_GenerateDownloadLink_TokenError(t *testing.T) {
 	cloudUseCase := &CloudUseCase{
 		cloudService: mockCloudService,
 		dataService:  MockDataServiceCloud,
+		quotaService: &MockQuotaService{},
 		jwtService:   mockJwtService,
+		keyService:   &MockKeyService{},
 	}
 
 	// Создаем тестовый HTTP запрос и ответ
@@ -535,7 +724,9 @@ func TestCloudUseCase_GenerateDownloadLink_EmptyLabel(t *testing.T) {
 	cloudUseCase := &CloudUseCase{
 		cloudService: mockCloudService,
 		dataService:  MockDataServiceCloud,
+		quotaService: &MockQuotaService{},
 		jwtService:   MockJwtService,
+		keyService:   &MockKeyService{},
 	}
 
 	// Создаем тестовый HTTP запрос и ответ
@@ -572,7 +763,9 @@ func TestCloudUseCase_GenerateDownloadLink_GetFileMetadataError(t *testing.T) {
 	cloudUseCase := &CloudUseCase{
 		cloudService: mockCloudService,
 		dataService:  MockDataServiceCloud,
+		quotaService: &MockQuotaService{},
 		jwtService:   mockJwtService,
+		keyService:   &MockKeyService{},
 	}
 
 	// Создаем тестовый HTTP запрос и ответ
@@ -598,8 +791,8 @@ func TestCloudUseCase_GenerateDownloadLink_GetFileMetadataError(t *testing.T) {
 func TestCloudUseCase_GenerateDownloadLink_GenerateDownloadLinkError(t *testing.T) {
 	// Создаем моки для сервисов
 	mockCloudService := &MockCloudService{
-		GenerateDownloadLinkFunc: func(fileName string) (string, error) {
-			return "", errors.New("ошибка генерации ссылки для скачивания")
+		GenerateDownloadLinkFunc: func(fileName string, key []byte) (*domain.DownloadLink, error) {
+			return nil, errors.New("ошибка генерации ссылки для скачивания")
 		},
 	}
 	mockJwtService := &MockJwtService{
@@ -620,7 +813,9 @@ func TestCloudUseCase_GenerateDownloadLink_GenerateDownloadLinkError(t *testing.
 	cloudUseCase := &CloudUseCase{
 		cloudService: mockCloudService,
 		dataService:  MockDataServiceCloud,
+		quotaService: &MockQuotaService{},
 		jwtService:   mockJwtService,
+		keyService:   &MockKeyService{},
 	}
 
 	// Создаем тестовый HTTP запрос и ответ
@@ -641,3 +836,450 @@ func TestCloudUseCase_GenerateDownloadLink_GenerateDownloadLinkError(t *testing.
 		t.Errorf("Ожидалось сообщение об ошибке с текстом 'Ошибка при генерации ссылки для скачивания', получено '%s'", w.Body.String())
 	}
 }
+
+// TestCloudUseCase_DeleteFile проверяет удаление файла вместе с объектом в хранилище
+func TestCloudUseCase_DeleteFile(t *testing.T) {
+	deletedObject := ""
+	metadataDeleted := false
+
+	cloudUseCase := &CloudUseCase{
+		cloudService: &MockCloudService{
+			DeleteFileFunc: func(fileName string) error {
+				deletedObject = fileName
+				return nil
+			},
+		},
+		dataService: &MockDataServiceCloud{
+			GetFileMetadataFunc: func(login string, label string) (*domain.FileMetadata, string, error) {
+				return &domain.FileMetadata{FileName: "test-file", Extension: "txt"}, "", nil
+			},
+			DeleteFileMetadataFunc: func(login string, label string) error {
+				metadataDeleted = true
+				return nil
+			},
+		},
+		quotaService: &MockQuotaService{},
+		jwtService:   &MockJwtService{},
+		keyService:   &MockKeyService{},
+	}
+
+	req := httptest.NewRequest("DELETE", "/api/file?label=test-file", nil)
+	req.Header.Set("Authorization", "Bearer valid-token")
+	w := httptest.NewRecorder()
+
+	cloudUseCase.DeleteFile(w, req, "test-file")
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "testuser_test-file.txt", deletedObject)
+	assert.True(t, metadataDeleted)
+}
+
+// TestCloudUseCase_VerifyFile проверяет определение типа загруженного файла по содержимому
+// и удаление файла запрещенного типа
+func TestCloudUseCase_VerifyFile(t *testing.T) {
+	policy := domain.FileTypePolicy{
+		Allow: []string{"image/*", "text/plain"},
+	}
+
+	tests := []struct {
+		name         string
+		head         []byte
+		headErr      error
+		expectedCode int
+		deleted      bool
+	}{
+		{"Allowed", []byte("\x89PNG\r\n\x1a\n"), nil, http.StatusOK, false},
+		// Клиент заявил картинку, но загрузил исполняемый файл
+		{"Denied", []byte("MZ\x90\x00\x03\x00"), nil, http.StatusUnsupportedMediaType, true},
+		{"NotUploaded", nil, domain.ErrNotFound, http.StatusNotFound, false},
+		{"StorageError", nil, errors.New("storage error"), http.StatusInternalServerError, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			readObject := ""
+			deletedObject := ""
+			metadataDeleted := false
+
+			cloudUseCase := &CloudUseCase{
+				cloudService: &MockCloudService{
+					ReadFileHeadFunc: func(fileName string, n int64, key []byte) ([]byte, error) {
+						readObject = fileName
+						assert.Equal(t, int64(512), n)
+						return tt.head, tt.headErr
+					},
+					DeleteFileFunc: func(fileName string) error {
+						deletedObject = fileName
+						return nil
+					},
+				},
+				dataService: &MockDataServiceCloud{
+					GetFileMetadataFunc: func(login string, label string) (*domain.FileMetadata, string, error) {
+						return &domain.FileMetadata{FileName: "photo", Extension: "png", MimeType: "image/png", OriginalName: "photo.png"}, "", nil
+					},
+					DeleteFileMetadataFunc: func(login string, label string) error {
+						metadataDeleted = true
+						return nil
+					},
+				},
+				quotaService:   &MockQuotaService{},
+				jwtService:     &MockJwtService{},
+				keyService:     &MockKeyService{},
+				fileTypePolicy: policy,
+			}
+
+			req := httptest.NewRequest("POST", "/api/file/verify?label=photo", nil)
+			req.Header.Set("Authorization", "Bearer valid-token")
+			w := httptest.NewRecorder()
+
+			cloudUseCase.VerifyFile(w, req, "photo")
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			assert.Equal(t, "testuser_photo.png", readObject)
+			assert.Equal(t, tt.deleted, metadataDeleted)
+			if tt.deleted {
+				assert.Equal(t, "testuser_photo.png", deletedObject)
+				assert.Contains(t, w.Body.String(), "application/octet-stream")
+			} else {
+				assert.Empty(t, deletedObject)
+			}
+		})
+	}
+}
+
+// TestCloudUseCase_GenerateDownloadLink_FileTypeNotAllowed проверяет, что ссылка не выдается на файл запрещенного
+// типа, если клиент не запросил проверку после загрузки
+func TestCloudUseCase_GenerateDownloadLink_FileTypeNotAllowed(t *testing.T) {
+	metadataDeleted := false
+
+	cloudUseCase := &CloudUseCase{
+		cloudService: &MockCloudService{
+			ReadFileHeadFunc: func(fileName string, n int64, key []byte) ([]byte, error) {
+				return []byte("MZ\x90\x00\x03\x00"), nil
+			},
+			GenerateDownloadLinkFunc: func(fileName string, key []byte) (*domain.DownloadLink, error) {
+				t.Error("Ссылка не должна генерироваться для файла запрещенного типа")
+				return nil, nil
+			},
+		},
+		dataService: &MockDataServiceCloud{
+			GetFileMetadataFunc: func(login string, label string) (*domain.FileMetadata, string, error) {
+				return &domain.FileMetadata{FileName: "photo", Extension: "png", MimeType: "image/png"}, "", nil
+			},
+			DeleteFileMetadataFunc: func(login string, label string) error {
+				metadataDeleted = true
+				return nil
+			},
+		},
+		quotaService:   &MockQuotaService{},
+		jwtService:     &MockJwtService{},
+		keyService:     &MockKeyService{},
+		fileTypePolicy: domain.FileTypePolicy{Deny: []string{"application/octet-stream"}},
+	}
+
+	req := httptest.NewRequest("GET", "/api/file/download?label=photo", nil)
+	req.Header.Set("Authorization", "Bearer valid-token")
+	w := httptest.NewRecorder()
+
+	cloudUseCase.GenerateDownloadLink(w, req, "photo")
+
+	assert.Equal(t, http.StatusUnsupportedMediaType, w.Code)
+	assert.True(t, metadataDeleted)
+}
+
+// TestCloudUseCase_DeleteFile_NotFound проверяет ответ 404 для несуществующего файла
+func TestCloudUseCase_DeleteFile_NotFound(t *testing.T) {
+	cloudUseCase := &CloudUseCase{
+		cloudService: &MockCloudService{
+			DeleteFileFunc: func(fileName string) error {
+				t.Error("Объект не должен удаляться, если метаданные не найдены")
+				return nil
+			},
+		},
+		dataService: &MockDataServiceCloud{
+			GetFileMetadataFunc: func(login string, label string) (*domain.FileMetadata, string, error) {
+				return nil, "", fmt.Errorf("ошибка при получении метаданных файла: %w", domain.ErrNotFound)
+			},
+		},
+		quotaService: &MockQuotaService{},
+		jwtService:   &MockJwtService{},
+		keyService:   &MockKeyService{},
+	}
+
+	req := httptest.NewRequest("DELETE", "/api/file?label=missing", nil)
+	req.Header.Set("Authorization", "Bearer valid-token")
+	w := httptest.NewRecorder()
+
+	cloudUseCase.DeleteFile(w, req, "missing")
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+// TestCloudUseCase_RenameFile проверяет переименование файла и перенос объекта
+func TestCloudUseCase_RenameFile(t *testing.T) {
+	var from, to string
+
+	cloudUseCase := &CloudUseCase{
+		cloudService: &MockCloudService{
+			RenameFileFunc: func(fileName string, newFileName string, key []byte) error {
+				from, to = fileName, newFileName
+				return nil
+			},
+		},
+		dataService: &MockDataServiceCloud{
+			RenameFileMetadataFunc: func(login string, label string, newLabel string) (*domain.FileMetadata, error) {
+				return &domain.FileMetadata{FileName: label, Extension: "txt"}, nil
+			},
+		},
+		quotaService: &MockQuotaService{},
+		jwtService:   &MockJwtService{},
+		keyService:   &MockKeyService{},
+	}
+
+	req := httptest.NewRequest("POST", "/api/file/rename", nil)
+	req.Header.Set("Authorization", "Bearer valid-token")
+	w := httptest.NewRecorder()
+
+	cloudUseCase.RenameFile(w, req, &domain.FileRename{Label: "old", NewLabel: "new"})
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "testuser_old.txt", from)
+	assert.Equal(t, "testuser_new.txt", to)
+}
+
+// TestCloudUseCase_RenameFile_Conflict проверяет ответ 409, если новая метка занята
+func TestCloudUseCase_RenameFile_Conflict(t *testing.T) {
+	cloudUseCase := &CloudUseCase{
+		cloudService: &MockCloudService{},
+		dataService: &MockDataServiceCloud{
+			RenameFileMetadataFunc: func(login string, label string, newLabel string) (*domain.FileMetadata, error) {
+				return nil, fmt.Errorf("метка '%s' уже используется: %w", newLabel, domain.ErrAlreadyExists)
+			},
+		},
+		quotaService: &MockQuotaService{},
+		jwtService:   &MockJwtService{},
+		keyService:   &MockKeyService{},
+	}
+
+	req := httptest.NewRequest("POST", "/api/file/rename", nil)
+	req.Header.Set("Authorization", "Bearer valid-token")
+	w := httptest.NewRecorder()
+
+	cloudUseCase.RenameFile(w, req, &domain.FileRename{Label: "old", NewLabel: "taken"})
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+}
+
+// TestCloudUseCase_RenameFile_StorageError проверяет возврат прежней метки при ошибке хранилища
+func TestCloudUseCase_RenameFile_StorageError(t *testing.T) {
+	var renames [][2]string
+
+	cloudUseCase := &CloudUseCase{
+		cloudService: &MockCloudService{
+			RenameFileFunc: func(fileName string, newFileName string, key []byte) error {
+				return errors.New("хранилище недоступно")
+			},
+		},
+		dataService: &MockDataServiceCloud{
+			RenameFileMetadataFunc: func(login string, label string, newLabel string) (*domain.FileMetadata, error) {
+				renames = append(renames, [2]string{label, newLabel})
+				return &domain.FileMetadata{FileName: label, Extension: "txt"}, nil
+			},
+		},
+		quotaService: &MockQuotaService{},
+		jwtService:   &MockJwtService{},
+		keyService:   &MockKeyService{},
+	}
+
+	req := httptest.NewRequest("POST", "/api/file/rename", nil)
+	req.Header.Set("Authorization", "Bearer valid-token")
+	w := httptest.NewRecorder()
+
+	cloudUseCase.RenameFile(w, req, &domain.FileRename{Label: "old", NewLabel: "new"})
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Equal(t, [][2]string{{"old", "new"}, {"new", "old"}}, renames)
+}
+
+// TestCloudUseCase_RenameFile_RollbackError проверяет, что ошибка отката метки попадает в ответ
+func TestCloudUseCase_RenameFile_RollbackError(t *testing.T) {
+	cloudUseCase := &CloudUseCase{
+		cloudService: &MockCloudService{
+			RenameFileFunc: func(fileName string, newFileName string, key []byte) error {
+				return errors.New("хранилище недоступно")
+			},
+		},
+		dataService: &MockDataServiceCloud{
+			RenameFileMetadataFunc: func(login string, label string, newLabel string) (*domain.FileMetadata, error) {
+				if label == "new" {
+					return nil, errors.New("база данных недоступна")
+				}
+				return &domain.FileMetadata{FileName: label, Extension: "txt"}, nil
+			},
+		},
+		quotaService: &MockQuotaService{},
+		jwtService:   &MockJwtService{},
+		keyService:   &MockKeyService{},
+	}
+
+	req := httptest.NewRequest("POST", "/api/file/rename", nil)
+	req.Header.Set("Authorization", "Bearer valid-token")
+	w := httptest.NewRecorder()
+
+	cloudUseCase.RenameFile(w, req, &domain.FileRename{Label: "old", NewLabel: "new"})
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Contains(t, w.Body.String(), "хранилище недоступно")
+	assert.Contains(t, w.Body.String(), "прежняя метка не восстановлена: база данных недоступна")
+}
+
+// TestCloudUseCase_ListFiles проверяет получение списка файлов
+func TestCloudUseCase_ListFiles(t *testing.T) {
+	cloudUseCase := &CloudUseCase{
+		cloudService: &MockCloudService{},
+		dataService: &MockDataServiceCloud{
+			ListFilesFunc: func(login string) ([]domain.FileInfo, error) {
+				return []domain.FileInfo{{Label: "a", Extension: "txt", Size: 1}}, nil
+			},
+		},
+		quotaService: &MockQuotaService{},
+		jwtService:   &MockJwtService{},
+		keyService:   &MockKeyService{},
+	}
+
+	req := httptest.NewRequest("GET", "/api/file/list", nil)
+	req.Header.Set("Authorization", "Bearer valid-token")
+	w := httptest.NewRecorder()
+
+	cloudUseCase.ListFiles(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var files []domain.FileInfo
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&files))
+	assert.Len(t, files, 1)
+	assert.Equal(t, "a", files[0].Label)
+}
+
+// TestCloudUseCase_Encryption проверяет передачу ключа пользователя в хранилище для зашифрованных объектов
+func TestCloudUseCase_Encryption(t *testing.T) {
+	userKey := []byte("0123456789abcdef0123456789abcdef")
+
+	t.Run("Upload", func(t *testing.T) {
+		var savedEncrypted bool
+		cloudUseCase := &CloudUseCase{
+			cloudService: &MockCloudService{
+				GenerateUploadLinkFunc: func(fileName string, maxSize int64, contentType string, key []byte) (string, map[string]string, error) {
+					if string(key) != string(userKey) {
+						t.Error("Ожидалась передача ключа пользователя при загрузке")
+					}
+					return "https://example.com/upload", map[string]string{}, nil
+				},
+			},
+			dataService: &MockDataServiceCloud{
+				SaveFileMetadataFunc: func(login string, label string, fileData *domain.FileData, metadata string) error {
+					savedEncrypted = fileData.Encrypted
+					return nil
+				},
+			},
+			quotaService: &MockQuotaService{},
+			jwtService: &MockJwtService{
+				ExtractLoginFromTokenFunc: func(tokenString string) (string, error) {
+					return "testuser", nil
+				},
+			},
+			keyService: &MockKeyService{
+				GetUserKeyFunc: func(login string) ([]byte, error) {
+					return userKey, nil
+				},
+			},
+		}
+
+		req := httptest.NewRequest("POST", "/api/file/upload", nil)
+		w := httptest.NewRecorder()
+		cloudUseCase.GenerateUploadLink(w, req, &domain.FileData{Name: "file", Extension: "txt", Size: 10, MimeType: "text/plain"})
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("Ожидался статус %d, получен %d", http.StatusOK, w.Code)
+		}
+		if !savedEncrypted {
+			t.Error("Ожидалась отметка о шифровании в метаданных файла")
+		}
+	})
+
+	t.Run("Download", func(t *testing.T) {
+		cloudUseCase := &CloudUseCase{
+			cloudService: &MockCloudService{
+				GenerateDownloadLinkFunc: func(fileName string, key []byte) (*domain.DownloadLink, error) {
+					if string(key) != string(userKey) {
+						t.Error("Ожидалась передача ключа пользователя при скачивании")
+					}
+					return &domain.DownloadLink{
+						URL:     "https://example.com/download",
+						Headers: map[string]string{"X-Amz-Server-Side-Encryption-Customer-Algorithm": "AES256"},
+					}, nil
+				},
+			},
+			dataService: &MockDataServiceCloud{
+				GetFileMetadataFunc: func(login string, label string) (*domain.FileMetadata, string, error) {
+					return &domain.FileMetadata{FileName: label, Extension: "txt", Encrypted: true}, "", nil
+				},
+			},
+			quotaService: &MockQuotaService{},
+			jwtService: &MockJwtService{
+				ExtractLoginFromTokenFunc: func(tokenString string) (string, error) {
+					return "testuser", nil
+				},
+			},
+			keyService: &MockKeyService{
+				GetUserKeyFunc: func(login string) ([]byte, error) {
+					return userKey, nil
+				},
+			},
+		}
+
+		req := httptest.NewRequest("GET", "/api/file/download?label=file", nil)
+		w := httptest.NewRecorder()
+		cloudUseCase.GenerateDownloadLink(w, req, "file")
+
+		var response struct {
+			Headers map[string]string `json:"headers"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+			t.Fatalf("Ошибка при разборе JSON ответа: %v", err)
+		}
+		if response.Headers["X-Amz-Server-Side-Encryption-Customer-Algorithm"] != "AES256" {
+			t.Errorf("Ожидались заголовки SSE-C в ответе, получено %v", response.Headers)
+		}
+	})
+
+	t.Run("EncryptionDisabled", func(t *testing.T) {
+		cloudUseCase := &CloudUseCase{
+			cloudService: &MockCloudService{
+				GenerateDownloadLinkFunc: func(fileName string, key []byte) (*domain.DownloadLink, error) {
+					t.Error("Ссылка не должна генерироваться без ключа для зашифрованного объекта")
+					return nil, nil
+				},
+			},
+			dataService: &MockDataServiceCloud{
+				GetFileMetadataFunc: func(login string, label string) (*domain.FileMetadata, string, error) {
+					return &domain.FileMetadata{FileName: label, Extension: "txt", Encrypted: true}, "", nil
+				},
+			},
+			quotaService: &MockQuotaService{},
+			jwtService: &MockJwtService{
+				ExtractLoginFromTokenFunc: func(tokenString string) (string, error) {
+					return "testuser", nil
+				},
+			},
+			keyService: &MockKeyService{},
+		}
+
+		req := httptest.NewRequest("GET", "/api/file/download?label=file", nil)
+		w := httptest.NewRecorder()
+		cloudUseCase.GenerateDownloadLink(w, req, "file")
+
+		if w.Code != http.StatusInternalServerError {
+			t.Errorf("Ожидался статус %d, получен %d", http.StatusInternalServerError, w.Code)
+		}
+	})
+}
@@ -2,98 +2,96 @@ package usecase
 
 import (
 	"encoding/json"
+	"errors"
 	"github.com/SmirnovND/gophkeeper/internal/domain"
 	"github.com/SmirnovND/gophkeeper/internal/interfaces"
+	"github.com/SmirnovND/gophkeeper/internal/itemtype"
 	"net/http"
 )
 
 type DataUseCase struct {
-	dataService interfaces.DataService
-	jwtService  interfaces.JwtService
+	dataService  interfaces.DataService
+	quotaService interfaces.QuotaService
+	jwtService   interfaces.JwtService
 }
 
 func NewDataUseCase(
 	dataService interfaces.DataService,
+	quotaService interfaces.QuotaService,
 	jwtService interfaces.JwtService,
 ) interfaces.DataUseCase {
 	return &DataUseCase{
-		dataService: dataService,
-		jwtService:  jwtService,
+		dataService:  dataService,
+		quotaService: quotaService,
+		jwtService:   jwtService,
 	}
 }
-func (c *DataUseCase) GetCredential(w http.ResponseWriter, r *http.Request, label string) {
-	login, err := c.jwtService.ExtractLoginFromToken(r.Header.Get("Authorization"))
-	if err != nil {
-		http.Error(w, "Ошибка получения логина: "+err.Error(), http.StatusInternalServerError)
-		return
-	}
 
-	credentialData, metadata, err := c.dataService.GetCredential(login, label)
-	if err != nil {
-		if err.Error() == "учетные данные не найдены" {
-			http.Error(w, err.Error(), http.StatusNotFound)
-			return
-		}
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-		return
+// checkItemQuota проверяет квоту на количество записей и пишет ошибку в ответ, если сохранение недопустимо
+func (c *DataUseCase) checkItemQuota(w http.ResponseWriter, login string, label string) bool {
+	err := c.quotaService.CheckItemSave(login, label)
+	if err == nil {
+		return true
 	}
-
-	// Создаем структуру ответа с метаинформацией
-	response := struct {
-		CredentialData *domain.CredentialData `json:"credential_data"`
-		Metadata       string                 `json:"metadata"`
-	}{
-		CredentialData: credentialData,
-		Metadata:       metadata,
+	if errors.Is(err, domain.ErrQuotaExceeded) {
+		http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+		return false
 	}
-
-	// Отправляем данные в ответе
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(response)
+	http.Error(w, "Ошибка при проверке квоты: "+err.Error(), http.StatusInternalServerError)
+	return false
 }
 
-func (c *DataUseCase) SaveCredential(w http.ResponseWriter, r *http.Request, label string, credentialData *domain.CredentialData, metadata string) {
+func (c *DataUseCase) ListItems(w http.ResponseWriter, r *http.Request) {
 	login, err := c.jwtService.ExtractLoginFromToken(r.Header.Get("Authorization"))
 	if err != nil {
 		http.Error(w, "Ошибка получения логина: "+err.Error(), http.StatusInternalServerError)
 		return
 	}
 
-	// Сохраняем данные
-	err = c.dataService.SaveCredential(login, label, credentialData, metadata)
+	items, err := c.dataService.ListItems(login)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		http.Error(w, "Ошибка при получении списка записей: "+err.Error(), http.StatusInternalServerError)
 		return
 	}
 
-	// Отправляем успешный ответ
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(map[string]string{"message": "учетные данные успешно сохранены"})
+	json.NewEncoder(w).Encode(items)
 }
 
-func (c *DataUseCase) SaveCard(w http.ResponseWriter, r *http.Request, label string, cardData *domain.CardData, metadata string) {
+// SaveItem сохраняет запись встроенного или пользовательского типа. Неизвестный тип отклоняется с кодом 404,
+// поля, не соответствующие описанию типа, - с кодом 400
+func (c *DataUseCase) SaveItem(w http.ResponseWriter, r *http.Request, itemType string, label string, data domain.ItemData, metadata string) {
 	login, err := c.jwtService.ExtractLoginFromToken(r.Header.Get("Authorization"))
 	if err != nil {
 		http.Error(w, "Ошибка получения логина: "+err.Error(), http.StatusInternalServerError)
 		return
 	}
 
+	if _, err := c.dataService.ItemType(login, itemType); err != nil {
+		writeItemError(w, err)
+		return
+	}
+	if !c.checkItemQuota(w, login, label) {
+		return
+	}
+
 	// Сохраняем данные
-	err = c.dataService.SaveCard(login, label, cardData, metadata)
+	err = c.dataService.SaveItem(login, itemType, label, data, metadata)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeItemError(w, err)
 		return
 	}
 
 	// Отправляем успешный ответ
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(map[string]string{"message": "данные карты успешно сохранены"})
+	json.NewEncoder(w).Encode(map[string]string{"message": "запись успешно сохранена"})
 }
 
-func (c *DataUseCase) GetCard(w http.ResponseWriter, r *http.Request, label string) {
+// GetItem получает запись зарегистрированного типа и отвечает ее содержимым под ключом view.DataKey.
+// Если view.Redact, значения секретных полей заменяются маской, если view.Lists - поля-списки выводятся списками
+func (c *DataUseCase) GetItem(w http.ResponseWriter, r *http.Request, itemType string, label string, view domain.ItemView) {
 	login, err := c.jwtService.ExtractLoginFromToken(r.Header.Get("Authorization"))
 	if err != nil {
 		http.Error(w, "Ошибка получения логина: "+err.Error(), http.StatusInternalServerError)
@@ -101,23 +99,31 @@ func (c *DataUseCase) GetCard(w http.ResponseWriter, r *http.Request, label stri
 	}
 
 	// Получаем данные
-	cardData, metadata, err := c.dataService.GetCard(login, label)
+	data, metadata, err := c.dataService.GetItem(login, itemType, label)
 	if err != nil {
-		if err.Error() == "данные карты не найдены" {
-			http.Error(w, err.Error(), http.StatusNotFound)
+		writeItemError(w, err)
+		return
+	}
+	var content interface{} = data
+	if view.Redact || view.Lists {
+		t, err := c.dataService.ItemType(login, itemType)
+		if err != nil {
+			writeItemError(w, err)
 			return
 		}
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-		return
+		if view.Redact {
+			data = t.Redact(data)
+		}
+		content = data
+		if view.Lists {
+			content = t.Values(data)
+		}
 	}
 
 	// Создаем структуру ответа с метаинформацией
-	response := struct {
-		CardData *domain.CardData `json:"card_data"`
-		Metadata string           `json:"metadata"`
-	}{
-		CardData: cardData,
-		Metadata: metadata,
+	response := map[string]interface{}{
+		view.DataKey: content,
+		"metadata":   metadata,
 	}
 
 	// Отправляем данные в ответе
@@ -126,127 +132,116 @@ func (c *DataUseCase) GetCard(w http.ResponseWriter, r *http.Request, label stri
 	json.NewEncoder(w).Encode(response)
 }
 
-func (c *DataUseCase) SaveText(w http.ResponseWriter, r *http.Request, label string, textData *domain.TextData, metadata string) {
+// DeleteItem удаляет запись зарегистрированного типа
+func (c *DataUseCase) DeleteItem(w http.ResponseWriter, r *http.Request, itemType string, label string) {
 	login, err := c.jwtService.ExtractLoginFromToken(r.Header.Get("Authorization"))
 	if err != nil {
 		http.Error(w, "Ошибка получения логина: "+err.Error(), http.StatusInternalServerError)
 		return
 	}
 
-	// Сохраняем данные
-	err = c.dataService.SaveText(login, label, textData, metadata)
+	// Удаляем данные
+	err = c.dataService.DeleteItem(login, itemType, label)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeItemError(w, err)
 		return
 	}
 
 	// Отправляем успешный ответ
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(map[string]string{"message": "текстовые данные успешно сохранены"})
+	json.NewEncoder(w).Encode(map[string]string{"message": "запись успешно удалена"})
 }
 
-func (c *DataUseCase) GetText(w http.ResponseWriter, r *http.Request, label string) {
+// SaveItemTemplate сохраняет шаблон пользовательского типа записей. Неверная схема отклоняется с кодом 400
+func (c *DataUseCase) SaveItemTemplate(w http.ResponseWriter, r *http.Request, name string, schema json.RawMessage) {
 	login, err := c.jwtService.ExtractLoginFromToken(r.Header.Get("Authorization"))
 	if err != nil {
 		http.Error(w, "Ошибка получения логина: "+err.Error(), http.StatusInternalServerError)
 		return
 	}
 
-	// Получаем данные
-	textData, metadata, err := c.dataService.GetText(login, label)
+	template, err := c.dataService.SaveItemTemplate(login, name, schema)
 	if err != nil {
-		if err.Error() == "текстовые данные не найдены" {
-			http.Error(w, err.Error(), http.StatusNotFound)
-			return
-		}
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeItemError(w, err)
 		return
 	}
 
-	// Создаем структуру ответа с метаинформацией
-	response := struct {
-		TextData *domain.TextData `json:"text_data"`
-		Metadata string           `json:"metadata"`
-	}{
-		TextData: textData,
-		Metadata: metadata,
-	}
-
-	// Отправляем данные в ответе
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(response)
+	json.NewEncoder(w).Encode(template)
 }
 
-func (c *DataUseCase) DeleteCredential(w http.ResponseWriter, r *http.Request, label string) {
+// GetItemTemplate получает шаблон пользовательского типа записей
+func (c *DataUseCase) GetItemTemplate(w http.ResponseWriter, r *http.Request, name string) {
 	login, err := c.jwtService.ExtractLoginFromToken(r.Header.Get("Authorization"))
 	if err != nil {
 		http.Error(w, "Ошибка получения логина: "+err.Error(), http.StatusInternalServerError)
 		return
 	}
 
-	// Удаляем данные
-	err = c.dataService.DeleteCredential(login, label)
+	template, err := c.dataService.GetItemTemplate(login, name)
 	if err != nil {
-		if err.Error() == "учетные данные не найдены" {
-			http.Error(w, err.Error(), http.StatusNotFound)
-			return
-		}
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeItemError(w, err)
 		return
 	}
 
-	// Отправляем успешный ответ
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(map[string]string{"message": "учетные данные успешно удалены"})
+	json.NewEncoder(w).Encode(template)
 }
 
-func (c *DataUseCase) DeleteCard(w http.ResponseWriter, r *http.Request, label string) {
+// ListItemTemplates возвращает все шаблоны пользовательских типов записей
+func (c *DataUseCase) ListItemTemplates(w http.ResponseWriter, r *http.Request) {
 	login, err := c.jwtService.ExtractLoginFromToken(r.Header.Get("Authorization"))
 	if err != nil {
 		http.Error(w, "Ошибка получения логина: "+err.Error(), http.StatusInternalServerError)
 		return
 	}
 
-	// Удаляем данные
-	err = c.dataService.DeleteCard(login, label)
+	templates, err := c.dataService.ListItemTemplates(login)
 	if err != nil {
-		if err.Error() == "данные карты не найдены" {
-			http.Error(w, err.Error(), http.StatusNotFound)
-			return
-		}
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		http.Error(w, "Ошибка при получении списка шаблонов: "+err.Error(), http.StatusInternalServerError)
 		return
 	}
 
-	// Отправляем успешный ответ
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(map[string]string{"message": "данные карты успешно удалены"})
+	json.NewEncoder(w).Encode(templates)
 }
 
-func (c *DataUseCase) DeleteText(w http.ResponseWriter, r *http.Request, label string) {
+// DeleteItemTemplate удаляет шаблон пользовательского типа записей. Шаблон, по которому сохранены записи,
+// не удаляется и отклоняется с кодом 409
+func (c *DataUseCase) DeleteItemTemplate(w http.ResponseWriter, r *http.Request, name string) {
 	login, err := c.jwtService.ExtractLoginFromToken(r.Header.Get("Authorization"))
 	if err != nil {
 		http.Error(w, "Ошибка получения логина: "+err.Error(), http.StatusInternalServerError)
 		return
 	}
 
-	// Удаляем данные
-	err = c.dataService.DeleteText(login, label)
+	err = c.dataService.DeleteItemTemplate(login, name)
 	if err != nil {
-		if err.Error() == "текстовые данные не найдены" {
-			http.Error(w, err.Error(), http.StatusNotFound)
-			return
-		}
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeItemError(w, err)
 		return
 	}
 
-	// Отправляем успешный ответ
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(map[string]string{"message": "текстовые данные успешно удалены"})
+	json.NewEncoder(w).Encode(map[string]string{"message": "шаблон успешно удален"})
+}
+
+// writeItemError пишет в ответ ошибку работы с записью или шаблоном с кодом, соответствующим ее причине
+func writeItemError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, itemtype.ErrUnknownType), errors.Is(err, domain.ErrNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, itemtype.ErrInvalidItem), errors.Is(err, itemtype.ErrInvalidSchema):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, domain.ErrTemplateInUse):
+		http.Error(w, err.Error(), http.StatusConflict)
+	case errors.Is(err, domain.ErrQuotaExceeded):
+		http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
 }
@@ -3,9 +3,14 @@ package usecase
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
+	"github.com/SmirnovND/gophkeeper/internal/controllers"
 	"github.com/SmirnovND/gophkeeper/internal/domain"
+	"github.com/SmirnovND/gophkeeper/internal/itemtype"
+	"github.com/go-chi/chi/v5"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
 	"strings"
 	"testing"
 )
@@ -14,381 +19,181 @@ import (
 func TestNewDataUseCase(t *testing.T) {
 	mockDataService := &MockDataService{}
 	mockJwtService := &MockJwtService{}
-	dataUseCase := NewDataUseCase(mockDataService, mockJwtService)
+	dataUseCase := NewDataUseCase(mockDataService, &MockQuotaService{}, mockJwtService)
 
 	if dataUseCase == nil {
 		t.Fatal("NewDataUseCase вернул nil")
 	}
 }
 
-// TestDataUseCase_GetCredential_Success проверяет успешное получение учетных данных
-func TestDataUseCase_GetCredential_Success(t *testing.T) {
-	// Создаем мок для DataService
-	mockDataService := &MockDataService{
-		GetCredentialFunc: func(login string, label string) (*domain.CredentialData, string, error) {
-			if login != "testuser" {
-				t.Errorf("Ожидался логин 'testuser', получен '%s'", login)
-			}
-			if label != "test-credential" {
-				t.Errorf("Ожидалась метка 'test-credential', получена '%s'", label)
-			}
-			return &domain.CredentialData{
-				Login:    "service-login",
-				Password: "service-password",
-			}, "", nil
-		},
-	}
-
-	// Создаем мок для JwtService
-	mockJwtService := &MockJwtService{
-		ExtractLoginFromTokenFunc: func(tokenString string) (string, error) {
-			if tokenString == "Bearer valid-token" {
-				return "testuser", nil
-			}
-			return "", errors.New("неверный токен")
-		},
-	}
-
-	// Создаем экземпляр DataUseCase
-	dataUseCase := &DataUseCase{
-		dataService: mockDataService,
-		jwtService:  mockJwtService,
-	}
-
-	// Создаем тестовый HTTP запрос и ответ
-	req := httptest.NewRequest("GET", "/api/data/credential/test-credential", nil)
-	req.Header.Set("Authorization", "Bearer valid-token")
-	w := httptest.NewRecorder()
-
-	// Вызываем метод GetCredential
-	dataUseCase.GetCredential(w, req, "test-credential")
-
-	// Проверяем статус ответа
-	if w.Code != http.StatusOK {
-		t.Errorf("Ожидался статус %d, получен %d", http.StatusOK, w.Code)
-	}
-
-	// Проверяем тип содержимого
-	contentType := w.Header().Get("Content-Type")
-	if contentType != "application/json" {
-		t.Errorf("Ожидался Content-Type 'application/json', получен '%s'", contentType)
-	}
-
-	// Проверяем тело ответа
-	var response struct {
-		CredentialData *domain.CredentialData `json:"credential_data"`
-		Metadata       string                 `json:"metadata"`
-	}
-	err := json.Unmarshal(w.Body.Bytes(), &response)
-	if err != nil {
-		t.Fatalf("Ошибка при разборе JSON ответа: %v", err)
-	}
-
-	if response.CredentialData.Login != "service-login" {
-		t.Errorf("Ожидался логин 'service-login', получен '%s'", response.CredentialData.Login)
-	}
-	if response.CredentialData.Password != "service-password" {
-		t.Errorf("Ожидался пароль 'service-password', получен '%s'", response.CredentialData.Password)
-	}
-}
-
-// TestDataUseCase_GetCredential_TokenError проверяет обработку ошибки при извлечении логина из токена
-func TestDataUseCase_GetCredential_TokenError(t *testing.T) {
-	// Создаем мок для DataService
-	mockDataService := &MockDataService{}
-
-	// Создаем мок для JwtService
-	mockJwtService := &MockJwtService{
-		ExtractLoginFromTokenFunc: func(tokenString string) (string, error) {
-			return "", errors.New("ошибка извлечения логина из токена")
-		},
-	}
-
-	// Создаем экземпляр DataUseCase
-	dataUseCase := &DataUseCase{
-		dataService: mockDataService,
-		jwtService:  mockJwtService,
-	}
-
-	// Создаем тестовый HTTP запрос и ответ
-	req := httptest.NewRequest("GET", "/api/data/credential/test-credential", nil)
-	req.Header.Set("Authorization", "Bearer error-token")
-	w := httptest.NewRecorder()
-
-	// Вызываем метод GetCredential
-	dataUseCase.GetCredential(w, req, "test-credential")
-
-	// Проверяем статус ответа
-	if w.Code != http.StatusInternalServerError {
-		t.Errorf("Ожидался статус %d, получен %d", http.StatusInternalServerError, w.Code)
-	}
-
-	// Проверяем сообщение об ошибке
-	if !strings.Contains(w.Body.String(), "Ошибка получения логина") {
-		t.Errorf("Ожидалось сообщение об ошибке с текстом 'Ошибка получения логина', получено '%s'", w.Body.String())
-	}
-}
-
-// TestDataUseCase_GetCredential_NotFound проверяет обработку ошибки "учетные данные не найдены"
-func TestDataUseCase_GetCredential_NotFound(t *testing.T) {
-	// Создаем мок для DataService
-	mockDataService := &MockDataService{
-		GetCredentialFunc: func(login string, label string) (*domain.CredentialData, string, error) {
-			return nil, "", errors.New("учетные данные не найдены")
-		},
-	}
-
-	// Создаем мок для JwtService
-	mockJwtService := &MockJwtService{
-		ExtractLoginFromTokenFunc: func(tokenString string) (string, error) {
-			return "testuser", nil
+// newItemDataUseCase создает DataUseCase с моком JwtService, который принимает только токен "Bearer valid-token"
+func newItemDataUseCase(dataService *MockDataService) *DataUseCase {
+	return &DataUseCase{
+		dataService:  dataService,
+		quotaService: &MockQuotaService{},
+		jwtService: &MockJwtService{
+			ExtractLoginFromTokenFunc: func(tokenString string) (string, error) {
+				if tokenString == "Bearer valid-token" {
+					return "testuser", nil
+				}
+				return "", errors.New("неверный токен")
+			},
 		},
 	}
-
-	// Создаем экземпляр DataUseCase
-	dataUseCase := &DataUseCase{
-		dataService: mockDataService,
-		jwtService:  mockJwtService,
-	}
-
-	// Создаем тестовый HTTP запрос и ответ
-	req := httptest.NewRequest("GET", "/api/data/credential/test-credential", nil)
-	req.Header.Set("Authorization", "Bearer valid-token")
-	w := httptest.NewRecorder()
-
-	// Вызываем метод GetCredential
-	dataUseCase.GetCredential(w, req, "test-credential")
-
-	// Проверяем статус ответа
-	if w.Code != http.StatusNotFound {
-		t.Errorf("Ожидался статус %d, получен %d", http.StatusNotFound, w.Code)
-	}
-
-	// Проверяем сообщение об ошибке
-	if !strings.Contains(w.Body.String(), "учетные данные не найдены") {
-		t.Errorf("Ожидалось сообщение об ошибке с текстом 'учетные данные не найдены', получено '%s'", w.Body.String())
-	}
 }
 
-// TestDataUseCase_GetCredential_OtherError проверяет обработку других ошибок
-func TestDataUseCase_GetCredential_OtherError(t *testing.T) {
-	// Создаем мок для DataService
-	mockDataService := &MockDataService{
-		GetCredentialFunc: func(login string, label string) (*domain.CredentialData, string, error) {
-			return nil, "", errors.New("внутренняя ошибка сервера")
-		},
-	}
-
-	// Создаем мок для JwtService
-	mockJwtService := &MockJwtService{
-		ExtractLoginFromTokenFunc: func(tokenString string) (string, error) {
-			return "testuser", nil
+// TestDataUseCase_GetItem_Success проверяет ответ с содержимым записи под ключом API v2 и ключом типа API v1
+func TestDataUseCase_GetItem_Success(t *testing.T) {
+	dataUseCase := newItemDataUseCase(&MockDataService{
+		GetItemFunc: func(login string, itemType string, label string) (domain.ItemData, string, error) {
+			if login != "testuser" || itemType != domain.UserDataTypeCredential || label != "test-credential" {
+				t.Errorf("Неожиданные аргументы: %s, %s, %s", login, itemType, label)
+			}
+			return domain.ItemData{"login": "service-login", "password": "service-password"}, "test metadata", nil
 		},
-	}
+	})
 
-	// Создаем экземпляр DataUseCase
-	dataUseCase := &DataUseCase{
-		dataService: mockDataService,
-		jwtService:  mockJwtService,
-	}
-
-	// Создаем тестовый HTTP запрос и ответ
-	req := httptest.NewRequest("GET", "/api/data/credential/test-credential", nil)
-	req.Header.Set("Authorization", "Bearer valid-token")
-	w := httptest.NewRecorder()
+	for _, key := range []string{"data", "credential_data"} {
+		t.Run(key, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/api/v2/items/credential/test-credential", nil)
+			req.Header.Set("Authorization", "Bearer valid-token")
+			w := httptest.NewRecorder()
 
-	// Вызываем метод GetCredential
-	dataUseCase.GetCredential(w, req, "test-credential")
-
-	// Проверяем статус ответа
-	if w.Code != http.StatusInternalServerError {
-		t.Errorf("Ожидался статус %d, получен %d", http.StatusInternalServerError, w.Code)
-	}
+			dataUseCase.GetItem(w, req, domain.UserDataTypeCredential, "test-credential", domain.ItemView{DataKey: key})
 
-	// Проверяем сообщение об ошибке
-	if !strings.Contains(w.Body.String(), "внутренняя ошибка сервера") {
-		t.Errorf("Ожидалось сообщение об ошибке с текстом 'внутренняя ошибка сервера', получено '%s'", w.Body.String())
-	}
-}
+			if w.Code != http.StatusOK {
+				t.Fatalf("Ожидался статус %d, получен %d", http.StatusOK, w.Code)
+			}
+			if contentType := w.Header().Get("Content-Type"); contentType != "application/json" {
+				t.Errorf("Ожидался Content-Type 'application/json', получен '%s'", contentType)
+			}
 
-// TestDataUseCase_SaveCredential_Success проверяет успешное сохранение учетных данных
-func TestDataUseCase_SaveCredential_Success(t *testing.T) {
-	// Создаем мок для DataService
-	mockDataService := &MockDataService{
-		SaveCredentialFunc: func(login string, label string, credentialData *domain.CredentialData, metadata string) error {
-			if login != "testuser" {
-				t.Errorf("Ожидался логин 'testuser', получен '%s'", login)
+			var response map[string]json.RawMessage
+			if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+				t.Fatalf("Ошибка при разборе JSON ответа: %v", err)
 			}
-			if label != "test-credential" {
-				t.Errorf("Ожидалась метка 'test-credential', получена '%s'", label)
+			var credential domain.CredentialData
+			if err := json.Unmarshal(response[key], &credential); err != nil {
+				t.Fatalf("Ошибка при разборе содержимого записи: %v", err)
 			}
-			if credentialData.Login != "service-login" {
-				t.Errorf("Ожидался логин 'service-login', получен '%s'", credentialData.Login)
+			if credential.Login != "service-login" || credential.Password != "service-password" {
+				t.Errorf("Неверное содержимое записи: %+v", credential)
 			}
-			if credentialData.Password != "service-password" {
-				t.Errorf("Ожидался пароль 'service-password', получен '%s'", credentialData.Password)
+			if string(response["metadata"]) != `"test metadata"` {
+				t.Errorf("Неверная метаинформация: %s", response["metadata"])
 			}
-			return nil
-		},
+		})
 	}
+}
 
-	// Создаем мок для JwtService
-	mockJwtService := &MockJwtService{
-		ExtractLoginFromTokenFunc: func(tokenString string) (string, error) {
-			return "testuser", nil
+// TestDataUseCase_GetItem_Redact проверяет скрытие секретных полей в ответе
+func TestDataUseCase_GetItem_Redact(t *testing.T) {
+	dataUseCase := newItemDataUseCase(&MockDataService{
+		GetItemFunc: func(login string, itemType string, label string) (domain.ItemData, string, error) {
+			return domain.ItemData{"number": "4111 1111 1111 1234", "holder": "Test User", "expiry_date": "12/25", "cvv": "123"}, "", nil
 		},
-	}
-
-	// Создаем экземпляр DataUseCase
-	dataUseCase := &DataUseCase{
-		dataService: mockDataService,
-		jwtService:  mockJwtService,
-	}
+	})
 
-	// Создаем тестовый HTTP запрос и ответ
-	req := httptest.NewRequest("POST", "/api/data/credential/test-credential", nil)
+	req := httptest.NewRequest("GET", "/api/v2/items/card/test-card?redact=true", nil)
 	req.Header.Set("Authorization", "Bearer valid-token")
 	w := httptest.NewRecorder()
 
-	// Создаем данные для сохранения
-	credentialData := &domain.CredentialData{
-		Login:    "service-login",
-		Password: "service-password",
-	}
-
-	// Вызываем метод SaveCredential
-	dataUseCase.SaveCredential(w, req, "test-credential", credentialData, "")
-
-	// Проверяем статус ответа
-	if w.Code != http.StatusOK {
-		t.Errorf("Ожидался статус %d, получен %d", http.StatusOK, w.Code)
-	}
+	dataUseCase.GetItem(w, req, domain.UserDataTypeCard, "test-card", domain.ItemView{DataKey: "data", Redact: true})
 
-	// Проверяем тип содержимого
-	contentType := w.Header().Get("Content-Type")
-	if contentType != "application/json" {
-		t.Errorf("Ожидался Content-Type 'application/json', получен '%s'", contentType)
+	var response struct {
+		Data domain.ItemData `json:"data"`
 	}
-
-	// Проверяем тело ответа
-	var response map[string]string
-	err := json.Unmarshal(w.Body.Bytes(), &response)
-	if err != nil {
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
 		t.Fatalf("Ошибка при разборе JSON ответа: %v", err)
 	}
-
-	if message, ok := response["message"]; !ok || message != "учетные данные успешно сохранены" {
-		t.Errorf("Ожидалось сообщение 'учетные данные успешно сохранены', получено '%v'", response)
+	if response.Data["number"] != "•••• 1234" || response.Data["cvv"] != itemtype.Mask || response.Data["holder"] != "Test User" {
+		t.Errorf("Неверно скрыта запись: %v", response.Data)
 	}
 }
 
-// TestDataUseCase_SaveCredential_TokenError проверяет обработку ошибки при извлечении логина из токена
-func TestDataUseCase_SaveCredential_TokenError(t *testing.T) {
-	// Создаем мок для DataService
-	mockDataService := &MockDataService{}
-
-	// Создаем мок для JwtService
-	mockJwtService := &MockJwtService{
-		ExtractLoginFromTokenFunc: func(tokenString string) (string, error) {
-			return "", errors.New("ошибка извлечения логина из токена")
-		},
-	}
-
-	// Создаем экземпляр DataUseCase
-	dataUseCase := &DataUseCase{
-		dataService: mockDataService,
-		jwtService:  mockJwtService,
-	}
-
-	// Создаем тестовый HTTP запрос и ответ
-	req := httptest.NewRequest("POST", "/api/data/credential/test-credential", nil)
-	req.Header.Set("Authorization", "Bearer error-token")
-	w := httptest.NewRecorder()
-
-	// Создаем данные для сохранения
-	credentialData := &domain.CredentialData{
-		Login:    "service-login",
-		Password: "service-password",
-	}
-
-	// Вызываем метод SaveCredential
-	dataUseCase.SaveCredential(w, req, "test-credential", credentialData, "")
-
-	// Проверяем статус ответа
-	if w.Code != http.StatusInternalServerError {
-		t.Errorf("Ожидался статус %d, получен %d", http.StatusInternalServerError, w.Code)
-	}
-
-	// Проверяем сообщение об ошибке
-	if !strings.Contains(w.Body.String(), "Ошибка получения логина") {
-		t.Errorf("Ожидалось сообщение об ошибке с текстом 'Ошибка получения логина', получено '%s'", w.Body.String())
+// TestDataUseCase_Item_TokenError проверяет обработку ошибки при извлечении логина из токена
+func TestDataUseCase_Item_TokenError(t *testing.T) {
+	dataUseCase := newItemDataUseCase(&MockDataService{})
+
+	tests := []struct {
+		name string
+		call func(w http.ResponseWriter, r *http.Request)
+	}{
+		{"SaveItem", func(w http.ResponseWriter, r *http.Request) {
+			dataUseCase.SaveItem(w, r, domain.UserDataTypeText, "note", domain.ItemData{"content": "x"}, "")
+		}},
+		{"GetItem", func(w http.ResponseWriter, r *http.Request) {
+			dataUseCase.GetItem(w, r, domain.UserDataTypeText, "note", domain.ItemView{DataKey: "data"})
+		}},
+		{"DeleteItem", func(w http.ResponseWriter, r *http.Request) {
+			dataUseCase.DeleteItem(w, r, domain.UserDataTypeText, "note")
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/api/v2/items/text/note", nil)
+			req.Header.Set("Authorization", "Bearer error-token")
+			w := httptest.NewRecorder()
+
+			tt.call(w, req)
+
+			if w.Code != http.StatusInternalServerError {
+				t.Errorf("Ожидался статус %d, получен %d", http.StatusInternalServerError, w.Code)
+			}
+			if !strings.Contains(w.Body.String(), "Ошибка получения логина") {
+				t.Errorf("Ожидалось сообщение об ошибке с текстом 'Ошибка получения логина', получено '%s'", w.Body.String())
+			}
+		})
 	}
 }
 
-// TestDataUseCase_SaveCredential_Error проверяет обработку ошибки при сохранении учетных данных
-func TestDataUseCase_SaveCredential_Error(t *testing.T) {
-	// Создаем мок для DataService
-	mockDataService := &MockDataService{
-		SaveCredentialFunc: func(login string, label string, credentialData *domain.CredentialData, metadata string) error {
-			return errors.New("ошибка при сохранении учетных данных")
-		},
-	}
-
-	// Создаем мок для JwtService
-	mockJwtService := &MockJwtService{
-		ExtractLoginFromTokenFunc: func(tokenString string) (string, error) {
-			return "testuser", nil
+// TestDataUseCase_DeleteItem проверяет коды ответа на удаление записи
+func TestDataUseCase_DeleteItem(t *testing.T) {
+	dataUseCase := newItemDataUseCase(&MockDataService{
+		DeleteItemFunc: func(login string, itemType string, label string) error {
+			switch label {
+			case "missing":
+				return fmt.Errorf("запись '%s' не найдена: %w", label, domain.ErrNotFound)
+			case "broken":
+				return errors.New("ошибка при удалении записи")
+			}
+			return nil
 		},
-	}
-
-	// Создаем экземпляр DataUseCase
-	dataUseCase := &DataUseCase{
-		dataService: mockDataService,
-		jwtService:  mockJwtService,
-	}
-
-	// Создаем тестовый HTTP запрос и ответ
-	req := httptest.NewRequest("POST", "/api/data/credential/test-credential", nil)
-	req.Header.Set("Authorization", "Bearer valid-token")
-	w := httptest.NewRecorder()
+	})
 
-	// Создаем данные для сохранения
-	credentialData := &domain.CredentialData{
-		Login:    "service-login",
-		Password: "service-password",
+	tests := []struct {
+		label  string
+		status int
+	}{
+		{"test-card", http.StatusOK},
+		{"missing", http.StatusNotFound},
+		{"broken", http.StatusInternalServerError},
 	}
+	for _, tt := range tests {
+		t.Run(tt.label, func(t *testing.T) {
+			req := httptest.NewRequest("DELETE", "/api/v2/items/card/"+tt.label, nil)
+			req.Header.Set("Authorization", "Bearer valid-token")
+			w := httptest.NewRecorder()
 
-	// Вызываем метод SaveCredential
-	dataUseCase.SaveCredential(w, req, "test-credential", credentialData, "")
-
-	// Проверяем статус ответа
-	if w.Code != http.StatusInternalServerError {
-		t.Errorf("Ожидался статус %d, получен %d", http.StatusInternalServerError, w.Code)
-	}
+			dataUseCase.DeleteItem(w, req, domain.UserDataTypeCard, tt.label)
 
-	// Проверяем сообщение об ошибке
-	if !strings.Contains(w.Body.String(), "ошибка при сохранении учетных данных") {
-		t.Errorf("Ожидалось сообщение об ошибке с текстом 'ошибка при сохранении учетных данных', получено '%s'", w.Body.String())
+			if w.Code != tt.status {
+				t.Errorf("Ожидался статус %d, получен %d", tt.status, w.Code)
+			}
+		})
 	}
 }
 
-// TestDataUseCase_GetCard_Success проверяет успешное получение данных карты
-func TestDataUseCase_GetCard_Success(t *testing.T) {
-	// Создаем мок для DataService
+// TestDataUseCase_SaveItem_Errors проверяет ответы 404 на неизвестный тип и 400 на неверные поля записи
+func TestDataUseCase_SaveItem_Errors(t *testing.T) {
+	// Создаем мок для DataService, проверяющий поля по описанию типа
 	mockDataService := &MockDataService{
-		GetCardFunc: func(login string, label string) (*domain.CardData, string, error) {
-			if login != "testuser" {
-				t.Errorf("Ожидался логин 'testuser', получен '%s'", login)
+		SaveItemFunc: func(login string, itemType string, label string, data domain.ItemData, metadata string) error {
+			described, err := itemtype.Lookup(itemType)
+			if err != nil {
+				return err
 			}
-			if label != "test-card" {
-				t.Errorf("Ожидалась метка 'test-card', получена '%s'", label)
-			}
-			return &domain.CardData{
-				Number:     "1234567890123456",
-				Holder:     "Test User",
-				ExpiryDate: "12/25",
-				CVV:        "123",
-			}, "", nil
+			return described.Validate(data)
 		},
 	}
 
@@ -401,885 +206,220 @@ func TestDataUseCase_GetCard_Success(t *testing.T) {
 
 	// Создаем экземпляр DataUseCase
 	dataUseCase := &DataUseCase{
-		dataService: mockDataService,
-		jwtService:  mockJwtService,
-	}
-
-	// Создаем тестовый HTTP запрос и ответ
-	req := httptest.NewRequest("GET", "/api/data/card/test-card", nil)
-	req.Header.Set("Authorization", "Bearer valid-token")
-	w := httptest.NewRecorder()
-
-	// Вызываем метод GetCard
-	dataUseCase.GetCard(w, req, "test-card")
-
-	// Проверяем статус ответа
-	if w.Code != http.StatusOK {
-		t.Errorf("Ожидался статус %d, получен %d", http.StatusOK, w.Code)
-	}
-
-	// Проверяем тип содержимого
-	contentType := w.Header().Get("Content-Type")
-	if contentType != "application/json" {
-		t.Errorf("Ожидался Content-Type 'application/json', получен '%s'", contentType)
-	}
-
-	// Проверяем тело ответа
-	var response struct {
-		CardData *domain.CardData `json:"card_data"`
-		Metadata string           `json:"metadata"`
-	}
-	err := json.Unmarshal(w.Body.Bytes(), &response)
-	if err != nil {
-		t.Fatalf("Ошибка при разборе JSON ответа: %v", err)
-	}
-
-	if response.CardData.Number != "1234567890123456" {
-		t.Errorf("Ожидался номер карты '1234567890123456', получен '%s'", response.CardData.Number)
-	}
-	if response.CardData.Holder != "Test User" {
-		t.Errorf("Ожидался держатель карты 'Test User', получен '%s'", response.CardData.Holder)
-	}
-	if response.CardData.ExpiryDate != "12/25" {
-		t.Errorf("Ожидался срок действия '12/25', получен '%s'", response.CardData.ExpiryDate)
-	}
-	if response.CardData.CVV != "123" {
-		t.Errorf("Ожидался CVV '123', получен '%s'", response.CardData.CVV)
+		dataService:  mockDataService,
+		quotaService: &MockQuotaService{},
+		jwtService:   mockJwtService,
+	}
+
+	tests := []struct {
+		name     string
+		itemType string
+		data     domain.ItemData
+		status   int
+	}{
+		{"запись сохранена", domain.UserDataTypeAPIToken, domain.ItemData{"service": "GitHub", "token": "ghp_x"}, http.StatusOK},
+		{"учетные данные сохранены", domain.UserDataTypeCredential, domain.ItemData{"login": "alice", "password": "secret"}, http.StatusOK},
+		{"неизвестный тип", "passport", domain.ItemData{"number": "1"}, http.StatusNotFound},
+		{"неверные поля", domain.UserDataTypeAPIToken, domain.ItemData{"service": "GitHub"}, http.StatusBadRequest},
+		{"неверный ключ TOTP", domain.UserDataTypeCredential, domain.ItemData{"login": "alice", "totp": "otpauth://totp/x"}, http.StatusBadRequest},
+		{"неверный ключ SSH", domain.UserDataTypeSSHKey, domain.ItemData{"private_key": "key", "public_key": "ssh-ed25519 AAAA", "fingerprint": "SHA256:x"}, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/api/data/"+tt.itemType+"/github", nil)
+			req.Header.Set("Authorization", "Bearer valid-token")
+			w := httptest.NewRecorder()
+
+			dataUseCase.SaveItem(w, req, tt.itemType, "github", tt.data, "")
+
+			if w.Code != tt.status {
+				t.Errorf("Ожидался статус %d, получен %d: %s", tt.status, w.Code, w.Body.String())
+			}
+		})
 	}
 }
 
-// TestDataUseCase_GetCard_NotFound проверяет обработку ошибки "данные карты не найдены"
-func TestDataUseCase_GetCard_NotFound(t *testing.T) {
-	// Создаем мок для DataService
+// TestDataUseCase_GetItem_NotFound проверяет ответ 404 на отсутствующую запись
+func TestDataUseCase_GetItem_NotFound(t *testing.T) {
 	mockDataService := &MockDataService{
-		GetCardFunc: func(login string, label string) (*domain.CardData, string, error) {
-			return nil, "", errors.New("данные карты не найдены")
+		GetItemFunc: func(login string, itemType string, label string) (domain.ItemData, string, error) {
+			return nil, "", fmt.Errorf("запись '%s' не найдена: %w", label, domain.ErrNotFound)
 		},
 	}
-
-	// Создаем мок для JwtService
 	mockJwtService := &MockJwtService{
 		ExtractLoginFromTokenFunc: func(tokenString string) (string, error) {
 			return "testuser", nil
 		},
 	}
-
-	// Создаем экземпляр DataUseCase
 	dataUseCase := &DataUseCase{
-		dataService: mockDataService,
-		jwtService:  mockJwtService,
+		dataService:  mockDataService,
+		quotaService: &MockQuotaService{},
+		jwtService:   mockJwtService,
 	}
 
-	// Создаем тестовый HTTP запрос и ответ
-	req := httptest.NewRequest("GET", "/api/data/card/test-card", nil)
+	req := httptest.NewRequest("GET", "/api/data/wifi/home", nil)
 	req.Header.Set("Authorization", "Bearer valid-token")
 	w := httptest.NewRecorder()
 
-	// Вызываем метод GetCard
-	dataUseCase.GetCard(w, req, "test-card")
+	dataUseCase.GetItem(w, req, domain.UserDataTypeWiFi, "home", domain.ItemView{DataKey: "data"})
 
-	// Проверяем статус ответа
 	if w.Code != http.StatusNotFound {
 		t.Errorf("Ожидался статус %d, получен %d", http.StatusNotFound, w.Code)
 	}
-
-	// Проверяем сообщение об ошибке
-	if !strings.Contains(w.Body.String(), "данные карты не найдены") {
-		t.Errorf("Ожидалось сообщение об ошибке с текстом 'данные карты не найдены', получено '%s'", w.Body.String())
-	}
 }
 
-// TestDataUseCase_SaveCard_Success проверяет успешное сохранение данных карты
-func TestDataUseCase_SaveCard_Success(t *testing.T) {
-	// Создаем мок для DataService
-	mockDataService := &MockDataService{
-		SaveCardFunc: func(login string, label string, cardData *domain.CardData, metadata string) error {
-			if login != "testuser" {
-				t.Errorf("Ожидался логин 'testuser', получен '%s'", login)
-			}
-			if label != "test-card" {
-				t.Errorf("Ожидалась метка 'test-card', получена '%s'", label)
-			}
-			if cardData.Number != "1234567890123456" {
-				t.Errorf("Ожидался номер карты '1234567890123456', получен '%s'", cardData.Number)
-			}
-			if cardData.Holder != "Test User" {
-				t.Errorf("Ожидался держатель карты 'Test User', получен '%s'", cardData.Holder)
+// TestDataUseCase_ItemTemplates проверяет коды ответа на запросы к шаблонам пользовательских типов
+func TestDataUseCase_ItemTemplates(t *testing.T) {
+	dataUseCase := newItemDataUseCase(&MockDataService{
+		SaveItemTemplateFunc: func(login string, name string, schema json.RawMessage) (*domain.ItemTemplate, error) {
+			if _, err := itemtype.ParseSchema(name, schema); err != nil {
+				return nil, err
 			}
-			if cardData.ExpiryDate != "12/25" {
-				t.Errorf("Ожидался срок действия '12/25', получен '%s'", cardData.ExpiryDate)
-			}
-			if cardData.CVV != "123" {
-				t.Errorf("Ожидался CVV '123', получен '%s'", cardData.CVV)
+			return &domain.ItemTemplate{Name: name, Schema: schema}, nil
+		},
+		DeleteItemTemplateFunc: func(login string, name string) error {
+			switch name {
+			case "db_profile":
+				return fmt.Errorf("шаблон '%s' используется записями (1): %w", name, domain.ErrTemplateInUse)
+			case "missing":
+				return fmt.Errorf("шаблон '%s' не найден: %w", name, domain.ErrNotFound)
 			}
 			return nil
 		},
+		ListItemTemplatesFunc: func(login string) ([]domain.ItemTemplate, error) {
+			return []domain.ItemTemplate{{Name: "db_profile", Schema: json.RawMessage(`{}`)}}, nil
+		},
+	})
+
+	tests := []struct {
+		name   string
+		call   func(w http.ResponseWriter, r *http.Request)
+		status int
+	}{
+		{"сохранение", func(w http.ResponseWriter, r *http.Request) {
+			dataUseCase.SaveItemTemplate(w, r, "db_profile", json.RawMessage(`{"type": "object", "properties": {"host": {"type": "string"}}}`))
+		}, http.StatusOK},
+		{"неверная схема", func(w http.ResponseWriter, r *http.Request) {
+			dataUseCase.SaveItemTemplate(w, r, "db_profile", json.RawMessage(`{"type": "array"}`))
+		}, http.StatusBadRequest},
+		{"имя встроенного типа", func(w http.ResponseWriter, r *http.Request) {
+			dataUseCase.SaveItemTemplate(w, r, "wifi", json.RawMessage(`{"type": "object", "properties": {"ssid": {"type": "string"}}}`))
+		}, http.StatusBadRequest},
+		{"шаблон не найден", func(w http.ResponseWriter, r *http.Request) {
+			dataUseCase.GetItemTemplate(w, r, "missing")
+		}, http.StatusNotFound},
+		{"список", func(w http.ResponseWriter, r *http.Request) {
+			dataUseCase.ListItemTemplates(w, r)
+		}, http.StatusOK},
+		{"удаление", func(w http.ResponseWriter, r *http.Request) {
+			dataUseCase.DeleteItemTemplate(w, r, "unused")
+		}, http.StatusOK},
+		{"удаление используемого шаблона", func(w http.ResponseWriter, r *http.Request) {
+			dataUseCase.DeleteItemTemplate(w, r, "db_profile")
+		}, http.StatusConflict},
+		{"удаление отсутствующего шаблона", func(w http.ResponseWriter, r *http.Request) {
+			dataUseCase.DeleteItemTemplate(w, r, "missing")
+		}, http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/api/v2/templates/db_profile", nil)
+			req.Header.Set("Authorization", "Bearer valid-token")
+			w := httptest.NewRecorder()
+
+			tt.call(w, req)
+
+			if w.Code != tt.status {
+				t.Errorf("Ожидался статус %d, получен %d: %s", tt.status, w.Code, w.Body.String())
+			}
+		})
 	}
+}
 
-	// Создаем мок для JwtService
-	mockJwtService := &MockJwtService{
-		ExtractLoginFromTokenFunc: func(tokenString string) (string, error) {
-			return "testuser", nil
-		},
-	}
-
-	// Создаем экземпляр DataUseCase
-	dataUseCase := &DataUseCase{
-		dataService: mockDataService,
-		jwtService:  mockJwtService,
+// TestDataUseCase_CustomItem проверяет сохранение и скрытие секретов записи пользовательского типа
+func TestDataUseCase_CustomItem(t *testing.T) {
+	dbProfile, err := itemtype.ParseSchema("db_profile", []byte(`{"type": "object", "properties": `+
+		`{"host": {"type": "string"}, "password": {"type": "string", "writeOnly": true}}}`))
+	if err != nil {
+		t.Fatal(err)
 	}
+	dataUseCase := newItemDataUseCase(&MockDataService{
+		ItemTypeFunc: func(login string, itemType string) (*itemtype.Type, error) {
+			if itemType == dbProfile.Name {
+				return dbProfile, nil
+			}
+			return itemtype.Lookup(itemType)
+		},
+		GetItemFunc: func(login string, itemType string, label string) (domain.ItemData, string, error) {
+			return domain.ItemData{"host": "db.local", "password": "s3cret"}, "", nil
+		},
+	})
 
-	// Создаем тестовый HTTP запрос и ответ
-	req := httptest.NewRequest("POST", "/api/data/card/test-card", nil)
+	req := httptest.NewRequest("POST", "/api/v2/items/db_profile/prod", nil)
 	req.Header.Set("Authorization", "Bearer valid-token")
 	w := httptest.NewRecorder()
-
-	// Создаем данные для сохранения
-	cardData := &domain.CardData{
-		Number:     "1234567890123456",
-		Holder:     "Test User",
-		ExpiryDate: "12/25",
-		CVV:        "123",
-	}
-
-	// Вызываем метод SaveCard
-	dataUseCase.SaveCard(w, req, "test-card", cardData, "")
-
-	// Проверяем статус ответа
+	dataUseCase.SaveItem(w, req, "db_profile", "prod", domain.ItemData{"host": "db.local"}, "")
 	if w.Code != http.StatusOK {
-		t.Errorf("Ожидался статус %d, получен %d", http.StatusOK, w.Code)
+		t.Errorf("Ожидался статус %d, получен %d: %s", http.StatusOK, w.Code, w.Body.String())
 	}
 
-	// Проверяем тип содержимого
-	contentType := w.Header().Get("Content-Type")
-	if contentType != "application/json" {
-		t.Errorf("Ожидался Content-Type 'application/json', получен '%s'", contentType)
+	w = httptest.NewRecorder()
+	dataUseCase.GetItem(w, req, "db_profile", "prod", domain.ItemView{DataKey: "data", Redact: true})
+	var response struct {
+		Data domain.ItemData `json:"data"`
 	}
-
-	// Проверяем тело ответа
-	var response map[string]string
-	err := json.Unmarshal(w.Body.Bytes(), &response)
-	if err != nil {
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
 		t.Fatalf("Ошибка при разборе JSON ответа: %v", err)
 	}
-
-	if message, ok := response["message"]; !ok || message != "данные карты успешно сохранены" {
-		t.Errorf("Ожидалось сообщение 'данные карты успешно сохранены', получено '%v'", response)
+	if response.Data["host"] != "db.local" || response.Data["password"] != itemtype.Mask {
+		t.Errorf("Неверно скрыта запись: %v", response.Data)
 	}
 }
 
-// TestDataUseCase_GetText_Success проверяет успешное получение текстовых данных
-func TestDataUseCase_GetText_Success(t *testing.T) {
-	// Создаем мок для DataService
-	mockDataService := &MockDataService{
-		GetTextFunc: func(login string, label string) (*domain.TextData, string, error) {
-			if login != "testuser" {
-				t.Errorf("Ожидался логин 'testuser', получен '%s'", login)
-			}
-			if label != "test-text" {
-				t.Errorf("Ожидалась метка 'test-text', получена '%s'", label)
-			}
-			return &domain.TextData{
-				Content: "test text content",
-			}, "", nil
+// TestDataUseCase_LegacyRoundTrip проверяет, что учетные данные с адресами, сохраненные клиентом API v1,
+// возвращаются ему в том же виде: поля-списки - списками, а не текстом JSON
+func TestDataUseCase_LegacyRoundTrip(t *testing.T) {
+	stored := make(map[string]domain.ItemData)
+	controller := controllers.NewDataController(newItemDataUseCase(&MockDataService{
+		SaveItemFunc: func(login string, itemType string, label string, data domain.ItemData, metadata string) error {
+			stored[label] = data
+			return nil
 		},
-	}
-
-	// Создаем мок для JwtService
-	mockJwtService := &MockJwtService{
-		ExtractLoginFromTokenFunc: func(tokenString string) (string, error) {
-			return "testuser", nil
+		GetItemFunc: func(login string, itemType string, label string) (domain.ItemData, string, error) {
+			return stored[label], "", nil
 		},
-	}
-
-	// Создаем экземпляр DataUseCase
-	dataUseCase := &DataUseCase{
-		dataService: mockDataService,
-		jwtService:  mockJwtService,
-	}
+	}))
+	router := chi.NewRouter()
+	router.Post("/api/data/{type}/{label}", controller.SaveLegacyItem)
+	router.Get("/api/data/{type}/{label}", controller.GetLegacyItem)
 
-	// Создаем тестовый HTTP запрос и ответ
-	req := httptest.NewRequest("GET", "/api/data/text/test-text", nil)
+	credential := `{"login":"alice","password":"s3cret","uris":[{"uri":"https://github.com","match":"host"}],` +
+		`"fields":[{"name":"pin","type":"hidden","value":"1234"}]}`
+	req := httptest.NewRequest("POST", "/api/data/credential/github", strings.NewReader(`{"credential_data":`+credential+`}`))
 	req.Header.Set("Authorization", "Bearer valid-token")
 	w := httptest.NewRecorder()
-
-	// Вызываем метод GetText
-	dataUseCase.GetText(w, req, "test-text")
-
-	// Проверяем статус ответа
+	router.ServeHTTP(w, req)
 	if w.Code != http.StatusOK {
-		t.Errorf("Ожидался статус %d, получен %d", http.StatusOK, w.Code)
+		t.Fatalf("Ожидался код %d, получен %d: %s", http.StatusOK, w.Code, w.Body.String())
 	}
-
-	// Проверяем тип содержимого
-	contentType := w.Header().Get("Content-Type")
-	if contentType != "application/json" {
-		t.Errorf("Ожидался Content-Type 'application/json', получен '%s'", contentType)
+	if stored["github"]["uris"] != `[{"uri":"https://github.com","match":"host"}]` {
+		t.Errorf("Адреса должны храниться текстом JSON, получено %q", stored["github"]["uris"])
 	}
 
-	// Проверяем тело ответа
+	req = httptest.NewRequest("GET", "/api/data/credential/github", nil)
+	req.Header.Set("Authorization", "Bearer valid-token")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
 	var response struct {
-		TextData *domain.TextData `json:"text_data"`
-		Metadata string           `json:"metadata"`
+		CredentialData interface{} `json:"credential_data"`
 	}
-	err := json.Unmarshal(w.Body.Bytes(), &response)
-	if err != nil {
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
 		t.Fatalf("Ошибка при разборе JSON ответа: %v", err)
 	}
-
-	if response.TextData.Content != "test text content" {
-		t.Errorf("Ожидался текст 'test text content', получен '%s'", response.TextData.Content)
-	}
-}
-
-// TestDataUseCase_GetText_NotFound проверяет обработку ошибки "текстовые данные не найдены"
-func TestDataUseCase_GetText_NotFound(t *testing.T) {
-	// Создаем мок для DataService
-	mockDataService := &MockDataService{
-		GetTextFunc: func(login string, label string) (*domain.TextData, string, error) {
-			return nil, "", errors.New("текстовые данные не найдены")
-		},
-	}
-
-	// Создаем мок для JwtService
-	mockJwtService := &MockJwtService{
-		ExtractLoginFromTokenFunc: func(tokenString string) (string, error) {
-			return "testuser", nil
-		},
-	}
-
-	// Создаем экземпляр DataUseCase
-	dataUseCase := &DataUseCase{
-		dataService: mockDataService,
-		jwtService:  mockJwtService,
-	}
-
-	// Создаем тестовый HTTP запрос и ответ
-	req := httptest.NewRequest("GET", "/api/data/text/test-text", nil)
-	req.Header.Set("Authorization", "Bearer valid-token")
-	w := httptest.NewRecorder()
-
-	// Вызываем метод GetText
-	dataUseCase.GetText(w, req, "test-text")
-
-	// Проверяем статус ответа
-	if w.Code != http.StatusNotFound {
-		t.Errorf("Ожидался статус %d, получен %d", http.StatusNotFound, w.Code)
-	}
-
-	// Проверяем сообщение об ошибке
-	if !strings.Contains(w.Body.String(), "текстовые данные не найдены") {
-		t.Errorf("Ожидалось сообщение об ошибке с текстом 'текстовые данные не найдены', получено '%s'", w.Body.String())
-	}
-}
-
-// TestDataUseCase_SaveText_Success проверяет успешное сохранение текстовых данных
-func TestDataUseCase_SaveText_Success(t *testing.T) {
-	// Создаем мок для DataService
-	mockDataService := &MockDataService{
-		SaveTextFunc: func(login string, label string, textData *domain.TextData, metadata string) error {
-			if login != "testuser" {
-				t.Errorf("Ожидался логин 'testuser', получен '%s'", login)
-			}
-			if label != "test-text" {
-				t.Errorf("Ожидалась метка 'test-text', получена '%s'", label)
-			}
-			if textData.Content != "test text content" {
-				t.Errorf("Ожидался текст 'test text content', получен '%s'", textData.Content)
-			}
-			return nil
-		},
-	}
-
-	// Создаем мок для JwtService
-	mockJwtService := &MockJwtService{
-		ExtractLoginFromTokenFunc: func(tokenString string) (string, error) {
-			return "testuser", nil
-		},
-	}
-
-	// Создаем экземпляр DataUseCase
-	dataUseCase := &DataUseCase{
-		dataService: mockDataService,
-		jwtService:  mockJwtService,
-	}
-
-	// Создаем тестовый HTTP запрос и ответ
-	req := httptest.NewRequest("POST", "/api/data/text/test-text", nil)
-	req.Header.Set("Authorization", "Bearer valid-token")
-	w := httptest.NewRecorder()
-
-	// Создаем данные для сохранения
-	textData := &domain.TextData{
-		Content: "test text content",
-	}
-
-	// Вызываем метод SaveText
-	dataUseCase.SaveText(w, req, "test-text", textData, "")
-
-	// Проверяем статус ответа
-	if w.Code != http.StatusOK {
-		t.Errorf("Ожидался статус %d, получен %d", http.StatusOK, w.Code)
-	}
-
-	// Проверяем тип содержимого
-	contentType := w.Header().Get("Content-Type")
-	if contentType != "application/json" {
-		t.Errorf("Ожидался Content-Type 'application/json', получен '%s'", contentType)
-	}
-
-	// Проверяем тело ответа
-	var response map[string]string
-	err := json.Unmarshal(w.Body.Bytes(), &response)
-	if err != nil {
-		t.Fatalf("Ошибка при разборе JSON ответа: %v", err)
-	}
-
-	if message, ok := response["message"]; !ok || message != "текстовые данные успешно сохранены" {
-		t.Errorf("Ожидалось сообщение 'текстовые данные успешно сохранены', получено '%v'", response)
-	}
-}
-
-// TestDataUseCase_SaveText_TokenError проверяет обработку ошибки при извлечении логина из токена
-func TestDataUseCase_SaveText_TokenError(t *testing.T) {
-	// Создаем мок для DataService
-	mockDataService := &MockDataService{}
-
-	// Создаем мок для JwtService
-	mockJwtService := &MockJwtService{
-		ExtractLoginFromTokenFunc: func(tokenString string) (string, error) {
-			return "", errors.New("ошибка извлечения логина из токена")
-		},
-	}
-
-	// Создаем экземпляр DataUseCase
-	dataUseCase := &DataUseCase{
-		dataService: mockDataService,
-		jwtService:  mockJwtService,
-	}
-
-	// Создаем тестовый HTTP запрос и ответ
-	req := httptest.NewRequest("POST", "/api/data/text/test-text", nil)
-	req.Header.Set("Authorization", "Bearer error-token")
-	w := httptest.NewRecorder()
-
-	// Создаем данные для сохранения
-	textData := &domain.TextData{
-		Content: "test text content",
-	}
-
-	// Вызываем метод SaveText
-	dataUseCase.SaveText(w, req, "test-text", textData, "")
-
-	// Проверяем статус ответа
-	if w.Code != http.StatusInternalServerError {
-		t.Errorf("Ожидался статус %d, получен %d", http.StatusInternalServerError, w.Code)
-	}
-
-	// Проверяем сообщение об ошибке
-	if !strings.Contains(w.Body.String(), "Ошибка получения логина") {
-		t.Errorf("Ожидалось сообщение об ошибке с текстом 'Ошибка получения логина', получено '%s'", w.Body.String())
-	}
-}
-
-// TestDataUseCase_SaveText_Error проверяет обработку ошибки при сохранении текстовых данных
-func TestDataUseCase_SaveText_Error(t *testing.T) {
-	// Создаем мок для DataService
-	mockDataService := &MockDataService{
-		SaveTextFunc: func(login string, label string, textData *domain.TextData, metadata string) error {
-			return errors.New("ошибка при сохранении текстовых данных")
-		},
-	}
-
-	// Создаем мок для JwtService
-	mockJwtService := &MockJwtService{
-		ExtractLoginFromTokenFunc: func(tokenString string) (string, error) {
-			return "testuser", nil
-		},
-	}
-
-	// Создаем экземпляр DataUseCase
-	dataUseCase := &DataUseCase{
-		dataService: mockDataService,
-		jwtService:  mockJwtService,
-	}
-
-	// Создаем тестовый HTTP запрос и ответ
-	req := httptest.NewRequest("POST", "/api/data/text/test-text", nil)
-	req.Header.Set("Authorization", "Bearer valid-token")
-	w := httptest.NewRecorder()
-
-	// Создаем данные для сохранения
-	textData := &domain.TextData{
-		Content: "test text content",
-	}
-
-	// Вызываем метод SaveText
-	dataUseCase.SaveText(w, req, "test-text", textData, "")
-
-	// Проверяем статус ответа
-	if w.Code != http.StatusInternalServerError {
-		t.Errorf("Ожидался статус %d, получен %d", http.StatusInternalServerError, w.Code)
-	}
-
-	// Проверяем сообщение об ошибке
-	if !strings.Contains(w.Body.String(), "ошибка при сохранении текстовых данных") {
-		t.Errorf("Ожидалось сообщение об ошибке с текстом 'ошибка при сохранении текстовых данных', получено '%s'", w.Body.String())
-	}
-}
-
-// TestDataUseCase_DeleteCredential_Success проверяет успешное удаление учетных данных
-func TestDataUseCase_DeleteCredential_Success(t *testing.T) {
-	// Создаем мок для DataService
-	mockDataService := &MockDataService{
-		DeleteCredentialFunc: func(login string, label string) error {
-			if login != "testuser" {
-				t.Errorf("Ожидался логин 'testuser', получен '%s'", login)
-			}
-			if label != "test-credential" {
-				t.Errorf("Ожидалась метка 'test-credential', получена '%s'", label)
-			}
-			return nil
-		},
-	}
-
-	// Создаем мок для JwtService
-	mockJwtService := &MockJwtService{
-		ExtractLoginFromTokenFunc: func(tokenString string) (string, error) {
-			return "testuser", nil
-		},
-	}
-
-	// Создаем экземпляр DataUseCase
-	dataUseCase := &DataUseCase{
-		dataService: mockDataService,
-		jwtService:  mockJwtService,
-	}
-
-	// Создаем тестовый HTTP запрос и ответ
-	req := httptest.NewRequest("DELETE", "/api/data/credential/test-credential", nil)
-	req.Header.Set("Authorization", "Bearer valid-token")
-	w := httptest.NewRecorder()
-
-	// Вызываем метод DeleteCredential
-	dataUseCase.DeleteCredential(w, req, "test-credential")
-
-	// Проверяем статус ответа
-	if w.Code != http.StatusOK {
-		t.Errorf("Ожидался статус %d, получен %d", http.StatusOK, w.Code)
-	}
-
-	// Проверяем тип содержимого
-	contentType := w.Header().Get("Content-Type")
-	if contentType != "application/json" {
-		t.Errorf("Ожидался Content-Type 'application/json', получен '%s'", contentType)
-	}
-
-	// Проверяем тело ответа
-	var response map[string]string
-	err := json.Unmarshal(w.Body.Bytes(), &response)
-	if err != nil {
-		t.Fatalf("Ошибка при разборе JSON ответа: %v", err)
-	}
-
-	if message, ok := response["message"]; !ok || message != "учетные данные успешно удалены" {
-		t.Errorf("Ожидалось сообщение 'учетные данные успешно удалены', получено '%v'", response)
-	}
-}
-
-// TestDataUseCase_DeleteCredential_NotFound проверяет обработку ошибки "учетные данные не найдены"
-func TestDataUseCase_DeleteCredential_NotFound(t *testing.T) {
-	// Создаем мок для DataService
-	mockDataService := &MockDataService{
-		DeleteCredentialFunc: func(login string, label string) error {
-			return errors.New("учетные данные не найдены")
-		},
-	}
-
-	// Создаем мок для JwtService
-	mockJwtService := &MockJwtService{
-		ExtractLoginFromTokenFunc: func(tokenString string) (string, error) {
-			return "testuser", nil
-		},
-	}
-
-	// Создаем экземпляр DataUseCase
-	dataUseCase := &DataUseCase{
-		dataService: mockDataService,
-		jwtService:  mockJwtService,
-	}
-
-	// Создаем тестовый HTTP запрос и ответ
-	req := httptest.NewRequest("DELETE", "/api/data/credential/test-credential", nil)
-	req.Header.Set("Authorization", "Bearer valid-token")
-	w := httptest.NewRecorder()
-
-	// Вызываем метод DeleteCredential
-	dataUseCase.DeleteCredential(w, req, "test-credential")
-
-	// Проверяем статус ответа
-	if w.Code != http.StatusNotFound {
-		t.Errorf("Ожидался статус %d, получен %d", http.StatusNotFound, w.Code)
-	}
-
-	// Проверяем сообщение об ошибке
-	if !strings.Contains(w.Body.String(), "учетные данные не найдены") {
-		t.Errorf("Ожидалось сообщение об ошибке с текстом 'учетные данные не найдены', получено '%s'", w.Body.String())
-	}
-}
-
-// TestDataUseCase_DeleteCard_Success проверяет успешное удаление данных карты
-func TestDataUseCase_DeleteCard_Success(t *testing.T) {
-	// Создаем мок для DataService
-	mockDataService := &MockDataService{
-		DeleteCardFunc: func(login string, label string) error {
-			if login != "testuser" {
-				t.Errorf("Ожидался логин 'testuser', получен '%s'", login)
-			}
-			if label != "test-card" {
-				t.Errorf("Ожидалась метка 'test-card', получена '%s'", label)
-			}
-			return nil
-		},
-	}
-
-	// Создаем мок для JwtService
-	mockJwtService := &MockJwtService{
-		ExtractLoginFromTokenFunc: func(tokenString string) (string, error) {
-			return "testuser", nil
-		},
-	}
-
-	// Создаем экземпляр DataUseCase
-	dataUseCase := &DataUseCase{
-		dataService: mockDataService,
-		jwtService:  mockJwtService,
-	}
-
-	// Создаем тестовый HTTP запрос и ответ
-	req := httptest.NewRequest("DELETE", "/api/data/card/test-card", nil)
-	req.Header.Set("Authorization", "Bearer valid-token")
-	w := httptest.NewRecorder()
-
-	// Вызываем метод DeleteCard
-	dataUseCase.DeleteCard(w, req, "test-card")
-
-	// Проверяем статус ответа
-	if w.Code != http.StatusOK {
-		t.Errorf("Ожидался статус %d, получен %d", http.StatusOK, w.Code)
-	}
-
-	// Проверяем тип содержимого
-	contentType := w.Header().Get("Content-Type")
-	if contentType != "application/json" {
-		t.Errorf("Ожидался Content-Type 'application/json', получен '%s'", contentType)
-	}
-
-	// Проверяем тело ответа
-	var response map[string]string
-	err := json.Unmarshal(w.Body.Bytes(), &response)
-	if err != nil {
-		t.Fatalf("Ошибка при разборе JSON ответа: %v", err)
-	}
-
-	if message, ok := response["message"]; !ok || message != "данные карты успешно удалены" {
-		t.Errorf("Ожидалось сообщение 'данные карты успешно удалены', получено '%v'", response)
-	}
-}
-
-// TestDataUseCase_DeleteCard_TokenError проверяет обработку ошибки при извлечении логина из токена
-func TestDataUseCase_DeleteCard_TokenError(t *testing.T) {
-	// Создаем мок для DataService
-	mockDataService := &MockDataService{}
-
-	// Создаем мок для JwtService
-	mockJwtService := &MockJwtService{
-		ExtractLoginFromTokenFunc: func(tokenString string) (string, error) {
-			return "", errors.New("ошибка извлечения логина из токена")
-		},
-	}
-
-	// Создаем экземпляр DataUseCase
-	dataUseCase := &DataUseCase{
-		dataService: mockDataService,
-		jwtService:  mockJwtService,
-	}
-
-	// Создаем тестовый HTTP запрос и ответ
-	req := httptest.NewRequest("DELETE", "/api/data/card/test-card", nil)
-	req.Header.Set("Authorization", "Bearer error-token")
-	w := httptest.NewRecorder()
-
-	// Вызываем метод DeleteCard
-	dataUseCase.DeleteCard(w, req, "test-card")
-
-	// Проверяем статус ответа
-	if w.Code != http.StatusInternalServerError {
-		t.Errorf("Ожидался статус %d, получен %d", http.StatusInternalServerError, w.Code)
-	}
-
-	// Проверяем сообщение об ошибке
-	if !strings.Contains(w.Body.String(), "Ошибка получения логина") {
-		t.Errorf("Ожидалось сообщение об ошибке с текстом 'Ошибка получения логина', получено '%s'", w.Body.String())
-	}
-}
-
-// TestDataUseCase_DeleteCard_NotFound проверяет обработку ошибки "данные карты не найдены"
-func TestDataUseCase_DeleteCard_NotFound(t *testing.T) {
-	// Создаем мок для DataService
-	mockDataService := &MockDataService{
-		DeleteCardFunc: func(login string, label string) error {
-			return errors.New("данные карты не найдены")
-		},
-	}
-
-	// Создаем мок для JwtService
-	mockJwtService := &MockJwtService{
-		ExtractLoginFromTokenFunc: func(tokenString string) (string, error) {
-			return "testuser", nil
-		},
-	}
-
-	// Создаем экземпляр DataUseCase
-	dataUseCase := &DataUseCase{
-		dataService: mockDataService,
-		jwtService:  mockJwtService,
-	}
-
-	// Создаем тестовый HTTP запрос и ответ
-	req := httptest.NewRequest("DELETE", "/api/data/card/test-card", nil)
-	req.Header.Set("Authorization", "Bearer valid-token")
-	w := httptest.NewRecorder()
-
-	// Вызываем метод DeleteCard
-	dataUseCase.DeleteCard(w, req, "test-card")
-
-	// Проверяем статус ответа
-	if w.Code != http.StatusNotFound {
-		t.Errorf("Ожидался статус %d, получен %d", http.StatusNotFound, w.Code)
-	}
-
-	// Проверяем сообщение об ошибке
-	if !strings.Contains(w.Body.String(), "данные карты не найдены") {
-		t.Errorf("Ожидалось сообщение об ошибке с текстом 'данные карты не найдены', получено '%s'", w.Body.String())
-	}
-}
-
-// TestDataUseCase_DeleteCard_OtherError проверяет обработку других ошибок
-func TestDataUseCase_DeleteCard_OtherError(t *testing.T) {
-	// Создаем мок для DataService
-	mockDataService := &MockDataService{
-		DeleteCardFunc: func(login string, label string) error {
-			return errors.New("внутренняя ошибка сервера")
-		},
-	}
-
-	// Создаем мок для JwtService
-	mockJwtService := &MockJwtService{
-		ExtractLoginFromTokenFunc: func(tokenString string) (string, error) {
-			return "testuser", nil
-		},
-	}
-
-	// Создаем экземпляр DataUseCase
-	dataUseCase := &DataUseCase{
-		dataService: mockDataService,
-		jwtService:  mockJwtService,
-	}
-
-	// Создаем тестовый HTTP запрос и ответ
-	req := httptest.NewRequest("DELETE", "/api/data/card/test-card", nil)
-	req.Header.Set("Authorization", "Bearer valid-token")
-	w := httptest.NewRecorder()
-
-	// Вызываем метод DeleteCard
-	dataUseCase.DeleteCard(w, req, "test-card")
-
-	// Проверяем статус ответа
-	if w.Code != http.StatusInternalServerError {
-		t.Errorf("Ожидался статус %d, получен %d", http.StatusInternalServerError, w.Code)
-	}
-
-	// Проверяем сообщение об ошибке
-	if !strings.Contains(w.Body.String(), "внутренняя ошибка сервера") {
-		t.Errorf("Ожидалось сообщение об ошибке с текстом 'внутренняя ошибка сервера', получено '%s'", w.Body.String())
-	}
-}
-
-// TestDataUseCase_DeleteText_Success проверяет успешное удаление текстовых данных
-func TestDataUseCase_DeleteText_Success(t *testing.T) {
-	// Создаем мок для DataService
-	mockDataService := &MockDataService{
-		DeleteTextFunc: func(login string, label string) error {
-			if login != "testuser" {
-				t.Errorf("Ожидался логин 'testuser', получен '%s'", login)
-			}
-			if label != "test-text" {
-				t.Errorf("Ожидалась метка 'test-text', получена '%s'", label)
-			}
-			return nil
-		},
-	}
-
-	// Создаем мок для JwtService
-	mockJwtService := &MockJwtService{
-		ExtractLoginFromTokenFunc: func(tokenString string) (string, error) {
-			return "testuser", nil
-		},
-	}
-
-	// Создаем экземпляр DataUseCase
-	dataUseCase := &DataUseCase{
-		dataService: mockDataService,
-		jwtService:  mockJwtService,
-	}
-
-	// Создаем тестовый HTTP запрос и ответ
-	req := httptest.NewRequest("DELETE", "/api/data/text/test-text", nil)
-	req.Header.Set("Authorization", "Bearer valid-token")
-	w := httptest.NewRecorder()
-
-	// Вызываем метод DeleteText
-	dataUseCase.DeleteText(w, req, "test-text")
-
-	// Проверяем статус ответа
-	if w.Code != http.StatusOK {
-		t.Errorf("Ожидался статус %d, получен %d", http.StatusOK, w.Code)
-	}
-
-	// Проверяем тип содержимого
-	contentType := w.Header().Get("Content-Type")
-	if contentType != "application/json" {
-		t.Errorf("Ожидался Content-Type 'application/json', получен '%s'", contentType)
-	}
-
-	// Проверяем тело ответа
-	var response map[string]string
-	err := json.Unmarshal(w.Body.Bytes(), &response)
-	if err != nil {
-		t.Fatalf("Ошибка при разборе JSON ответа: %v", err)
-	}
-
-	if message, ok := response["message"]; !ok || message != "текстовые данные успешно удалены" {
-		t.Errorf("Ожидалось сообщение 'текстовые данные успешно удалены', получено '%v'", response)
-	}
-}
-
-// TestDataUseCase_DeleteText_TokenError проверяет обработку ошибки при извлечении логина из токена
-func TestDataUseCase_DeleteText_TokenError(t *testing.T) {
-	// Создаем мок для DataService
-	mockDataService := &MockDataService{}
-
-	// Создаем мок для JwtService
-	mockJwtService := &MockJwtService{
-		ExtractLoginFromTokenFunc: func(tokenString string) (string, error) {
-			return "", errors.New("ошибка извлечения логина из токена")
-		},
-	}
-
-	// Создаем экземпляр DataUseCase
-	dataUseCase := &DataUseCase{
-		dataService: mockDataService,
-		jwtService:  mockJwtService,
-	}
-
-	// Создаем тестовый HTTP запрос и ответ
-	req := httptest.NewRequest("DELETE", "/api/data/text/test-text", nil)
-	req.Header.Set("Authorization", "Bearer error-token")
-	w := httptest.NewRecorder()
-
-	// Вызываем метод DeleteText
-	dataUseCase.DeleteText(w, req, "test-text")
-
-	// Проверяем статус ответа
-	if w.Code != http.StatusInternalServerError {
-		t.Errorf("Ожидался статус %d, получен %d", http.StatusInternalServerError, w.Code)
-	}
-
-	// Проверяем сообщение об ошибке
-	if !strings.Contains(w.Body.String(), "Ошибка получения логина") {
-		t.Errorf("Ожидалось сообщение об ошибке с текстом 'Ошибка получения логина', получено '%s'", w.Body.String())
-	}
-}
-
-// TestDataUseCase_DeleteText_NotFound проверяет обработку ошибки "текстовые данные не найдены"
-func TestDataUseCase_DeleteText_NotFound(t *testing.T) {
-	// Создаем мок для DataService
-	mockDataService := &MockDataService{
-		DeleteTextFunc: func(login string, label string) error {
-			return errors.New("текстовые данные не найдены")
-		},
-	}
-
-	// Создаем мок для JwtService
-	mockJwtService := &MockJwtService{
-		ExtractLoginFromTokenFunc: func(tokenString string) (string, error) {
-			return "testuser", nil
-		},
-	}
-
-	// Создаем экземпляр DataUseCase
-	dataUseCase := &DataUseCase{
-		dataService: mockDataService,
-		jwtService:  mockJwtService,
-	}
-
-	// Создаем тестовый HTTP запрос и ответ
-	req := httptest.NewRequest("DELETE", "/api/data/text/test-text", nil)
-	req.Header.Set("Authorization", "Bearer valid-token")
-	w := httptest.NewRecorder()
-
-	// Вызываем метод DeleteText
-	dataUseCase.DeleteText(w, req, "test-text")
-
-	// Проверяем статус ответа
-	if w.Code != http.StatusNotFound {
-		t.Errorf("Ожидался статус %d, получен %d", http.StatusNotFound, w.Code)
-	}
-
-	// Проверяем сообщение об ошибке
-	if !strings.Contains(w.Body.String(), "текстовые данные не найдены") {
-		t.Errorf("Ожидалось сообщение об ошибке с текстом 'текстовые данные не найдены', получено '%s'", w.Body.String())
-	}
-}
-
-// TestDataUseCase_DeleteText_OtherError проверяет обработку других ошибок
-func TestDataUseCase_DeleteText_OtherError(t *testing.T) {
-	// Создаем мок для DataService
-	mockDataService := &MockDataService{
-		DeleteTextFunc: func(login string, label string) error {
-			return errors.New("внутренняя ошибка сервера")
-		},
-	}
-
-	// Создаем мок для JwtService
-	mockJwtService := &MockJwtService{
-		ExtractLoginFromTokenFunc: func(tokenString string) (string, error) {
-			return "testuser", nil
-		},
-	}
-
-	// Создаем экземпляр DataUseCase
-	dataUseCase := &DataUseCase{
-		dataService: mockDataService,
-		jwtService:  mockJwtService,
-	}
-
-	// Создаем тестовый HTTP запрос и ответ
-	req := httptest.NewRequest("DELETE", "/api/data/text/test-text", nil)
-	req.Header.Set("Authorization", "Bearer valid-token")
-	w := httptest.NewRecorder()
-
-	// Вызываем метод DeleteText
-	dataUseCase.DeleteText(w, req, "test-text")
-
-	// Проверяем статус ответа
-	if w.Code != http.StatusInternalServerError {
-		t.Errorf("Ожидался статус %d, получен %d", http.StatusInternalServerError, w.Code)
-	}
-
-	// Проверяем сообщение об ошибке
-	if !strings.Contains(w.Body.String(), "внутренняя ошибка сервера") {
-		t.Errorf("Ожидалось сообщение об ошибке с текстом 'внутренняя ошибка сервера', получено '%s'", w.Body.String())
+	var want interface{}
+	json.Unmarshal([]byte(credential), &want)
+	if !reflect.DeepEqual(response.CredentialData, want) {
+		t.Errorf("Ожидалось %v, получено %v", want, response.CredentialData)
 	}
 }
@@ -3,7 +3,9 @@ package usecase
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"github.com/SmirnovND/gophkeeper/internal/domain"
+	"github.com/SmirnovND/gophkeeper/internal/itemtype"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"net/http"
@@ -26,84 +28,119 @@ type MockDataServiceForDataUseCase struct {
 	mock.Mock
 }
 
-func (m *MockDataServiceForDataUseCase) SaveCard(login string, label string, cardData *domain.CardData, metadata string) error {
-	args := m.Called(login, label, cardData, metadata)
+func (m *MockDataServiceForDataUseCase) SaveItem(login string, itemType string, label string, data domain.ItemData, metadata string) error {
+	args := m.Called(login, itemType, label, data, metadata)
 	return args.Error(0)
 }
 
-func (m *MockDataServiceForDataUseCase) GetCard(login string, label string) (*domain.CardData, string, error) {
-	args := m.Called(login, label)
-	var cardData *domain.CardData
+func (m *MockDataServiceForDataUseCase) GetItem(login string, itemType string, label string) (domain.ItemData, string, error) {
+	args := m.Called(login, itemType, label)
+	var data domain.ItemData
 	if args.Get(0) != nil {
-		cardData = args.Get(0).(*domain.CardData)
+		data = args.Get(0).(domain.ItemData)
 	}
-	return cardData, args.String(1), args.Error(2)
+	return data, args.String(1), args.Error(2)
 }
 
-func (m *MockDataServiceForDataUseCase) DeleteCard(login string, label string) error {
-	args := m.Called(login, label)
+func (m *MockDataServiceForDataUseCase) DeleteItem(login string, itemType string, label string) error {
+	args := m.Called(login, itemType, label)
 	return args.Error(0)
 }
 
-func (m *MockDataServiceForDataUseCase) SaveText(login string, label string, textData *domain.TextData, metadata string) error {
-	args := m.Called(login, label, textData, metadata)
+func (m *MockDataServiceForDataUseCase) SaveFileMetadata(login string, label string, fileData *domain.FileData, metadata string) error {
+	args := m.Called(login, label, fileData, metadata)
 	return args.Error(0)
 }
 
-func (m *MockDataServiceForDataUseCase) GetText(login string, label string) (*domain.TextData, string, error) {
+func (m *MockDataServiceForDataUseCase) GetFileMetadata(login string, label string) (*domain.FileMetadata, string, error) {
 	args := m.Called(login, label)
-	var textData *domain.TextData
+	var fileMetadata *domain.FileMetadata
 	if args.Get(0) != nil {
-		textData = args.Get(0).(*domain.TextData)
+		fileMetadata = args.Get(0).(*domain.FileMetadata)
 	}
-	return textData, args.String(1), args.Error(2)
+	return fileMetadata, args.String(1), args.Error(2)
 }
 
-func (m *MockDataServiceForDataUseCase) DeleteText(login string, label string) error {
+func (m *MockDataServiceForDataUseCase) DeleteFileMetadata(login string, label string) error {
 	args := m.Called(login, label)
 	return args.Error(0)
 }
 
-func (m *MockDataServiceForDataUseCase) SaveCredential(login string, label string, credentialData *domain.CredentialData, metadata string) error {
-	args := m.Called(login, label, credentialData, metadata)
-	return args.Error(0)
+func (m *MockDataServiceForDataUseCase) ListFiles(login string) ([]domain.FileInfo, error) {
+	args := m.Called(login)
+	var files []domain.FileInfo
+	if args.Get(0) != nil {
+		files = args.Get(0).([]domain.FileInfo)
+	}
+	return files, args.Error(1)
 }
 
-func (m *MockDataServiceForDataUseCase) GetCredential(login string, label string) (*domain.CredentialData, string, error) {
-	args := m.Called(login, label)
-	var credentialData *domain.CredentialData
+func (m *MockDataServiceForDataUseCase) ListItems(login string) ([]domain.ItemInfo, error) {
+	args := m.Called(login)
+	var items []domain.ItemInfo
 	if args.Get(0) != nil {
-		credentialData = args.Get(0).(*domain.CredentialData)
+		items = args.Get(0).([]domain.ItemInfo)
 	}
-	return credentialData, args.String(1), args.Error(2)
+	return items, args.Error(1)
 }
 
-func (m *MockDataServiceForDataUseCase) DeleteCredential(login string, label string) error {
+func (m *MockDataServiceForDataUseCase) GetFileInfo(login string, label string) (*domain.FileInfo, error) {
 	args := m.Called(login, label)
-	return args.Error(0)
-}
-
-func (m *MockDataServiceForDataUseCase) SaveFileMetadata(login string, label string, fileData *domain.FileData, metadata string) error {
-	args := m.Called(login, label, fileData, metadata)
-	return args.Error(0)
+	var fileInfo *domain.FileInfo
+	if args.Get(0) != nil {
+		fileInfo = args.Get(0).(*domain.FileInfo)
+	}
+	return fileInfo, args.Error(1)
 }
 
-func (m *MockDataServiceForDataUseCase) GetFileMetadata(login string, label string) (*domain.FileMetadata, string, error) {
-	args := m.Called(login, label)
+func (m *MockDataServiceForDataUseCase) RenameFileMetadata(login string, label string, newLabel string) (*domain.FileMetadata, error) {
+	args := m.Called(login, label, newLabel)
 	var fileMetadata *domain.FileMetadata
 	if args.Get(0) != nil {
 		fileMetadata = args.Get(0).(*domain.FileMetadata)
 	}
-	return fileMetadata, args.String(1), args.Error(2)
+	return fileMetadata, args.Error(1)
 }
 
-func (m *MockDataServiceForDataUseCase) DeleteFileMetadata(login string, label string) error {
-	args := m.Called(login, label)
+// ItemType ищет тип только среди встроенных, поэтому не требует настройки мока
+func (m *MockDataServiceForDataUseCase) ItemType(login string, itemType string) (*itemtype.Type, error) {
+	return itemtype.Lookup(itemType)
+}
+
+func (m *MockDataServiceForDataUseCase) SaveItemTemplate(login string, name string, schema json.RawMessage) (*domain.ItemTemplate, error) {
+	args := m.Called(login, name, schema)
+	var template *domain.ItemTemplate
+	if args.Get(0) != nil {
+		template = args.Get(0).(*domain.ItemTemplate)
+	}
+	return template, args.Error(1)
+}
+
+func (m *MockDataServiceForDataUseCase) GetItemTemplate(login string, name string) (*domain.ItemTemplate, error) {
+	args := m.Called(login, name)
+	var template *domain.ItemTemplate
+	if args.Get(0) != nil {
+		template = args.Get(0).(*domain.ItemTemplate)
+	}
+	return template, args.Error(1)
+}
+
+func (m *MockDataServiceForDataUseCase) ListItemTemplates(login string) ([]domain.ItemTemplate, error) {
+	args := m.Called(login)
+	var templates []domain.ItemTemplate
+	if args.Get(0) != nil {
+		templates = args.Get(0).([]domain.ItemTemplate)
+	}
+	return templates, args.Error(1)
+}
+
+func (m *MockDataServiceForDataUseCase) DeleteItemTemplate(login string, name string) error {
+	args := m.Called(login, name)
 	return args.Error(0)
 }
 
-// TestDataUseCase_SaveCard тестирует метод SaveCard
-func TestDataUseCase_SaveCard(t *testing.T) {
+// TestDataUseCase_SaveItem_Card тестирует метод SaveItem для банковской карты
+func TestDataUseCase_SaveItem_Card(t *testing.T) {
 	// Тест успешного сохранения карты
 	t.Run("Success", func(t *testing.T) {
 		// Создаем моки
@@ -112,27 +149,28 @@ func TestDataUseCase_SaveCard(t *testing.T) {
 
 		// Настраиваем поведение моков
 		mockJWTService.On("ExtractLoginFromToken", "Bearer token123").Return("testuser", nil)
-		mockDataService.On("SaveCard", "testuser", "test-card", mock.AnythingOfType("*domain.CardData"), "test metadata").Return(nil)
+		mockDataService.On("SaveItem", "testuser", domain.UserDataTypeCard, "test-card", mock.AnythingOfType("domain.ItemData"), "test metadata").Return(nil)
 
 		// Создаем экземпляр DataUseCase
 		dataUseCase := &DataUseCase{
-			jwtService:  mockJWTService,
-			dataService: mockDataService,
+			jwtService:   mockJWTService,
+			dataService:  mockDataService,
+			quotaService: &MockQuotaService{},
 		}
 
 		// Создаем тестовые данные
 		w := httptest.NewRecorder()
 		r := httptest.NewRequest("POST", "/api/data/card/test-card", nil)
 		r.Header.Set("Authorization", "Bearer token123")
-		cardData := &domain.CardData{
-			Number:     "1234567890123456",
-			Holder:     "Test User",
-			ExpiryDate: "12/25",
-			CVV:        "123",
+		cardData := domain.ItemData{
+			"number":      "1234567890123456",
+			"holder":      "Test User",
+			"expiry_date": "12/25",
+			"cvv":         "123",
 		}
 
-		// Вызываем метод SaveCard
-		dataUseCase.SaveCard(w, r, "test-card", cardData, "test metadata")
+		// Вызываем метод SaveItem
+		dataUseCase.SaveItem(w, r, domain.UserDataTypeCard, "test-card", cardData, "test metadata")
 
 		// Проверяем результаты
 		assert.Equal(t, http.StatusOK, w.Code)
@@ -151,28 +189,29 @@ func TestDataUseCase_SaveCard(t *testing.T) {
 
 		// Создаем экземпляр DataUseCase
 		dataUseCase := &DataUseCase{
-			jwtService:  mockJWTService,
-			dataService: mockDataService,
+			jwtService:   mockJWTService,
+			dataService:  mockDataService,
+			quotaService: &MockQuotaService{},
 		}
 
 		// Создаем тестовые данные
 		w := httptest.NewRecorder()
 		r := httptest.NewRequest("POST", "/api/data/card/test-card", nil)
 		r.Header.Set("Authorization", "Bearer token123")
-		cardData := &domain.CardData{
-			Number:     "1234567890123456",
-			Holder:     "Test User",
-			ExpiryDate: "12/25",
-			CVV:        "123",
+		cardData := domain.ItemData{
+			"number":      "1234567890123456",
+			"holder":      "Test User",
+			"expiry_date": "12/25",
+			"cvv":         "123",
 		}
 
-		// Вызываем метод SaveCard
-		dataUseCase.SaveCard(w, r, "test-card", cardData, "test metadata")
+		// Вызываем метод SaveItem
+		dataUseCase.SaveItem(w, r, domain.UserDataTypeCard, "test-card", cardData, "test metadata")
 
 		// Проверяем результаты
 		assert.Equal(t, http.StatusInternalServerError, w.Code)
 		mockJWTService.AssertExpectations(t)
-		mockDataService.AssertNotCalled(t, "SaveCard")
+		mockDataService.AssertNotCalled(t, "SaveItem")
 	})
 
 	// Тест ошибки при сохранении карты
@@ -183,27 +222,28 @@ func TestDataUseCase_SaveCard(t *testing.T) {
 
 		// Настраиваем поведение моков
 		mockJWTService.On("ExtractLoginFromToken", "Bearer token123").Return("testuser", nil)
-		mockDataService.On("SaveCard", "testuser", "test-card", mock.AnythingOfType("*domain.CardData"), "test metadata").Return(errors.New("ошибка сохранения карты"))
+		mockDataService.On("SaveItem", "testuser", domain.UserDataTypeCard, "test-card", mock.AnythingOfType("domain.ItemData"), "test metadata").Return(errors.New("ошибка сохранения карты"))
 
 		// Создаем экземпляр DataUseCase
 		dataUseCase := &DataUseCase{
-			jwtService:  mockJWTService,
-			dataService: mockDataService,
+			jwtService:   mockJWTService,
+			dataService:  mockDataService,
+			quotaService: &MockQuotaService{},
 		}
 
 		// Создаем тестовые данные
 		w := httptest.NewRecorder()
 		r := httptest.NewRequest("POST", "/api/data/card/test-card", nil)
 		r.Header.Set("Authorization", "Bearer token123")
-		cardData := &domain.CardData{
-			Number:     "1234567890123456",
-			Holder:     "Test User",
-			ExpiryDate: "12/25",
-			CVV:        "123",
+		cardData := domain.ItemData{
+			"number":      "1234567890123456",
+			"holder":      "Test User",
+			"expiry_date": "12/25",
+			"cvv":         "123",
 		}
 
-		// Вызываем метод SaveCard
-		dataUseCase.SaveCard(w, r, "test-card", cardData, "test metadata")
+		// Вызываем метод SaveItem
+		dataUseCase.SaveItem(w, r, domain.UserDataTypeCard, "test-card", cardData, "test metadata")
 
 		// Проверяем результаты
 		assert.Equal(t, http.StatusInternalServerError, w.Code)
@@ -212,8 +252,8 @@ func TestDataUseCase_SaveCard(t *testing.T) {
 	})
 }
 
-// TestDataUseCase_GetCard тестирует метод GetCard
-func TestDataUseCase_GetCard(t *testing.T) {
+// TestDataUseCase_GetItem_Card тестирует метод GetItem для банковской карты в представлении API v1
+func TestDataUseCase_GetItem_Card(t *testing.T) {
 	// Тест успешного получения карты
 	t.Run("Success", func(t *testing.T) {
 		// Создаем моки
@@ -221,21 +261,22 @@ func TestDataUseCase_GetCard(t *testing.T) {
 		mockDataService := new(MockDataServiceForDataUseCase)
 
 		// Создаем тестовые данные
-		cardData := &domain.CardData{
-			Number:     "1234567890123456",
-			Holder:     "Test User",
-			ExpiryDate: "12/25",
-			CVV:        "123",
+		cardData := domain.ItemData{
+			"number":      "1234567890123456",
+			"holder":      "Test User",
+			"expiry_date": "12/25",
+			"cvv":         "123",
 		}
 
 		// Настраиваем поведение моков
 		mockJWTService.On("ExtractLoginFromToken", "Bearer token123").Return("testuser", nil)
-		mockDataService.On("GetCard", "testuser", "test-card").Return(cardData, "test metadata", nil)
+		mockDataService.On("GetItem", "testuser", domain.UserDataTypeCard, "test-card").Return(cardData, "test metadata", nil)
 
 		// Создаем экземпляр DataUseCase
 		dataUseCase := &DataUseCase{
-			jwtService:  mockJWTService,
-			dataService: mockDataService,
+			jwtService:   mockJWTService,
+			dataService:  mockDataService,
+			quotaService: &MockQuotaService{},
 		}
 
 		// Создаем тестовые данные
@@ -243,8 +284,8 @@ func TestDataUseCase_GetCard(t *testing.T) {
 		r := httptest.NewRequest("GET", "/api/data/card/test-card", nil)
 		r.Header.Set("Authorization", "Bearer token123")
 
-		// Вызываем метод GetCard
-		dataUseCase.GetCard(w, r, "test-card")
+		// Вызываем метод GetItem
+		dataUseCase.GetItem(w, r, domain.UserDataTypeCard, "test-card", domain.ItemView{DataKey: "card_data"})
 
 		// Проверяем результаты
 		assert.Equal(t, http.StatusOK, w.Code)
@@ -253,7 +294,7 @@ func TestDataUseCase_GetCard(t *testing.T) {
 
 		// Проверяем содержимое ответа
 		var response struct {
-			CardData *domain.CardData `json:"card_data"`
+			CardData domain.ItemData `json:"card_data"`
 			Metadata string           `json:"metadata"`
 		}
 		err := json.Unmarshal(w.Body.Bytes(), &response)
@@ -273,8 +314,9 @@ func TestDataUseCase_GetCard(t *testing.T) {
 
 		// Создаем экземпляр DataUseCase
 		dataUseCase := &DataUseCase{
-			jwtService:  mockJWTService,
-			dataService: mockDataService,
+			jwtService:   mockJWTService,
+			dataService:  mockDataService,
+			quotaService: &MockQuotaService{},
 		}
 
 		// Создаем тестовые данные
@@ -282,13 +324,13 @@ func TestDataUseCase_GetCard(t *testing.T) {
 		r := httptest.NewRequest("GET", "/api/data/card/test-card", nil)
 		r.Header.Set("Authorization", "Bearer token123")
 
-		// Вызываем метод GetCard
-		dataUseCase.GetCard(w, r, "test-card")
+		// Вызываем метод GetItem
+		dataUseCase.GetItem(w, r, domain.UserDataTypeCard, "test-card", domain.ItemView{DataKey: "card_data"})
 
 		// Проверяем результаты
 		assert.Equal(t, http.StatusInternalServerError, w.Code)
 		mockJWTService.AssertExpectations(t)
-		mockDataService.AssertNotCalled(t, "GetCard")
+		mockDataService.AssertNotCalled(t, "GetItem")
 	})
 
 	// Тест ошибки "данные карты не найдены"
@@ -299,12 +341,13 @@ func TestDataUseCase_GetCard(t *testing.T) {
 
 		// Настраиваем поведение моков
 		mockJWTService.On("ExtractLoginFromToken", "Bearer token123").Return("testuser", nil)
-		mockDataService.On("GetCard", "testuser", "test-card").Return(nil, "", errors.New("данные карты не найдены"))
+		mockDataService.On("GetItem", "testuser", domain.UserDataTypeCard, "test-card").Return(nil, "", fmt.Errorf("данные карты не найдены: %w", domain.ErrNotFound))
 
 		// Создаем экземпляр DataUseCase
 		dataUseCase := &DataUseCase{
-			jwtService:  mockJWTService,
-			dataService: mockDataService,
+			jwtService:   mockJWTService,
+			dataService:  mockDataService,
+			quotaService: &MockQuotaService{},
 		}
 
 		// Создаем тестовые данные
@@ -312,8 +355,8 @@ func TestDataUseCase_GetCard(t *testing.T) {
 		r := httptest.NewRequest("GET", "/api/data/card/test-card", nil)
 		r.Header.Set("Authorization", "Bearer token123")
 
-		// Вызываем метод GetCard
-		dataUseCase.GetCard(w, r, "test-card")
+		// Вызываем метод GetItem
+		dataUseCase.GetItem(w, r, domain.UserDataTypeCard, "test-card", domain.ItemView{DataKey: "card_data"})
 
 		// Проверяем результаты
 		assert.Equal(t, http.StatusNotFound, w.Code)
@@ -329,12 +372,13 @@ func TestDataUseCase_GetCard(t *testing.T) {
 
 		// Настраиваем поведение моков
 		mockJWTService.On("ExtractLoginFromToken", "Bearer token123").Return("testuser", nil)
-		mockDataService.On("GetCard", "testuser", "test-card").Return(nil, "", errors.New("другая ошибка"))
+		mockDataService.On("GetItem", "testuser", domain.UserDataTypeCard, "test-card").Return(nil, "", errors.New("другая ошибка"))
 
 		// Создаем экземпляр DataUseCase
 		dataUseCase := &DataUseCase{
-			jwtService:  mockJWTService,
-			dataService: mockDataService,
+			jwtService:   mockJWTService,
+			dataService:  mockDataService,
+			quotaService: &MockQuotaService{},
 		}
 
 		// Создаем тестовые данные
@@ -342,8 +386,8 @@ func TestDataUseCase_GetCard(t *testing.T) {
 		r := httptest.NewRequest("GET", "/api/data/card/test-card", nil)
 		r.Header.Set("Authorization", "Bearer token123")
 
-		// Вызываем метод GetCard
-		dataUseCase.GetCard(w, r, "test-card")
+		// Вызываем метод GetItem
+		dataUseCase.GetItem(w, r, domain.UserDataTypeCard, "test-card", domain.ItemView{DataKey: "card_data"})
 
 		// Проверяем результаты
 		assert.Equal(t, http.StatusInternalServerError, w.Code)
@@ -352,8 +396,8 @@ func TestDataUseCase_GetCard(t *testing.T) {
 	})
 }
 
-// TestDataUseCase_GetText тестирует метод GetText
-func TestDataUseCase_GetText(t *testing.T) {
+// TestDataUseCase_GetItem_Text тестирует метод GetItem для текста в представлении API v1
+func TestDataUseCase_GetItem_Text(t *testing.T) {
 	// Тест успешного получения текста
 	t.Run("Success", func(t *testing.T) {
 		// Создаем моки
@@ -361,18 +405,19 @@ func TestDataUseCase_GetText(t *testing.T) {
 		mockDataService := new(MockDataServiceForDataUseCase)
 
 		// Создаем тестовые данные
-		textData := &domain.TextData{
-			Content: "Test content",
+		textData := domain.ItemData{
+			"content": "Test content",
 		}
 
 		// Настраиваем поведение моков
 		mockJWTService.On("ExtractLoginFromToken", "Bearer token123").Return("testuser", nil)
-		mockDataService.On("GetText", "testuser", "test-text").Return(textData, "test metadata", nil)
+		mockDataService.On("GetItem", "testuser", domain.UserDataTypeText, "test-text").Return(textData, "test metadata", nil)
 
 		// Создаем экземпляр DataUseCase
 		dataUseCase := &DataUseCase{
-			jwtService:  mockJWTService,
-			dataService: mockDataService,
+			jwtService:   mockJWTService,
+			dataService:  mockDataService,
+			quotaService: &MockQuotaService{},
 		}
 
 		// Создаем тестовые данные
@@ -380,8 +425,8 @@ func TestDataUseCase_GetText(t *testing.T) {
 		r := httptest.NewRequest("GET", "/api/data/text/test-text", nil)
 		r.Header.Set("Authorization", "Bearer token123")
 
-		// Вызываем метод GetText
-		dataUseCase.GetText(w, r, "test-text")
+		// Вызываем метод GetItem
+		dataUseCase.GetItem(w, r, domain.UserDataTypeText, "test-text", domain.ItemView{DataKey: "text_data"})
 
 		// Проверяем результаты
 		assert.Equal(t, http.StatusOK, w.Code)
@@ -390,7 +435,7 @@ func TestDataUseCase_GetText(t *testing.T) {
 
 		// Проверяем содержимое ответа
 		var response struct {
-			TextData *domain.TextData `json:"text_data"`
+			TextData domain.ItemData `json:"text_data"`
 			Metadata string           `json:"metadata"`
 		}
 		err := json.Unmarshal(w.Body.Bytes(), &response)
@@ -410,8 +455,9 @@ func TestDataUseCase_GetText(t *testing.T) {
 
 		// Создаем экземпляр DataUseCase
 		dataUseCase := &DataUseCase{
-			jwtService:  mockJWTService,
-			dataService: mockDataService,
+			jwtService:   mockJWTService,
+			dataService:  mockDataService,
+			quotaService: &MockQuotaService{},
 		}
 
 		// Создаем тестовые данные
@@ -419,13 +465,13 @@ func TestDataUseCase_GetText(t *testing.T) {
 		r := httptest.NewRequest("GET", "/api/data/text/test-text", nil)
 		r.Header.Set("Authorization", "Bearer token123")
 
-		// Вызываем метод GetText
-		dataUseCase.GetText(w, r, "test-text")
+		// Вызываем метод GetItem
+		dataUseCase.GetItem(w, r, domain.UserDataTypeText, "test-text", domain.ItemView{DataKey: "text_data"})
 
 		// Проверяем результаты
 		assert.Equal(t, http.StatusInternalServerError, w.Code)
 		mockJWTService.AssertExpectations(t)
-		mockDataService.AssertNotCalled(t, "GetText")
+		mockDataService.AssertNotCalled(t, "GetItem")
 	})
 
 	// Тест ошибки "текстовые данные не найдены"
@@ -436,12 +482,13 @@ func TestDataUseCase_GetText(t *testing.T) {
 
 		// Настраиваем поведение моков
 		mockJWTService.On("ExtractLoginFromToken", "Bearer token123").Return("testuser", nil)
-		mockDataService.On("GetText", "testuser", "test-text").Return(nil, "", errors.New("текстовые данные не найдены"))
+		mockDataService.On("GetItem", "testuser", domain.UserDataTypeText, "test-text").Return(nil, "", fmt.Errorf("текстовые данные не найдены: %w", domain.ErrNotFound))
 
 		// Создаем экземпляр DataUseCase
 		dataUseCase := &DataUseCase{
-			jwtService:  mockJWTService,
-			dataService: mockDataService,
+			jwtService:   mockJWTService,
+			dataService:  mockDataService,
+			quotaService: &MockQuotaService{},
 		}
 
 		// Создаем тестовые данные
@@ -449,8 +496,8 @@ func TestDataUseCase_GetText(t *testing.T) {
 		r := httptest.NewRequest("GET", "/api/data/text/test-text", nil)
 		r.Header.Set("Authorization", "Bearer token123")
 
-		// Вызываем метод GetText
-		dataUseCase.GetText(w, r, "test-text")
+		// Вызываем метод GetItem
+		dataUseCase.GetItem(w, r, domain.UserDataTypeText, "test-text", domain.ItemView{DataKey: "text_data"})
 
 		// Проверяем результаты
 		assert.Equal(t, http.StatusNotFound, w.Code)
@@ -466,12 +513,13 @@ func TestDataUseCase_GetText(t *testing.T) {
 
 		// Настраиваем поведение моков
 		mockJWTService.On("ExtractLoginFromToken", "Bearer token123").Return("testuser", nil)
-		mockDataService.On("GetText", "testuser", "test-text").Return(nil, "", errors.New("другая ошибка"))
+		mockDataService.On("GetItem", "testuser", domain.UserDataTypeText, "test-text").Return(nil, "", errors.New("другая ошибка"))
 
 		// Создаем экземпляр DataUseCase
 		dataUseCase := &DataUseCase{
-			jwtService:  mockJWTService,
-			dataService: mockDataService,
+			jwtService:   mockJWTService,
+			dataService:  mockDataService,
+			quotaService: &MockQuotaService{},
 		}
 
 		// Создаем тестовые данные
@@ -479,8 +527,8 @@ func TestDataUseCase_GetText(t *testing.T) {
 		r := httptest.NewRequest("GET", "/api/data/text/test-text", nil)
 		r.Header.Set("Authorization", "Bearer token123")
 
-		// Вызываем метод GetText
-		dataUseCase.GetText(w, r, "test-text")
+		// Вызываем метод GetItem
+		dataUseCase.GetItem(w, r, domain.UserDataTypeText, "test-text", domain.ItemView{DataKey: "text_data"})
 
 		// Проверяем результаты
 		assert.Equal(t, http.StatusInternalServerError, w.Code)
@@ -490,7 +538,40 @@ func TestDataUseCase_GetText(t *testing.T) {
 }
 
 // TestDataUseCase_DeleteCredential тестирует метод DeleteCredential
-func TestDataUseCase_DeleteCredential(t *testing.T) {
+func TestDataUseCase_ListItems(t *testing.T) {
+	mockJWTService := new(MockJWTServiceForDataUseCase)
+	mockDataService := new(MockDataServiceForDataUseCase)
+
+	items := []domain.ItemInfo{
+		{Type: domain.UserDataTypeCredential, Label: "bank", Metadata: "рабочий"},
+		{Type: domain.UserDataTypeText, Label: "notes"},
+	}
+	mockJWTService.On("ExtractLoginFromToken", "Bearer token123").Return("testuser", nil)
+	mockDataService.On("ListItems", "testuser").Return(items, nil)
+
+	dataUseCase := &DataUseCase{
+		jwtService:   mockJWTService,
+		dataService:  mockDataService,
+		quotaService: &MockQuotaService{},
+	}
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/api/data", nil)
+	r.Header.Set("Authorization", "Bearer token123")
+
+	dataUseCase.ListItems(w, r)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockJWTService.AssertExpectations(t)
+	mockDataService.AssertExpectations(t)
+
+	var response []domain.ItemInfo
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, items, response)
+}
+
+func TestDataUseCase_DeleteItem_Credential(t *testing.T) {
 	// Тест успешного удаления учетных данных
 	t.Run("Success", func(t *testing.T) {
 		// Создаем моки
@@ -499,12 +580,13 @@ func TestDataUseCase_DeleteCredential(t *testing.T) {
 
 		// Настраиваем поведение моков
 		mockJWTService.On("ExtractLoginFromToken", "Bearer token123").Return("testuser", nil)
-		mockDataService.On("DeleteCredential", "testuser", "test-credential").Return(nil)
+		mockDataService.On("DeleteItem", "testuser", domain.UserDataTypeCredential, "test-credential").Return(nil)
 
 		// Создаем экземпляр DataUseCase
 		dataUseCase := &DataUseCase{
-			jwtService:  mockJWTService,
-			dataService: mockDataService,
+			jwtService:   mockJWTService,
+			dataService:  mockDataService,
+			quotaService: &MockQuotaService{},
 		}
 
 		// Создаем тестовые данные
@@ -512,8 +594,8 @@ func TestDataUseCase_DeleteCredential(t *testing.T) {
 		r := httptest.NewRequest("DELETE", "/api/data/credential/test-credential", nil)
 		r.Header.Set("Authorization", "Bearer token123")
 
-		// Вызываем метод DeleteCredential
-		dataUseCase.DeleteCredential(w, r, "test-credential")
+		// Вызываем метод DeleteItem
+		dataUseCase.DeleteItem(w, r, domain.UserDataTypeCredential, "test-credential")
 
 		// Проверяем результаты
 		assert.Equal(t, http.StatusOK, w.Code)
@@ -532,8 +614,9 @@ func TestDataUseCase_DeleteCredential(t *testing.T) {
 
 		// Создаем экземпляр DataUseCase
 		dataUseCase := &DataUseCase{
-			jwtService:  mockJWTService,
-			dataService: mockDataService,
+			jwtService:   mockJWTService,
+			dataService:  mockDataService,
+			quotaService: &MockQuotaService{},
 		}
 
 		// Создаем тестовые данные
@@ -541,13 +624,13 @@ func TestDataUseCase_DeleteCredential(t *testing.T) {
 		r := httptest.NewRequest("DELETE", "/api/data/credential/test-credential", nil)
 		r.Header.Set("Authorization", "Bearer token123")
 
-		// Вызываем метод DeleteCredential
-		dataUseCase.DeleteCredential(w, r, "test-credential")
+		// Вызываем метод DeleteItem
+		dataUseCase.DeleteItem(w, r, domain.UserDataTypeCredential, "test-credential")
 
 		// Проверяем результаты
 		assert.Equal(t, http.StatusInternalServerError, w.Code)
 		mockJWTService.AssertExpectations(t)
-		mockDataService.AssertNotCalled(t, "DeleteCredential")
+		mockDataService.AssertNotCalled(t, "DeleteItem")
 	})
 
 	// Тест ошибки "учетные данные не найдены"
@@ -558,12 +641,13 @@ func TestDataUseCase_DeleteCredential(t *testing.T) {
 
 		// Настраиваем поведение моков
 		mockJWTService.On("ExtractLoginFromToken", "Bearer token123").Return("testuser", nil)
-		mockDataService.On("DeleteCredential", "testuser", "test-credential").Return(errors.New("учетные данные не найдены"))
+		mockDataService.On("DeleteItem", "testuser", domain.UserDataTypeCredential, "test-credential").Return(fmt.Errorf("учетные данные не найдены: %w", domain.ErrNotFound))
 
 		// Создаем экземпляр DataUseCase
 		dataUseCase := &DataUseCase{
-			jwtService:  mockJWTService,
-			dataService: mockDataService,
+			jwtService:   mockJWTService,
+			dataService:  mockDataService,
+			quotaService: &MockQuotaService{},
 		}
 
 		// Создаем тестовые данные
@@ -571,8 +655,8 @@ func TestDataUseCase_DeleteCredential(t *testing.T) {
 		r := httptest.NewRequest("DELETE", "/api/data/credential/test-credential", nil)
 		r.Header.Set("Authorization", "Bearer token123")
 
-		// Вызываем метод DeleteCredential
-		dataUseCase.DeleteCredential(w, r, "test-credential")
+		// Вызываем метод DeleteItem
+		dataUseCase.DeleteItem(w, r, domain.UserDataTypeCredential, "test-credential")
 
 		// Проверяем результаты
 		assert.Equal(t, http.StatusNotFound, w.Code)
@@ -588,12 +672,13 @@ func TestDataUseCase_DeleteCredential(t *testing.T) {
 
 		// Настраиваем поведение моков
 		mockJWTService.On("ExtractLoginFromToken", "Bearer token123").Return("testuser", nil)
-		mockDataService.On("DeleteCredential", "testuser", "test-credential").Return(errors.New("другая ошибка"))
+		mockDataService.On("DeleteItem", "testuser", domain.UserDataTypeCredential, "test-credential").Return(errors.New("другая ошибка"))
 
 		// Создаем экземпляр DataUseCase
 		dataUseCase := &DataUseCase{
-			jwtService:  mockJWTService,
-			dataService: mockDataService,
+			jwtService:   mockJWTService,
+			dataService:  mockDataService,
+			quotaService: &MockQuotaService{},
 		}
 
 		// Создаем тестовые данные
@@ -601,8 +686,8 @@ func TestDataUseCase_DeleteCredential(t *testing.T) {
 		r := httptest.NewRequest("DELETE", "/api/data/credential/test-credential", nil)
 		r.Header.Set("Authorization", "Bearer token123")
 
-		// Вызываем метод DeleteCredential
-		dataUseCase.DeleteCredential(w, r, "test-credential")
+		// Вызываем метод DeleteItem
+		dataUseCase.DeleteItem(w, r, domain.UserDataTypeCredential, "test-credential")
 
 		// Проверяем результаты
 		assert.Equal(t, http.StatusInternalServerError, w.Code)
@@ -1,8 +1,10 @@
 package usecase
 
 import (
+	"encoding/json"
 	"errors"
 	"github.com/SmirnovND/gophkeeper/internal/domain"
+	"github.com/SmirnovND/gophkeeper/internal/itemtype"
 )
 
 // MockJwtService - мок для интерфейса JwtService
@@ -25,104 +27,196 @@ func (m *MockJwtService) ExtractLoginFromToken(tokenString string) (string, erro
 
 // MockDataService - мок для интерфейса DataService
 type MockDataService struct {
-	GetCredentialFunc    func(login string, label string) (*domain.CredentialData, string, error)
-	SaveCredentialFunc   func(login string, label string, credentialData *domain.CredentialData, metadata string) error
-	DeleteCredentialFunc func(login string, label string) error
-
-	GetCardFunc    func(login string, label string) (*domain.CardData, string, error)
-	SaveCardFunc   func(login string, label string, cardData *domain.CardData, metadata string) error
-	DeleteCardFunc func(login string, label string) error
-
-	GetTextFunc    func(login string, label string) (*domain.TextData, string, error)
-	SaveTextFunc   func(login string, label string, textData *domain.TextData, metadata string) error
-	DeleteTextFunc func(login string, label string) error
+	GetItemFunc    func(login string, itemType string, label string) (domain.ItemData, string, error)
+	SaveItemFunc   func(login string, itemType string, label string, data domain.ItemData, metadata string) error
+	DeleteItemFunc func(login string, itemType string, label string) error
 
 	GetFileMetadataFunc    func(login string, label string) (*domain.FileMetadata, string, error)
 	SaveFileMetadataFunc   func(login string, label string, fileData *domain.FileData, metadata string) error
 	DeleteFileMetadataFunc func(login string, label string) error
+	ListFilesFunc          func(login string) ([]domain.FileInfo, error)
+	GetFileInfoFunc        func(login string, label string) (*domain.FileInfo, error)
+	RenameFileMetadataFunc func(login string, label string, newLabel string) (*domain.FileMetadata, error)
+
+	ListItemsFunc func(login string) ([]domain.ItemInfo, error)
+
+	ItemTypeFunc           func(login string, itemType string) (*itemtype.Type, error)
+	SaveItemTemplateFunc   func(login string, name string, schema json.RawMessage) (*domain.ItemTemplate, error)
+	GetItemTemplateFunc    func(login string, name string) (*domain.ItemTemplate, error)
+	ListItemTemplatesFunc  func(login string) ([]domain.ItemTemplate, error)
+	DeleteItemTemplateFunc func(login string, name string) error
 }
 
-// Реализация методов интерфейса DataService для мока
-func (m *MockDataService) GetCredential(login string, label string) (*domain.CredentialData, string, error) {
-	if m.GetCredentialFunc != nil {
-		return m.GetCredentialFunc(login, label)
+func (m *MockDataService) GetItem(login string, itemType string, label string) (domain.ItemData, string, error) {
+	if m.GetItemFunc != nil {
+		return m.GetItemFunc(login, itemType, label)
 	}
 	return nil, "", nil
 }
 
-func (m *MockDataService) SaveCredential(login string, label string, credentialData *domain.CredentialData, metadata string) error {
-	if m.SaveCredentialFunc != nil {
-		return m.SaveCredentialFunc(login, label, credentialData, metadata)
+func (m *MockDataService) SaveItem(login string, itemType string, label string, data domain.ItemData, metadata string) error {
+	if m.SaveItemFunc != nil {
+		return m.SaveItemFunc(login, itemType, label, data, metadata)
 	}
 	return nil
 }
 
-func (m *MockDataService) DeleteCredential(login string, label string) error {
-	if m.DeleteCredentialFunc != nil {
-		return m.DeleteCredentialFunc(login, label)
+func (m *MockDataService) DeleteItem(login string, itemType string, label string) error {
+	if m.DeleteItemFunc != nil {
+		return m.DeleteItemFunc(login, itemType, label)
 	}
 	return nil
 }
 
-func (m *MockDataService) GetCard(login string, label string) (*domain.CardData, string, error) {
-	if m.GetCardFunc != nil {
-		return m.GetCardFunc(login, label)
+func (m *MockDataService) GetFileMetadata(login string, label string) (*domain.FileMetadata, string, error) {
+	if m.GetFileMetadataFunc != nil {
+		return m.GetFileMetadataFunc(login, label)
 	}
 	return nil, "", nil
 }
 
-func (m *MockDataService) SaveCard(login string, label string, cardData *domain.CardData, metadata string) error {
-	if m.SaveCardFunc != nil {
-		return m.SaveCardFunc(login, label, cardData, metadata)
+func (m *MockDataService) SaveFileMetadata(login string, label string, fileData *domain.FileData, metadata string) error {
+	if m.SaveFileMetadataFunc != nil {
+		return m.SaveFileMetadataFunc(login, label, fileData, metadata)
 	}
 	return nil
 }
 
-func (m *MockDataService) DeleteCard(login string, label string) error {
-	if m.DeleteCardFunc != nil {
-		return m.DeleteCardFunc(login, label)
+func (m *MockDataService) DeleteFileMetadata(login string, label string) error {
+	if m.DeleteFileMetadataFunc != nil {
+		return m.DeleteFileMetadataFunc(login, label)
 	}
 	return nil
 }
 
-func (m *MockDataService) GetText(login string, label string) (*domain.TextData, string, error) {
-	if m.GetTextFunc != nil {
-		return m.GetTextFunc(login, label)
+func (m *MockDataService) ListFiles(login string) ([]domain.FileInfo, error) {
+	if m.ListFilesFunc != nil {
+		return m.ListFilesFunc(login)
 	}
-	return nil, "", nil
+	return nil, nil
 }
 
-func (m *MockDataService) SaveText(login string, label string, textData *domain.TextData, metadata string) error {
-	if m.SaveTextFunc != nil {
-		return m.SaveTextFunc(login, label, textData, metadata)
+func (m *MockDataService) ListItems(login string) ([]domain.ItemInfo, error) {
+	if m.ListItemsFunc != nil {
+		return m.ListItemsFunc(login)
 	}
-	return nil
+	return nil, nil
+}
+
+// ItemType по умолчанию ищет тип только среди встроенных
+func (m *MockDataService) ItemType(login string, itemType string) (*itemtype.Type, error) {
+	if m.ItemTypeFunc != nil {
+		return m.ItemTypeFunc(login, itemType)
+	}
+	return itemtype.Lookup(itemType)
+}
+
+func (m *MockDataService) SaveItemTemplate(login string, name string, schema json.RawMessage) (*domain.ItemTemplate, error) {
+	if m.SaveItemTemplateFunc != nil {
+		return m.SaveItemTemplateFunc(login, name, schema)
+	}
+	return &domain.ItemTemplate{Name: name, Schema: schema}, nil
+}
+
+func (m *MockDataService) GetItemTemplate(login string, name string) (*domain.ItemTemplate, error) {
+	if m.GetItemTemplateFunc != nil {
+		return m.GetItemTemplateFunc(login, name)
+	}
+	return nil, domain.ErrNotFound
 }
 
-func (m *MockDataService) DeleteText(login string, label string) error {
-	if m.DeleteTextFunc != nil {
-		return m.DeleteTextFunc(login, label)
+func (m *MockDataService) ListItemTemplates(login string) ([]domain.ItemTemplate, error) {
+	if m.ListItemTemplatesFunc != nil {
+		return m.ListItemTemplatesFunc(login)
+	}
+	return nil, nil
+}
+
+func (m *MockDataService) DeleteItemTemplate(login string, name string) error {
+	if m.DeleteItemTemplateFunc != nil {
+		return m.DeleteItemTemplateFunc(login, name)
 	}
 	return nil
 }
 
-func (m *MockDataService) GetFileMetadata(login string, label string) (*domain.FileMetadata, string, error) {
-	if m.GetFileMetadataFunc != nil {
-		return m.GetFileMetadataFunc(login, label)
+func (m *MockDataService) GetFileInfo(login string, label string) (*domain.FileInfo, error) {
+	if m.GetFileInfoFunc != nil {
+		return m.GetFileInfoFunc(login, label)
 	}
-	return nil, "", nil
+	return nil, nil
 }
 
-func (m *MockDataService) SaveFileMetadata(login string, label string, fileData *domain.FileData, metadata string) error {
-	if m.SaveFileMetadataFunc != nil {
-		return m.SaveFileMetadataFunc(login, label, fileData, metadata)
+func (m *MockDataService) RenameFileMetadata(login string, label string, newLabel string) (*domain.FileMetadata, error) {
+	if m.RenameFileMetadataFunc != nil {
+		return m.RenameFileMetadataFunc(login, label, newLabel)
+	}
+	return nil, nil
+}
+
+// MockQuotaService - мок для интерфейса QuotaService
+type MockQuotaService struct {
+	GetUsageFunc        func(login string) (*domain.Usage, error)
+	CheckFileUploadFunc func(login string, label string, size int64) error
+	CheckItemSaveFunc   func(login string, label string) error
+	GetUserQuotaFunc    func(userID string) (*domain.Quota, error)
+}
+
+func (m *MockQuotaService) GetUsage(login string) (*domain.Usage, error) {
+	if m.GetUsageFunc != nil {
+		return m.GetUsageFunc(login)
+	}
+	return &domain.Usage{}, nil
+}
+
+func (m *MockQuotaService) CheckFileUpload(login string, label string, size int64) error {
+	if m.CheckFileUploadFunc != nil {
+		return m.CheckFileUploadFunc(login, label, size)
 	}
 	return nil
 }
 
-func (m *MockDataService) DeleteFileMetadata(login string, label string) error {
-	if m.DeleteFileMetadataFunc != nil {
-		return m.DeleteFileMetadataFunc(login, label)
+func (m *MockQuotaService) CheckItemSave(login string, label string) error {
+	if m.CheckItemSaveFunc != nil {
+		return m.CheckItemSaveFunc(login, label)
 	}
 	return nil
-}
\ No newline at end of file
+}
+
+func (m *MockQuotaService) GetUserQuota(userID string) (*domain.Quota, error) {
+	if m.GetUserQuotaFunc != nil {
+		return m.GetUserQuotaFunc(userID)
+	}
+	return &domain.Quota{}, nil
+}
+
+// MockKeyService - мок для KeyService
+type MockKeyService struct {
+	CreateUserKeyFunc   func(login string) error
+	GetUserKeyFunc      func(login string) ([]byte, error)
+	RotateMasterKeyFunc func() (int, error)
+}
+
+func (m *MockKeyService) Enabled() bool {
+	return m.GetUserKeyFunc != nil
+}
+
+func (m *MockKeyService) CreateUserKey(login string) error {
+	if m.CreateUserKeyFunc != nil {
+		return m.CreateUserKeyFunc(login)
+	}
+	return nil
+}
+
+func (m *MockKeyService) GetUserKey(login string) ([]byte, error) {
+	if m.GetUserKeyFunc != nil {
+		return m.GetUserKeyFunc(login)
+	}
+	return nil, nil
+}
+
+func (m *MockKeyService) RotateMasterKey() (int, error) {
+	if m.RotateMasterKeyFunc != nil {
+		return m.RotateMasterKeyFunc()
+	}
+	return 0, nil
+}
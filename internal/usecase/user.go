@@ -0,0 +1,94 @@
+package usecase
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/SmirnovND/gophkeeper/internal/domain"
+	"github.com/SmirnovND/gophkeeper/internal/interfaces"
+	"net/http"
+)
+
+type UserUseCase struct {
+	quotaService    interfaces.QuotaService
+	settingsService interfaces.SettingsService
+	jwtService      interfaces.JwtService
+}
+
+func NewUserUseCase(
+	quotaService interfaces.QuotaService,
+	settingsService interfaces.SettingsService,
+	jwtService interfaces.JwtService,
+) interfaces.UserUseCase {
+	return &UserUseCase{
+		quotaService:    quotaService,
+		settingsService: settingsService,
+		jwtService:      jwtService,
+	}
+}
+
+// GetUsage отправляет в ответе текущее потребление хранилища и квоту пользователя
+func (u *UserUseCase) GetUsage(w http.ResponseWriter, r *http.Request) {
+	login, err := u.jwtService.ExtractLoginFromToken(r.Header.Get("Authorization"))
+	if err != nil {
+		http.Error(w, "Ошибка получения логина: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	usage, err := u.quotaService.GetUsage(login)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(usage)
+}
+
+// GetSettings отправляет в ответе действующие настройки аккаунта пользователя
+func (u *UserUseCase) GetSettings(w http.ResponseWriter, r *http.Request) {
+	login, err := u.jwtService.ExtractLoginFromToken(r.Header.Get("Authorization"))
+	if err != nil {
+		http.Error(w, "Ошибка получения логина: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	settings, err := u.settingsService.GetSettings(login)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(settings)
+}
+
+// SaveSettings сохраняет настройки аккаунта из тела запроса. Недопустимые значения отклоняются с кодом 400
+func (u *UserUseCase) SaveSettings(w http.ResponseWriter, r *http.Request) {
+	login, err := u.jwtService.ExtractLoginFromToken(r.Header.Get("Authorization"))
+	if err != nil {
+		http.Error(w, "Ошибка получения логина: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	settings := &domain.UserSettings{}
+	if err := json.NewDecoder(r.Body).Decode(settings); err != nil {
+		http.Error(w, "Неверный формат настроек: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	err = u.settingsService.SaveSettings(login, settings)
+	if errors.Is(err, domain.ErrInvalidSettings) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(settings)
+}
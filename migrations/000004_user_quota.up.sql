@@ -0,0 +1,8 @@
+-- user_quota: индивидуальные квоты пользователей.
+-- NULL в поле означает, что используется значение по умолчанию из конфигурации сервера, 0 - без ограничений
+CREATE TABLE user_quota (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    max_total_bytes BIGINT,
+    max_items BIGINT,
+    max_file_size BIGINT
+);
@@ -0,0 +1,11 @@
+-- user_key: ключи данных пользователей для шифрования объектов в хранилище (SSE-C).
+-- Ключ хранится только в обернутом мастер-ключом сервера виде, master_key_id - отпечаток мастер-ключа
+CREATE TABLE user_key (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    wrapped_key BYTEA NOT NULL,
+    master_key_id VARCHAR(16) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_user_key_master_key_id ON user_key(master_key_id);
@@ -0,0 +1,10 @@
+-- item_template: пользовательские типы записей, описанные подмножеством JSON Schema.
+-- Имя шаблона служит типом записей в user_data
+CREATE TABLE item_template (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    schema JSONB NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_id, name)
+);
@@ -0,0 +1,6 @@
+-- user_settings: настройки аккаунта, которые пользователь меняет сам.
+-- NULL в поле означает, что используется значение по умолчанию из конфигурации сервера
+CREATE TABLE user_settings (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    password_history INTEGER
+);
@@ -0,0 +1,267 @@
+package pkg
+
+import (
+	"archive/tar"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// TarMimeType - тип содержимого архива директории
+const TarMimeType = "application/x-tar"
+
+// ErrUnsafeArchivePath возвращается при распаковке записи, которая выходит за пределы целевой директории
+var ErrUnsafeArchivePath = errors.New("небезопасный путь в архиве")
+
+// WriteTar упаковывает содержимое директории dir в tar-архив, записывая его потоком в w.
+// Пути в архиве относительны dir, права доступа и символические ссылки сохраняются.
+// Специальные файлы (устройства, сокеты, каналы) пропускаются
+func WriteTar(w io.Writer, dir string) error {
+	tw := tar.NewWriter(w)
+
+	err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		name, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		if name == "." {
+			return nil
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+
+		var link string
+		switch {
+		case info.Mode()&fs.ModeSymlink != 0:
+			if link, err = os.Readlink(path); err != nil {
+				return err
+			}
+		case info.IsDir(), info.Mode().IsRegular():
+		default:
+			return nil
+		}
+
+		header, err := tar.FileInfoHeader(info, link)
+		if err != nil {
+			return err
+		}
+		header.Name = filepath.ToSlash(name)
+		if info.IsDir() {
+			header.Name += "/"
+		}
+		// Владелец файла на другой машине не имеет смысла
+		header.Uid, header.Gid, header.Uname, header.Gname = 0, 0, "", ""
+
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+
+		file, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+
+		_, err = io.Copy(tw, file)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	return tw.Close()
+}
+
+// ExtractTar распаковывает tar-архив из r в директорию dest.
+// Записи с абсолютными путями, выходом за пределы dest через ".." или через символические ссылки отклоняются.
+// Биты setuid, setgid и sticky не восстанавливаются
+func ExtractTar(r io.Reader, dest string) error {
+	dest, err := filepath.Abs(dest)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dest, 0o755); err != nil {
+		return err
+	}
+
+	// Права директорий выставляются после распаковки, чтобы директории без права записи можно было заполнить
+	dirModes := map[string]os.FileMode{}
+
+	tr := tar.NewReader(r)
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("ошибка чтения архива: %w", err)
+		}
+
+		target, err := archiveTarget(dest, header.Name)
+		if err != nil {
+			return err
+		}
+		if target == dest {
+			continue
+		}
+		if err := checkNoSymlinkParents(dest, target); err != nil {
+			return err
+		}
+
+		mode := os.FileMode(header.Mode).Perm()
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, 0o700); err != nil {
+				return err
+			}
+			dirModes[target] = mode
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(target), 0o700); err != nil {
+				return err
+			}
+			if err := extractFile(tr, target, mode); err != nil {
+				return err
+			}
+		case tar.TypeSymlink:
+			if err := checkSymlinkTarget(dest, target, header.Linkname); err != nil {
+				return err
+			}
+			if err := os.MkdirAll(filepath.Dir(target), 0o700); err != nil {
+				return err
+			}
+			if err := removeExisting(target); err != nil {
+				return err
+			}
+			if err := os.Symlink(header.Linkname, target); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("%w: неподдерживаемый тип записи '%s'", ErrUnsafeArchivePath, header.Name)
+		}
+	}
+
+	for dir, mode := range dirModes {
+		if err := os.Chmod(dir, mode); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// archiveTarget возвращает путь на диске для записи архива, проверяя, что он остается внутри dest
+func archiveTarget(dest string, name string) (string, error) {
+	if name == "" || filepath.IsAbs(name) || strings.HasPrefix(name, "/") || strings.HasPrefix(name, `\`) || filepath.VolumeName(name) != "" {
+		return "", fmt.Errorf("%w: '%s'", ErrUnsafeArchivePath, name)
+	}
+
+	target := filepath.Join(dest, filepath.FromSlash(name))
+	if !isWithin(dest, target) {
+		return "", fmt.Errorf("%w: '%s'", ErrUnsafeArchivePath, name)
+	}
+
+	return target, nil
+}
+
+// checkNoSymlinkParents проверяет, что между dest и target нет символических ссылок,
+// иначе запись через ранее распакованную ссылку окажется за пределами dest
+func checkNoSymlinkParents(dest string, target string) error {
+	rel, err := filepath.Rel(dest, filepath.Dir(target))
+	if err != nil || rel == "." {
+		return err
+	}
+
+	current := dest
+	for _, part := range strings.Split(rel, string(filepath.Separator)) {
+		current = filepath.Join(current, part)
+		info, err := os.Lstat(current)
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if info.Mode()&fs.ModeSymlink != 0 {
+			return fmt.Errorf("%w: '%s' проходит через символическую ссылку", ErrUnsafeArchivePath, target)
+		}
+	}
+
+	return nil
+}
+
+// checkSymlinkTarget проверяет, что символическая ссылка указывает внутрь dest
+func checkSymlinkTarget(dest string, target string, linkname string) error {
+	if linkname == "" || filepath.IsAbs(linkname) || strings.HasPrefix(linkname, "/") {
+		return fmt.Errorf("%w: ссылка '%s' указывает на абсолютный путь '%s'", ErrUnsafeArchivePath, target, linkname)
+	}
+
+	resolved := filepath.Join(filepath.Dir(target), filepath.FromSlash(linkname))
+	if !isWithin(dest, resolved) {
+		return fmt.Errorf("%w: ссылка '%s' указывает за пределы директории", ErrUnsafeArchivePath, target)
+	}
+
+	return nil
+}
+
+// extractFile записывает содержимое обычного файла, заменяя существующий файл или ссылку
+func extractFile(r io.Reader, target string, mode os.FileMode) error {
+	if err := removeExisting(target); err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(target, os.O_WRONLY|os.O_CREATE|os.O_EXCL, mode)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(file, r); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+
+	// Права при создании урезаются umask, выставляем их явно
+	return os.Chmod(target, mode)
+}
+
+// removeExisting удаляет существующий файл или ссылку по пути target, директории не трогает
+func removeExisting(target string) error {
+	info, err := os.Lstat(target)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if info.IsDir() {
+		return fmt.Errorf("%w: '%s' уже существует и является директорией", ErrUnsafeArchivePath, target)
+	}
+	return os.Remove(target)
+}
+
+// isWithin сообщает, находится ли path внутри base или совпадает с ним
+func isWithin(base string, path string) bool {
+	rel, err := filepath.Rel(base, path)
+	if err != nil {
+		return false
+	}
+	return rel == "." || (rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)))
+}
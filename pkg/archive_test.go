@@ -0,0 +1,103 @@
+package pkg
+
+import (
+	"archive/tar"
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteTar_ExtractTar_RoundTrip(t *testing.T) {
+	src := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(src, "certs", "private"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(src, "certs", "ca.crt"), []byte("certificate"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(src, "certs", "private", "tls.key"), []byte("private key"), 0o600))
+	require.NoError(t, os.Chmod(filepath.Join(src, "certs", "private"), 0o700))
+	require.NoError(t, os.Symlink("ca.crt", filepath.Join(src, "certs", "current.crt")))
+
+	var archive bytes.Buffer
+	require.NoError(t, WriteTar(&archive, src))
+
+	dest := filepath.Join(t.TempDir(), "restored")
+	require.NoError(t, ExtractTar(&archive, dest))
+
+	content, err := os.ReadFile(filepath.Join(dest, "certs", "private", "tls.key"))
+	require.NoError(t, err)
+	assert.Equal(t, "private key", string(content))
+
+	keyInfo, err := os.Stat(filepath.Join(dest, "certs", "private", "tls.key"))
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), keyInfo.Mode().Perm())
+
+	dirInfo, err := os.Stat(filepath.Join(dest, "certs", "private"))
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o700), dirInfo.Mode().Perm())
+
+	link, err := os.Readlink(filepath.Join(dest, "certs", "current.crt"))
+	require.NoError(t, err)
+	assert.Equal(t, "ca.crt", link)
+}
+
+func TestExtractTar_Unsafe(t *testing.T) {
+	tests := []struct {
+		name    string
+		headers []*tar.Header
+	}{
+		{
+			name:    "Parent traversal",
+			headers: []*tar.Header{{Name: "../evil.txt", Typeflag: tar.TypeReg, Mode: 0o644}},
+		},
+		{
+			name:    "Nested traversal",
+			headers: []*tar.Header{{Name: "dir/../../evil.txt", Typeflag: tar.TypeReg, Mode: 0o644}},
+		},
+		{
+			name:    "Absolute path",
+			headers: []*tar.Header{{Name: "/tmp/evil.txt", Typeflag: tar.TypeReg, Mode: 0o644}},
+		},
+		{
+			name:    "Absolute symlink",
+			headers: []*tar.Header{{Name: "passwd", Typeflag: tar.TypeSymlink, Linkname: "/etc/passwd"}},
+		},
+		{
+			name:    "Symlink outside",
+			headers: []*tar.Header{{Name: "up", Typeflag: tar.TypeSymlink, Linkname: "../.."}},
+		},
+		{
+			name: "Write through symlink",
+			headers: []*tar.Header{
+				{Name: "self", Typeflag: tar.TypeSymlink, Linkname: "."},
+				{Name: "self/evil.txt", Typeflag: tar.TypeReg, Mode: 0o644},
+			},
+		},
+		{
+			name:    "Hard link",
+			headers: []*tar.Header{{Name: "shadow", Typeflag: tar.TypeLink, Linkname: "/etc/shadow"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var archive bytes.Buffer
+			tw := tar.NewWriter(&archive)
+			for _, header := range tt.headers {
+				require.NoError(t, tw.WriteHeader(header))
+			}
+			require.NoError(t, tw.Close())
+
+			root := t.TempDir()
+			dest := filepath.Join(root, "a", "b")
+
+			err := ExtractTar(&archive, dest)
+			assert.True(t, errors.Is(err, ErrUnsafeArchivePath), "ожидалась ошибка небезопасного пути, получена %v", err)
+
+			_, statErr := os.Stat(filepath.Join(root, "evil.txt"))
+			assert.True(t, os.IsNotExist(statErr), "файл не должен создаваться за пределами директории")
+		})
+	}
+}
@@ -1,5 +1,7 @@
 package pkg
 
+import "fmt"
+
 func GetExtensionByPath(filePath string) string {
 	// Извлекаем расширение файла из пути
 	extension := ""
@@ -19,3 +21,19 @@ func GetExtensionByPath(filePath string) string {
 
 	return extension
 }
+
+// FormatBytes форматирует размер в байтах в человекочитаемый вид
+func FormatBytes(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d Б", size)
+	}
+	units := []string{"КБ", "МБ", "ГБ", "ТБ"}
+	value := float64(size) / unit
+	i := 0
+	for value >= unit && i < len(units)-1 {
+		value /= unit
+		i++
+	}
+	return fmt.Sprintf("%.1f %s", value, units[i])
+}
@@ -70,4 +70,22 @@ func TestGetExtensionByPath(t *testing.T) {
 			assert.Equal(t, tt.expected, result)
 		})
 	}
-}
\ No newline at end of file
+}
+func TestFormatBytes(t *testing.T) {
+	tests := []struct {
+		size     int64
+		expected string
+	}{
+		{size: 0, expected: "0 Б"},
+		{size: 1023, expected: "1023 Б"},
+		{size: 1536, expected: "1.5 КБ"},
+		{size: 5 * 1024 * 1024, expected: "5.0 МБ"},
+		{size: 3 << 40, expected: "3.0 ТБ"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expected, func(t *testing.T) {
+			assert.Equal(t, tt.expected, FormatBytes(tt.size))
+		})
+	}
+}